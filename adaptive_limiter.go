@@ -0,0 +1,185 @@
+package azrealtime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultThrottleBelow is the AdaptiveLimiter threshold used when
+// AdaptiveLimiterConfig.ThrottleBelow is zero.
+const DefaultThrottleBelow = 0.2
+
+// AdaptiveLimiterConfig configures NewAdaptiveLimiter.
+type AdaptiveLimiterConfig struct {
+	// ThrottleBelow is the fraction of a limit below which requests are spaced
+	// out. Above it requests are not delayed. If zero, DefaultThrottleBelow is used.
+	ThrottleBelow float64
+
+	// OnThrottle, if set, is called when the limiter starts or stops spacing
+	// requests, with the current spacing (zero when throttling ends).
+	OnThrottle func(throttled bool, interval time.Duration)
+}
+
+// AdaptiveLimiter smooths response.create requests so they stay under the
+// quota reported in rate_limits.updated events, instead of bursting until the
+// service refuses them. Once a limit drops below ThrottleBelow of its size,
+// requests are spaced out until it resets:
+//
+//   - requests: the remaining requests are spread evenly over the time to reset.
+//   - tokens: the spacing grows linearly from zero at the threshold to the full
+//     time to reset when no tokens remain.
+//
+// Set it as Config.AdaptiveLimiter to have CreateResponse wait for its turn;
+// clients sharing a deployment's quota can share one limiter. It can also be
+// used on its own with Update and Wait. An AdaptiveLimiter is safe for
+// concurrent use.
+type AdaptiveLimiter struct {
+	cfg AdaptiveLimiterConfig
+
+	mu        sync.Mutex
+	interval  time.Duration // Spacing between requests while throttled
+	resetAt   time.Time     // When the spacing stops applying
+	next      time.Time     // Earliest time for the next request
+	throttled bool
+}
+
+// NewAdaptiveLimiter returns a limiter that does not delay anything until it
+// receives its first Update.
+func NewAdaptiveLimiter(cfg AdaptiveLimiterConfig) *AdaptiveLimiter {
+	if cfg.ThrottleBelow <= 0 || cfg.ThrottleBelow > 1 {
+		cfg.ThrottleBelow = DefaultThrottleBelow
+	}
+	return &AdaptiveLimiter{cfg: cfg}
+}
+
+// Update recomputes the spacing from a rate_limits.updated event.
+func (l *AdaptiveLimiter) Update(rl RateLimitsUpdated) {
+	l.update(rl, time.Now())
+}
+
+func (l *AdaptiveLimiter) update(rl RateLimitsUpdated, now time.Time) {
+	var interval, reset time.Duration
+	for _, r := range rl.RateLimits {
+		if r.Limit <= 0 {
+			continue
+		}
+		frac := float64(r.Remaining) / float64(r.Limit)
+		if frac >= l.cfg.ThrottleBelow {
+			continue
+		}
+		resetIn := r.resetIn()
+		if resetIn <= 0 {
+			resetIn = defaultRateLimitReset
+		}
+		var d time.Duration
+		switch {
+		case r.Remaining <= 0:
+			d = resetIn
+		case r.Name == RateLimitRequests:
+			d = resetIn / time.Duration(r.Remaining)
+		default:
+			d = time.Duration(float64(resetIn) * (1 - frac/l.cfg.ThrottleBelow))
+		}
+		if d > interval {
+			interval = d
+		}
+		if resetIn > reset {
+			reset = resetIn
+		}
+	}
+
+	l.mu.Lock()
+	l.interval = interval
+	l.resetAt = now.Add(reset)
+	l.mu.Unlock()
+	l.transition(now)
+}
+
+// Interval returns the current spacing between requests, or zero when not throttled.
+func (l *AdaptiveLimiter) Interval() time.Duration {
+	l.transition(time.Now())
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.interval
+}
+
+// Throttled reports whether requests are currently being spaced out.
+func (l *AdaptiveLimiter) Throttled() bool {
+	return l.Interval() > 0
+}
+
+// Wait blocks until the next request may be sent, reserving that slot.
+func (l *AdaptiveLimiter) Wait(ctx context.Context) error {
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// reserve claims the next request slot and returns how long to wait for it.
+func (l *AdaptiveLimiter) reserve(now time.Time) time.Duration {
+	l.transition(now)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.interval <= 0 {
+		return 0
+	}
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return delay
+}
+
+// transition ends throttling once the limits have reset and reports changes
+// to OnThrottle.
+func (l *AdaptiveLimiter) transition(now time.Time) {
+	l.mu.Lock()
+	if l.interval > 0 && !now.Before(l.resetAt) {
+		l.interval = 0
+	}
+	throttled := l.interval > 0
+	changed := throttled != l.throttled
+	l.throttled = throttled
+	if !throttled {
+		l.next = time.Time{}
+	}
+	interval, fn := l.interval, l.cfg.OnThrottle
+	l.mu.Unlock()
+
+	if changed && fn != nil {
+		fn(throttled, interval)
+	}
+}
+
+// adaptiveWait waits for Config.AdaptiveLimiter before sending eventType.
+func (c *Client) adaptiveWait(ctx context.Context, eventType string) error {
+	l := c.cfg.AdaptiveLimiter
+	if l == nil {
+		return nil
+	}
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return NewSendError(eventType, "", ctx.Err())
+	case <-c.closedCh:
+		return ErrClosed
+	case <-t.C:
+		return nil
+	}
+}
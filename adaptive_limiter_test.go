@@ -0,0 +1,99 @@
+package azrealtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter_Spacing(t *testing.T) {
+	var transitions []bool
+	l := NewAdaptiveLimiter(AdaptiveLimiterConfig{
+		OnThrottle: func(throttled bool, _ time.Duration) { transitions = append(transitions, throttled) },
+	})
+	now := time.Now()
+
+	// Plenty of quota: no delay
+	l.update(RateLimitsUpdated{RateLimits: []RateLimit{{Name: RateLimitRequests, Limit: 100, Remaining: 50, ResetSeconds: 10}}}, now)
+	if d := l.reserve(now); d != 0 {
+		t.Errorf("delay with plenty of quota = %v", d)
+	}
+
+	// 4 requests left for 10s: one every 2.5s
+	l.update(RateLimitsUpdated{RateLimits: []RateLimit{{Name: RateLimitRequests, Limit: 100, Remaining: 4, ResetSeconds: 10}}}, now)
+	for i, want := range []time.Duration{0, 2500 * time.Millisecond, 5 * time.Second} {
+		if d := l.reserve(now); d != want {
+			t.Errorf("request %d delay = %v, want %v", i, d, want)
+		}
+	}
+
+	// Tokens ramp from no spacing at the threshold to the full reset time
+	l.update(RateLimitsUpdated{RateLimits: []RateLimit{{Name: RateLimitTokens, Limit: 1000, Remaining: 100, ResetSeconds: 4}}}, now)
+	if got := l.interval; got != 2*time.Second {
+		t.Errorf("token interval = %v, want 2s", got)
+	}
+	l.update(RateLimitsUpdated{RateLimits: []RateLimit{{Name: RateLimitTokens, Limit: 1000, Remaining: 0, ResetSeconds: 4}}}, now)
+	if got := l.interval; got != 4*time.Second {
+		t.Errorf("exhausted interval = %v, want 4s", got)
+	}
+
+	// Throttling ends when the limit resets
+	if d := l.reserve(now.Add(5 * time.Second)); d != 0 {
+		t.Errorf("delay after reset = %v", d)
+	}
+	if len(transitions) != 2 || !transitions[0] || transitions[1] {
+		t.Errorf("transitions = %v, want [true false]", transitions)
+	}
+}
+
+func TestClient_AdaptiveLimiter(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+	mockServer.AddMessage(map[string]any{
+		"type":        "rate_limits.updated",
+		"rate_limits": []map[string]any{{"name": "requests", "limit": 100, "remaining": 8, "reset_seconds": 2}},
+	})
+
+	throttled := make(chan time.Duration, 1)
+	cfg := CreateMockConfig(mockServer.URL())
+	cfg.AdaptiveLimiter = NewAdaptiveLimiter(AdaptiveLimiterConfig{
+		OnThrottle: func(on bool, interval time.Duration) {
+			if on {
+				throttled <- interval
+			}
+		},
+	})
+	client, err := Dial(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	select {
+	case interval := <-throttled:
+		if interval != 250*time.Millisecond {
+			t.Errorf("interval = %v, want 250ms", interval)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("limiter never throttled")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.CreateResponse(ctx, CreateResponseOptions{Modalities: []string{"text"}}); err != nil {
+			t.Fatalf("CreateResponse: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond {
+		t.Errorf("3 responses took %v, want them spaced 250ms apart", elapsed)
+	}
+
+	// A canceled context stops the wait
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	if _, err := client.CreateResponse(short, CreateResponseOptions{}); err == nil {
+		t.Error("expected error when the context ends while waiting")
+	}
+}
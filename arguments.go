@@ -0,0 +1,307 @@
+package azrealtime
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ArgumentsAssembler collects streaming function call arguments per call ID and
+// exposes the fields known so far, e.g. to show "searching for …" while the
+// model is still writing the call. Feed it ResponseFunctionCallArgumentsDelta
+// and ResponseFunctionCallArgumentsDone events.
+// An ArgumentsAssembler is safe for concurrent use.
+type ArgumentsAssembler struct {
+	mu    sync.Mutex
+	calls map[string]*partialJSON
+}
+
+// NewArgumentsAssembler creates a new ArgumentsAssembler.
+func NewArgumentsAssembler() *ArgumentsAssembler {
+	return &ArgumentsAssembler{calls: make(map[string]*partialJSON)}
+}
+
+// OnDelta appends the delta to its call's arguments and returns the fields
+// parsed so far (see Partial).
+func (a *ArgumentsAssembler) OnDelta(e ResponseFunctionCallArgumentsDelta) map[string]any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	p := a.calls[e.CallID]
+	if p == nil {
+		p = &partialJSON{}
+		a.calls[e.CallID] = p
+	}
+	p.write(e.Delta)
+	return p.object()
+}
+
+// Partial returns the arguments of callID known so far. String values may be
+// cut short; a field appears once its value has started (strings) or is
+// complete (numbers, booleans, objects and arrays are filled in as they
+// stream). It returns nil for unknown calls and before the first field.
+func (a *ArgumentsAssembler) Partial(callID string) map[string]any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if p := a.calls[callID]; p != nil {
+		return p.object()
+	}
+	return nil
+}
+
+// DecodePartial unmarshals the arguments of callID known so far into v, like
+// Partial but into a typed struct. It is a no-op for unknown calls.
+func (a *ArgumentsAssembler) DecodePartial(callID string, v any) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	p := a.calls[callID]
+	if p == nil {
+		return nil
+	}
+	s := p.complete()
+	if s == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(s), v)
+}
+
+// OnDone retrieves and removes the complete arguments of the call, preferring
+// the event's Arguments field and falling back to the assembled deltas.
+func (a *ArgumentsAssembler) OnDone(e ResponseFunctionCallArgumentsDone) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	p := a.calls[e.CallID]
+	delete(a.calls, e.CallID)
+	if e.Arguments != "" || p == nil {
+		return e.Arguments
+	}
+	return p.buf.String()
+}
+
+// CompletePartialJSON returns the longest prefix of the truncated JSON text s
+// that can be closed into a valid document, with the closing quotes, brackets
+// and braces appended. Unfinished keys, numbers and literals and trailing
+// commas are dropped; an unfinished string value is kept as far as it goes.
+// It returns "" if s does not yet contain a value.
+func CompletePartialJSON(s string) string {
+	var p partialJSON
+	p.write(s)
+	return p.complete()
+}
+
+// partialJSON scans JSON text as it arrives. Scanning resumes at the first
+// token that was incomplete on the previous write, so each byte outside such a
+// token is examined once.
+type partialJSON struct {
+	buf   strings.Builder
+	pos   int         // Start of the first unscanned token
+	stack []jsonFrame // Open containers
+	safe  int         // buf[:safe]+closers is valid JSON
+	close string      // Closers for buf[:safe]
+	tail  string      // Unfinished string value after safe, already repaired, or ""
+	done  bool        // A complete top-level value was scanned
+	cache *string     // Memoized complete(), reset by write
+}
+
+type jsonFrame struct {
+	object bool
+	state  jsonState
+}
+
+type jsonState int
+
+const (
+	expectValue jsonState = iota
+	expectKey
+	expectColon
+	expectComma
+)
+
+func (p *partialJSON) write(s string) {
+	p.buf.WriteString(s)
+	p.cache = nil
+	p.scan()
+}
+
+// complete returns the closed prefix, or "" if there is no value yet.
+func (p *partialJSON) complete() string {
+	if p.cache != nil {
+		return *p.cache
+	}
+	var out string
+	switch {
+	case p.tail != "":
+		out = p.buf.String()[:p.safe] + p.tail + p.close
+	case p.safe > 0:
+		out = p.buf.String()[:p.safe] + p.close
+	}
+	p.cache = &out
+	return out
+}
+
+// object decodes the closed prefix as a JSON object.
+func (p *partialJSON) object() map[string]any {
+	s := p.complete()
+	if s == "" {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+func (p *partialJSON) scan() {
+	s := p.buf.String()
+	p.tail = ""
+	for !p.done {
+		i := p.pos
+		for i < len(s) && isJSONSpace(s[i]) {
+			i++
+		}
+		if i == len(s) {
+			p.pos = i
+			return
+		}
+		var top *jsonFrame
+		if n := len(p.stack); n > 0 {
+			top = &p.stack[n-1]
+		}
+		isKey := top != nil && top.state == expectKey
+
+		switch c := s[i]; c {
+		case '"':
+			end, ok := scanJSONString(s, i)
+			if !ok {
+				// Surface an unfinished string value, but not an unfinished key
+				if !isKey {
+					p.safe = i
+					p.close = p.closers()
+					p.tail = repairJSONString(s[i:]) + `"`
+				}
+				p.pos = i
+				return
+			}
+			if isKey {
+				top.state = expectColon
+			} else {
+				p.valueDone(end)
+			}
+			p.pos = end
+		case '{', '[':
+			f := jsonFrame{object: c == '{'}
+			if f.object {
+				f.state = expectKey
+			}
+			p.stack = append(p.stack, f)
+			p.pos = i + 1
+			p.mark(p.pos)
+		case '}', ']':
+			if n := len(p.stack); n > 0 {
+				p.stack = p.stack[:n-1]
+			}
+			p.valueDone(i + 1)
+			p.pos = i + 1
+		case ':', ',':
+			if top == nil {
+				p.done = true // Malformed; keep what was parsed
+				return
+			}
+			top.state = expectValue
+			if c == ',' && top.object {
+				top.state = expectKey
+			}
+			p.pos = i + 1
+		default:
+			end := i
+			for end < len(s) && !isJSONDelim(s[end]) {
+				end++
+			}
+			if end == len(s) {
+				p.pos = i // The number or literal may continue
+				return
+			}
+			p.valueDone(end)
+			p.pos = end
+		}
+	}
+}
+
+// valueDone records that a value ended at end.
+func (p *partialJSON) valueDone(end int) {
+	if n := len(p.stack); n > 0 {
+		p.stack[n-1].state = expectComma
+	} else {
+		p.done = true
+	}
+	p.mark(end)
+}
+
+func (p *partialJSON) mark(end int) {
+	p.safe = end
+	p.close = p.closers()
+}
+
+func (p *partialJSON) closers() string {
+	b := make([]byte, len(p.stack))
+	for i, f := range p.stack {
+		c := byte(']')
+		if f.object {
+			c = '}'
+		}
+		b[len(b)-1-i] = c
+	}
+	return string(b)
+}
+
+// scanJSONString returns the index after the string starting at s[start].
+func scanJSONString(s string, start int) (int, bool) {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// repairJSONString trims an unterminated string so that closing it with a
+// quote is valid: incomplete escapes and a cut multi-byte rune are dropped.
+func repairJSONString(s string) string {
+	// Find the start of a trailing escape sequence, if any
+	for i := len(s) - 1; i >= len(s)-6 && i > 0; i-- {
+		if s[i] != '\\' {
+			continue
+		}
+		backslashes := 0
+		for j := i; j > 0 && s[j] == '\\'; j-- {
+			backslashes++
+		}
+		if backslashes%2 == 0 {
+			break // Escaped backslash
+		}
+		rest := s[i+1:]
+		if rest == "" || (rest[0] == 'u' && len(rest) < 5) {
+			s = s[:i]
+		}
+		break
+	}
+	for n := 0; n < utf8.UTFMax && len(s) > 1; n++ {
+		if r, size := utf8.DecodeLastRuneInString(s); r != utf8.RuneError || size != 1 {
+			break
+		}
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isJSONDelim(c byte) bool {
+	return isJSONSpace(c) || c == ',' || c == '}' || c == ']' || c == ':'
+}
@@ -0,0 +1,101 @@
+package azrealtime
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCompletePartialJSON(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{``, ``},
+		{`  `, ``},
+		{`{`, `{}`},
+		{`{"qu`, `{}`},
+		{`{"query"`, `{}`},
+		{`{"query":`, `{}`},
+		{`{"query": "wea`, `{"query": "wea"}`},
+		{`{"query": "weather", `, `{"query": "weather"}`},
+		{`{"query": "weather", "limit": 1`, `{"query": "weather"}`},
+		{`{"query": "weather", "limit": 10,`, `{"query": "weather", "limit": 10}`},
+		{`{"ok": tr`, `{}`},
+		{`{"ok": true}`, `{"ok": true}`},
+		{`{"tags": ["a", "b`, `{"tags": ["a", "b"]}`},
+		{`{"tags": [1, 2`, `{"tags": [1]}`},
+		{`{"a": {"b": [{"c": "d`, `{"a": {"b": [{"c": "d"}]}}`},
+		{`{"s": "line\`, `{"s": "line"}`},
+		{`{"s": "x\u00`, `{"s": "x"}`},
+		{`{"s": "a\\`, `{"s": "a\\"}`},
+		{`{"s": "caf` + "\xc3", `{"s": "caf"}`},
+		{`"partial`, `"partial"`},
+		{`[1, 2, 3]`, `[1, 2, 3]`},
+	}
+	for _, tt := range tests {
+		got := CompletePartialJSON(tt.in)
+		if got != tt.want {
+			t.Errorf("CompletePartialJSON(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got != "" && !json.Valid([]byte(got)) {
+			t.Errorf("CompletePartialJSON(%q) = %q is not valid JSON", tt.in, got)
+		}
+	}
+}
+
+func TestCompletePartialJSON_EveryPrefix(t *testing.T) {
+	doc := `{"query": "café \"near\" me", "filters": {"open": true, "rating": 4.5, "tags": ["wifi", null]}, "limit": 10}`
+	for i := 0; i <= len(doc); i++ {
+		if got := CompletePartialJSON(doc[:i]); got != "" && !json.Valid([]byte(got)) {
+			t.Fatalf("prefix %q completed to invalid %q", doc[:i], got)
+		}
+	}
+	if got := CompletePartialJSON(doc); got != doc {
+		t.Errorf("complete document changed: %q", got)
+	}
+}
+
+func TestArgumentsAssembler(t *testing.T) {
+	a := NewArgumentsAssembler()
+	deltas := []string{`{"que`, `ry": "best pi`, `zza", "lim`, `it": 3}`}
+	var seen []map[string]any
+	for _, d := range deltas {
+		seen = append(seen, a.OnDelta(ResponseFunctionCallArgumentsDelta{CallID: "call_1", Delta: d}))
+	}
+	// Another call streams independently
+	a.OnDelta(ResponseFunctionCallArgumentsDelta{CallID: "call_2", Delta: `{"x": 1,`})
+
+	want := []map[string]any{
+		{},
+		{"query": "best pi"},
+		{"query": "best pizza"},
+		{"query": "best pizza", "limit": float64(3)},
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("partial arguments = %v, want %v", seen, want)
+	}
+	if got := a.Partial("call_2"); !reflect.DeepEqual(got, map[string]any{"x": float64(1)}) {
+		t.Errorf("Partial(call_2) = %v", got)
+	}
+	if got := a.Partial("unknown"); got != nil {
+		t.Errorf("Partial(unknown) = %v, want nil", got)
+	}
+
+	var typed struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := a.DecodePartial("call_1", &typed); err != nil || typed.Query != "best pizza" || typed.Limit != 3 {
+		t.Errorf("DecodePartial = %+v, %v", typed, err)
+	}
+
+	if got := a.OnDone(ResponseFunctionCallArgumentsDone{CallID: "call_1"}); got != `{"query": "best pizza", "limit": 3}` {
+		t.Errorf("OnDone fallback = %q", got)
+	}
+	if got := a.OnDone(ResponseFunctionCallArgumentsDone{CallID: "call_2", Arguments: `{"x": 1, "y": 2}`}); got != `{"x": 1, "y": 2}` {
+		t.Errorf("OnDone = %q", got)
+	}
+	if a.Partial("call_1") != nil || a.Partial("call_2") != nil {
+		t.Error("OnDone did not forget the calls")
+	}
+}
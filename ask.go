@@ -0,0 +1,56 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// askCancelTimeout bounds the response.cancel sent when Ask's context ends.
+const askCancelTimeout = 5 * time.Second
+
+// Ask sends prompt as a user message, requests a text response and waits for
+// it to complete, returning the full reply. It is meant for scripts and health
+// checks where streaming isn't needed; the prompt and reply stay in the
+// conversation like any other turn.
+//
+// Ask does not use or replace any registered handlers. It fails if the server
+// rejects the request (e.g. while another response is in progress), if the
+// response ends with a status other than "completed", or if the client closes.
+// If ctx ends first, the response is cancelled and ctx's error is returned.
+func (c *Client) Ask(ctx context.Context, prompt string) (string, error) {
+	if ctx == nil {
+		return "", NewSendError("response.create", "", errors.New("context cannot be nil"))
+	}
+	if strings.TrimSpace(prompt) == "" {
+		return "", NewSendError("conversation.item.create", "", errors.New("prompt cannot be empty"))
+	}
+
+	if err := c.CreateConversationItem(ctx, NewUserTextItem(prompt)); err != nil {
+		return "", err
+	}
+
+	tracker := c.TrackResponses()
+	defer tracker.Stop()
+	_, tag, err := c.CreateTaggedResponse(ctx, CreateResponseOptions{Modalities: []string{"text"}})
+	if err != nil {
+		return "", err
+	}
+	defer c.ForgetResponseTag(tag)
+
+	resp, err := tracker.AwaitTag(ctx, tag)
+	if err != nil {
+		if ctx.Err() != nil {
+			cancelCtx, cancel := context.WithTimeout(context.Background(), askCancelTimeout)
+			_ = c.CancelResponse(cancelCtx)
+			cancel()
+		}
+		return "", err
+	}
+	if resp.Status != "completed" {
+		return "", fmt.Errorf("azrealtime: response %s ended with status %q", resp.ID, resp.Status)
+	}
+	return resp.Text, nil
+}
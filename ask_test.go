@@ -0,0 +1,63 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_Ask(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	sent := recordOutbound(client)
+
+	// Registered handlers keep working alongside Ask
+	deltas := make(chan string, 1)
+	client.OnResponseTextDelta(func(e ResponseTextDelta) { deltas <- e.Delta })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	reply, err := client.Ask(ctx, "Say hello")
+	if err != nil {
+		t.Fatalf("Ask: %v", err)
+	}
+	if reply != "Hello from mock server!" {
+		t.Errorf("reply = %q", reply)
+	}
+	select {
+	case <-deltas:
+	default:
+		t.Error("OnResponseTextDelta handler was not called")
+	}
+
+	items := sent.ofType("conversation.item.create")
+	if len(items) != 1 || !strings.Contains(items[0]["item"].(map[string]any)["content"].([]any)[0].(map[string]any)["text"].(string), "Say hello") {
+		t.Errorf("conversation items = %v", items)
+	}
+	creates := sent.ofType("response.create")
+	if len(creates) != 1 {
+		t.Fatalf("sent %d response.create, want 1", len(creates))
+	}
+	if m := creates[0]["response"].(map[string]any)["modalities"]; len(m.([]any)) != 1 || m.([]any)[0] != "text" {
+		t.Errorf("modalities = %v, want [text]", m)
+	}
+
+	if _, err := client.Ask(ctx, "  "); err == nil {
+		t.Error("expected error for an empty prompt")
+	}
+}
+
+func TestClient_Ask_ContextCancelled(t *testing.T) {
+	client, _ := dialProfile(t, &MockProfile{Response: SyntheticResponse(10*time.Millisecond, 50*time.Millisecond, 100)})
+	sent := recordOutbound(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := client.Ask(ctx, "Tell me a long story"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Ask error = %v, want deadline exceeded", err)
+	}
+	if got := len(sent.ofType("response.cancel")); got != 1 {
+		t.Errorf("sent %d response.cancel, want 1", got)
+	}
+}
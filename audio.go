@@ -6,9 +6,15 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 )
 
+// MaxAppendBytes is the largest audio chunk a single append accepts. Set
+// Config.AutoChunkAudio or use AppendPCM16From to send larger buffers.
+const MaxAppendBytes = 1024 * 1024
+
 // AppendPCM16 sends PCM16 audio data to the assistant's input buffer.
 // The audio should be 16-bit little-endian PCM at 24kHz sample rate.
 // Audio data is automatically base64-encoded before transmission.
@@ -26,17 +32,75 @@ func (c *Client) AppendPCM16(ctx context.Context, pcmLE []byte) error {
 	}
 
 	// Check for reasonable size limits (prevent massive payloads)
-	const maxChunkSize = 1024 * 1024 // 1MB per chunk
-	if len(pcmLE) > maxChunkSize {
+	if len(pcmLE) > MaxAppendBytes && !c.cfg.AutoChunkAudio {
 		return NewSendError("input_audio_buffer.append", "",
-			fmt.Errorf("PCM data too large (%d bytes), maximum is %d bytes", len(pcmLE), maxChunkSize))
+			fmt.Errorf("PCM data too large (%d bytes), maximum is %d bytes", len(pcmLE), MaxAppendBytes))
+	}
+
+	return c.appendAudio(ctx, pcmLE, len(pcmLE))
+}
+
+// AppendPCM16From reads PCM16 audio from r until EOF and appends it in chunks of
+// up to MaxAppendBytes, as fast as the connection allows. A trailing odd byte
+// is dropped. It returns the number of bytes sent, which is less than the bytes
+// read if an error stops it early. Use StreamPCM16 to pace audio in real time.
+func (c *Client) AppendPCM16From(ctx context.Context, r io.Reader) (int64, error) {
+	if ctx == nil {
+		return 0, NewSendError("input_audio_buffer.append", "", errors.New("context cannot be nil"))
+	}
+	if r == nil {
+		return 0, NewSendError("input_audio_buffer.append", "", errors.New("reader cannot be nil"))
+	}
+
+	var sent int64
+	buf := make([]byte, MaxAppendBytes)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n -= n % 2; n > 0 {
+			if serr := c.AppendPCM16(ctx, buf[:n]); serr != nil {
+				return sent, serr
+			}
+			sent += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return sent, nil
+		}
+		if err != nil {
+			return sent, err
+		}
+	}
+}
+
+// appendAudio sends encoded audio to the input buffer, in chunks of up to
+// MaxAppendBytes, and records it in the stats as pcm16Bytes of DefaultSampleRate
+// PCM16.
+func (c *Client) appendAudio(ctx context.Context, audio []byte, pcm16Bytes int) error {
+	if len(audio) > MaxAppendBytes {
+		// Chunks stay whole-sample for PCM16; G.711 has one byte per sample
+		for len(audio) > 0 {
+			n := min(len(audio), MaxAppendBytes)
+			if err := c.appendAudio(ctx, audio[:n], pcm16Bytes*n/len(audio)); err != nil {
+				return err
+			}
+			pcm16Bytes -= pcm16Bytes * n / len(audio)
+			audio = audio[n:]
+		}
+		return nil
+	}
+
+	if err := c.throttle(ctx, "input_audio_buffer.append", "", RateLimitTokens); err != nil {
+		return err
 	}
 
 	payload := map[string]any{
 		"type":  "input_audio_buffer.append",
-		"audio": base64.StdEncoding.EncodeToString(pcmLE),
+		"audio": base64.StdEncoding.EncodeToString(audio),
 	}
-	return c.send(ctx, payload)
+	if err := c.send(ctx, payload); err != nil {
+		return err
+	}
+	c.stats.recordAudioIn(pcm16Bytes)
+	return nil
 }
 
 // InputCommit signals that the current audio input is complete and ready for processing.
@@ -57,48 +121,310 @@ func (c *Client) InputClear(ctx context.Context) error {
 	return c.send(ctx, map[string]any{"type": "input_audio_buffer.clear"})
 }
 
+// AudioEvictReason says why an AudioAssembler dropped a response's audio.
+type AudioEvictReason string
+
+// Reasons passed to AudioAssemblerOptions.OnEvict.
+const (
+	AudioEvictMaxBytes AudioEvictReason = "max_bytes" // Buffered audio exceeded MaxBytes
+	AudioEvictTTL      AudioEvictReason = "ttl"       // No delta arrived within TTL
+	AudioEvictReset    AudioEvictReason = "reset"     // Reset was called
+)
+
+// AudioAssemblerOptions bounds the memory held by an AudioAssembler for responses
+// whose OnDone is never called, e.g. after a cancellation or dropped connection.
+type AudioAssemblerOptions struct {
+	// MaxBytes caps the audio buffered across all responses. When a delta would
+	// exceed it, the least recently updated responses are dropped first; a single
+	// response larger than MaxBytes is dropped itself. Zero means no limit.
+	MaxBytes int
+
+	// TTL drops a response when no delta has arrived for it for this long.
+	// Expiry is checked whenever a delta arrives. Zero means no expiry.
+	TTL time.Duration
+
+	// OnEvict, if set, is called with the response ID, the number of bytes
+	// dropped and the reason. After a max_bytes eviction the response's later
+	// deltas are discarded too, and OnDone returns nil for it. Only the
+	// maxDroppedAudio (16) most recently updated evicted responses are
+	// remembered this way, so a MaxBytes limit without TTL stays bounded.
+	OnEvict func(responseID string, bytes int, reason AudioEvictReason)
+}
+
 // AudioAssembler collects streaming audio chunks and reassembles them into complete audio data.
 // Use this to handle ResponseAudioDelta events and reconstruct the full audio response.
-type AudioAssembler struct{ data map[string][]byte }
+// It is safe for concurrent use.
+type AudioAssembler struct {
+	mu    sync.Mutex
+	data  map[string]*assembledAudio
+	total int       // Bytes buffered across all responses
+	w     io.Writer // If set, decoded audio is written here instead of buffered
+	opts  AudioAssemblerOptions
+}
+
+// assembledAudio is the audio buffered for one response.
+type assembledAudio struct {
+	pcm     []byte
+	updated time.Time
+	dropped bool // Evicted; later deltas are discarded until OnDone
+}
+
+// audioEviction is an OnEvict call deferred until the lock is released.
+type audioEviction struct {
+	id     string
+	bytes  int
+	reason AudioEvictReason
+}
 
 // NewAudioAssembler creates a new AudioAssembler instance.
-func NewAudioAssembler() *AudioAssembler { return &AudioAssembler{data: make(map[string][]byte)} }
+func NewAudioAssembler() *AudioAssembler {
+	return NewAudioAssemblerWithOptions(AudioAssemblerOptions{})
+}
+
+// NewAudioAssemblerWithOptions creates an AudioAssembler with memory limits.
+func NewAudioAssemblerWithOptions(opts AudioAssemblerOptions) *AudioAssembler {
+	return &AudioAssembler{data: make(map[string]*assembledAudio), opts: opts}
+}
+
+// NewStreamingAudioAssembler creates an AudioAssembler that writes decoded PCM16
+// to w as each delta arrives instead of buffering whole responses, e.g. to feed a
+// player or a file during multi-minute answers. Audio from all responses goes to
+// w in arrival order, and OnDone returns nil.
+func NewStreamingAudioAssembler(w io.Writer) *AudioAssembler {
+	return &AudioAssembler{data: make(map[string]*assembledAudio), w: w}
+}
 
 // OnDelta processes a ResponseAudioDelta event by decoding and appending the audio data.
-// Call this from your ResponseAudioDelta event handler.
+// Call this from your ResponseAudioDelta event handler. For a streaming assembler,
+// errors from the writer are returned.
 func (a *AudioAssembler) OnDelta(e ResponseAudioDelta) error {
-	b, err := base64.StdEncoding.DecodeString(e.DeltaBase64)
+	bufp := decodePool.Get().(*decodeBuffers)
+	defer decodePool.Put(bufp)
+	b, err := bufp.decode(e.DeltaBase64)
 	if err != nil {
 		return err
 	}
-	a.data[e.ResponseID] = append(a.data[e.ResponseID], b...)
+	if a.w != nil {
+		_, err = a.w.Write(b)
+		return err
+	}
+
+	a.mu.Lock()
+	now := time.Now()
+	var evicted []audioEviction
+	if a.opts.TTL > 0 {
+		for id, buf := range a.data {
+			if id != e.ResponseID && now.Sub(buf.updated) > a.opts.TTL {
+				evicted = append(evicted, a.evictLocked(id, AudioEvictTTL))
+				delete(a.data, id) // Forget it entirely; OnDone will never come
+			}
+		}
+	}
+
+	buf := a.data[e.ResponseID]
+	if buf == nil {
+		a.forgetDroppedLocked()
+		buf = &assembledAudio{}
+		a.data[e.ResponseID] = buf
+	}
+	buf.updated = now
+	if !buf.dropped {
+		buf.pcm = appendPCM(buf.pcm, b)
+		a.total += len(b)
+		evicted = append(evicted, a.enforceMaxBytesLocked(e.ResponseID)...)
+	}
+	a.mu.Unlock()
+
+	a.notify(evicted)
 	return nil
 }
 
+// decodeBuffers are scratch buffers for decoding one delta, reused through
+// decodePool so steady streaming does not allocate per delta.
+type decodeBuffers struct {
+	src, dst []byte
+}
+
+var decodePool = sync.Pool{New: func() any { return new(decodeBuffers) }}
+
+// decode decodes s into d.dst and returns it; the result is valid until d is
+// returned to the pool.
+func (d *decodeBuffers) decode(s string) ([]byte, error) {
+	d.src = append(d.src[:0], s...)
+	if n := base64.StdEncoding.DecodedLen(len(s)); cap(d.dst) < n {
+		d.dst = make([]byte, n)
+	}
+	n, err := base64.StdEncoding.Decode(d.dst[:cap(d.dst)], d.src)
+	if err != nil {
+		return nil, err
+	}
+	return d.dst[:n], nil
+}
+
+// minAssemblyCap is the initial capacity of a response's audio buffer: 500ms
+// at DefaultSampleRate.
+const minAssemblyCap = DefaultSampleRate
+
+// appendPCM appends b to pcm, doubling the capacity when it runs out.
+func appendPCM(pcm, b []byte) []byte {
+	if need := len(pcm) + len(b); need > cap(pcm) {
+		grown := make([]byte, len(pcm), max(2*cap(pcm), need, minAssemblyCap))
+		copy(grown, pcm)
+		pcm = grown
+	}
+	return append(pcm, b...)
+}
+
+// enforceMaxBytesLocked drops responses, least recently updated first, until the
+// buffered total fits MaxBytes. current is dropped only if it alone is too large.
+func (a *AudioAssembler) enforceMaxBytesLocked(current string) []audioEviction {
+	if a.opts.MaxBytes <= 0 {
+		return nil
+	}
+	var evicted []audioEviction
+	if len(a.data[current].pcm) > a.opts.MaxBytes {
+		evicted = append(evicted, a.evictLocked(current, AudioEvictMaxBytes))
+	}
+	for a.total > a.opts.MaxBytes {
+		oldest := ""
+		for id, buf := range a.data {
+			if id == current || len(buf.pcm) == 0 {
+				continue
+			}
+			if oldest == "" || buf.updated.Before(a.data[oldest].updated) {
+				oldest = id
+			}
+		}
+		if oldest == "" {
+			break
+		}
+		evicted = append(evicted, a.evictLocked(oldest, AudioEvictMaxBytes))
+	}
+	return evicted
+}
+
+// maxDroppedAudio bounds how many evicted responses an AudioAssembler keeps
+// marked as dropped. Responses cancelled after a max_bytes eviction never get
+// OnDone, so without a TTL their markers would otherwise accumulate.
+const maxDroppedAudio = 16
+
+// forgetDroppedLocked deletes the least recently updated dropped marker once
+// there are maxDroppedAudio of them, making room for a new response. Its later
+// deltas, if any still arrive, are buffered as a new response.
+func (a *AudioAssembler) forgetDroppedLocked() {
+	n, oldest := 0, ""
+	for id, buf := range a.data {
+		if !buf.dropped {
+			continue
+		}
+		n++
+		if oldest == "" || buf.updated.Before(a.data[oldest].updated) {
+			oldest = id
+		}
+	}
+	if n >= maxDroppedAudio {
+		delete(a.data, oldest)
+	}
+}
+
+// evictLocked drops the buffered audio of id and marks it dropped.
+func (a *AudioAssembler) evictLocked(id string, reason AudioEvictReason) audioEviction {
+	buf := a.data[id]
+	n := len(buf.pcm)
+	a.total -= n
+	buf.pcm = nil
+	buf.dropped = true
+	return audioEviction{id: id, bytes: n, reason: reason}
+}
+
+// notify reports evictions to OnEvict.
+func (a *AudioAssembler) notify(evicted []audioEviction) {
+	if a.opts.OnEvict == nil {
+		return
+	}
+	for _, ev := range evicted {
+		a.opts.OnEvict(ev.id, ev.bytes, ev.reason)
+	}
+}
+
 // OnDone retrieves and removes the complete audio data for a given response ID.
 // Call this when you receive a ResponseAudioDone event to get the final audio.
-func (a *AudioAssembler) OnDone(id string) []byte { buf := a.data[id]; delete(a.data, id); return buf }
+// It returns nil if the response's audio was evicted.
+func (a *AudioAssembler) OnDone(id string) []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	buf := a.data[id]
+	delete(a.data, id)
+	if buf == nil {
+		return nil
+	}
+	a.total -= len(buf.pcm)
+	return buf.pcm
+}
+
+// Reset drops all buffered audio, reporting each response with data to OnEvict,
+// e.g. after a reconnect when pending responses will never complete.
+func (a *AudioAssembler) Reset() {
+	a.mu.Lock()
+	var evicted []audioEviction
+	for id, buf := range a.data {
+		if len(buf.pcm) > 0 {
+			evicted = append(evicted, audioEviction{id: id, bytes: len(buf.pcm), reason: AudioEvictReset})
+		}
+	}
+	a.data = make(map[string]*assembledAudio)
+	a.total = 0
+	a.mu.Unlock()
+
+	a.notify(evicted)
+}
+
+// Buffered returns the number of audio bytes currently held across all responses.
+func (a *AudioAssembler) Buffered() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.total
+}
 
 // WAVFromPCM16Mono converts raw PCM16 audio data to a complete WAV file.
 // This is useful for saving audio responses to disk or streaming to audio players.
 // The input should be 16-bit little-endian PCM data (mono channel).
 func WAVFromPCM16Mono(pcm []byte, sampleRate int) []byte {
-	blockAlign := uint16(2)
+	return WAVFromPCM16(pcm, sampleRate, 1)
+}
+
+// WAVFromPCM16 converts interleaved 16-bit little-endian PCM with the given
+// number of channels to a complete WAV file. Channels below 1 are treated as 1.
+// Use InterleavePCM16Stereo to build two-channel audio, e.g. the user on the
+// left and the assistant on the right.
+func WAVFromPCM16(pcm []byte, sampleRate, channels int) []byte {
+	if channels < 1 {
+		channels = 1
+	}
+	out := make([]byte, wavHeaderSize+len(pcm))
+	putWAVHeader(out, uint32(len(pcm)), sampleRate, channels)
+	copy(out[wavHeaderSize:], pcm)
+	return out
+}
+
+// wavHeaderSize is the size of the canonical PCM WAV header.
+const wavHeaderSize = 44
+
+// putWAVHeader writes a PCM16 WAV header for dataLen bytes of audio into out.
+func putWAVHeader(out []byte, dataLen uint32, sampleRate, channels int) {
+	blockAlign := uint16(2 * channels)
 	byteRate := uint32(sampleRate) * uint32(blockAlign)
-	dataLen := uint32(len(pcm))
-	riffLen := 36 + dataLen
-	out := make([]byte, 44+len(pcm))
 
 	// RIFF header
 	copy(out[0:], []byte("RIFF"))
-	binary.LittleEndian.PutUint32(out[4:], riffLen)
+	binary.LittleEndian.PutUint32(out[4:], 36+dataLen)
 	copy(out[8:], []byte("WAVE"))
 
 	// Format chunk
 	copy(out[12:], []byte("fmt "))
 	binary.LittleEndian.PutUint32(out[16:], 16) // fmt chunk size
 	binary.LittleEndian.PutUint16(out[20:], 1)  // audio format (PCM)
-	binary.LittleEndian.PutUint16(out[22:], 1)  // num channels (mono)
+	binary.LittleEndian.PutUint16(out[22:], uint16(channels))
 	binary.LittleEndian.PutUint32(out[24:], uint32(sampleRate))
 	binary.LittleEndian.PutUint32(out[28:], byteRate)
 	binary.LittleEndian.PutUint16(out[32:], blockAlign)
@@ -107,7 +433,33 @@ func WAVFromPCM16Mono(pcm []byte, sampleRate int) []byte {
 	// Data chunk
 	copy(out[36:], []byte("data"))
 	binary.LittleEndian.PutUint32(out[40:], dataLen)
-	copy(out[44:], pcm)
+}
+
+// InterleavePCM16Stereo combines two mono PCM16 streams into interleaved stereo.
+// The shorter input is padded with silence; trailing odd bytes are ignored.
+func InterleavePCM16Stereo(left, right []byte) []byte {
+	n := max(len(left), len(right)) / 2
+	out := make([]byte, 4*n)
+	for i := 0; i < n; i++ {
+		if 2*i+1 < len(left) {
+			copy(out[4*i:], left[2*i:2*i+2])
+		}
+		if 2*i+1 < len(right) {
+			copy(out[4*i+2:], right[2*i:2*i+2])
+		}
+	}
+	return out
+}
+
+// DownmixStereoToMono averages the channels of interleaved stereo PCM16 into
+// mono PCM16. A trailing partial frame is ignored.
+func DownmixStereoToMono(stereo []byte) []byte {
+	out := make([]byte, len(stereo)/4*2)
+	for i := 0; i < len(out)/2; i++ {
+		l := int32(int16(binary.LittleEndian.Uint16(stereo[4*i:])))
+		r := int32(int16(binary.LittleEndian.Uint16(stereo[4*i+2:])))
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(int16((l+r)/2)))
+	}
 	return out
 }
 
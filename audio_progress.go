@@ -0,0 +1,123 @@
+package azrealtime
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultAudioProgressInterval is used by OnResponseAudioProgress when every is zero.
+const DefaultAudioProgressInterval = 100 * time.Millisecond
+
+// AudioProgress reports how much assistant audio has been received for one
+// output item. Because audio arrives faster than real time, it marks how far a
+// player could have progressed, not what has actually been heard.
+type AudioProgress struct {
+	ResponseID   string        // Response the audio belongs to
+	ItemID       string        // Output item carrying the audio
+	OutputIndex  int           // Index of the item in the response output
+	ContentIndex int           // Index of the audio content part
+	Received     time.Duration // Total audio received for the item so far
+	Bytes        int           // Total audio bytes received for the item so far
+	Done         bool          // True for the final report, sent on response.audio.done
+}
+
+// AudioEndMs returns Received in milliseconds, the unit expected by
+// TruncateConversationItem's audioEndMs.
+func (p AudioProgress) AudioEndMs() int {
+	return int(p.Received / time.Millisecond)
+}
+
+// audioProgressKey identifies an audio content part.
+type audioProgressKey struct {
+	itemID       string
+	contentIndex int
+}
+
+// audioProgressTracker accumulates audio per content part and emits progress
+// each time another interval of audio has been assembled.
+type audioProgressTracker struct {
+	mu    sync.Mutex
+	every time.Duration
+	fn    func(AudioProgress)
+	parts map[audioProgressKey]*AudioProgress
+}
+
+// OnResponseAudioProgress registers a callback that receives an AudioProgress
+// every time another interval (default DefaultAudioProgressInterval) of assistant
+// audio has been received for an output item, plus a final report when the
+// item's audio is done. UIs can render playback progress from it, and barge-in
+// logic can use AudioEndMs to compute truncation points without owning the sink.
+//
+// The callback is invoked from the read loop after the OnResponseAudioDelta or
+// OnResponseAudioDone callback.
+func (c *Client) OnResponseAudioProgress(every time.Duration, fn func(AudioProgress)) {
+	if every <= 0 {
+		every = DefaultAudioProgressInterval
+	}
+	c.audioProgress.mu.Lock()
+	defer c.audioProgress.mu.Unlock()
+	c.audioProgress.every = every
+	c.audioProgress.fn = fn
+	c.audioProgress.parts = nil
+}
+
+// delta records n bytes of audio and returns a progress report if an interval boundary was crossed.
+func (t *audioProgressTracker) delta(e ResponseAudioDelta, n int, format func() string) (func(AudioProgress), AudioProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.fn == nil {
+		return nil, AudioProgress{}, false
+	}
+	if t.parts == nil {
+		t.parts = make(map[audioProgressKey]*AudioProgress)
+	}
+	key := audioProgressKey{e.ItemID, e.ContentIndex}
+	p := t.parts[key]
+	if p == nil {
+		p = &AudioProgress{ResponseID: e.ResponseID, ItemID: e.ItemID, OutputIndex: e.OutputIndex, ContentIndex: e.ContentIndex}
+		t.parts[key] = p
+	}
+	before := p.Received / t.every
+	p.Bytes += n
+	p.Received = audioDuration(format(), p.Bytes)
+	if p.Received/t.every == before {
+		return nil, AudioProgress{}, false
+	}
+	return t.fn, *p, true
+}
+
+// done finalizes a content part and returns its final report.
+func (t *audioProgressTracker) done(e ResponseAudioDone) (func(AudioProgress), AudioProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.fn == nil {
+		return nil, AudioProgress{}, false
+	}
+	key := audioProgressKey{e.ItemID, e.ContentIndex}
+	p := t.parts[key]
+	if p == nil {
+		p = &AudioProgress{ResponseID: e.ResponseID, ItemID: e.ItemID, OutputIndex: e.OutputIndex, ContentIndex: e.ContentIndex}
+	}
+	delete(t.parts, key)
+	p.Done = true
+	return t.fn, *p, true
+}
+
+// audioDuration returns the playback duration of n bytes of audio in the given
+// session audio format: 8 kHz 8-bit for G.711, 24 kHz PCM16 otherwise.
+func audioDuration(format string, n int) time.Duration {
+	switch format {
+	case "g711_ulaw", "g711_alaw":
+		return time.Duration(n) * time.Second / 8000
+	default:
+		return time.Duration(n) * time.Second / time.Duration(2*DefaultSampleRate)
+	}
+}
+
+// outputAudioFormat returns the output audio format configured through SessionUpdate.
+func (c *Client) outputAudioFormat() string {
+	if f := c.sessionUpdates.State().OutputAudioFormat; f != nil {
+		return *f
+	}
+	return "pcm16"
+}
@@ -0,0 +1,56 @@
+package azrealtime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func audioDeltaEvent(t *testing.T, itemID string, pcmBytes int) []byte {
+	t.Helper()
+	b, err := json.Marshal(ResponseAudioDelta{
+		Type:        "response.audio.delta",
+		ResponseID:  "resp_1",
+		ItemID:      itemID,
+		DeltaBase64: base64.StdEncoding.EncodeToString(make([]byte, pcmBytes)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestClient_OnResponseAudioProgress(t *testing.T) {
+	client := NewReplayClient(CreateMockConfig("ws://localhost"))
+
+	var reports []AudioProgress
+	client.OnResponseAudioProgress(100*time.Millisecond, func(p AudioProgress) { reports = append(reports, p) })
+
+	chunk := PCM16BytesFor(40, DefaultSampleRate) // 40ms per delta
+	for i := 0; i < 6; i++ {
+		client.handleMessage(audioDeltaEvent(t, "item_1", chunk))
+	}
+	client.handleMessage([]byte(`{"type":"response.audio.done","response_id":"resp_1","item_id":"item_1"}`))
+
+	// 240ms of audio crosses the 100ms and 200ms marks, then the final report
+	if len(reports) != 3 {
+		t.Fatalf("expected 3 reports, got %+v", reports)
+	}
+	if reports[0].Received != 120*time.Millisecond || reports[1].Received != 200*time.Millisecond {
+		t.Errorf("unexpected progress marks: %v, %v", reports[0].Received, reports[1].Received)
+	}
+	last := reports[2]
+	if !last.Done || last.AudioEndMs() != 240 || last.Bytes != 6*chunk || last.ItemID != "item_1" {
+		t.Errorf("unexpected final report: %+v", last)
+	}
+}
+
+func TestAudioDuration(t *testing.T) {
+	if d := audioDuration("pcm16", 48000); d != time.Second {
+		t.Errorf("expected 1s of pcm16, got %v", d)
+	}
+	if d := audioDuration("g711_ulaw", 8000); d != time.Second {
+		t.Errorf("expected 1s of g711, got %v", d)
+	}
+}
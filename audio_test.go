@@ -3,7 +3,12 @@ package azrealtime
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestAudioAssembler(t *testing.T) {
@@ -60,6 +65,40 @@ func TestAudioAssembler_InvalidBase64(t *testing.T) {
 	}
 }
 
+func TestStreamingAudioAssembler(t *testing.T) {
+	var buf bytes.Buffer
+	assembler := NewStreamingAudioAssembler(&buf)
+
+	for _, chunk := range []string{"Hello", " World"} {
+		err := assembler.OnDelta(ResponseAudioDelta{
+			ResponseID:  "resp_123",
+			DeltaBase64: base64.StdEncoding.EncodeToString([]byte(chunk)),
+		})
+		if err != nil {
+			t.Fatalf("failed to add delta: %v", err)
+		}
+		// Each delta is written as soon as it arrives
+		if !bytes.HasSuffix(buf.Bytes(), []byte(chunk)) {
+			t.Errorf("after delta %q, writer has %q", chunk, buf.String())
+		}
+	}
+
+	if buf.String() != "Hello World" {
+		t.Errorf("expected %q, got %q", "Hello World", buf.String())
+	}
+	if done := assembler.OnDone("resp_123"); done != nil {
+		t.Errorf("expected nil from OnDone when streaming, got %q", done)
+	}
+
+	// Writer errors are returned
+	pr, pw := io.Pipe()
+	pr.Close()
+	err := NewStreamingAudioAssembler(pw).OnDelta(ResponseAudioDelta{DeltaBase64: base64.StdEncoding.EncodeToString([]byte("x"))})
+	if err == nil {
+		t.Error("expected error from closed writer, got nil")
+	}
+}
+
 func TestPCM16BytesFor(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -144,6 +183,38 @@ func TestWAVFromPCM16Mono_EmptyData(t *testing.T) {
 	}
 }
 
+func TestWAVFromPCM16_Stereo(t *testing.T) {
+	pcm := InterleavePCM16Stereo(pcm16(1, 2, 3), pcm16(-1, -2))
+	if want := pcm16(1, -1, 2, -2, 3, 0); !bytes.Equal(pcm, want) {
+		t.Fatalf("InterleavePCM16Stereo = %v, want %v", pcm, want)
+	}
+
+	wav := WAVFromPCM16(pcm, 16000, 2)
+	if got := binary.LittleEndian.Uint16(wav[22:]); got != 2 {
+		t.Errorf("channels = %d, want 2", got)
+	}
+	if got := binary.LittleEndian.Uint32(wav[28:]); got != 64000 {
+		t.Errorf("byte rate = %d, want 64000", got)
+	}
+	if got := binary.LittleEndian.Uint16(wav[32:]); got != 4 {
+		t.Errorf("block align = %d, want 4", got)
+	}
+	if !bytes.Equal(wav[44:], pcm) {
+		t.Error("PCM data not correctly appended")
+	}
+
+	if !bytes.Equal(WAVFromPCM16Mono(pcm16(7), 24000), WAVFromPCM16(pcm16(7), 24000, 1)) {
+		t.Error("WAVFromPCM16Mono differs from WAVFromPCM16 with one channel")
+	}
+}
+
+func TestDownmixStereoToMono(t *testing.T) {
+	got := DownmixStereoToMono(append(pcm16(100, 200, 32767, 32767, -32768, -32768, 5, -5), 0xAA))
+	if want := pcm16(150, 32767, -32768, 0); !bytes.Equal(got, want) {
+		t.Errorf("DownmixStereoToMono = %v, want %v", got, want)
+	}
+}
+
 func BenchmarkAudioAssembler(b *testing.B) {
 	assembler := NewAudioAssembler()
 	testData := base64.StdEncoding.EncodeToString(make([]byte, 1024))
@@ -161,6 +232,62 @@ func BenchmarkAudioAssembler(b *testing.B) {
 	}
 }
 
+func TestAudioAssembler_DeltaAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful under the race detector")
+	}
+	delta := ResponseAudioDelta{
+		ResponseID:  "resp_1",
+		DeltaBase64: base64.StdEncoding.EncodeToString(make([]byte, PCM16BytesFor(40, DefaultSampleRate))),
+	}
+
+	streaming := NewStreamingAudioAssembler(io.Discard)
+	if allocs := testing.AllocsPerRun(100, func() { _ = streaming.OnDelta(delta) }); allocs > 0 {
+		t.Errorf("streaming OnDelta allocates %v times per delta, want 0", allocs)
+	}
+
+	// Buffered responses grow geometrically: ten seconds of 40ms deltas cost a
+	// handful of allocations in total
+	a := NewAudioAssembler()
+	allocs := testing.AllocsPerRun(1, func() {
+		for i := 0; i < 250; i++ {
+			_ = a.OnDelta(delta)
+		}
+		a.OnDone("resp_1")
+	})
+	if allocs > 20 {
+		t.Errorf("250 buffered deltas cost %v allocations, want at most 20", allocs)
+	}
+}
+
+// BenchmarkAudioAssembler_Stream measures a 10-second response arriving in
+// 40ms deltas, the common streaming case.
+func BenchmarkAudioAssembler_Stream(b *testing.B) {
+	delta := ResponseAudioDelta{
+		ResponseID:  "resp_1",
+		DeltaBase64: base64.StdEncoding.EncodeToString(make([]byte, PCM16BytesFor(40, DefaultSampleRate))),
+	}
+	b.Run("buffered", func(b *testing.B) {
+		a := NewAudioAssembler()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 250; j++ {
+				_ = a.OnDelta(delta)
+			}
+			a.OnDone("resp_1")
+		}
+	})
+	b.Run("streaming", func(b *testing.B) {
+		a := NewStreamingAudioAssembler(io.Discard)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 250; j++ {
+				_ = a.OnDelta(delta)
+			}
+		}
+	})
+}
+
 func BenchmarkWAVFromPCM16Mono(b *testing.B) {
 	pcmData := make([]byte, 9600) // 200ms at 24kHz
 
@@ -169,3 +296,108 @@ func BenchmarkWAVFromPCM16Mono(b *testing.B) {
 		_ = WAVFromPCM16Mono(pcmData, 24000)
 	}
 }
+
+func TestAudioAssembler_MaxBytes(t *testing.T) {
+	type eviction struct {
+		id     string
+		bytes  int
+		reason AudioEvictReason
+	}
+	var evictions []eviction
+	assembler := NewAudioAssemblerWithOptions(AudioAssemblerOptions{
+		MaxBytes: 10,
+		OnEvict: func(id string, n int, reason AudioEvictReason) {
+			evictions = append(evictions, eviction{id, n, reason})
+		},
+	})
+	add := func(id, data string) {
+		t.Helper()
+		if err := assembler.OnDelta(ResponseAudioDelta{ResponseID: id, DeltaBase64: base64.StdEncoding.EncodeToString([]byte(data))}); err != nil {
+			t.Fatalf("OnDelta: %v", err)
+		}
+	}
+
+	add("old", "aaaaaa")
+	add("new", "bbbbbb") // 12 bytes total: "old" is least recently updated
+	if want := []eviction{{"old", 6, AudioEvictMaxBytes}}; !reflect.DeepEqual(evictions, want) {
+		t.Errorf("evictions = %v, want %v", evictions, want)
+	}
+	add("old", "more") // Dropped responses stay dropped
+	if got := assembler.OnDone("old"); got != nil {
+		t.Errorf("OnDone(old) = %q, want nil", got)
+	}
+	if got := assembler.Buffered(); got != 6 {
+		t.Errorf("Buffered() = %d, want 6", got)
+	}
+
+	evictions = nil
+	add("new", "ccccc") // "new" alone exceeds the limit
+	if want := []eviction{{"new", 11, AudioEvictMaxBytes}}; !reflect.DeepEqual(evictions, want) {
+		t.Errorf("evictions = %v, want %v", evictions, want)
+	}
+	if got := assembler.OnDone("new"); got != nil {
+		t.Errorf("OnDone(new) = %q, want nil", got)
+	}
+	if got := assembler.Buffered(); got != 0 {
+		t.Errorf("Buffered() = %d, want 0", got)
+	}
+}
+
+func TestAudioAssembler_TTLAndReset(t *testing.T) {
+	evicted := map[string]AudioEvictReason{}
+	assembler := NewAudioAssemblerWithOptions(AudioAssemblerOptions{
+		TTL:     20 * time.Millisecond,
+		OnEvict: func(id string, n int, reason AudioEvictReason) { evicted[id] = reason },
+	})
+	delta := func(id string) ResponseAudioDelta {
+		return ResponseAudioDelta{ResponseID: id, DeltaBase64: base64.StdEncoding.EncodeToString([]byte("pcm"))}
+	}
+
+	_ = assembler.OnDelta(delta("stale"))
+	time.Sleep(40 * time.Millisecond)
+	_ = assembler.OnDelta(delta("fresh"))
+	if evicted["stale"] != AudioEvictTTL {
+		t.Errorf("stale response not evicted by TTL: %v", evicted)
+	}
+	if got := assembler.Buffered(); got != 3 {
+		t.Errorf("Buffered() = %d, want 3", got)
+	}
+
+	assembler.Reset()
+	if evicted["fresh"] != AudioEvictReset {
+		t.Errorf("fresh response not evicted by Reset: %v", evicted)
+	}
+	if got := assembler.OnDone("fresh"); got != nil {
+		t.Errorf("OnDone after Reset = %q, want nil", got)
+	}
+	if got := assembler.Buffered(); got != 0 {
+		t.Errorf("Buffered() = %d, want 0", got)
+	}
+}
+
+func TestAudioAssembler_MaxBytesWithoutTTL(t *testing.T) {
+	assembler := NewAudioAssemblerWithOptions(AudioAssemblerOptions{MaxBytes: 4})
+	add := func(id string) {
+		t.Helper()
+		if err := assembler.OnDelta(ResponseAudioDelta{ResponseID: id, DeltaBase64: base64.StdEncoding.EncodeToString([]byte("pcm16"))}); err != nil {
+			t.Fatalf("OnDelta: %v", err)
+		}
+	}
+
+	// Each response exceeds MaxBytes and is cancelled without OnDone
+	for i := 0; i < 3*maxDroppedAudio; i++ {
+		add(fmt.Sprintf("resp_%d", i))
+	}
+	assembler.mu.Lock()
+	n := len(assembler.data)
+	assembler.mu.Unlock()
+	if n > maxDroppedAudio {
+		t.Errorf("%d responses remembered, want at most %d", n, maxDroppedAudio)
+	}
+
+	// The most recent ones still discard their later deltas
+	add(fmt.Sprintf("resp_%d", 3*maxDroppedAudio-1))
+	if got := assembler.Buffered(); got != 0 {
+		t.Errorf("Buffered() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,130 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// AudioTimeline tracks how much assistant audio has been received and how much
+// the application has actually played, so that on barge-in the conversation
+// can be truncated at what the user heard rather than at what the server sent:
+//
+//	tl := azrealtime.NewAudioTimeline(azrealtime.DefaultSampleRate)
+//	client.OnResponseAudioDelta(func(e azrealtime.ResponseAudioDelta) { tl.OnDelta(e) })
+//	client.OnInputAudioBufferSpeechStarted(func(azrealtime.InputAudioBufferSpeechStarted) {
+//		player.Stop()
+//		tl.Truncate(ctx, client)
+//	})
+//	// In the playback loop, after each buffer reaches the speaker:
+//	tl.Played(len(buf))
+//
+// Audio is assumed to be played in the order it was received. An AudioTimeline
+// is safe for concurrent use.
+type AudioTimeline struct {
+	bytesPerSec int
+
+	mu       sync.Mutex
+	segments []audioSegment // Received audio not yet fully played, in order
+	played   int            // Bytes of segments[0] already played
+}
+
+// audioSegment is the audio received for one content part of an output item.
+type audioSegment struct {
+	itemID       string
+	contentIndex int
+	bytes        int
+}
+
+// NewAudioTimeline creates an AudioTimeline for PCM16 mono audio at sampleRate.
+// A sampleRate of zero uses DefaultSampleRate.
+func NewAudioTimeline(sampleRate int) *AudioTimeline {
+	if sampleRate <= 0 {
+		sampleRate = DefaultSampleRate
+	}
+	return &AudioTimeline{bytesPerSec: 2 * sampleRate}
+}
+
+// OnDelta records the audio of a response.audio.delta event as received.
+func (t *AudioTimeline) OnDelta(e ResponseAudioDelta) error {
+	if len(e.DeltaBase64)%4 != 0 {
+		return base64.CorruptInputError(len(e.DeltaBase64))
+	}
+	t.Received(e.ItemID, e.ContentIndex, base64DecodedLen(e.DeltaBase64))
+	return nil
+}
+
+// Received records n bytes of PCM16 audio received for an item's content part.
+func (t *AudioTimeline) Received(itemID string, contentIndex, n int) {
+	if n <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if last := len(t.segments) - 1; last >= 0 && t.segments[last].itemID == itemID && t.segments[last].contentIndex == contentIndex {
+		t.segments[last].bytes += n
+		return
+	}
+	t.segments = append(t.segments, audioSegment{itemID: itemID, contentIndex: contentIndex, bytes: n})
+}
+
+// Played advances the play head by n bytes of PCM16 audio that reached the
+// speaker. Playing past the received audio is ignored.
+func (t *AudioTimeline) Played(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.played += n
+	// Drop fully played segments, but keep the last one so a finished item
+	// still reports its full length
+	for len(t.segments) > 1 && t.played >= t.segments[0].bytes {
+		t.played -= t.segments[0].bytes
+		t.segments = t.segments[1:]
+	}
+	if len(t.segments) == 1 && t.played > t.segments[0].bytes {
+		t.played = t.segments[0].bytes
+	}
+	if len(t.segments) == 0 {
+		t.played = 0
+	}
+}
+
+// PlayedFor advances the play head by a duration of audio.
+func (t *AudioTimeline) PlayedFor(d time.Duration) {
+	n := int(int64(d) * int64(t.bytesPerSec) / int64(time.Second))
+	t.Played(n - n%2)
+}
+
+// Position returns the item and content part under the play head and how many
+// milliseconds of it have been played: the arguments for
+// Client.TruncateConversationItem. ok is false when no received audio is left
+// unplayed, so there is nothing to truncate.
+func (t *AudioTimeline) Position() (itemID string, contentIndex, audioEndMs int, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.segments) == 0 || (len(t.segments) == 1 && t.played >= t.segments[0].bytes) {
+		return "", 0, 0, false
+	}
+	seg := t.segments[0]
+	ms := int(int64(t.played) * 1000 / int64(t.bytesPerSec))
+	return seg.itemID, seg.contentIndex, ms, true
+}
+
+// Truncate tells the server to cut the item under the play head at the played
+// position, so the conversation history matches what the user heard, and then
+// clears the timeline. It does nothing if all received audio has been played.
+func (t *AudioTimeline) Truncate(ctx context.Context, c *Client) error {
+	itemID, contentIndex, audioEndMs, ok := t.Position()
+	t.Reset()
+	if !ok {
+		return nil
+	}
+	return c.TruncateConversationItem(ctx, itemID, contentIndex, audioEndMs)
+}
+
+// Reset clears the timeline, e.g. after a truncation or reconnect.
+func (t *AudioTimeline) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.segments, t.played = nil, 0
+}
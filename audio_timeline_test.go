@@ -0,0 +1,105 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func audioDelta(itemID string, contentIndex int, d time.Duration) ResponseAudioDelta {
+	pcm := make([]byte, PCM16BytesFor(int(d/time.Millisecond), DefaultSampleRate))
+	return ResponseAudioDelta{ItemID: itemID, ContentIndex: contentIndex, DeltaBase64: base64.StdEncoding.EncodeToString(pcm)}
+}
+
+func TestAudioTimeline_Position(t *testing.T) {
+	tl := NewAudioTimeline(0)
+	if _, _, _, ok := tl.Position(); ok {
+		t.Error("empty timeline reported a position")
+	}
+
+	for _, e := range []ResponseAudioDelta{
+		audioDelta("item_a", 0, 300*time.Millisecond),
+		audioDelta("item_a", 0, 200*time.Millisecond),
+		audioDelta("item_b", 0, 400*time.Millisecond),
+	} {
+		if err := tl.OnDelta(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := func(wantItem string, wantMs int) {
+		t.Helper()
+		item, idx, ms, ok := tl.Position()
+		if !ok || item != wantItem || idx != 0 || ms != wantMs {
+			t.Errorf("Position = %q/%d at %dms (ok %v), want %q at %dms", item, idx, ms, ok, wantItem, wantMs)
+		}
+	}
+	check("item_a", 0)
+	tl.PlayedFor(250 * time.Millisecond)
+	check("item_a", 250)
+	tl.PlayedFor(300 * time.Millisecond) // Crosses into the second item
+	check("item_b", 50)
+
+	tl.PlayedFor(time.Second) // Everything played
+	if _, _, _, ok := tl.Position(); ok {
+		t.Error("fully played timeline reported a position")
+	}
+}
+
+func TestAudioTimeline_OnDeltaInvalid(t *testing.T) {
+	tl := NewAudioTimeline(0)
+	if err := tl.OnDelta(ResponseAudioDelta{ItemID: "x", DeltaBase64: "abc"}); err == nil {
+		t.Error("expected error for malformed base64")
+	}
+}
+
+func TestAudioTimeline_Truncate(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	sent := make(chan map[string]any, 1)
+	client.OnRawEvent(func(dir EventDirection, data []byte) {
+		var m map[string]any
+		_ = json.Unmarshal(data, &m)
+		if dir == DirectionOutbound && m["type"] == "conversation.item.truncate" {
+			sent <- m
+		}
+	})
+
+	tl := NewAudioTimeline(0)
+	_ = tl.OnDelta(audioDelta("item_a", 1, time.Second))
+	tl.PlayedFor(420 * time.Millisecond)
+	if err := tl.Truncate(ctx, client); err != nil {
+		t.Fatalf("Truncate: %v", err)
+	}
+
+	select {
+	case m := <-sent:
+		if m["item_id"] != "item_a" || m["content_index"] != float64(1) || m["audio_end_ms"] != float64(420) {
+			t.Errorf("truncate event = %v", m)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no truncate event sent")
+	}
+
+	// The timeline is cleared, so a second barge-in sends nothing
+	if err := tl.Truncate(ctx, client); err != nil {
+		t.Errorf("second Truncate: %v", err)
+	}
+	select {
+	case m := <-sent:
+		t.Errorf("unexpected second truncate: %v", m)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,226 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// DefaultReconnectBufferSize is the number of events buffered while the client
+// reconnects when AutoReconnectConfig.BufferSize is zero.
+const DefaultReconnectBufferSize = 64
+
+// ErrReconnectBufferFull is returned by sends made while the client is
+// reconnecting once AutoReconnectConfig.BufferSize events are buffered.
+var ErrReconnectBufferFull = errors.New("azrealtime: reconnect buffer full")
+
+// AutoReconnectConfig configures Config.AutoReconnect.
+//
+// While the client reconnects, outbound events are handled as follows:
+//   - response.create and conversation.item.* events (CreateResponse,
+//     CreateConversationItem, ...) are buffered and sent in order once the new
+//     connection is up; the call returns nil as soon as the event is buffered.
+//   - session.update is not buffered: SessionUpdate returns nil and its fields
+//     are part of the session configuration restored on the new session.
+//   - input audio (input_audio_buffer.append, .commit and .clear) and
+//     response.cancel are dropped, since the audio and any response in progress
+//     belonged to the lost session. The calls return nil.
+//
+// Once the new connection is up, session.update and input audio go straight
+// to it, while buffered events are still being flushed.
+//
+// If every dial attempt fails, buffered events are discarded and the client
+// closes with Err reporting the original read failure.
+type AutoReconnectConfig struct {
+	// Retry controls the dial attempts and the backoff between them.
+	// If zero, DefaultRetryConfig() is used.
+	Retry RetryConfig
+
+	// BufferSize limits the number of events buffered while reconnecting.
+	// If zero, DefaultReconnectBufferSize is used.
+	BufferSize int
+}
+
+// outageBuffer holds outbound events while the client reconnects after a lost
+// connection.
+type outageBuffer struct {
+	mu        sync.Mutex
+	active    bool
+	connected bool // The new connection is up and the buffer is being flushed
+	limit     int
+	events    []any
+	dropped   int // Input audio events dropped during the current outage
+}
+
+// start begins buffering. It returns false if an outage is already in progress.
+func (b *outageBuffer) start(limit int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.active {
+		return false
+	}
+	b.active, b.connected, b.limit, b.events, b.dropped = true, false, limit, nil, 0
+	return true
+}
+
+// hold buffers or drops payload if an outage is in progress, reporting whether
+// it did so and the error to return to the caller.
+func (b *outageBuffer) hold(payload any) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.active {
+		return false, nil
+	}
+	typ := payloadType(payload)
+	if b.connected && (typ == "session.update" || strings.HasPrefix(typ, "input_audio_buffer.")) {
+		return false, nil
+	}
+	switch {
+	case typ == "session.update", typ == "response.cancel":
+		return true, nil
+	case strings.HasPrefix(typ, "input_audio_buffer."):
+		b.dropped++
+		return true, nil
+	case len(b.events) >= b.limit:
+		return true, NewSendError(typ, "", ErrReconnectBufferFull)
+	}
+	b.events = append(b.events, payload)
+	return true, nil
+}
+
+// connect marks the new connection as up, so session updates and input audio
+// are no longer held back while the buffer is flushed.
+func (b *outageBuffer) connect() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.active {
+		b.connected = true
+	}
+}
+
+// next removes and returns the oldest buffered event. When none remain it ends
+// the outage, so later sends go straight to the connection.
+func (b *outageBuffer) next() (any, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.events) == 0 {
+		b.active = false
+		return nil, false
+	}
+	payload := b.events[0]
+	b.events = b.events[1:]
+	return payload, true
+}
+
+// discard ends the outage and returns the number of buffered events dropped.
+func (b *outageBuffer) discard() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(b.events)
+	b.active, b.events = false, nil
+	return n
+}
+
+func (b *outageBuffer) droppedAudio() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// payloadType returns the "type" of an outbound event payload.
+func payloadType(payload any) string {
+	if m, ok := payload.(map[string]any); ok {
+		if typ, ok := m["type"].(string); ok {
+			return typ
+		}
+	}
+	return "unknown"
+}
+
+// autoReconnect redials after conn was lost with readErr, then flushes the
+// events buffered in the meantime. If all attempts fail, the client closes.
+func (c *Client) autoReconnect(conn *websocket.Conn, readErr error) {
+	policy := *c.cfg.AutoReconnect
+	retry := policy.Retry
+	if retry.MaxRetries == 0 && retry.BaseDelay == 0 {
+		retry = DefaultRetryConfig()
+	}
+	classify := retry.RetryableErrors
+	retry.RetryableErrors = func(err error) bool {
+		if errors.Is(err, ErrClosed) {
+			return false
+		}
+		return classify == nil || classify(err)
+	}
+
+	// Stop retrying as soon as the client is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.closedCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	c.logWarn("ws_reconnecting", map[string]any{"err": readErr})
+	err := WithRetry(ctx, retry, func() error {
+		timeout := c.cfg.DialTimeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		dialCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return c.Reconnect(dialCtx)
+	})
+	if err != nil {
+		dropped := c.outage.discard()
+		c.logError("ws_reconnect_failed", map[string]any{"err": err, "discarded": dropped})
+		c.writeMu.Lock()
+		if c.conn != conn {
+			// Closed by Close, or replaced by a manual Reconnect
+			c.writeMu.Unlock()
+			return
+		}
+		c.conn = nil
+		c.closeErr = NewConnectionError(c.handshake.URL, "read", readErr)
+		c.writeMu.Unlock()
+		c.closeOnce.Do(func() {
+			close(c.closedCh)
+		})
+		return
+	}
+
+	// Flush in order; events sent meanwhile queue behind the buffered ones
+	flushed := 0
+	for {
+		payload, ok := c.outage.next()
+		if !ok {
+			break
+		}
+		if err := c.sendNow(ctx, payload); err != nil {
+			dropped := c.outage.discard()
+			c.logError("reconnect_flush_failed", map[string]any{"err": err, "discarded": dropped + 1})
+			return
+		}
+		flushed++
+	}
+	c.log("reconnect_buffer_flushed", map[string]any{"events": flushed, "dropped_audio": c.outage.droppedAudio()})
+}
+
+// validate checks an AutoReconnectConfig for ValidateConfig.
+func (p *AutoReconnectConfig) validate() error {
+	if p.BufferSize < 0 {
+		return NewConfigError("AutoReconnect.BufferSize", fmt.Sprint(p.BufferSize), "must not be negative")
+	}
+	if p.Retry.MaxRetries < 0 {
+		return NewConfigError("AutoReconnect.Retry.MaxRetries", fmt.Sprint(p.Retry.MaxRetries), "must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func dialAutoReconnect(t *testing.T, policy AutoReconnectConfig) (*Client, *MockServer) {
+	t.Helper()
+	mockServer := NewMockServer(t)
+	cfg := CreateMockConfig(mockServer.URL())
+	cfg.AutoReconnect = &policy
+	client, err := Dial(context.Background(), cfg)
+	if err != nil {
+		mockServer.Close()
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		mockServer.Close()
+	})
+	return client, mockServer
+}
+
+// waitUntil polls cond until it holds or the test times out.
+func waitUntil(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (b *outageBuffer) isActive() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.active
+}
+
+func TestClient_AutoReconnect_BuffersAndFlushes(t *testing.T) {
+	client, mockServer := dialAutoReconnect(t, AutoReconnectConfig{
+		Retry:      RetryConfig{MaxRetries: 20, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond, Multiplier: 2},
+		BufferSize: 2,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.SessionUpdate(ctx, Session{Voice: Ptr(VoiceAlloy)}); err != nil {
+		t.Fatalf("SessionUpdate: %v", err)
+	}
+
+	mockServer.Reject.Store(true)
+	mockServer.DropConnections()
+	waitUntil(t, "the outage to start", client.outage.isActive)
+	sent := recordOutbound(client)
+
+	if err := client.AppendPCM16(ctx, make([]byte, 320)); err != nil {
+		t.Errorf("AppendPCM16 while reconnecting = %v, want nil (dropped)", err)
+	}
+	if err := client.SessionUpdate(ctx, Session{Instructions: Ptr("Be brief.")}); err != nil {
+		t.Errorf("SessionUpdate while reconnecting = %v", err)
+	}
+	if err := client.CreateConversationItem(ctx, NewUserTextItem("Hello?")); err != nil {
+		t.Errorf("CreateConversationItem while reconnecting = %v", err)
+	}
+	if _, err := client.CreateResponse(ctx, CreateResponseOptions{Modalities: []string{"text"}}); err != nil {
+		t.Errorf("CreateResponse while reconnecting = %v", err)
+	}
+	if err := client.DeleteConversationItem(ctx, "item_1"); !errors.Is(err, ErrReconnectBufferFull) {
+		t.Errorf("send beyond BufferSize = %v, want ErrReconnectBufferFull", err)
+	}
+	select {
+	case <-client.Done():
+		t.Fatal("client closed while reconnecting")
+	default:
+	}
+
+	mockServer.Reject.Store(false)
+	waitUntil(t, "the buffer to flush", func() bool { return len(sent.ofType("response.create")) == 1 })
+
+	sent.mu.Lock()
+	var types []string
+	for _, e := range sent.events {
+		types = append(types, e["type"].(string))
+	}
+	sent.mu.Unlock()
+	want := []string{"session.update", "conversation.item.create", "response.create"}
+	if len(types) < 3 || types[0] != want[0] || types[1] != want[1] || types[2] != want[2] {
+		t.Fatalf("sent %v, want %v first", types, want)
+	}
+	if n := len(sent.ofType("input_audio_buffer.append")); n != 0 {
+		t.Errorf("sent %d audio appends buffered during the outage", n)
+	}
+	restored := sent.ofType("session.update")[0]["session"].(map[string]any)
+	if restored["voice"] != VoiceAlloy || restored["instructions"] != "Be brief." {
+		t.Errorf("restored session = %v", restored)
+	}
+
+	// Sends go straight to the new connection once flushed
+	if err := client.InputCommit(ctx); err != nil {
+		t.Fatalf("InputCommit after reconnect: %v", err)
+	}
+	if n := len(sent.ofType("input_audio_buffer.commit")); n != 1 {
+		t.Errorf("sent %d input_audio_buffer.commit after reconnect, want 1", n)
+	}
+	if got := client.Stats().Reconnects; got != 1 {
+		t.Errorf("Reconnects = %d, want 1", got)
+	}
+}
+
+func TestClient_AutoReconnect_SendsDuringFlush(t *testing.T) {
+	client, mockServer := dialAutoReconnect(t, AutoReconnectConfig{
+		Retry: RetryConfig{MaxRetries: 20, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond, Multiplier: 2},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var applied []string
+	client.OnSessionUpdated(func(e SessionUpdated) {
+		mu.Lock()
+		applied = append(applied, e.Session.Instructions)
+		mu.Unlock()
+	})
+
+	mockServer.Reject.Store(true)
+	mockServer.DropConnections()
+	waitUntil(t, "the outage to start", client.outage.isActive)
+	if err := client.SessionUpdate(ctx, Session{Instructions: Ptr("during the outage")}); err != nil {
+		t.Fatalf("SessionUpdate while reconnecting: %v", err)
+	}
+	if err := client.CreateConversationItem(ctx, NewUserTextItem("Hello?")); err != nil {
+		t.Fatalf("CreateConversationItem while reconnecting: %v", err)
+	}
+
+	// Hold the flush on its first buffered event, with the new connection up
+	flushing, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	var audio atomic.Int32
+	client.OnRawEvent(func(dir EventDirection, data []byte) {
+		if dir != DirectionOutbound {
+			return
+		}
+		switch {
+		case strings.Contains(string(data), `"conversation.item.create"`):
+			once.Do(func() {
+				close(flushing)
+				<-release
+			})
+		case strings.Contains(string(data), `"input_audio_buffer.append"`):
+			audio.Add(1)
+		}
+	})
+	mockServer.Reject.Store(false)
+	select {
+	case <-flushing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("buffered events were not flushed")
+	}
+
+	if err := client.SessionUpdate(ctx, Session{Instructions: Ptr("after reconnecting")}); err != nil {
+		t.Errorf("SessionUpdate during the flush: %v", err)
+	}
+	if err := client.AppendPCM16(ctx, make([]byte, 320)); err != nil {
+		t.Errorf("AppendPCM16 during the flush: %v", err)
+	}
+	close(release)
+
+	waitUntil(t, "the server to apply the update", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(applied) > 0 && applied[len(applied)-1] == "after reconnecting"
+	})
+	mu.Lock()
+	if applied[0] != "during the outage" {
+		t.Errorf("server applied %q, want the restored session first", applied)
+	}
+	mu.Unlock()
+	if n := audio.Load(); n != 1 {
+		t.Errorf("sent %d audio appends during the flush, want 1", n)
+	}
+}
+
+func TestClient_AutoReconnect_GivesUp(t *testing.T) {
+	client, mockServer := dialAutoReconnect(t, AutoReconnectConfig{
+		Retry: RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1},
+	})
+
+	mockServer.Reject.Store(true)
+	mockServer.DropConnections()
+	select {
+	case <-client.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("client not closed after reconnect attempts failed")
+	}
+	var connErr *ConnectionError
+	if err := client.Err(); !errors.As(err, &connErr) || connErr.Operation != "read" {
+		t.Errorf("Err() = %v, want the read error", err)
+	}
+	if err := client.CreateConversationItem(context.Background(), NewUserTextItem("Hi")); !errors.Is(err, ErrClosed) {
+		t.Errorf("send after giving up = %v, want ErrClosed", err)
+	}
+}
+
+func TestValidateConfig_AutoReconnect(t *testing.T) {
+	cfg := CreateMockConfig("ws://localhost")
+	cfg.AutoReconnect = &AutoReconnectConfig{BufferSize: -1}
+	if err := ValidateConfig(cfg); err == nil {
+		t.Error("expected error for negative BufferSize")
+	}
+}
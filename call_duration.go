@@ -0,0 +1,130 @@
+package azrealtime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults used by EnforceCallDuration when the corresponding policy field is zero.
+const (
+	DefaultCallWarnBefore  = time.Minute
+	DefaultCallGracePeriod = 15 * time.Second
+
+	DefaultWrapUpInstructions = "The call is about to reach its time limit. Politely let the user know, " +
+		"briefly summarize any next steps, and say goodbye."
+)
+
+// CallDurationPolicy limits how long a conversation may run.
+type CallDurationPolicy struct {
+	// MaxDuration is the maximum call length, measured from EnforceCallDuration. Required.
+	MaxDuration time.Duration
+
+	// WarnBefore is how long before MaxDuration the wrap-up response is requested.
+	// Default: DefaultCallWarnBefore (capped at MaxDuration).
+	WarnBefore time.Duration
+
+	// WrapUpInstructions are sent as response-level instructions for the wrap-up
+	// response; the session instructions are left untouched.
+	// Default: DefaultWrapUpInstructions.
+	WrapUpInstructions string
+
+	// GracePeriod is how long to wait at the limit for the wrap-up response to
+	// finish before the client is closed. Default: DefaultCallGracePeriod.
+	GracePeriod time.Duration
+
+	// OnWarning is called when the wrap-up stage starts, with the time remaining.
+	OnWarning func(remaining time.Duration)
+
+	// OnLimitReached is called when MaxDuration elapses, before the grace period.
+	OnLimitReached func()
+
+	// OnEnded is called after the client has been closed by the policy, with any
+	// error from requesting the wrap-up response.
+	OnEnded func(err error)
+}
+
+// EnforceCallDuration starts enforcing policy on the client. Near the limit it
+// requests a wrap-up response in the conversation, so the caller hears it and
+// it stays in the history (WrapUpInstructions apply to that response only).
+// Once the limit is reached it waits up to GracePeriod for the wrap-up to
+// finish, then closes the client. Callbacks are invoked at each stage.
+//
+// The returned function stops enforcement; it is safe to call more than once.
+func (c *Client) EnforceCallDuration(policy CallDurationPolicy) (stop func(), err error) {
+	if policy.MaxDuration <= 0 {
+		return nil, NewConfigError("MaxDuration", policy.MaxDuration.String(), "must be positive")
+	}
+	if policy.WarnBefore <= 0 {
+		policy.WarnBefore = DefaultCallWarnBefore
+	}
+	if policy.WarnBefore > policy.MaxDuration {
+		policy.WarnBefore = policy.MaxDuration
+	}
+	if policy.WrapUpInstructions == "" {
+		policy.WrapUpInstructions = DefaultWrapUpInstructions
+	}
+	if policy.GracePeriod <= 0 {
+		policy.GracePeriod = DefaultCallGracePeriod
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var once sync.Once
+	stop = func() { once.Do(cancel) }
+
+	go c.runCallDuration(ctx, policy, time.Now())
+	return stop, nil
+}
+
+// runCallDuration drives the warning, limit and close stages of a policy.
+func (c *Client) runCallDuration(ctx context.Context, policy CallDurationPolicy, start time.Time) {
+	wait := func(until time.Time) bool {
+		t := time.NewTimer(time.Until(until))
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return false
+		case <-c.closedCh:
+			return false
+		case <-t.C:
+			return true
+		}
+	}
+
+	limit := start.Add(policy.MaxDuration)
+	if !wait(limit.Add(-policy.WarnBefore)) {
+		return
+	}
+	if policy.OnWarning != nil {
+		policy.OnWarning(time.Until(limit))
+	}
+	_, tag, wrapErr := c.CreateTaggedResponse(ctx, CreateResponseOptions{Instructions: policy.WrapUpInstructions})
+
+	if !wait(limit) {
+		return
+	}
+	if policy.OnLimitReached != nil {
+		policy.OnLimitReached()
+	}
+
+	// Let the wrap-up finish speaking before hanging up
+	if wrapErr == nil {
+		deadline := time.Now().Add(policy.GracePeriod)
+		for time.Now().Before(deadline) {
+			if r, ok := c.ResponseByTag(tag); !ok || r.Done {
+				break
+			}
+			if !wait(time.Now().Add(50 * time.Millisecond)) {
+				break
+			}
+		}
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+	_ = c.Close()
+	if policy.OnEnded != nil {
+		policy.OnEnded(wrapErr)
+	}
+}
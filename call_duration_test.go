@@ -0,0 +1,96 @@
+package azrealtime
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_EnforceCallDuration(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	var mu sync.Mutex
+	var stages []string
+	record := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		stages = append(stages, s)
+	}
+	ended := make(chan error, 1)
+
+	var wrapUp string
+	client.OnResponseTextDone(func(e ResponseTextDone) {
+		mu.Lock()
+		defer mu.Unlock()
+		wrapUp = e.Text
+	})
+
+	_, err = client.EnforceCallDuration(CallDurationPolicy{
+		MaxDuration:    300 * time.Millisecond,
+		WarnBefore:     200 * time.Millisecond,
+		OnWarning:      func(time.Duration) { record("warning") },
+		OnLimitReached: func() { record("limit") },
+		OnEnded:        func(err error) { record("ended"); ended <- err },
+	})
+	if err != nil {
+		t.Fatalf("EnforceCallDuration: %v", err)
+	}
+
+	select {
+	case err := <-ended:
+		if err != nil {
+			t.Errorf("unexpected wrap-up error: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("policy did not end the call")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(stages) != 3 || stages[0] != "warning" || stages[1] != "limit" || stages[2] != "ended" {
+		t.Errorf("unexpected stages: %v", stages)
+	}
+	if wrapUp == "" {
+		t.Error("expected a wrap-up response before the call ended")
+	}
+	if err := client.SessionUpdate(ctx, Session{}); err == nil {
+		t.Error("expected the client to be closed")
+	}
+}
+
+func TestClient_EnforceCallDuration_Stop(t *testing.T) {
+	client := NewReplayClient(CreateMockConfig("ws://localhost"))
+
+	if _, err := client.EnforceCallDuration(CallDurationPolicy{}); err == nil {
+		t.Error("expected error without MaxDuration")
+	}
+
+	warned := make(chan struct{}, 1)
+	stop, err := client.EnforceCallDuration(CallDurationPolicy{
+		MaxDuration: 100 * time.Millisecond,
+		WarnBefore:  50 * time.Millisecond,
+		OnWarning:   func(time.Duration) { warned <- struct{}{} },
+	})
+	if err != nil {
+		t.Fatalf("EnforceCallDuration: %v", err)
+	}
+	stop()
+	stop()
+
+	select {
+	case <-warned:
+		t.Error("stopped policy should not warn")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,117 @@
+package azrealtime
+
+import (
+	"context"
+	"sync"
+)
+
+// Checkpoint is a snapshot of the conversation's item IDs, in order, taken with
+// Client.Checkpoint. Pass it to Client.Rollback to delete everything added since.
+type Checkpoint struct {
+	ItemIDs []string // Conversation item IDs present when the checkpoint was taken
+}
+
+// Contains reports whether the item was part of the conversation at the checkpoint.
+func (cp Checkpoint) Contains(itemID string) bool {
+	for _, id := range cp.ItemIDs {
+		if id == itemID {
+			return true
+		}
+	}
+	return false
+}
+
+// conversationLog mirrors the server-side conversation item order from
+// conversation.item.created and conversation.item.deleted events.
+type conversationLog struct {
+	mu    sync.Mutex
+	items []string
+}
+
+// created inserts itemID after previousID, or appends it if previousID is unknown.
+func (l *conversationLog) created(itemID, previousID string) {
+	if itemID == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range l.items {
+		if id == itemID {
+			return
+		}
+	}
+	pos := len(l.items)
+	if previousID == "" && len(l.items) > 0 {
+		// The server inserted the item at the start of the conversation
+		pos = 0
+	}
+	for i, id := range l.items {
+		if id == previousID {
+			pos = i + 1
+			break
+		}
+	}
+	l.items = append(l.items, "")
+	copy(l.items[pos+1:], l.items[pos:])
+	l.items[pos] = itemID
+}
+
+// deleted removes itemID from the log.
+func (l *conversationLog) deleted(itemID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, id := range l.items {
+		if id == itemID {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+			return
+		}
+	}
+}
+
+// reset forgets all items, e.g. when a new session starts.
+func (l *conversationLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = nil
+}
+
+// snapshot returns a copy of the current item IDs.
+func (l *conversationLog) snapshot() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.items...)
+}
+
+// ConversationItemIDs returns the IDs of the items currently in the conversation,
+// in conversation order, as reported by the server.
+func (c *Client) ConversationItemIDs() []string {
+	return c.conversation.snapshot()
+}
+
+// Checkpoint records the current conversation so it can be restored with Rollback,
+// e.g. before an exploratory tool run or to offer "undo that last exchange".
+func (c *Client) Checkpoint() Checkpoint {
+	return Checkpoint{ItemIDs: c.conversation.snapshot()}
+}
+
+// Rollback deletes every conversation item created after cp was taken, newest
+// first, and returns the IDs it asked the server to delete. Items are removed from
+// ConversationItemIDs as the server confirms each deletion. Cancel any in-progress
+// response first, or its output may be added after the rollback.
+//
+// On error, the returned IDs are the deletions sent before the failure.
+func (c *Client) Rollback(ctx context.Context, cp Checkpoint) ([]string, error) {
+	current := c.conversation.snapshot()
+	var deleted []string
+	for i := len(current) - 1; i >= 0; i-- {
+		id := current[i]
+		if cp.Contains(id) {
+			continue
+		}
+		if err := c.DeleteConversationItem(ctx, id); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, id)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,84 @@
+package azrealtime
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConversationLog_Order(t *testing.T) {
+	var l conversationLog
+	l.created("a", "")
+	l.created("c", "a")
+	l.created("b", "a")    // inserted between a and c
+	l.created("z", "")     // inserted at the start
+	l.created("d", "nope") // unknown predecessor: appended
+	l.created("b", "c")    // duplicate: ignored
+
+	if got, want := l.snapshot(), []string{"z", "a", "b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	l.deleted("b")
+	l.deleted("missing")
+	if got, want := l.snapshot(), []string{"z", "a", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	l.reset()
+	if len(l.snapshot()) != 0 {
+		t.Error("expected empty log after reset")
+	}
+}
+
+func TestClient_CheckpointRollback(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	createItems := func(n int) {
+		for i := 0; i < n; i++ {
+			item := ConversationItem{Type: "message", Role: "user", Content: []ContentPart{{Type: "input_text", Text: "hi"}}}
+			if err := client.CreateConversationItem(ctx, item); err != nil {
+				t.Fatalf("CreateConversationItem: %v", err)
+			}
+		}
+	}
+	waitForItems := func(n int) {
+		deadline := time.Now().Add(2 * time.Second)
+		for len(client.ConversationItemIDs()) != n {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d items, got %v", n, client.ConversationItemIDs())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	createItems(2)
+	waitForItems(2)
+	cp := client.Checkpoint()
+
+	createItems(2)
+	waitForItems(4)
+
+	deleted, err := client.Rollback(ctx, cp)
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if want := []string{"item_mock_4", "item_mock_3"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("expected deletions %v, got %v", want, deleted)
+	}
+	waitForItems(2)
+	if got := client.ConversationItemIDs(); !reflect.DeepEqual(got, cp.ItemIDs) {
+		t.Errorf("expected conversation %v after rollback, got %v", cp.ItemIDs, got)
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
+	"io"
+	"math"
 	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"nhooyr.io/websocket"
@@ -29,13 +32,48 @@ type Client struct {
 	readCancel context.CancelFunc // Cancels the read loop when closing
 	closedCh   chan struct{}      // Signals when the client is closed
 	closeOnce  sync.Once          // Ensures closedCh is only closed once
+	closeErr   error              // Why the client stopped, reported by Err (protected by writeMu)
+	handshake  HandshakeInfo      // Metadata from the current connection's handshake (protected by writeMu)
+
+	sessionUpdates SessionUpdateQueue   // Serializes and coalesces session.update requests
+	responseTags   responseTagTracker   // Tracks responses created with a correlation tag
+	conversation   conversationLog      // Mirrors conversation item order for Checkpoint/Rollback
+	expiry         sessionExpiry        // Tracks server session expiry for OnSessionExpiring
+	stats          clientStats          // Traffic counters reported by Stats
+	rtt            atomic.Int64         // Last measured ping round-trip time (nanoseconds)
+	rateLimitsMu   sync.RWMutex         // Protects rateLimits
+	rateLimits     *RateLimitsUpdated   // Most recent rate_limits.updated event
+	limiter        rateLimiter          // Local quota tracking for Config.RateLimitMode
+	audioProgress  audioProgressTracker // Emits OnResponseAudioProgress reports
+	report         sessionReportTracker // Accumulates the SessionReport produced on close
+	recovering     atomic.Bool          // Set while an error-triggered reconnect runs
+	interrupts     interruptTracker     // Active response and audio item for Interrupt
+	transcript     transcriptTracker    // Conversation text for Transcript
+	trackers       responseTrackers     // ResponseTrackers from TrackResponses
+	outOfBand      outOfBandTracker     // In-progress out-of-band responses
+	conversations  conversationHandles  // Named conversations (see Client.Conversation)
+	tools          toolRunner           // Executes function calls for UseTools
+	outage         outageBuffer         // Events held while Config.AutoReconnect redials
+	integrity      streamIntegrity      // Response event ordering for Config.StreamIntegrityChecks
+	latency        latencyTracker       // Response timing for Stats and OnResponseLatency
+	correlation    correlation          // Correlation ID set with SetCorrelationID
+	transport      Transport            // Replaces conn for clients from NewTransportClient
+	isTransport    bool                 // Created by NewTransportClient; stays set after Close
+	samplerOnce    sync.Once            // Creates sampler on first use
+	sampler        *logSampler          // Config.LogSampling for per-event debug lines
 
 	// Event handlers - these functions are called when corresponding events are received
 	handlerMu                                          sync.RWMutex                                           // Protects event handler fields
+	onRawEvent                                         func(EventDirection, []byte)                           // Called with every raw inbound and outbound event
 	onError                                            func(ErrorEvent)                                       // Called for API errors
+	onErrorRecovery                                    func(ErrorEvent, error)                                // Called after an error-triggered reconnect
+	onInterrupt                                        func()                                                 // Called when Interrupt has run
+	onToolCall                                         func(ToolCall)                                         // Called after UseTools executed a function call
 	onSessionCreated                                   func(SessionCreated)                                   // Called when session is established
 	onSessionUpdated                                   func(SessionUpdated)                                   // Called when session config changes
+	onSessionExpiring                                  func(time.Duration)                                    // Called shortly before the session expires
 	onRateLimitsUpdated                                func(RateLimitsUpdated)                                // Called for rate limit updates
+	onResponseLatency                                  func(ResponseLatency)                                  // Called with each response's timing at response.done
 	onResponseTextDelta                                func(ResponseTextDelta)                                // Called for streaming text responses
 	onResponseTextDone                                 func(ResponseTextDone)                                 // Called when text response completes
 	onResponseAudioDelta                               func(ResponseAudioDelta)                               // Called for streaming audio responses
@@ -47,6 +85,7 @@ type Client struct {
 	onConversationItemCreated                          func(ConversationItemCreated)                          // Called when conversation item is created
 	onConversationItemInputAudioTranscriptionCompleted func(ConversationItemInputAudioTranscriptionCompleted) // Called when audio transcription completes
 	onConversationItemInputAudioTranscriptionFailed    func(ConversationItemInputAudioTranscriptionFailed)    // Called when audio transcription fails
+	onConversationItemInputAudioTranscriptionDelta     func(ConversationItemInputAudioTranscriptionDelta)     // Called for partial audio transcripts
 	onConversationItemTruncated                        func(ConversationItemTruncated)                        // Called when conversation item is truncated
 	onConversationItemDeleted                          func(ConversationItemDeleted)                          // Called when conversation item is deleted
 	onResponseCreated                                  func(ResponseCreated)                                  // Called when response is created
@@ -75,34 +114,44 @@ func Dial(ctx context.Context, cfg Config) (*Client, error) {
 		return nil, err
 	}
 
-	// Construct WebSocket URL from HTTP endpoint
-	u, err := url.Parse(cfg.ResourceEndpoint)
+	normalizeLoggers(&cfg)
+
+	ws, info, err := dialWebSocket(ctx, cfg)
 	if err != nil {
-		return nil, NewConfigError("ResourceEndpoint", cfg.ResourceEndpoint, "invalid URL format")
+		return nil, err
 	}
 
-	// Set WebSocket scheme based on HTTP scheme
-	if u.Scheme == "https" {
-		u.Scheme = "wss"
-	} else {
-		u.Scheme = "ws" // For HTTP (mainly for testing)
-	}
-	u.Path = "/openai/realtime"
-	q := u.Query()
-	q.Set("api-version", cfg.APIVersion)
-	q.Set("deployment", cfg.Deployment)
-	u.RawQuery = q.Encode()
+	// Create client and start background operations
+	c := &Client{cfg: cfg, conn: ws, closedCh: make(chan struct{}), handshake: info}
+	c.report.startedAt = time.Now()
+	c.transcript.startedAt = c.report.startedAt
+	c.log("ws_connected", map[string]any{"url": info.URL, "request_id": info.RequestID})
 
-	// Prepare authentication and custom headers
-	h := http.Header{}
-	if cfg.HandshakeHeaders != nil {
-		for k, vals := range cfg.HandshakeHeaders {
-			for _, v := range vals {
-				h.Add(k, v)
-			}
+	// Start read loop in separate goroutine
+	rcCtx, cancel := context.WithCancel(context.Background())
+	c.readCancel = cancel
+	go c.readLoop(rcCtx, ws)
+
+	// Start ping loop to maintain connection
+	go c.pingLoop()
+
+	// Start periodic stats export if requested
+	if cfg.StatsHandler != nil {
+		if c.cfg.StatsInterval == 0 {
+			c.cfg.StatsInterval = DefaultStatsInterval
 		}
+		go c.statsLoop()
+	}
+	return c, nil
+}
+
+// dialWebSocket performs the handshake described by HandshakeRequest. It
+// returns the connection and metadata from the handshake response.
+func dialWebSocket(ctx context.Context, cfg Config) (*websocket.Conn, HandshakeInfo, error) {
+	u, h, err := handshakeRequest(cfg)
+	if err != nil {
+		return nil, HandshakeInfo{}, err
 	}
-	cfg.Credential.apply(h)
 
 	// Apply dial timeout if specified
 	dialCtx := ctx
@@ -113,23 +162,104 @@ func Dial(ctx context.Context, cfg Config) (*Client, error) {
 	}
 
 	// Establish WebSocket connection
-	ws, _, err := websocket.Dial(dialCtx, u.String(), &websocket.DialOptions{HTTPHeader: h})
+	ws, resp, err := websocket.Dial(dialCtx, u.String(), &websocket.DialOptions{HTTPHeader: h})
+	info := NewHandshakeInfo(u.String(), resp)
 	if err != nil {
-		return nil, NewConnectionError(u.String(), "dial", err)
+		connErr := NewConnectionError(u.String(), "dial", err)
+		connErr.StatusCode = info.StatusCode
+		connErr.Header = info.Header
+		return nil, info, connErr
 	}
+	return ws, info, nil
+}
 
-	// Create client and start background operations
-	c := &Client{cfg: cfg, conn: ws, closedCh: make(chan struct{})}
-	c.log("ws_connected", map[string]any{"url": u.String()})
+// realtimeURL builds the WebSocket URL for cfg's provider.
+func realtimeURL(cfg Config) (*url.URL, error) {
+	endpoint := cfg.ResourceEndpoint
+	if endpoint == "" && cfg.Provider == ProviderOpenAI {
+		endpoint = DefaultOpenAIEndpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, NewConfigError("ResourceEndpoint", cfg.ResourceEndpoint, "invalid URL format")
+	}
 
-	// Start read loop in separate goroutine
+	// Set WebSocket scheme based on HTTP scheme
+	if u.Scheme == "https" || u.Scheme == "wss" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws" // For HTTP (mainly for testing)
+	}
+	// Keep any base path, e.g. of a gateway in front of a private endpoint
+	base := strings.TrimRight(u.Path, "/")
+	q := u.Query()
+	if cfg.Provider == ProviderOpenAI {
+		u.Path = base + "/v1/realtime"
+		q.Set("model", cfg.Deployment)
+	} else {
+		u.Path = base + "/openai/realtime"
+		q.Set("api-version", cfg.APIVersion)
+		q.Set("deployment", cfg.Deployment)
+	}
+	u.RawQuery = q.Encode()
+	return u, nil
+}
+
+// Reconnect replaces the client's WebSocket connection with a freshly dialed one,
+// starting a new server-side session. Registered event handlers are kept, and the
+// session configuration accumulated through SessionUpdate is re-applied to the new
+// session. Conversation history is not carried over.
+//
+// Returns ErrClosed if the client has been closed.
+func (c *Client) Reconnect(ctx context.Context) error {
+	if ctx == nil {
+		return NewConnectionError(c.cfg.ResourceEndpoint, "reconnect", errors.New("context cannot be nil"))
+	}
+
+	if c.isTransport {
+		return NewConnectionError("", "reconnect", ErrTransportUnsupported)
+	}
+
+	ws, info, err := dialWebSocket(ctx, c.cfg)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	old := c.conn
+	if old == nil {
+		c.writeMu.Unlock()
+		_ = ws.Close(websocket.StatusNormalClosure, "client closed")
+		return ErrClosed
+	}
+	if c.readCancel != nil {
+		c.readCancel()
+	}
+	c.conn = ws
 	rcCtx, cancel := context.WithCancel(context.Background())
 	c.readCancel = cancel
-	go c.readLoop(rcCtx)
+	c.handshake = info
+	c.conversation.reset()
+	c.outage.connect()
+	c.writeMu.Unlock()
+	c.report.reset()
+	c.interrupts.take()
+	c.tools.reset()
+	c.conversations.reset()
+	c.integrity.reset()
+	c.latency.reset()
+	c.trackers.each(func(t *ResponseTracker) { t.reconnected() })
 
-	// Start ping loop to maintain connection
-	go c.pingLoop()
-	return c, nil
+	_ = old.Close(websocket.StatusNormalClosure, "reconnect")
+	go c.readLoop(rcCtx, ws)
+	c.stats.recordReconnect()
+	c.log("ws_reconnected", map[string]any{"url": info.URL, "request_id": info.RequestID})
+
+	// Restore the session configuration on the new server-side session, ahead
+	// of any events buffered by AutoReconnect
+	return c.sessionUpdates.restore(ctx, func(ctx context.Context, s Session) error {
+		return c.sendNow(ctx, map[string]any{"type": "session.update", "session": s})
+	})
 }
 
 // DialResilient creates a new client with built-in retry and resilience features.
@@ -149,30 +279,71 @@ func DialResilient(ctx context.Context, cfg Config) (*WithRetryableClient, error
 // This method is safe to call multiple times and will not block.
 // After calling Close(), the client should not be used for further operations.
 func (c *Client) Close() error {
-	// Cancel the read loop to stop processing incoming messages
+	// Close the WebSocket connection safely and stop the read loop
+	c.writeMu.Lock()
 	if c.readCancel != nil {
 		c.readCancel()
 	}
-
-	// Close the WebSocket connection safely
-	c.writeMu.Lock()
 	if c.conn != nil {
 		_ = c.conn.Close(websocket.StatusNormalClosure, "closing")
 		c.conn = nil
 	}
+	if c.transport != nil {
+		_ = c.transport.Close()
+		c.transport = nil
+	}
+	if c.closeErr == nil {
+		c.closeErr = ErrClosed
+	}
 	c.writeMu.Unlock()
 
+	c.stopSessionExpiry()
+
 	// Signal that the client is closed
 	c.closeOnce.Do(func() {
 		close(c.closedCh)
 	})
+	c.finishReport()
 	return nil
 }
 
+// Done returns a channel that is closed when the client stops, either because
+// Close was called or because the connection was lost. Use Err to learn why.
+// A connection replaced by Reconnect does not close the channel.
+func (c *Client) Done() <-chan struct{} {
+	return c.closedCh
+}
+
+// Err returns nil while the client is running. Once Done is closed, it returns
+// ErrClosed if Close was called, or a *ConnectionError with Operation "read"
+// wrapping the underlying read failure if the connection was lost. Use
+// websocket.CloseStatus on the result to inspect the server's close code.
+func (c *Client) Err() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.closeErr
+}
+
+// SessionUpdates returns the queue that serializes this client's session updates.
+// Use it to check whether updates are still pending or to observe the final
+// session configuration once concurrent updates have been applied.
+func (c *Client) SessionUpdates() *SessionUpdateQueue {
+	return &c.sessionUpdates
+}
+
 // Event handler registration methods
 // These methods allow you to register callback functions for different event types.
 // Callbacks are executed in the read loop goroutine, so they should not block.
 
+// OnRawEvent registers a callback that receives the raw JSON of every event
+// read from or successfully written to the connection, tagged with its direction.
+// This is the tap used by SessionRecorder. The data slice must not be modified.
+func (c *Client) OnRawEvent(fn func(dir EventDirection, data []byte)) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	c.onRawEvent = fn
+}
+
 // OnError registers a callback for API error events.
 func (c *Client) OnError(fn func(ErrorEvent)) {
 	c.handlerMu.Lock()
@@ -271,6 +442,14 @@ func (c *Client) OnConversationItemInputAudioTranscriptionCompleted(fn func(Conv
 	c.onConversationItemInputAudioTranscriptionCompleted = fn
 }
 
+// OnConversationItemInputAudioTranscriptionDelta registers a callback for partial
+// audio transcripts, sent by the gpt-4o transcription models.
+func (c *Client) OnConversationItemInputAudioTranscriptionDelta(fn func(ConversationItemInputAudioTranscriptionDelta)) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	c.onConversationItemInputAudioTranscriptionDelta = fn
+}
+
 // OnConversationItemInputAudioTranscriptionFailed registers a callback for audio transcription failed events.
 func (c *Client) OnConversationItemInputAudioTranscriptionFailed(fn func(ConversationItemInputAudioTranscriptionFailed)) {
 	c.handlerMu.Lock()
@@ -365,42 +544,129 @@ func (c *Client) OnResponseAudioTranscriptDone(fn func(ResponseAudioTranscriptDo
 // readLoop continuously reads messages from the WebSocket connection.
 // It runs in a separate goroutine and handles message parsing and event dispatching.
 // The loop terminates when the context is canceled or the connection fails.
-func (c *Client) readLoop(ctx context.Context) {
+func (c *Client) readLoop(ctx context.Context, conn *websocket.Conn) {
+	var err error
 	defer func() {
-		// Clean up connection state when read loop exits
+		// Clean up connection state when read loop exits. If the connection
+		// was replaced by Reconnect, the client stays open.
 		c.writeMu.Lock()
-		if c.conn != nil {
-			_ = c.conn.Close(websocket.StatusNormalClosure, "reader_exit")
-			c.conn = nil
+		if c.conn != conn {
+			c.writeMu.Unlock()
+			return
+		}
+		if p := c.cfg.AutoReconnect; p != nil && ctx.Err() == nil {
+			limit := p.BufferSize
+			if limit == 0 {
+				limit = DefaultReconnectBufferSize
+			}
+			if c.outage.start(limit) {
+				// Keep c.conn so Reconnect can replace it
+				c.writeMu.Unlock()
+				go c.autoReconnect(conn, err)
+				return
+			}
 		}
+		_ = conn.Close(websocket.StatusNormalClosure, "reader_exit")
+		c.conn = nil
+		c.closeErr = NewConnectionError(c.handshake.URL, "read", err)
 		c.writeMu.Unlock()
+		c.logError("read_loop_exit", map[string]any{"err": err})
 		c.closeOnce.Do(func() {
 			close(c.closedCh)
 		})
 	}()
 
+	maxBytes := c.cfg.MaxMessageBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxMessageBytes
+	}
+	// Enforce the limit here rather than with the library limit, which closes the connection
+	conn.SetReadLimit(math.MaxInt64 - 1)
+
 	for {
 		// Read next message from WebSocket
-		typ, data, err := c.conn.Read(ctx)
+		var typ websocket.MessageType
+		var r io.Reader
+		typ, r, err = conn.Reader(ctx)
 		if err != nil {
 			return
 		} // Connection closed or error occurred
 
-		// Only process text messages (JSON events)
-		if typ != websocket.MessageText {
+		var data []byte
+		data, err = io.ReadAll(io.LimitReader(r, maxBytes+1))
+		if err != nil {
+			return
+		}
+		if int64(len(data)) > maxBytes {
+			// Skip the rest of the oversized message without buffering it
+			var n int64
+			n, err = io.Copy(io.Discard, r)
+			if err != nil {
+				return
+			}
+			c.messageTooLarge(int64(len(data))+n, maxBytes, data)
 			continue
 		}
 
-		// Parse the event envelope to determine event type
-		var env envelope
-		if err := json.Unmarshal(data, &env); err != nil {
-			c.logError("bad_event_json", map[string]any{"err": err, "raw_data": string(data)})
+		// Only process text messages (JSON events)
+		if typ != websocket.MessageText {
 			continue
 		}
 
-		// Dispatch to appropriate event handler
-		c.dispatch(env, data)
+		c.handleMessage(data)
+	}
+}
+
+// messageTooLarge reports a dropped oversized message through the logger and
+// the OnError handler as a client_error event with code "message_too_large".
+func (c *Client) messageTooLarge(size, limit int64, head []byte) {
+	var env envelope
+	_ = json.Unmarshal(head, &env) // usually fails on truncated JSON; best effort
+	c.logError("message_too_large", map[string]any{"bytes": size, "limit": limit, "event_type": env.Type})
+
+	c.clientError(ErrorCodeMessageTooLarge, fmt.Errorf("dropped %d-byte message exceeding MaxMessageBytes (%d)", size, limit))
+}
+
+// clientError reports a problem detected by the client to OnError as a
+// client_error event with the given code and cause.
+func (c *Client) clientError(code string, cause error) {
+	e := ErrorEvent{Type: "error", Cause: cause, Error: ErrorDetails{
+		Type:    ErrorTypeClient,
+		Code:    code,
+		Message: cause.Error(),
+	}}
+	c.report.errorReceived(e.Error)
+	c.handlerMu.RLock()
+	if c.onError != nil {
+		c.onError(e)
 	}
+	c.handlerMu.RUnlock()
+}
+
+// handleMessage parses one inbound event and dispatches it to the registered handlers.
+func (c *Client) handleMessage(data []byte) {
+	// Parse the event envelope to determine event type
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		c.logError("bad_event_json", map[string]any{"err": err, "raw_data": string(data)})
+		return
+	}
+	c.stats.recordReceived(env.Type, len(data))
+	c.emitRaw(DirectionInbound, data)
+	c.logDebugEvent("event_received", env.Type, len(data))
+
+	if c.cfg.StreamIntegrityChecks {
+		if err := c.integrity.observe(CanonicalEventType(env.Type), data); err != nil {
+			err.EventType = env.Type // Report the name sent on the wire
+			c.logError("stream_integrity", map[string]any{"problem": string(err.Problem), "event_type": err.EventType, "response_id": err.ResponseID, "detail": err.Detail})
+			c.clientError(ErrorCodeStreamIntegrity, err)
+		}
+	}
+
+	c.observeLatency(CanonicalEventType(env.Type), data)
+
+	// Dispatch to appropriate event handler
+	c.dispatch(env, data)
 }
 
 func (c *Client) pingLoop() {
@@ -411,28 +677,75 @@ func (c *Client) pingLoop() {
 		case <-c.closedCh:
 			return
 		case <-t.C:
-			c.writeMu.Lock()
-			if c.conn != nil {
-				_ = c.conn.Ping(context.Background())
-			}
-			c.writeMu.Unlock()
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			_, _ = c.Ping(ctx)
+			cancel()
 		}
 	}
 }
 
+// Ping sends a WebSocket ping and waits for the pong, returning the round-trip
+// time. A successful ping also updates RTT. Returns ErrClosed if the client is
+// closed, or a *ConnectionError with Operation "ping" if no pong arrives.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	if ctx == nil {
+		return 0, NewConnectionError(c.cfg.ResourceEndpoint, "ping", errors.New("context cannot be nil"))
+	}
+
+	// Ping outside writeMu so sends are not blocked while waiting for the pong
+	c.writeMu.Lock()
+	conn, url := c.conn, c.handshake.URL
+	c.writeMu.Unlock()
+	if c.isTransport {
+		return 0, NewConnectionError("", "ping", ErrTransportUnsupported)
+	}
+	if conn == nil {
+		return 0, ErrClosed
+	}
+	start := time.Now()
+	if err := conn.Ping(ctx); err != nil {
+		return 0, NewConnectionError(url, "ping", err)
+	}
+	rtt := time.Since(start)
+	c.rtt.Store(int64(rtt))
+	return rtt, nil
+}
+
+// RTT returns the round-trip time measured by the most recent successful
+// keepalive ping or Ping call, or zero if no ping has completed yet.
+func (c *Client) RTT() time.Duration {
+	return time.Duration(c.rtt.Load())
+}
+
+// LastRateLimits returns the most recent rate_limits.updated event received
+// from the server. The boolean is false if none has been received yet.
+func (c *Client) LastRateLimits() (RateLimitsUpdated, bool) {
+	c.rateLimitsMu.RLock()
+	defer c.rateLimitsMu.RUnlock()
+	if c.rateLimits == nil {
+		return RateLimitsUpdated{}, false
+	}
+	return *c.rateLimits, true
+}
+
 func (c *Client) dispatch(env envelope, raw []byte) {
-	switch env.Type {
+	switch CanonicalEventType(env.Type) {
 	case "error":
 		var e ErrorEvent
 		_ = json.Unmarshal(raw, &e)
+		c.report.errorReceived(e.Error)
+		c.trackers.errorReceived(c, e.Error)
 		c.handlerMu.RLock()
 		if c.onError != nil {
 			c.onError(e)
 		}
 		c.handlerMu.RUnlock()
+		c.maybeRecover(e)
 	case "session.created":
 		var e SessionCreated
 		_ = json.Unmarshal(raw, &e)
+		c.trackSessionExpiry(e.Session.ExpiresAt)
+		c.report.sessionCreated(e.Session.ID)
 		c.handlerMu.RLock()
 		if c.onSessionCreated != nil {
 			c.onSessionCreated(e)
@@ -449,6 +762,13 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "rate_limits.updated":
 		var e RateLimitsUpdated
 		_ = json.Unmarshal(raw, &e)
+		c.rateLimitsMu.Lock()
+		c.rateLimits = &e
+		c.rateLimitsMu.Unlock()
+		c.limiter.update(e, time.Now())
+		if c.cfg.AdaptiveLimiter != nil {
+			c.cfg.AdaptiveLimiter.Update(e)
+		}
 		c.handlerMu.RLock()
 		if c.onRateLimitsUpdated != nil {
 			c.onRateLimitsUpdated(e)
@@ -457,6 +777,10 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "response.text.delta":
 		var e ResponseTextDelta
 		_ = json.Unmarshal(raw, &e)
+		c.trackers.each(func(t *ResponseTracker) { t.textDelta(e.ResponseID, e.Delta) })
+		if !c.outOfBand.has(e.ResponseID) {
+			c.transcript.assistantText(e.ItemID, e.Delta, time.Now())
+		}
 		c.handlerMu.RLock()
 		if c.onResponseTextDelta != nil {
 			c.onResponseTextDelta(e)
@@ -465,6 +789,9 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "response.text.done":
 		var e ResponseTextDone
 		_ = json.Unmarshal(raw, &e)
+		if !c.outOfBand.has(e.ResponseID) {
+			c.transcript.assistantTextDone(e.ItemID, e.Text, time.Now())
+		}
 		c.handlerMu.RLock()
 		if c.onResponseTextDone != nil {
 			c.onResponseTextDone(e)
@@ -473,11 +800,21 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "response.audio.delta":
 		var e ResponseAudioDelta
 		_ = json.Unmarshal(raw, &e)
+		n := base64DecodedLen(e.DeltaBase64)
+		c.stats.recordAudioOut(n)
+		if !c.outOfBand.has(e.ResponseID) {
+			c.interrupts.audioDelta(e, n)
+			c.transcript.assistantAudio(e.ItemID, audioDuration(c.outputAudioFormat(), n), time.Now())
+		}
+		c.trackers.audioDelta(e)
 		c.handlerMu.RLock()
 		if c.onResponseAudioDelta != nil {
 			c.onResponseAudioDelta(e)
 		}
 		c.handlerMu.RUnlock()
+		if fn, p, ok := c.audioProgress.delta(e, n, c.outputAudioFormat); ok {
+			fn(p)
+		}
 	case "response.audio.done":
 		var e ResponseAudioDone
 		_ = json.Unmarshal(raw, &e)
@@ -486,9 +823,13 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 			c.onResponseAudioDone(e)
 		}
 		c.handlerMu.RUnlock()
+		if fn, p, ok := c.audioProgress.done(e); ok {
+			fn(p)
+		}
 	case "input_audio_buffer.speech_started":
 		var e InputAudioBufferSpeechStarted
 		_ = json.Unmarshal(raw, &e)
+		c.transcript.speechStarted(e.ItemID, time.Now())
 		c.handlerMu.RLock()
 		if c.onInputAudioBufferSpeechStarted != nil {
 			c.onInputAudioBufferSpeechStarted(e)
@@ -497,6 +838,7 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "input_audio_buffer.speech_stopped":
 		var e InputAudioBufferSpeechStopped
 		_ = json.Unmarshal(raw, &e)
+		c.transcript.speechStopped(e.ItemID, time.Now())
 		c.handlerMu.RLock()
 		if c.onInputAudioBufferSpeechStopped != nil {
 			c.onInputAudioBufferSpeechStopped(e)
@@ -521,6 +863,8 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "conversation.item.created":
 		var e ConversationItemCreated
 		_ = json.Unmarshal(raw, &e)
+		c.conversation.created(e.Item.ID, e.PreviousItemID)
+		c.transcript.itemCreated(e.Item, time.Now())
 		c.handlerMu.RLock()
 		if c.onConversationItemCreated != nil {
 			c.onConversationItemCreated(e)
@@ -529,11 +873,21 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "conversation.item.input_audio_transcription.completed":
 		var e ConversationItemInputAudioTranscriptionCompleted
 		_ = json.Unmarshal(raw, &e)
+		c.transcript.userTranscript(e.ItemID, e.Transcript, time.Now())
 		c.handlerMu.RLock()
 		if c.onConversationItemInputAudioTranscriptionCompleted != nil {
 			c.onConversationItemInputAudioTranscriptionCompleted(e)
 		}
 		c.handlerMu.RUnlock()
+	case "conversation.item.input_audio_transcription.delta":
+		var e ConversationItemInputAudioTranscriptionDelta
+		_ = json.Unmarshal(raw, &e)
+		c.transcript.userTranscriptDelta(e.ItemID, e.Delta, time.Now())
+		c.handlerMu.RLock()
+		if c.onConversationItemInputAudioTranscriptionDelta != nil {
+			c.onConversationItemInputAudioTranscriptionDelta(e)
+		}
+		c.handlerMu.RUnlock()
 	case "conversation.item.input_audio_transcription.failed":
 		var e ConversationItemInputAudioTranscriptionFailed
 		_ = json.Unmarshal(raw, &e)
@@ -553,6 +907,7 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "conversation.item.deleted":
 		var e ConversationItemDeleted
 		_ = json.Unmarshal(raw, &e)
+		c.conversation.deleted(e.ItemID)
 		c.handlerMu.RLock()
 		if c.onConversationItemDeleted != nil {
 			c.onConversationItemDeleted(e)
@@ -561,6 +916,14 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "response.created":
 		var e ResponseCreated
 		_ = json.Unmarshal(raw, &e)
+		c.responseTags.observe(e.Response, false)
+		c.outOfBand.responseCreated(e.Response)
+		c.conversations.responseCreated(e.Response)
+		c.report.responseCreated(e.Response.ID)
+		if !IsOutOfBand(e.Response) {
+			c.interrupts.responseCreated(e.Response.ID)
+		}
+		c.trackers.each(func(t *ResponseTracker) { t.responseCreated(e.Response) })
 		c.handlerMu.RLock()
 		if c.onResponseCreated != nil {
 			c.onResponseCreated(e)
@@ -569,6 +932,15 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "response.done":
 		var e ResponseDone
 		_ = json.Unmarshal(raw, &e)
+		c.responseTags.observe(e.Response, true)
+		c.report.responseDone(e.Response)
+		c.interrupts.responseDone(e.Response.ID)
+		if !IsOutOfBand(e.Response) {
+			c.tools.responseDone(c, e.Response)
+		}
+		c.outOfBand.responseDone(e.Response.ID)
+		c.conversations.responseDone(e.Response)
+		c.trackers.each(func(t *ResponseTracker) { t.responseDone(e.Response) })
 		c.handlerMu.RLock()
 		if c.onResponseDone != nil {
 			c.onResponseDone(e)
@@ -577,6 +949,9 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "response.output_item.added":
 		var e ResponseOutputItemAdded
 		_ = json.Unmarshal(raw, &e)
+		if !c.outOfBand.has(e.ResponseID) {
+			c.tools.itemAdded(e.Item)
+		}
 		c.handlerMu.RLock()
 		if c.onResponseOutputItemAdded != nil {
 			c.onResponseOutputItemAdded(e)
@@ -617,6 +992,9 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "response.function_call_arguments.done":
 		var e ResponseFunctionCallArgumentsDone
 		_ = json.Unmarshal(raw, &e)
+		if !c.outOfBand.has(e.ResponseID) {
+			c.tools.argumentsDone(c, e)
+		}
 		c.handlerMu.RLock()
 		if c.onResponseFunctionCallArgumentsDone != nil {
 			c.onResponseFunctionCallArgumentsDone(e)
@@ -625,6 +1003,10 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "response.audio_transcript.delta":
 		var e ResponseAudioTranscriptDelta
 		_ = json.Unmarshal(raw, &e)
+		c.trackers.each(func(t *ResponseTracker) { t.transcriptDelta(e.ResponseID, e.Delta) })
+		if !c.outOfBand.has(e.ResponseID) {
+			c.transcript.assistantText(e.ItemID, e.Delta, time.Now())
+		}
 		c.handlerMu.RLock()
 		if c.onResponseAudioTranscriptDelta != nil {
 			c.onResponseAudioTranscriptDelta(e)
@@ -633,6 +1015,9 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 	case "response.audio_transcript.done":
 		var e ResponseAudioTranscriptDone
 		_ = json.Unmarshal(raw, &e)
+		if !c.outOfBand.has(e.ResponseID) {
+			c.transcript.assistantTextDone(e.ItemID, e.Transcript, time.Now())
+		}
 		c.handlerMu.RLock()
 		if c.onResponseAudioTranscriptDone != nil {
 			c.onResponseAudioTranscriptDone(e)
@@ -645,49 +1030,104 @@ func (c *Client) dispatch(env envelope, raw []byte) {
 }
 
 func (c *Client) send(ctx context.Context, payload any) error {
+	if held, err := c.outage.hold(payload); held {
+		return err
+	}
+	return c.sendNow(ctx, payload)
+}
+
+// sendNow writes payload to the connection even while an outage is buffering.
+func (c *Client) sendNow(ctx context.Context, payload any) error {
+	start := time.Now()
+	b, err := c.write(ctx, payload)
+	if err != nil {
+		c.stats.recordSendError()
+		return err
+	}
+	typ := payloadType(payload)
+	if typ == "response.create" {
+		c.latency.requested(start)
+	}
+	c.stats.recordSent(len(b))
+	c.emitRaw(DirectionOutbound, b)
+	c.logDebugEvent("event_sent", typ, len(b))
+	return nil
+}
+
+// write marshals payload and writes it to the connection, returning the bytes written.
+func (c *Client) write(ctx context.Context, payload any) ([]byte, error) {
 	c.writeMu.Lock()
 	defer c.writeMu.Unlock()
-	if c.conn == nil {
-		return ErrClosed
+	if c.conn == nil && c.transport == nil {
+		return nil, ErrClosed
 	}
 
 	b, err := json.Marshal(payload)
 	if err != nil {
-		return NewSendError("unknown", "", fmt.Errorf("marshal payload: %w", err))
+		return nil, NewSendError("unknown", "", fmt.Errorf("marshal payload: %w", err))
 	}
 
 	// Apply send timeout
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	err = c.conn.Write(ctx, websocket.MessageText, b)
+	if c.transport != nil {
+		err = c.transport.Send(ctx, b)
+	} else {
+		err = c.conn.Write(ctx, websocket.MessageText, b)
+	}
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
-			return NewSendError("unknown", "", ErrSendTimeout)
+			return nil, NewSendError("unknown", "", ErrSendTimeout)
 		}
-		return NewSendError("unknown", "", err)
+		return nil, NewSendError("unknown", "", err)
 	}
 
-	return nil
+	return b, nil
+}
+
+// emitRaw passes a raw event to the OnRawEvent hook, if one is registered.
+func (c *Client) emitRaw(dir EventDirection, data []byte) {
+	c.handlerMu.RLock()
+	defer c.handlerMu.RUnlock()
+	if c.onRawEvent != nil {
+		c.onRawEvent(dir, data)
+	}
 }
 
 func (c *Client) nextEventID(ctx context.Context, payload map[string]any) (string, error) {
-	id := fmt.Sprintf("evt_%d", time.Now().UnixNano())
+	id := newEventID()
 	payload["event_id"] = id
 	return id, c.send(ctx, payload)
 }
+
+// newEventID returns an ID for a client event.
+func newEventID() string {
+	return fmt.Sprintf("evt_%d", time.Now().UnixNano())
+}
+
+// Log fields are passed through logFields, so credentials and audio never
+// reach the configured logger and every line carries the correlation ID.
 func (c *Client) log(event string, fields map[string]any) {
 	if c.cfg.StructuredLogger != nil {
-		c.cfg.StructuredLogger.Info(event, fields)
+		c.cfg.StructuredLogger.Info(event, c.logFields(fields))
+	} else if c.cfg.Logger != nil {
+		c.cfg.Logger(event, c.logFields(fields))
+	}
+}
+
+func (c *Client) logWarn(event string, fields map[string]any) {
+	if c.cfg.StructuredLogger != nil {
+		c.cfg.StructuredLogger.Warn(event, c.logFields(fields))
 	} else if c.cfg.Logger != nil {
-		c.cfg.Logger(event, fields)
+		c.cfg.Logger("WARN: "+event, c.logFields(fields))
 	}
 }
 
 func (c *Client) logError(event string, fields map[string]any) {
 	if c.cfg.StructuredLogger != nil {
-		c.cfg.StructuredLogger.Error(event, fields)
+		c.cfg.StructuredLogger.Error(event, c.logFields(fields))
 	} else if c.cfg.Logger != nil {
-		c.cfg.Logger("ERROR: "+event, fields)
+		c.cfg.Logger("ERROR: "+event, c.logFields(fields))
 	}
 }
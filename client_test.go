@@ -2,10 +2,16 @@ package azrealtime
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"nhooyr.io/websocket"
 )
 
 func TestDial_InvalidConfig(t *testing.T) {
@@ -236,12 +242,7 @@ func TestClient_EventHandlers(t *testing.T) {
 	// Add a custom error event to the mock server
 	errorEvent := ErrorEvent{
 		Type: "error",
-		Error: struct {
-			Type    string `json:"type,omitempty"`
-			Message string `json:"message,omitempty"`
-			Role    string `json:"role,omitempty"`
-			Content string `json:"content,omitempty"`
-		}{
+		Error: ErrorDetails{
 			Type:    "test_error",
 			Message: "Test error message",
 		},
@@ -398,3 +399,133 @@ func TestClient_NextEventID(t *testing.T) {
 		t.Error("expected non-empty event IDs")
 	}
 }
+
+func TestClient_MaxMessageBytes(t *testing.T) {
+	// The server sends an oversized message, then a normal one, once the client
+	// has sent its first event (so handlers are registered before either arrives).
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close(websocket.StatusNormalClosure, "")
+		if _, _, err := conn.Read(r.Context()); err != nil {
+			return
+		}
+		big := `{"type":"response.text.delta","delta":"` + strings.Repeat("x", 4096) + `"}`
+		_ = conn.Write(r.Context(), websocket.MessageText, []byte(big))
+		_ = conn.Write(r.Context(), websocket.MessageText, []byte(`{"type":"response.text.delta","delta":"ok"}`))
+		_, _, _ = conn.Read(r.Context())
+	}))
+	defer server.Close()
+
+	config := CreateMockConfig("ws" + strings.TrimPrefix(server.URL, "http"))
+	config.MaxMessageBytes = 1024
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	errs := make(chan ErrorEvent, 1)
+	deltas := make(chan string, 2)
+	client.OnError(func(e ErrorEvent) { errs <- e })
+	client.OnResponseTextDelta(func(e ResponseTextDelta) { deltas <- e.Delta })
+
+	if err := client.SessionUpdate(ctx, Session{}); err != nil {
+		t.Fatalf("session update: %v", err)
+	}
+
+	select {
+	case e := <-errs:
+		if e.Error.Type != ErrorTypeClient || e.Error.Code != ErrorCodeMessageTooLarge {
+			t.Errorf("error = %+v, want %s/%s", e.Error, ErrorTypeClient, ErrorCodeMessageTooLarge)
+		}
+	case <-ctx.Done():
+		t.Fatal("no error event for oversized message")
+	}
+
+	// The connection survives and the next message is delivered
+	select {
+	case d := <-deltas:
+		if d != "ok" {
+			t.Errorf("delta = %q, want %q", d, "ok")
+		}
+	case <-ctx.Done():
+		t.Fatal("message after oversized one was not delivered")
+	}
+}
+
+func TestClient_DoneAndErr(t *testing.T) {
+	t.Run("close", func(t *testing.T) {
+		mockServer := NewMockServer(t)
+		defer mockServer.Close()
+
+		client, err := Dial(context.Background(), CreateMockConfig(mockServer.URL()))
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		if err := client.Err(); err != nil {
+			t.Errorf("Err() before close = %v, want nil", err)
+		}
+		select {
+		case <-client.Done():
+			t.Fatal("Done closed before Close")
+		default:
+		}
+
+		client.Close()
+		select {
+		case <-client.Done():
+		case <-time.After(time.Second):
+			t.Fatal("Done not closed after Close")
+		}
+		if err := client.Err(); !errors.Is(err, ErrClosed) {
+			t.Errorf("Err() = %v, want ErrClosed", err)
+		}
+	})
+
+	t.Run("connection lost", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+			conn.Close(websocket.StatusGoingAway, "server restarting")
+		}))
+		defer server.Close()
+
+		client, err := Dial(context.Background(), CreateMockConfig("ws"+strings.TrimPrefix(server.URL, "http")))
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer client.Close()
+
+		select {
+		case <-client.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("Done not closed after server closed the connection")
+		}
+		err = client.Err()
+		var connErr *ConnectionError
+		if !errors.As(err, &connErr) || connErr.Operation != "read" {
+			t.Fatalf("Err() = %v, want *ConnectionError for read", err)
+		}
+		if got := websocket.CloseStatus(err); got != websocket.StatusGoingAway {
+			t.Errorf("close status = %v, want %v", got, websocket.StatusGoingAway)
+		}
+
+		// Close after the connection was lost keeps the original cause
+		client.Close()
+		if !errors.As(client.Err(), &connErr) {
+			t.Errorf("Err() after Close = %v, want the read error", client.Err())
+		}
+		if err := client.SessionUpdate(context.Background(), Session{}); err != ErrClosed {
+			t.Errorf("SessionUpdate after loss = %v, want ErrClosed", err)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+// Connects the microphone to the relay over WebRTC and plays back assistant audio.
+const startButton = document.getElementById('start');
+const statusEl = document.getElementById('status');
+const transcriptEl = document.getElementById('transcript');
+const audioEl = document.getElementById('assistant-audio');
+
+let pc, stream, assistantLine = null;
+
+function setStatus(text) {
+    statusEl.textContent = text;
+}
+
+function addLine(role, text) {
+    const p = document.createElement('p');
+    p.className = role;
+    p.textContent = text;
+    transcriptEl.appendChild(p);
+    transcriptEl.scrollTop = transcriptEl.scrollHeight;
+    return p;
+}
+
+// onEvent renders the realtime server events passed through by the relay.
+function onEvent(event) {
+    const msg = JSON.parse(event.data);
+    switch (msg.type) {
+    case 'session.created':
+    case 'session.updated':
+        setStatus('Listening — start talking');
+        break;
+    case 'input_audio_buffer.speech_started':
+        assistantLine = null;
+        break;
+    case 'conversation.item.input_audio_transcription.completed':
+        addLine('user', msg.transcript);
+        break;
+    case 'response.audio_transcript.delta':
+        assistantLine = assistantLine || addLine('assistant', '');
+        assistantLine.textContent += msg.delta;
+        break;
+    case 'response.done':
+        assistantLine = null;
+        break;
+    case 'error':
+        addLine('error', msg.error.message);
+        break;
+    }
+}
+
+async function start() {
+    startButton.disabled = true;
+    setStatus('Connecting…');
+    try {
+        stream = await navigator.mediaDevices.getUserMedia({
+            audio: { channelCount: 1, echoCancellation: true, noiseSuppression: true },
+        });
+    } catch (err) {
+        setStatus('Microphone unavailable: ' + err.message);
+        startButton.disabled = false;
+        return;
+    }
+
+    pc = new RTCPeerConnection();
+    stream.getTracks().forEach((t) => pc.addTrack(t, stream));
+    pc.ontrack = (e) => { audioEl.srcObject = e.streams[0]; };
+    pc.onconnectionstatechange = () => {
+        if (['failed', 'closed'].includes(pc.connectionState)) {
+            stop();
+        }
+    };
+    const events = pc.createDataChannel('oai-events');
+    events.onmessage = onEvent;
+
+    // Candidates found before the answer arrives wait for the session ID
+    let session, queued = [];
+    const sendCandidate = (c) => fetch('/ice-candidate', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json', 'X-Relay-Session': session },
+        body: JSON.stringify(c),
+    });
+    pc.onicecandidate = (e) => {
+        if (!e.candidate) {
+            return;
+        }
+        if (session) {
+            sendCandidate(e.candidate);
+        } else {
+            queued.push(e.candidate);
+        }
+    };
+
+    try {
+        await pc.setLocalDescription(await pc.createOffer());
+        const resp = await fetch('/offer', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/sdp' },
+            body: pc.localDescription.sdp,
+        });
+        if (!resp.ok) {
+            throw new Error(await resp.text());
+        }
+        session = resp.headers.get('X-Relay-Session');
+        await pc.setRemoteDescription({ type: 'answer', sdp: await resp.text() });
+        queued.forEach(sendCandidate);
+        queued = [];
+    } catch (err) {
+        addLine('error', 'Could not connect: ' + err.message);
+        stop();
+        return;
+    }
+
+    startButton.textContent = 'Stop';
+    startButton.onclick = stop;
+    startButton.disabled = false;
+}
+
+function stop() {
+    if (pc) {
+        pc.close();
+        pc = null;
+    }
+    if (stream) {
+        stream.getTracks().forEach((t) => t.stop());
+        stream = null;
+    }
+    audioEl.srcObject = null;
+    assistantLine = null;
+    setStatus('Disconnected');
+    startButton.textContent = 'Start';
+    startButton.onclick = start;
+    startButton.disabled = false;
+}
+
+startButton.onclick = start;
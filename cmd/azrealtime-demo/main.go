@@ -0,0 +1,125 @@
+// Command azrealtime-demo serves a minimal web page for talking to a realtime
+// deployment from the browser: microphone audio is streamed to the model and
+// spoken replies are played back. The UI is embedded, so the binary is all you
+// need.
+//
+// Configuration comes from the environment:
+//
+//	AZURE_OPENAI_ENDPOINT             resource endpoint (required)
+//	AZURE_OPENAI_REALTIME_DEPLOYMENT  realtime deployment name (required)
+//	AZURE_OPENAI_API_KEY              API key (required)
+//	AZURE_OPENAI_REGION               region of the WebRTC endpoint, e.g. eastus2
+//	AZURE_OPENAI_WEBRTC_URL           WebRTC endpoint, instead of AZURE_OPENAI_REGION
+//	AZURE_OPENAI_API_VERSION          API version (default: azrealtime.DefaultAPIVersion)
+//	AZURE_OPENAI_VOICE                assistant voice (default: alloy)
+//	DEMO_INSTRUCTIONS                 system instructions
+//	DEMO_ADDR                         listen address (default: localhost:8080)
+//
+// Each browser gets its own realtime session through the webrtc/relay
+// package: the page sends a WebRTC offer to /offer and trickles ICE candidates
+// to /ice-candidate, and the relay forwards its microphone track and data
+// channel events to Azure and the assistant audio back. The API key never
+// leaves the server; each session is set up with a freshly minted ephemeral key.
+package main
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/enesunal-m/azrealtime"
+	"github.com/enesunal-m/azrealtime/webrtc"
+	"github.com/enesunal-m/azrealtime/webrtc/relay"
+)
+
+//go:embed assets
+var assets embed.FS
+
+func main() {
+	endpoint := must("AZURE_OPENAI_ENDPOINT")
+	deployment := must("AZURE_OPENAI_REALTIME_DEPLOYMENT")
+	apiKey := must("AZURE_OPENAI_API_KEY")
+	apiVersion := env("AZURE_OPENAI_API_VERSION", azrealtime.DefaultAPIVersion)
+	region := env("AZURE_OPENAI_REGION", "")
+	webrtcURL := env("AZURE_OPENAI_WEBRTC_URL", "")
+	if region == "" && webrtcURL == "" {
+		log.Fatalf("missing env AZURE_OPENAI_REGION (or AZURE_OPENAI_WEBRTC_URL)")
+	}
+	addr := env("DEMO_ADDR", "localhost:8080")
+
+	// The session is configured when minting its key, so the page only has to
+	// connect and talk
+	mint := webrtc.MintSessionOptions{Session: azrealtime.Session{
+		Voice:              azrealtime.Ptr(env("AZURE_OPENAI_VOICE", "alloy")),
+		Instructions:       azrealtime.Ptr(env("DEMO_INSTRUCTIONS", "You are a friendly assistant. Keep answers short.")),
+		InputTranscription: &azrealtime.InputTranscription{Model: "whisper-1"},
+		TurnDetection: &azrealtime.TurnDetection{
+			Type:              "server_vad",
+			Threshold:         0.5,
+			PrefixPaddingMS:   300,
+			SilenceDurationMS: 500,
+			CreateResponse:    true,
+			InterruptResponse: true,
+		},
+	}}
+
+	sig := relay.NewSignaling(relay.SignalingOptions{
+		Relay: relay.Options{
+			Azure: webrtc.EnhancedHeadlessOptions{Region: region, WebRTCURL: webrtcURL, Deployment: deployment},
+			Key: func(ctx context.Context) (string, error) {
+				key, err := webrtc.MintEphemeralSession(ctx, endpoint, apiVersion, deployment, apiKey, mint)
+				return key.Value, err
+			},
+			OnError: func(err error) { log.Printf("relay: %v", err) },
+		},
+		OnSession:    func(id string, _ *relay.Relay) { log.Printf("session %s started", id) },
+		OnSessionEnd: func(id string) { log.Printf("session %s ended", id) },
+	})
+	defer sig.Close()
+
+	static, err := fs.Sub(assets, "assets")
+	if err != nil {
+		log.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.Handle("/offer", sig.OfferHandler())
+	mux.Handle("/ice-candidate", sig.ICECandidateHandler())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("demo listening on http://%s", addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
+
+func must(key string) string {
+	v := os.Getenv(key)
+	if v == "" {
+		log.Fatalf("missing env %s", key)
+	}
+	return v
+}
+
+func env(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
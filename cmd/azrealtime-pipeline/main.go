@@ -0,0 +1,89 @@
+// Command azrealtime-pipeline runs a voice agent described by a YAML pipeline file.
+// Each line read from stdin is sent as a user message; assistant output goes to the
+// sinks configured in the file. See package pipeline for the file format.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/enesunal-m/azrealtime"
+	"github.com/enesunal-m/azrealtime/pipeline"
+)
+
+func main() {
+	file := flag.String("f", "pipeline.yaml", "pipeline file")
+	linger := flag.Duration("linger", 5*time.Second, "how long to wait for final responses after stdin closes")
+	flag.Parse()
+
+	p, err := pipeline.Load(*file)
+	if err != nil {
+		log.Fatalf("load %s: %v", *file, err)
+	}
+	cfg, err := p.ClientConfig()
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := azrealtime.Dial(ctx, cfg)
+	if err != nil {
+		log.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+
+	agent, err := pipeline.Start(ctx, p, client)
+	if err != nil {
+		log.Fatalf("start: %v", err)
+	}
+	defer func() {
+		if err := agent.Close(); err != nil {
+			log.Printf("close: %v", err)
+		}
+	}()
+
+	go func() {
+		for err := range agent.Errors() {
+			log.Printf("error: %v", err)
+		}
+	}()
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(os.Stdin)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case <-ctx.Done():
+				case <-time.After(*linger):
+				}
+				return
+			}
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			if err := agent.Say(ctx, line); err != nil {
+				log.Printf("send: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenRefreshBefore is how long before expiry a cached Entra ID token is replaced.
+const tokenRefreshBefore = 5 * time.Minute
+
+// imdsEndpoint is the Azure Instance Metadata Service token endpoint.
+const imdsEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// accessToken is an Entra ID token and its expiry.
+type accessToken struct {
+	value   string
+	expires time.Time
+}
+
+// entraCredential obtains Entra ID access tokens for the Azure OpenAI
+// resource, so the issuer needs no API key. Like DefaultAzureCredential it
+// picks the first source configured in the environment:
+//
+//   - workload identity: AZURE_FEDERATED_TOKEN_FILE, AZURE_CLIENT_ID, AZURE_TENANT_ID
+//   - service principal: AZURE_CLIENT_SECRET, AZURE_CLIENT_ID, AZURE_TENANT_ID
+//   - App Service / Container Apps managed identity: IDENTITY_ENDPOINT, IDENTITY_HEADER
+//   - VM / AKS managed identity through IMDS (AZURE_CLIENT_ID selects a user-assigned one)
+//
+// Tokens are cached until tokenRefreshBefore ahead of their expiry.
+type entraCredential struct {
+	source string // Describes the source, for logging
+	fetch  func(ctx context.Context) (accessToken, error)
+	client *http.Client
+
+	mu       sync.Mutex
+	cached   accessToken
+	inflight *tokenFetch // The fetch in progress, shared by concurrent callers
+}
+
+// tokenFetch is one token request and its result.
+type tokenFetch struct {
+	done chan struct{}
+	tok  accessToken
+	err  error
+}
+
+// newEntraCredential configures a credential for scope from the environment.
+func newEntraCredential(scope string) *entraCredential {
+	c := &entraCredential{client: &http.Client{Timeout: 15 * time.Second}}
+	resource := strings.TrimSuffix(scope, "/.default")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	authority := "https://login.microsoftonline.com"
+	if host := os.Getenv("AZURE_AUTHORITY_HOST"); host != "" {
+		authority = strings.TrimRight(host, "/")
+	}
+	tokenURL := fmt.Sprintf("%s/%s/oauth2/v2.0/token", authority, tenantID)
+
+	switch {
+	case os.Getenv("AZURE_FEDERATED_TOKEN_FILE") != "" && clientID != "" && tenantID != "":
+		file := os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+		c.source = "workload identity"
+		c.fetch = func(ctx context.Context) (accessToken, error) {
+			assertion, err := os.ReadFile(file) // Rotated by the platform; read each time
+			if err != nil {
+				return accessToken{}, err
+			}
+			return c.clientCredentials(ctx, tokenURL, url.Values{
+				"client_id":             {clientID},
+				"scope":                 {scope},
+				"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+				"client_assertion":      {strings.TrimSpace(string(assertion))},
+			})
+		}
+	case os.Getenv("AZURE_CLIENT_SECRET") != "" && clientID != "" && tenantID != "":
+		secret := os.Getenv("AZURE_CLIENT_SECRET")
+		c.source = "service principal"
+		c.fetch = func(ctx context.Context) (accessToken, error) {
+			return c.clientCredentials(ctx, tokenURL, url.Values{
+				"client_id":     {clientID},
+				"scope":         {scope},
+				"client_secret": {secret},
+			})
+		}
+	case os.Getenv("IDENTITY_ENDPOINT") != "" && os.Getenv("IDENTITY_HEADER") != "":
+		endpoint, header := os.Getenv("IDENTITY_ENDPOINT"), os.Getenv("IDENTITY_HEADER")
+		c.source = "managed identity (App Service)"
+		c.fetch = func(ctx context.Context) (accessToken, error) {
+			q := url.Values{"resource": {resource}, "api-version": {"2019-08-01"}}
+			if clientID != "" {
+				q.Set("client_id", clientID)
+			}
+			return c.managedIdentity(ctx, endpoint+"?"+q.Encode(), "X-IDENTITY-HEADER", header)
+		}
+	default:
+		c.source = "managed identity (IMDS)"
+		c.fetch = func(ctx context.Context) (accessToken, error) {
+			q := url.Values{"resource": {resource}, "api-version": {"2018-02-01"}}
+			if clientID != "" {
+				q.Set("client_id", clientID)
+			}
+			return c.managedIdentity(ctx, imdsEndpoint+"?"+q.Encode(), "Metadata", "true")
+		}
+	}
+	return c
+}
+
+// Token returns the cached token, fetching a new one when it is about to
+// expire. Concurrent callers share a single fetch, made without holding the
+// lock, and may give up on it through their own ctx.
+func (c *entraCredential) Token(ctx context.Context) (string, error) {
+	for {
+		c.mu.Lock()
+		if c.cached.value != "" && time.Until(c.cached.expires) > tokenRefreshBefore {
+			tok := c.cached.value
+			c.mu.Unlock()
+			return tok, nil
+		}
+		call := c.inflight
+		if call == nil {
+			call = &tokenFetch{done: make(chan struct{})}
+			c.inflight = call
+			c.mu.Unlock()
+			c.run(ctx, call)
+			return call.tok.value, call.err
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-call.done:
+		}
+		// A fetch aborted by its caller's context does not fail the others
+		if call.err != nil && (errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded)) {
+			continue
+		}
+		return call.tok.value, call.err
+	}
+}
+
+// run fetches a token for call and caches it.
+func (c *entraCredential) run(ctx context.Context, call *tokenFetch) {
+	call.tok, call.err = c.fetch(ctx)
+	if call.err != nil {
+		call.err = fmt.Errorf("entra id (%s): %w", c.source, call.err)
+	}
+	c.mu.Lock()
+	c.inflight = nil
+	if call.err == nil {
+		c.cached = call.tok
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// clientCredentials runs an OAuth2 client credentials grant.
+func (c *entraCredential) clientCredentials(ctx context.Context, tokenURL string, form url.Values) (accessToken, error) {
+	form.Set("grant_type", "client_credentials")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return accessToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c.do(req)
+}
+
+// managedIdentity requests a token from a managed identity endpoint.
+func (c *entraCredential) managedIdentity(ctx context.Context, endpoint, header, value string) (accessToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return accessToken{}, err
+	}
+	req.Header.Set(header, value)
+	return c.do(req)
+}
+
+// do sends a token request and parses the response. Entra ID reports
+// expires_in as a number; managed identity endpoints send strings and
+// expires_on as Unix seconds.
+func (c *entraCredential) do(req *http.Request) (accessToken, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return accessToken{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return accessToken{}, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return accessToken{}, fmt.Errorf("token request: status %d: %s", resp.StatusCode, body)
+	}
+
+	var tr struct {
+		AccessToken string          `json:"access_token"`
+		ExpiresIn   json.RawMessage `json:"expires_in"`
+		ExpiresOn   json.RawMessage `json:"expires_on"`
+	}
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return accessToken{}, err
+	}
+	if tr.AccessToken == "" {
+		return accessToken{}, errors.New("token response has no access_token")
+	}
+	tok := accessToken{value: tr.AccessToken, expires: time.Now().Add(time.Hour)}
+	if on, ok := jsonSeconds(tr.ExpiresOn); ok {
+		tok.expires = time.Unix(on, 0)
+	} else if in, ok := jsonSeconds(tr.ExpiresIn); ok {
+		tok.expires = time.Now().Add(time.Duration(in) * time.Second)
+	}
+	return tok, nil
+}
+
+// jsonSeconds reads a number that may be sent as a JSON number or string.
+func jsonSeconds(raw json.RawMessage) (int64, bool) {
+	s := strings.Trim(string(raw), `"`)
+	if s == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setEntraEnv clears the credential settings, then applies env.
+func setEntraEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, k := range []string{
+		"AZURE_FEDERATED_TOKEN_FILE", "AZURE_CLIENT_ID", "AZURE_TENANT_ID", "AZURE_CLIENT_SECRET",
+		"AZURE_AUTHORITY_HOST", "IDENTITY_ENDPOINT", "IDENTITY_HEADER",
+	} {
+		t.Setenv(k, env[k])
+	}
+}
+
+// tokenServer serves tokens numbered by request, checking each request with
+// check and answering with the JSON expiry fields in expiry.
+func tokenServer(t *testing.T, expiry string, check func(r *http.Request)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if check != nil {
+			check(r)
+		}
+		fmt.Fprintf(w, `{"access_token":"tok_%d",%s}`, n.Add(1), expiry)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &n
+}
+
+func TestEntraCredential_Sources(t *testing.T) {
+	assertion := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(assertion, []byte("federated-jwt\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	form := func(r *http.Request, want map[string]string) {
+		if r.Method != http.MethodPost || r.URL.Path != "/tenant-1/oauth2/v2.0/token" {
+			t.Errorf("%s %s, want POST /tenant-1/oauth2/v2.0/token", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		want["grant_type"] = "client_credentials"
+		want["client_id"] = "client-1"
+		want["scope"] = "https://cognitiveservices.azure.com/.default"
+		for k, v := range want {
+			if got := r.PostForm.Get(k); got != v {
+				t.Errorf("form %s = %q, want %q", k, got, v)
+			}
+		}
+	}
+
+	for _, tt := range []struct {
+		name   string
+		source string
+		env    func(url string) map[string]string
+		check  func(r *http.Request)
+	}{
+		{
+			name:   "service principal",
+			source: "service principal",
+			env: func(url string) map[string]string {
+				return map[string]string{"AZURE_AUTHORITY_HOST": url, "AZURE_TENANT_ID": "tenant-1", "AZURE_CLIENT_ID": "client-1", "AZURE_CLIENT_SECRET": "s3cret"}
+			},
+			check: func(r *http.Request) { form(r, map[string]string{"client_secret": "s3cret"}) },
+		},
+		{
+			name:   "workload identity",
+			source: "workload identity",
+			env: func(url string) map[string]string {
+				return map[string]string{"AZURE_AUTHORITY_HOST": url, "AZURE_TENANT_ID": "tenant-1", "AZURE_CLIENT_ID": "client-1", "AZURE_FEDERATED_TOKEN_FILE": assertion}
+			},
+			check: func(r *http.Request) {
+				form(r, map[string]string{
+					"client_assertion":      "federated-jwt",
+					"client_assertion_type": "urn:ietf:params:oauth:client-assertion-type:jwt-bearer",
+				})
+			},
+		},
+		{
+			name:   "app service managed identity",
+			source: "managed identity (App Service)",
+			env: func(url string) map[string]string {
+				return map[string]string{"IDENTITY_ENDPOINT": url + "/msi/token", "IDENTITY_HEADER": "id-header", "AZURE_CLIENT_ID": "client-1"}
+			},
+			check: func(r *http.Request) {
+				q := r.URL.Query()
+				if r.URL.Path != "/msi/token" || r.Header.Get("X-IDENTITY-HEADER") != "id-header" {
+					t.Errorf("%s with header %q, want /msi/token with the identity header", r.URL.Path, r.Header.Get("X-IDENTITY-HEADER"))
+				}
+				if q.Get("resource") != "https://cognitiveservices.azure.com" || q.Get("client_id") != "client-1" {
+					t.Errorf("query %v, want the resource and client_id", q)
+				}
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := tokenServer(t, `"expires_in":3600`, tt.check)
+			setEntraEnv(t, tt.env(srv.URL))
+			c := newEntraCredential("https://cognitiveservices.azure.com/.default")
+			if c.source != tt.source {
+				t.Errorf("source %q, want %q", c.source, tt.source)
+			}
+			for i := 0; i < 2; i++ {
+				tok, err := c.Token(context.Background())
+				if err != nil || tok != "tok_1" {
+					t.Fatalf("Token = %q, %v; want the cached tok_1", tok, err)
+				}
+			}
+			if n := requests.Load(); n != 1 {
+				t.Errorf("%d token requests, want 1", n)
+			}
+		})
+	}
+}
+
+func TestEntraCredential_Expiry(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		expiry string
+		cached bool
+	}{
+		{"expires_in number", `"expires_in":3600`, true},
+		{"expires_in string", `"expires_in":"3600"`, true},
+		{"expires_on", fmt.Sprintf(`"expires_on":"%d"`, time.Now().Add(time.Hour).Unix()), true},
+		{"near expiry", `"expires_in":60`, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := tokenServer(t, tt.expiry, nil)
+			setEntraEnv(t, map[string]string{"IDENTITY_ENDPOINT": srv.URL, "IDENTITY_HEADER": "h"})
+			c := newEntraCredential("https://cognitiveservices.azure.com/.default")
+			_, _ = c.Token(context.Background())
+			tok, err := c.Token(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cached := tok == "tok_1"; cached != tt.cached {
+				t.Errorf("second Token = %q, cached %v; want cached %v", tok, cached, tt.cached)
+			}
+		})
+	}
+}
+
+func TestEntraCredential_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"status", http.StatusUnauthorized, `{"error":"invalid_client"}`, "status 401"},
+		{"no token", http.StatusOK, `{"expires_in":3600}`, "no access_token"},
+		{"bad json", http.StatusOK, `<html>`, "invalid character"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+			setEntraEnv(t, map[string]string{"IDENTITY_ENDPOINT": srv.URL, "IDENTITY_HEADER": "h"})
+			c := newEntraCredential("https://cognitiveservices.azure.com/.default")
+			_, err := c.Token(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), "managed identity (App Service)") {
+				t.Errorf("Token error %v, want one naming the source and containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntraCredential_ConcurrentFetch(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		fmt.Fprint(w, `{"access_token":"tok_shared","expires_in":3600}`)
+	}))
+	defer srv.Close()
+	setEntraEnv(t, map[string]string{"IDENTITY_ENDPOINT": srv.URL, "IDENTITY_HEADER": "h"})
+	c := newEntraCredential("https://cognitiveservices.azure.com/.default")
+
+	var wg sync.WaitGroup
+	toks := make([]string, 4)
+	for i := range toks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			toks[i], _ = c.Token(context.Background())
+		}(i)
+	}
+	for deadline := time.Now().Add(5 * time.Second); requests.Load() == 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("no token request")
+		}
+	}
+
+	// The slow fetch does not hold up a caller that gives up
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.Token(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("caller with a deadline got %v, want context.DeadlineExceeded", err)
+	}
+
+	close(release)
+	wg.Wait()
+	for i, tok := range toks {
+		if tok != "tok_shared" {
+			t.Errorf("caller %d got %q", i, tok)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d token requests for concurrent callers, want 1", n)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keyVaultPrefix marks a setting whose value is the name of a Key Vault secret.
+const keyVaultPrefix = "keyvault:"
+
+// keyVaultScope is the Entra ID scope of Key Vault in the public cloud.
+const keyVaultScope = "https://vault.azure.net/.default"
+
+// minSecretRefresh bounds how often a failed mint triggers a secret refresh.
+const minSecretRefresh = 30 * time.Second
+
+// settings resolves configuration: environment variables first, then the JSON
+// object in CONFIG_FILE. A value "keyvault:<secret-name>" is read from the
+// vault at KEYVAULT_URL.
+type settings struct {
+	file  map[string]string
+	vault *keyVault
+}
+
+// cfg holds the issuer's settings; main loads it before anything else.
+var cfg = &settings{}
+
+func loadSettings() (*settings, error) {
+	st := &settings{}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &st.file); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if vaultURL := st.raw("KEYVAULT_URL"); vaultURL != "" {
+		cred := newEntraCredential(st.rawOr("KEYVAULT_SCOPE", keyVaultScope))
+		st.vault = &keyVault{url: strings.TrimRight(vaultURL, "/"), cred: cred, client: &http.Client{Timeout: 15 * time.Second}}
+		log.Println("Key Vault", vaultURL, "via", cred.source)
+	}
+	return st, nil
+}
+
+// raw returns a setting without resolving Key Vault references.
+func (st *settings) raw(k string) string {
+	if v := os.Getenv(k); v != "" {
+		return v
+	}
+	return st.file[k]
+}
+
+func (st *settings) rawOr(k, def string) string {
+	if v := st.raw(k); v != "" {
+		return v
+	}
+	return def
+}
+
+// get returns a setting, reading it from Key Vault if it is a reference.
+func (st *settings) get(k string) (string, error) {
+	v := st.raw(k)
+	name, ok := strings.CutPrefix(v, keyVaultPrefix)
+	if !ok {
+		return v, nil
+	}
+	if st.vault == nil {
+		return "", fmt.Errorf("%s refers to Key Vault but KEYVAULT_URL is not set", k)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return st.vault.secret(ctx, name)
+}
+
+// secretSetting returns a setting that is re-read from Key Vault when it is a
+// reference, so rotated values are picked up.
+func (st *settings) secretSetting(k string) (*secret, error) {
+	s := &secret{}
+	if name, ok := strings.CutPrefix(st.raw(k), keyVaultPrefix); ok && st.vault != nil {
+		s.name, s.vault = name, st.vault
+	}
+	v, err := st.get(k)
+	if err != nil {
+		return nil, err
+	}
+	s.value, s.refreshed = v, time.Now()
+	return s, nil
+}
+
+// keyVault reads secrets with the Key Vault REST API.
+type keyVault struct {
+	url    string
+	cred   *entraCredential
+	client *http.Client
+}
+
+func (v *keyVault) secret(ctx context.Context, name string) (string, error) {
+	token, err := v.cred.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url+"/secrets/"+url.PathEscape(name)+"?api-version=7.4", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("key vault secret %q: status %d", name, resp.StatusCode)
+	}
+	var sb struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &sb); err != nil {
+		return "", err
+	}
+	if sb.Value == "" {
+		return "", errors.New("key vault secret " + name + " is empty")
+	}
+	return sb.Value, nil
+}
+
+// secret is a setting value that may be refreshed from Key Vault.
+type secret struct {
+	name  string // Key Vault secret name; empty for plain values
+	vault *keyVault
+
+	mu        sync.Mutex
+	value     string
+	refreshed time.Time
+}
+
+// Get returns the current value.
+func (s *secret) Get() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value
+}
+
+// Refresh re-reads the secret from Key Vault; plain values never change.
+func (s *secret) Refresh(ctx context.Context) error {
+	if s.vault == nil {
+		return nil
+	}
+	v, err := s.vault.secret(ctx, s.name)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if v != s.value {
+		log.Printf("Key Vault secret %s rotated", s.name)
+	}
+	s.value, s.refreshed = v, time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// RefreshSoon refreshes the secret in the background, e.g. after the service
+// rejected it, unless it was refreshed within minSecretRefresh.
+func (s *secret) RefreshSoon() {
+	if s.vault == nil {
+		return
+	}
+	s.mu.Lock()
+	recent := time.Since(s.refreshed) < minSecretRefresh
+	if !recent {
+		s.refreshed = time.Now() // Claim this refresh
+	}
+	s.mu.Unlock()
+	if recent {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.Refresh(ctx); err != nil {
+			log.Println("key vault refresh:", err)
+		}
+	}()
+}
+
+// Run refreshes the secret every interval until ctx is done.
+func (s *secret) Run(ctx context.Context, interval time.Duration) {
+	if s.vault == nil || interval <= 0 {
+		return
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Println("key vault refresh:", err)
+			}
+		}
+	}
+}
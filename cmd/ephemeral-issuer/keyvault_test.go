@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeVault serves the Key Vault secrets API, with tokens from its managed
+// identity endpoint at /msi/token.
+type fakeVault struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	secrets map[string]string
+	reads   map[string]int
+}
+
+func newFakeVault(t *testing.T, secrets map[string]string) *fakeVault {
+	t.Helper()
+	v := &fakeVault{secrets: secrets, reads: map[string]int{}}
+	v.Server = httptest.NewServer(http.HandlerFunc(v.serve))
+	t.Cleanup(v.Close)
+	return v
+}
+
+func (v *fakeVault) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/msi/token" {
+		w.Write([]byte(`{"access_token":"vault-token","expires_in":3600}`))
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer vault-token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	name, ok := strings.CutPrefix(r.URL.Path, "/secrets/")
+	if !ok || r.URL.Query().Get("api-version") != "7.4" {
+		http.NotFound(w, r)
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	value, ok := v.secrets[name]
+	if !ok {
+		http.Error(w, `{"error":{"code":"SecretNotFound"}}`, http.StatusNotFound)
+		return
+	}
+	v.reads[name]++
+	json.NewEncoder(w).Encode(map[string]string{"value": value})
+}
+
+// rotate stores a new version of a secret.
+func (v *fakeVault) rotate(name, value string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.secrets[name] = value
+}
+
+func (v *fakeVault) readCount(name string) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.reads[name]
+}
+
+// vaultSettings loads settings that use v, with the extra environment in env.
+func vaultSettings(t *testing.T, v *fakeVault, env map[string]string) *settings {
+	t.Helper()
+	setEntraEnv(t, map[string]string{"IDENTITY_ENDPOINT": v.URL + "/msi/token", "IDENTITY_HEADER": "h"})
+	t.Setenv("KEYVAULT_URL", v.URL+"/")
+	t.Setenv("CONFIG_FILE", "")
+	for k, val := range env {
+		t.Setenv(k, val)
+	}
+	st, err := loadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestSettings_Get(t *testing.T) {
+	v := newFakeVault(t, map[string]string{"openai-key": "sk-from-vault"})
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"FROM_FILE":"file-value","SHADOWED":"file-value"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	st := vaultSettings(t, v, map[string]string{
+		"CONFIG_FILE": file,
+		"SHADOWED":    "env-value",
+		"API_KEY":     "keyvault:openai-key",
+		"MISSING_KEY": "keyvault:no-such-secret",
+	})
+
+	for k, want := range map[string]string{"FROM_FILE": "file-value", "SHADOWED": "env-value", "API_KEY": "sk-from-vault"} {
+		if got, err := st.get(k); err != nil || got != want {
+			t.Errorf("get(%s) = %q, %v; want %q", k, got, err, want)
+		}
+	}
+	if _, err := st.get("MISSING_KEY"); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("missing secret: %v, want a 404 error", err)
+	}
+}
+
+func TestSettings_ReferenceWithoutVault(t *testing.T) {
+	t.Setenv("KEYVAULT_URL", "")
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("API_KEY", "keyvault:openai-key")
+	st, err := loadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.get("API_KEY"); err == nil || !strings.Contains(err.Error(), "KEYVAULT_URL is not set") {
+		t.Errorf("get: %v, want an error about KEYVAULT_URL", err)
+	}
+}
+
+func TestKeyVault_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		handler http.HandlerFunc
+		token   error
+		want    string
+	}{
+		{
+			name:    "forbidden",
+			handler: func(w http.ResponseWriter, r *http.Request) { http.Error(w, "forbidden", http.StatusForbidden) },
+			want:    "status 403",
+		},
+		{
+			name:    "empty value",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{"value":""}`)) },
+			want:    "is empty",
+		},
+		{
+			name:    "bad json",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`<html>`)) },
+			want:    "invalid character",
+		},
+		{
+			name:  "token",
+			token: errors.New("no identity"),
+			want:  "no identity",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+			cred := &entraCredential{source: "test", fetch: func(ctx context.Context) (accessToken, error) {
+				return accessToken{value: "t", expires: time.Now().Add(time.Hour)}, tt.token
+			}}
+			v := &keyVault{url: srv.URL, cred: cred, client: srv.Client()}
+			if _, err := v.secret(context.Background(), "openai-key"); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("secret: %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecret_Rotation(t *testing.T) {
+	v := newFakeVault(t, map[string]string{"openai-key": "sk-v1"})
+	st := vaultSettings(t, v, map[string]string{"API_KEY": "keyvault:openai-key", "PLAIN_KEY": "sk-plain"})
+
+	s, err := st.secretSetting("API_KEY")
+	if err != nil || s.Get() != "sk-v1" {
+		t.Fatalf("secretSetting = %v; want sk-v1", err)
+	}
+	v.rotate("openai-key", "sk-v2")
+	if err := s.Refresh(context.Background()); err != nil || s.Get() != "sk-v2" {
+		t.Errorf("after Refresh: %q, %v; want the new version sk-v2", s.Get(), err)
+	}
+
+	// A failed refresh keeps the current value
+	v.mu.Lock()
+	delete(v.secrets, "openai-key")
+	v.mu.Unlock()
+	if err := s.Refresh(context.Background()); err == nil || s.Get() != "sk-v2" {
+		t.Errorf("failed Refresh: %q, %v; want an error and sk-v2 kept", s.Get(), err)
+	}
+
+	plain, err := st.secretSetting("PLAIN_KEY")
+	if err != nil || plain.Get() != "sk-plain" || plain.Refresh(context.Background()) != nil {
+		t.Errorf("plain setting: %q, %v", plain.Get(), err)
+	}
+}
+
+func TestSecret_RefreshSoon(t *testing.T) {
+	v := newFakeVault(t, map[string]string{"openai-key": "sk-v1"})
+	st := vaultSettings(t, v, map[string]string{"API_KEY": "keyvault:openai-key"})
+	s, err := st.secretSetting("API_KEY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.rotate("openai-key", "sk-v2")
+
+	// Just loaded, so the refresh is skipped
+	s.RefreshSoon()
+	if got := s.Get(); got != "sk-v1" || v.readCount("openai-key") != 1 {
+		t.Errorf("value %q after %d reads, want no refresh within minSecretRefresh", got, v.readCount("openai-key"))
+	}
+
+	s.mu.Lock()
+	s.refreshed = time.Now().Add(-minSecretRefresh)
+	s.mu.Unlock()
+	s.RefreshSoon()
+	s.RefreshSoon() // Claimed by the first call
+	for deadline := time.Now().Add(5 * time.Second); s.Get() != "sk-v2"; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("secret not refreshed")
+		}
+	}
+	if n := v.readCount("openai-key"); n != 2 {
+		t.Errorf("%d reads, want 2", n)
+	}
+}
+
+func TestSecret_Run(t *testing.T) {
+	v := newFakeVault(t, map[string]string{"openai-key": "sk-v1"})
+	st := vaultSettings(t, v, map[string]string{"API_KEY": "keyvault:openai-key"})
+	s, err := st.secretSetting("API_KEY")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+	v.rotate("openai-key", "sk-v2")
+	for deadline := time.Now().Add(5 * time.Second); s.Get() != "sk-v2"; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("rotated secret not picked up")
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
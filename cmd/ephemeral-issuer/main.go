@@ -1,202 +1,255 @@
-// Minimal server that mints ephemeral keys for browser WebRTC clients.
-// Features: optional OIDC (Entra ID) verification for callers and simple CORS.
+// Minimal server that mints ephemeral keys for browser WebRTC clients, using
+// the webrtc/issuer package. Features: optional OIDC (Entra ID) verification
+// for callers, API key or Entra ID (managed identity) auth to Azure OpenAI,
+// per-IP and per-subject rate limiting, per-subject session quotas, an audit
+// log of issued keys, deployment and voice allowlists, simple CORS,
+// Prometheus metrics and a readiness probe. With WS_PROXY=true it also proxies
+// browser WebSocket connections to the realtime API (see the gateway
+// package). Settings come from the environment or a JSON CONFIG_FILE, and may
+// refer to Azure Key Vault secrets.
 package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"expvar"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/MicahParks/keyfunc/v2"
-	oidc "github.com/coreos/go-oidc/v3/oidc"
-	"github.com/golang-jwt/jwt/v5"
-
+	"github.com/enesunal-m/azrealtime"
+	"github.com/enesunal-m/azrealtime/gateway"
 	"github.com/enesunal-m/azrealtime/webrtc"
+	"github.com/enesunal-m/azrealtime/webrtc/issuer"
 )
 
-type TokenResponse struct {
-	SessionID  string `json:"session_id"`
-	Ephemeral  string `json:"ephemeral"`
-	RegionURL  string `json:"region_url"`
-	Deployment string `json:"deployment"`
-}
-
-type server struct {
-	endpoint   string
-	apiKey     string
-	deployment string
-	region     string
-	apiVersion string
-	voice      string
+func main() {
+	var err error
+	if cfg, err = loadSettings(); err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	ic := issuer.Config{
+		Endpoint:           must("AZURE_OPENAI_ENDPOINT"),
+		Deployment:         must("AZURE_OPENAI_REALTIME_DEPLOYMENT"),
+		Region:             env("AZURE_OPENAI_REGION", ""),
+		WebRTCURL:          env("AZURE_OPENAI_WEBRTC_URL", ""),
+		APIVersion:         env("AZURE_OPENAI_API_VERSION", issuer.DefaultAPIVersion),
+		Voice:              env("AZURE_OPENAI_VOICE", "verse"),
+		AllowedDeployments: splitCSV(env("ALLOWED_DEPLOYMENTS", "")),
+		AllowedVoices:      splitCSV(env("ALLOWED_VOICES", "")),
+		AllowedOrigins:     splitCSV(env("CORS_ALLOWED_ORIGINS", "")),
 
-	// OIDC config
-	tokenType string // "id" (ID token) or "access" (JWT access token)
-	issuer    string
-	audience  string
-	verifier  *oidc.IDTokenVerifier
-	jwks      *keyfunc.JWKS
+		IPRatePerMinute:      envInt("RATE_LIMIT_IP_PER_MINUTE", 0),
+		IPBurst:              envInt("RATE_LIMIT_IP_BURST", 5),
+		SubjectRatePerMinute: envInt("RATE_LIMIT_SUBJECT_PER_MINUTE", 0),
+		SubjectBurst:         envInt("RATE_LIMIT_SUBJECT_BURST", 3),
+		TrustProxyHeaders:    env("TRUST_PROXY_HEADERS", "") == "true",
+		TrustedProxyHops:     envInt("TRUSTED_PROXY_HOPS", 1),
 
-	// CORS
-	allowedOrigins []string
-}
+		DailySessions:      envInt("QUOTA_DAILY_SESSIONS", 0),
+		ConcurrentSessions: envInt("QUOTA_CONCURRENT_SESSIONS", 0),
+		SessionDuration:    envDuration("QUOTA_SESSION_DURATION", issuer.DefaultSessionDuration),
+		ReadyCache:         envDuration("READYZ_CACHE", issuer.DefaultReadyCache),
+	}
+	if ic.Region == "" && ic.WebRTCURL == "" {
+		log.Fatalf("missing env AZURE_OPENAI_REGION (or AZURE_OPENAI_WEBRTC_URL)")
+	}
 
-func main() {
-	s := &server{
-		endpoint:   must("AZURE_OPENAI_ENDPOINT"),
-		apiKey:     must("AZURE_OPENAI_API_KEY"),
-		deployment: must("AZURE_OPENAI_REALTIME_DEPLOYMENT"),
-		region:     must("AZURE_OPENAI_REGION"),
-		apiVersion: env("AZURE_OPENAI_API_VERSION", "2025-04-01-preview"),
-		voice:      env("AZURE_OPENAI_VOICE", "verse"),
+	apiKey, err := cfg.secretSetting("AZURE_OPENAI_API_KEY")
+	if err != nil {
+		log.Fatalf("AZURE_OPENAI_API_KEY: %v", err)
+	}
+	go apiKey.Run(context.Background(), envDuration("KEYVAULT_REFRESH_INTERVAL", 5*time.Minute))
+	ic.APIKey = apiKey.Get
+	var cred *entraCredential
+	if apiKey.Get() == "" {
+		cred = newEntraCredential(env("AZURE_OPENAI_TOKEN_SCOPE", webrtc.CognitiveServicesScope))
+		ic.AccessToken = cred.Token
+		log.Println("no AZURE_OPENAI_API_KEY; authenticating to Azure OpenAI with Entra ID via", cred.source)
+	}
+	ic.OnError = func(err error) {
+		log.Println(err)
+		if errors.Is(err, issuer.ErrMint) {
+			apiKey.RefreshSoon() // The key may have been rotated
+		}
 	}
 
 	// OIDC setup
-	if iss := os.Getenv("OIDC_ISSUER"); iss != "" {
-		aud := must("OIDC_AUDIENCE")
-		s.issuer = iss
-		s.audience = aud
-		s.tokenType = env("OIDC_TOKEN_TYPE", "access") // "id" or "access"
-
-		prov, err := oidc.NewProvider(context.Background(), iss)
-		if err != nil {
-			log.Fatalf("oidc provider: %v", err)
+	if iss := env("OIDC_ISSUER", ""); iss != "" {
+		oc := issuer.OIDCConfig{
+			Issuer:    iss,
+			Audience:  must("OIDC_AUDIENCE"),
+			TokenType: env("OIDC_TOKEN_TYPE", "access"), // "id" or "access"
 		}
-
-		if s.tokenType == "id" {
-			s.verifier = prov.Verifier(&oidc.Config{ClientID: aud})
-			log.Println("OIDC (ID token) enabled", iss, "aud", aud)
-		} else {
-			// Access token: load JWKS
-			var disc struct {
-				JWKSURI string `json:"jwks_uri"`
-			}
-			if err := prov.Claims(&disc); err != nil || disc.JWKSURI == "" {
-				log.Fatalf("failed to discover jwks_uri: %v", err)
-			}
-			jwks, err := keyfunc.Get(disc.JWKSURI, keyfunc.Options{
-				RefreshInterval: time.Hour,
-				RefreshTimeout:  10 * time.Second,
-			})
-			if err != nil {
-				log.Fatalf("jwks: %v", err)
-			}
-			s.jwks = jwks
-			log.Println("OIDC (access token) enabled", iss, "aud", aud)
+		if ic.Authenticate, err = issuer.NewOIDCAuthenticator(context.Background(), oc); err != nil {
+			log.Fatalf("%v", err)
 		}
+		log.Println("OIDC ("+oc.TokenType+" token) enabled", iss, "aud", oc.Audience)
 	} else {
 		log.Println("OIDC disabled")
 	}
 
-	if ao := os.Getenv("CORS_ALLOWED_ORIGINS"); ao != "" {
-		s.allowedOrigins = splitCSV(ao)
-		log.Println("CORS allowed origins:", s.allowedOrigins)
+	if len(ic.AllowedOrigins) > 0 {
+		log.Println("CORS allowed origins:", ic.AllowedOrigins)
+	}
+	if ic.IPRatePerMinute > 0 || ic.SubjectRatePerMinute > 0 {
+		log.Println("rate limiting enabled")
+	}
+	if ic.DailySessions > 0 || ic.ConcurrentSessions > 0 {
+		if ic.Authenticate == nil {
+			log.Fatalf("session quotas need OIDC_ISSUER to identify subjects")
+		}
+		log.Println("session quotas enabled")
+	}
+	if ic.Audit, err = auditSink(ic.OnError); err != nil {
+		log.Fatalf("audit log: %v", err)
 	}
 
+	h := issuer.NewHandler(ic)
+	publishStats(h)
+
 	mux := http.NewServeMux()
-	mux.Handle("/token", s.cors(s.auth(http.HandlerFunc(s.handleToken))))
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200)
-		if _, err := w.Write([]byte("ok")); err != nil {
-			log.Printf("Failed to write health check response: %v", err)
-		}
-	})
+	mux.Handle("/", h) // /token, /metrics, /readyz and /healthz
+	mux.Handle("/debug/vars", expvar.Handler())
+	if env("WS_PROXY", "") == "true" {
+		gw := newGateway(ic, apiKey, cred)
+		path := env("WS_PROXY_PATH", "/realtime")
+		mux.Handle(path, h.SessionHandler(gw))
+		expvar.Publish("ws_sessions", expvar.Func(func() any { return gw.Len() }))
+		log.Println("WebSocket proxy on", path)
+	}
 
 	addr := env("ADDR", ":8080")
 	log.Println("ephemeral-issuer on", addr)
 	log.Fatal(http.ListenAndServe(addr, mux))
 }
 
-func (s *server) handleToken(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-	defer cancel()
-	sessionID, eph, err := webrtc.MintEphemeralKey(ctx, s.endpoint, s.apiVersion, s.deployment, s.apiKey, s.voice)
-	if err != nil {
-		log.Println("mint error:", err)
-		http.Error(w, "mint failed", http.StatusBadGateway)
-		return
-	}
-	if err := json.NewEncoder(w).Encode(TokenResponse{
-		SessionID:  sessionID,
-		Ephemeral:  eph,
-		RegionURL:  webrtc.RegionWebRTCURL(s.region),
-		Deployment: s.deployment,
-	}); err != nil {
-		log.Printf("Failed to encode token response: %v", err)
+// newGateway proxies browser WebSockets to the default deployment with the
+// issuer's credential and CORS origins. It is served behind the issuer's
+// SessionHandler, which authenticates callers and applies the rate limits,
+// quotas and audit log.
+func newGateway(ic issuer.Config, apiKey *secret, cred *entraCredential) *gateway.Gateway {
+	if ic.Authenticate == nil {
+		log.Fatalf("WS_PROXY needs OIDC_ISSUER to authenticate browsers")
 	}
-}
-
-// Middleware: OIDC auth
-func (s *server) auth(next http.Handler) http.Handler {
-	if s.issuer == "" {
-		return next
-	}
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		auth := r.Header.Get("Authorization")
-		if !strings.HasPrefix(strings.ToLower(auth), "bearer ") {
-			http.Error(w, "missing bearer", http.StatusUnauthorized)
-			return
-		}
-		raw := strings.TrimSpace(auth[len("Bearer "):])
-		if s.tokenType == "id" {
-			if s.verifier == nil {
-				http.Error(w, "verifier not initialized", http.StatusInternalServerError)
-				return
-			}
-			if _, err := s.verifier.Verify(r.Context(), raw); err != nil {
-				http.Error(w, "invalid token", http.StatusUnauthorized)
-				return
+	gw, err := gateway.New(gateway.Config{
+		Upstream: azrealtime.Config{
+			ResourceEndpoint: ic.Endpoint,
+			Deployment:       ic.Deployment,
+			APIVersion:       env("WS_PROXY_API_VERSION", ic.APIVersion),
+		},
+		Credential: func(ctx context.Context) (azrealtime.Credential, error) {
+			if key := apiKey.Get(); key != "" || cred == nil {
+				return azrealtime.APIKey(key), nil
 			}
-		} else {
-			if s.jwks == nil {
-				http.Error(w, "jwks not initialized", http.StatusInternalServerError)
-				return
-			}
-			tok, err := jwt.Parse(raw, s.jwks.Keyfunc, jwt.WithAudience(s.audience), jwt.WithIssuer(s.issuer))
-			if err != nil || !tok.Valid {
-				http.Error(w, "invalid token", http.StatusUnauthorized)
-				return
+			token, err := cred.Token(ctx)
+			return azrealtime.Bearer(token), err
+		},
+		Authenticate: func(r *http.Request) (string, error) {
+			return issuer.Subject(r.Context()), nil // Verified by SessionHandler
+		},
+		AllowedOrigins: ic.AllowedOrigins,
+		OnError: func(err error) {
+			log.Println("ws proxy:", err)
+			apiKey.RefreshSoon() // The key may have been rotated
+		},
+		OnSessionEnd: func(subject string, err error) {
+			if err != nil {
+				log.Printf("ws proxy: session of %q: %v", subject, err)
 			}
-		}
-		next.ServeHTTP(w, r)
+		},
 	})
+	if err != nil {
+		log.Fatalf("ws proxy: %v", err)
+	}
+	return gw
 }
 
-// Middleware: CORS
-func (s *server) cors(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		origin := r.Header.Get("Origin")
-		if origin != "" && (len(s.allowedOrigins) == 0 || contains(s.allowedOrigins, origin) || contains(s.allowedOrigins, "*")) {
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
-			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		}
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
+// auditSink builds the audit sink from AUDIT_LOG and AUDIT_WEBHOOK_URL, or
+// returns nil if both are off. AUDIT_LOG is "stdout" (the default), "stderr",
+// "off" or a file to append to.
+func auditSink(onError func(error)) (issuer.AuditSink, error) {
+	var sinks issuer.MultiSink
+	switch dest := env("AUDIT_LOG", "stdout"); dest {
+	case "off":
+	case "stdout":
+		sinks = append(sinks, issuer.NewWriterSink(os.Stdout))
+	case "stderr":
+		sinks = append(sinks, issuer.NewWriterSink(os.Stderr))
+	default:
+		f, err := os.OpenFile(dest, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, err
 		}
-		next.ServeHTTP(w, r)
-	})
+		sinks = append(sinks, issuer.NewWriterSink(f))
+	}
+	if u := env("AUDIT_WEBHOOK_URL", ""); u != "" {
+		sinks = append(sinks, issuer.NewWebhookSink(u, onError))
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	return sinks, nil
+}
+
+// publishStats serves the handler's counters on /debug/vars.
+func publishStats(h *issuer.Handler) {
+	for name, v := range map[string]func(issuer.Stats) int64{
+		"tokens_issued":        func(s issuer.Stats) int64 { return s.TokensIssued },
+		"mint_failures":        func(s issuer.Stats) int64 { return s.MintFailures },
+		"auth_failures":        func(s issuer.Stats) int64 { return s.AuthFailures },
+		"rate_limited_ip":      func(s issuer.Stats) int64 { return s.RateLimitedIP },
+		"rate_limited_subject": func(s issuer.Stats) int64 { return s.RateLimitedSubject },
+		"quota_exceeded":       func(s issuer.Stats) int64 { return s.QuotaExceeded },
+		"audit_failures":       func(s issuer.Stats) int64 { return s.AuditFailures },
+	} {
+		expvar.Publish(name, expvar.Func(func() any { return v(h.Stats()) }))
+	}
 }
 
 // helpers
 func must(k string) string {
-	v := os.Getenv(k)
+	v := env(k, "")
 	if v == "" {
 		log.Fatalf("missing env %s", k)
 	}
 	return v
 }
 func env(k, def string) string {
-	if v := os.Getenv(k); v != "" {
+	v, err := cfg.get(k)
+	if err != nil {
+		log.Fatalf("%s: %v", k, err)
+	}
+	if v != "" {
 		return v
 	}
 	return def
 }
+func envDuration(k string, def time.Duration) time.Duration {
+	v := env(k, "")
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("invalid env %s: %v", k, err)
+	}
+	return d
+}
+func envInt(k string, def int) int {
+	v := env(k, "")
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("invalid env %s: %v", k, err)
+	}
+	return n
+}
 func splitCSV(s string) []string {
 	parts := strings.Split(s, ",")
 	out := make([]string, 0, len(parts))
@@ -207,11 +260,3 @@ func splitCSV(s string) []string {
 	}
 	return out
 }
-func contains(a []string, v string) bool {
-	for _, x := range a {
-		if x == v {
-			return true
-		}
-	}
-	return false
-}
@@ -20,6 +20,18 @@ func (k APIKey) apply(h http.Header) {
 	}
 }
 
+// openAIAuth sends an API key as a Bearer token, as openai.com expects.
+type openAIAuth struct{ Credential }
+
+// apply converts APIKey credentials to an Authorization header.
+func (a openAIAuth) apply(h http.Header) {
+	if k, ok := a.Credential.(APIKey); ok {
+		Bearer(k).apply(h)
+		return
+	}
+	a.Credential.apply(h)
+}
+
 // Bearer implements Credential using OAuth2 Bearer token authentication.
 // Use this when authenticating with Azure AD tokens or other Bearer tokens.
 type Bearer string
@@ -31,22 +43,48 @@ func (b Bearer) apply(h http.Header) {
 	}
 }
 
+// Provider selects which realtime service a Config targets.
+type Provider string
+
+const (
+	// ProviderAzure targets an Azure OpenAI resource (the default).
+	ProviderAzure Provider = "azure"
+	// ProviderOpenAI targets the openai.com realtime endpoint.
+	ProviderOpenAI Provider = "openai"
+)
+
+// DefaultOpenAIEndpoint is the base URL used for ProviderOpenAI when ResourceEndpoint is empty.
+const DefaultOpenAIEndpoint = "https://api.openai.com"
+
+// DefaultMaxMessageBytes is the inbound message size limit used when Config.MaxMessageBytes is zero.
+const DefaultMaxMessageBytes = 8 << 20
+
 // Config holds all configuration options for creating an Azure OpenAI Realtime client.
 // All fields marked as required must be provided for successful connection.
 type Config struct {
+	// Provider selects the realtime service: ProviderAzure or ProviderOpenAI.
+	// With ProviderOpenAI the client connects to {ResourceEndpoint}/v1/realtime?model={Deployment}
+	// and sends APIKey credentials as "Authorization: Bearer".
+	// Required: No (default: ProviderAzure)
+	Provider Provider
+
 	// ResourceEndpoint is the base URL of your Azure OpenAI resource.
-	// Format: https://{resource-name}.openai.azure.com
-	// Required: Yes
+	// Format: https://{resource-name}.openai.azure.com, or the resource's host
+	// in a sovereign cloud (e.g. openai.azure.us for Azure Government,
+	// openai.azure.cn for Azure China). A path is kept as a prefix, so private
+	// endpoints behind a gateway such as https://gateway.contoso.com/aoai work.
+	// Required: Yes for ProviderAzure (ProviderOpenAI defaults to DefaultOpenAIEndpoint)
 	ResourceEndpoint string
 
 	// Deployment is the name of your GPT-4o Realtime deployment.
 	// This should match the deployment name configured in Azure OpenAI Studio.
+	// With ProviderOpenAI this is the model name (e.g. "gpt-4o-realtime-preview").
 	// Required: Yes
 	Deployment string
 
 	// APIVersion specifies the Azure OpenAI API version to use.
 	// Recommended: "2025-04-01-preview" (latest as of implementation)
-	// Required: Yes
+	// Required: Yes for ProviderAzure (ignored by ProviderOpenAI)
 	APIVersion string
 
 	// Credential provides authentication for API requests.
@@ -60,6 +98,88 @@ type Config struct {
 	// Required: No
 	DialTimeout time.Duration
 
+	// SessionExpiryWarning sets how long before the server session's expires_at
+	// the OnSessionExpiring callback fires.
+	// If zero, DefaultSessionExpiryWarning (1 minute) is used.
+	// Required: No
+	SessionExpiryWarning time.Duration
+
+	// ReconnectOnSessionExpiry makes the client call Reconnect automatically when
+	// the session expiry warning fires, so long-running sessions survive expiry.
+	// The new session receives the configuration sent through SessionUpdate, but
+	// not the previous conversation history.
+	// Required: No (default: false)
+	ReconnectOnSessionExpiry bool
+
+	// ReconnectOnErrors lists the error classes (see ErrorEvent.Class) that make
+	// the client call Reconnect automatically, restoring the session configuration
+	// on a new server session. For example, []ErrorClass{ErrorClassSessionExpired,
+	// ErrorClassInvalidState} self-heals fatal session errors. Use OnErrorRecovery
+	// to observe the outcome.
+	// Required: No (default: never reconnect on errors)
+	ReconnectOnErrors []ErrorClass
+
+	// StreamIntegrityChecks makes the client verify that response events arrive
+	// complete and in order: every delta belongs to an announced, unfinished
+	// output item and content part, and text deltas add up to the final text.
+	// Problems are reported to OnError as a client_error with code
+	// ErrorCodeStreamIntegrity and a *StreamIntegrityError Cause.
+	// Required: No (default: false)
+	StreamIntegrityChecks bool
+
+	// AutoReconnect, if set, makes the client redial with backoff when the
+	// connection is lost instead of closing. Session and response events sent
+	// meanwhile are buffered and flushed on the new connection; input audio is
+	// dropped. See AutoReconnectConfig.
+	// Required: No (default: nil, a lost connection closes the client)
+	AutoReconnect *AutoReconnectConfig
+
+	// StatsHandler, if set, is called periodically with a snapshot of the client's
+	// traffic counters (see Client.Stats), e.g. to export them to Prometheus.
+	// It is also called once more when the client closes.
+	// Required: No
+	StatsHandler func(Stats)
+
+	// StatsInterval sets how often StatsHandler is called.
+	// If zero, DefaultStatsInterval (10 seconds) is used.
+	// Required: No
+	StatsInterval time.Duration
+
+	// RateLimitMode makes CreateResponse and AppendPCM16 respect the limits reported
+	// in rate_limits.updated events: RateLimitWait blocks until quota resets and
+	// RateLimitFail returns an error matching ErrRateLimited instead of sending.
+	// Required: No (default: RateLimitIgnore)
+	RateLimitMode RateLimitMode
+
+	// AdaptiveLimiter, if set, spaces out CreateResponse calls as the quota
+	// reported in rate_limits.updated runs low, so they stay under the limit
+	// instead of being refused. It is applied before RateLimitMode and can be
+	// shared by clients using the same deployment.
+	// Required: No
+	AdaptiveLimiter *AdaptiveLimiter
+
+	// ValidationMode controls local validation of SessionUpdate and
+	// CreateResponse payloads: ValidationWarn logs problems through Logger or
+	// StructuredLogger and sends anyway, ValidationOff skips the checks.
+	// Required: No (default: ValidationStrict)
+	ValidationMode ValidationMode
+
+	// Pricing, if set, is used to estimate SessionReport.EstimatedCost from token usage.
+	// Required: No
+	Pricing *Pricing
+
+	// MaxMessageBytes caps the size of a single inbound WebSocket message.
+	// Larger messages are discarded without being buffered, and reported to the
+	// OnError handler as a client_error with code "message_too_large".
+	// If zero, DefaultMaxMessageBytes (8 MiB) is used.
+	// Required: No
+	MaxMessageBytes int64
+
+	// AutoChunkAudio makes AppendPCM16 and AppendG711 split buffers larger than
+	// MaxAppendBytes into several append messages instead of rejecting them.
+	// Required: No (default: false)
+	AutoChunkAudio bool
+
 	// HandshakeHeaders allows adding custom headers to the WebSocket handshake request.
 	// Useful for proxy authentication, tracing headers, etc.
 	// Required: No
@@ -71,9 +191,31 @@ type Config struct {
 	// Required: No (if nil, no logging occurs)
 	Logger func(event string, fields map[string]any)
 
+	// LogAudioPayloads keeps base64 audio in logged event payloads. By default
+	// long base64 runs are truncated. Credentials (api-key headers and query
+	// parameters, bearer tokens, OpenAI and ephemeral keys) are always redacted
+	// from log output.
+	// Required: No (default: false)
+	LogAudioPayloads bool
+
 	// StructuredLogger provides advanced structured logging with configurable levels.
 	// If both Logger and StructuredLogger are provided, StructuredLogger takes precedence.
-	// Use NewLogger() or NewLoggerFromEnv() to create a structured logger.
+	// Use NewLogger(), NewLoggerFromEnv() or NewSlogLogger(), or plug in any
+	// implementation of the StructuredLogger interface, e.g. backed by zap or zerolog.
 	// Required: No (if nil, falls back to Logger or no logging)
-	StructuredLogger *Logger
+	StructuredLogger StructuredLogger
+
+	// LogSampling limits the per-event "event_received" and "event_sent" debug
+	// lines written to StructuredLogger, so audio and text deltas do not flood
+	// the log; suppressed lines are summarized periodically.
+	// Required: No (default: DefaultLogSampling())
+	LogSampling *LogSampling
+
+	// CorrelationID, e.g. a call or trace ID, is added as "correlation_id" to
+	// every log line and to the metadata of every response.create, so a voice
+	// pipeline can be traced across services. It can be changed later with
+	// Client.SetCorrelationID, and ContextWithCorrelationID overrides it for a
+	// single CreateResponse call.
+	// Required: No (default: none)
+	CorrelationID string
 }
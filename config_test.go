@@ -149,3 +149,85 @@ func TestConfig_validation(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenAIAuth_apply(t *testing.T) {
+	h := http.Header{}
+	openAIAuth{APIKey("sk-test")}.apply(h)
+	if h.Get("Authorization") != "Bearer sk-test" {
+		t.Errorf("expected Bearer header, got %q", h.Get("Authorization"))
+	}
+	if h.Get("api-key") != "" {
+		t.Errorf("expected no api-key header, got %q", h.Get("api-key"))
+	}
+
+	h = http.Header{}
+	openAIAuth{Bearer("ek-test")}.apply(h)
+	if h.Get("Authorization") != "Bearer ek-test" {
+		t.Errorf("expected Bearer header, got %q", h.Get("Authorization"))
+	}
+}
+
+func TestRealtimeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected string
+	}{
+		{
+			name: "azure",
+			cfg: Config{
+				ResourceEndpoint: "https://test.openai.azure.com",
+				Deployment:       "gpt-4o-realtime",
+				APIVersion:       "2025-04-01-preview",
+			},
+			expected: "wss://test.openai.azure.com/openai/realtime?api-version=2025-04-01-preview&deployment=gpt-4o-realtime",
+		},
+		{
+			name: "azure government",
+			cfg: Config{
+				ResourceEndpoint: "https://test.openai.azure.us/",
+				Deployment:       "gpt-4o-realtime",
+				APIVersion:       "2025-04-01-preview",
+			},
+			expected: "wss://test.openai.azure.us/openai/realtime?api-version=2025-04-01-preview&deployment=gpt-4o-realtime",
+		},
+		{
+			name: "private endpoint behind a gateway",
+			cfg: Config{
+				ResourceEndpoint: "wss://gateway.contoso.com/aoai/",
+				Deployment:       "gpt-4o-realtime",
+				APIVersion:       "2025-04-01-preview",
+			},
+			expected: "wss://gateway.contoso.com/aoai/openai/realtime?api-version=2025-04-01-preview&deployment=gpt-4o-realtime",
+		},
+		{
+			name: "openai default endpoint",
+			cfg: Config{
+				Provider:   ProviderOpenAI,
+				Deployment: "gpt-4o-realtime-preview",
+			},
+			expected: "wss://api.openai.com/v1/realtime?model=gpt-4o-realtime-preview",
+		},
+		{
+			name: "openai custom endpoint",
+			cfg: Config{
+				Provider:         ProviderOpenAI,
+				ResourceEndpoint: "http://localhost:8080",
+				Deployment:       "gpt-4o-realtime-preview",
+			},
+			expected: "ws://localhost:8080/v1/realtime?model=gpt-4o-realtime-preview",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := realtimeURL(tt.cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if u.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, u.String())
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package azrealtime
 
+import "encoding/base64"
+
 // ConversationItem represents an item in the conversation.
 // Items can be messages, function calls, or function call responses.
 type ConversationItem struct {
@@ -23,6 +25,28 @@ type ContentPart struct {
 	Transcript string `json:"transcript,omitempty"` // The transcript of the audio
 }
 
+// NewUserTextItem returns a user message with text content, ready for
+// Client.CreateConversationItem.
+func NewUserTextItem(text string) ConversationItem {
+	return ConversationItem{
+		Type:    "message",
+		Role:    "user",
+		Content: []ContentPart{{Type: "input_text", Text: text}},
+	}
+}
+
+// NewUserAudioItem returns a user message with audio content, e.g. to add
+// prerecorded speech to the conversation history. pcm must be in the
+// session's input audio format (PCM16 at 24kHz unless changed); it is
+// base64-encoded into the item.
+func NewUserAudioItem(pcm []byte) ConversationItem {
+	return ConversationItem{
+		Type:    "message",
+		Role:    "user",
+		Content: []ContentPart{{Type: "input_audio", Audio: base64.StdEncoding.EncodeToString(pcm)}},
+	}
+}
+
 // ResponseObject represents a response from the assistant.
 type ResponseObject struct {
 	ID            string                 `json:"id"`                       // The unique ID of the response
@@ -45,9 +69,16 @@ type ResponseUsage struct {
 
 // ResponseUsageInputTokens provides a breakdown of input tokens.
 type ResponseUsageInputTokens struct {
-	CachedTokens int `json:"cached_tokens"` // Number of cached tokens used
-	TextTokens   int `json:"text_tokens"`   // Number of text tokens used
-	AudioTokens  int `json:"audio_tokens"`  // Number of audio tokens used
+	CachedTokens        int                        `json:"cached_tokens"`                   // Number of cached tokens used
+	TextTokens          int                        `json:"text_tokens"`                     // Number of text tokens used
+	AudioTokens         int                        `json:"audio_tokens"`                    // Number of audio tokens used
+	CachedTokensDetails *ResponseUsageCachedTokens `json:"cached_tokens_details,omitempty"` // Breakdown of cached tokens
+}
+
+// ResponseUsageCachedTokens provides a breakdown of cached input tokens.
+type ResponseUsageCachedTokens struct {
+	TextTokens  int `json:"text_tokens"`  // Number of cached text tokens
+	AudioTokens int `json:"audio_tokens"` // Number of cached audio tokens
 }
 
 // ResponseUsageOutputTokens provides a breakdown of output tokens.
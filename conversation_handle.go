@@ -0,0 +1,182 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ResponseConversationMetadataKey names the ConversationHandle a response
+// belongs to in its metadata; see ConversationOf.
+const ResponseConversationMetadataKey = "azrealtime_conversation"
+
+// ConversationHandle is a named conversation that shares the client's
+// connection with the default conversation and with other handles, e.g. to
+// serve several chat threads or agents over one socket.
+//
+// The server keeps a single conversation per session, so a handle keeps its
+// history on the client: AddItem appends locally, and CreateResponse requests
+// an out-of-band response (see CreateOutOfBandResponse) whose input is that
+// history. The response's output is appended to the history when it is done.
+// Its events do not touch the default conversation, the Transcript or
+// UseTools; filter them with Owns. To answer a function call, add a
+// function_call_output item and create another response.
+//
+// Handles survive Reconnect. A ConversationHandle is safe for concurrent use.
+type ConversationHandle struct {
+	c    *Client
+	name string
+
+	mu    sync.Mutex
+	items []ConversationItem
+}
+
+// Conversation returns the handle for the named conversation, creating it on
+// first use. Later calls with the same name return the same handle.
+func (c *Client) Conversation(name string) *ConversationHandle {
+	return c.conversations.get(c, name)
+}
+
+// ConversationOf returns the name of the ConversationHandle that created r,
+// or "" for responses of the default conversation.
+func ConversationOf(r ResponseObject) string {
+	name, _ := r.Metadata[ResponseConversationMetadataKey].(string)
+	return name
+}
+
+// Name returns the conversation's name.
+func (h *ConversationHandle) Name() string {
+	return h.name
+}
+
+// AddItem appends items to the conversation's history. Nothing is sent until
+// the next CreateResponse.
+func (h *ConversationHandle) AddItem(items ...ConversationItem) error {
+	for _, item := range items {
+		if item.Type == "" {
+			return errors.New("azrealtime: conversation item type is required")
+		}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.items = append(h.items, items...)
+	return nil
+}
+
+// Items returns a copy of the conversation's history.
+func (h *ConversationHandle) Items() []ConversationItem {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]ConversationItem(nil), h.items...)
+}
+
+// Reset clears the conversation's history.
+func (h *ConversationHandle) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.items = nil
+}
+
+// CreateResponse requests a response to the conversation's history. opts.Input
+// and opts.Conversation are set by the handle; the other options apply as for
+// Client.CreateResponse. Await the result with a ResponseTracker and the
+// returned tag.
+func (h *ConversationHandle) CreateResponse(ctx context.Context, opts CreateResponseOptions) (eventID, tag string, err error) {
+	h.mu.Lock()
+	input := make([]any, 0, len(h.items))
+	for _, item := range h.items {
+		if restored, ok := restorableItem(item); ok {
+			input = append(input, restored)
+		}
+	}
+	h.mu.Unlock()
+
+	opts.Input = input
+	md := make(map[string]any, len(opts.Metadata)+1)
+	for k, v := range opts.Metadata {
+		md[k] = v
+	}
+	md[ResponseConversationMetadataKey] = h.name
+	opts.Metadata = md
+	return h.c.CreateOutOfBandResponse(ctx, opts)
+}
+
+// Owns reports whether the response with the given ID belongs to this
+// conversation. Use it to filter events that carry a response ID, such as
+// ResponseTextDelta. It is true from response.created until response.done.
+func (h *ConversationHandle) Owns(responseID string) bool {
+	return h.c.conversations.owner(responseID) == h
+}
+
+// conversationHandles routes responses to the handles that created them.
+type conversationHandles struct {
+	mu         sync.Mutex
+	byName     map[string]*ConversationHandle
+	byResponse map[string]*ConversationHandle // In-progress responses
+}
+
+func (s *conversationHandles) get(c *Client, name string) *ConversationHandle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if h, ok := s.byName[name]; ok {
+		return h
+	}
+	if s.byName == nil {
+		s.byName = make(map[string]*ConversationHandle)
+	}
+	h := &ConversationHandle{c: c, name: name}
+	s.byName[name] = h
+	return h
+}
+
+func (s *conversationHandles) owner(responseID string) *ConversationHandle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.byResponse[responseID]
+}
+
+func (s *conversationHandles) responseCreated(r ResponseObject) {
+	name := ConversationOf(r)
+	if name == "" || r.ID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.byName[name]
+	if !ok {
+		return
+	}
+	if s.byResponse == nil {
+		s.byResponse = make(map[string]*ConversationHandle)
+	}
+	s.byResponse[r.ID] = h
+}
+
+// responseDone appends the response's output to its conversation's history.
+func (s *conversationHandles) responseDone(r ResponseObject) {
+	name := ConversationOf(r)
+	if name == "" {
+		return
+	}
+	s.mu.Lock()
+	h := s.byName[name]
+	delete(s.byResponse, r.ID)
+	s.mu.Unlock()
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, item := range r.Output {
+		if restored, ok := restorableItem(item); ok {
+			h.items = append(h.items, restored)
+		}
+	}
+}
+
+// reset forgets in-progress responses, which cannot complete on a new connection.
+func (s *conversationHandles) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byResponse = nil
+}
@@ -0,0 +1,99 @@
+package azrealtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConversationHandle(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	sent := recordOutbound(client)
+	tracker := client.TrackResponses()
+	defer tracker.Stop()
+
+	support := client.Conversation("support")
+	sales := client.Conversation("sales")
+	if client.Conversation("support") != support || support.Name() != "support" {
+		t.Fatal("Conversation does not return the same handle per name")
+	}
+
+	owned := make(chan bool, 8)
+	client.OnResponseTextDelta(func(e ResponseTextDelta) { owned <- support.Owns(e.ResponseID) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ask := func(h *ConversationHandle, text string) CompletedResponse {
+		t.Helper()
+		if err := h.AddItem(NewUserTextItem(text)); err != nil {
+			t.Fatalf("AddItem: %v", err)
+		}
+		_, tag, err := h.CreateResponse(ctx, CreateResponseOptions{Modalities: []string{"text"}})
+		if err != nil {
+			t.Fatalf("CreateResponse: %v", err)
+		}
+		resp, err := tracker.AwaitTag(ctx, tag)
+		if err != nil {
+			t.Fatalf("AwaitTag: %v", err)
+		}
+		return resp
+	}
+
+	ask(support, "My order is late.")
+	if !<-owned {
+		t.Error("support does not own its response")
+	}
+	ask(sales, "Do you ship to Norway?")
+	if <-owned {
+		t.Error("support owns the sales response")
+	}
+	resp := ask(support, "Can you check again?")
+	if got := ConversationOf(ResponseObject{Metadata: map[string]any{ResponseConversationMetadataKey: "support"}}); got != "support" {
+		t.Errorf("ConversationOf = %q", got)
+	}
+	if support.Owns(resp.ID) {
+		t.Error("finished response is still owned")
+	}
+
+	// Each handle keeps its own history, including the replies
+	items := support.Items()
+	if len(items) != 4 {
+		t.Fatalf("support has %d items, want 4: %+v", len(items), items)
+	}
+	if items[1].Role != "assistant" || items[1].Content[0].Text != "Hello from mock server!" {
+		t.Errorf("reply item = %+v", items[1])
+	}
+	if n := len(sales.Items()); n != 2 {
+		t.Errorf("sales has %d items, want 2", n)
+	}
+
+	// Responses carry only their conversation's history and stay out of band
+	creates := sent.ofType("response.create")
+	if len(creates) != 3 {
+		t.Fatalf("sent %d response.create, want 3", len(creates))
+	}
+	last := creates[2]["response"].(map[string]any)
+	if last["conversation"] != ConversationNone {
+		t.Errorf("conversation = %v", last["conversation"])
+	}
+	if md := last["metadata"].(map[string]any); md[ResponseConversationMetadataKey] != "support" || md[ResponseOutOfBandMetadataKey] != "true" {
+		t.Errorf("metadata = %v", md)
+	}
+	if input := last["input"].([]any); len(input) != 3 {
+		t.Errorf("input has %d items, want the 3 support items so far", len(input))
+	}
+	if n := len(sent.ofType("conversation.item.create")); n != 0 {
+		t.Errorf("sent %d conversation.item.create, want none", n)
+	}
+	if entries := client.Transcript().Entries; len(entries) != 0 {
+		t.Errorf("default transcript has entries: %+v", entries)
+	}
+
+	support.Reset()
+	if n := len(support.Items()); n != 0 {
+		t.Errorf("Reset left %d items", n)
+	}
+	if err := support.AddItem(ConversationItem{}); err == nil {
+		t.Error("expected error for an item without type")
+	}
+}
@@ -0,0 +1,51 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestNewUserTextItem(t *testing.T) {
+	item := NewUserTextItem("Hello")
+	if item.Type != "message" || item.Role != "user" || len(item.Content) != 1 {
+		t.Fatalf("item = %+v", item)
+	}
+	if part := item.Content[0]; part.Type != "input_text" || part.Text != "Hello" {
+		t.Errorf("content = %+v", part)
+	}
+}
+
+func TestNewUserAudioItem(t *testing.T) {
+	pcm := []byte{0x01, 0x02, 0xff, 0x7f}
+	item := NewUserAudioItem(pcm)
+	if item.Type != "message" || item.Role != "user" || len(item.Content) != 1 {
+		t.Fatalf("item = %+v", item)
+	}
+	part := item.Content[0]
+	if part.Type != "input_audio" || part.Text != "" {
+		t.Errorf("content = %+v", part)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(part.Audio)
+	if err != nil || string(decoded) != string(pcm) {
+		t.Errorf("audio = %q (%v), want the base64 of the input", part.Audio, err)
+	}
+
+	// The item is accepted as is
+	client, _ := dialProfile(t, nil)
+	sent := recordOutbound(client)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.CreateConversationItem(ctx, item); err != nil {
+		t.Fatalf("CreateConversationItem: %v", err)
+	}
+	creates := sent.ofType("conversation.item.create")
+	if len(creates) != 1 {
+		t.Fatalf("sent %d conversation.item.create, want 1", len(creates))
+	}
+	content := creates[0]["item"].(map[string]any)["content"].([]any)[0].(map[string]any)
+	if content["type"] != "input_audio" || content["audio"] != part.Audio {
+		t.Errorf("sent content = %v", content)
+	}
+}
@@ -0,0 +1,87 @@
+package azrealtime
+
+import (
+	"context"
+	"sync"
+)
+
+// CorrelationIDMetadataKey is the response metadata key, and the log field,
+// that carries the correlation ID of a client (see Config.CorrelationID).
+const CorrelationIDMetadataKey = "correlation_id"
+
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying a correlation ID,
+// e.g. the trace ID of the request that triggered a response. CreateResponse
+// sends it in place of the client's correlation ID.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID set with
+// ContextWithCorrelationID, or "" if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// correlation holds the correlation ID set with SetCorrelationID.
+type correlation struct {
+	mu  sync.RWMutex
+	id  string
+	set bool
+}
+
+// SetCorrelationID replaces the client's correlation ID, which is added as
+// CorrelationIDMetadataKey to every log line and to the metadata of every
+// response.create. An empty id stops adding it.
+func (c *Client) SetCorrelationID(id string) {
+	c.correlation.mu.Lock()
+	defer c.correlation.mu.Unlock()
+	c.correlation.id, c.correlation.set = id, true
+}
+
+// CorrelationID returns the client's correlation ID: the last value passed to
+// SetCorrelationID, or Config.CorrelationID.
+func (c *Client) CorrelationID() string {
+	c.correlation.mu.RLock()
+	defer c.correlation.mu.RUnlock()
+	if c.correlation.set {
+		return c.correlation.id
+	}
+	return c.cfg.CorrelationID
+}
+
+// correlateResponse returns opts with the correlation ID of ctx, or else the
+// client's, in its metadata. An ID the caller already put there is kept.
+func (c *Client) correlateResponse(ctx context.Context, opts CreateResponseOptions) CreateResponseOptions {
+	id := CorrelationIDFromContext(ctx)
+	if id == "" {
+		id = c.CorrelationID()
+	}
+	if _, ok := opts.Metadata[CorrelationIDMetadataKey]; ok || id == "" {
+		return opts
+	}
+	md := make(map[string]any, len(opts.Metadata)+1)
+	for k, v := range opts.Metadata {
+		md[k] = v
+	}
+	md[CorrelationIDMetadataKey] = id
+	opts.Metadata = md
+	return opts
+}
+
+// logFields prepares fields for the configured logger: credentials and audio
+// are redacted and the correlation ID is added.
+func (c *Client) logFields(fields map[string]any) map[string]any {
+	out := redactFields(fields, c.cfg.LogAudioPayloads)
+	if id := c.CorrelationID(); id != "" {
+		if out == nil {
+			out = make(map[string]any, 1)
+		}
+		if _, ok := out[CorrelationIDMetadataKey]; !ok {
+			out[CorrelationIDMetadataKey] = id
+		}
+	}
+	return out
+}
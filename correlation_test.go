@@ -0,0 +1,73 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClient_CorrelationID(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	var mu sync.Mutex
+	logged := map[string]map[string]any{}
+	cfg := CreateMockConfig(mockServer.URL())
+	cfg.CorrelationID = "call-1"
+	cfg.Logger = func(event string, fields map[string]any) {
+		mu.Lock()
+		defer mu.Unlock()
+		logged[event] = fields
+	}
+	client, err := Dial(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+	sent := recordOutbound(client)
+
+	mu.Lock()
+	got := logged["ws_connected"][CorrelationIDMetadataKey]
+	mu.Unlock()
+	if got != "call-1" {
+		t.Errorf("ws_connected correlation_id = %v, want call-1", got)
+	}
+
+	ctx := context.Background()
+	create := func(ctx context.Context, md map[string]any) {
+		t.Helper()
+		if _, err := client.CreateResponse(ctx, CreateResponseOptions{Metadata: md}); err != nil {
+			t.Fatalf("CreateResponse: %v", err)
+		}
+	}
+	create(ctx, nil)
+	create(ContextWithCorrelationID(ctx, "trace-9"), nil)
+	create(ctx, map[string]any{CorrelationIDMetadataKey: "explicit"})
+	client.SetCorrelationID("")
+	create(ctx, nil)
+
+	creates := sent.ofType("response.create")
+	if len(creates) != 4 {
+		t.Fatalf("sent %d response.create events, want 4", len(creates))
+	}
+	for i, want := range []any{"call-1", "trace-9", "explicit", nil} {
+		md, _ := creates[i]["response"].(map[string]any)["metadata"].(map[string]any)
+		if md[CorrelationIDMetadataKey] != want {
+			t.Errorf("response.create %d correlation_id = %v, want %v", i, md[CorrelationIDMetadataKey], want)
+		}
+	}
+	if id := client.CorrelationID(); id != "" {
+		t.Errorf("CorrelationID() = %q after SetCorrelationID(\"\")", id)
+	}
+}
+
+func TestValidateConfig_CorrelationIDTooLong(t *testing.T) {
+	cfg := CreateMockConfig("ws://localhost")
+	cfg.CorrelationID = strings.Repeat("x", maxMetadataValueLength+1)
+	var cfgErr *ConfigError
+	if err := ValidateConfig(cfg); !errors.As(err, &cfgErr) || cfgErr.Field != "CorrelationID" {
+		t.Errorf("ValidateConfig = %v, want CorrelationID error", err)
+	}
+}
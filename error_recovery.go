@@ -0,0 +1,119 @@
+package azrealtime
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ErrorClass groups server error events by how a client can react to them.
+type ErrorClass string
+
+// Error classes returned by ErrorEvent.Class.
+const (
+	ErrorClassSessionExpired ErrorClass = "session_expired" // The server session reached its maximum lifetime
+	ErrorClassInvalidState   ErrorClass = "invalid_state"   // The server session can no longer be used
+	ErrorClassServer         ErrorClass = "server"          // Internal server failure
+	ErrorClassRateLimit      ErrorClass = "rate_limit"      // A rate limit was exceeded
+	ErrorClassInvalidRequest ErrorClass = "invalid_request" // A client event was rejected
+	ErrorClassClient         ErrorClass = "client"          // Raised locally by the client (ErrorTypeClient)
+	ErrorClassOther          ErrorClass = "other"           // Anything else
+)
+
+// invalidStateCodes are error codes reporting that the session cannot continue.
+var invalidStateCodes = map[string]bool{
+	"session_not_found":     true,
+	"session_closed":        true,
+	"invalid_session_state": true,
+	"session_terminated":    true,
+}
+
+// Class classifies the error event. Session expiry and invalid session state are
+// fatal for the current session; see ErrorClass.Fatal.
+func (e ErrorEvent) Class() ErrorClass {
+	code := e.Error.Code
+	switch {
+	case code == "session_expired" || strings.Contains(strings.ToLower(e.Error.Message), "session expired"):
+		return ErrorClassSessionExpired
+	case invalidStateCodes[code]:
+		return ErrorClassInvalidState
+	case e.IsRateLimited():
+		return ErrorClassRateLimit
+	case e.IsServerError():
+		return ErrorClassServer
+	case e.IsInvalidRequest():
+		return ErrorClassInvalidRequest
+	case e.Error.Type == ErrorTypeClient:
+		return ErrorClassClient
+	default:
+		return ErrorClassOther
+	}
+}
+
+// Fatal reports whether errors of this class leave the server session unusable,
+// so only a new session (Client.Reconnect) can continue the call.
+func (c ErrorClass) Fatal() bool {
+	return c == ErrorClassSessionExpired || c == ErrorClassInvalidState
+}
+
+// valid reports whether c is one of the defined error classes.
+func (c ErrorClass) valid() bool {
+	switch c {
+	case ErrorClassSessionExpired, ErrorClassInvalidState, ErrorClassServer, ErrorClassRateLimit,
+		ErrorClassInvalidRequest, ErrorClassClient, ErrorClassOther:
+		return true
+	}
+	return false
+}
+
+// OnErrorRecovery registers a callback invoked after the client reconnected in
+// response to an error event whose class is listed in Config.ReconnectOnErrors.
+// err is the result of the reconnect (nil on success).
+func (c *Client) OnErrorRecovery(fn func(e ErrorEvent, err error)) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	c.onErrorRecovery = fn
+}
+
+// maybeRecover reconnects in the background if e's class is configured for
+// recovery. Errors arriving while a recovery is running are ignored.
+func (c *Client) maybeRecover(e ErrorEvent) {
+	class := e.Class()
+	enabled := false
+	for _, cl := range c.cfg.ReconnectOnErrors {
+		if cl == class {
+			enabled = true
+			break
+		}
+	}
+	if !enabled || !c.recovering.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer c.recovering.Store(false)
+		select {
+		case <-c.closedCh:
+			return
+		default:
+		}
+
+		c.log("error_recovery", map[string]any{"class": string(class), "code": e.Error.Code})
+		timeout := c.cfg.DialTimeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err := c.Reconnect(ctx)
+		if err != nil {
+			c.logError("error_recovery_failed", map[string]any{"class": string(class), "err": err})
+		}
+
+		c.handlerMu.RLock()
+		if c.onErrorRecovery != nil {
+			c.onErrorRecovery(e, err)
+		}
+		c.handlerMu.RUnlock()
+	}()
+}
@@ -0,0 +1,137 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+func TestErrorEvent_Class(t *testing.T) {
+	tests := []struct {
+		name    string
+		details ErrorDetails
+		want    ErrorClass
+		fatal   bool
+	}{
+		{"session expired code", ErrorDetails{Type: ErrorTypeInvalidRequest, Code: "session_expired"}, ErrorClassSessionExpired, true},
+		{"session expired message", ErrorDetails{Type: ErrorTypeInvalidRequest, Message: "Your session hit the maximum duration. Session expired."}, ErrorClassSessionExpired, true},
+		{"invalid state", ErrorDetails{Type: ErrorTypeInvalidRequest, Code: "session_not_found"}, ErrorClassInvalidState, true},
+		{"rate limit", ErrorDetails{Type: ErrorTypeRateLimit}, ErrorClassRateLimit, false},
+		{"server", ErrorDetails{Type: ErrorTypeServer}, ErrorClassServer, false},
+		{"invalid request", ErrorDetails{Type: ErrorTypeInvalidRequest, Code: "invalid_value"}, ErrorClassInvalidRequest, false},
+		{"client", ErrorDetails{Type: ErrorTypeClient, Code: ErrorCodeMessageTooLarge}, ErrorClassClient, false},
+		{"other", ErrorDetails{Type: "mystery"}, ErrorClassOther, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := ErrorEvent{Type: "error", Error: tt.details}
+			if got := e.Class(); got != tt.want {
+				t.Errorf("Class() = %q, want %q", got, tt.want)
+			}
+			if got := e.Class().Fatal(); got != tt.fatal {
+				t.Errorf("Fatal() = %v, want %v", got, tt.fatal)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_ReconnectOnErrors(t *testing.T) {
+	cfg := CreateMockConfig("ws://localhost")
+	cfg.ReconnectOnErrors = []ErrorClass{ErrorClassSessionExpired, "bogus"}
+	err := ValidateConfig(cfg)
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "ReconnectOnErrors" {
+		t.Errorf("expected ReconnectOnErrors config error, got %v", err)
+	}
+}
+
+// expiringServer sends a session_expired error on the first connection only.
+func expiringServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var conns atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close(websocket.StatusNormalClosure, "")
+		if conns.Add(1) == 1 {
+			time.Sleep(50 * time.Millisecond) // Let the test register handlers
+			_ = conn.Write(r.Context(), websocket.MessageText,
+				[]byte(`{"type":"error","error":{"type":"invalid_request_error","code":"session_expired","message":"Session expired"}}`))
+		}
+		for {
+			if _, _, err := conn.Read(r.Context()); err != nil {
+				return
+			}
+		}
+	}))
+	return server, &conns
+}
+
+func TestClient_ReconnectOnErrors(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		server, conns := expiringServer(t)
+		defer server.Close()
+
+		cfg := CreateMockConfig("ws" + strings.TrimPrefix(server.URL, "http"))
+		cfg.ReconnectOnErrors = []ErrorClass{ErrorClassSessionExpired}
+		client, err := Dial(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer client.Close()
+
+		recovered := make(chan error, 1)
+		client.OnErrorRecovery(func(e ErrorEvent, err error) {
+			if e.Class() != ErrorClassSessionExpired {
+				t.Errorf("recovery for class %q", e.Class())
+			}
+			recovered <- err
+		})
+
+		select {
+		case err := <-recovered:
+			if err != nil {
+				t.Fatalf("recovery reconnect failed: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("client did not recover from session_expired")
+		}
+		if got := conns.Load(); got != 2 {
+			t.Errorf("server saw %d connections, want 2", got)
+		}
+		if got := client.Stats().Reconnects; got != 1 {
+			t.Errorf("Reconnects = %d, want 1", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server, conns := expiringServer(t)
+		defer server.Close()
+
+		client, err := Dial(context.Background(), CreateMockConfig("ws"+strings.TrimPrefix(server.URL, "http")))
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer client.Close()
+
+		gotError := make(chan struct{})
+		client.OnError(func(ErrorEvent) { close(gotError) })
+		select {
+		case <-gotError:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no error event")
+		}
+		time.Sleep(100 * time.Millisecond)
+		if got := conns.Load(); got != 1 {
+			t.Errorf("server saw %d connections, want 1", got)
+		}
+	})
+}
@@ -3,7 +3,9 @@ package azrealtime
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"strings"
 )
 
 // Common error variables
@@ -24,6 +26,14 @@ var (
 
 	// ErrInvalidEventData is returned when event data cannot be parsed.
 	ErrInvalidEventData = errors.New("azrealtime: invalid event data")
+
+	// ErrRateLimited is matched by RateLimitError, returned when a request is
+	// refused locally because a server-reported rate limit is exhausted.
+	ErrRateLimited = errors.New("azrealtime: rate limited")
+
+	// ErrResponseInterrupted is returned by ResponseTracker.Await and AwaitTag
+	// for a response that was still in progress when the client reconnected.
+	ErrResponseInterrupted = errors.New("azrealtime: response interrupted by reconnect")
 )
 
 // ConfigError represents a configuration validation error.
@@ -49,16 +59,32 @@ func (e *ConfigError) Is(target error) bool {
 // ConnectionError represents a WebSocket connection error.
 // It wraps underlying network errors with additional context.
 type ConnectionError struct {
-	URL       string // The WebSocket URL that failed to connect
-	Cause     error  // The underlying error
-	Operation string // The operation that failed (e.g., "dial", "handshake")
+	URL        string      // The WebSocket URL that failed to connect
+	Cause      error       // The underlying error
+	Operation  string      // The operation that failed (e.g., "dial", "handshake")
+	StatusCode int         // HTTP status of the handshake response, if one was received
+	Header     http.Header // Headers of the handshake response, if one was received
 }
 
 func (e *ConnectionError) Error() string {
+	msg := fmt.Sprintf("azrealtime: %s failed for %q", e.Operation, e.URL)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d", e.StatusCode)
+		if id := requestIDFromHeader(e.Header); id != "" {
+			msg += fmt.Sprintf(", request id %s", id)
+		}
+		msg += ")"
+	}
 	if e.Cause != nil {
-		return fmt.Sprintf("azrealtime: %s failed for %q: %v", e.Operation, e.URL, e.Cause)
+		msg += fmt.Sprintf(": %v", e.Cause)
 	}
-	return fmt.Sprintf("azrealtime: %s failed for %q", e.Operation, e.URL)
+	return msg
+}
+
+// RequestID returns the server request ID from the handshake response headers,
+// for correlating failed dials with Azure support tickets.
+func (e *ConnectionError) RequestID() string {
+	return requestIDFromHeader(e.Header)
 }
 
 // Unwrap returns the underlying error for error unwrapping.
@@ -116,6 +142,53 @@ func (e *EventError) Is(target error) bool {
 	return target == ErrInvalidEventData
 }
 
+// Validation error codes reported in ValidationError.Code.
+const (
+	ValidationCodeRequired     = "required"      // A required field is missing or empty
+	ValidationCodeInvalidValue = "invalid_value" // The value is not one of the accepted values
+	ValidationCodeOutOfRange   = "out_of_range"  // A numeric value is outside its allowed range
+	ValidationCodeTooLong      = "too_long"      // A string or collection exceeds its maximum size
+)
+
+// ValidationError describes a single invalid field in a request payload.
+// Field is a JSON path relative to the validated object (e.g. "turn_detection.threshold"
+// or "modalities[1]"), so UIs and APIs can map failures back to form fields.
+type ValidationError struct {
+	Field   string // JSON path of the invalid field
+	Code    string // Machine-readable failure code (one of the ValidationCode constants)
+	Message string // Human-readable description
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors collects every validation failure found in a payload.
+// ValidateSession and ValidateCreateResponseOptions return this type; use
+// errors.As to retrieve it from a returned error.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, ve := range e {
+		msgs[i] = ve.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// add appends a validation failure.
+func (e *ValidationErrors) add(field, code, format string, args ...any) {
+	*e = append(*e, ValidationError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns e as an error, or nil if no failures were recorded.
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
 // Helper functions for creating specific errors
 
 // NewConfigError creates a new configuration error.
@@ -158,7 +231,14 @@ func NewEventError(eventType string, rawData []byte, cause error) *EventError {
 
 // ValidateConfig performs comprehensive configuration validation.
 func ValidateConfig(cfg Config) error {
-	if cfg.ResourceEndpoint == "" {
+	switch cfg.Provider {
+	case "", ProviderAzure, ProviderOpenAI:
+	default:
+		return NewConfigError("Provider", string(cfg.Provider), "must be 'azure' or 'openai'")
+	}
+	openAI := cfg.Provider == ProviderOpenAI
+
+	if cfg.ResourceEndpoint == "" && !openAI {
 		return NewConfigError("ResourceEndpoint", "", "cannot be empty")
 	}
 
@@ -171,7 +251,7 @@ func ValidateConfig(cfg Config) error {
 		return NewConfigError("Deployment", "", "cannot be empty")
 	}
 
-	if cfg.APIVersion == "" {
+	if cfg.APIVersion == "" && !openAI {
 		return NewConfigError("APIVersion", "", "cannot be empty")
 	}
 
@@ -184,5 +264,47 @@ func ValidateConfig(cfg Config) error {
 		return NewConfigError("DialTimeout", cfg.DialTimeout.String(), "cannot be negative")
 	}
 
+	if cfg.SessionExpiryWarning < 0 {
+		return NewConfigError("SessionExpiryWarning", cfg.SessionExpiryWarning.String(), "cannot be negative")
+	}
+
+	if cfg.RateLimitMode < RateLimitIgnore || cfg.RateLimitMode > RateLimitFail {
+		return NewConfigError("RateLimitMode", fmt.Sprint(cfg.RateLimitMode), "must be RateLimitIgnore, RateLimitWait or RateLimitFail")
+	}
+
+	if cfg.AutoReconnect != nil {
+		if err := cfg.AutoReconnect.validate(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.ValidationMode < ValidationStrict || cfg.ValidationMode > ValidationOff {
+		return NewConfigError("ValidationMode", fmt.Sprint(cfg.ValidationMode), "must be ValidationStrict, ValidationWarn or ValidationOff")
+	}
+
+	for _, class := range cfg.ReconnectOnErrors {
+		if !class.valid() {
+			return NewConfigError("ReconnectOnErrors", string(class), "unknown error class")
+		}
+	}
+
+	if cfg.MaxMessageBytes < 0 {
+		return NewConfigError("MaxMessageBytes", fmt.Sprint(cfg.MaxMessageBytes), "cannot be negative")
+	}
+
+	if p := cfg.Pricing; p != nil && (p.TextInputPerMillion < 0 || p.TextOutputPerMillion < 0 ||
+		p.AudioInputPerMillion < 0 || p.AudioOutputPerMillion < 0 || p.CachedInputPerMillion < 0 ||
+		p.CachedAudioInputPerMillion < 0) {
+		return NewConfigError("Pricing", fmt.Sprintf("%+v", *p), "prices cannot be negative")
+	}
+
+	if len(cfg.CorrelationID) > maxMetadataValueLength {
+		return NewConfigError("CorrelationID", cfg.CorrelationID, fmt.Sprintf("cannot exceed %d characters", maxMetadataValueLength))
+	}
+
+	if cfg.StatsInterval < 0 {
+		return NewConfigError("StatsInterval", cfg.StatsInterval.String(), "cannot be negative")
+	}
+
 	return nil
 }
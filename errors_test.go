@@ -217,6 +217,39 @@ func TestValidateConfig(t *testing.T) {
 			expectError: true,
 			errorField:  "DialTimeout",
 		},
+		{
+			name: "negative session expiry warning",
+			config: Config{
+				ResourceEndpoint:     "https://test.openai.azure.com",
+				Deployment:           "test-deployment",
+				APIVersion:           "2025-04-01-preview",
+				Credential:           APIKey("test-key"),
+				SessionExpiryWarning: -1 * time.Second,
+			},
+			expectError: true,
+			errorField:  "SessionExpiryWarning",
+		},
+		{
+			name: "openai provider without endpoint or api version",
+			config: Config{
+				Provider:   ProviderOpenAI,
+				Deployment: "gpt-4o-realtime-preview",
+				Credential: APIKey("sk-test"),
+			},
+			expectError: false,
+		},
+		{
+			name: "unknown provider",
+			config: Config{
+				Provider:         "bedrock",
+				ResourceEndpoint: "https://test.openai.azure.com",
+				Deployment:       "test-deployment",
+				APIVersion:       "2025-04-01-preview",
+				Credential:       APIKey("test-key"),
+			},
+			expectError: true,
+			errorField:  "Provider",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,26 @@
+package azrealtime
+
+// gaEventAliases maps event names introduced by the GA realtime API onto the
+// preview names used by the typed callbacks.
+var gaEventAliases = map[string]string{
+	"response.output_text.delta":             "response.text.delta",
+	"response.output_text.done":              "response.text.done",
+	"response.output_audio.delta":            "response.audio.delta",
+	"response.output_audio.done":             "response.audio.done",
+	"response.output_audio_transcript.delta": "response.audio_transcript.delta",
+	"response.output_audio_transcript.done":  "response.audio_transcript.done",
+	"conversation.item.added":                "conversation.item.created",
+}
+
+// CanonicalEventType returns the preview-API name for a server event type, so
+// GA and preview events can be handled alike. Unknown and preview names are
+// returned unchanged.
+//
+// The client dispatches both spellings to the same On* callback; the Type field
+// of the delivered event keeps the name sent on the wire.
+func CanonicalEventType(eventType string) string {
+	if canonical, ok := gaEventAliases[eventType]; ok {
+		return canonical
+	}
+	return eventType
+}
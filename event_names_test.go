@@ -0,0 +1,47 @@
+package azrealtime
+
+import "testing"
+
+func TestCanonicalEventType(t *testing.T) {
+	tests := map[string]string{
+		"response.output_text.delta":  "response.text.delta",
+		"response.output_audio.delta": "response.audio.delta",
+		"conversation.item.added":     "conversation.item.created",
+		"response.text.delta":         "response.text.delta",
+		"session.created":             "session.created",
+		"unknown.event":               "unknown.event",
+	}
+	for in, want := range tests {
+		if got := CanonicalEventType(in); got != want {
+			t.Errorf("CanonicalEventType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestClient_DispatchesGAEventNames(t *testing.T) {
+	client := NewReplayClient(CreateMockConfig("ws://localhost"))
+
+	var text ResponseTextDelta
+	var audio ResponseAudioDelta
+	var item ConversationItemCreated
+	client.OnResponseTextDelta(func(e ResponseTextDelta) { text = e })
+	client.OnResponseAudioDelta(func(e ResponseAudioDelta) { audio = e })
+	client.OnConversationItemCreated(func(e ConversationItemCreated) { item = e })
+
+	client.handleMessage([]byte(`{"type":"response.output_text.delta","response_id":"resp_1","delta":"Hi"}`))
+	client.handleMessage([]byte(`{"type":"response.output_audio.delta","response_id":"resp_1","delta":"AAAA"}`))
+	client.handleMessage([]byte(`{"type":"conversation.item.added","item":{"id":"item_1","type":"message"}}`))
+
+	if text.Delta != "Hi" || text.Type != "response.output_text.delta" {
+		t.Errorf("unexpected text delta: %+v", text)
+	}
+	if audio.DeltaBase64 != "AAAA" {
+		t.Errorf("unexpected audio delta: %+v", audio)
+	}
+	if item.Item.ID != "item_1" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	if got := client.ConversationItemIDs(); len(got) != 1 || got[0] != "item_1" {
+		t.Errorf("expected GA item to be tracked, got %v", got)
+	}
+}
@@ -1,22 +1,74 @@
 package azrealtime
 
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
 // envelope is used for initial JSON parsing to determine the event type
 // before unmarshaling into the specific event struct.
 type envelope struct {
 	Type string `json:"type"`
 }
 
+// EventDirection tells whether a raw event was received from or sent to the server.
+type EventDirection string
+
+const (
+	DirectionInbound  EventDirection = "inbound"  // Event received from the server
+	DirectionOutbound EventDirection = "outbound" // Event sent by the client
+)
+
 // ErrorEvent represents an error received from the Azure OpenAI Realtime API.
 // This includes both API-level errors (authentication, rate limits) and
 // conversation-level errors (invalid requests, content policy violations).
 type ErrorEvent struct {
-	Type  string `json:"type"` // Always "error"
-	Error struct {
-		Type    string `json:"type,omitempty"`    // Error category (e.g., "invalid_request_error")
-		Message string `json:"message,omitempty"` // Human-readable error description
-		Role    string `json:"role,omitempty"`    // Role associated with error (if applicable)
-		Content string `json:"content,omitempty"` // Error content or context
-	} `json:"error"`
+	Type    string       `json:"type"`               // Always "error"
+	EventID string       `json:"event_id,omitempty"` // Unique identifier for this event
+	Error   ErrorDetails `json:"error"`              // Details of the error
+
+	// Cause is the Go error behind a client_error raised locally, such as a
+	// *StreamIntegrityError, or nil. It is never sent or received.
+	Cause error `json:"-"`
+}
+
+// ErrorDetails describes an API error.
+type ErrorDetails struct {
+	Type    string `json:"type,omitempty"`     // Error category (e.g., "invalid_request_error")
+	Code    string `json:"code,omitempty"`     // Machine-readable error code (e.g., "rate_limit_exceeded")
+	Message string `json:"message,omitempty"`  // Human-readable error description
+	Param   string `json:"param,omitempty"`    // Request parameter related to the error, if any
+	EventID string `json:"event_id,omitempty"` // ID of the client event that caused the error, if any
+	Role    string `json:"role,omitempty"`     // Role associated with error (if applicable)
+	Content string `json:"content,omitempty"`  // Error content or context
+}
+
+// Error categories reported in ErrorDetails.Type.
+const (
+	ErrorTypeInvalidRequest = "invalid_request_error"
+	ErrorTypeRateLimit      = "rate_limit_error"
+	ErrorTypeServer         = "server_error"
+	ErrorTypeClient         = "client_error" // Synthesized by the client, not sent by the server
+)
+
+// ErrorCodeMessageTooLarge is the code of the client_error reported when an
+// inbound message exceeds Config.MaxMessageBytes and is dropped.
+const ErrorCodeMessageTooLarge = "message_too_large"
+
+// IsRateLimited reports whether the error was caused by exceeding a rate limit.
+func (e ErrorEvent) IsRateLimited() bool {
+	return e.Error.Type == ErrorTypeRateLimit || e.Error.Code == "rate_limit_exceeded"
+}
+
+// IsInvalidRequest reports whether the server rejected a client event as malformed or invalid.
+func (e ErrorEvent) IsInvalidRequest() bool {
+	return e.Error.Type == ErrorTypeInvalidRequest
+}
+
+// IsServerError reports whether the error originated on the server and may succeed on retry.
+func (e ErrorEvent) IsServerError() bool {
+	return e.Error.Type == ErrorTypeServer
 }
 
 // SessionCreated is sent by the server when a new session is established.
@@ -36,21 +88,62 @@ type SessionCreated struct {
 // SessionUpdated is sent when session configuration is modified.
 // This occurs after sending a session.update event.
 type SessionUpdated struct {
-	Type    string `json:"type"`               // Always "session.updated"
-	EventID string `json:"event_id,omitempty"` // Event identifier (may be empty)
-	Session any    `json:"session"`            // Updated session configuration (dynamic structure)
+	Type    string          `json:"type"`               // Always "session.updated"
+	EventID string          `json:"event_id,omitempty"` // Event identifier (may be empty)
+	Session SessionResource `json:"session"`            // Effective session configuration after the update
 }
 
 // RateLimitsUpdated provides current rate limiting information.
 // This helps clients implement proper rate limiting and backoff strategies.
 type RateLimitsUpdated struct {
-	Type       string `json:"type"` // Always "rate_limits.updated"
-	RateLimits []struct {
-		Name         string `json:"name"`          // Rate limit name (e.g., "requests", "tokens")
-		Limit        int    `json:"limit"`         // Maximum allowed per time window
-		Remaining    int    `json:"remaining"`     // Remaining quota in current window
-		ResetSeconds int    `json:"reset_seconds"` // Seconds until quota resets
-	} `json:"rate_limits"`
+	Type       string      `json:"type"`        // Always "rate_limits.updated"
+	RateLimits []RateLimit `json:"rate_limits"` // Current state of each rate limit
+}
+
+// RateLimit describes the state of a single rate limit.
+type RateLimit struct {
+	Name         string        `json:"name"`          // Rate limit name (e.g., "requests", "tokens")
+	Limit        int           `json:"limit"`         // Maximum allowed per time window
+	Remaining    int           `json:"remaining"`     // Remaining quota in current window
+	ResetSeconds int           `json:"reset_seconds"` // Seconds until quota resets, rounded up
+	Reset        time.Duration `json:"-"`             // Time until quota resets, from the fractional reset_seconds
+}
+
+// rateLimitJSON is the wire form of RateLimit, whose reset_seconds may be fractional.
+type rateLimitJSON struct {
+	Name         string  `json:"name"`
+	Limit        int     `json:"limit"`
+	Remaining    int     `json:"remaining"`
+	ResetSeconds float64 `json:"reset_seconds"`
+}
+
+// UnmarshalJSON fills both ResetSeconds and Reset from reset_seconds.
+func (r *RateLimit) UnmarshalJSON(data []byte) error {
+	var w rateLimitJSON
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	*r = RateLimit{
+		Name:         w.Name,
+		Limit:        w.Limit,
+		Remaining:    w.Remaining,
+		ResetSeconds: int(math.Ceil(w.ResetSeconds)),
+		Reset:        time.Duration(w.ResetSeconds * float64(time.Second)),
+	}
+	return nil
+}
+
+// MarshalJSON encodes reset_seconds from Reset when set, else from ResetSeconds.
+func (r RateLimit) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rateLimitJSON{Name: r.Name, Limit: r.Limit, Remaining: r.Remaining, ResetSeconds: r.resetIn().Seconds()})
+}
+
+// resetIn returns the time until the quota resets.
+func (r RateLimit) resetIn() time.Duration {
+	if r.Reset > 0 {
+		return r.Reset
+	}
+	return time.Duration(r.ResetSeconds) * time.Second
 }
 
 // ResponseTextDelta contains incremental text content from the assistant.
@@ -145,6 +238,17 @@ type ConversationItemInputAudioTranscriptionCompleted struct {
 	Transcript   string `json:"transcript"`    // The transcribed text
 }
 
+// ConversationItemInputAudioTranscriptionDelta carries part of the transcript
+// of user audio while it is being transcribed. Only the gpt-4o transcription
+// models stream deltas; whisper-1 sends the completed event alone.
+type ConversationItemInputAudioTranscriptionDelta struct {
+	Type         string `json:"type"`          // Always "conversation.item.input_audio_transcription.delta"
+	EventID      string `json:"event_id"`      // Unique identifier for this event
+	ItemID       string `json:"item_id"`       // The ID of the user message item
+	ContentIndex int    `json:"content_index"` // The index of the content part containing the audio
+	Delta        string `json:"delta"`         // The transcript text to append
+}
+
 // ConversationItemInputAudioTranscriptionFailed indicates that transcription of user audio failed.
 type ConversationItemInputAudioTranscriptionFailed struct {
 	Type         string `json:"type"`          // Always "conversation.item.input_audio_transcription.failed"
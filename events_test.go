@@ -63,12 +63,7 @@ func TestErrorEvent_Unmarshal(t *testing.T) {
 
 	expected := ErrorEvent{
 		Type: "error",
-		Error: struct {
-			Type    string `json:"type,omitempty"`
-			Message string `json:"message,omitempty"`
-			Role    string `json:"role,omitempty"`
-			Content string `json:"content,omitempty"`
-		}{
+		Error: ErrorDetails{
 			Type:    "invalid_request_error",
 			Message: "Invalid request format",
 			Role:    "user",
@@ -81,6 +76,63 @@ func TestErrorEvent_Unmarshal(t *testing.T) {
 	}
 }
 
+func TestErrorEvent_Details(t *testing.T) {
+	jsonData := `{
+		"type": "error",
+		"event_id": "evt_err_1",
+		"error": {
+			"type": "invalid_request_error",
+			"code": "invalid_value",
+			"message": "Invalid value for 'voice'",
+			"param": "session.voice",
+			"event_id": "evt_client_7"
+		}
+	}`
+
+	var event ErrorEvent
+	if err := json.Unmarshal([]byte(jsonData), &event); err != nil {
+		t.Fatalf("failed to unmarshal ErrorEvent: %v", err)
+	}
+	if event.EventID != "evt_err_1" || event.Error.Code != "invalid_value" ||
+		event.Error.Param != "session.voice" || event.Error.EventID != "evt_client_7" {
+		t.Errorf("unexpected error details: %+v", event)
+	}
+	if !event.IsInvalidRequest() || event.IsRateLimited() || event.IsServerError() {
+		t.Errorf("unexpected category for %+v", event.Error)
+	}
+}
+
+func TestErrorEvent_Categories(t *testing.T) {
+	tests := []struct {
+		name           string
+		details        ErrorDetails
+		rateLimited    bool
+		invalidRequest bool
+		serverError    bool
+	}{
+		{"rate limit type", ErrorDetails{Type: ErrorTypeRateLimit}, true, false, false},
+		{"rate limit code", ErrorDetails{Type: "requests", Code: "rate_limit_exceeded"}, true, false, false},
+		{"invalid request", ErrorDetails{Type: ErrorTypeInvalidRequest}, false, true, false},
+		{"server error", ErrorDetails{Type: ErrorTypeServer}, false, false, true},
+		{"unknown", ErrorDetails{Type: "other"}, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := ErrorEvent{Type: "error", Error: tt.details}
+			if e.IsRateLimited() != tt.rateLimited {
+				t.Errorf("IsRateLimited() = %v, want %v", e.IsRateLimited(), tt.rateLimited)
+			}
+			if e.IsInvalidRequest() != tt.invalidRequest {
+				t.Errorf("IsInvalidRequest() = %v, want %v", e.IsInvalidRequest(), tt.invalidRequest)
+			}
+			if e.IsServerError() != tt.serverError {
+				t.Errorf("IsServerError() = %v, want %v", e.IsServerError(), tt.serverError)
+			}
+		})
+	}
+}
+
 func TestSessionCreated_Unmarshal(t *testing.T) {
 	jsonData := `{
 		"type": "session.created",
@@ -110,9 +110,9 @@ func testWithAudioFile(ctx context.Context) error {
 	log.Printf("Decoded PCM length: %d bytes (%.2f seconds)",
 		len(pcmData), float64(len(pcmData))/(2.0*SampleRate))
 
-	// Send audio and let server VAD handle detection and response
-	if err := client.AppendPCM16(ctx, pcmData); err != nil {
-		return fmt.Errorf("failed to append audio: %w", err)
+	// Stream audio at real-time pace and let server VAD handle detection and response
+	if err := client.StreamPCM16(ctx, bytes.NewReader(pcmData), azrealtime.StreamOptions{}); err != nil {
+		return fmt.Errorf("failed to stream audio: %w", err)
 	}
 	log.Println("Audio sent. Waiting for server VAD to detect speech...")
 
@@ -0,0 +1,152 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FallbackStrategy selects the order in which DialWithFallback tries its targets.
+type FallbackStrategy int
+
+const (
+	// FallbackInOrder tries the targets in the order given (the default),
+	// e.g. a primary region followed by its pair.
+	FallbackInOrder FallbackStrategy = iota
+	// FallbackLowestLatency probes every target's endpoint with a TCP connect
+	// and tries them from fastest to slowest. Unreachable targets go last.
+	FallbackLowestLatency
+)
+
+// DefaultFallbackProbeTimeout bounds the latency probes of
+// FallbackLowestLatency when FallbackPolicy.ProbeTimeout is zero.
+const DefaultFallbackProbeTimeout = 2 * time.Second
+
+// FallbackPolicy configures DialWithFallback.
+type FallbackPolicy struct {
+	// Strategy orders the targets. Default: FallbackInOrder.
+	Strategy FallbackStrategy
+
+	// AttemptTimeout, if positive, bounds each dial attempt, so an unresponsive
+	// region does not consume the whole context deadline. A target's own
+	// Config.DialTimeout still applies.
+	AttemptTimeout time.Duration
+
+	// ProbeTimeout bounds the latency probes of FallbackLowestLatency.
+	// If zero, DefaultFallbackProbeTimeout is used.
+	ProbeTimeout time.Duration
+}
+
+// DialWithFallback dials the targets one after another, e.g. deployments in
+// paired Azure regions, and returns the first client that connects together
+// with the index of its Config in targets. An invalid Config counts as a
+// failed attempt. If every target fails, the error joins the individual
+// failures.
+//
+// Only the initial connection fails over: Reconnect and Config.AutoReconnect
+// keep using the selected target.
+func DialWithFallback(ctx context.Context, targets []Config, policy FallbackPolicy) (*Client, int, error) {
+	if ctx == nil {
+		return nil, -1, errors.New("azrealtime: context cannot be nil")
+	}
+	if len(targets) == 0 {
+		return nil, -1, NewConfigError("targets", "", "at least one target is required")
+	}
+
+	order := make([]int, len(targets))
+	for i := range order {
+		order[i] = i
+	}
+	if policy.Strategy == FallbackLowestLatency {
+		order = orderByLatency(ctx, targets, policy.ProbeTimeout)
+	}
+
+	var errs []error
+	for _, i := range order {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		dialCtx, cancel := ctx, context.CancelFunc(func() {})
+		if policy.AttemptTimeout > 0 {
+			dialCtx, cancel = context.WithTimeout(ctx, policy.AttemptTimeout)
+		}
+		c, err := Dial(dialCtx, targets[i])
+		cancel()
+		if err == nil {
+			c.log("dial_fallback", map[string]any{"target": i, "failed_attempts": len(errs)})
+			return c, i, nil
+		}
+		errs = append(errs, fmt.Errorf("target %d (%s): %w", i, targets[i].ResourceEndpoint, err))
+	}
+	return nil, -1, fmt.Errorf("azrealtime: all %d dial targets failed: %w", len(targets), errors.Join(errs...))
+}
+
+// orderByLatency probes all targets concurrently and returns their indexes
+// sorted by connect time. Targets whose probe fails keep their relative order
+// at the end.
+func orderByLatency(ctx context.Context, targets []Config, timeout time.Duration) []int {
+	if timeout <= 0 {
+		timeout = DefaultFallbackProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	latency := make([]time.Duration, len(targets))
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d, err := probeLatency(ctx, targets[i])
+			if err != nil {
+				d = -1
+			}
+			latency[i] = d
+		}(i)
+	}
+	wg.Wait()
+
+	order := make([]int, len(targets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		la, lb := latency[order[a]], latency[order[b]]
+		if la < 0 || lb < 0 {
+			return lb < 0 && la >= 0
+		}
+		return la < lb
+	})
+	return order
+}
+
+// probeLatency measures a TCP connect to cfg's realtime endpoint.
+func probeLatency(ctx context.Context, cfg Config) (time.Duration, error) {
+	u, err := realtimeURL(cfg)
+	if err != nil {
+		return 0, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "wss" {
+			port = "443"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	var d net.Dialer
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(start)
+	_ = conn.Close()
+	return elapsed, nil
+}
@@ -0,0 +1,92 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDialWithFallback_InOrder(t *testing.T) {
+	down := NewMockServer(t)
+	defer down.Close()
+	down.Reject.Store(true)
+	up := NewMockServer(t)
+	defer up.Close()
+
+	targets := []Config{{}, CreateMockConfig(down.URL()), CreateMockConfig(up.URL())}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, used, err := DialWithFallback(ctx, targets, FallbackPolicy{AttemptTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("DialWithFallback: %v", err)
+	}
+	defer client.Close()
+	if used != 2 {
+		t.Errorf("used target %d, want 2", used)
+	}
+	if got := client.HandshakeInfo().URL; !strings.HasPrefix(got, up.URL()) {
+		t.Errorf("connected to %s, want %s", got, up.URL())
+	}
+
+	// A healthy first target is used without trying the others
+	client2, used, err := DialWithFallback(ctx, targets[2:], FallbackPolicy{})
+	if err != nil || used != 0 {
+		t.Fatalf("DialWithFallback = %d, %v", used, err)
+	}
+	client2.Close()
+}
+
+func TestDialWithFallback_AllFail(t *testing.T) {
+	down := NewMockServer(t)
+	defer down.Close()
+	down.Reject.Store(true)
+
+	targets := []Config{CreateMockConfig(down.URL()), {}}
+	client, used, err := DialWithFallback(context.Background(), targets, FallbackPolicy{})
+	if err == nil {
+		client.Close()
+		t.Fatal("expected error")
+	}
+	if used != -1 || !strings.Contains(err.Error(), "all 2 dial targets failed") {
+		t.Errorf("DialWithFallback = %d, %v", used, err)
+	}
+	var connErr *ConnectionError
+	var cfgErr *ConfigError
+	if !errors.As(err, &connErr) || !errors.As(err, &cfgErr) {
+		t.Errorf("error does not wrap each failure: %v", err)
+	}
+
+	if _, _, err := DialWithFallback(context.Background(), nil, FallbackPolicy{}); err == nil {
+		t.Error("expected error for no targets")
+	}
+}
+
+func TestDialWithFallback_LowestLatency(t *testing.T) {
+	// An address nothing listens on
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "ws://" + l.Addr().String() + "/openai/realtime"
+	l.Close()
+
+	up := NewMockServer(t)
+	defer up.Close()
+
+	targets := []Config{CreateMockConfig(closed), CreateMockConfig(up.URL())}
+	if order := orderByLatency(context.Background(), targets, time.Second); order[0] != 1 || order[1] != 0 {
+		t.Errorf("order = %v, want the reachable target first", order)
+	}
+
+	client, used, err := DialWithFallback(context.Background(), targets, FallbackPolicy{Strategy: FallbackLowestLatency})
+	if err != nil {
+		t.Fatalf("DialWithFallback: %v", err)
+	}
+	defer client.Close()
+	if used != 1 {
+		t.Errorf("used target %d, want 1", used)
+	}
+}
@@ -0,0 +1,140 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// G711SampleRate is the sample rate of G.711 audio (g711_ulaw and g711_alaw).
+const G711SampleRate = 8000
+
+// G.711 codec helpers, for sessions using the "g711_ulaw" or "g711_alaw" audio
+// formats (typically telephony). G.711 carries one byte per sample at 8 kHz;
+// the converters map between that and 16-bit little-endian PCM at the same
+// rate. Use ResamplePCM16 to move between 8 kHz and DefaultSampleRate.
+
+const (
+	ulawBias = 0x84
+	ulawClip = 32635
+)
+
+// G711UlawToPCM16 decodes µ-law bytes to PCM16 little-endian samples.
+func G711UlawToPCM16(ulaw []byte) []byte {
+	out := make([]byte, 2*len(ulaw))
+	for i, b := range ulaw {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(ulawDecode(b)))
+	}
+	return out
+}
+
+// PCM16ToG711Ulaw encodes PCM16 little-endian samples as µ-law. A trailing odd
+// byte is ignored.
+func PCM16ToG711Ulaw(pcm []byte) []byte {
+	out := make([]byte, len(pcm)/2)
+	for i := range out {
+		out[i] = ulawEncode(int16(binary.LittleEndian.Uint16(pcm[2*i:])))
+	}
+	return out
+}
+
+// G711AlawToPCM16 decodes A-law bytes to PCM16 little-endian samples.
+func G711AlawToPCM16(alaw []byte) []byte {
+	out := make([]byte, 2*len(alaw))
+	for i, b := range alaw {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(alawDecode(b)))
+	}
+	return out
+}
+
+// PCM16ToG711Alaw encodes PCM16 little-endian samples as A-law. A trailing odd
+// byte is ignored.
+func PCM16ToG711Alaw(pcm []byte) []byte {
+	out := make([]byte, len(pcm)/2)
+	for i := range out {
+		out[i] = alawEncode(int16(binary.LittleEndian.Uint16(pcm[2*i:])))
+	}
+	return out
+}
+
+func ulawEncode(s int16) byte {
+	v := int(s)
+	sign := 0
+	if v < 0 {
+		v = -v
+		sign = 0x80
+	}
+	if v > ulawClip {
+		v = ulawClip
+	}
+	v += ulawBias
+	exp := 7
+	for mask := 0x4000; v&mask == 0 && exp > 0; mask >>= 1 {
+		exp--
+	}
+	mantissa := (v >> (exp + 3)) & 0x0F
+	return ^byte(sign | exp<<4 | mantissa)
+}
+
+func ulawDecode(b byte) int16 {
+	b = ^b
+	exp := int(b>>4) & 0x07
+	v := ((int(b&0x0F) << 3) + ulawBias) << exp
+	v -= ulawBias
+	if b&0x80 != 0 {
+		return int16(-v)
+	}
+	return int16(v)
+}
+
+func alawEncode(s int16) byte {
+	v := int(s) >> 3 // A-law works on 13-bit magnitudes
+	sign := 0x80
+	if v < 0 {
+		v = -v - 1
+		sign = 0
+	}
+	var b int
+	if v < 32 {
+		b = v >> 1
+	} else {
+		exp := 1
+		for t := v >> 5; t > 1; t >>= 1 {
+			exp++
+		}
+		b = exp<<4 | (v>>exp)&0x0F
+	}
+	return byte(sign|b) ^ 0x55
+}
+
+func alawDecode(b byte) int16 {
+	b ^= 0x55
+	exp := int(b>>4) & 0x07
+	v := int(b&0x0F)<<4 + 8
+	if exp > 0 {
+		v = (v + 0x100) << (exp - 1)
+	}
+	if b&0x80 == 0 {
+		return int16(-v)
+	}
+	return int16(v)
+}
+
+// AppendG711 sends G.711 encoded audio (µ-law or A-law) to the input buffer
+// without transcoding. The session's InputAudioFormat must be "g711_ulaw" or
+// "g711_alaw" to match.
+func (c *Client) AppendG711(ctx context.Context, g711 []byte) error {
+	if ctx == nil {
+		return NewSendError("input_audio_buffer.append", "", errors.New("context cannot be nil"))
+	}
+	if len(g711) == 0 {
+		return nil
+	}
+	if len(g711) > MaxAppendBytes && !c.cfg.AutoChunkAudio {
+		return NewSendError("input_audio_buffer.append", "",
+			fmt.Errorf("G.711 data too large (%d bytes), maximum is %d bytes", len(g711), MaxAppendBytes))
+	}
+	// Stats count audio as DefaultSampleRate PCM16: one 8 kHz byte is 2*3 such bytes
+	return c.appendAudio(ctx, g711, len(g711)*2*DefaultSampleRate/G711SampleRate)
+}
@@ -0,0 +1,116 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestG711_KnownValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample int16
+		ulaw   byte
+		alaw   byte
+	}{
+		{"zero", 0, 0xFF, 0xD5},
+		{"max", 32767, 0x80, 0xAA},
+		{"min", -32768, 0x00, 0x2A},
+		{"small positive", 100, 0xF2, 0xD3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PCM16ToG711Ulaw(pcm16(tt.sample)); got[0] != tt.ulaw {
+				t.Errorf("µ-law(%d) = %#x, want %#x", tt.sample, got[0], tt.ulaw)
+			}
+			if got := PCM16ToG711Alaw(pcm16(tt.sample)); got[0] != tt.alaw {
+				t.Errorf("A-law(%d) = %#x, want %#x", tt.sample, got[0], tt.alaw)
+			}
+		})
+	}
+}
+
+func TestG711_RoundTrip(t *testing.T) {
+	codecs := []struct {
+		name   string
+		encode func([]byte) []byte
+		decode func([]byte) []byte
+	}{
+		{"ulaw", PCM16ToG711Ulaw, G711UlawToPCM16},
+		{"alaw", PCM16ToG711Alaw, G711AlawToPCM16},
+	}
+	for _, c := range codecs {
+		t.Run(c.name, func(t *testing.T) {
+			var samples []int16
+			for v := -32768; v <= 32767; v += 97 {
+				samples = append(samples, int16(v))
+			}
+			in := pcm16(samples...)
+			enc := c.encode(in)
+			if len(enc) != len(samples) {
+				t.Fatalf("encoded %d samples to %d bytes", len(samples), len(enc))
+			}
+			out := c.decode(enc)
+			if len(out) != len(in) {
+				t.Fatalf("decoded length %d, want %d", len(out), len(in))
+			}
+			for i, s := range samples {
+				got := int16(binary.LittleEndian.Uint16(out[2*i:]))
+				// Logarithmic quantization: the error grows with magnitude, within ~1/16
+				diff := int(got) - int(s)
+				if diff < 0 {
+					diff = -diff
+				}
+				mag := int(s)
+				if mag < 0 {
+					mag = -mag
+				}
+				if limit := mag/16 + 140; diff > limit {
+					t.Fatalf("sample %d decoded to %d (error %d > %d)", s, got, diff, limit)
+				}
+			}
+
+			// Every code decodes to a value that encodes back to the same code
+			for b := 0; b < 256; b++ {
+				dec := c.decode([]byte{byte(b)})
+				re := c.encode(dec)[0]
+				if c.name == "ulaw" && b == 0x7F {
+					continue // Negative zero encodes as positive zero
+				}
+				if re != byte(b) {
+					t.Errorf("code %#x -> %d -> %#x", b, int16(binary.LittleEndian.Uint16(dec)), re)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_AppendG711(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.AppendG711(ctx, nil); err != nil {
+		t.Errorf("empty append: %v", err)
+	}
+	if err := client.AppendG711(ctx, make([]byte, 2<<20)); err == nil {
+		t.Error("expected error for oversized chunk")
+	}
+
+	// 200ms at 8 kHz
+	if err := client.AppendG711(ctx, make([]byte, G711SampleRate/5)); err != nil {
+		t.Fatalf("AppendG711: %v", err)
+	}
+	if got := client.Stats().AudioInSeconds; got < 0.199 || got > 0.201 {
+		t.Errorf("AudioInSeconds = %v, want 0.2", got)
+	}
+}
@@ -0,0 +1,427 @@
+// Package gateway proxies browser WebSocket connections to the realtime API,
+// so browser apps can use the realtime protocol over WebSocket while the API
+// key or Entra ID token stays on the server. Each browser connection is
+// authenticated, then paired with its own upstream connection opened with the
+// server's credential; events are relayed unchanged in both directions.
+//
+//	gw, err := gateway.New(gateway.Config{
+//		Upstream: azrealtime.Config{
+//			ResourceEndpoint: endpoint,
+//			Deployment:       deployment,
+//			APIVersion:       "2025-04-01-preview",
+//			Credential:       azrealtime.APIKey(apiKey),
+//		},
+//		Authenticate: auth, // e.g. an issuer.Authenticator
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	mux.Handle("/realtime", gw)
+//
+// Browsers cannot set headers on a WebSocket handshake, so they pass their
+// bearer token as the access_token query parameter:
+//
+//	new WebSocket(`wss://app.example.com/realtime?access_token=${token}`)
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/enesunal-m/azrealtime"
+	"nhooyr.io/websocket"
+)
+
+// TokenQueryParam is the query parameter a browser may carry its bearer
+// token in.
+const TokenQueryParam = "access_token"
+
+// ErrNoAuthenticator is returned by New for a Config with neither
+// Authenticate nor AllowAnonymous.
+var ErrNoAuthenticator = errors.New("gateway: Authenticate is required unless AllowAnonymous is set")
+
+// DefaultDialTimeout bounds the upstream handshake when Upstream.DialTimeout
+// is zero.
+const DefaultDialTimeout = 30 * time.Second
+
+// Config configures New.
+type Config struct {
+	// Upstream describes the connection opened for each browser: endpoint,
+	// deployment, API version, credential and handshake headers. Its client
+	// options (logging, tracking, ...) are not used.
+	Upstream azrealtime.Config
+
+	// Credential, if set, returns the credential for each new upstream
+	// connection in place of Upstream.Credential, e.g. a fresh Entra ID token
+	// or a rotated API key.
+	Credential func(ctx context.Context) (azrealtime.Credential, error)
+
+	// Authenticate verifies the browser and returns its subject; errors
+	// reject the handshake with 401. A token in the access_token query
+	// parameter is moved to the Authorization header first. It is required
+	// unless AllowAnonymous is set.
+	Authenticate func(r *http.Request) (subject string, err error)
+
+	// AllowAnonymous lets every caller use the server's credential when
+	// Authenticate is nil, e.g. on a private network.
+	AllowAnonymous bool
+
+	// AllowedOrigins lists the origins (such as "https://app.example.com")
+	// browsers may connect from, besides the gateway's own; "*" allows any.
+	// Handshakes without an Origin header are rejected unless "*" is listed,
+	// so other clients must send one too.
+	AllowedOrigins []string
+
+	// MaxSessionsPerSubject caps the concurrent connections of each
+	// authenticated subject; zero is unlimited.
+	MaxSessionsPerSubject int
+
+	// Session, if set, is sent as session.update as soon as the upstream
+	// connects, ahead of any browser event.
+	Session *azrealtime.Session
+
+	// Filter, if set, sees each browser event before it is forwarded. It may
+	// return a rewritten event, nil to drop the event silently, or an error
+	// to drop it and send the browser an error event with the error's
+	// message. Browsers that send binary messages are disconnected with
+	// StatusUnsupportedData.
+	Filter func(subject, eventType string, data []byte) ([]byte, error)
+
+	// OnEvent is called with every event passing through. Outbound events
+	// come from the browser; inbound events from the service.
+	OnEvent func(subject string, dir azrealtime.EventDirection, eventType string, data []byte)
+
+	// OnSession is called when a browser is connected to the service, and
+	// OnSessionEnd when either side closes; err is nil for a normal closure.
+	OnSession    func(subject string, info azrealtime.HandshakeInfo)
+	OnSessionEnd func(subject string, err error)
+
+	// OnError is called when a browser cannot be connected to the service.
+	OnError func(error)
+}
+
+// Gateway is an http.Handler that proxies realtime WebSocket connections.
+type Gateway struct {
+	cfg Config
+
+	mu       sync.Mutex
+	sessions map[*session]struct{}
+	subjects map[string]int // Live sessions per subject
+	closed   bool
+}
+
+// New returns a Gateway with no sessions. It returns ErrNoAuthenticator if
+// cfg would let anyone in without AllowAnonymous.
+func New(cfg Config) (*Gateway, error) {
+	if cfg.Authenticate == nil && !cfg.AllowAnonymous {
+		return nil, ErrNoAuthenticator
+	}
+	if cfg.Upstream.DialTimeout <= 0 {
+		cfg.Upstream.DialTimeout = DefaultDialTimeout
+	}
+	if cfg.Upstream.MaxMessageBytes <= 0 {
+		cfg.Upstream.MaxMessageBytes = azrealtime.DefaultMaxMessageBytes
+	}
+	return &Gateway{cfg: cfg, sessions: make(map[*session]struct{}), subjects: make(map[string]int)}, nil
+}
+
+// Len returns the number of live sessions.
+func (g *Gateway) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.sessions)
+}
+
+// Close closes every live session and rejects new ones.
+func (g *Gateway) Close() error {
+	g.mu.Lock()
+	g.closed = true
+	sessions := make([]*session, 0, len(g.sessions))
+	for s := range g.sessions {
+		sessions = append(sessions, s)
+	}
+	g.mu.Unlock()
+	for _, s := range sessions {
+		s.close(websocket.StatusGoingAway, "gateway closed")
+	}
+	return nil
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !g.allowOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	if tok := r.URL.Query().Get(TokenQueryParam); tok != "" && r.Header.Get("Authorization") == "" {
+		r.Header.Set("Authorization", "Bearer "+tok)
+	}
+	var subject string
+	if g.cfg.Authenticate != nil {
+		var err error
+		if subject, err = g.cfg.Authenticate(r); err != nil {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+	}
+	if !g.reserve(subject) {
+		http.Error(w, "too many sessions", http.StatusTooManyRequests)
+		return
+	}
+	defer g.unreserve(subject)
+
+	// Dial first so a failure is reported to the browser as an HTTP status
+	upstream, info, err := g.dial(r.Context())
+	if err != nil {
+		g.reportError(err)
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+		return
+	}
+	browser, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: g.originPatterns()})
+	if err != nil {
+		_ = upstream.Close(websocket.StatusGoingAway, "browser handshake failed")
+		g.reportError(fmt.Errorf("gateway: accept: %w", err))
+		return
+	}
+	browser.SetReadLimit(g.cfg.Upstream.MaxMessageBytes)
+	upstream.SetReadLimit(g.cfg.Upstream.MaxMessageBytes)
+
+	s := &session{gw: g, subject: subject, browser: browser, upstream: upstream}
+	if !g.add(s) {
+		s.close(websocket.StatusGoingAway, "gateway closed")
+		return
+	}
+	defer g.remove(s)
+	if g.cfg.OnSession != nil {
+		g.cfg.OnSession(subject, info)
+	}
+	err = s.run(r.Context())
+	if g.cfg.OnSessionEnd != nil {
+		g.cfg.OnSessionEnd(subject, err)
+	}
+}
+
+// dial opens the upstream connection with the current credential.
+func (g *Gateway) dial(ctx context.Context) (*websocket.Conn, azrealtime.HandshakeInfo, error) {
+	cfg := g.cfg.Upstream
+	ctx, cancel := context.WithTimeout(ctx, cfg.DialTimeout)
+	defer cancel()
+	if g.cfg.Credential != nil {
+		cred, err := g.cfg.Credential(ctx)
+		if err != nil {
+			return nil, azrealtime.HandshakeInfo{}, fmt.Errorf("gateway: credential: %w", err)
+		}
+		cfg.Credential = cred
+	}
+	u, h, err := azrealtime.HandshakeRequest(cfg)
+	if err != nil {
+		return nil, azrealtime.HandshakeInfo{}, fmt.Errorf("gateway: %w", err)
+	}
+	conn, resp, err := websocket.Dial(ctx, u, &websocket.DialOptions{HTTPHeader: h})
+	info := azrealtime.NewHandshakeInfo(u, resp)
+	if err != nil {
+		connErr := azrealtime.NewConnectionError(u, "dial", err)
+		connErr.StatusCode, connErr.Header = info.StatusCode, info.Header
+		return nil, info, connErr
+	}
+	return conn, info, nil
+}
+
+// allowOrigin reports whether r comes from the gateway's own origin or one
+// of Config.AllowedOrigins.
+func (g *Gateway) allowOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	for _, o := range g.cfg.AllowedOrigins {
+		if o == "*" || (o == origin && origin != "") {
+			return true
+		}
+	}
+	if origin == "" {
+		return false
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// originPatterns repeats AllowedOrigins for websocket.Accept's own check,
+// which matches hosts only.
+func (g *Gateway) originPatterns() []string {
+	var patterns []string
+	for _, o := range g.cfg.AllowedOrigins {
+		if o == "*" {
+			return []string{"*"}
+		}
+		if u, err := url.Parse(o); err == nil && u.Host != "" {
+			patterns = append(patterns, u.Host)
+		}
+	}
+	return patterns
+}
+
+// reserve counts a connection against its subject's limit.
+func (g *Gateway) reserve(subject string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cfg.MaxSessionsPerSubject > 0 && subject != "" && g.subjects[subject] >= g.cfg.MaxSessionsPerSubject {
+		return false
+	}
+	g.subjects[subject]++
+	return true
+}
+
+func (g *Gateway) unreserve(subject string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.subjects[subject]--; g.subjects[subject] <= 0 {
+		delete(g.subjects, subject)
+	}
+}
+
+func (g *Gateway) add(s *session) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return false
+	}
+	g.sessions[s] = struct{}{}
+	return true
+}
+
+func (g *Gateway) remove(s *session) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.sessions, s)
+}
+
+func (g *Gateway) reportError(err error) {
+	if g.cfg.OnError != nil {
+		g.cfg.OnError(err)
+	}
+}
+
+// session is one browser connection and its upstream connection.
+type session struct {
+	gw       *Gateway
+	subject  string
+	browser  *websocket.Conn
+	upstream *websocket.Conn
+
+	closeOnce sync.Once
+}
+
+// run relays events until either side closes, then closes the other side
+// with the same status. It returns nil for a normal closure.
+func (s *session) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if s.gw.cfg.Session != nil {
+		update, err := json.Marshal(map[string]any{"type": "session.update", "session": s.gw.cfg.Session})
+		if err == nil {
+			err = s.upstream.Write(ctx, websocket.MessageText, update)
+		}
+		if err != nil {
+			s.close(websocket.StatusInternalError, "session.update failed")
+			return fmt.Errorf("gateway: session.update: %w", err)
+		}
+	}
+
+	errc := make(chan error, 2)
+	go func() { errc <- s.pump(ctx, s.browser, s.upstream, azrealtime.DirectionOutbound) }()
+	go func() { errc <- s.pump(ctx, s.upstream, s.browser, azrealtime.DirectionInbound) }()
+	err := <-errc
+
+	// Pass the close status on; reserved codes cannot be sent
+	status, reason := websocket.StatusGoingAway, "peer connection lost"
+	var ce websocket.CloseError
+	if errors.As(err, &ce) {
+		switch {
+		case ce.Code == websocket.StatusNoStatusRcvd:
+			status, reason = websocket.StatusNormalClosure, ""
+		case ce.Code != websocket.StatusAbnormalClosure && ce.Code != websocket.StatusTLSHandshake:
+			status, reason = ce.Code, ce.Reason
+		}
+	}
+	s.close(status, reason)
+	<-errc
+	if errors.As(err, &ce) && (status == websocket.StatusNormalClosure || status == websocket.StatusGoingAway) {
+		return nil
+	}
+	return err
+}
+
+// errBinaryMessage closes a browser that sends a binary message, which the
+// realtime protocol does not use and Filter could not see.
+var errBinaryMessage = websocket.CloseError{Code: websocket.StatusUnsupportedData, Reason: "binary messages are not supported"}
+
+// pump copies messages from src to dst.
+func (s *session) pump(ctx context.Context, src, dst *websocket.Conn, dir azrealtime.EventDirection) error {
+	cfg := s.gw.cfg
+	for {
+		typ, data, err := src.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if typ != websocket.MessageText && dir == azrealtime.DirectionOutbound {
+			return errBinaryMessage
+		}
+		if typ == websocket.MessageText && (cfg.OnEvent != nil || (cfg.Filter != nil && dir == azrealtime.DirectionOutbound)) {
+			var env struct {
+				Type string `json:"type"`
+			}
+			_ = json.Unmarshal(data, &env)
+			if cfg.Filter != nil && dir == azrealtime.DirectionOutbound {
+				filtered, err := cfg.Filter(s.subject, env.Type, data)
+				if err != nil {
+					if err := s.rejected(ctx, env.Type, err); err != nil {
+						return err
+					}
+					continue
+				}
+				if filtered == nil {
+					continue
+				}
+				data = filtered
+			}
+			if cfg.OnEvent != nil {
+				cfg.OnEvent(s.subject, dir, env.Type, data)
+			}
+		}
+		if err := dst.Write(ctx, typ, data); err != nil {
+			return err
+		}
+	}
+}
+
+// rejected tells the browser an event was dropped by Filter.
+func (s *session) rejected(ctx context.Context, eventType string, reason error) error {
+	ev, _ := json.Marshal(map[string]any{
+		"type": "error",
+		"error": map[string]any{
+			"type":    "invalid_request_error",
+			"code":    "gateway_rejected",
+			"message": reason.Error(),
+			"param":   eventType,
+		},
+	})
+	return s.browser.Write(ctx, websocket.MessageText, ev)
+}
+
+func (s *session) close(status websocket.StatusCode, reason string) {
+	s.closeOnce.Do(func() {
+		var wg sync.WaitGroup
+		for _, c := range []*websocket.Conn{s.browser, s.upstream} {
+			wg.Add(1)
+			go func(c *websocket.Conn) {
+				defer wg.Done()
+				_ = c.Close(status, reason)
+			}(c)
+		}
+		wg.Wait()
+	})
+}
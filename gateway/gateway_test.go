@@ -0,0 +1,389 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enesunal-m/azrealtime"
+	"nhooyr.io/websocket"
+)
+
+// fakeRealtime is an upstream realtime endpoint that records the events it
+// receives and answers each one with a response.done carrying its type.
+type fakeRealtime struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	received []string
+	closeMsg string // Closes the connection with status 4000 when received
+}
+
+func newFakeRealtime(t *testing.T) *fakeRealtime {
+	t.Helper()
+	f := &fakeRealtime{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-key") != "server-key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		c, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close(websocket.StatusInternalError, "")
+		for {
+			_, data, err := c.Read(r.Context())
+			if err != nil {
+				return
+			}
+			var ev struct {
+				Type string `json:"type"`
+			}
+			_ = json.Unmarshal(data, &ev)
+			f.mu.Lock()
+			f.received = append(f.received, ev.Type)
+			closeMsg := f.closeMsg
+			f.mu.Unlock()
+			if ev.Type == closeMsg {
+				c.Close(4000, "bye")
+				return
+			}
+			reply, _ := json.Marshal(map[string]string{"type": "response.done", "for": ev.Type})
+			if err := c.Write(r.Context(), websocket.MessageText, reply); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeRealtime) events() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.received...)
+}
+
+func upstreamConfig(f *fakeRealtime) azrealtime.Config {
+	return azrealtime.Config{
+		ResourceEndpoint: f.URL,
+		Deployment:       "gpt-realtime",
+		APIVersion:       "2025-04-01-preview",
+		Credential:       azrealtime.APIKey("server-key"),
+	}
+}
+
+// tokenAuth accepts the bearer token "token-<subject>".
+func tokenAuth(r *http.Request) (string, error) {
+	sub, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer token-")
+	if !ok {
+		return "", errors.New("invalid token")
+	}
+	return sub, nil
+}
+
+func newGateway(t *testing.T, cfg Config) (*Gateway, *httptest.Server) {
+	t.Helper()
+	gw, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(gw)
+	t.Cleanup(func() {
+		srv.Close()
+		gw.Close()
+	})
+	return gw, srv
+}
+
+// dialGateway connects to srv from its own origin.
+func dialGateway(t *testing.T, srv *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	h := http.Header{"Origin": {srv.URL}}
+	c, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+query, &websocket.DialOptions{HTTPHeader: h})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close(websocket.StatusNormalClosure, "") })
+	return c
+}
+
+func send(t *testing.T, c *websocket.Conn, eventType string) {
+	t.Helper()
+	data, _ := json.Marshal(map[string]string{"type": eventType})
+	if err := c.Write(context.Background(), websocket.MessageText, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func receive(t *testing.T, c *websocket.Conn) map[string]any {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, data, err := c.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ev map[string]any
+	if err := json.Unmarshal(data, &ev); err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+func TestNew_RequiresAuthenticator(t *testing.T) {
+	if _, err := New(Config{}); !errors.Is(err, ErrNoAuthenticator) {
+		t.Errorf("New without Authenticate: %v, want ErrNoAuthenticator", err)
+	}
+	if _, err := New(Config{AllowAnonymous: true}); err != nil {
+		t.Errorf("New with AllowAnonymous: %v", err)
+	}
+}
+
+func TestGateway_Relays(t *testing.T) {
+	f := newFakeRealtime(t)
+	var mu sync.Mutex
+	var seen []string
+	ended := make(chan error, 1)
+	gw, srv := newGateway(t, Config{
+		Upstream:     upstreamConfig(f),
+		Authenticate: tokenAuth,
+		Session:      &azrealtime.Session{Instructions: azrealtime.Ptr("Be brief.")},
+		OnEvent: func(subject string, dir azrealtime.EventDirection, eventType string, data []byte) {
+			mu.Lock()
+			seen = append(seen, subject+" "+string(dir)+" "+eventType)
+			mu.Unlock()
+		},
+		OnSessionEnd: func(subject string, err error) { ended <- err },
+	})
+
+	c := dialGateway(t, srv, "?access_token=token-alice")
+	if ev := receive(t, c); ev["for"] != "session.update" {
+		t.Fatalf("first event = %v, want the reply to session.update", ev)
+	}
+	send(t, c, "response.create")
+	if ev := receive(t, c); ev["for"] != "response.create" {
+		t.Fatalf("reply = %v", ev)
+	}
+	if got := f.events(); strings.Join(got, ",") != "session.update,response.create" {
+		t.Errorf("upstream received %v", got)
+	}
+	if gw.Len() != 1 {
+		t.Errorf("Len = %d, want 1", gw.Len())
+	}
+
+	c.Close(websocket.StatusNormalClosure, "")
+	select {
+	case err := <-ended:
+		if err != nil {
+			t.Errorf("session ended with %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("session did not end")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := "alice inbound response.done,alice outbound response.create,alice inbound response.done"
+	if strings.Join(seen, ",") != want {
+		t.Errorf("OnEvent saw %q", seen)
+	}
+}
+
+func TestGateway_Rejects(t *testing.T) {
+	f := newFakeRealtime(t)
+	up := upstreamConfig(f)
+	const app = "https://app.example.com"
+	_, srv := newGateway(t, Config{
+		Upstream:              up,
+		Authenticate:          tokenAuth,
+		AllowedOrigins:        []string{app},
+		MaxSessionsPerSubject: 1,
+	})
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	dial := func(query string, origin string) int {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		h := http.Header{}
+		if origin != "" {
+			h.Set("Origin", origin)
+		}
+		c, resp, err := websocket.Dial(ctx, url+query, &websocket.DialOptions{HTTPHeader: h})
+		if err == nil {
+			t.Cleanup(func() { c.Close(websocket.StatusNormalClosure, "") })
+			go func() { _, _, _ = c.Read(context.Background()) }()
+			return http.StatusSwitchingProtocols
+		}
+		if resp == nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	if code := dial("", app); code != http.StatusUnauthorized {
+		t.Errorf("without token: status %d", code)
+	}
+	if code := dial("?access_token=token-bob", "https://evil.example.com"); code != http.StatusForbidden {
+		t.Errorf("other origin: status %d", code)
+	}
+	if code := dial("?access_token=token-bob", ""); code != http.StatusForbidden {
+		t.Errorf("no origin: status %d", code)
+	}
+	if code := dial("?access_token=token-bob", app); code != http.StatusSwitchingProtocols {
+		t.Errorf("allowed origin: status %d", code)
+	}
+	if code := dial("?access_token=token-bob", app); code != http.StatusTooManyRequests {
+		t.Errorf("second session: status %d", code)
+	}
+	if code := dial("?access_token=token-carol", srv.URL); code != http.StatusSwitchingProtocols {
+		t.Errorf("same origin: status %d", code)
+	}
+
+	// A key the service rejects fails the browser's upgrade
+	var dialErr error
+	bad, badSrv := newGateway(t, Config{Upstream: up, AllowAnonymous: true, AllowedOrigins: []string{app}, OnError: func(err error) { dialErr = err }})
+	bad.cfg.Upstream.Credential = azrealtime.APIKey("wrong")
+	url = "ws" + strings.TrimPrefix(badSrv.URL, "http")
+	if code := dial("", app); code != http.StatusBadGateway {
+		t.Errorf("upstream unauthorized: status %d", code)
+	}
+	var connErr *azrealtime.ConnectionError
+	if !errors.As(dialErr, &connErr) || connErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("OnError got %v", dialErr)
+	}
+}
+
+func TestGateway_Filter(t *testing.T) {
+	f := newFakeRealtime(t)
+	_, srv := newGateway(t, Config{
+		Upstream:       upstreamConfig(f),
+		AllowAnonymous: true,
+		Filter: func(subject, eventType string, data []byte) ([]byte, error) {
+			if eventType == "session.update" {
+				return nil, errors.New("session.update is not allowed")
+			}
+			return data, nil
+		},
+	})
+
+	c := dialGateway(t, srv, "")
+	send(t, c, "session.update")
+	ev := receive(t, c)
+	if ev["type"] != "error" || ev["error"].(map[string]any)["code"] != "gateway_rejected" {
+		t.Fatalf("got %v, want a gateway_rejected error", ev)
+	}
+	send(t, c, "response.create")
+	if ev := receive(t, c); ev["for"] != "response.create" {
+		t.Fatalf("reply = %v", ev)
+	}
+	if got := f.events(); len(got) != 1 || got[0] != "response.create" {
+		t.Errorf("upstream received %v", got)
+	}
+	c.Close(websocket.StatusNormalClosure, "")
+}
+
+func TestGateway_FilterDrops(t *testing.T) {
+	f := newFakeRealtime(t)
+	var mu sync.Mutex
+	var seen []string
+	_, srv := newGateway(t, Config{
+		Upstream:       upstreamConfig(f),
+		AllowAnonymous: true,
+		Filter: func(subject, eventType string, data []byte) ([]byte, error) {
+			if eventType == "input_audio_buffer.clear" {
+				return nil, nil
+			}
+			return data, nil
+		},
+		OnEvent: func(subject string, dir azrealtime.EventDirection, eventType string, data []byte) {
+			if dir == azrealtime.DirectionOutbound {
+				mu.Lock()
+				seen = append(seen, eventType)
+				mu.Unlock()
+			}
+		},
+	})
+
+	c := dialGateway(t, srv, "")
+	send(t, c, "input_audio_buffer.clear")
+	send(t, c, "response.create")
+	// The dropped event gets no reply, so the first one is for response.create
+	if ev := receive(t, c); ev["for"] != "response.create" {
+		t.Fatalf("reply = %v", ev)
+	}
+	if got := f.events(); len(got) != 1 || got[0] != "response.create" {
+		t.Errorf("upstream received %v", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != 1 || seen[0] != "response.create" {
+		t.Errorf("OnEvent saw %v", seen)
+	}
+	c.Close(websocket.StatusNormalClosure, "")
+}
+
+func TestGateway_PassesCloseStatus(t *testing.T) {
+	f := newFakeRealtime(t)
+	f.closeMsg = "session.close"
+	ended := make(chan error, 1)
+	gw, srv := newGateway(t, Config{
+		Upstream:       upstreamConfig(f),
+		AllowAnonymous: true,
+		OnSessionEnd:   func(subject string, err error) { ended <- err },
+	})
+
+	c := dialGateway(t, srv, "")
+	send(t, c, "session.close")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _, err := c.Read(ctx)
+	if status := websocket.CloseStatus(err); status != 4000 {
+		t.Errorf("browser close status = %v (%v), want 4000", status, err)
+	}
+	select {
+	case err := <-ended:
+		if err == nil {
+			t.Error("expected the session to end with an error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("session did not end")
+	}
+	if gw.Len() != 0 {
+		t.Errorf("Len = %d after close", gw.Len())
+	}
+}
+
+func TestGateway_ClosesOnBinaryMessage(t *testing.T) {
+	f := newFakeRealtime(t)
+	_, srv := newGateway(t, Config{
+		Upstream:       upstreamConfig(f),
+		AllowAnonymous: true,
+		Filter: func(subject, eventType string, data []byte) ([]byte, error) {
+			return nil, errors.New("nothing is allowed")
+		},
+	})
+
+	c := dialGateway(t, srv, "")
+	data, _ := json.Marshal(map[string]string{"type": "session.update"})
+	if err := c.Write(context.Background(), websocket.MessageBinary, data); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _, err := c.Read(ctx)
+	if status := websocket.CloseStatus(err); status != websocket.StatusUnsupportedData {
+		t.Errorf("browser close status = %v (%v), want %v", status, err, websocket.StatusUnsupportedData)
+	}
+	if got := f.events(); len(got) != 0 {
+		t.Errorf("upstream received %v, want nothing", got)
+	}
+}
@@ -0,0 +1,175 @@
+package azrealtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// GlossaryEntry is a name or domain term the assistant should recognize and pronounce consistently.
+type GlossaryEntry struct {
+	Term          string // The term as written (e.g. "Azure", "Nguyen")
+	Pronunciation string // Optional spoken form (e.g. "AZH-er", "win")
+	Note          string // Optional short description to disambiguate the term
+}
+
+// Glossary manages a set of terms that are merged into the session instructions
+// (for consistent TTS pronunciation) and the input transcription prompt (for
+// better ASR accuracy). Add entries as new entities appear and push the result
+// with Client.UpdateGlossary. The zero value is an empty glossary. A Glossary
+// is safe for concurrent use.
+type Glossary struct {
+	mu      sync.RWMutex
+	order   []string                 // Terms in insertion order
+	entries map[string]GlossaryEntry // Entries keyed by lower-cased term
+}
+
+// NewGlossary creates a glossary with the given entries.
+func NewGlossary(entries ...GlossaryEntry) *Glossary {
+	g := &Glossary{entries: make(map[string]GlossaryEntry)}
+	g.Add(entries...)
+	return g
+}
+
+// Add inserts or replaces entries, matching terms case-insensitively.
+// Entries with an empty term are ignored. Returns true if the glossary changed.
+func (g *Glossary) Add(entries ...GlossaryEntry) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.entries == nil {
+		g.entries = make(map[string]GlossaryEntry)
+	}
+	changed := false
+	for _, e := range entries {
+		e.Term = strings.TrimSpace(e.Term)
+		if e.Term == "" {
+			continue
+		}
+		key := strings.ToLower(e.Term)
+		old, ok := g.entries[key]
+		if !ok {
+			g.order = append(g.order, key)
+		}
+		if !ok || old != e {
+			g.entries[key] = e
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Remove deletes the given terms. Returns true if any term was removed.
+func (g *Glossary) Remove(terms ...string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	changed := false
+	for _, t := range terms {
+		key := strings.ToLower(strings.TrimSpace(t))
+		if _, ok := g.entries[key]; !ok {
+			continue
+		}
+		delete(g.entries, key)
+		for i, k := range g.order {
+			if k == key {
+				g.order = append(g.order[:i], g.order[i+1:]...)
+				break
+			}
+		}
+		changed = true
+	}
+	return changed
+}
+
+// Entries returns the glossary entries in insertion order.
+func (g *Glossary) Entries() []GlossaryEntry {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make([]GlossaryEntry, 0, len(g.order))
+	for _, k := range g.order {
+		out = append(out, g.entries[k])
+	}
+	return out
+}
+
+// Len returns the number of entries.
+func (g *Glossary) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.order)
+}
+
+// InstructionsSection renders the glossary as a block to append to session
+// instructions. Returns an empty string for an empty glossary.
+func (g *Glossary) InstructionsSection() string {
+	entries := g.Entries()
+	if len(entries) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Glossary (use these spellings and pronunciations consistently):")
+	for _, e := range entries {
+		b.WriteString("\n- ")
+		b.WriteString(e.Term)
+		if e.Pronunciation != "" {
+			fmt.Fprintf(&b, " (pronounced %q)", e.Pronunciation)
+		}
+		if e.Note != "" {
+			b.WriteString(": ")
+			b.WriteString(e.Note)
+		}
+	}
+	return b.String()
+}
+
+// TranscriptionPrompt renders the glossary terms as a vocabulary hint for the
+// input transcription prompt. Returns an empty string for an empty glossary.
+func (g *Glossary) TranscriptionPrompt() string {
+	entries := g.Entries()
+	if len(entries) == 0 {
+		return ""
+	}
+	terms := make([]string, len(entries))
+	for i, e := range entries {
+		terms[i] = e.Term
+	}
+	return "Vocabulary: " + strings.Join(terms, ", ") + "."
+}
+
+// Apply returns a copy of base with the glossary merged into Instructions and,
+// if base configures InputTranscription, into its Prompt. Base instructions and
+// prompt are kept and the glossary is appended after them, so pass the
+// glossary-free originals each time the glossary changes.
+func (g *Glossary) Apply(base Session) Session {
+	out := base
+	if section := g.InstructionsSection(); section != "" {
+		instructions := section
+		if base.Instructions != nil && *base.Instructions != "" {
+			instructions = *base.Instructions + "\n\n" + section
+		}
+		out.Instructions = &instructions
+	}
+	if base.InputTranscription != nil {
+		it := *base.InputTranscription
+		if vocab := g.TranscriptionPrompt(); vocab != "" {
+			prompt := vocab
+			if it.Prompt != nil && *it.Prompt != "" {
+				prompt = *it.Prompt + " " + vocab
+			}
+			it.Prompt = &prompt
+		}
+		out.InputTranscription = &it
+	}
+	return out
+}
+
+// UpdateGlossary merges g into base (see Glossary.Apply) and sends the resulting
+// instructions and transcription settings as a session update. Call it again
+// whenever the glossary changes mid-session.
+func (c *Client) UpdateGlossary(ctx context.Context, base Session, g *Glossary) error {
+	merged := g.Apply(base)
+	return c.SessionUpdate(ctx, Session{
+		Instructions:       merged.Instructions,
+		InputTranscription: merged.InputTranscription,
+	})
+}
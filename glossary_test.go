@@ -0,0 +1,90 @@
+package azrealtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGlossary_AddRemove(t *testing.T) {
+	g := NewGlossary(GlossaryEntry{Term: "Azure"}, GlossaryEntry{Term: " "})
+	if g.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", g.Len())
+	}
+	if g.Add(GlossaryEntry{Term: "Azure "}) {
+		t.Error("re-adding an identical term should report no change")
+	}
+	if !g.Add(GlossaryEntry{Term: "Azure", Pronunciation: "AZH-er"}) {
+		t.Error("updating a term should report a change")
+	}
+	g.Add(GlossaryEntry{Term: "Nguyen", Pronunciation: "win", Note: "customer surname"})
+	if got := g.Entries(); len(got) != 2 || got[0].Pronunciation != "AZH-er" || got[1].Term != "Nguyen" {
+		t.Errorf("unexpected entries: %+v", got)
+	}
+	if !g.Remove("AZURE") || g.Remove("missing") || g.Len() != 1 {
+		t.Errorf("unexpected remove behavior, entries: %+v", g.Entries())
+	}
+}
+
+func TestGlossary_ZeroValue(t *testing.T) {
+	var g Glossary
+	if g.Remove("Azure") || g.Len() != 0 || g.InstructionsSection() != "" {
+		t.Errorf("unexpected empty glossary: %+v", g.Entries())
+	}
+	if !g.Add(GlossaryEntry{Term: "Azure"}) || g.Len() != 1 {
+		t.Errorf("expected Add on the zero value to store the term, entries: %+v", g.Entries())
+	}
+}
+
+func TestGlossary_Apply(t *testing.T) {
+	g := NewGlossary(
+		GlossaryEntry{Term: "Contoso", Pronunciation: "con-TOE-so"},
+		GlossaryEntry{Term: "Nguyen", Note: "customer surname"},
+	)
+	base := Session{
+		Instructions:       Ptr("You are a support agent."),
+		InputTranscription: &InputTranscription{Model: "whisper-1", Prompt: Ptr("Support call.")},
+	}
+	out := g.Apply(base)
+
+	if !strings.HasPrefix(*out.Instructions, "You are a support agent.\n\nGlossary") ||
+		!strings.Contains(*out.Instructions, `- Contoso (pronounced "con-TOE-so")`) ||
+		!strings.Contains(*out.Instructions, "- Nguyen: customer surname") {
+		t.Errorf("unexpected instructions: %q", *out.Instructions)
+	}
+	if *out.InputTranscription.Prompt != "Support call. Vocabulary: Contoso, Nguyen." {
+		t.Errorf("unexpected prompt: %q", *out.InputTranscription.Prompt)
+	}
+	if *base.InputTranscription.Prompt != "Support call." {
+		t.Error("Apply must not modify the base session")
+	}
+
+	empty := NewGlossary().Apply(base)
+	if *empty.Instructions != "You are a support agent." {
+		t.Errorf("empty glossary should leave instructions unchanged, got %q", *empty.Instructions)
+	}
+}
+
+func TestClient_UpdateGlossary(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	g := NewGlossary(GlossaryEntry{Term: "Contoso"})
+	if err := client.UpdateGlossary(ctx, Session{Instructions: Ptr("Be helpful.")}, g); err != nil {
+		t.Fatalf("UpdateGlossary: %v", err)
+	}
+	state := client.SessionUpdates().State()
+	if state.Instructions == nil || !strings.Contains(*state.Instructions, "Contoso") {
+		t.Errorf("expected glossary in session instructions, got %+v", state)
+	}
+}
@@ -6,7 +6,9 @@ require (
 	github.com/MicahParks/keyfunc/v2 v2.1.0
 	github.com/coreos/go-oidc/v3 v3.7.0
 	github.com/golang-jwt/jwt/v5 v5.2.1
+	github.com/pion/rtp v1.8.5
 	github.com/pion/webrtc/v3 v3.2.39
+	gopkg.in/yaml.v3 v3.0.1
 	nhooyr.io/websocket v1.8.7
 )
 
@@ -24,7 +26,6 @@ require (
 	github.com/pion/mdns v0.0.12 // indirect
 	github.com/pion/randutil v0.1.0 // indirect
 	github.com/pion/rtcp v1.2.12 // indirect
-	github.com/pion/rtp v1.8.5 // indirect
 	github.com/pion/sctp v1.8.16 // indirect
 	github.com/pion/sdp/v3 v3.0.9 // indirect
 	github.com/pion/srtp/v2 v2.0.18 // indirect
@@ -39,5 +40,4 @@ require (
 	golang.org/x/sys v0.18.0 // indirect
 	google.golang.org/appengine v1.6.8 // indirect
 	google.golang.org/protobuf v1.31.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
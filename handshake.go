@@ -0,0 +1,85 @@
+package azrealtime
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// requestIDHeaders lists response headers carrying a server request ID, in order of preference.
+var requestIDHeaders = []string{"x-ms-request-id", "apim-request-id", "x-request-id"}
+
+// HandshakeInfo describes the HTTP response to the WebSocket handshake.
+type HandshakeInfo struct {
+	URL        string      // The WebSocket URL dialed
+	StatusCode int         // HTTP status (101 on success)
+	Header     http.Header // Response headers, including rate-limit headers
+	RequestID  string      // Server request ID (x-ms-request-id or equivalent), if present
+}
+
+// HandshakeRequest returns the WebSocket URL and handshake headers, including
+// credentials, that Dial uses for cfg. Proxies and other WebSocket libraries
+// can use them to open the same connection; see the gateway package.
+func HandshakeRequest(cfg Config) (string, http.Header, error) {
+	if err := ValidateConfig(cfg); err != nil {
+		return "", nil, err
+	}
+	u, h, err := handshakeRequest(cfg)
+	if err != nil {
+		return "", nil, err
+	}
+	return u.String(), h, nil
+}
+
+// handshakeRequest builds the realtime URL for cfg and applies authentication
+// and custom headers.
+func handshakeRequest(cfg Config) (*url.URL, http.Header, error) {
+	u, err := realtimeURL(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	h := http.Header{}
+	for k, vals := range cfg.HandshakeHeaders {
+		for _, v := range vals {
+			h.Add(k, v)
+		}
+	}
+	if cfg.Provider == ProviderOpenAI {
+		h.Set("OpenAI-Beta", "realtime=v1")
+		openAIAuth{cfg.Credential}.apply(h)
+	} else {
+		cfg.Credential.apply(h)
+	}
+	return u, h, nil
+}
+
+// NewHandshakeInfo extracts handshake metadata from the response to a
+// handshake with url; resp may be nil. Proxies dialing with HandshakeRequest
+// use it to report the same HandshakeInfo as Dial.
+func NewHandshakeInfo(url string, resp *http.Response) HandshakeInfo {
+	info := HandshakeInfo{URL: url}
+	if resp == nil {
+		return info
+	}
+	info.StatusCode = resp.StatusCode
+	info.Header = resp.Header.Clone()
+	info.RequestID = requestIDFromHeader(resp.Header)
+	return info
+}
+
+// requestIDFromHeader returns the first request ID header present in h.
+func requestIDFromHeader(h http.Header) string {
+	for _, name := range requestIDHeaders {
+		if v := h.Get(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// HandshakeInfo returns metadata from the handshake of the current connection.
+// After Reconnect it describes the new connection.
+func (c *Client) HandshakeInfo() HandshakeInfo {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.handshake
+}
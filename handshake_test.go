@@ -0,0 +1,96 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_HandshakeInfo(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	info := client.HandshakeInfo()
+	if info.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("expected status 101, got %d", info.StatusCode)
+	}
+	if info.RequestID != "req_mock_123" || info.Header.Get("x-ms-request-id") != "req_mock_123" {
+		t.Errorf("expected request ID from headers, got %+v", info)
+	}
+	if !strings.Contains(info.URL, "/openai/realtime") {
+		t.Errorf("unexpected URL %q", info.URL)
+	}
+}
+
+func TestDial_ConnectionErrorCarriesHandshakeResponse(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The mock server rejects handshakes without credentials
+	cfg := CreateMockConfig(mockServer.URL())
+	cfg.Credential = APIKey("")
+
+	_, err := Dial(ctx, cfg)
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("expected ConnectionError, got %v", err)
+	}
+	if connErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", connErr.StatusCode)
+	}
+	if connErr.RequestID() != "req_mock_123" {
+		t.Errorf("expected request ID, got %q", connErr.RequestID())
+	}
+	if !strings.Contains(err.Error(), "status 401, request id req_mock_123") {
+		t.Errorf("expected status and request ID in message, got %q", err.Error())
+	}
+}
+
+func TestHandshakeRequest(t *testing.T) {
+	cfg := Config{
+		ResourceEndpoint: "https://example.openai.azure.com",
+		Deployment:       "gpt-realtime",
+		APIVersion:       "2025-04-01-preview",
+		Credential:       APIKey("secret"),
+		HandshakeHeaders: http.Header{"X-Team": {"voice"}},
+	}
+	u, h, err := HandshakeRequest(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "wss://example.openai.azure.com/openai/realtime?api-version=2025-04-01-preview&deployment=gpt-realtime"
+	if u != want {
+		t.Errorf("url = %q, want %q", u, want)
+	}
+	if h.Get("api-key") != "secret" || h.Get("X-Team") != "voice" {
+		t.Errorf("headers = %v", h)
+	}
+
+	cfg.Provider, cfg.ResourceEndpoint = ProviderOpenAI, ""
+	_, h, err = HandshakeRequest(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Get("Authorization") != "Bearer secret" || h.Get("api-key") != "" || h.Get("OpenAI-Beta") == "" {
+		t.Errorf("openai headers = %v", h)
+	}
+
+	if _, _, err := HandshakeRequest(Config{}); err == nil {
+		t.Error("expected an error for an empty config")
+	}
+}
@@ -0,0 +1,195 @@
+package azrealtime
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthStatus is the state reported by a HealthChecker.
+type HealthStatus int
+
+const (
+	HealthUnknown   HealthStatus = iota // No check has completed yet
+	HealthHealthy                       // The last check succeeded
+	HealthUnhealthy                     // FailureThreshold consecutive checks failed, or the client stopped
+)
+
+// String returns "unknown", "healthy" or "unhealthy".
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthHealthy:
+		return "healthy"
+	case HealthUnhealthy:
+		return "unhealthy"
+	default:
+		return "unknown"
+	}
+}
+
+// Defaults for HealthCheckConfig.
+const (
+	DefaultHealthCheckInterval = 10 * time.Second
+	DefaultHealthCheckTimeout  = 5 * time.Second
+)
+
+// HealthCheckConfig configures NewHealthChecker.
+type HealthCheckConfig struct {
+	// Interval between pings. If zero, DefaultHealthCheckInterval is used.
+	Interval time.Duration
+
+	// Timeout for each ping. If zero, DefaultHealthCheckTimeout is used.
+	Timeout time.Duration
+
+	// FailureThreshold is the number of consecutive failed pings before the
+	// status becomes HealthUnhealthy. If zero, one failure is enough.
+	FailureThreshold int
+
+	// OnChange, if set, is called on every status transition with the new
+	// status and the error of the check that caused it (nil when healthy).
+	OnChange func(status HealthStatus, err error)
+}
+
+// HealthChecker pings a client periodically and tracks whether its connection
+// is healthy, e.g. for the readiness probe of a service embedding the client.
+// It implements http.Handler, answering 200 when healthy and 503 otherwise:
+//
+//	health := azrealtime.NewHealthChecker(client, azrealtime.HealthCheckConfig{})
+//	defer health.Stop()
+//	http.Handle("/readyz", health)
+//
+// The client stopping (see Client.Done) makes the status HealthUnhealthy at
+// once. A HealthChecker is safe for concurrent use.
+type HealthChecker struct {
+	client RealtimeClient
+	cfg    HealthCheckConfig
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+
+	mu       sync.Mutex
+	status   HealthStatus
+	err      error
+	rtt      time.Duration
+	failures int
+	checked  time.Time
+}
+
+// NewHealthChecker starts checking client, pinging it once immediately and
+// then every cfg.Interval. Call Stop to end the checks.
+func NewHealthChecker(client RealtimeClient, cfg HealthCheckConfig) *HealthChecker {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultHealthCheckInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultHealthCheckTimeout
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 1
+	}
+	h := &HealthChecker{client: client, cfg: cfg, stop: make(chan struct{}), done: make(chan struct{})}
+	go h.run()
+	return h
+}
+
+// Status returns the current status and the error of the last failed check
+// (nil once a check succeeds).
+func (h *HealthChecker) Status() (HealthStatus, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status, h.err
+}
+
+// LastRTT returns the round-trip time of the last successful check.
+func (h *HealthChecker) LastRTT() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.rtt
+}
+
+// LastCheck returns when the last check completed.
+func (h *HealthChecker) LastCheck() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.checked
+}
+
+// Stop ends the checks and waits for a running check to finish. It is safe
+// to call more than once.
+func (h *HealthChecker) Stop() {
+	h.once.Do(func() { close(h.stop) })
+	<-h.done
+}
+
+// ServeHTTP reports the status as text: 200 when healthy, 503 otherwise.
+func (h *HealthChecker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	status, err := h.Status()
+	code := http.StatusOK
+	if status != HealthHealthy {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	msg := status.String()
+	if err != nil {
+		msg += ": " + err.Error()
+	}
+	_, _ = w.Write([]byte(msg + "\n"))
+}
+
+func (h *HealthChecker) run() {
+	defer close(h.done)
+	t := time.NewTicker(h.cfg.Interval)
+	defer t.Stop()
+	h.check()
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-h.client.Done():
+			err := h.client.Err()
+			if err == nil {
+				err = ErrClosed
+			}
+			h.record(0, err, true)
+			return
+		case <-t.C:
+			h.check()
+		}
+	}
+}
+
+func (h *HealthChecker) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), h.cfg.Timeout)
+	defer cancel()
+	rtt, err := h.client.Ping(ctx)
+	h.record(rtt, err, false)
+}
+
+// record applies a check result and reports a status transition. fatal marks
+// the client as unhealthy regardless of FailureThreshold.
+func (h *HealthChecker) record(rtt time.Duration, err error, fatal bool) {
+	h.mu.Lock()
+	h.checked = time.Now()
+	h.err = err
+	next := h.status
+	if err == nil {
+		h.failures = 0
+		h.rtt = rtt
+		next = HealthHealthy
+	} else {
+		h.failures++
+		if fatal || h.failures >= h.cfg.FailureThreshold {
+			next = HealthUnhealthy
+		}
+	}
+	changed := next != h.status
+	h.status = next
+	fn := h.cfg.OnChange
+	h.mu.Unlock()
+
+	if changed && fn != nil {
+		fn(next, err)
+	}
+}
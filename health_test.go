@@ -0,0 +1,91 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Ping(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+
+	rtt, err := client.Ping(context.Background())
+	if err != nil || rtt <= 0 {
+		t.Fatalf("Ping = %v, %v", rtt, err)
+	}
+	if client.RTT() != rtt {
+		t.Errorf("RTT = %v, want %v", client.RTT(), rtt)
+	}
+
+	client.Close()
+	if _, err := client.Ping(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Ping after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestHealthChecker(t *testing.T) {
+	client, mockServer := dialProfile(t, nil)
+
+	changes := make(chan HealthStatus, 4)
+	health := NewHealthChecker(client, HealthCheckConfig{
+		Interval: 10 * time.Millisecond,
+		OnChange: func(s HealthStatus, _ error) { changes <- s },
+	})
+	defer health.Stop()
+
+	wait := func(want HealthStatus) {
+		t.Helper()
+		select {
+		case got := <-changes:
+			if got != want {
+				t.Fatalf("status changed to %v, want %v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no transition to %v", want)
+		}
+	}
+	probe := func() int {
+		rec := httptest.NewRecorder()
+		health.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code
+	}
+
+	wait(HealthHealthy)
+	if code := probe(); code != http.StatusOK {
+		t.Errorf("probe while healthy = %d", code)
+	}
+	if health.LastRTT() <= 0 || health.LastCheck().IsZero() {
+		t.Errorf("LastRTT = %v, LastCheck = %v", health.LastRTT(), health.LastCheck())
+	}
+
+	// Losing the connection is reported without waiting for the threshold
+	mockServer.DropConnections()
+	wait(HealthUnhealthy)
+	if status, err := health.Status(); status != HealthUnhealthy || err == nil {
+		t.Errorf("Status = %v, %v", status, err)
+	}
+	if code := probe(); code != http.StatusServiceUnavailable {
+		t.Errorf("probe while unhealthy = %d", code)
+	}
+	health.Stop()
+}
+
+func TestHealthChecker_FailureThreshold(t *testing.T) {
+	h := &HealthChecker{cfg: HealthCheckConfig{FailureThreshold: 2}}
+	h.record(time.Millisecond, nil, false)
+	h.record(0, errors.New("timeout"), false)
+	if s, _ := h.Status(); s != HealthHealthy {
+		t.Errorf("status after one failure = %v, want healthy", s)
+	}
+	h.record(0, errors.New("timeout"), false)
+	if s, _ := h.Status(); s != HealthUnhealthy {
+		t.Errorf("status after two failures = %v, want unhealthy", s)
+	}
+	h.record(time.Millisecond, nil, false)
+	if s, err := h.Status(); s != HealthHealthy || err != nil {
+		t.Errorf("status after recovery = %v, %v", s, err)
+	}
+}
@@ -0,0 +1,91 @@
+package azrealtime
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// MaxInstructionsLength is the longest instructions text accepted by
+// ValidateSession and ValidateCreateResponseOptions.
+const MaxInstructionsLength = 10000
+
+// InstructionsTemplate builds per-user instructions from a text/template,
+// e.g. to greet the caller by name or include their account tier:
+//
+//	tmpl, err := azrealtime.NewInstructionsTemplate(
+//		"You are a support agent for {{.Company}}. The caller is {{.Name}} ({{.Tier}} customer).")
+//	session, err := tmpl.Session(map[string]any{"Company": "Contoso", "Name": name, "Tier": tier})
+//	err = client.SessionUpdate(ctx, session)
+//
+// Referencing a variable that is not provided is an error, so a typo cannot
+// silently produce an empty field. An InstructionsTemplate is safe for
+// concurrent use once its limits are set.
+type InstructionsTemplate struct {
+	tmpl *template.Template
+
+	// MaxLength limits the rendered instructions. Zero or values above
+	// MaxInstructionsLength mean MaxInstructionsLength.
+	MaxLength int
+
+	// MaxVarLength, if positive, limits the length of each variable's value,
+	// so user-supplied values such as names cannot take over the prompt.
+	MaxVarLength int
+}
+
+// NewInstructionsTemplate parses text as a text/template.
+func NewInstructionsTemplate(text string) (*InstructionsTemplate, error) {
+	tmpl, err := template.New("instructions").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("azrealtime: instructions template: %w", err)
+	}
+	return &InstructionsTemplate{tmpl: tmpl}, nil
+}
+
+// Render executes the template with vars. It fails with ValidationErrors if a
+// variable or the result is too long.
+func (t *InstructionsTemplate) Render(vars map[string]any) (string, error) {
+	var errs ValidationErrors
+	if t.MaxVarLength > 0 {
+		names := make([]string, 0, len(vars))
+		for name := range vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if n := len(fmt.Sprint(vars[name])); n > t.MaxVarLength {
+				errs.add(fmt.Sprintf("instructions.%s", name), ValidationCodeTooLong, "variable %s too long (%d characters), maximum is %d", name, n, t.MaxVarLength)
+			}
+		}
+		if err := errs.err(); err != nil {
+			return "", err
+		}
+	}
+
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("azrealtime: instructions template: %w", err)
+	}
+	out := b.String()
+
+	limit := t.MaxLength
+	if limit <= 0 || limit > MaxInstructionsLength {
+		limit = MaxInstructionsLength
+	}
+	if len(out) > limit {
+		errs.add("instructions", ValidationCodeTooLong, "instructions too long (%d characters), maximum is %d", len(out), limit)
+		return "", errs.err()
+	}
+	return out, nil
+}
+
+// Session renders the template into a Session that only sets Instructions,
+// ready for Client.SessionUpdate.
+func (t *InstructionsTemplate) Session(vars map[string]any) (Session, error) {
+	out, err := t.Render(vars)
+	if err != nil {
+		return Session{}, err
+	}
+	return Session{Instructions: Ptr(out)}, nil
+}
@@ -0,0 +1,107 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// interruptTracker follows the in-progress response and the assistant audio
+// item being streamed, for Client.Interrupt. Out-of-band responses are not
+// tracked. The item is kept after its response is done, since playback
+// usually lags behind the server.
+type interruptTracker struct {
+	mu           sync.Mutex
+	responseID   string // Active response, empty once done
+	itemID       string // Last item that received audio
+	contentIndex int
+	audioBytes   int // Audio received for itemID
+}
+
+func (t *interruptTracker) responseCreated(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.responseID = id
+}
+
+func (t *interruptTracker) responseDone(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.responseID == id {
+		t.responseID = ""
+	}
+}
+
+func (t *interruptTracker) audioDelta(e ResponseAudioDelta, n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e.ItemID != t.itemID || e.ContentIndex != t.contentIndex {
+		t.itemID, t.contentIndex, t.audioBytes = e.ItemID, e.ContentIndex, 0
+	}
+	t.audioBytes += n
+}
+
+// take returns the current state and clears it.
+func (t *interruptTracker) take() (responseID, itemID string, contentIndex, audioBytes int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	responseID, itemID, contentIndex, audioBytes = t.responseID, t.itemID, t.contentIndex, t.audioBytes
+	t.responseID, t.itemID, t.contentIndex, t.audioBytes = "", "", 0, 0
+	return
+}
+
+// OnInterrupt registers a callback invoked by Interrupt after the cancel and
+// truncate events were sent. Use it to drop locally queued audio, e.g. by
+// calling Reset on an AudioAssembler, JitterBuffer or AudioTimeline.
+func (c *Client) OnInterrupt(fn func()) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	c.onInterrupt = fn
+}
+
+// Interrupt handles a barge-in in one call: it cancels the in-progress
+// response, truncates the assistant audio item being streamed at playedMS
+// (the milliseconds of it the user actually heard, e.g. from AudioTimeline or
+// the player), and invokes the OnInterrupt callback so local buffers can be
+// cleared. Steps with nothing to do are skipped, and playedMS is clamped to
+// the audio received for the item. A response that is already done is not
+// canceled, but its item is still truncated, as the user is usually still
+// hearing it. Out-of-band responses are never canceled or truncated.
+//
+// The OnInterrupt callback runs even if sending fails; the first send error is
+// returned.
+func (c *Client) Interrupt(ctx context.Context, playedMS int) error {
+	if ctx == nil {
+		return NewSendError("response.cancel", "", errors.New("context cannot be nil"))
+	}
+	if playedMS < 0 {
+		return NewSendError("conversation.item.truncate", "", errors.New("played duration must be non-negative"))
+	}
+
+	responseID, itemID, contentIndex, audioBytes := c.interrupts.take()
+	var errs []error
+	if responseID != "" {
+		if err := c.CancelResponse(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if itemID != "" {
+		received := int(audioDuration(c.outputAudioFormat(), audioBytes) / time.Millisecond)
+		if err := c.TruncateConversationItem(ctx, itemID, contentIndex, min(playedMS, received)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.log("interrupt", map[string]any{"response_id": responseID, "item_id": itemID, "played_ms": playedMS})
+
+	c.handlerMu.RLock()
+	if c.onInterrupt != nil {
+		c.onInterrupt()
+	}
+	c.handlerMu.RUnlock()
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// outboundRecorder collects the client events a client sends.
+type outboundRecorder struct {
+	mu     sync.Mutex
+	events []map[string]any
+}
+
+func recordOutbound(c *Client) *outboundRecorder {
+	r := &outboundRecorder{}
+	c.OnRawEvent(func(dir EventDirection, data []byte) {
+		if dir != DirectionOutbound {
+			return
+		}
+		var m map[string]any
+		_ = json.Unmarshal(data, &m)
+		r.mu.Lock()
+		r.events = append(r.events, m)
+		r.mu.Unlock()
+	})
+	return r
+}
+
+func (r *outboundRecorder) ofType(typ string) []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []map[string]any
+	for _, m := range r.events {
+		if m["type"] == typ {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func TestClient_Interrupt_ActiveResponse(t *testing.T) {
+	client, _ := dialProfile(t, &MockProfile{Response: SyntheticResponse(10*time.Millisecond, 30*time.Millisecond, 100)})
+	sent := recordOutbound(client)
+
+	deltas := make(chan struct{}, 100)
+	done := make(chan string, 1)
+	client.OnResponseAudioDelta(func(ResponseAudioDelta) { deltas <- struct{}{} })
+	client.OnResponseDone(func(e ResponseDone) { done <- e.Response.Status })
+	interrupted := make(chan struct{})
+	client.OnInterrupt(func() { close(interrupted) })
+
+	ctx := context.Background()
+	if _, err := client.CreateResponse(ctx, CreateResponseOptions{Modalities: []string{"audio", "text"}}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-deltas:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for audio")
+		}
+	}
+
+	if err := client.Interrupt(ctx, 120); err != nil {
+		t.Fatalf("Interrupt: %v", err)
+	}
+	select {
+	case <-interrupted:
+	default:
+		t.Error("OnInterrupt was not called")
+	}
+	select {
+	case status := <-done:
+		if status != "cancelled" {
+			t.Errorf("response status = %q, want cancelled", status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("response was not cancelled")
+	}
+
+	if got := len(sent.ofType("response.cancel")); got != 1 {
+		t.Errorf("sent %d response.cancel events, want 1", got)
+	}
+	truncates := sent.ofType("conversation.item.truncate")
+	if len(truncates) != 1 {
+		t.Fatalf("sent %d truncate events, want 1", len(truncates))
+	}
+	if m := truncates[0]; m["item_id"] != "item_profile" || m["audio_end_ms"] != float64(120) {
+		t.Errorf("truncate event = %v", m)
+	}
+}
+
+func TestClient_Interrupt_AfterResponseDone(t *testing.T) {
+	// Two 100ms deltas: the response is complete before playback finishes
+	client, _ := dialProfile(t, &MockProfile{Response: SyntheticResponse(0, 0, 2)})
+	sent := recordOutbound(client)
+
+	done := make(chan struct{})
+	client.OnResponseDone(func(ResponseDone) { close(done) })
+
+	ctx := context.Background()
+	if _, err := client.CreateResponse(ctx, CreateResponseOptions{Modalities: []string{"audio", "text"}}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for response.done")
+	}
+
+	if err := client.Interrupt(ctx, 500); err != nil {
+		t.Fatalf("Interrupt: %v", err)
+	}
+	if got := len(sent.ofType("response.cancel")); got != 0 {
+		t.Errorf("sent %d response.cancel events for a finished response", got)
+	}
+	truncates := sent.ofType("conversation.item.truncate")
+	if len(truncates) != 1 || truncates[0]["audio_end_ms"] != float64(200) {
+		t.Errorf("truncate events = %v, want one clamped to 200ms", truncates)
+	}
+
+	// Nothing is left to interrupt
+	if err := client.Interrupt(ctx, 0); err != nil {
+		t.Fatalf("second Interrupt: %v", err)
+	}
+	if got := len(sent.ofType("conversation.item.truncate")); got != 1 {
+		t.Errorf("second Interrupt sent another truncate")
+	}
+
+	if err := client.Interrupt(ctx, -1); err == nil {
+		t.Error("expected error for negative played duration")
+	}
+}
+
+func TestClient_Interrupt_IgnoresOutOfBand(t *testing.T) {
+	// An out-of-band response still streaming audio
+	steps := SyntheticResponse(0, 0, 2)
+	steps = steps[:len(steps)-2]
+	steps[0].Event = ResponseCreated{Type: "response.created", Response: ResponseObject{
+		ID: "resp_profile", Status: "in_progress", Metadata: map[string]any{ResponseOutOfBandMetadataKey: "true"},
+	}}
+	client, _ := dialProfile(t, &MockProfile{Response: steps})
+	sent := recordOutbound(client)
+
+	deltas := make(chan struct{}, 2)
+	client.OnResponseAudioDelta(func(ResponseAudioDelta) { deltas <- struct{}{} })
+
+	ctx := context.Background()
+	if _, _, err := client.CreateOutOfBandResponse(ctx, CreateResponseOptions{Modalities: []string{"audio", "text"}}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-deltas:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for audio")
+		}
+	}
+
+	if err := client.Interrupt(ctx, 100); err != nil {
+		t.Fatalf("Interrupt: %v", err)
+	}
+	if got := len(sent.ofType("response.cancel")); got != 0 {
+		t.Errorf("sent %d response.cancel events for an out-of-band response", got)
+	}
+	if got := len(sent.ofType("conversation.item.truncate")); got != 0 {
+		t.Errorf("sent %d truncate events for an out-of-band response", got)
+	}
+}
@@ -0,0 +1,169 @@
+package azrealtime
+
+import (
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// JitterBufferOptions configures a JitterBuffer. Zero fields use defaults.
+type JitterBufferOptions struct {
+	// SampleRate of the PCM16 mono audio. Default: DefaultSampleRate.
+	SampleRate int
+
+	// Frame is the size of frames returned by NextFrame. Default: 20ms.
+	Frame time.Duration
+
+	// TargetLatency is how much audio is buffered before playback starts, and
+	// again after an underrun. Higher values ride out burstier delivery at the
+	// cost of delay. Default: 100ms.
+	TargetLatency time.Duration
+
+	// MaxLatency caps the buffered audio; beyond it the oldest audio is dropped
+	// so playback catches up. Default: 2s.
+	MaxLatency time.Duration
+}
+
+// JitterBufferStats counts playback irregularities.
+type JitterBufferStats struct {
+	Underruns    int           // Times playback ran dry mid-response
+	DroppedBytes int           // Audio discarded for exceeding MaxLatency
+	Buffered     time.Duration // Audio currently queued
+}
+
+// JitterBuffer smooths assistant audio for live playback. Deltas arrive in
+// bursts; the buffer holds back TargetLatency of audio and then hands out a
+// steady stream, filling gaps with silence instead of stalling the player:
+//
+//	jb := azrealtime.NewJitterBuffer(azrealtime.JitterBufferOptions{})
+//	client.OnResponseAudioDelta(func(e azrealtime.ResponseAudioDelta) { jb.OnDelta(e) })
+//	client.OnResponseAudioDone(func(e azrealtime.ResponseAudioDone) { jb.OnDone(e.ResponseID) })
+//	client.OnInputAudioBufferSpeechStarted(func(azrealtime.InputAudioBufferSpeechStarted) { jb.Reset() })
+//	// Audio callback, every 20ms:
+//	speaker.Write(jb.NextFrame())
+//
+// Read and NextFrame never block. A JitterBuffer is safe for concurrent use.
+type JitterBuffer struct {
+	frameBytes  int
+	targetBytes int
+	maxBytes    int
+	bytesPerSec int
+
+	mu        sync.Mutex
+	buf       []byte
+	playing   bool // Target reached; handing out audio
+	draining  bool // Response done; play out what is left without waiting
+	underruns int
+	dropped   int
+}
+
+// NewJitterBuffer creates a JitterBuffer.
+func NewJitterBuffer(opts JitterBufferOptions) *JitterBuffer {
+	if opts.SampleRate <= 0 {
+		opts.SampleRate = DefaultSampleRate
+	}
+	if opts.Frame <= 0 {
+		opts.Frame = 20 * time.Millisecond
+	}
+	if opts.TargetLatency <= 0 {
+		opts.TargetLatency = 100 * time.Millisecond
+	}
+	if opts.MaxLatency <= 0 {
+		opts.MaxLatency = 2 * time.Second
+	}
+	bytesFor := func(d time.Duration) int {
+		return int(int64(opts.SampleRate)*int64(d)/int64(time.Second)) * 2
+	}
+	return &JitterBuffer{
+		frameBytes:  max(bytesFor(opts.Frame), 2),
+		targetBytes: bytesFor(opts.TargetLatency),
+		maxBytes:    max(bytesFor(opts.MaxLatency), bytesFor(opts.TargetLatency)),
+		bytesPerSec: opts.SampleRate * 2,
+	}
+}
+
+// OnDelta queues the audio of a response.audio.delta event.
+func (jb *JitterBuffer) OnDelta(e ResponseAudioDelta) error {
+	pcm, err := base64.StdEncoding.DecodeString(e.DeltaBase64)
+	if err != nil {
+		return err
+	}
+	_, err = jb.Write(pcm)
+	return err
+}
+
+// Write queues PCM16 audio. It implements io.Writer and never fails.
+func (jb *JitterBuffer) Write(pcm []byte) (int, error) {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+	jb.buf = append(jb.buf, pcm...)
+	jb.draining = false
+	if over := len(jb.buf) - jb.maxBytes; over > 0 {
+		over += over % 2 // Keep sample alignment
+		jb.buf = jb.buf[over:]
+		jb.dropped += over
+	}
+	return len(pcm), nil
+}
+
+// OnDone marks the end of a response's audio, so the remainder is played
+// without waiting for TargetLatency to fill.
+func (jb *JitterBuffer) OnDone(responseID string) {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+	jb.draining = true
+}
+
+// Read fills p with the next audio, padding with silence while buffering or on
+// underrun. It implements io.Reader and always returns len(p), nil.
+func (jb *JitterBuffer) Read(p []byte) (int, error) {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+
+	if !jb.playing && (len(jb.buf) >= jb.targetBytes || (jb.draining && len(jb.buf) > 0)) {
+		jb.playing = true
+	}
+	n := 0
+	if jb.playing {
+		n = copy(p, jb.buf)
+		n -= n % 2
+		jb.buf = jb.buf[n:]
+		if len(jb.buf) == 0 {
+			jb.buf = nil // Release the backing array
+			if n < len(p) {
+				// Ran dry: rebuild the cushion before playing again
+				if !jb.draining {
+					jb.underruns++
+				}
+				jb.playing = false
+			}
+		}
+	}
+	clear(p[n:])
+	return len(p), nil
+}
+
+// NextFrame returns one Frame of audio, silence-padded like Read.
+func (jb *JitterBuffer) NextFrame() []byte {
+	frame := make([]byte, jb.frameBytes)
+	_, _ = jb.Read(frame)
+	return frame
+}
+
+// Reset discards all queued audio, e.g. when the user barges in.
+func (jb *JitterBuffer) Reset() {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+	jb.buf, jb.playing, jb.draining = nil, false, false
+}
+
+// Stats returns playback counters and the current buffer depth.
+func (jb *JitterBuffer) Stats() JitterBufferStats {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+	return JitterBufferStats{
+		Underruns:    jb.underruns,
+		DroppedBytes: jb.dropped,
+		Buffered:     time.Duration(len(jb.buf)) * time.Second / time.Duration(jb.bytesPerSec),
+	}
+}
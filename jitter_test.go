@@ -0,0 +1,96 @@
+package azrealtime
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+// 1 kHz keeps the numbers small: 20ms frames are 40 bytes
+func newTestJitterBuffer() *JitterBuffer {
+	return NewJitterBuffer(JitterBufferOptions{
+		SampleRate:    1000,
+		TargetLatency: 60 * time.Millisecond,
+		MaxLatency:    200 * time.Millisecond,
+	})
+}
+
+func TestJitterBuffer_WaitsForTarget(t *testing.T) {
+	jb := newTestJitterBuffer()
+	audio := bytes.Repeat([]byte{1}, 80) // 40ms, below the 60ms target
+
+	if err := jb.OnDelta(ResponseAudioDelta{DeltaBase64: base64.StdEncoding.EncodeToString(audio)}); err != nil {
+		t.Fatal(err)
+	}
+	if f := jb.NextFrame(); !bytes.Equal(f, make([]byte, 40)) {
+		t.Error("played audio before reaching the target latency")
+	}
+
+	jb.Write(bytes.Repeat([]byte{2}, 40)) // Now 60ms
+	if f := jb.NextFrame(); !bytes.Equal(f, bytes.Repeat([]byte{1}, 40)) {
+		t.Errorf("first frame = %v, want queued audio", f)
+	}
+	if got := jb.Stats().Buffered; got != 40*time.Millisecond {
+		t.Errorf("Buffered = %v, want 40ms", got)
+	}
+}
+
+func TestJitterBuffer_UnderrunPadsSilence(t *testing.T) {
+	jb := newTestJitterBuffer()
+	jb.Write(bytes.Repeat([]byte{1}, 130)) // 65ms
+
+	for i := 0; i < 3; i++ {
+		jb.NextFrame()
+	}
+	// 5ms of audio left, then silence
+	f := jb.NextFrame()
+	if !bytes.Equal(f[:10], bytes.Repeat([]byte{1}, 10)) || !bytes.Equal(f[10:], make([]byte, 30)) {
+		t.Errorf("underrun frame = %v", f)
+	}
+	if got := jb.Stats().Underruns; got != 1 {
+		t.Errorf("Underruns = %d, want 1", got)
+	}
+
+	// After an underrun the buffer waits for the target again
+	jb.Write(bytes.Repeat([]byte{3}, 40))
+	if f := jb.NextFrame(); !bytes.Equal(f, make([]byte, 40)) {
+		t.Error("resumed before rebuilding the cushion")
+	}
+}
+
+func TestJitterBuffer_OnDoneDrains(t *testing.T) {
+	jb := newTestJitterBuffer()
+	jb.Write(bytes.Repeat([]byte{1}, 20)) // A 10ms tail, below target
+	jb.OnDone("resp_1")
+
+	f := jb.NextFrame()
+	if !bytes.Equal(f[:20], bytes.Repeat([]byte{1}, 20)) {
+		t.Error("tail of a finished response was not played")
+	}
+	if got := jb.Stats().Underruns; got != 0 {
+		t.Errorf("end of response counted as %d underruns", got)
+	}
+}
+
+func TestJitterBuffer_MaxLatencyAndReset(t *testing.T) {
+	jb := newTestJitterBuffer()
+	jb.Write(bytes.Repeat([]byte{1}, 300))
+	jb.Write(bytes.Repeat([]byte{2}, 300)) // 300ms total, 200ms max
+
+	st := jb.Stats()
+	if st.DroppedBytes != 200 || st.Buffered != 200*time.Millisecond {
+		t.Errorf("stats = %+v, want 200 bytes dropped and 200ms buffered", st)
+	}
+	if f := jb.NextFrame(); f[0] != 1 {
+		t.Error("oldest audio kept instead of newest")
+	}
+
+	jb.Reset()
+	if jb.Stats().Buffered != 0 {
+		t.Error("Reset left audio queued")
+	}
+	if f := jb.NextFrame(); !bytes.Equal(f, make([]byte, 40)) {
+		t.Error("played audio after Reset")
+	}
+}
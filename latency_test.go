@@ -0,0 +1,227 @@
+package azrealtime
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// timeline records when named events reach the client's handlers.
+type timeline struct {
+	mu     sync.Mutex
+	start  time.Time
+	events []timedEvent
+	notify chan struct{}
+}
+
+type timedEvent struct {
+	name string
+	at   time.Duration // Since start
+}
+
+func newTimeline() *timeline {
+	return &timeline{start: time.Now(), notify: make(chan struct{}, 1)}
+}
+
+func (tl *timeline) add(name string) {
+	tl.mu.Lock()
+	tl.events = append(tl.events, timedEvent{name, time.Since(tl.start)})
+	tl.mu.Unlock()
+	select {
+	case tl.notify <- struct{}{}:
+	default:
+	}
+}
+
+// wait blocks until an event named name is recorded, and returns its time.
+func (tl *timeline) wait(t *testing.T, name string) time.Duration {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		tl.mu.Lock()
+		for _, e := range tl.events {
+			if e.name == name {
+				tl.mu.Unlock()
+				return e.at
+			}
+		}
+		tl.mu.Unlock()
+		select {
+		case <-tl.notify:
+		case <-deadline:
+			t.Fatalf("timed out waiting for %s", name)
+		}
+	}
+}
+
+func (tl *timeline) all(name string) []time.Duration {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	var out []time.Duration
+	for _, e := range tl.events {
+		if e.name == name {
+			out = append(out, e.at)
+		}
+	}
+	return out
+}
+
+// timingSlack allows a gap measured at the client to come out slightly shorter
+// than scheduled, when the earlier of two events was delivered late.
+const timingSlack = 10 * time.Millisecond
+
+// assertBetween fails unless lo-timingSlack <= d <= hi. The upper bound absorbs scheduling noise.
+func assertBetween(t *testing.T, what string, d, lo, hi time.Duration) {
+	t.Helper()
+	if d < lo-timingSlack || d > hi {
+		t.Errorf("%s = %v, want between %v and %v", what, d, lo, hi)
+	}
+}
+
+func dialProfile(t *testing.T, p *MockProfile) (*Client, *MockServer) {
+	t.Helper()
+	mockServer := NewMockServer(t)
+	mockServer.Profile = p
+	client, err := Dial(context.Background(), CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		mockServer.Close()
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		mockServer.Close()
+	})
+	return client, mockServer
+}
+
+func TestMockProfile_TimeToFirstAudio(t *testing.T) {
+	const firstDelta, interval, n = 150 * time.Millisecond, 30 * time.Millisecond, 5
+	client, _ := dialProfile(t, &MockProfile{Response: SyntheticResponse(firstDelta, interval, n)})
+
+	tl := newTimeline()
+	client.OnResponseCreated(func(ResponseCreated) { tl.add("created") })
+	client.OnResponseAudioDelta(func(ResponseAudioDelta) { tl.add("delta") })
+	client.OnResponseDone(func(ResponseDone) { tl.add("done") })
+
+	tl.start = time.Now()
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{Modalities: []string{"audio", "text"}}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+
+	created := tl.wait(t, "created")
+	done := tl.wait(t, "done")
+	deltas := tl.all("delta")
+	if len(deltas) != n {
+		t.Fatalf("got %d deltas, want %d", len(deltas), n)
+	}
+
+	assertBetween(t, "time to response.created", created, 0, 100*time.Millisecond)
+	assertBetween(t, "time to first audio", deltas[0], firstDelta, firstDelta+150*time.Millisecond)
+	for i := 1; i < n; i++ {
+		assertBetween(t, "inter-delta gap", deltas[i]-deltas[i-1], interval, interval+100*time.Millisecond)
+	}
+	wantTotal := firstDelta + (n-1)*interval
+	assertBetween(t, "time to response.done", done, wantTotal, wantTotal+200*time.Millisecond)
+}
+
+func TestMockProfile_VADSequence(t *testing.T) {
+	speech := []MockStep{
+		{Delay: 50 * time.Millisecond, Event: InputAudioBufferSpeechStarted{Type: "input_audio_buffer.speech_started", AudioStartMs: 50, ItemID: "item_user"}},
+		{Delay: 300 * time.Millisecond, Event: InputAudioBufferSpeechStopped{Type: "input_audio_buffer.speech_stopped", AudioEndMs: 350, ItemID: "item_user"}},
+		{Delay: 20 * time.Millisecond, Event: InputAudioBufferCommitted{Type: "input_audio_buffer.committed", ItemID: "item_user"}},
+	}
+	client, _ := dialProfile(t, &MockProfile{Speech: speech})
+
+	tl := newTimeline()
+	client.OnInputAudioBufferSpeechStarted(func(InputAudioBufferSpeechStarted) { tl.add("started") })
+	client.OnInputAudioBufferSpeechStopped(func(InputAudioBufferSpeechStopped) { tl.add("stopped") })
+	client.OnInputAudioBufferCommitted(func(InputAudioBufferCommitted) { tl.add("committed") })
+
+	tl.start = time.Now()
+	if err := client.AppendPCM16(context.Background(), make([]byte, PCM16BytesFor(100, DefaultSampleRate))); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	started := tl.wait(t, "started")
+	stopped := tl.wait(t, "stopped")
+	committed := tl.wait(t, "committed")
+
+	assertBetween(t, "time to speech_started", started, 50*time.Millisecond, 200*time.Millisecond)
+	assertBetween(t, "speech duration", stopped-started, 300*time.Millisecond, 400*time.Millisecond)
+	assertBetween(t, "commit after stop", committed-stopped, 20*time.Millisecond, 120*time.Millisecond)
+}
+
+func TestMockProfile_CancelStopsAudio(t *testing.T) {
+	// A long reply the client interrupts after a few deltas, as a barge-in would
+	client, _ := dialProfile(t, &MockProfile{Response: SyntheticResponse(20*time.Millisecond, 20*time.Millisecond, 100)})
+
+	tl := newTimeline()
+	var once sync.Once
+	var status string
+	client.OnResponseAudioDelta(func(ResponseAudioDelta) {
+		tl.add("delta")
+		if len(tl.all("delta")) == 3 {
+			once.Do(func() { go func() { _ = client.CancelResponse(context.Background()) }() })
+		}
+	})
+	client.OnResponseDone(func(e ResponseDone) {
+		status = e.Response.Status
+		tl.add("done")
+	})
+
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{Modalities: []string{"audio", "text"}}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	done := tl.wait(t, "done")
+	time.Sleep(100 * time.Millisecond) // Leave room for stray deltas to show up
+
+	if status != "cancelled" {
+		t.Errorf("response status = %q, want cancelled", status)
+	}
+	deltas := tl.all("delta")
+	if len(deltas) >= 10 {
+		t.Errorf("got %d deltas, want playback to stop soon after cancel", len(deltas))
+	}
+	if last := deltas[len(deltas)-1]; last > done {
+		t.Errorf("delta at %v arrived after response.done at %v", last, done)
+	}
+}
+
+func TestMockStepsFromRecording(t *testing.T) {
+	transcript := strings.Join([]string{
+		`{"ts":"2025-01-01T00:00:00Z","dir":"outbound","type":"response.create","event":{"type":"response.create"}}`,
+		`{"ts":"2025-01-01T00:00:00.100Z","dir":"inbound","type":"response.created","event":{"type":"response.created"}}`,
+		`{"ts":"2025-01-01T00:00:00.350Z","dir":"inbound","type":"response.text.delta","event":{"type":"response.text.delta","delta":"Hi"}}`,
+		`{"ts":"2025-01-01T00:00:00.400Z","dir":"inbound","type":"response.done","event":{"type":"response.done","response":{"status":"completed"}}}`,
+	}, "\n")
+
+	steps, err := MockStepsFromRecording(strings.NewReader(transcript))
+	if err != nil {
+		t.Fatalf("MockStepsFromRecording: %v", err)
+	}
+	want := []time.Duration{0, 250 * time.Millisecond, 50 * time.Millisecond}
+	if len(steps) != len(want) {
+		t.Fatalf("got %d steps, want %d (outbound events skipped)", len(steps), len(want))
+	}
+	for i, d := range want {
+		if steps[i].Delay != d {
+			t.Errorf("step %d delay = %v, want %v", i, steps[i].Delay, d)
+		}
+	}
+
+	// The recorded timing is reproduced end to end
+	client, _ := dialProfile(t, &MockProfile{Response: steps})
+	tl := newTimeline()
+	client.OnResponseTextDelta(func(ResponseTextDelta) { tl.add("delta") })
+	client.OnResponseDone(func(ResponseDone) { tl.add("done") })
+	tl.start = time.Now()
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{Modalities: []string{"text"}}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	delta := tl.wait(t, "delta")
+	done := tl.wait(t, "done")
+	assertBetween(t, "recorded delta delay", delta, 250*time.Millisecond, 400*time.Millisecond)
+	assertBetween(t, "recorded done gap", done-delta, 50*time.Millisecond, 150*time.Millisecond)
+}
@@ -0,0 +1,130 @@
+package azrealtime
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLogSummaryInterval is how often suppressed log lines are summarized
+// when LogSampling.SummaryInterval is zero.
+const DefaultLogSummaryInterval = 10 * time.Second
+
+// LogSampling limits log output for high-frequency events such as audio and
+// text deltas: events of a sampled type are logged 1 in Every[type] times, and
+// the number of suppressed lines per type is logged periodically as a
+// "log_sampling_summary" event.
+//
+// The type of a log line is its "type" field when that is a string (as in the
+// client's event_received and event_sent debug lines), otherwise its event name.
+// The client samples GA event names under their preview names (see
+// CanonicalEventType), so one key covers both spellings.
+type LogSampling struct {
+	// Every maps a type to N, logging only every Nth line of that type.
+	// Types not listed, or with N <= 1, are not sampled.
+	Every map[string]int
+
+	// SummaryInterval sets how often suppressed counts are logged. If zero,
+	// DefaultLogSummaryInterval is used; if negative, no summaries are logged.
+	SummaryInterval time.Duration
+}
+
+// DefaultLogSampling returns the sampling used for the client's per-event
+// debug lines when Config.LogSampling is nil: 1 in 100 audio events and
+// 1 in 50 text, transcript and argument deltas.
+func DefaultLogSampling() LogSampling {
+	return LogSampling{Every: map[string]int{
+		"input_audio_buffer.append":                         100,
+		"response.audio.delta":                              100,
+		"response.text.delta":                               50,
+		"response.audio_transcript.delta":                   50,
+		"response.function_call_arguments.delta":            50,
+		"conversation.item.input_audio_transcription.delta": 50,
+	}}
+}
+
+// logSampler applies a LogSampling.
+type logSampler struct {
+	mu          sync.Mutex
+	cfg         LogSampling
+	seen        map[string]uint64 // Lines per type, to pick every Nth
+	suppressed  map[string]uint64 // Lines dropped since the last summary
+	lastSummary time.Time
+}
+
+func newLogSampler(cfg LogSampling) *logSampler {
+	if cfg.SummaryInterval == 0 {
+		cfg.SummaryInterval = DefaultLogSummaryInterval
+	}
+	return &logSampler{cfg: cfg, seen: make(map[string]uint64), suppressed: make(map[string]uint64), lastSummary: time.Now()}
+}
+
+// sampleKey returns the type a log line is sampled by.
+func sampleKey(event string, fields map[string]any) string {
+	if typ, ok := fields["type"].(string); ok {
+		return typ
+	}
+	return event
+}
+
+// allow reports whether a line of type key should be logged. When a summary
+// is due it also returns the fields of a log_sampling_summary line.
+func (s *logSampler) allow(key string, now time.Time) (bool, map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ok := true
+	if n := s.cfg.Every[key]; n > 1 {
+		s.seen[key]++
+		if s.seen[key]%uint64(n) != 1 {
+			s.suppressed[key]++
+			ok = false
+		}
+	}
+
+	if s.cfg.SummaryInterval < 0 || len(s.suppressed) == 0 || now.Sub(s.lastSummary) < s.cfg.SummaryInterval {
+		return ok, nil
+	}
+	keys := make([]string, 0, len(s.suppressed))
+	for k := range s.suppressed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	counts := make(map[string]any, len(keys))
+	for _, k := range keys {
+		counts[k] = s.suppressed[k]
+	}
+	summary := map[string]any{"suppressed": counts, "interval": now.Sub(s.lastSummary).String()}
+	s.suppressed = make(map[string]uint64)
+	s.lastSummary = now
+	return ok, summary
+}
+
+// SetSampling enables sampling of high-frequency log lines; see LogSampling.
+// Summaries are logged at the level of the line that triggered them.
+func (l *Logger) SetSampling(s LogSampling) {
+	l.sampler = newLogSampler(s)
+}
+
+// logDebugEvent writes a sampled debug line for an inbound or outbound event.
+// Only StructuredLogger receives these lines, since Config.Logger has no levels.
+func (c *Client) logDebugEvent(event, typ string, size int) {
+	if c.cfg.StructuredLogger == nil {
+		return
+	}
+	c.samplerOnce.Do(func() {
+		sampling := DefaultLogSampling()
+		if c.cfg.LogSampling != nil {
+			sampling = *c.cfg.LogSampling
+		}
+		c.sampler = newLogSampler(sampling)
+	})
+	// Sample GA and preview spellings of an event alike
+	ok, summary := c.sampler.allow(CanonicalEventType(typ), time.Now())
+	if summary != nil {
+		c.cfg.StructuredLogger.Debug("log_sampling_summary", c.logFields(summary))
+	}
+	if ok {
+		c.cfg.StructuredLogger.Debug(event, c.logFields(map[string]any{"type": typ, "bytes": size}))
+	}
+}
@@ -0,0 +1,169 @@
+package azrealtime
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogSampler_OneInN(t *testing.T) {
+	s := newLogSampler(LogSampling{Every: map[string]int{"response.audio.delta": 3}, SummaryInterval: time.Minute})
+	now := s.lastSummary
+
+	var logged int
+	for i := 0; i < 7; i++ {
+		if ok, _ := s.allow("response.audio.delta", now); ok {
+			logged++
+		}
+	}
+	if logged != 3 { // 1st, 4th and 7th
+		t.Errorf("logged %d of 7, want 3", logged)
+	}
+	if ok, _ := s.allow("session.updated", now); !ok {
+		t.Error("unsampled type was suppressed")
+	}
+
+	ok, summary := s.allow("response.audio.delta", now.Add(time.Minute))
+	if ok {
+		t.Error("8th delta was logged")
+	}
+	if summary == nil {
+		t.Fatal("no summary after the interval")
+	}
+	counts := summary["suppressed"].(map[string]any)
+	if counts["response.audio.delta"] != uint64(5) {
+		t.Errorf("suppressed = %v, want 5", counts)
+	}
+	if _, summary := s.allow("response.audio.delta", now.Add(time.Minute+time.Second)); summary != nil {
+		t.Error("summary repeated before the next interval")
+	}
+}
+
+func TestLogSampler_NoSummary(t *testing.T) {
+	s := newLogSampler(LogSampling{Every: map[string]int{"x": 2}, SummaryInterval: -1})
+	for i := 0; i < 4; i++ {
+		if _, summary := s.allow("x", time.Now().Add(time.Hour)); summary != nil {
+			t.Fatal("summary logged with negative SummaryInterval")
+		}
+	}
+}
+
+func TestLogger_SetSampling(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(LogLevelDebug)
+	logger.logger = log.New(&buf, "", 0)
+	logger.SetSampling(LogSampling{Every: map[string]int{"audio_chunk": 10, "response.text.delta": 2}})
+
+	for i := 0; i < 20; i++ {
+		logger.Debug("audio_chunk", nil)
+		logger.Debug("event", map[string]any{"type": "response.text.delta"})
+	}
+	logger.Info("ws_connected", nil)
+
+	out := buf.String()
+	if n := strings.Count(out, "audio_chunk"); n != 2 {
+		t.Errorf("audio_chunk logged %d times, want 2", n)
+	}
+	if n := strings.Count(out, "type=response.text.delta"); n != 10 {
+		t.Errorf("text delta logged %d times, want 10", n)
+	}
+	if !strings.Contains(out, "ws_connected") {
+		t.Error("unsampled event missing")
+	}
+}
+
+func TestClient_LogSampling(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	rec := &levelRecorder{}
+	cfg := CreateMockConfig(mockServer.URL())
+	cfg.StructuredLogger = rec
+	cfg.LogSampling = &LogSampling{Every: map[string]int{"input_audio_buffer.append": 5}}
+	client, err := Dial(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 10; i++ {
+		if err := client.AppendPCM16(context.Background(), make([]byte, 320)); err != nil {
+			t.Fatalf("AppendPCM16: %v", err)
+		}
+	}
+
+	count := func(event string) int {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		n := 0
+		for _, e := range rec.events {
+			if e == event {
+				n++
+			}
+		}
+		return n
+	}
+	if n := count("DEBUG event_sent"); n != 2 {
+		t.Errorf("event_sent logged %d times for 10 appends, want 2", n)
+	}
+	waitUntil(t, "session.created to be logged", func() bool { return count("DEBUG event_received") > 0 })
+}
+
+// typeRecorder counts the event_received debug lines per event type.
+type typeRecorder struct {
+	mu       sync.Mutex
+	received map[string]int
+}
+
+func (r *typeRecorder) Debug(event string, fields map[string]any) {
+	if event != "event_received" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.received == nil {
+		r.received = make(map[string]int)
+	}
+	r.received[fields["type"].(string)]++
+}
+func (r *typeRecorder) Info(string, map[string]any)  {}
+func (r *typeRecorder) Warn(string, map[string]any)  {}
+func (r *typeRecorder) Error(string, map[string]any) {}
+
+func (r *typeRecorder) count(typ string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.received[typ]
+}
+
+func TestClient_LogSamplingGANames(t *testing.T) {
+	const id = "resp_ga"
+	steps := []MockStep{{Event: ResponseCreated{Type: "response.created", Response: ResponseObject{ID: id}}}}
+	for i := 0; i < 10; i++ {
+		steps = append(steps, MockStep{Event: ResponseAudioDelta{Type: "response.output_audio.delta", ResponseID: id, DeltaBase64: "AAAA"}})
+	}
+	steps = append(steps, MockStep{Event: ResponseDone{Type: "response.done", Response: ResponseObject{ID: id, Status: "completed"}}})
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+	mockServer.Profile = &MockProfile{Response: steps}
+
+	rec := &typeRecorder{}
+	cfg := CreateMockConfig(mockServer.URL())
+	cfg.StructuredLogger = rec
+	client, err := Dial(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{Modalities: []string{"audio"}}); err != nil {
+		t.Fatalf("CreateResponse: %v", err)
+	}
+	waitUntil(t, "response.done to be logged", func() bool { return rec.count("response.done") > 0 })
+	if n := rec.count("response.output_audio.delta"); n != 1 {
+		t.Errorf("event_received logged %d times for 10 GA audio deltas, want 1", n)
+	}
+}
@@ -1,10 +1,14 @@
 package azrealtime
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"sort"
 	"strings"
+	"time"
 )
 
 // LogLevel represents the severity level of a log message
@@ -59,11 +63,42 @@ func ParseLogLevel(level string) LogLevel {
 	}
 }
 
+// StructuredLogger receives the client's log events with their fields at four
+// levels. *Logger implements it; implement it directly to route events to
+// another logging library without losing levels:
+//
+//	type zapLogger struct{ l *zap.SugaredLogger }
+//
+//	func (z zapLogger) Info(event string, fields map[string]any) {
+//		z.l.Infow(event, flatten(fields)...)
+//	}
+//	// Debug, Warn and Error likewise
+//
+// Implementations must be safe for concurrent use.
+type StructuredLogger interface {
+	Debug(event string, fields map[string]any)
+	Info(event string, fields map[string]any)
+	Warn(event string, fields map[string]any)
+	Error(event string, fields map[string]any)
+}
+
+var _ StructuredLogger = (*Logger)(nil)
+
+// normalizeLoggers clears a StructuredLogger holding a nil *Logger, so that
+// it falls back to Config.Logger as it did when the field was a *Logger.
+func normalizeLoggers(cfg *Config) {
+	if l, ok := cfg.StructuredLogger.(*Logger); ok && l == nil {
+		cfg.StructuredLogger = nil
+	}
+}
+
 // Logger provides structured logging with configurable levels
 type Logger struct {
-	level  LogLevel
-	prefix string
-	logger *log.Logger
+	level   LogLevel
+	prefix  string
+	logger  *log.Logger
+	slog    *slog.Logger // If set, receives records instead of logger (see NewSlogLogger)
+	sampler *logSampler  // If set, drops high-frequency lines (see SetSampling)
 }
 
 // NewLogger creates a new structured logger
@@ -75,6 +110,18 @@ func NewLogger(level LogLevel) *Logger {
 	}
 }
 
+// NewSlogLogger creates a structured logger that writes to sl, for use as
+// Config.StructuredLogger in services built on log/slog. Events become the
+// record message and fields become attributes; levels map to their slog
+// equivalents, and sl's handler decides which are enabled. A nil sl uses
+// slog.Default().
+func NewSlogLogger(sl *slog.Logger) *Logger {
+	if sl == nil {
+		sl = slog.Default()
+	}
+	return &Logger{level: LogLevelDebug, slog: sl}
+}
+
 // NewLoggerFromEnv creates a logger with level from AZREALTIME_LOG_LEVEL env var
 func NewLoggerFromEnv() *Logger {
 	level := ParseLogLevel(os.Getenv("AZREALTIME_LOG_LEVEL"))
@@ -113,7 +160,25 @@ func (l *Logger) Error(event string, fields map[string]interface{}) {
 
 // log is the internal logging method
 func (l *Logger) log(level LogLevel, event string, fields map[string]interface{}) {
-	if level < l.level {
+	if l == nil || level < l.level {
+		return
+	}
+	if l.sampler != nil {
+		ok, summary := l.sampler.allow(sampleKey(event, fields), time.Now())
+		if summary != nil {
+			l.write(level, "log_sampling_summary", summary)
+		}
+		if !ok {
+			return
+		}
+	}
+	l.write(level, event, fields)
+}
+
+// write formats and emits one line.
+func (l *Logger) write(level LogLevel, event string, fields map[string]interface{}) {
+	if l.slog != nil {
+		l.logSlog(level, event, fields)
 		return
 	}
 
@@ -131,6 +196,39 @@ func (l *Logger) log(level LogLevel, event string, fields map[string]interface{}
 	l.logger.Print(message)
 }
 
+// slogLevel maps a LogLevel to the corresponding slog.Level.
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelWarn:
+		return slog.LevelWarn
+	case LogLevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// logSlog writes one record to the slog.Logger, with fields sorted by key.
+func (l *Logger) logSlog(level LogLevel, event string, fields map[string]interface{}) {
+	ctx := context.Background()
+	sl := slogLevel(level)
+	if !l.slog.Enabled(ctx, sl) {
+		return
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+	l.slog.LogAttrs(ctx, sl, event, attrs...)
+}
+
 // LoggerFunc creates a logger function compatible with the Config.Logger field
 func (l *Logger) LoggerFunc() func(string, map[string]interface{}) {
 	return func(event string, fields map[string]interface{}) {
@@ -2,10 +2,15 @@ package azrealtime
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -249,3 +254,112 @@ func TestLogger_EmptyFields(t *testing.T) {
 		}
 	}
 }
+
+func TestNewSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	sl := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	logger := NewSlogLogger(sl)
+
+	logger.Debug("hidden", nil)
+	logger.Warn("ws_reconnecting", map[string]interface{}{"err": errors.New("eof"), "attempt": 2})
+	logger.WithContext(map[string]interface{}{"session": "sess_1"}).Error("send_failed", nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d records, want 2 (debug filtered by the handler): %q", len(lines), buf.String())
+	}
+	var warn, errRec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &warn); err != nil {
+		t.Fatal(err)
+	}
+	if warn["level"] != "WARN" || warn["msg"] != "ws_reconnecting" || warn["err"] != "eof" || warn["attempt"] != float64(2) {
+		t.Errorf("warn record = %v", warn)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &errRec); err != nil {
+		t.Fatal(err)
+	}
+	if errRec["level"] != "ERROR" || errRec["session"] != "sess_1" {
+		t.Errorf("error record = %v", errRec)
+	}
+
+	// SetLevel still filters before slog
+	logger.SetLevel(LogLevelError)
+	buf.Reset()
+	logger.Info("filtered", nil)
+	if buf.Len() != 0 {
+		t.Errorf("SetLevel did not filter: %q", buf.String())
+	}
+}
+
+// levelRecorder is a StructuredLogger that records "LEVEL event" lines.
+type levelRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *levelRecorder) record(level, event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, level+" "+event)
+}
+
+func (r *levelRecorder) Debug(event string, _ map[string]any) { r.record("DEBUG", event) }
+func (r *levelRecorder) Info(event string, _ map[string]any)  { r.record("INFO", event) }
+func (r *levelRecorder) Warn(event string, _ map[string]any)  { r.record("WARN", event) }
+func (r *levelRecorder) Error(event string, _ map[string]any) { r.record("ERROR", event) }
+
+func TestConfig_CustomStructuredLogger(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	rec := &levelRecorder{}
+	cfg := CreateMockConfig(mockServer.URL())
+	cfg.StructuredLogger = rec
+	cfg.ValidationMode = ValidationWarn
+	client, err := Dial(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+	if err := client.SessionUpdate(context.Background(), Session{Voice: Ptr("newvoice")}); err != nil {
+		t.Fatalf("SessionUpdate: %v", err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	got := strings.Join(rec.events, ", ")
+	if !strings.Contains(got, "INFO ws_connected") || !strings.Contains(got, "WARN validation_warning") {
+		t.Errorf("events = %s", got)
+	}
+}
+
+func TestConfig_NilLoggerFallsBack(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	var mu sync.Mutex
+	var events []string
+	var nilLogger *Logger
+	cfg := CreateMockConfig(mockServer.URL())
+	cfg.StructuredLogger = nilLogger
+	cfg.Logger = func(event string, _ map[string]interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+	client, err := Dial(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) == 0 || events[0] != "ws_connected" {
+		t.Errorf("Logger got %v, want ws_connected", events)
+	}
+
+	// Calling a nil *Logger directly is a no-op too
+	nilLogger.Debug("ignored", nil)
+	nilLogger.Error("ignored", nil)
+}
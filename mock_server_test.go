@@ -1,11 +1,19 @@
 package azrealtime
 
 import (
+	"bufio"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"nhooyr.io/websocket"
 )
@@ -15,6 +23,130 @@ type MockServer struct {
 	server   *httptest.Server
 	messages []interface{}
 	t        *testing.T
+
+	// SessionExpiresIn, if set, makes session.created report an expires_at this far in the future
+	SessionExpiresIn time.Duration
+
+	// Profile, if set, scripts server timing for latency tests
+	Profile *MockProfile
+
+	connections atomic.Int32
+
+	// Reject, while set, makes the server refuse new connections
+	Reject atomic.Bool
+
+	liveMu sync.Mutex
+	live   map[*websocket.Conn]bool
+}
+
+// DropConnections closes every open connection as if the server went away.
+func (ms *MockServer) DropConnections() {
+	ms.liveMu.Lock()
+	defer ms.liveMu.Unlock()
+	for conn := range ms.live {
+		_ = conn.Close(websocket.StatusGoingAway, "server restarting")
+	}
+}
+
+// MockStep is a scripted server event, sent Delay after the previous step.
+type MockStep struct {
+	Delay time.Duration
+	Event interface{}
+}
+
+// MockProfile scripts the timing of server events so latency-sensitive code can
+// be tested against realistic pacing with asserted bounds.
+type MockProfile struct {
+	// Response replaces the default reply to response.create. A response.cancel
+	// received while it plays stops it and sends response.done with status "cancelled".
+	Response []MockStep
+
+	// Responses, when set, are played for successive response.create events
+	// (the i-th request plays Responses[i]); later requests fall back to Response.
+	Responses [][]MockStep
+
+	// Speech is played once, after the first input_audio_buffer.append, to
+	// simulate server VAD (speech_started, speech_stopped, committed, ...).
+	Speech []MockStep
+}
+
+// SyntheticResponse returns response steps for an audio reply: response.created
+// immediately, the first of n 100 ms PCM16 audio deltas after firstDelta, the
+// rest every interval, then response.audio.done and response.done.
+func SyntheticResponse(firstDelta, interval time.Duration, n int) []MockStep {
+	const id, item = "resp_profile", "item_profile"
+	chunk := base64.StdEncoding.EncodeToString(make([]byte, PCM16BytesFor(100, DefaultSampleRate)))
+	steps := []MockStep{{Event: ResponseCreated{Type: "response.created", Response: ResponseObject{ID: id, Status: "in_progress"}}}}
+	for i := 0; i < n; i++ {
+		delay := interval
+		if i == 0 {
+			delay = firstDelta
+		}
+		steps = append(steps, MockStep{Delay: delay, Event: ResponseAudioDelta{Type: "response.audio.delta", ResponseID: id, ItemID: item, DeltaBase64: chunk}})
+	}
+	return append(steps,
+		MockStep{Event: ResponseAudioDone{Type: "response.audio.done", ResponseID: id, ItemID: item}},
+		MockStep{Event: ResponseDone{Type: "response.done", Response: ResponseObject{ID: id, Status: "completed"}}},
+	)
+}
+
+// MockStepsFromRecording converts the inbound events of a SessionRecorder
+// transcript into steps that keep the recorded spacing between events.
+func MockStepsFromRecording(r io.Reader) ([]MockStep, error) {
+	var steps []MockStep
+	var last time.Time
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxReplayLineSize)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var rec RecordedEvent
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, err
+		}
+		if rec.Direction != DirectionInbound {
+			continue
+		}
+		var delay time.Duration
+		if !last.IsZero() {
+			delay = rec.Time.Sub(last)
+		}
+		last = rec.Time
+		steps = append(steps, MockStep{Delay: delay, Event: rec.Event})
+	}
+	return steps, sc.Err()
+}
+
+// play sends steps in order with their delays until ctx is done. Each step is
+// written while holding sendMu, and only if ctx is still live, so nothing from a
+// stopped script follows an event written after it was stopped.
+func (ms *MockServer) play(ctx context.Context, conn *websocket.Conn, sendMu *sync.Mutex, steps []MockStep) {
+	for _, step := range steps {
+		if step.Delay > 0 {
+			t := time.NewTimer(step.Delay)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+		}
+		data, err := json.Marshal(step.Event)
+		if err != nil {
+			ms.t.Errorf("failed to marshal step: %v", err)
+			return
+		}
+		sendMu.Lock()
+		if ctx.Err() == nil {
+			// Write with a background context: canceling a write closes the connection
+			err = conn.Write(context.Background(), websocket.MessageText, data)
+		}
+		sendMu.Unlock()
+		if err != nil || ctx.Err() != nil {
+			return
+		}
+	}
 }
 
 // NewMockServer creates a new mock server for testing
@@ -35,12 +167,23 @@ func (ms *MockServer) URL() string {
 	return "ws" + strings.TrimPrefix(ms.server.URL, "http") + "/openai/realtime"
 }
 
+// Connections returns how many WebSocket connections the server has accepted
+func (ms *MockServer) Connections() int {
+	return int(ms.connections.Load())
+}
+
 // AddMessage adds a message that the server will send to clients
 func (ms *MockServer) AddMessage(msg interface{}) {
 	ms.messages = append(ms.messages, msg)
 }
 
 func (ms *MockServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("x-ms-request-id", "req_mock_123")
+	if ms.Reject.Load() {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
 	// Check for API key in header
 	if r.Header.Get("api-key") == "" && r.Header.Get("Authorization") == "" {
 		http.Error(w, "Missing authentication", http.StatusUnauthorized)
@@ -56,6 +199,24 @@ func (ms *MockServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer conn.Close(websocket.StatusNormalClosure, "")
+	conn.SetReadLimit(4 << 20) // Room for MaxAppendBytes of base64 audio
+	ms.connections.Add(1)
+	ms.liveMu.Lock()
+	if ms.live == nil {
+		ms.live = make(map[*websocket.Conn]bool)
+	}
+	ms.live[conn] = true
+	ms.liveMu.Unlock()
+	defer func() {
+		ms.liveMu.Lock()
+		delete(ms.live, conn)
+		ms.liveMu.Unlock()
+	}()
+
+	expiresAt := int64(1640995200)
+	if ms.SessionExpiresIn > 0 {
+		expiresAt = time.Now().Add(ms.SessionExpiresIn).Unix()
+	}
 
 	// Send initial session created event
 	sessionCreated := SessionCreated{
@@ -72,14 +233,17 @@ func (ms *MockServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			Model:      "gpt-4o-realtime-preview",
 			Modalities: []string{"text", "audio"},
 			Voice:      "alloy",
-			ExpiresAt:  1640995200,
+			ExpiresAt:  expiresAt,
 		},
 	}
 
 	data, _ := json.Marshal(sessionCreated)
 	err = conn.Write(r.Context(), websocket.MessageText, data)
 	if err != nil {
-		ms.t.Errorf("failed to write session created: %v", err)
+		// The client may already have dropped the connection (e.g. on reconnect)
+		if r.Context().Err() == nil {
+			ms.t.Errorf("failed to write session created: %v", err)
+		}
 		return
 	}
 
@@ -98,7 +262,19 @@ func (ms *MockServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Scripted profile playback runs alongside the read loop so cancels are seen
+	var speechOnce sync.Once
+	responseSeq := 0
+	var playing, sendMu sync.Mutex
+	stopResponse := func() {}
+	defer func() {
+		playing.Lock()
+		stopResponse()
+		playing.Unlock()
+	}()
+
 	// Keep connection alive and echo any received messages
+	itemSeq, lastItemID := 0, ""
 	for {
 		_, data, err := conn.Read(r.Context())
 		if err != nil {
@@ -111,21 +287,115 @@ func (ms *MockServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
+		if p := ms.Profile; p != nil {
+			switch {
+			case env.Type == "input_audio_buffer.append" && len(p.Speech) > 0:
+				speechOnce.Do(func() { go ms.play(r.Context(), conn, &sendMu, p.Speech) })
+				continue
+			case env.Type == "response.create" && (len(p.Response) > 0 || responseSeq < len(p.Responses)):
+				steps := p.Response
+				if responseSeq < len(p.Responses) {
+					steps = p.Responses[responseSeq]
+				}
+				responseSeq++
+				ctx, cancel := context.WithCancel(r.Context())
+				playing.Lock()
+				stopResponse()
+				stopResponse = cancel
+				playing.Unlock()
+				go ms.play(ctx, conn, &sendMu, steps)
+				continue
+			case env.Type == "response.cancel":
+				playing.Lock()
+				stopResponse()
+				playing.Unlock()
+				done, _ := json.Marshal(ResponseDone{Type: "response.done", Response: ResponseObject{ID: "resp_profile", Status: "cancelled"}})
+				sendMu.Lock()
+				_ = conn.Write(r.Context(), websocket.MessageText, done)
+				sendMu.Unlock()
+				continue
+			}
+		}
+
 		// Handle specific message types
 		switch env.Type {
 		case "session.update":
-			// Respond with session.updated
-			response := SessionUpdated{
-				Type:    "session.updated",
-				EventID: "evt_mock_session_updated",
-				Session: map[string]interface{}{"updated": true},
+			// Respond with session.updated, echoing the requested settings
+			var req struct {
+				Session map[string]any `json:"session"`
 			}
-			respData, _ := json.Marshal(response)
+			_ = json.Unmarshal(data, &req)
+			session := map[string]any{"id": "sess_mock_123", "object": "realtime.session", "model": "gpt-4o-realtime-preview"}
+			for k, v := range req.Session {
+				session[k] = v
+			}
+			respData, _ := json.Marshal(map[string]any{
+				"type":     "session.updated",
+				"event_id": "evt_mock_session_updated",
+				"session":  session,
+			})
 			if err := conn.Write(r.Context(), websocket.MessageText, respData); err != nil {
 				ms.t.Logf("Failed to write response: %v", err)
 			}
 
+		case "conversation.item.create":
+			// Assign an ID and append the item to the conversation
+			var req struct {
+				Item ConversationItem `json:"item"`
+			}
+			_ = json.Unmarshal(data, &req)
+			itemSeq++
+			req.Item.ID = fmt.Sprintf("item_mock_%d", itemSeq)
+			created := ConversationItemCreated{
+				Type:           "conversation.item.created",
+				EventID:        "evt_mock_item_created",
+				PreviousItemID: lastItemID,
+				Item:           req.Item,
+			}
+			lastItemID = req.Item.ID
+			createdData, _ := json.Marshal(created)
+			if err := conn.Write(r.Context(), websocket.MessageText, createdData); err != nil {
+				ms.t.Logf("Failed to write conversation.item.created: %v", err)
+			}
+
+		case "conversation.item.delete":
+			var req struct {
+				ItemID string `json:"item_id"`
+			}
+			_ = json.Unmarshal(data, &req)
+			deleted := ConversationItemDeleted{
+				Type:    "conversation.item.deleted",
+				EventID: "evt_mock_item_deleted",
+				ItemID:  req.ItemID,
+			}
+			deletedData, _ := json.Marshal(deleted)
+			if err := conn.Write(r.Context(), websocket.MessageText, deletedData); err != nil {
+				ms.t.Logf("Failed to write conversation.item.deleted: %v", err)
+			}
+
 		case "response.create":
+			// Echo the request metadata back on response.created/response.done
+			var req struct {
+				Response struct {
+					Metadata map[string]interface{} `json:"metadata"`
+				} `json:"response"`
+			}
+			_ = json.Unmarshal(data, &req)
+			created := ResponseCreated{
+				Type:    "response.created",
+				EventID: "evt_mock_response_created",
+				Response: ResponseObject{
+					ID:       "resp_mock_123",
+					Object:   "realtime.response",
+					Status:   "in_progress",
+					Metadata: req.Response.Metadata,
+				},
+			}
+			createdData, _ := json.Marshal(created)
+			if err := conn.Write(r.Context(), websocket.MessageText, createdData); err != nil {
+				ms.t.Logf("Failed to write response.created: %v", err)
+			}
+
 			// Respond with text delta and done events
 			textDelta := ResponseTextDelta{
 				Type:         "response.text.delta",
@@ -152,6 +422,32 @@ func (ms *MockServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			if err := conn.Write(r.Context(), websocket.MessageText, doneData); err != nil {
 				ms.t.Logf("Failed to write done: %v", err)
 			}
+
+			responseDone := ResponseDone{
+				Type:    "response.done",
+				EventID: "evt_mock_response_done",
+				Response: ResponseObject{
+					ID:       "resp_mock_123",
+					Object:   "realtime.response",
+					Status:   "completed",
+					Metadata: req.Response.Metadata,
+					Output: []ConversationItem{{
+						ID: "item_mock_456", Type: "message", Role: "assistant", Status: "completed",
+						Content: []ContentPart{{Type: "text", Text: "Hello from mock server!"}},
+					}},
+					Usage: &ResponseUsage{
+						TotalTokens:        150,
+						InputTokens:        100,
+						OutputTokens:       50,
+						InputTokenDetails:  &ResponseUsageInputTokens{CachedTokens: 20, TextTokens: 40, AudioTokens: 60, CachedTokensDetails: &ResponseUsageCachedTokens{TextTokens: 15, AudioTokens: 5}},
+						OutputTokenDetails: &ResponseUsageOutputTokens{TextTokens: 10, AudioTokens: 40},
+					},
+				},
+			}
+			responseDoneData, _ := json.Marshal(responseDone)
+			if err := conn.Write(r.Context(), websocket.MessageText, responseDoneData); err != nil {
+				ms.t.Logf("Failed to write response.done: %v", err)
+			}
 		}
 	}
 }
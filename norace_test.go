@@ -0,0 +1,5 @@
+//go:build !race
+
+package azrealtime
+
+const raceEnabled = false
@@ -0,0 +1,157 @@
+package azrealtime
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DefaultAPIVersion is the Azure OpenAI API version used by NewConfig when
+// WithAPIVersion is not given.
+const DefaultAPIVersion = "2025-04-01-preview"
+
+// Option configures a Config built by NewConfig or DialWith.
+type Option func(*Config)
+
+// NewConfig builds a Config for endpoint and deployment, applying opts in order.
+// APIVersion defaults to DefaultAPIVersion.
+func NewConfig(endpoint, deployment string, opts ...Option) Config {
+	cfg := Config{
+		ResourceEndpoint: endpoint,
+		Deployment:       deployment,
+		APIVersion:       DefaultAPIVersion,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// DialWith is like Dial, but builds the configuration from functional options:
+//
+//	client, err := azrealtime.DialWith(ctx, endpoint, deployment,
+//		azrealtime.WithAPIKey(key),
+//		azrealtime.WithTimeout(15*time.Second),
+//	)
+func DialWith(ctx context.Context, endpoint, deployment string, opts ...Option) (*Client, error) {
+	return Dial(ctx, NewConfig(endpoint, deployment, opts...))
+}
+
+// WithProvider selects the realtime service (see Config.Provider).
+func WithProvider(p Provider) Option {
+	return func(c *Config) { c.Provider = p }
+}
+
+// WithAPIVersion sets Config.APIVersion.
+func WithAPIVersion(v string) Option {
+	return func(c *Config) { c.APIVersion = v }
+}
+
+// WithCredential sets Config.Credential.
+func WithCredential(cred Credential) Option {
+	return func(c *Config) { c.Credential = cred }
+}
+
+// WithAPIKey authenticates with an API key.
+func WithAPIKey(key string) Option {
+	return WithCredential(APIKey(key))
+}
+
+// WithBearer authenticates with a Bearer token.
+func WithBearer(token string) Option {
+	return WithCredential(Bearer(token))
+}
+
+// WithTimeout sets Config.DialTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Config) { c.DialTimeout = d }
+}
+
+// WithHandshakeHeader adds a header to the WebSocket handshake request.
+func WithHandshakeHeader(key, value string) Option {
+	return func(c *Config) {
+		if c.HandshakeHeaders == nil {
+			c.HandshakeHeaders = http.Header{}
+		}
+		c.HandshakeHeaders.Add(key, value)
+	}
+}
+
+// WithLogger sets Config.StructuredLogger.
+func WithLogger(l StructuredLogger) Option {
+	return func(c *Config) { c.StructuredLogger = l }
+}
+
+// WithLogSampling sets Config.LogSampling.
+func WithLogSampling(s LogSampling) Option {
+	return func(c *Config) { c.LogSampling = &s }
+}
+
+// WithCorrelationID sets Config.CorrelationID.
+func WithCorrelationID(id string) Option {
+	return func(c *Config) { c.CorrelationID = id }
+}
+
+// WithLogFunc sets the simple Config.Logger callback.
+func WithLogFunc(fn func(event string, fields map[string]any)) Option {
+	return func(c *Config) { c.Logger = fn }
+}
+
+// WithSessionExpiryWarning sets Config.SessionExpiryWarning.
+func WithSessionExpiryWarning(d time.Duration) Option {
+	return func(c *Config) { c.SessionExpiryWarning = d }
+}
+
+// WithReconnectOnSessionExpiry enables Config.ReconnectOnSessionExpiry.
+func WithReconnectOnSessionExpiry() Option {
+	return func(c *Config) { c.ReconnectOnSessionExpiry = true }
+}
+
+// WithAutoReconnect sets Config.AutoReconnect.
+func WithAutoReconnect(policy AutoReconnectConfig) Option {
+	return func(c *Config) { c.AutoReconnect = &policy }
+}
+
+// WithStatsHandler sets Config.StatsHandler and Config.StatsInterval.
+// A zero interval uses DefaultStatsInterval.
+func WithStatsHandler(fn func(Stats), interval time.Duration) Option {
+	return func(c *Config) {
+		c.StatsHandler = fn
+		c.StatsInterval = interval
+	}
+}
+
+// WithReconnectOnErrors sets Config.ReconnectOnErrors.
+func WithReconnectOnErrors(classes ...ErrorClass) Option {
+	return func(c *Config) { c.ReconnectOnErrors = classes }
+}
+
+// WithStreamIntegrityChecks enables Config.StreamIntegrityChecks.
+func WithStreamIntegrityChecks() Option {
+	return func(c *Config) { c.StreamIntegrityChecks = true }
+}
+
+// WithAdaptiveLimiter sets Config.AdaptiveLimiter.
+func WithAdaptiveLimiter(l *AdaptiveLimiter) Option {
+	return func(c *Config) { c.AdaptiveLimiter = l }
+}
+
+// WithValidationMode sets Config.ValidationMode.
+func WithValidationMode(m ValidationMode) Option {
+	return func(c *Config) { c.ValidationMode = m }
+}
+
+// WithPricing sets Config.Pricing, enabling cost estimates in SessionReport.
+func WithPricing(p Pricing) Option {
+	return func(c *Config) { c.Pricing = &p }
+}
+
+// WithRateLimitMode sets Config.RateLimitMode.
+func WithRateLimitMode(m RateLimitMode) Option {
+	return func(c *Config) { c.RateLimitMode = m }
+}
+
+// WithMaxMessageBytes sets Config.MaxMessageBytes.
+func WithMaxMessageBytes(n int64) Option {
+	return func(c *Config) { c.MaxMessageBytes = n }
+}
@@ -0,0 +1,78 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewConfig_Options(t *testing.T) {
+	logger := NewLogger(LogLevelWarn)
+	limiter := NewAdaptiveLimiter(AdaptiveLimiterConfig{})
+	cfg := NewConfig("https://test.openai.azure.com", "gpt-4o-realtime",
+		WithAPIKey("test-key"),
+		WithTimeout(15*time.Second),
+		WithLogger(logger),
+		WithHandshakeHeader("X-Trace", "a"),
+		WithHandshakeHeader("X-Trace", "b"),
+		WithReconnectOnSessionExpiry(),
+		WithReconnectOnErrors(ErrorClassSessionExpired, ErrorClassInvalidState),
+		WithStreamIntegrityChecks(),
+		WithAdaptiveLimiter(limiter),
+		WithValidationMode(ValidationWarn),
+		WithPricing(Pricing{TextInputPerMillion: 5}),
+		WithRateLimitMode(RateLimitWait),
+		WithMaxMessageBytes(1<<20),
+	)
+
+	if cfg.APIVersion != DefaultAPIVersion {
+		t.Errorf("expected default API version, got %q", cfg.APIVersion)
+	}
+	if cfg.Credential != APIKey("test-key") || cfg.DialTimeout != 15*time.Second {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.StructuredLogger != logger || !cfg.ReconnectOnSessionExpiry || len(cfg.ReconnectOnErrors) != 2 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if !cfg.StreamIntegrityChecks || cfg.AdaptiveLimiter != limiter || cfg.ValidationMode != ValidationWarn {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.RateLimitMode != RateLimitWait || cfg.MaxMessageBytes != 1<<20 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.Pricing == nil || cfg.Pricing.TextInputPerMillion != 5 {
+		t.Errorf("unexpected pricing: %+v", cfg.Pricing)
+	}
+	if got := cfg.HandshakeHeaders.Values("X-Trace"); len(got) != 2 {
+		t.Errorf("expected two header values, got %v", got)
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	// Later options override earlier ones
+	cfg = NewConfig("https://test.openai.azure.com", "d", WithAPIKey("k"), WithBearer("t"), WithAPIVersion("2024-10-01-preview"))
+	if cfg.Credential != Bearer("t") || cfg.APIVersion != "2024-10-01-preview" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestDialWith(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint := CreateMockConfig(mockServer.URL()).ResourceEndpoint
+	client, err := DialWith(ctx, endpoint, "test-deployment", WithAPIKey("test-key"), WithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("DialWith: %v", err)
+	}
+	client.Close()
+
+	if _, err := DialWith(ctx, endpoint, "test-deployment"); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected config error without credential, got %v", err)
+	}
+}
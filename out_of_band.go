@@ -0,0 +1,81 @@
+package azrealtime
+
+import (
+	"context"
+	"sync"
+)
+
+// Values for CreateResponseOptions.Conversation.
+const (
+	ConversationAuto = "auto" // Add the response to the default conversation (the server default)
+	ConversationNone = "none" // Out-of-band: the response is not added to any conversation
+)
+
+// ResponseOutOfBandMetadataKey marks responses created by
+// CreateOutOfBandResponse in their metadata; see IsOutOfBand.
+const ResponseOutOfBandMetadataKey = "azrealtime_oob"
+
+// CreateOutOfBandResponse requests a response that is not written into the
+// conversation (Conversation "none"), e.g. to classify or moderate the last
+// user turn on the same session without adding to the dialogue history.
+//
+// By default the model sees the current conversation as context; set
+// opts.Input to give it explicit items instead (see NewItemReference). The
+// response is marked in its metadata so IsOutOfBand recognizes it, and the
+// client's own bookkeeping (Transcript, UseTools) ignores it. Await the result
+// with a ResponseTracker and the returned tag:
+//
+//	tracker := client.TrackResponses()
+//	_, tag, err := client.CreateOutOfBandResponse(ctx, azrealtime.CreateResponseOptions{
+//		Modalities:   []string{"text"},
+//		Instructions: "Classify the user's last message as billing, support or other. Reply with one word.",
+//	})
+//	resp, err := tracker.AwaitTag(ctx, tag)
+func (c *Client) CreateOutOfBandResponse(ctx context.Context, opts CreateResponseOptions) (eventID, tag string, err error) {
+	opts.Conversation = ConversationNone
+	md := make(map[string]any, len(opts.Metadata)+1)
+	for k, v := range opts.Metadata {
+		md[k] = v
+	}
+	md[ResponseOutOfBandMetadataKey] = "true"
+	opts.Metadata = md
+	return c.CreateTaggedResponse(ctx, opts)
+}
+
+// IsOutOfBand reports whether r was created with CreateOutOfBandResponse.
+func IsOutOfBand(r ResponseObject) bool {
+	v, _ := r.Metadata[ResponseOutOfBandMetadataKey].(string)
+	return v == "true"
+}
+
+// outOfBandTracker remembers the IDs of in-progress out-of-band responses so
+// their events can be told apart from conversation output.
+type outOfBandTracker struct {
+	mu  sync.Mutex
+	ids map[string]bool
+}
+
+func (t *outOfBandTracker) responseCreated(r ResponseObject) {
+	if !IsOutOfBand(r) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ids == nil {
+		t.ids = make(map[string]bool)
+	}
+	t.ids[r.ID] = true
+}
+
+func (t *outOfBandTracker) responseDone(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.ids, id)
+}
+
+// has reports whether responseID belongs to an out-of-band response.
+func (t *outOfBandTracker) has(responseID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.ids[responseID]
+}
@@ -0,0 +1,91 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_CreateOutOfBandResponse(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	sent := recordOutbound(client)
+	tracker := client.TrackResponses()
+	defer tracker.Stop()
+
+	dones := make(chan ResponseObject, 4)
+	client.OnResponseDone(func(e ResponseDone) { dones <- e.Response })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, tag, err := client.CreateOutOfBandResponse(ctx, CreateResponseOptions{
+		Modalities:   []string{"text"},
+		Instructions: "Classify the last message.",
+		Metadata:     map[string]any{"purpose": "classification"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOutOfBandResponse: %v", err)
+	}
+	resp, err := tracker.AwaitTag(ctx, tag)
+	if err != nil {
+		t.Fatalf("AwaitTag: %v", err)
+	}
+	if resp.Text != "Hello from mock server!" {
+		t.Errorf("text = %q", resp.Text)
+	}
+	if done := <-dones; !IsOutOfBand(done) || done.Metadata["purpose"] != "classification" {
+		t.Errorf("response.done metadata = %v", done.Metadata)
+	}
+
+	creates := sent.ofType("response.create")
+	if len(creates) != 1 {
+		t.Fatalf("sent %d response.create, want 1", len(creates))
+	}
+	if conv := creates[0]["response"].(map[string]any)["conversation"]; conv != ConversationNone {
+		t.Errorf("conversation = %v, want none", conv)
+	}
+
+	// Out-of-band output is not part of the conversation transcript
+	if entries := client.Transcript().Entries; len(entries) != 0 {
+		t.Errorf("transcript has out-of-band entries: %+v", entries)
+	}
+	if client.outOfBand.has(resp.ID) {
+		t.Error("finished response is still tracked as in progress")
+	}
+
+	// Regular responses are still transcribed
+	if _, err := client.Ask(ctx, "Hi"); err != nil {
+		t.Fatalf("Ask: %v", err)
+	}
+	if IsOutOfBand(<-dones) {
+		t.Error("regular response reported as out-of-band")
+	}
+	found := false
+	for _, e := range client.Transcript().Entries {
+		found = found || (e.Role == "assistant" && e.Text == "Hello from mock server!")
+	}
+	if !found {
+		t.Errorf("assistant reply missing from transcript: %+v", client.Transcript().Entries)
+	}
+}
+
+func TestValidateCreateResponseOptions_Metadata(t *testing.T) {
+	valid := CreateResponseOptions{Conversation: ConversationNone, Metadata: map[string]any{"purpose": "moderation"}}
+	if err := ValidateCreateResponseOptions(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := ValidateCreateResponseOptions(CreateResponseOptions{Metadata: map[string]any{
+		"count":                 3,
+		strings.Repeat("k", 65): "v",
+		"long":                  strings.Repeat("v", 513),
+	}})
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 3 {
+		t.Fatalf("expected 3 validation errors, got %v", err)
+	}
+	if verrs[0].Field != `metadata["count"]` || verrs[0].Code != ValidationCodeInvalidValue {
+		t.Errorf("first error = %+v", verrs[0])
+	}
+}
@@ -0,0 +1,304 @@
+package azrealtime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Chunk size bounds used by PacedAppender when the corresponding option is zero.
+const (
+	DefaultMinChunkMS = 20
+	DefaultMaxChunkMS = 100
+)
+
+// Chunking regimes reported in PacedAppenderMetrics.Regime.
+const (
+	RegimeLowLatency  = "low_latency"  // Smallest chunks: the link is fast and has headroom
+	RegimeBalanced    = "balanced"     // Intermediate chunk size
+	RegimeDegraded    = "degraded"     // Largest chunks: sends are slow relative to chunk duration
+	RegimeRateLimited = "rate_limited" // Largest chunks: rate-limit headroom is low
+)
+
+// PacedAppenderOptions configures a PacedAppender.
+type PacedAppenderOptions struct {
+	// SampleRate of the PCM16 input. Default: DefaultSampleRate.
+	SampleRate int
+
+	// MinChunkMS and MaxChunkMS bound the chunk duration in milliseconds.
+	// Defaults: DefaultMinChunkMS and DefaultMaxChunkMS.
+	MinChunkMS int
+	MaxChunkMS int
+
+	// ChunkMS is the initial chunk duration. Default: MinChunkMS.
+	ChunkMS int
+
+	// Adaptive enables automatic chunk sizing from measured send latency,
+	// ping RTT and rate-limit headroom. When false, ChunkMS is used throughout.
+	Adaptive bool
+
+	// RealTime paces sends at wall-clock speed, so each chunk is sent no earlier
+	// than the moment its audio would be played back live.
+	RealTime bool
+
+	// Speed scales RealTime pacing: 2.0 sends audio twice as fast as it would
+	// play back. Default: 1.0.
+	Speed float64
+
+	// LowHeadroom is the remaining rate-limit fraction (0.0-1.0) below which the
+	// appender switches to the largest chunks to reduce message count. Default: 0.1.
+	LowHeadroom float64
+}
+
+// PacedAppenderMetrics describes the chunking regime a PacedAppender has chosen.
+type PacedAppenderMetrics struct {
+	ChunkMS       int           // Current chunk duration in milliseconds
+	Regime        string        // One of the Regime constants
+	SendLatency   time.Duration // Smoothed time taken by each append to be written
+	RTT           time.Duration // Last ping round-trip time reported by the client
+	RateHeadroom  float64       // Lowest remaining/limit fraction across reported rate limits (1.0 if unknown)
+	ChunksSent    uint64        // Number of append messages sent
+	Adjustments   uint64        // Number of times the chunk size changed
+	BytesBuffered int           // PCM bytes waiting for a full chunk
+}
+
+// PacedAppender slices PCM16 audio into input_audio_buffer.append messages.
+// In adaptive mode it trades latency for per-message overhead automatically:
+// chunks shrink toward MinChunkMS while sends are fast, and grow toward
+// MaxChunkMS when send latency rises or rate-limit headroom runs low.
+//
+// A PacedAppender is safe for concurrent use, but audio from concurrent
+// writers is interleaved in call order. Metrics does not wait for a write
+// that is pacing or sending.
+type PacedAppender struct {
+	client *Client
+	opts   PacedAppenderOptions
+
+	// sendMu orders Write and Flush calls and is held across pacing and
+	// sends. It guards the pacing state and is taken before mu.
+	sendMu    sync.Mutex
+	started   time.Time     // Wall-clock start of real-time pacing
+	sentAudio time.Duration // Audio duration sent since started
+
+	// mu guards the buffer and measurements; it is never held while waiting
+	// or sending.
+	mu          sync.Mutex
+	buf         []byte
+	chunkMS     int
+	regime      string
+	latency     time.Duration // Exponentially weighted moving average of send latency
+	headroom    float64
+	chunksSent  uint64
+	adjustments uint64
+}
+
+// NewPacedAppender creates a PacedAppender that sends audio through c.
+func NewPacedAppender(c *Client, opts PacedAppenderOptions) *PacedAppender {
+	if opts.SampleRate <= 0 {
+		opts.SampleRate = DefaultSampleRate
+	}
+	if opts.MinChunkMS <= 0 {
+		opts.MinChunkMS = DefaultMinChunkMS
+	}
+	if opts.MaxChunkMS < opts.MinChunkMS {
+		opts.MaxChunkMS = DefaultMaxChunkMS
+		if opts.MaxChunkMS < opts.MinChunkMS {
+			opts.MaxChunkMS = opts.MinChunkMS
+		}
+	}
+	if opts.ChunkMS <= 0 {
+		opts.ChunkMS = opts.MinChunkMS
+	}
+	if opts.ChunkMS < opts.MinChunkMS {
+		opts.ChunkMS = opts.MinChunkMS
+	}
+	if opts.ChunkMS > opts.MaxChunkMS {
+		opts.ChunkMS = opts.MaxChunkMS
+	}
+	if opts.LowHeadroom <= 0 {
+		opts.LowHeadroom = 0.1
+	}
+	if opts.Speed <= 0 {
+		opts.Speed = 1
+	}
+	p := &PacedAppender{client: c, opts: opts, chunkMS: opts.ChunkMS, headroom: 1}
+	p.regime = p.regimeFor(p.chunkMS, false)
+	return p
+}
+
+// Write buffers pcm and sends every complete chunk. A trailing partial chunk
+// stays buffered until more audio arrives or Flush is called.
+func (p *PacedAppender) Write(ctx context.Context, pcm []byte) error {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+	p.buf = append(p.buf, pcm...)
+	p.mu.Unlock()
+	for {
+		// Only writers, serialized by sendMu, change buf's contents, so the
+		// chunk stays valid after mu is released
+		p.mu.Lock()
+		n, chunk := p.chunkBytes(), p.buf
+		p.mu.Unlock()
+		if len(chunk) < n {
+			return nil
+		}
+		if err := p.send(ctx, chunk[:n]); err != nil {
+			return err
+		}
+		p.consume(n)
+	}
+}
+
+// Flush sends any buffered audio, even if it is shorter than a full chunk.
+func (p *PacedAppender) Flush(ctx context.Context) error {
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	p.mu.Lock()
+	chunk := p.buf[:len(p.buf)&^1] // keep whole samples only
+	p.mu.Unlock()
+	if len(chunk) == 0 {
+		return nil
+	}
+	if err := p.send(ctx, chunk); err != nil {
+		return err
+	}
+	p.consume(len(chunk))
+	return nil
+}
+
+// consume removes n sent bytes from the front of the buffer.
+func (p *PacedAppender) consume(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.buf = p.buf[n:]
+}
+
+// Metrics returns the appender's current chunking regime and measurements.
+func (p *PacedAppender) Metrics() PacedAppenderMetrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PacedAppenderMetrics{
+		ChunkMS:       p.chunkMS,
+		Regime:        p.regime,
+		SendLatency:   p.latency,
+		RTT:           p.client.RTT(),
+		RateHeadroom:  p.headroom,
+		ChunksSent:    p.chunksSent,
+		Adjustments:   p.adjustments,
+		BytesBuffered: len(p.buf),
+	}
+}
+
+// chunkBytes returns the size of one chunk in bytes at the current chunk
+// duration. p.mu must be held.
+func (p *PacedAppender) chunkBytes() int {
+	return PCM16BytesFor(p.chunkMS, p.opts.SampleRate)
+}
+
+// send paces, sends one chunk and adapts the chunk size. p.sendMu must be
+// held; p.mu is taken only to record the measurements.
+func (p *PacedAppender) send(ctx context.Context, chunk []byte) error {
+	if p.opts.RealTime {
+		if p.started.IsZero() {
+			p.started = time.Now()
+		}
+		due := p.started.Add(time.Duration(float64(p.sentAudio) / p.opts.Speed))
+		if wait := time.Until(due); wait > 0 {
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return NewSendError("input_audio_buffer.append", "", ctx.Err())
+			case <-t.C:
+			}
+		}
+	}
+
+	start := time.Now()
+	if err := p.client.AppendPCM16(ctx, chunk); err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+	p.sentAudio += time.Duration(len(chunk)/2) * time.Second / time.Duration(p.opts.SampleRate)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.chunksSent++
+	if p.latency == 0 {
+		p.latency = elapsed
+	} else {
+		p.latency = (p.latency*7 + elapsed) / 8
+	}
+	if p.opts.Adaptive {
+		p.adapt()
+	}
+	return nil
+}
+
+// adapt picks the next chunk size from the latest latency and headroom
+// measurements. p.mu must be held.
+func (p *PacedAppender) adapt() {
+	if rl, ok := p.client.LastRateLimits(); ok {
+		p.headroom = rateLimitHeadroom(rl)
+	}
+
+	signal := p.latency
+	if rtt := p.client.RTT(); rtt/2 > signal {
+		signal = rtt / 2
+	}
+	chunkDur := time.Duration(p.chunkMS) * time.Millisecond
+
+	next := p.chunkMS
+	rateLimited := p.headroom < p.opts.LowHeadroom
+	switch {
+	case rateLimited:
+		next = p.opts.MaxChunkMS
+	case signal > chunkDur/2:
+		// Sending takes a large share of the chunk's duration: batch more audio per message
+		next = p.chunkMS * 2
+	case signal < chunkDur/8:
+		// Plenty of slack: shrink toward the low-latency end
+		next = p.chunkMS - p.opts.MinChunkMS
+	}
+	if next < p.opts.MinChunkMS {
+		next = p.opts.MinChunkMS
+	}
+	if next > p.opts.MaxChunkMS {
+		next = p.opts.MaxChunkMS
+	}
+	if next != p.chunkMS {
+		p.chunkMS = next
+		p.adjustments++
+	}
+	p.regime = p.regimeFor(p.chunkMS, rateLimited)
+}
+
+// regimeFor names the regime for a chunk size.
+func (p *PacedAppender) regimeFor(chunkMS int, rateLimited bool) string {
+	switch {
+	case rateLimited:
+		return RegimeRateLimited
+	case chunkMS <= p.opts.MinChunkMS:
+		return RegimeLowLatency
+	case chunkMS >= p.opts.MaxChunkMS:
+		return RegimeDegraded
+	default:
+		return RegimeBalanced
+	}
+}
+
+// rateLimitHeadroom returns the lowest remaining/limit fraction across the
+// reported rate limits, or 1.0 if none carry a limit.
+func rateLimitHeadroom(rl RateLimitsUpdated) float64 {
+	headroom := 1.0
+	for _, l := range rl.RateLimits {
+		if l.Limit <= 0 {
+			continue
+		}
+		if f := float64(l.Remaining) / float64(l.Limit); f < headroom {
+			headroom = f
+		}
+	}
+	return headroom
+}
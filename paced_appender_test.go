@@ -0,0 +1,138 @@
+package azrealtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPacedAppender_Chunking(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	p := NewPacedAppender(client, PacedAppenderOptions{ChunkMS: 20})
+	// 110ms of audio: five 20ms chunks plus 10ms left over
+	if err := p.Write(ctx, make([]byte, PCM16BytesFor(110, DefaultSampleRate))); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	m := p.Metrics()
+	if m.ChunksSent != 5 || m.BytesBuffered != PCM16BytesFor(10, DefaultSampleRate) {
+		t.Errorf("unexpected metrics after write: %+v", m)
+	}
+	if err := p.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if m := p.Metrics(); m.ChunksSent != 6 || m.BytesBuffered != 0 {
+		t.Errorf("unexpected metrics after flush: %+v", m)
+	}
+	if got := client.Stats().AudioInSeconds; got < 0.109 || got > 0.111 {
+		t.Errorf("expected 0.11s of audio sent, got %v", got)
+	}
+}
+
+func TestPacedAppender_RealTime(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	p := NewPacedAppender(client, PacedAppenderOptions{ChunkMS: 20, RealTime: true})
+	start := time.Now()
+	if err := p.Write(ctx, make([]byte, PCM16BytesFor(100, DefaultSampleRate))); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	// The fifth chunk may not be sent before 80ms of wall-clock time has passed
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("expected real-time pacing, finished in %v", elapsed)
+	}
+}
+
+func TestPacedAppender_Adapt(t *testing.T) {
+	p := NewPacedAppender(&Client{}, PacedAppenderOptions{Adaptive: true})
+	if m := p.Metrics(); m.ChunkMS != DefaultMinChunkMS || m.Regime != RegimeLowLatency {
+		t.Fatalf("unexpected initial metrics: %+v", m)
+	}
+
+	// Slow sends grow the chunk size up to the maximum
+	p.latency = 50 * time.Millisecond
+	for i := 0; i < 5; i++ {
+		p.adapt()
+	}
+	if m := p.Metrics(); m.ChunkMS != DefaultMaxChunkMS || m.Regime != RegimeDegraded {
+		t.Errorf("expected degraded regime, got %+v", p.Metrics())
+	}
+
+	// Fast sends shrink it back
+	p.latency = time.Millisecond
+	for i := 0; i < 10; i++ {
+		p.adapt()
+	}
+	if m := p.Metrics(); m.ChunkMS != DefaultMinChunkMS || m.Regime != RegimeLowLatency {
+		t.Errorf("expected low-latency regime, got %+v", p.Metrics())
+	}
+
+	// Low rate-limit headroom forces the largest chunks
+	var rl RateLimitsUpdated
+	rl.RateLimits = append(rl.RateLimits, RateLimit{Name: "requests", Limit: 100, Remaining: 5})
+	p.client.rateLimits = &rl
+	p.adapt()
+	if m := p.Metrics(); m.ChunkMS != DefaultMaxChunkMS || m.Regime != RegimeRateLimited || m.RateHeadroom != 0.05 {
+		t.Errorf("expected rate-limited regime, got %+v", m)
+	}
+}
+
+func TestPacedAppender_MetricsWhilePacing(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	// At 1% speed the second 20ms chunk is not due for two seconds
+	p := NewPacedAppender(client, PacedAppenderOptions{ChunkMS: 20, RealTime: true, Speed: 0.01})
+	writeCtx, stop := context.WithCancel(ctx)
+	written := make(chan error, 1)
+	go func() { written <- p.Write(writeCtx, make([]byte, PCM16BytesFor(40, DefaultSampleRate))) }()
+
+	waitUntil(t, "first chunk sent", func() bool {
+		metrics := make(chan PacedAppenderMetrics, 1)
+		go func() { metrics <- p.Metrics() }()
+		select {
+		case m := <-metrics:
+			return m.ChunksSent == 1
+		case <-time.After(time.Second):
+			t.Fatal("Metrics blocked while Write was pacing")
+			return false
+		}
+	})
+
+	stop()
+	if err := <-written; err == nil {
+		t.Error("Write returned nil after its context was canceled")
+	}
+	if m := p.Metrics(); m.ChunksSent != 1 || m.BytesBuffered != PCM16BytesFor(20, DefaultSampleRate) {
+		t.Errorf("unexpected metrics after cancel: %+v", m)
+	}
+}
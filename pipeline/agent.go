@@ -0,0 +1,223 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/enesunal-m/azrealtime"
+)
+
+// Agent runs a Pipeline on a connected client: it applies the session, executes
+// tool calls, records the session, and writes assistant output to the sinks.
+//
+// Start takes over the client's OnRawEvent (when recording), OnResponseTextDelta,
+// OnResponseAudioTranscriptDelta, OnResponseAudioDelta, OnResponseDone and
+// OnError handlers.
+type Agent struct {
+	p      *Pipeline
+	client *azrealtime.Client
+	ctx    context.Context
+
+	text      io.Writer
+	closers   []io.Closer
+	recorder  *azrealtime.SessionRecorder
+	audioPath string
+
+	mu            sync.Mutex
+	audio         []byte     // Assistant PCM16 audio written to Outputs.Audio on Close
+	maxAudioBytes int        // Cap on audio, from Outputs.MaxAudio
+	audioDropped  bool       // Whether audio past the cap was dropped
+	errs          chan error // Asynchronous tool and server errors
+	wg            sync.WaitGroup
+}
+
+// Start configures c according to p and begins handling events. Call Close when
+// done to flush the outputs.
+func Start(ctx context.Context, p *Pipeline, c *azrealtime.Client) (*Agent, error) {
+	a := &Agent{p: p, client: c, ctx: ctx, audioPath: p.Outputs.Audio, errs: make(chan error, 16)}
+	a.maxAudioBytes = azrealtime.PCM16BytesFor(int(p.Outputs.maxAudio().Milliseconds()), azrealtime.DefaultSampleRate)
+
+	switch p.Outputs.Text {
+	case "", "stdout":
+		a.text = os.Stdout
+	case "stderr":
+		a.text = os.Stderr
+	case "none":
+		a.text = io.Discard
+	default:
+		f, err := os.Create(p.Outputs.Text)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline: text output: %w", err)
+		}
+		a.text = f
+		a.closers = append(a.closers, f)
+	}
+
+	if r := p.Recording; r != nil {
+		f, err := os.Create(r.Path)
+		if err != nil {
+			a.closeFiles()
+			return nil, fmt.Errorf("pipeline: recording: %w", err)
+		}
+		a.closers = append(a.closers, f)
+		opts := azrealtime.SessionRecorderOptions{}
+		if r.Audio == "elide" {
+			opts.AudioMode = azrealtime.AudioElide
+		}
+		a.recorder = azrealtime.NewSessionRecorder(f, opts)
+		a.recorder.Attach(c)
+	}
+
+	c.OnResponseTextDelta(func(e azrealtime.ResponseTextDelta) { _, _ = io.WriteString(a.text, e.Delta) })
+	c.OnResponseAudioTranscriptDelta(func(e azrealtime.ResponseAudioTranscriptDelta) { _, _ = io.WriteString(a.text, e.Delta) })
+	c.OnResponseAudioDelta(a.onAudioDelta)
+	c.OnResponseDone(a.onResponseDone)
+	c.OnError(func(e azrealtime.ErrorEvent) { a.report(fmt.Errorf("server error: %s", e.Error.Message)) })
+
+	if err := c.SessionUpdate(ctx, p.SessionConfig()); err != nil {
+		a.closeFiles()
+		return nil, err
+	}
+	return a, nil
+}
+
+// Errors delivers tool failures and server error events as they occur.
+// Errors are dropped if the channel is not drained.
+func (a *Agent) Errors() <-chan error {
+	return a.errs
+}
+
+// Say sends text as a user message and requests a response.
+func (a *Agent) Say(ctx context.Context, text string) error {
+	if err := a.client.CreateConversationItem(ctx, azrealtime.NewUserTextItem(text)); err != nil {
+		return err
+	}
+	_, err := a.client.CreateResponse(ctx, a.responseOptions())
+	return err
+}
+
+// Close waits for running tools, writes buffered audio and closes output files.
+// It does not close the client.
+func (a *Agent) Close() error {
+	a.wg.Wait()
+	var err error
+	if a.audioPath != "" {
+		a.mu.Lock()
+		wav := azrealtime.WAVFromPCM16Mono(a.audio, azrealtime.DefaultSampleRate)
+		a.mu.Unlock()
+		if werr := os.WriteFile(a.audioPath, wav, 0o644); werr != nil {
+			err = fmt.Errorf("pipeline: audio output: %w", werr)
+		}
+	}
+	if a.recorder != nil && err == nil {
+		err = a.recorder.Err()
+	}
+	if cerr := a.closeFiles(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// responseOptions returns the modalities matching the configured outputs.
+func (a *Agent) responseOptions() azrealtime.CreateResponseOptions {
+	if a.audioPath != "" {
+		return azrealtime.CreateResponseOptions{Modalities: []string{"text", "audio"}}
+	}
+	return azrealtime.CreateResponseOptions{Modalities: []string{"text"}}
+}
+
+func (a *Agent) onAudioDelta(e azrealtime.ResponseAudioDelta) {
+	if a.audioPath == "" {
+		return
+	}
+	pcm, err := base64.StdEncoding.DecodeString(e.DeltaBase64)
+	if err != nil {
+		a.report(fmt.Errorf("decode audio delta: %w", err))
+		return
+	}
+	a.mu.Lock()
+	room := a.maxAudioBytes - len(a.audio)
+	dropped := len(pcm) > room && !a.audioDropped
+	if len(pcm) > room {
+		pcm = pcm[:max(room, 0)&^1] // Whole samples only
+		a.audioDropped = true
+	}
+	a.audio = append(a.audio, pcm...)
+	a.mu.Unlock()
+	if dropped {
+		a.report(fmt.Errorf("audio output: longer than %v, dropping the rest", a.p.Outputs.maxAudio()))
+	}
+}
+
+// onResponseDone runs the tools called by the response, returns their output to
+// the model and requests a follow-up response. Tools run off the read loop.
+func (a *Agent) onResponseDone(e azrealtime.ResponseDone) {
+	_, _ = io.WriteString(a.text, "\n")
+
+	var calls []azrealtime.ConversationItem
+	for _, item := range e.Response.Output {
+		if item.Type == "function_call" {
+			calls = append(calls, item)
+		}
+	}
+	if len(calls) == 0 {
+		return
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		for _, call := range calls {
+			output, err := a.runTool(call.Name, call.Arguments)
+			if err != nil {
+				a.report(err)
+				output = fmt.Sprintf(`{"error":%q}`, err.Error())
+			}
+			item := azrealtime.ConversationItem{Type: "function_call_output", CallID: call.CallID, Output: output}
+			if err := a.client.CreateConversationItem(a.ctx, item); err != nil {
+				a.report(err)
+				return
+			}
+		}
+		if _, err := a.client.CreateResponse(a.ctx, a.responseOptions()); err != nil {
+			a.report(err)
+		}
+	}()
+}
+
+// runTool executes the named tool with args and returns its output.
+func (a *Agent) runTool(name, args string) (string, error) {
+	for _, t := range a.p.Tools {
+		if t.Name == name {
+			out, err := t.Handler()(a.ctx, args)
+			if err != nil {
+				return "", fmt.Errorf("tool %q: %w", name, err)
+			}
+			return out, nil
+		}
+	}
+	return "", fmt.Errorf("tool %q: not declared in pipeline", name)
+}
+
+// report delivers err on the Errors channel without blocking.
+func (a *Agent) report(err error) {
+	select {
+	case a.errs <- err:
+	default:
+	}
+}
+
+func (a *Agent) closeFiles() error {
+	var err error
+	for _, c := range a.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	a.closers = nil
+	return err
+}
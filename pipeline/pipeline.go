@@ -0,0 +1,379 @@
+// Package pipeline drives an azrealtime voice agent from a declarative YAML file.
+//
+// A pipeline file describes the connection, the session configuration, tools
+// backed by shell commands, session recording, and where assistant text and
+// audio are written, so a working agent can be stood up without writing Go:
+//
+//	connection:
+//	  endpoint: ${AZURE_OPENAI_ENDPOINT}
+//	  deployment: gpt-4o-realtime
+//	  api_version: 2025-04-01-preview
+//	  api_key_env: AZURE_OPENAI_API_KEY
+//	session:
+//	  voice: alloy
+//	  instructions: You are a helpful support agent.
+//	tools:
+//	  - name: lookup_order
+//	    description: Look up an order by ID
+//	    parameters: {type: object, properties: {order_id: {type: string}}}
+//	    command: ["./lookup_order.sh"]
+//	recording:
+//	  path: session.jsonl
+//	  audio: elide
+//	outputs:
+//	  text: stdout
+//	  audio: reply.wav
+//
+// $VAR and ${VAR} references are expanded from the environment in the
+// connection settings, tool commands, directories and environments, the
+// recording path and the outputs; "$$" stands for a literal "$". Other fields,
+// such as instructions and tool descriptions, are used as written.
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/enesunal-m/azrealtime"
+)
+
+// connectionFields maps azrealtime.Config field names to their YAML keys.
+var connectionFields = map[string]string{
+	"Provider":         "provider",
+	"ResourceEndpoint": "endpoint",
+	"Deployment":       "deployment",
+	"APIVersion":       "api_version",
+	"Credential":       "api_key_env",
+	"DialTimeout":      "dial_timeout",
+}
+
+// Pipeline is the top-level structure of a pipeline file.
+type Pipeline struct {
+	Connection ConnectionSpec `yaml:"connection"` // How to reach the realtime service
+	Session    SessionSpec    `yaml:"session"`    // Session configuration applied after connecting
+	Tools      []ToolSpec     `yaml:"tools"`      // Function tools backed by shell commands
+	Recording  *RecordingSpec `yaml:"recording"`  // Optional JSONL session recording
+	Outputs    OutputSpec     `yaml:"outputs"`    // Where assistant output is written
+}
+
+// ConnectionSpec configures the client connection.
+type ConnectionSpec struct {
+	Provider    string        `yaml:"provider"`     // "azure" (default) or "openai"
+	Endpoint    string        `yaml:"endpoint"`     // Resource endpoint URL
+	Deployment  string        `yaml:"deployment"`   // Deployment (Azure) or model (OpenAI) name
+	APIVersion  string        `yaml:"api_version"`  // Azure API version
+	APIKeyEnv   string        `yaml:"api_key_env"`  // Environment variable holding the API key
+	BearerEnv   string        `yaml:"bearer_env"`   // Environment variable holding a Bearer token
+	DialTimeout time.Duration `yaml:"dial_timeout"` // Connection timeout (e.g. "30s")
+}
+
+// SessionSpec mirrors azrealtime.Session in YAML form.
+type SessionSpec struct {
+	Voice             string             `yaml:"voice"`
+	Instructions      string             `yaml:"instructions"`
+	InputAudioFormat  string             `yaml:"input_audio_format"`
+	OutputAudioFormat string             `yaml:"output_audio_format"`
+	Transcription     *TranscriptionSpec `yaml:"transcription"`
+	TurnDetection     *TurnDetectionSpec `yaml:"turn_detection"`
+	Modalities        []string           `yaml:"modalities"`
+	Temperature       float64            `yaml:"temperature"`
+	MaxOutputTokens   string             `yaml:"max_response_output_tokens"` // A number or "inf"
+}
+
+// TranscriptionSpec configures input audio transcription.
+type TranscriptionSpec struct {
+	Model    string `yaml:"model"`
+	Language string `yaml:"language"`
+	Prompt   string `yaml:"prompt"`
+}
+
+// TurnDetectionSpec configures voice activity detection.
+type TurnDetectionSpec struct {
+	Type              string  `yaml:"type"`
+	Threshold         float64 `yaml:"threshold"`
+	PrefixPaddingMS   int     `yaml:"prefix_padding_ms"`
+	SilenceDurationMS int     `yaml:"silence_duration_ms"`
+	CreateResponse    bool    `yaml:"create_response"`
+	InterruptResponse bool    `yaml:"interrupt_response"`
+	Eagerness         string  `yaml:"eagerness"`
+}
+
+// ToolSpec declares a function tool implemented by a shell command (see
+// azrealtime.CommandTool). The command receives the call arguments as JSON on
+// stdin and in the AZREALTIME_TOOL_ARGS environment variable; its stdout is
+// returned to the model as the call output.
+type ToolSpec struct {
+	Name           string         `yaml:"name"`
+	Description    string         `yaml:"description"`
+	Parameters     map[string]any `yaml:"parameters"`       // JSON schema of the arguments
+	Command        []string       `yaml:"command"`          // Program and arguments
+	Timeout        time.Duration  `yaml:"timeout"`          // Default: azrealtime.DefaultToolTimeout
+	Dir            string         `yaml:"dir"`              // Working directory
+	Env            []string       `yaml:"env"`              // Extra KEY=value environment entries
+	CleanEnv       bool           `yaml:"clean_env"`        // Don't inherit the agent's environment
+	MaxOutputBytes int            `yaml:"max_output_bytes"` // Default: azrealtime.DefaultToolMaxOutputBytes
+}
+
+// Handler returns the azrealtime.ToolHandler running the tool's command.
+func (t ToolSpec) Handler() azrealtime.ToolHandler {
+	return azrealtime.CommandTool(t.Command, azrealtime.CommandToolOptions{
+		Timeout:        t.Timeout,
+		Dir:            t.Dir,
+		Env:            t.Env,
+		CleanEnv:       t.CleanEnv,
+		MaxOutputBytes: t.MaxOutputBytes,
+	})
+}
+
+// RecordingSpec configures a SessionRecorder transcript.
+type RecordingSpec struct {
+	Path  string `yaml:"path"`  // JSONL output file
+	Audio string `yaml:"audio"` // "keep" (default) or "elide"
+}
+
+// DefaultMaxAudio is the longest assistant audio kept for Outputs.Audio when
+// OutputSpec.MaxAudio is zero.
+const DefaultMaxAudio = 30 * time.Minute
+
+// OutputSpec selects where assistant output goes.
+type OutputSpec struct {
+	Text     string        `yaml:"text"`      // "stdout" (default), "stderr", "none", or a file path
+	Audio    string        `yaml:"audio"`     // WAV file for assistant audio; empty disables audio output
+	MaxAudio time.Duration `yaml:"max_audio"` // Audio buffered for the WAV file; later audio is dropped. Default: DefaultMaxAudio
+}
+
+func (o OutputSpec) maxAudio() time.Duration {
+	if o.MaxAudio > 0 {
+		return o.MaxAudio
+	}
+	return DefaultMaxAudio
+}
+
+// Load reads and parses a pipeline file.
+func Load(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes the YAML, expands environment references in the fields that
+// allow them, and validates the result.
+func Parse(data []byte) (*Pipeline, error) {
+	var p Pipeline
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("pipeline: %w", err)
+	}
+	p.expandEnv()
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// expandEnv expands environment references in the fields listed in the
+// package documentation. Expanding after decoding keeps variable values from
+// changing the YAML structure.
+func (p *Pipeline) expandEnv() {
+	for _, s := range []*string{
+		&p.Connection.Provider, &p.Connection.Endpoint, &p.Connection.Deployment,
+		&p.Connection.APIVersion, &p.Connection.APIKeyEnv, &p.Connection.BearerEnv,
+		&p.Outputs.Text, &p.Outputs.Audio,
+	} {
+		*s = expand(*s)
+	}
+	if p.Recording != nil {
+		p.Recording.Path = expand(p.Recording.Path)
+	}
+	for i := range p.Tools {
+		t := &p.Tools[i]
+		t.Dir = expand(t.Dir)
+		for j := range t.Command {
+			t.Command[j] = expand(t.Command[j])
+		}
+		for j := range t.Env {
+			t.Env[j] = expand(t.Env[j])
+		}
+	}
+}
+
+// expand replaces $VAR and ${VAR} with environment values and "$$" with "$".
+func expand(s string) string {
+	return os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		return os.Getenv(name)
+	})
+}
+
+// Validate checks the pipeline for errors the server would otherwise report late.
+// All failures are reported together as azrealtime.ValidationErrors.
+func (p *Pipeline) Validate() error {
+	var errs azrealtime.ValidationErrors
+	add := func(field, code, format string, args ...any) {
+		errs = append(errs, azrealtime.ValidationError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if _, err := p.ClientConfig(); err != nil {
+		var cfgErr *azrealtime.ConfigError
+		if errors.As(err, &cfgErr) {
+			add("connection."+connectionFields[cfgErr.Field], azrealtime.ValidationCodeInvalidValue, "%s", err)
+		} else {
+			add("connection", azrealtime.ValidationCodeInvalidValue, "%v", err)
+		}
+	}
+	for _, env := range []string{p.Connection.APIKeyEnv, p.Connection.BearerEnv} {
+		if env != "" && os.Getenv(env) == "" {
+			add("connection.credential", azrealtime.ValidationCodeRequired, "environment variable %s is not set", env)
+		}
+	}
+	if err := azrealtime.ValidateSession(p.SessionConfig()); err != nil {
+		var verrs azrealtime.ValidationErrors
+		if errors.As(err, &verrs) {
+			for _, e := range verrs {
+				e.Field = "session." + e.Field
+				errs = append(errs, e)
+			}
+		}
+	}
+
+	if _, err := parseMaxTokens(p.Session.MaxOutputTokens); err != nil {
+		add("session.max_response_output_tokens", azrealtime.ValidationCodeInvalidValue, "%v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i, t := range p.Tools {
+		field := fmt.Sprintf("tools[%d]", i)
+		switch {
+		case t.Name == "":
+			add(field+".name", azrealtime.ValidationCodeRequired, "tool name is required")
+		case seen[t.Name]:
+			add(field+".name", azrealtime.ValidationCodeInvalidValue, "duplicate tool name %q", t.Name)
+		}
+		seen[t.Name] = true
+		if len(t.Command) == 0 {
+			add(field+".command", azrealtime.ValidationCodeRequired, "tool %q has no command", t.Name)
+		}
+		if t.Timeout < 0 {
+			add(field+".timeout", azrealtime.ValidationCodeOutOfRange, "tool %q timeout cannot be negative", t.Name)
+		}
+	}
+
+	if p.Recording != nil {
+		if p.Recording.Path == "" {
+			add("recording.path", azrealtime.ValidationCodeRequired, "recording path is required")
+		}
+		switch p.Recording.Audio {
+		case "", "keep", "elide":
+		default:
+			add("recording.audio", azrealtime.ValidationCodeInvalidValue, "invalid recording audio mode %q, must be 'keep' or 'elide'", p.Recording.Audio)
+		}
+	}
+
+	if p.Outputs.MaxAudio < 0 {
+		add("outputs.max_audio", azrealtime.ValidationCodeOutOfRange, "max_audio cannot be negative")
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ClientConfig returns the azrealtime.Config described by the connection section.
+func (p *Pipeline) ClientConfig() (azrealtime.Config, error) {
+	c := p.Connection
+	cfg := azrealtime.Config{
+		Provider:         azrealtime.Provider(c.Provider),
+		ResourceEndpoint: c.Endpoint,
+		Deployment:       c.Deployment,
+		APIVersion:       c.APIVersion,
+		DialTimeout:      c.DialTimeout,
+	}
+	switch {
+	case c.APIKeyEnv != "":
+		cfg.Credential = azrealtime.APIKey(os.Getenv(c.APIKeyEnv))
+	case c.BearerEnv != "":
+		cfg.Credential = azrealtime.Bearer(os.Getenv(c.BearerEnv))
+	}
+	if err := azrealtime.ValidateConfig(cfg); err != nil {
+		return azrealtime.Config{}, err
+	}
+	return cfg, nil
+}
+
+// SessionConfig returns the azrealtime.Session described by the session and tools sections.
+func (p *Pipeline) SessionConfig() azrealtime.Session {
+	s := p.Session
+	var out azrealtime.Session
+	if s.Voice != "" {
+		out.Voice = azrealtime.Ptr(s.Voice)
+	}
+	if s.Instructions != "" {
+		out.Instructions = azrealtime.Ptr(s.Instructions)
+	}
+	if s.InputAudioFormat != "" {
+		out.InputAudioFormat = azrealtime.Ptr(s.InputAudioFormat)
+	}
+	if s.OutputAudioFormat != "" {
+		out.OutputAudioFormat = azrealtime.Ptr(s.OutputAudioFormat)
+	}
+	if t := s.Transcription; t != nil {
+		out.InputTranscription = &azrealtime.InputTranscription{Model: t.Model, Language: t.Language}
+		if t.Prompt != "" {
+			out.InputTranscription.Prompt = azrealtime.Ptr(t.Prompt)
+		}
+	}
+	if td := s.TurnDetection; td != nil {
+		out.TurnDetection = &azrealtime.TurnDetection{
+			Type:              td.Type,
+			Threshold:         td.Threshold,
+			PrefixPaddingMS:   td.PrefixPaddingMS,
+			SilenceDurationMS: td.SilenceDurationMS,
+			CreateResponse:    td.CreateResponse,
+			InterruptResponse: td.InterruptResponse,
+			Eagerness:         td.Eagerness,
+		}
+	}
+	out.Modalities = s.Modalities
+	if s.Temperature != 0 {
+		out.Temperature = azrealtime.Ptr(s.Temperature)
+	}
+	out.MaxOutputTokens, _ = parseMaxTokens(s.MaxOutputTokens)
+	for _, t := range p.Tools {
+		params := t.Parameters
+		if params == nil {
+			params = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		out.Tools = append(out.Tools, map[string]any{
+			"type":        "function",
+			"name":        t.Name,
+			"description": t.Description,
+			"parameters":  params,
+		})
+	}
+	return out
+}
+
+// parseMaxTokens parses a token limit written as a number or "inf". An empty
+// string is the zero MaxTokens.
+func parseMaxTokens(s string) (azrealtime.MaxTokens, error) {
+	switch s {
+	case "":
+		return 0, nil
+	case "inf":
+		return azrealtime.MaxTokensInf, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token limit %q, must be a number or \"inf\"", s)
+	}
+	return azrealtime.MaxTokens(n), nil
+}
@@ -0,0 +1,200 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enesunal-m/azrealtime"
+)
+
+const testPipeline = `
+connection:
+  endpoint: ${PIPELINE_TEST_ENDPOINT}
+  deployment: gpt-4o-realtime
+  api_version: 2025-04-01-preview
+  api_key_env: PIPELINE_TEST_KEY
+  dial_timeout: 20s
+session:
+  voice: alloy
+  instructions: Be brief.
+  transcription:
+    model: whisper-1
+  turn_detection:
+    type: server_vad
+    threshold: 0.6
+  modalities: [text, audio]
+  temperature: 0.9
+  max_response_output_tokens: inf
+tools:
+  - name: echo_args
+    description: Echo the arguments
+    parameters: {type: object, properties: {text: {type: string}}}
+    command: ["cat"]
+    timeout: 5s
+recording:
+  path: session.jsonl
+  audio: elide
+outputs:
+  text: none
+`
+
+func TestParse(t *testing.T) {
+	t.Setenv("PIPELINE_TEST_ENDPOINT", "https://test.openai.azure.com")
+	t.Setenv("PIPELINE_TEST_KEY", "test-key")
+
+	p, err := Parse([]byte(testPipeline))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	cfg, err := p.ClientConfig()
+	if err != nil {
+		t.Fatalf("ClientConfig: %v", err)
+	}
+	if cfg.ResourceEndpoint != "https://test.openai.azure.com" || cfg.DialTimeout != 20*time.Second {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.Credential != azrealtime.APIKey("test-key") {
+		t.Errorf("unexpected credential: %v", cfg.Credential)
+	}
+
+	s := p.SessionConfig()
+	if *s.Voice != "alloy" || *s.Instructions != "Be brief." {
+		t.Errorf("unexpected session: %+v", s)
+	}
+	if len(s.Modalities) != 2 || *s.Temperature != 0.9 || s.MaxOutputTokens != azrealtime.MaxTokensInf {
+		t.Errorf("unexpected response defaults: %v %v %v", s.Modalities, *s.Temperature, s.MaxOutputTokens)
+	}
+	if s.InputTranscription.Model != "whisper-1" || s.TurnDetection.Threshold != 0.6 {
+		t.Errorf("unexpected transcription/turn detection: %+v %+v", s.InputTranscription, s.TurnDetection)
+	}
+	if len(s.Tools) != 1 || s.Tools[0].(map[string]any)["name"] != "echo_args" {
+		t.Errorf("unexpected tools: %+v", s.Tools)
+	}
+	if p.Tools[0].Timeout != 5*time.Second || p.Recording.Audio != "elide" {
+		t.Errorf("unexpected tool/recording spec: %+v %+v", p.Tools[0], p.Recording)
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	yaml := `
+connection:
+  endpoint: https://test.openai.azure.com
+  api_version: 2025-04-01-preview
+  api_key_env: PIPELINE_TEST_MISSING_KEY
+session:
+  voice: robot
+  max_response_output_tokens: lots
+tools:
+  - name: a
+    command: ["true"]
+  - name: a
+recording:
+  path: out.jsonl
+  audio: mp3
+`
+	_, err := Parse([]byte(yaml))
+	var verrs azrealtime.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, e := range verrs {
+		fields[e.Field] = true
+	}
+	for _, want := range []string{
+		"connection.deployment",
+		"connection.credential",
+		"session.voice",
+		"session.max_response_output_tokens",
+		"tools[1].name",
+		"tools[1].command",
+		"recording.audio",
+	} {
+		if !fields[want] {
+			t.Errorf("expected error for %s, got %v", want, err)
+		}
+	}
+}
+
+func TestAgent_RunTool(t *testing.T) {
+	a := &Agent{
+		p: &Pipeline{Tools: []ToolSpec{
+			{Name: "echo", Command: []string{"cat"}},
+			{Name: "fail", Command: []string{"sh", "-c", "echo boom >&2; exit 3"}},
+		}},
+		ctx: context.Background(),
+	}
+
+	out, err := a.runTool("echo", `{"text":"hi"}`)
+	if err != nil || out != `{"text":"hi"}` {
+		t.Errorf("expected echoed args, got %q (err %v)", out, err)
+	}
+
+	if _, err := a.runTool("fail", "{}"); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected failure with stderr, got %v", err)
+	}
+	if _, err := a.runTool("missing", "{}"); err == nil {
+		t.Error("expected error for undeclared tool")
+	}
+}
+
+func TestParse_ExpandsOnlyListedFields(t *testing.T) {
+	t.Setenv("PIPELINE_TEST_ENDPOINT", "https://test.openai.azure.com")
+	t.Setenv("PIPELINE_TEST_KEY", "test-key")
+	t.Setenv("PIPELINE_TEST_DIR", "/tmp/tools")
+	t.Setenv("PIPELINE_TEST_INJECT", "x\nsession:\n  voice: evil")
+
+	p, err := Parse([]byte(`
+connection:
+  endpoint: ${PIPELINE_TEST_ENDPOINT}
+  deployment: gpt-4o-realtime-$PIPELINE_TEST_INJECT
+  api_version: 2025-04-01-preview
+  api_key_env: PIPELINE_TEST_KEY
+session:
+  voice: alloy
+  instructions: Orders over $5 ship free; never print $HOME.
+tools:
+  - name: price
+    command: ["echo", "$$5", "${PIPELINE_TEST_DIR}"]
+    dir: $PIPELINE_TEST_DIR
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if p.Connection.Endpoint != "https://test.openai.azure.com" {
+		t.Errorf("endpoint = %q", p.Connection.Endpoint)
+	}
+	if want := "Orders over $5 ship free; never print $HOME."; p.Session.Instructions != want {
+		t.Errorf("instructions = %q, want %q", p.Session.Instructions, want)
+	}
+	if p.Session.Voice != "alloy" || !strings.HasSuffix(p.Connection.Deployment, "voice: evil") {
+		t.Errorf("a variable changed the YAML structure: voice %q, deployment %q", p.Session.Voice, p.Connection.Deployment)
+	}
+	if got := strings.Join(p.Tools[0].Command, " "); got != "echo $5 /tmp/tools" || p.Tools[0].Dir != "/tmp/tools" {
+		t.Errorf("tool command %q, dir %q", got, p.Tools[0].Dir)
+	}
+}
+
+func TestAgent_CapsAudio(t *testing.T) {
+	a := &Agent{
+		p:             &Pipeline{Outputs: OutputSpec{MaxAudio: time.Millisecond}},
+		audioPath:     "reply.wav",
+		maxAudioBytes: 10,
+		errs:          make(chan error, 4),
+	}
+	delta := azrealtime.ResponseAudioDelta{DeltaBase64: "AAAAAAAAAAA="} // 8 bytes
+	a.onAudioDelta(delta)
+	a.onAudioDelta(delta)
+	a.onAudioDelta(delta)
+	if len(a.audio) != 10 {
+		t.Errorf("buffered %d bytes, want the 10 byte cap", len(a.audio))
+	}
+	if len(a.errs) != 1 {
+		t.Errorf("reported %d errors, want 1", len(a.errs))
+	}
+}
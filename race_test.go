@@ -0,0 +1,7 @@
+//go:build race
+
+package azrealtime
+
+// raceEnabled reports whether tests run under the race detector, which makes
+// sync.Pool drop items and so skews allocation counts.
+const raceEnabled = true
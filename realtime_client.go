@@ -0,0 +1,114 @@
+package azrealtime
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// RealtimeClient is the full set of public methods of *Client. Both *Client
+// and *WithRetryableClient implement it; depend on RealtimeClient to substitute
+// mocks in unit tests or to compose decorators.
+type RealtimeClient interface {
+	// Session
+	SessionUpdate(ctx context.Context, s Session) error
+	SessionUpdates() *SessionUpdateQueue
+	UpdateGlossary(ctx context.Context, base Session, g *Glossary) error
+	SessionExpiresAt() time.Time
+	HandshakeInfo() HandshakeInfo
+
+	// Input audio
+	AppendPCM16(ctx context.Context, pcmLE []byte) error
+	AppendPCM16From(ctx context.Context, r io.Reader) (int64, error)
+	AppendG711(ctx context.Context, g711 []byte) error
+	StreamPCM16(ctx context.Context, r io.Reader, opts StreamOptions) error
+	InputCommit(ctx context.Context) error
+	InputClear(ctx context.Context) error
+
+	// Conversation
+	CreateConversationItem(ctx context.Context, item ConversationItem) error
+	TruncateConversationItem(ctx context.Context, itemID string, contentIndex int, audioEndMs int) error
+	DeleteConversationItem(ctx context.Context, itemID string) error
+	ConversationItemIDs() []string
+	Conversation(name string) *ConversationHandle
+	RestoreConversation(ctx context.Context, items []ConversationItem) error
+	Checkpoint() Checkpoint
+	Rollback(ctx context.Context, cp Checkpoint) ([]string, error)
+	Transcript() Transcript
+
+	// Responses
+	CreateResponse(ctx context.Context, opts CreateResponseOptions) (string, error)
+	CreateTaggedResponse(ctx context.Context, opts CreateResponseOptions) (eventID, tag string, err error)
+	CreateOutOfBandResponse(ctx context.Context, opts CreateResponseOptions) (eventID, tag string, err error)
+	CreateResponseWithContext(ctx context.Context, opts CreateResponseOptions, rc RetrievalContext) (string, error)
+	CancelResponse(ctx context.Context) error
+	Interrupt(ctx context.Context, playedMS int) error
+	Ask(ctx context.Context, prompt string) (string, error)
+	ResponseByTag(tag string) (TaggedResponse, bool)
+	ForgetResponseTag(tag string)
+	TrackResponses() *ResponseTracker
+	UseTools(r *ToolRegistry)
+
+	// Connection
+	Reconnect(ctx context.Context) error
+	Close() error
+	CloseWithReport(ctx context.Context) (SessionReport, error)
+	Done() <-chan struct{}
+	Err() error
+	EnforceCallDuration(policy CallDurationPolicy) (stop func(), err error)
+
+	// Correlation
+	SetCorrelationID(id string)
+	CorrelationID() string
+
+	// Metrics
+	Stats() Stats
+	Ping(ctx context.Context) (time.Duration, error)
+	RTT() time.Duration
+	LastRateLimits() (RateLimitsUpdated, bool)
+	Usage() TokenUsage
+	EstimatedCost() float64
+
+	// Event handlers
+	OnError(fn func(ErrorEvent))
+	OnErrorRecovery(fn func(e ErrorEvent, err error))
+	OnRawEvent(fn func(dir EventDirection, data []byte))
+	OnSessionCreated(fn func(SessionCreated))
+	OnSessionUpdated(fn func(SessionUpdated))
+	OnSessionExpiring(fn func(remaining time.Duration))
+	OnSessionReport(fn func(SessionReport))
+	OnRateLimitsUpdated(fn func(RateLimitsUpdated))
+	OnInputAudioBufferSpeechStarted(fn func(InputAudioBufferSpeechStarted))
+	OnInputAudioBufferSpeechStopped(fn func(InputAudioBufferSpeechStopped))
+	OnInputAudioBufferCommitted(fn func(InputAudioBufferCommitted))
+	OnInputAudioBufferCleared(fn func(InputAudioBufferCleared))
+	OnConversationItemCreated(fn func(ConversationItemCreated))
+	OnConversationItemDeleted(fn func(ConversationItemDeleted))
+	OnConversationItemTruncated(fn func(ConversationItemTruncated))
+	OnConversationItemInputAudioTranscriptionCompleted(fn func(ConversationItemInputAudioTranscriptionCompleted))
+	OnConversationItemInputAudioTranscriptionDelta(fn func(ConversationItemInputAudioTranscriptionDelta))
+	OnConversationItemInputAudioTranscriptionFailed(fn func(ConversationItemInputAudioTranscriptionFailed))
+	OnResponseCreated(fn func(ResponseCreated))
+	OnResponseDone(fn func(ResponseDone))
+	OnResponseLatency(fn func(ResponseLatency))
+	OnResponseOutputItemAdded(fn func(ResponseOutputItemAdded))
+	OnResponseOutputItemDone(fn func(ResponseOutputItemDone))
+	OnResponseContentPartAdded(fn func(ResponseContentPartAdded))
+	OnResponseContentPartDone(fn func(ResponseContentPartDone))
+	OnResponseTextDelta(fn func(ResponseTextDelta))
+	OnResponseTextDone(fn func(ResponseTextDone))
+	OnResponseAudioDelta(fn func(ResponseAudioDelta))
+	OnResponseAudioDone(fn func(ResponseAudioDone))
+	OnResponseAudioProgress(every time.Duration, fn func(AudioProgress))
+	OnResponseAudioTranscriptDelta(fn func(ResponseAudioTranscriptDelta))
+	OnResponseAudioTranscriptDone(fn func(ResponseAudioTranscriptDone))
+	OnResponseFunctionCallArgumentsDelta(fn func(ResponseFunctionCallArgumentsDelta))
+	OnResponseFunctionCallArgumentsDone(fn func(ResponseFunctionCallArgumentsDone))
+	OnInterrupt(fn func())
+	OnToolCall(fn func(ToolCall))
+}
+
+var (
+	_ RealtimeClient = (*Client)(nil)
+	_ RealtimeClient = (*WithRetryableClient)(nil)
+)
@@ -0,0 +1,19 @@
+package azrealtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestRealtimeClient_Complete fails when a public method is added to *Client
+// without being added to RealtimeClient.
+func TestRealtimeClient_Complete(t *testing.T) {
+	iface := reflect.TypeOf((*RealtimeClient)(nil)).Elem()
+	clientType := reflect.TypeOf(&Client{})
+	for i := 0; i < clientType.NumMethod(); i++ {
+		name := clientType.Method(i).Name
+		if _, ok := iface.MethodByName(name); !ok {
+			t.Errorf("RealtimeClient is missing %s", name)
+		}
+	}
+}
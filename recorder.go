@@ -0,0 +1,160 @@
+package azrealtime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// AudioRecordMode controls how SessionRecorder handles base64 audio payloads.
+type AudioRecordMode int
+
+const (
+	// AudioKeep records audio payloads inline, unchanged.
+	AudioKeep AudioRecordMode = iota
+	// AudioElide replaces audio payloads with an empty string and records their decoded size.
+	AudioElide
+	// AudioExternalize hands decoded audio to SessionRecorderOptions.AudioSink and
+	// records the reference it returns in place of the payload.
+	AudioExternalize
+)
+
+// audioFields maps event types carrying base64 audio to the JSON field holding it.
+var audioFields = map[string]string{
+	"input_audio_buffer.append":   "audio",
+	"response.audio.delta":        "delta",
+	"response.output_audio.delta": "delta",
+}
+
+// RecordedEvent is one line of a SessionRecorder transcript.
+type RecordedEvent struct {
+	Time       time.Time       `json:"ts"`                    // When the event was recorded
+	Direction  EventDirection  `json:"dir"`                   // Inbound or outbound
+	Type       string          `json:"type"`                  // Event type (e.g. "response.text.delta")
+	AudioBytes int             `json:"audio_bytes,omitempty"` // Decoded size of an elided or externalized audio payload
+	AudioRef   string          `json:"audio_ref,omitempty"`   // Reference returned by AudioSink for externalized audio
+	Event      json.RawMessage `json:"event"`                 // The event JSON, with audio handled per AudioMode
+}
+
+// SessionRecorderOptions configures a SessionRecorder.
+type SessionRecorderOptions struct {
+	// AudioMode controls how audio payloads are recorded. Default: AudioKeep.
+	AudioMode AudioRecordMode
+
+	// AudioSink receives decoded audio when AudioMode is AudioExternalize and
+	// returns a reference (e.g. a file name) recorded in RecordedEvent.AudioRef.
+	AudioSink func(dir EventDirection, eventType string, pcm []byte) (ref string, err error)
+
+	// Now returns the timestamp for each record. Default: time.Now.
+	Now func() time.Time
+}
+
+// SessionRecorder writes every inbound and outbound event as timestamped JSONL.
+// Attach it to a Client with Attach, or feed it raw events from any other
+// transport (such as a WebRTC data channel) with Record.
+//
+// A SessionRecorder is safe for concurrent use.
+type SessionRecorder struct {
+	opts SessionRecorderOptions
+
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error // First write error; once set, further records are dropped
+}
+
+// NewSessionRecorder creates a recorder that writes JSONL to w.
+func NewSessionRecorder(w io.Writer, opts SessionRecorderOptions) *SessionRecorder {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &SessionRecorder{opts: opts, enc: json.NewEncoder(w)}
+}
+
+// Attach registers the recorder as c's OnRawEvent hook, replacing any existing hook.
+// Write errors are available from Err.
+func (r *SessionRecorder) Attach(c *Client) {
+	c.OnRawEvent(func(dir EventDirection, data []byte) { _ = r.Record(dir, data) })
+}
+
+// Record writes a single raw event. Malformed JSON is recorded with an empty type.
+func (r *SessionRecorder) Record(dir EventDirection, raw []byte) error {
+	rec := RecordedEvent{Time: r.opts.Now(), Direction: dir}
+
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		// Keep malformed payloads as a JSON string so the transcript stays valid JSONL
+		quoted, _ := json.Marshal(string(raw))
+		rec.Event = quoted
+	} else {
+		rec.Type = env.Type
+		rec.Event = json.RawMessage(raw)
+		if field, ok := audioFields[env.Type]; ok && r.opts.AudioMode != AudioKeep {
+			if err := r.stripAudio(&rec, field); err != nil {
+				return r.fail(err)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.enc.Encode(rec); err != nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// Err returns the first error encountered while writing, if any.
+func (r *SessionRecorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// stripAudio removes the base64 audio in field from rec.Event according to the audio mode.
+func (r *SessionRecorder) stripAudio(rec *RecordedEvent, field string) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Event, &obj); err != nil {
+		return nil // not an object; record as-is
+	}
+	var b64 string
+	if err := json.Unmarshal(obj[field], &b64); err != nil || b64 == "" {
+		return nil
+	}
+	rec.AudioBytes = base64DecodedLen(b64)
+
+	if r.opts.AudioMode == AudioExternalize && r.opts.AudioSink != nil {
+		pcm, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return fmt.Errorf("decode %s audio: %w", rec.Type, err)
+		}
+		ref, err := r.opts.AudioSink(rec.Direction, rec.Type, pcm)
+		if err != nil {
+			return fmt.Errorf("externalize %s audio: %w", rec.Type, err)
+		}
+		rec.AudioRef = ref
+	}
+
+	obj[field] = json.RawMessage(`""`)
+	stripped, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	rec.Event = stripped
+	return nil
+}
+
+// fail records err as the recorder's sticky error.
+func (r *SessionRecorder) fail(err error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
@@ -0,0 +1,140 @@
+package azrealtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func decodeRecords(t *testing.T, data []byte) []RecordedEvent {
+	t.Helper()
+	var out []RecordedEvent
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var rec RecordedEvent
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid JSONL line %q: %v", sc.Text(), err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func TestSessionRecorder_AudioModes(t *testing.T) {
+	pcm := make([]byte, 480)
+	raw, _ := json.Marshal(map[string]any{
+		"type":        "response.audio.delta",
+		"response_id": "resp_1",
+		"delta":       base64.StdEncoding.EncodeToString(pcm),
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		var buf bytes.Buffer
+		r := NewSessionRecorder(&buf, SessionRecorderOptions{})
+		if err := r.Record(DirectionInbound, raw); err != nil {
+			t.Fatal(err)
+		}
+		recs := decodeRecords(t, buf.Bytes())
+		if len(recs) != 1 || recs[0].Type != "response.audio.delta" || !strings.Contains(string(recs[0].Event), "AAAA") {
+			t.Errorf("unexpected record: %+v", recs)
+		}
+	})
+
+	t.Run("elide", func(t *testing.T) {
+		var buf bytes.Buffer
+		r := NewSessionRecorder(&buf, SessionRecorderOptions{AudioMode: AudioElide})
+		if err := r.Record(DirectionInbound, raw); err != nil {
+			t.Fatal(err)
+		}
+		recs := decodeRecords(t, buf.Bytes())
+		if recs[0].AudioBytes != len(pcm) || strings.Contains(string(recs[0].Event), "AAAA") {
+			t.Errorf("expected elided audio, got %+v", recs[0])
+		}
+	})
+
+	t.Run("externalize", func(t *testing.T) {
+		var buf bytes.Buffer
+		var sunk []byte
+		r := NewSessionRecorder(&buf, SessionRecorderOptions{
+			AudioMode: AudioExternalize,
+			AudioSink: func(dir EventDirection, eventType string, b []byte) (string, error) {
+				sunk = b
+				return "chunk-0001.pcm", nil
+			},
+		})
+		if err := r.Record(DirectionInbound, raw); err != nil {
+			t.Fatal(err)
+		}
+		recs := decodeRecords(t, buf.Bytes())
+		if recs[0].AudioRef != "chunk-0001.pcm" || len(sunk) != len(pcm) {
+			t.Errorf("expected externalized audio, got %+v", recs[0])
+		}
+	})
+}
+
+func TestSessionRecorder_AttachToClient(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	var buf syncBuffer
+	rec := NewSessionRecorder(&buf, SessionRecorderOptions{AudioMode: AudioElide})
+	rec.Attach(client)
+
+	if err := client.AppendPCM16(ctx, make([]byte, 960)); err != nil {
+		t.Fatalf("AppendPCM16: %v", err)
+	}
+	if _, err := client.CreateResponse(ctx, CreateResponseOptions{Modalities: []string{"text"}}); err != nil {
+		t.Fatalf("CreateResponse: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	recs := decodeRecords(t, buf.Bytes())
+	var sawAppend, sawDone bool
+	for _, r := range recs {
+		switch {
+		case r.Direction == DirectionOutbound && r.Type == "input_audio_buffer.append":
+			sawAppend = r.AudioBytes == 960
+		case r.Direction == DirectionInbound && r.Type == "response.done":
+			sawDone = true
+		}
+	}
+	if !sawAppend || !sawDone {
+		t.Errorf("missing expected records (append=%v done=%v): %d records", sawAppend, sawDone, len(recs))
+	}
+	if rec.Err() != nil {
+		t.Errorf("unexpected recorder error: %v", rec.Err())
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes and reads in tests.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
@@ -0,0 +1,73 @@
+package azrealtime
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// redactedAudioPrefix is how many characters of a long base64 run are kept
+// when it is truncated in log output.
+const redactedAudioPrefix = 16
+
+// minLoggedBase64 is the length from which a base64 run in log output is
+// treated as audio and truncated.
+const minLoggedBase64 = 256
+
+var (
+	// Credentials in URL query strings
+	secretQueryPattern = regexp.MustCompile(`(?i)([?&](?:api-key|api_key|apikey|key|access_token|token|sig|client_secret)=)[^&\s"']+`)
+	// api-key headers or JSON fields
+	apiKeyFieldPattern = regexp.MustCompile(`(?i)(["']?api[-_]?key["']?\s*[:=]\s*["']?)[^"'\s,&}\]]+`)
+	// Authorization: Bearer <token>
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+	// OpenAI API keys and ephemeral client secrets
+	openAIKeyPattern    = regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{16,}`)
+	ephemeralKeyPattern = regexp.MustCompile(`\bek_[A-Za-z0-9_\-]{8,}`)
+	// Long base64 runs, e.g. audio deltas in raw event payloads
+	base64RunPattern = regexp.MustCompile(`[A-Za-z0-9+/]{` + fmt.Sprint(minLoggedBase64) + `,}={0,2}`)
+)
+
+// redactString removes credentials from s and, unless keepAudio is set,
+// truncates long base64 runs.
+func redactString(s string, keepAudio bool) string {
+	s = secretQueryPattern.ReplaceAllString(s, "${1}REDACTED")
+	s = apiKeyFieldPattern.ReplaceAllString(s, "${1}REDACTED")
+	s = bearerPattern.ReplaceAllString(s, "${1}REDACTED")
+	s = openAIKeyPattern.ReplaceAllString(s, "sk-REDACTED")
+	s = ephemeralKeyPattern.ReplaceAllString(s, "ek_REDACTED")
+	if !keepAudio {
+		s = base64RunPattern.ReplaceAllStringFunc(s, func(run string) string {
+			return fmt.Sprintf("%s...[%d base64 chars]", run[:redactedAudioPrefix], len(run))
+		})
+	}
+	return s
+}
+
+// redactFields returns a copy of fields with credentials removed from string,
+// []byte and error values, recursing into nested maps. Errors whose message
+// needs no redaction are kept as they are.
+func redactFields(fields map[string]any, keepAudio bool) map[string]any {
+	if fields == nil {
+		return nil
+	}
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		switch v := v.(type) {
+		case string:
+			out[k] = redactString(v, keepAudio)
+		case []byte:
+			out[k] = redactString(string(v), keepAudio)
+		case error:
+			if msg := v.Error(); redactString(msg, keepAudio) != msg {
+				out[k] = redactString(msg, keepAudio)
+			} else {
+				out[k] = v
+			}
+		case map[string]any:
+			out[k] = redactFields(v, keepAudio)
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
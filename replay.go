@@ -0,0 +1,94 @@
+package azrealtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// maxReplayLineSize bounds a single transcript line; inline audio deltas can be large.
+const maxReplayLineSize = 16 * 1024 * 1024
+
+// ReplayOptions configures Replay.
+type ReplayOptions struct {
+	// Speed scales the original timing: 2.0 replays twice as fast.
+	// Default: 1.0 (original timing).
+	Speed float64
+
+	// NoDelay dispatches events back to back, ignoring recorded timestamps.
+	NoDelay bool
+
+	// OnOutbound, if set, is called for each recorded outbound event in order,
+	// so tests can assert on what the original session sent.
+	OnOutbound func(RecordedEvent)
+}
+
+// NewReplayClient returns a Client with no connection, for replaying transcripts
+// through handlers offline. Register handlers as usual; any attempt to send
+// returns ErrClosed.
+func NewReplayClient(cfg Config) *Client {
+	normalizeLoggers(&cfg)
+	return &Client{cfg: cfg, closedCh: make(chan struct{})}
+}
+
+// Replay reads a JSONL transcript written by SessionRecorder and feeds every
+// inbound event through c's registered handlers, reproducing the original
+// spacing between events. c may be a live client or one from NewReplayClient.
+//
+// Replay returns when the transcript is exhausted, ctx is done, or a line cannot be parsed.
+func Replay(ctx context.Context, r io.Reader, c *Client, opts ReplayOptions) error {
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxReplayLineSize)
+
+	var first time.Time
+	start := time.Now()
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var rec RecordedEvent
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return fmt.Errorf("replay: line %d: %w", line, err)
+		}
+
+		if !opts.NoDelay && !rec.Time.IsZero() {
+			if first.IsZero() {
+				first = rec.Time
+			}
+			due := start.Add(time.Duration(float64(rec.Time.Sub(first)) / speed))
+			if wait := time.Until(due); wait > 0 {
+				t := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					return ctx.Err()
+				case <-t.C:
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch rec.Direction {
+		case DirectionInbound:
+			c.handleMessage(rec.Event)
+		case DirectionOutbound:
+			if opts.OnOutbound != nil {
+				opts.OnOutbound(rec)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package azrealtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func recordTranscript(t *testing.T, base time.Time) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	now := base
+	rec := NewSessionRecorder(&buf, SessionRecorderOptions{Now: func() time.Time { return now }})
+
+	events := []struct {
+		dir   EventDirection
+		after time.Duration
+		event any
+	}{
+		{DirectionOutbound, 0, map[string]any{"type": "response.create"}},
+		{DirectionInbound, 10 * time.Millisecond, ResponseTextDelta{Type: "response.text.delta", ResponseID: "r1", Delta: "Hel"}},
+		{DirectionInbound, 40 * time.Millisecond, ResponseTextDelta{Type: "response.text.delta", ResponseID: "r1", Delta: "lo"}},
+		{DirectionInbound, 50 * time.Millisecond, ResponseTextDone{Type: "response.text.done", ResponseID: "r1"}},
+	}
+	for _, e := range events {
+		now = base.Add(e.after)
+		raw, _ := json.Marshal(e.event)
+		if err := rec.Record(e.dir, raw); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	return &buf
+}
+
+func TestReplay_DispatchesThroughHandlers(t *testing.T) {
+	transcript := recordTranscript(t, time.Unix(1700000000, 0))
+
+	c := NewReplayClient(Config{})
+	asm := NewTextAssembler()
+	var text string
+	c.OnResponseTextDelta(asm.OnDelta)
+	c.OnResponseTextDone(func(e ResponseTextDone) { text = asm.OnDone(e) })
+
+	var outbound []string
+	start := time.Now()
+	err := Replay(context.Background(), transcript, c, ReplayOptions{
+		OnOutbound: func(r RecordedEvent) { outbound = append(outbound, r.Type) },
+	})
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("expected original timing to be preserved, replay took %v", elapsed)
+	}
+	if text != "Hello" {
+		t.Errorf("expected assembled text %q, got %q", "Hello", text)
+	}
+	if len(outbound) != 1 || outbound[0] != "response.create" {
+		t.Errorf("unexpected outbound events: %v", outbound)
+	}
+	if c.Stats().EventsReceived["response.text.delta"] != 2 {
+		t.Errorf("expected replayed events to be counted, got %v", c.Stats().EventsReceived)
+	}
+}
+
+func TestReplay_NoDelayAndErrors(t *testing.T) {
+	transcript := recordTranscript(t, time.Unix(1700000000, 0))
+	c := NewReplayClient(Config{})
+
+	start := time.Now()
+	if err := Replay(context.Background(), transcript, c, ReplayOptions{NoDelay: true}); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("NoDelay replay took %v", elapsed)
+	}
+
+	if err := Replay(context.Background(), strings.NewReader("not json\n"), c, ReplayOptions{}); err == nil {
+		t.Error("expected error for malformed transcript")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	transcript = recordTranscript(t, time.Unix(1700000000, 0))
+	if err := Replay(ctx, transcript, c, ReplayOptions{}); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
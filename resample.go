@@ -0,0 +1,109 @@
+package azrealtime
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// ResamplePCM16 converts 16-bit little-endian mono PCM from fromRate to toRate
+// using linear interpolation, e.g. 16 kHz or 48 kHz microphone audio to the
+// DefaultSampleRate the API expects. A trailing odd byte is ignored. It returns
+// nil if either rate is not positive.
+//
+// Linear interpolation applies no low-pass filter, so downsampling can alias
+// content above the target Nyquist frequency; this is inaudible for speech at
+// the rates used here.
+func ResamplePCM16(src []byte, fromRate, toRate int) []byte {
+	r, err := NewResampler(fromRate, toRate)
+	if err != nil {
+		return nil
+	}
+	return append(r.Process(src), r.Flush()...)
+}
+
+// Resampler converts a PCM16 stream between sample rates chunk by chunk,
+// carrying interpolation state across calls so chunk boundaries are seamless.
+// Feed it with Process and call Flush at the end of the stream.
+// A Resampler is not safe for concurrent use.
+type Resampler struct {
+	from, to int64
+
+	prev    int16 // Last input sample of the previous chunk
+	hasPrev bool
+	pos     int64 // Next output position, in 1/to units of an input sample, relative to prev
+	carry   []byte
+}
+
+// NewResampler creates a Resampler from fromRate to toRate (in Hz).
+func NewResampler(fromRate, toRate int) (*Resampler, error) {
+	if fromRate <= 0 || toRate <= 0 {
+		return nil, fmt.Errorf("azrealtime: invalid sample rates %d -> %d", fromRate, toRate)
+	}
+	return &Resampler{from: int64(fromRate), to: int64(toRate)}, nil
+}
+
+// Process resamples the next chunk of the stream and returns the output
+// produced so far. An odd trailing byte is held until the next call.
+func (r *Resampler) Process(pcm []byte) []byte {
+	if len(r.carry) > 0 {
+		pcm = append(r.carry, pcm...)
+		r.carry = nil
+	}
+	if len(pcm)%2 != 0 {
+		r.carry = []byte{pcm[len(pcm)-1]}
+		pcm = pcm[:len(pcm)-1]
+	}
+
+	n := len(pcm) / 2
+	if r.hasPrev {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	sample := func(i int64) int16 {
+		if r.hasPrev {
+			if i == 0 {
+				return r.prev
+			}
+			i--
+		}
+		return int16(binary.LittleEndian.Uint16(pcm[2*i:]))
+	}
+
+	if r.from == r.to {
+		out := make([]byte, len(pcm))
+		copy(out, pcm)
+		r.prev, r.hasPrev = sample(int64(n-1)), false
+		return out
+	}
+
+	// Emit every output position strictly before the last input sample; the
+	// rest needs the next chunk (or Flush) to interpolate.
+	last := int64(n - 1)
+	out := make([]byte, 0, int(last*r.to/r.from+1)*2)
+	for r.pos < last*r.to {
+		i := r.pos / r.to
+		frac := r.pos % r.to
+		a, b := int64(sample(i)), int64(sample(i+1))
+		v := a + (b-a)*frac/r.to
+		out = binary.LittleEndian.AppendUint16(out, uint16(int16(v)))
+		r.pos += r.from
+	}
+	r.pos -= last * r.to
+	r.prev, r.hasPrev = sample(last), true
+	return out
+}
+
+// Flush returns the output for the end of the stream, holding the last input
+// sample, and resets the Resampler for a new stream.
+func (r *Resampler) Flush() []byte {
+	var out []byte
+	if r.hasPrev && r.from != r.to {
+		for ; r.pos < r.to; r.pos += r.from {
+			out = binary.LittleEndian.AppendUint16(out, uint16(r.prev))
+		}
+	}
+	r.prev, r.hasPrev, r.pos, r.carry = 0, false, 0, nil
+	return out
+}
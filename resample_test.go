@@ -0,0 +1,84 @@
+package azrealtime
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// pcm16 encodes samples as little-endian PCM16.
+func pcm16(samples ...int16) []byte {
+	out := make([]byte, 0, 2*len(samples))
+	for _, s := range samples {
+		out = binary.LittleEndian.AppendUint16(out, uint16(s))
+	}
+	return out
+}
+
+// ramp returns n samples increasing by step.
+func ramp(n int, step int16) []byte {
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = int16(i) * step
+	}
+	return pcm16(samples...)
+}
+
+func TestResamplePCM16(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      []byte
+		from, to int
+		want     []byte
+	}{
+		{"same rate", pcm16(1, 2, 3), 24000, 24000, pcm16(1, 2, 3)},
+		{"downsample by two", ramp(8, 10), 48000, 24000, pcm16(0, 20, 40, 60)},
+		{"upsample 16k to 24k", ramp(4, 30), 16000, 24000, pcm16(0, 20, 40, 60, 80, 90)},
+		{"upsample by two", pcm16(0, 100, -100), 12000, 24000, pcm16(0, 50, 100, 0, -100, -100)},
+		{"odd byte ignored", append(pcm16(5, 5), 0x7f), 24000, 48000, pcm16(5, 5, 5, 5)},
+		{"empty", nil, 16000, 24000, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ResamplePCM16(tt.src, tt.from, tt.to)
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("ResamplePCM16() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := ResamplePCM16(pcm16(1), 0, 24000); got != nil {
+		t.Errorf("expected nil for invalid rate, got %v", got)
+	}
+	if _, err := NewResampler(16000, -1); err == nil {
+		t.Error("expected error for invalid rate")
+	}
+}
+
+func TestResampler_StreamingMatchesOneShot(t *testing.T) {
+	src := ramp(1000, 7)
+	for _, rates := range [][2]int{{16000, 24000}, {48000, 24000}, {44100, 24000}, {8000, 24000}} {
+		want := ResamplePCM16(src, rates[0], rates[1])
+
+		r, err := NewResampler(rates[0], rates[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []byte
+		// Uneven chunk sizes, including odd byte counts that split samples
+		for i, size := 0, 1; i < len(src); size = size%37 + 3 {
+			end := min(i+size, len(src))
+			got = append(got, r.Process(src[i:end])...)
+			i = end
+		}
+		got = append(got, r.Flush()...)
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("%d -> %d: streaming output (%d bytes) differs from one-shot (%d bytes)", rates[0], rates[1], len(got), len(want))
+		}
+		wantLen := (len(src)/2*rates[1] + rates[0] - 1) / rates[0] * 2
+		if len(want) != wantLen {
+			t.Errorf("%d -> %d: got %d bytes, want %d", rates[0], rates[1], len(want), wantLen)
+		}
+	}
+}
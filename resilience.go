@@ -3,6 +3,7 @@ package azrealtime
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
 	"time"
 )
@@ -193,7 +194,100 @@ func (r *WithRetryableClient) InputClear(ctx context.Context) error {
 	})
 }
 
-// Delegate methods that don't need retry logic
+// AppendG711 attempts to append G.711 audio with retry logic.
+func (r *WithRetryableClient) AppendG711(ctx context.Context, g711 []byte) error {
+	return WithRetry(ctx, r.config, func() error {
+		return r.client.AppendG711(ctx, g711)
+	})
+}
+
+// CreateTaggedResponse attempts to create a tagged response with retry logic.
+func (r *WithRetryableClient) CreateTaggedResponse(ctx context.Context, opts CreateResponseOptions) (eventID, tag string, err error) {
+	err = WithRetry(ctx, r.config, func() error {
+		var err error
+		eventID, tag, err = r.client.CreateTaggedResponse(ctx, opts)
+		return err
+	})
+	return eventID, tag, err
+}
+
+// CreateOutOfBandResponse attempts to create an out-of-band response with retry logic.
+func (r *WithRetryableClient) CreateOutOfBandResponse(ctx context.Context, opts CreateResponseOptions) (eventID, tag string, err error) {
+	err = WithRetry(ctx, r.config, func() error {
+		var err error
+		eventID, tag, err = r.client.CreateOutOfBandResponse(ctx, opts)
+		return err
+	})
+	return eventID, tag, err
+}
+
+// CreateResponseWithContext attempts to create a grounded response with retry logic.
+func (r *WithRetryableClient) CreateResponseWithContext(ctx context.Context, opts CreateResponseOptions, rc RetrievalContext) (string, error) {
+	var eventID string
+	err := WithRetry(ctx, r.config, func() error {
+		var err error
+		eventID, err = r.client.CreateResponseWithContext(ctx, opts, rc)
+		return err
+	})
+	return eventID, err
+}
+
+// CancelResponse attempts to cancel the current response with retry logic.
+func (r *WithRetryableClient) CancelResponse(ctx context.Context) error {
+	return WithRetry(ctx, r.config, func() error {
+		return r.client.CancelResponse(ctx)
+	})
+}
+
+// Interrupt attempts to interrupt the current response with retry logic.
+func (r *WithRetryableClient) Interrupt(ctx context.Context, playedMS int) error {
+	return WithRetry(ctx, r.config, func() error {
+		return r.client.Interrupt(ctx, playedMS)
+	})
+}
+
+// CreateConversationItem attempts to create a conversation item with retry logic.
+func (r *WithRetryableClient) CreateConversationItem(ctx context.Context, item ConversationItem) error {
+	return WithRetry(ctx, r.config, func() error {
+		return r.client.CreateConversationItem(ctx, item)
+	})
+}
+
+// TruncateConversationItem attempts to truncate a conversation item with retry logic.
+func (r *WithRetryableClient) TruncateConversationItem(ctx context.Context, itemID string, contentIndex int, audioEndMs int) error {
+	return WithRetry(ctx, r.config, func() error {
+		return r.client.TruncateConversationItem(ctx, itemID, contentIndex, audioEndMs)
+	})
+}
+
+// DeleteConversationItem attempts to delete a conversation item with retry logic.
+func (r *WithRetryableClient) DeleteConversationItem(ctx context.Context, itemID string) error {
+	return WithRetry(ctx, r.config, func() error {
+		return r.client.DeleteConversationItem(ctx, itemID)
+	})
+}
+
+// UpdateGlossary attempts to apply a glossary with retry logic.
+func (r *WithRetryableClient) UpdateGlossary(ctx context.Context, base Session, g *Glossary) error {
+	return WithRetry(ctx, r.config, func() error {
+		return r.client.UpdateGlossary(ctx, base, g)
+	})
+}
+
+// Reconnect attempts to reconnect with retry logic.
+func (r *WithRetryableClient) Reconnect(ctx context.Context) error {
+	return WithRetry(ctx, r.config, func() error {
+		return r.client.Reconnect(ctx)
+	})
+}
+
+// Client returns the wrapped client.
+func (r *WithRetryableClient) Client() *Client { return r.client }
+
+// Delegate methods that don't need retry logic. Methods that consume a reader
+// or send several events (AppendPCM16From, StreamPCM16, Ask,
+// RestoreConversation, Rollback) are not retried either, since a retry could
+// duplicate or skip part of their input.
 func (r *WithRetryableClient) Close() error                { return r.client.Close() }
 func (r *WithRetryableClient) OnError(fn func(ErrorEvent)) { r.client.OnError(fn) }
 func (r *WithRetryableClient) OnSessionCreated(fn func(SessionCreated)) {
@@ -217,6 +311,134 @@ func (r *WithRetryableClient) OnResponseAudioDelta(fn func(ResponseAudioDelta))
 func (r *WithRetryableClient) OnResponseAudioDone(fn func(ResponseAudioDone)) {
 	r.client.OnResponseAudioDone(fn)
 }
+func (r *WithRetryableClient) OnResponseAudioTranscriptDelta(fn func(ResponseAudioTranscriptDelta)) {
+	r.client.OnResponseAudioTranscriptDelta(fn)
+}
+func (r *WithRetryableClient) OnResponseAudioTranscriptDone(fn func(ResponseAudioTranscriptDone)) {
+	r.client.OnResponseAudioTranscriptDone(fn)
+}
+func (r *WithRetryableClient) OnResponseCreated(fn func(ResponseCreated)) {
+	r.client.OnResponseCreated(fn)
+}
+func (r *WithRetryableClient) OnResponseDone(fn func(ResponseDone)) {
+	r.client.OnResponseDone(fn)
+}
+func (r *WithRetryableClient) OnResponseOutputItemAdded(fn func(ResponseOutputItemAdded)) {
+	r.client.OnResponseOutputItemAdded(fn)
+}
+func (r *WithRetryableClient) OnResponseOutputItemDone(fn func(ResponseOutputItemDone)) {
+	r.client.OnResponseOutputItemDone(fn)
+}
+func (r *WithRetryableClient) OnResponseContentPartAdded(fn func(ResponseContentPartAdded)) {
+	r.client.OnResponseContentPartAdded(fn)
+}
+func (r *WithRetryableClient) OnResponseContentPartDone(fn func(ResponseContentPartDone)) {
+	r.client.OnResponseContentPartDone(fn)
+}
+func (r *WithRetryableClient) OnResponseFunctionCallArgumentsDelta(fn func(ResponseFunctionCallArgumentsDelta)) {
+	r.client.OnResponseFunctionCallArgumentsDelta(fn)
+}
+func (r *WithRetryableClient) OnResponseFunctionCallArgumentsDone(fn func(ResponseFunctionCallArgumentsDone)) {
+	r.client.OnResponseFunctionCallArgumentsDone(fn)
+}
+func (r *WithRetryableClient) OnInputAudioBufferSpeechStarted(fn func(InputAudioBufferSpeechStarted)) {
+	r.client.OnInputAudioBufferSpeechStarted(fn)
+}
+func (r *WithRetryableClient) OnInputAudioBufferSpeechStopped(fn func(InputAudioBufferSpeechStopped)) {
+	r.client.OnInputAudioBufferSpeechStopped(fn)
+}
+func (r *WithRetryableClient) OnInputAudioBufferCommitted(fn func(InputAudioBufferCommitted)) {
+	r.client.OnInputAudioBufferCommitted(fn)
+}
+func (r *WithRetryableClient) OnInputAudioBufferCleared(fn func(InputAudioBufferCleared)) {
+	r.client.OnInputAudioBufferCleared(fn)
+}
+func (r *WithRetryableClient) OnConversationItemCreated(fn func(ConversationItemCreated)) {
+	r.client.OnConversationItemCreated(fn)
+}
+func (r *WithRetryableClient) OnConversationItemDeleted(fn func(ConversationItemDeleted)) {
+	r.client.OnConversationItemDeleted(fn)
+}
+func (r *WithRetryableClient) OnConversationItemTruncated(fn func(ConversationItemTruncated)) {
+	r.client.OnConversationItemTruncated(fn)
+}
+func (r *WithRetryableClient) OnConversationItemInputAudioTranscriptionCompleted(fn func(ConversationItemInputAudioTranscriptionCompleted)) {
+	r.client.OnConversationItemInputAudioTranscriptionCompleted(fn)
+}
+func (r *WithRetryableClient) OnConversationItemInputAudioTranscriptionDelta(fn func(ConversationItemInputAudioTranscriptionDelta)) {
+	r.client.OnConversationItemInputAudioTranscriptionDelta(fn)
+}
+func (r *WithRetryableClient) OnConversationItemInputAudioTranscriptionFailed(fn func(ConversationItemInputAudioTranscriptionFailed)) {
+	r.client.OnConversationItemInputAudioTranscriptionFailed(fn)
+}
+func (r *WithRetryableClient) OnErrorRecovery(fn func(e ErrorEvent, err error)) {
+	r.client.OnErrorRecovery(fn)
+}
+func (r *WithRetryableClient) OnRawEvent(fn func(dir EventDirection, data []byte)) {
+	r.client.OnRawEvent(fn)
+}
+func (r *WithRetryableClient) OnInterrupt(fn func()) { r.client.OnInterrupt(fn) }
+func (r *WithRetryableClient) OnResponseAudioProgress(every time.Duration, fn func(AudioProgress)) {
+	r.client.OnResponseAudioProgress(every, fn)
+}
+func (r *WithRetryableClient) OnSessionExpiring(fn func(remaining time.Duration)) {
+	r.client.OnSessionExpiring(fn)
+}
+func (r *WithRetryableClient) SetCorrelationID(id string) { r.client.SetCorrelationID(id) }
+func (r *WithRetryableClient) CorrelationID() string      { return r.client.CorrelationID() }
+func (r *WithRetryableClient) OnResponseLatency(fn func(ResponseLatency)) {
+	r.client.OnResponseLatency(fn)
+}
+func (r *WithRetryableClient) OnSessionReport(fn func(SessionReport)) { r.client.OnSessionReport(fn) }
+func (r *WithRetryableClient) OnToolCall(fn func(ToolCall))           { r.client.OnToolCall(fn) }
+func (r *WithRetryableClient) AppendPCM16From(ctx context.Context, rd io.Reader) (int64, error) {
+	return r.client.AppendPCM16From(ctx, rd)
+}
+func (r *WithRetryableClient) StreamPCM16(ctx context.Context, rd io.Reader, opts StreamOptions) error {
+	return r.client.StreamPCM16(ctx, rd, opts)
+}
+func (r *WithRetryableClient) Ask(ctx context.Context, prompt string) (string, error) {
+	return r.client.Ask(ctx, prompt)
+}
+func (r *WithRetryableClient) RestoreConversation(ctx context.Context, items []ConversationItem) error {
+	return r.client.RestoreConversation(ctx, items)
+}
+func (r *WithRetryableClient) Checkpoint() Checkpoint { return r.client.Checkpoint() }
+func (r *WithRetryableClient) Rollback(ctx context.Context, cp Checkpoint) ([]string, error) {
+	return r.client.Rollback(ctx, cp)
+}
+func (r *WithRetryableClient) CloseWithReport(ctx context.Context) (SessionReport, error) {
+	return r.client.CloseWithReport(ctx)
+}
+func (r *WithRetryableClient) Conversation(name string) *ConversationHandle {
+	return r.client.Conversation(name)
+}
+func (r *WithRetryableClient) ConversationItemIDs() []string { return r.client.ConversationItemIDs() }
+func (r *WithRetryableClient) Done() <-chan struct{}         { return r.client.Done() }
+func (r *WithRetryableClient) Err() error                    { return r.client.Err() }
+func (r *WithRetryableClient) EnforceCallDuration(policy CallDurationPolicy) (stop func(), err error) {
+	return r.client.EnforceCallDuration(policy)
+}
+func (r *WithRetryableClient) EstimatedCost() float64       { return r.client.EstimatedCost() }
+func (r *WithRetryableClient) ForgetResponseTag(tag string) { r.client.ForgetResponseTag(tag) }
+func (r *WithRetryableClient) HandshakeInfo() HandshakeInfo { return r.client.HandshakeInfo() }
+func (r *WithRetryableClient) LastRateLimits() (RateLimitsUpdated, bool) {
+	return r.client.LastRateLimits()
+}
+func (r *WithRetryableClient) RTT() time.Duration { return r.client.RTT() }
+func (r *WithRetryableClient) Ping(ctx context.Context) (time.Duration, error) {
+	return r.client.Ping(ctx)
+}
+func (r *WithRetryableClient) ResponseByTag(tag string) (TaggedResponse, bool) {
+	return r.client.ResponseByTag(tag)
+}
+func (r *WithRetryableClient) SessionExpiresAt() time.Time         { return r.client.SessionExpiresAt() }
+func (r *WithRetryableClient) SessionUpdates() *SessionUpdateQueue { return r.client.SessionUpdates() }
+func (r *WithRetryableClient) Stats() Stats                        { return r.client.Stats() }
+func (r *WithRetryableClient) TrackResponses() *ResponseTracker    { return r.client.TrackResponses() }
+func (r *WithRetryableClient) Transcript() Transcript              { return r.client.Transcript() }
+func (r *WithRetryableClient) Usage() TokenUsage                   { return r.client.Usage() }
+func (r *WithRetryableClient) UseTools(reg *ToolRegistry)          { r.client.UseTools(reg) }
 
 // DialWithRetry creates a new client with automatic retry on connection failure.
 func DialWithRetry(ctx context.Context, cfg Config, retryConfig RetryConfig) (*Client, error) {
@@ -3,6 +3,8 @@ package azrealtime
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -208,6 +210,69 @@ func TestRetryableClient(t *testing.T) {
 	}
 }
 
+// TestRetryableClient_Coverage keeps WithRetryableClient a drop-in
+// replacement: every exported Client method must have a counterpart with the
+// same signature.
+func TestRetryableClient_Coverage(t *testing.T) {
+	clientType := reflect.TypeOf(&Client{})
+	wrapperType := reflect.TypeOf(&WithRetryableClient{})
+	for i := 0; i < clientType.NumMethod(); i++ {
+		m := clientType.Method(i)
+		w, ok := wrapperType.MethodByName(m.Name)
+		if !ok {
+			t.Errorf("WithRetryableClient is missing %s", m.Name)
+			continue
+		}
+		// Compare without the receiver
+		if got, want := methodSig(w.Type), methodSig(m.Type); got != want {
+			t.Errorf("%s: signature %s, want %s", m.Name, got, want)
+		}
+	}
+}
+
+func methodSig(fn reflect.Type) string {
+	var in, out []string
+	for i := 1; i < fn.NumIn(); i++ {
+		in = append(in, fn.In(i).String())
+	}
+	for i := 0; i < fn.NumOut(); i++ {
+		out = append(out, fn.Out(i).String())
+	}
+	return fmt.Sprintf("(%s) (%s)", strings.Join(in, ", "), strings.Join(out, ", "))
+}
+
+func TestRetryableClient_ConversationItems(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	sent := recordOutbound(client)
+	r := NewRetryableClient(client, RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+	if r.Client() != client {
+		t.Fatal("Client does not return the wrapped client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.CreateConversationItem(ctx, NewUserTextItem("Hi")); err != nil {
+		t.Errorf("CreateConversationItem: %v", err)
+	}
+	if err := r.TruncateConversationItem(ctx, "item_1", 0, 500); err != nil {
+		t.Errorf("TruncateConversationItem: %v", err)
+	}
+	if err := r.DeleteConversationItem(ctx, "item_1"); err != nil {
+		t.Errorf("DeleteConversationItem: %v", err)
+	}
+	for _, typ := range []string{"conversation.item.create", "conversation.item.truncate", "conversation.item.delete"} {
+		if n := len(sent.ofType(typ)); n != 1 {
+			t.Errorf("sent %d %s, want 1", n, typ)
+		}
+	}
+
+	// Invalid input fails without reaching the server
+	err := r.DeleteConversationItem(ctx, "")
+	if err == nil || len(sent.ofType("conversation.item.delete")) != 1 {
+		t.Errorf("DeleteConversationItem(\"\") = %v", err)
+	}
+}
+
 func TestCircuitBreaker(t *testing.T) {
 	config := CircuitBreakerConfig{
 		FailureThreshold: 3,
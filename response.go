@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 )
 
 // CreateResponseOptions configures how the assistant should generate a response.
@@ -17,7 +18,9 @@ type CreateResponseOptions struct {
 	// This is added to the conversation context temporarily.
 	Prompt string `json:"prompt,omitempty"`
 
-	// Conversation specifies a conversation ID if managing multiple conversations.
+	// Conversation selects the conversation the response is written to:
+	// ConversationAuto (the default) or ConversationNone for an out-of-band
+	// response that leaves the conversation untouched (see CreateOutOfBandResponse).
 	Conversation string `json:"conversation,omitempty"`
 
 	// Metadata allows attaching custom data to the response for tracking purposes.
@@ -32,61 +35,185 @@ type CreateResponseOptions struct {
 
 	// Input provides explicit input items for the response (advanced usage).
 	Input []any `json:"input,omitempty"`
+
+	// MaxOutputTokens limits the tokens generated for this response, between 1
+	// and 4096, or MaxTokensInf for no limit. Zero keeps the session setting.
+	MaxOutputTokens MaxTokens `json:"max_output_tokens,omitempty"`
+
+	// ToolChoice controls tool use for this response: ToolChoiceAuto,
+	// ToolChoiceNone, ToolChoiceRequired, or ToolChoiceFunction(name) to force
+	// a specific function. Nil keeps the session setting.
+	ToolChoice any `json:"tool_choice,omitempty"`
+
+	// Tools overrides the session's tools for this response. Entries are tool
+	// definitions such as Tool, HTTPTool or ToolRegistry.Definitions values.
+	Tools []any `json:"tools,omitempty"`
+
+	// Tag is a correlation tag for matching the response to the operation that
+	// requested it. It is sent in Metadata under ResponseTagMetadataKey and can be
+	// looked up with Client.ResponseByTag. If empty, a tag is generated.
+	Tag string `json:"-"`
 }
 
 // CreateResponse requests the assistant to generate a response with the given options.
 // Returns the event ID for tracking this response request.
 // The actual response will be delivered through the registered event handlers.
+//
+// Every response is tagged with a correlation ID in its metadata (see
+// CreateTaggedResponse); use ResponseTag on response.done events to recover it.
 func (c *Client) CreateResponse(ctx context.Context, opts CreateResponseOptions) (string, error) {
+	eventID, _, err := c.CreateTaggedResponse(ctx, opts)
+	return eventID, err
+}
+
+// CreateTaggedResponse is like CreateResponse but also returns the correlation tag
+// injected into the response metadata. The tag is opts.Tag if set, otherwise a
+// library-generated ID. Use Client.ResponseByTag to follow the response lifecycle.
+// The correlation ID of ctx or of the client, if any, is added to the metadata
+// as well (see Config.CorrelationID).
+func (c *Client) CreateTaggedResponse(ctx context.Context, opts CreateResponseOptions) (eventID, tag string, err error) {
 	if ctx == nil {
-		return "", NewSendError("response.create", "", errors.New("context cannot be nil"))
+		return "", "", NewSendError("response.create", "", errors.New("context cannot be nil"))
 	}
 
+	opts = c.correlateResponse(ctx, opts)
+
 	// Validate response options
-	if err := ValidateCreateResponseOptions(opts); err != nil {
-		return "", NewSendError("response.create", "", err)
+	if err := c.validate("response.create", func() error { return ValidateCreateResponseOptions(opts) }); err != nil {
+		return "", "", err
 	}
 
-	payload := map[string]any{"type": "response.create", "response": opts}
-	return c.nextEventID(ctx, payload)
+	opts, tag, err = tagResponseOptions(opts)
+	if err != nil {
+		return "", "", NewSendError("response.create", "", err)
+	}
+
+	if err := c.adaptiveWait(ctx, "response.create"); err != nil {
+		return "", "", err
+	}
+	if err := c.throttle(ctx, "response.create", RateLimitRequests, RateLimitRequests, RateLimitTokens); err != nil {
+		return "", "", err
+	}
+
+	// Track the tag before sending so an early response.created or error is not missed
+	eventID = newEventID()
+	c.responseTags.register(tag)
+	c.responseTags.setEventID(tag, eventID)
+	payload := map[string]any{"type": "response.create", "event_id": eventID, "response": opts}
+	if err := c.send(ctx, payload); err != nil {
+		c.ForgetResponseTag(tag)
+		return eventID, "", err
+	}
+	return eventID, tag, nil
 }
 
+// Limits on response metadata entries.
+const (
+	maxMetadataKeyLength   = 64
+	maxMetadataValueLength = 512
+)
+
 // ValidateCreateResponseOptions validates response creation options.
+// All failures are reported together as ValidationErrors.
 func ValidateCreateResponseOptions(opts CreateResponseOptions) error {
+	var errs ValidationErrors
+
 	// Validate modalities
 	if len(opts.Modalities) > 0 {
 		validModalities := map[string]bool{"text": true, "audio": true}
-		for _, modality := range opts.Modalities {
+		for i, modality := range opts.Modalities {
 			if !validModalities[modality] {
-				return fmt.Errorf("invalid modality %q, must be 'text' or 'audio'", modality)
+				errs.add(fmt.Sprintf("modalities[%d]", i), ValidationCodeInvalidValue, "invalid modality %q, must be 'text' or 'audio'", modality)
 			}
 		}
 	}
 
 	// Validate temperature
 	if opts.Temperature < 0.0 || opts.Temperature > 2.0 {
-		return fmt.Errorf("temperature must be between 0.0 and 2.0, got %f", opts.Temperature)
+		errs.add("temperature", ValidationCodeOutOfRange, "temperature must be between 0.0 and 2.0, got %f", opts.Temperature)
 	}
 
 	// Validate prompt length
 	if len(opts.Prompt) > 10000 {
-		return fmt.Errorf("prompt too long (%d characters), maximum is 10000", len(opts.Prompt))
+		errs.add("prompt", ValidationCodeTooLong, "prompt too long (%d characters), maximum is 10000", len(opts.Prompt))
 	}
 
 	// Validate instructions length
-	if len(opts.Instructions) > 10000 {
-		return fmt.Errorf("instructions too long (%d characters), maximum is 10000", len(opts.Instructions))
+	if len(opts.Instructions) > MaxInstructionsLength {
+		errs.add("instructions", ValidationCodeTooLong, "instructions too long (%d characters), maximum is %d", len(opts.Instructions), MaxInstructionsLength)
 	}
 
-	// Validate conversation ID format (if specified)
+	// Validate conversation ID format (if specified); "auto" and "none" (out-of-band) are accepted as is
 	if opts.Conversation != "" {
 		if len(opts.Conversation) > 100 {
-			return fmt.Errorf("conversation ID too long (%d characters), maximum is 100", len(opts.Conversation))
+			errs.add("conversation", ValidationCodeTooLong, "conversation ID too long (%d characters), maximum is 100", len(opts.Conversation))
+		}
+	}
+
+	// Validate metadata: the API accepts string values only
+	keys := make([]string, 0, len(opts.Metadata))
+	for k := range opts.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		field := fmt.Sprintf("metadata[%q]", k)
+		if len(k) > maxMetadataKeyLength {
+			errs.add(field, ValidationCodeTooLong, "metadata key too long (%d characters), maximum is %d", len(k), maxMetadataKeyLength)
+		}
+		v, ok := opts.Metadata[k].(string)
+		if !ok {
+			errs.add(field, ValidationCodeInvalidValue, "metadata values must be strings, got %T", opts.Metadata[k])
+		} else if len(v) > maxMetadataValueLength {
+			errs.add(field, ValidationCodeTooLong, "metadata value too long (%d characters), maximum is %d", len(v), maxMetadataValueLength)
+		}
+	}
+
+	// Validate max output tokens
+	if opts.MaxOutputTokens != 0 && opts.MaxOutputTokens != MaxTokensInf &&
+		(opts.MaxOutputTokens < 1 || opts.MaxOutputTokens > maxOutputTokensLimit) {
+		errs.add("max_output_tokens", ValidationCodeOutOfRange, "max_output_tokens must be between 1 and %d or \"inf\", got %d", maxOutputTokensLimit, opts.MaxOutputTokens)
+	}
+
+	// Validate tool overrides: function definitions with unique names
+	names := make(map[string]bool, len(opts.Tools))
+	for i, tool := range opts.Tools {
+		field := fmt.Sprintf("tools[%d]", i)
+		typ, name, err := toolName(tool)
+		switch {
+		case err != nil:
+			errs.add(field, ValidationCodeInvalidValue, "invalid tool definition: %v", err)
+		case typ != "function":
+			errs.add(field+".type", ValidationCodeInvalidValue, "invalid tool type %q, must be 'function'", typ)
+		case name == "":
+			errs.add(field+".name", ValidationCodeRequired, "tool name is required")
+		case names[name]:
+			errs.add(field+".name", ValidationCodeInvalidValue, "duplicate tool name %q", name)
+		default:
+			names[name] = true
+		}
+	}
+
+	// Validate tool choice
+	switch choice := opts.ToolChoice.(type) {
+	case nil:
+	case string:
+		if choice != ToolChoiceAuto && choice != ToolChoiceNone && choice != ToolChoiceRequired {
+			errs.add("tool_choice", ValidationCodeInvalidValue, "invalid tool_choice %q, must be 'auto', 'none', 'required' or a function", choice)
+		}
+	default:
+		typ, name, err := toolName(choice)
+		switch {
+		case err != nil || typ != "function":
+			errs.add("tool_choice", ValidationCodeInvalidValue, "tool_choice must be 'auto', 'none', 'required' or a function, got %T", choice)
+		case name == "":
+			errs.add("tool_choice.name", ValidationCodeRequired, "tool_choice function name is required")
+		case len(opts.Tools) > 0 && !names[name]:
+			errs.add("tool_choice.name", ValidationCodeInvalidValue, "tool_choice function %q is not in tools", name)
 		}
-		// Could add more specific format validation here
 	}
 
-	return nil
+	return errs.err()
 }
 
 // CancelResponse cancels an in-progress response.
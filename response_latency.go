@@ -0,0 +1,207 @@
+package azrealtime
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// ResponseLatency holds the timing of one response, measured at the client.
+// Durations are measured from when response.create was sent, or from
+// response.created for responses the server started on its own (e.g. after
+// server VAD detected the end of speech). They are zero when the response
+// produced no such event.
+type ResponseLatency struct {
+	ResponseID       string
+	Requested        time.Time     // When response.create was sent; zero if the server started the response
+	TimeToCreated    time.Duration // Until response.created
+	TimeToFirstToken time.Duration // Until the first response.text.delta or response.audio_transcript.delta
+	TimeToFirstAudio time.Duration // Until the first response.audio.delta
+	Total            time.Duration // Until response.done
+}
+
+// LatencyStats aggregates ResponseLatency over the responses a client completed.
+type LatencyStats struct {
+	Responses           uint64          // Completed responses
+	AvgTimeToFirstToken time.Duration   // Mean over responses that produced text
+	AvgTimeToFirstAudio time.Duration   // Mean over responses that produced audio
+	AvgTotal            time.Duration   // Mean time until response.done
+	Last                ResponseLatency // Most recent completed response
+}
+
+// maxPendingCreates bounds how many unanswered response.create sends are
+// remembered; older ones (e.g. rejected by the service) are discarded.
+const maxPendingCreates = 16
+
+// responseTiming is the in-progress timing of one response.
+type responseTiming struct {
+	latency ResponseLatency
+	start   time.Time
+}
+
+// latencyTracker measures ResponseLatency for Client.Stats and OnResponseLatency.
+type latencyTracker struct {
+	mu      sync.Mutex
+	pending []time.Time // Send times of response.create awaiting response.created
+	active  map[string]*responseTiming
+
+	responses      uint64
+	tokenResponses uint64
+	audioResponses uint64
+	sumToken       time.Duration
+	sumAudio       time.Duration
+	sumTotal       time.Duration
+	last           ResponseLatency
+}
+
+// requested records that response.create was sent at now.
+func (t *latencyTracker) requested(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, now)
+	if len(t.pending) > maxPendingCreates {
+		t.pending = t.pending[1:]
+	}
+}
+
+// awaiting reports whether some active response has not yet seen a delta of typ,
+// so deltas need not be parsed once every response has its first one.
+func (t *latencyTracker) awaiting(typ string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range t.active {
+		if typ == "response.audio.delta" && r.latency.TimeToFirstAudio == 0 ||
+			typ != "response.audio.delta" && r.latency.TimeToFirstToken == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// observe records an inbound event at now and returns the latency of a
+// response that just completed.
+func (t *latencyTracker) observe(typ string, raw []byte, now time.Time) (ResponseLatency, bool) {
+	switch typ {
+	case "response.created", "response.done":
+	case "response.text.delta", "response.audio_transcript.delta", "response.audio.delta":
+		if !t.awaiting(typ) {
+			return ResponseLatency{}, false
+		}
+	default:
+		return ResponseLatency{}, false
+	}
+	var e struct {
+		ResponseID string `json:"response_id"`
+		Response   struct {
+			ID string `json:"id"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return ResponseLatency{}, false
+	}
+	id := e.ResponseID
+	if id == "" {
+		id = e.Response.ID
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if typ == "response.created" {
+		r := &responseTiming{latency: ResponseLatency{ResponseID: id}, start: now}
+		if len(t.pending) > 0 {
+			r.start = t.pending[0]
+			r.latency.Requested = t.pending[0]
+			t.pending = t.pending[1:]
+		}
+		r.latency.TimeToCreated = now.Sub(r.start)
+		if t.active == nil {
+			t.active = make(map[string]*responseTiming)
+		}
+		t.active[id] = r
+		return ResponseLatency{}, false
+	}
+
+	r, ok := t.active[id]
+	if !ok {
+		return ResponseLatency{}, false
+	}
+	elapsed := now.Sub(r.start)
+	if elapsed <= 0 {
+		elapsed = time.Nanosecond // Keep a measured latency distinguishable from none
+	}
+	switch typ {
+	case "response.audio.delta":
+		if r.latency.TimeToFirstAudio == 0 {
+			r.latency.TimeToFirstAudio = elapsed
+		}
+		return ResponseLatency{}, false
+	case "response.text.delta", "response.audio_transcript.delta":
+		if r.latency.TimeToFirstToken == 0 {
+			r.latency.TimeToFirstToken = elapsed
+		}
+		return ResponseLatency{}, false
+	}
+
+	// response.done
+	delete(t.active, id)
+	r.latency.Total = elapsed
+	t.responses++
+	t.sumTotal += elapsed
+	if d := r.latency.TimeToFirstToken; d > 0 {
+		t.tokenResponses++
+		t.sumToken += d
+	}
+	if d := r.latency.TimeToFirstAudio; d > 0 {
+		t.audioResponses++
+		t.sumAudio += d
+	}
+	t.last = r.latency
+	return r.latency, true
+}
+
+// stats returns the aggregated latencies.
+func (t *latencyTracker) stats() LatencyStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := LatencyStats{Responses: t.responses, Last: t.last}
+	if t.responses > 0 {
+		s.AvgTotal = t.sumTotal / time.Duration(t.responses)
+	}
+	if t.tokenResponses > 0 {
+		s.AvgTimeToFirstToken = t.sumToken / time.Duration(t.tokenResponses)
+	}
+	if t.audioResponses > 0 {
+		s.AvgTimeToFirstAudio = t.sumAudio / time.Duration(t.audioResponses)
+	}
+	return s
+}
+
+// reset forgets in-progress responses, which cannot complete on a new connection.
+func (t *latencyTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = nil
+	t.active = nil
+}
+
+// OnResponseLatency registers a callback that receives the ResponseLatency of
+// each response when its response.done arrives, before the OnResponseDone callback.
+func (c *Client) OnResponseLatency(fn func(ResponseLatency)) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	c.onResponseLatency = fn
+}
+
+// observeLatency feeds an inbound event to the latency tracker.
+func (c *Client) observeLatency(typ string, raw []byte) {
+	l, done := c.latency.observe(typ, raw, time.Now())
+	if !done {
+		return
+	}
+	c.handlerMu.RLock()
+	fn := c.onResponseLatency
+	c.handlerMu.RUnlock()
+	if fn != nil {
+		fn(l)
+	}
+}
@@ -0,0 +1,104 @@
+package azrealtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	var lt latencyTracker
+	t0 := time.Now()
+	ev := func(typ, id string) []byte {
+		if typ == "response.created" || typ == "response.done" {
+			return []byte(`{"type":"` + typ + `","response":{"id":"` + id + `"}}`)
+		}
+		return []byte(`{"type":"` + typ + `","response_id":"` + id + `"}`)
+	}
+
+	lt.requested(t0)
+	lt.observe("response.created", ev("response.created", "r1"), t0.Add(50*time.Millisecond))
+	lt.observe("response.audio_transcript.delta", ev("response.audio_transcript.delta", "r1"), t0.Add(200*time.Millisecond))
+	lt.observe("response.audio.delta", ev("response.audio.delta", "r1"), t0.Add(300*time.Millisecond))
+	lt.observe("response.audio.delta", ev("response.audio.delta", "r1"), t0.Add(400*time.Millisecond))
+	l, done := lt.observe("response.done", ev("response.done", "r1"), t0.Add(time.Second))
+	if !done {
+		t.Fatal("response.done did not complete the response")
+	}
+	want := ResponseLatency{ResponseID: "r1", Requested: t0, TimeToCreated: 50 * time.Millisecond,
+		TimeToFirstToken: 200 * time.Millisecond, TimeToFirstAudio: 300 * time.Millisecond, Total: time.Second}
+	if l != want {
+		t.Errorf("latency = %+v, want %+v", l, want)
+	}
+
+	// A response started by server VAD is measured from response.created
+	t1 := t0.Add(2 * time.Second)
+	lt.observe("response.created", ev("response.created", "r2"), t1)
+	lt.observe("response.text.delta", ev("response.text.delta", "r2"), t1.Add(100*time.Millisecond))
+	l, _ = lt.observe("response.done", ev("response.done", "r2"), t1.Add(500*time.Millisecond))
+	if !l.Requested.IsZero() || l.TimeToFirstToken != 100*time.Millisecond || l.TimeToFirstAudio != 0 || l.Total != 500*time.Millisecond {
+		t.Errorf("server-initiated latency = %+v", l)
+	}
+
+	s := lt.stats()
+	if s.Responses != 2 || s.AvgTimeToFirstToken != 150*time.Millisecond || s.AvgTimeToFirstAudio != 300*time.Millisecond ||
+		s.AvgTotal != 750*time.Millisecond || s.Last.ResponseID != "r2" {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestClient_ResponseLatency(t *testing.T) {
+	const firstDelta = 150 * time.Millisecond
+	client, _ := dialProfile(t, &MockProfile{Response: SyntheticResponse(firstDelta, 20*time.Millisecond, 3)})
+
+	got := make(chan ResponseLatency, 1)
+	client.OnResponseLatency(func(l ResponseLatency) { got <- l })
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{Modalities: []string{"audio", "text"}}); err != nil {
+		t.Fatalf("CreateResponse: %v", err)
+	}
+
+	var l ResponseLatency
+	select {
+	case l = <-got:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnResponseLatency was not called")
+	}
+	if l.ResponseID != "resp_profile" || l.Requested.IsZero() {
+		t.Errorf("latency = %+v", l)
+	}
+	if l.TimeToFirstAudio < firstDelta || l.TimeToFirstAudio > firstDelta+time.Second {
+		t.Errorf("TimeToFirstAudio = %v, want about %v", l.TimeToFirstAudio, firstDelta)
+	}
+	if l.Total < l.TimeToFirstAudio {
+		t.Errorf("Total %v < TimeToFirstAudio %v", l.Total, l.TimeToFirstAudio)
+	}
+
+	st := client.Stats().Latency
+	if st.Responses != 1 || st.AvgTimeToFirstAudio != l.TimeToFirstAudio || st.Last != l {
+		t.Errorf("Stats().Latency = %+v", st)
+	}
+}
+
+func TestClient_ResponseLatencyGANames(t *testing.T) {
+	const id = "resp_ga"
+	client, _ := dialProfile(t, &MockProfile{Response: []MockStep{
+		{Event: ResponseCreated{Type: "response.created", Response: ResponseObject{ID: id, Status: "in_progress"}}},
+		{Delay: 50 * time.Millisecond, Event: ResponseTextDelta{Type: "response.output_audio_transcript.delta", ResponseID: id, Delta: "Hi"}},
+		{Delay: 50 * time.Millisecond, Event: ResponseAudioDelta{Type: "response.output_audio.delta", ResponseID: id, DeltaBase64: "AAAA"}},
+		{Event: ResponseDone{Type: "response.done", Response: ResponseObject{ID: id, Status: "completed"}}},
+	}})
+
+	got := make(chan ResponseLatency, 1)
+	client.OnResponseLatency(func(l ResponseLatency) { got <- l })
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{Modalities: []string{"audio", "text"}}); err != nil {
+		t.Fatalf("CreateResponse: %v", err)
+	}
+	select {
+	case l := <-got:
+		if l.TimeToFirstToken == 0 || l.TimeToFirstAudio <= l.TimeToFirstToken {
+			t.Errorf("latency = %+v, want first token and first audio from GA deltas", l)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnResponseLatency was not called")
+	}
+}
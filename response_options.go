@@ -0,0 +1,69 @@
+package azrealtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// MaxTokens limits the output tokens of a response. The zero value leaves the
+// limit to the server (the session setting); use MaxTokensInf for no limit.
+type MaxTokens int
+
+// MaxTokensInf requests the largest output the model allows ("inf").
+const MaxTokensInf MaxTokens = -1
+
+// maxOutputTokensLimit is the largest finite MaxOutputTokens the service accepts.
+const maxOutputTokensLimit = 4096
+
+// MarshalJSON encodes the limit as a number, or as "inf" for MaxTokensInf.
+func (m MaxTokens) MarshalJSON() ([]byte, error) {
+	if m == MaxTokensInf {
+		return []byte(`"inf"`), nil
+	}
+	return []byte(strconv.Itoa(int(m))), nil
+}
+
+// UnmarshalJSON accepts a number or "inf".
+func (m *MaxTokens) UnmarshalJSON(data []byte) error {
+	if string(data) == `"inf"` {
+		*m = MaxTokensInf
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("azrealtime: max tokens must be a number or \"inf\": %w", err)
+	}
+	*m = MaxTokens(n)
+	return nil
+}
+
+// Values for CreateResponseOptions.ToolChoice. To force a specific function,
+// use ToolChoiceFunction.
+const (
+	ToolChoiceAuto     = "auto"     // The model decides whether to call a tool (the default)
+	ToolChoiceNone     = "none"     // The model must not call tools
+	ToolChoiceRequired = "required" // The model must call at least one tool
+)
+
+// ToolChoiceFunction returns a tool choice that forces a call to the named function.
+func ToolChoiceFunction(name string) map[string]any {
+	return map[string]any{"type": "function", "name": name}
+}
+
+// toolName returns the type and name of a tool definition as it is sent to
+// the server, for any value that encodes as one (Tool, HTTPTool, a map, ...).
+func toolName(tool any) (typ, name string, err error) {
+	data, err := json.Marshal(tool)
+	if err != nil {
+		return "", "", err
+	}
+	var def struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &def); err != nil {
+		return "", "", fmt.Errorf("tool must encode as a JSON object")
+	}
+	return def.Type, def.Name, nil
+}
@@ -0,0 +1,81 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMaxTokens_JSON(t *testing.T) {
+	for _, tt := range []struct {
+		in   MaxTokens
+		want string
+	}{
+		{1024, `1024`},
+		{MaxTokensInf, `"inf"`},
+	} {
+		data, err := json.Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("Marshal(%d): %v", tt.in, err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("Marshal(%d) = %s, want %s", tt.in, data, tt.want)
+		}
+		var back MaxTokens
+		if err := json.Unmarshal(data, &back); err != nil || back != tt.in {
+			t.Errorf("Unmarshal(%s) = %d, %v", data, back, err)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`"lots"`), new(MaxTokens)); err == nil {
+		t.Error("expected error for non-numeric limit")
+	}
+
+	// The zero value is omitted so the session setting applies
+	data, _ := json.Marshal(CreateResponseOptions{})
+	if string(data) != `{}` {
+		t.Errorf("empty options = %s", data)
+	}
+}
+
+func TestClient_CreateResponseOverrides(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	sent := recordOutbound(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.CreateResponse(ctx, CreateResponseOptions{
+		MaxOutputTokens: MaxTokensInf,
+		Tools:           []any{Tool{Name: "lookup_order", Description: "Look up an order"}},
+		ToolChoice:      ToolChoiceRequired,
+	})
+	if err != nil {
+		t.Fatalf("CreateResponse: %v", err)
+	}
+
+	creates := sent.ofType("response.create")
+	if len(creates) != 1 {
+		t.Fatalf("sent %d response.create, want 1", len(creates))
+	}
+	resp := creates[0]["response"].(map[string]any)
+	if resp["max_output_tokens"] != "inf" {
+		t.Errorf("max_output_tokens = %v", resp["max_output_tokens"])
+	}
+	if resp["tool_choice"] != ToolChoiceRequired {
+		t.Errorf("tool_choice = %v", resp["tool_choice"])
+	}
+	tools, _ := resp["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["name"] != "lookup_order" {
+		t.Errorf("tools = %v", resp["tools"])
+	}
+
+	// Invalid options are rejected before sending
+	_, err = client.CreateResponse(ctx, CreateResponseOptions{ToolChoice: ToolChoiceFunction("")})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if n := len(sent.ofType("response.create")); n != 1 {
+		t.Errorf("sent %d response.create after rejection, want 1", n)
+	}
+}
@@ -0,0 +1,166 @@
+package azrealtime
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ResponseTagMetadataKey is the response metadata key used to carry the
+// correlation tag injected by CreateResponse. The server echoes metadata back
+// on response.created and response.done, which lets the client match those
+// events to the request that produced them.
+const ResponseTagMetadataKey = "azrealtime_tag"
+
+// maxResponseMetadataPairs is the maximum number of metadata key-value pairs
+// accepted by the API for a single response.
+const maxResponseMetadataPairs = 16
+
+// maxTrackedResponseTags bounds how many tagged responses a client remembers.
+// Once exceeded, the oldest entries are forgotten.
+const maxTrackedResponseTags = 256
+
+// TaggedResponse describes the lifecycle of a response created with a tag.
+type TaggedResponse struct {
+	Tag        string          // Correlation tag attached to the response metadata
+	EventID    string          // Event ID of the response.create request
+	ResponseID string          // Server-assigned response ID (empty until response.created)
+	Status     string          // Last known response status
+	Response   *ResponseObject // Last response object received (nil until response.created)
+	Done       bool            // True once response.done has been received
+}
+
+// responseTagTracker records tagged responses so they can be looked up by tag.
+type responseTagTracker struct {
+	mu      sync.Mutex
+	entries map[string]*TaggedResponse
+	order   []string // Insertion order, used to evict the oldest entries
+}
+
+var responseTagCounter atomic.Uint64
+
+// newResponseTag generates a library-unique correlation tag.
+func newResponseTag() string {
+	return fmt.Sprintf("tag_%d_%d", time.Now().UnixNano(), responseTagCounter.Add(1))
+}
+
+// ResponseTag returns the correlation tag carried in a response's metadata,
+// or an empty string if the response was not tagged.
+func ResponseTag(r ResponseObject) string {
+	if r.Metadata == nil {
+		return ""
+	}
+	tag, _ := r.Metadata[ResponseTagMetadataKey].(string)
+	return tag
+}
+
+// ResponseByTag returns the tracked state of the response created with the given tag.
+// The boolean is false if the tag is unknown or has been evicted.
+func (c *Client) ResponseByTag(tag string) (TaggedResponse, bool) {
+	c.responseTags.mu.Lock()
+	defer c.responseTags.mu.Unlock()
+	e, ok := c.responseTags.entries[tag]
+	if !ok {
+		return TaggedResponse{}, false
+	}
+	return *e, true
+}
+
+// ForgetResponseTag stops tracking the response with the given tag.
+func (c *Client) ForgetResponseTag(tag string) {
+	t := &c.responseTags
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[tag]; !ok {
+		return
+	}
+	delete(t.entries, tag)
+	for i, v := range t.order {
+		if v == tag {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// tagResponseOptions returns a copy of opts whose metadata carries a correlation tag,
+// along with the tag itself. A caller-supplied Tag is used as-is; otherwise one is generated.
+func tagResponseOptions(opts CreateResponseOptions) (CreateResponseOptions, string, error) {
+	tag := opts.Tag
+	if tag == "" {
+		tag = newResponseTag()
+	}
+
+	md := make(map[string]any, len(opts.Metadata)+1)
+	for k, v := range opts.Metadata {
+		md[k] = v
+	}
+	md[ResponseTagMetadataKey] = tag
+	if len(md) > maxResponseMetadataPairs {
+		var errs ValidationErrors
+		errs.add("metadata", ValidationCodeTooLong, "metadata has too many entries (%d), maximum is %d including the correlation tag", len(md), maxResponseMetadataPairs)
+		return opts, "", errs
+	}
+
+	opts.Metadata = md
+	opts.Tag = tag
+	return opts, tag, nil
+}
+
+// register starts tracking a newly requested tagged response.
+func (t *responseTagTracker) register(tag string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == nil {
+		t.entries = make(map[string]*TaggedResponse)
+	}
+	if _, ok := t.entries[tag]; !ok {
+		t.order = append(t.order, tag)
+	}
+	t.entries[tag] = &TaggedResponse{Tag: tag}
+
+	for len(t.order) > maxTrackedResponseTags {
+		delete(t.entries, t.order[0])
+		t.order = t.order[1:]
+	}
+}
+
+// setEventID records the event ID of the response.create request for tag.
+func (t *responseTagTracker) setEventID(tag, eventID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.entries[tag]; ok {
+		e.EventID = eventID
+	}
+}
+
+// tagForEvent returns the tag of the response requested by eventID.
+func (t *responseTagTracker) tagForEvent(eventID string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for tag, e := range t.entries {
+		if e.EventID == eventID {
+			return tag, true
+		}
+	}
+	return "", false
+}
+
+// observe updates the tracked state from a response.created or response.done event.
+func (t *responseTagTracker) observe(r ResponseObject, done bool) {
+	tag := ResponseTag(r)
+	if tag == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[tag]
+	if !ok {
+		return
+	}
+	e.ResponseID = r.ID
+	e.Status = r.Status
+	e.Response = &r
+	e.Done = e.Done || done
+}
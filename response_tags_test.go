@@ -0,0 +1,99 @@
+package azrealtime
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTagResponseOptions(t *testing.T) {
+	callerMD := map[string]any{"order_id": "42"}
+	opts, tag, err := tagResponseOptions(CreateResponseOptions{Metadata: callerMD})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(tag, "tag_") {
+		t.Errorf("expected generated tag, got %q", tag)
+	}
+	if opts.Metadata[ResponseTagMetadataKey] != tag || opts.Metadata["order_id"] != "42" {
+		t.Errorf("unexpected metadata: %v", opts.Metadata)
+	}
+	if _, ok := callerMD[ResponseTagMetadataKey]; ok {
+		t.Error("caller metadata map should not be modified")
+	}
+
+	_, tag, err = tagResponseOptions(CreateResponseOptions{Tag: "checkout"})
+	if err != nil || tag != "checkout" {
+		t.Errorf("expected caller tag to be used, got %q (%v)", tag, err)
+	}
+
+	full := make(map[string]any)
+	for i := 0; i < maxResponseMetadataPairs; i++ {
+		full[string(rune('a'+i))] = i
+	}
+	if _, _, err := tagResponseOptions(CreateResponseOptions{Metadata: full}); err == nil {
+		t.Error("expected error when metadata has no room for the tag")
+	}
+}
+
+func TestResponseTagTracker_Eviction(t *testing.T) {
+	var tr responseTagTracker
+	for i := 0; i < maxTrackedResponseTags+10; i++ {
+		tr.register(newResponseTag())
+	}
+	if len(tr.entries) != maxTrackedResponseTags || len(tr.order) != maxTrackedResponseTags {
+		t.Errorf("expected %d tracked tags, got %d entries / %d order", maxTrackedResponseTags, len(tr.entries), len(tr.order))
+	}
+}
+
+func TestClient_ResponseByTag(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	doneTags := make(chan string, 1)
+	var once sync.Once
+	client.OnResponseDone(func(e ResponseDone) {
+		once.Do(func() { doneTags <- ResponseTag(e.Response) })
+	})
+
+	eventID, tag, err := client.CreateTaggedResponse(ctx, CreateResponseOptions{Modalities: []string{"text"}, Tag: "order-42"})
+	if err != nil {
+		t.Fatalf("CreateTaggedResponse: %v", err)
+	}
+	if tag != "order-42" {
+		t.Errorf("expected tag order-42, got %q", tag)
+	}
+
+	select {
+	case got := <-doneTags:
+		if got != tag {
+			t.Errorf("expected response.done to carry tag %q, got %q", tag, got)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for response.done")
+	}
+
+	tr, ok := client.ResponseByTag(tag)
+	if !ok {
+		t.Fatal("expected tag to be tracked")
+	}
+	if tr.EventID != eventID || tr.ResponseID != "resp_mock_123" || !tr.Done || tr.Status != "completed" {
+		t.Errorf("unexpected tracked response: %+v", tr)
+	}
+
+	client.ForgetResponseTag(tag)
+	if _, ok := client.ResponseByTag(tag); ok {
+		t.Error("expected tag to be forgotten")
+	}
+}
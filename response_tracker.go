@@ -0,0 +1,387 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// maxCompletedResponses bounds how many finished responses a ResponseTracker
+// keeps for Await. Once exceeded, the oldest results are dropped.
+const maxCompletedResponses = 64
+
+// CompletedResponse is the outcome of a response, assembled from its events.
+type CompletedResponse struct {
+	ID            string             // Server-assigned response ID
+	Tag           string             // Correlation tag (see CreateTaggedResponse)
+	Status        string             // Final status ("completed", "cancelled", "failed", "incomplete")
+	StatusDetails map[string]any     // Details for statuses other than "completed"
+	Usage         *ResponseUsage     // Token usage, if reported
+	Output        []ConversationItem // Output items from response.done
+	Text          string             // Text output, from response.text.delta events
+	Transcript    string             // Transcript of the audio output
+	Audio         []byte             // Audio output, decoded (PCM16 unless the session uses G.711)
+}
+
+// ResponseTracker assembles every response the client receives and lets
+// callers wait for a specific one to finish, instead of wiring maps of
+// channels in event handlers. Create one with Client.TrackResponses; it does
+// not use or replace registered handlers.
+//
+// Finished responses are kept until maxCompletedResponses (64) newer ones
+// have completed, so Await soon after requesting a response. Responses still
+// in progress when the client reconnects are dropped, and awaiting them fails
+// with ErrResponseInterrupted.
+type ResponseTracker struct {
+	c *Client
+
+	mu       sync.Mutex
+	building map[string]*responseBuilder  // In-progress responses by ID
+	results  map[string]CompletedResponse // Finished responses by ID
+	tags     map[string]string            // Tag -> response ID, for finished responses
+	failed   map[string]error             // Failed responses by "id:" / "tag:" key
+	order    []string                     // Finished response IDs, oldest first
+	waiters  map[string][]chan struct{}   // Await and AwaitTag callers by "id:" / "tag:" key
+	stopped  bool
+}
+
+type responseBuilder struct {
+	tag        string
+	text       strings.Builder
+	transcript strings.Builder
+	audio      []byte
+}
+
+// TrackResponses starts tracking responses. Call Stop on the tracker when it
+// is no longer needed.
+func (c *Client) TrackResponses() *ResponseTracker {
+	t := &ResponseTracker{
+		c:        c,
+		building: make(map[string]*responseBuilder),
+		results:  make(map[string]CompletedResponse),
+		tags:     make(map[string]string),
+		failed:   make(map[string]error),
+		waiters:  make(map[string][]chan struct{}),
+	}
+	c.trackers.mu.Lock()
+	c.trackers.list = append(c.trackers.list, t)
+	c.trackers.mu.Unlock()
+	return t
+}
+
+// Stop detaches the tracker from the client and releases its results.
+// Pending Await calls return ErrClosed.
+func (t *ResponseTracker) Stop() {
+	set := &t.c.trackers
+	set.mu.Lock()
+	for i, other := range set.list {
+		if other == t {
+			set.list = append(set.list[:i], set.list[i+1:]...)
+			break
+		}
+	}
+	set.mu.Unlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	t.building, t.results, t.tags, t.failed, t.order = nil, nil, nil, nil, nil
+	for _, chs := range t.waiters {
+		for _, ch := range chs {
+			close(ch)
+		}
+	}
+	t.waiters = nil
+}
+
+// Await waits until the response with the given ID is done and returns it.
+// The response may have finished already. It returns ctx's error if ctx ends
+// first, and ErrClosed (or the client's error) if the client closes or the
+// tracker is stopped.
+func (t *ResponseTracker) Await(ctx context.Context, responseID string) (CompletedResponse, error) {
+	return t.await(ctx, "id:"+responseID, func() (CompletedResponse, bool, error) {
+		if err, ok := t.failed["id:"+responseID]; ok {
+			return CompletedResponse{}, true, err
+		}
+		r, ok := t.results[responseID]
+		return r, ok, nil
+	})
+}
+
+// AwaitTag is like Await for the response created with the given correlation
+// tag, as returned by CreateTaggedResponse. It also fails if the server
+// rejects that response.create request with an error event.
+func (t *ResponseTracker) AwaitTag(ctx context.Context, tag string) (CompletedResponse, error) {
+	return t.await(ctx, "tag:"+tag, func() (CompletedResponse, bool, error) {
+		if err, ok := t.failed["tag:"+tag]; ok {
+			return CompletedResponse{}, true, err
+		}
+		id, ok := t.tags[tag]
+		if !ok {
+			return CompletedResponse{}, false, nil
+		}
+		r, ok := t.results[id]
+		return r, ok, nil
+	})
+}
+
+// await waits on key until lookup, called with t.mu held, finds a result.
+func (t *ResponseTracker) await(ctx context.Context, key string, lookup func() (CompletedResponse, bool, error)) (CompletedResponse, error) {
+	for {
+		t.mu.Lock()
+		if t.stopped {
+			t.mu.Unlock()
+			return CompletedResponse{}, ErrClosed
+		}
+		if r, ok, err := lookup(); ok {
+			t.mu.Unlock()
+			return r, err
+		}
+		ch := make(chan struct{})
+		t.waiters[key] = append(t.waiters[key], ch)
+		t.mu.Unlock()
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			t.dropWaiter(key, ch)
+			return CompletedResponse{}, ctx.Err()
+		case <-t.c.closedCh:
+			t.dropWaiter(key, ch)
+			if err := t.c.Err(); err != nil {
+				return CompletedResponse{}, err
+			}
+			return CompletedResponse{}, ErrClosed
+		}
+	}
+}
+
+func (t *ResponseTracker) dropWaiter(key string, ch chan struct{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	chs := t.waiters[key]
+	for i, other := range chs {
+		if other == ch {
+			t.waiters[key] = append(chs[:i], chs[i+1:]...)
+			break
+		}
+	}
+	if len(t.waiters[key]) == 0 {
+		delete(t.waiters, key)
+	}
+}
+
+// wakeLocked releases the callers waiting on key.
+func (t *ResponseTracker) wakeLocked(key string) {
+	for _, ch := range t.waiters[key] {
+		close(ch)
+	}
+	delete(t.waiters, key)
+}
+
+// builderLocked returns the builder for a response, creating it if needed.
+func (t *ResponseTracker) builderLocked(id string) *responseBuilder {
+	b, ok := t.building[id]
+	if !ok {
+		b = &responseBuilder{}
+		t.building[id] = b
+	}
+	return b
+}
+
+func (t *ResponseTracker) responseCreated(r ResponseObject) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || r.ID == "" {
+		return
+	}
+	t.builderLocked(r.ID).tag = ResponseTag(r)
+}
+
+func (t *ResponseTracker) textDelta(responseID, delta string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || responseID == "" {
+		return
+	}
+	t.builderLocked(responseID).text.WriteString(delta)
+}
+
+func (t *ResponseTracker) transcriptDelta(responseID, delta string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || responseID == "" {
+		return
+	}
+	t.builderLocked(responseID).transcript.WriteString(delta)
+}
+
+func (t *ResponseTracker) audioDelta(responseID string, pcm []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || responseID == "" {
+		return
+	}
+	b := t.builderLocked(responseID)
+	b.audio = append(b.audio, pcm...)
+}
+
+func (t *ResponseTracker) responseDone(r ResponseObject) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || r.ID == "" {
+		return
+	}
+	b := t.builderLocked(r.ID)
+	delete(t.building, r.ID)
+
+	res := CompletedResponse{
+		ID:            r.ID,
+		Tag:           ResponseTag(r),
+		Status:        r.Status,
+		StatusDetails: r.StatusDetails,
+		Usage:         r.Usage,
+		Output:        r.Output,
+		Text:          b.text.String(),
+		Transcript:    b.transcript.String(),
+		Audio:         b.audio,
+	}
+	if res.Tag == "" {
+		res.Tag = b.tag
+	}
+	if res.Text == "" && res.Transcript == "" {
+		res.Text, res.Transcript = outputText(r.Output)
+	}
+
+	if _, ok := t.results[r.ID]; !ok {
+		t.order = append(t.order, r.ID)
+	}
+	t.results[r.ID] = res
+	if res.Tag != "" {
+		t.tags[res.Tag] = r.ID
+	}
+	for len(t.order) > maxCompletedResponses {
+		old := t.results[t.order[0]]
+		delete(t.results, old.ID)
+		if old.Tag != "" {
+			delete(t.tags, old.Tag)
+		}
+		t.order = t.order[1:]
+	}
+
+	t.wakeLocked("id:" + r.ID)
+	if res.Tag != "" {
+		t.wakeLocked("tag:" + res.Tag)
+	}
+}
+
+// createRejected fails AwaitTag for tag after the server rejected its
+// response.create request.
+func (t *ResponseTracker) createRejected(tag string, e ErrorDetails) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	if e.Code != "" {
+		t.failLocked("tag:"+tag, fmt.Errorf("azrealtime: response rejected: %s (%s)", e.Message, e.Code))
+	} else {
+		t.failLocked("tag:"+tag, fmt.Errorf("azrealtime: response rejected: %s", e.Message))
+	}
+}
+
+// reconnected drops the responses in progress on the old connection, which
+// will never finish, and fails their Await and AwaitTag calls.
+func (t *ResponseTracker) reconnected() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	for id, b := range t.building {
+		t.failLocked("id:"+id, ErrResponseInterrupted)
+		if b.tag != "" {
+			t.failLocked("tag:"+b.tag, ErrResponseInterrupted)
+		}
+	}
+	t.building = make(map[string]*responseBuilder)
+}
+
+// failLocked records err as the outcome for key and wakes its waiters.
+func (t *ResponseTracker) failLocked(key string, err error) {
+	t.failed[key] = err
+	if len(t.failed) > maxCompletedResponses {
+		for k := range t.failed {
+			if k != key {
+				delete(t.failed, k)
+				break
+			}
+		}
+	}
+	t.wakeLocked(key)
+}
+
+// outputText collects the text and audio transcripts of output message items,
+// for servers that send them only in response.done.
+func outputText(items []ConversationItem) (text, transcript string) {
+	var texts, transcripts []string
+	for _, item := range items {
+		for _, part := range item.Content {
+			if part.Text != "" {
+				texts = append(texts, part.Text)
+			}
+			if part.Transcript != "" {
+				transcripts = append(transcripts, part.Transcript)
+			}
+		}
+	}
+	return strings.Join(texts, "\n"), strings.Join(transcripts, "\n")
+}
+
+// responseTrackers are the trackers created with Client.TrackResponses.
+type responseTrackers struct {
+	mu   sync.RWMutex
+	list []*ResponseTracker
+}
+
+// each calls fn for every tracker.
+func (s *responseTrackers) each(fn func(*ResponseTracker)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, t := range s.list {
+		fn(t)
+	}
+}
+
+// active reports whether any tracker is attached.
+func (s *responseTrackers) active() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.list) > 0
+}
+
+// audioDelta decodes the delta once for all trackers.
+func (s *responseTrackers) audioDelta(e ResponseAudioDelta) {
+	if !s.active() {
+		return
+	}
+	pcm, err := base64.StdEncoding.DecodeString(e.DeltaBase64)
+	if err != nil {
+		return
+	}
+	s.each(func(t *ResponseTracker) { t.audioDelta(e.ResponseID, pcm) })
+}
+
+// errorReceived fails AwaitTag for a rejected response.create request.
+func (s *responseTrackers) errorReceived(c *Client, e ErrorDetails) {
+	if e.EventID == "" || !s.active() {
+		return
+	}
+	tag, ok := c.responseTags.tagForEvent(e.EventID)
+	if !ok {
+		return
+	}
+	s.each(func(t *ResponseTracker) { t.createRejected(tag, e) })
+}
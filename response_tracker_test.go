@@ -0,0 +1,156 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseTracker_Await(t *testing.T) {
+	reply := SyntheticResponse(10*time.Millisecond, 10*time.Millisecond, 5)
+	reply = append(reply[:len(reply)-1],
+		MockStep{Event: ResponseAudioTranscriptDelta{Type: "response.audio_transcript.delta", ResponseID: "resp_profile", Delta: "Hello "}},
+		MockStep{Event: ResponseAudioTranscriptDelta{Type: "response.audio_transcript.delta", ResponseID: "resp_profile", Delta: "world"}},
+		reply[len(reply)-1],
+	)
+	client, _ := dialProfile(t, &MockProfile{Response: reply})
+	tracker := client.TrackResponses()
+	defer tracker.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.CreateResponse(ctx, CreateResponseOptions{Modalities: []string{"audio", "text"}}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	resp, err := tracker.Await(ctx, "resp_profile")
+	if err != nil {
+		t.Fatalf("Await: %v", err)
+	}
+	if resp.Status != "completed" || resp.Transcript != "Hello world" {
+		t.Errorf("response = %+v", resp)
+	}
+	if want := 5 * PCM16BytesFor(100, DefaultSampleRate); len(resp.Audio) != want {
+		t.Errorf("got %d audio bytes, want %d", len(resp.Audio), want)
+	}
+
+	// A finished response is returned immediately
+	again, err := tracker.Await(ctx, "resp_profile")
+	if err != nil || again.Transcript != resp.Transcript {
+		t.Errorf("second Await = %+v, %v", again, err)
+	}
+}
+
+func TestResponseTracker_AwaitTag(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	tracker := client.TrackResponses()
+	defer tracker.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, tag, err := client.CreateTaggedResponse(ctx, CreateResponseOptions{Modalities: []string{"text"}})
+	if err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	resp, err := tracker.AwaitTag(ctx, tag)
+	if err != nil {
+		t.Fatalf("AwaitTag: %v", err)
+	}
+	if resp.Tag != tag || resp.Text != "Hello from mock server!" || resp.Usage == nil || resp.Usage.TotalTokens != 150 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestResponseTracker_Rejected(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	tracker := client.TrackResponses()
+	defer tracker.Stop()
+
+	// Simulate the server rejecting a response.create it received
+	client.responseTags.register("tag_rejected")
+	client.responseTags.setEventID("tag_rejected", "evt_rejected")
+	raw, _ := json.Marshal(ErrorEvent{Type: "error", Error: ErrorDetails{
+		Type: ErrorTypeInvalidRequest, Code: "conversation_already_has_active_response", Message: "busy", EventID: "evt_rejected",
+	}})
+	go client.dispatch(envelope{Type: "error"}, raw)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := tracker.AwaitTag(ctx, "tag_rejected")
+	if err == nil || !strings.Contains(err.Error(), "conversation_already_has_active_response") {
+		t.Errorf("AwaitTag error = %v", err)
+	}
+}
+
+func TestResponseTracker_StopAndTimeout(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	tracker := client.TrackResponses()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := tracker.Await(ctx, "resp_never"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Await error = %v, want deadline exceeded", err)
+	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		_, err := tracker.Await(context.Background(), "resp_never")
+		stopped <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	tracker.Stop()
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("Await after Stop = %v, want ErrClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Await did not return after Stop")
+	}
+	if client.trackers.active() {
+		t.Error("stopped tracker is still attached")
+	}
+}
+
+func TestResponseTracker_Reconnect(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	tracker := client.TrackResponses()
+	defer tracker.Stop()
+
+	// A response is still streaming when the connection is replaced
+	raw, _ := json.Marshal(ResponseCreated{Type: "response.created", Response: ResponseObject{
+		ID: "resp_open", Status: "in_progress", Metadata: map[string]any{ResponseTagMetadataKey: "tag_open"},
+	}})
+	client.dispatch(envelope{Type: "response.created"}, raw)
+	raw, _ = json.Marshal(ResponseTextDelta{Type: "response.text.delta", ResponseID: "resp_open", Delta: "Hel"})
+	client.dispatch(envelope{Type: "response.text.delta"}, raw)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	byID, byTag := make(chan error, 1), make(chan error, 1)
+	go func() {
+		_, err := tracker.Await(ctx, "resp_open")
+		byID <- err
+	}()
+	go func() {
+		_, err := tracker.AwaitTag(ctx, "tag_open")
+		byTag <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	if err := client.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	for what, ch := range map[string]chan error{"Await": byID, "AwaitTag": byTag} {
+		if err := <-ch; !errors.Is(err, ErrResponseInterrupted) {
+			t.Errorf("%s = %v, want ErrResponseInterrupted", what, err)
+		}
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if len(tracker.building) != 0 {
+		t.Errorf("%d responses still building after Reconnect", len(tracker.building))
+	}
+}
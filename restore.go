@@ -0,0 +1,93 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RestoreConversation replays stored conversation history into the current
+// session with conversation.item.create events, e.g. after Reconnect or on a
+// new connection following a server restart. Items are sent in order.
+//
+// Messages keep their text; audio parts are replaced by their transcripts,
+// since the audio itself is rarely worth re-uploading (audio without a
+// transcript is sent as is). Function calls and their outputs are kept so the
+// model knows which tools already ran. Server-assigned IDs and statuses are
+// dropped, and items left without content are skipped. The history can come
+// from conversation.item.created events, response output, or
+// Transcript.Items.
+//
+// On error, the items before the failing one have been sent.
+func (c *Client) RestoreConversation(ctx context.Context, items []ConversationItem) error {
+	if ctx == nil {
+		return NewSendError("conversation.item.create", "", errors.New("context cannot be nil"))
+	}
+	for i, item := range items {
+		restored, ok := restorableItem(item)
+		if !ok {
+			continue
+		}
+		if err := c.CreateConversationItem(ctx, restored); err != nil {
+			return fmt.Errorf("azrealtime: restore conversation item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Items returns the transcript as text messages for RestoreConversation.
+// Entries without text are skipped.
+func (t Transcript) Items() []ConversationItem {
+	var items []ConversationItem
+	for _, e := range t.Entries {
+		if e.Text == "" {
+			continue
+		}
+		items = append(items, ConversationItem{
+			Type:    "message",
+			Role:    e.Role,
+			Content: []ContentPart{{Type: textPartType(e.Role), Text: e.Text}},
+		})
+	}
+	return items
+}
+
+// restorableItem converts a stored item into one that can be created again.
+func restorableItem(item ConversationItem) (ConversationItem, bool) {
+	item.ID = ""
+	item.Status = ""
+	switch item.Type {
+	case "function_call":
+		return item, item.CallID != "" && item.Name != ""
+	case "function_call_output":
+		return item, item.CallID != ""
+	case "message":
+	default:
+		return ConversationItem{}, false
+	}
+
+	var parts []ContentPart
+	for _, p := range item.Content {
+		switch {
+		case p.Text != "":
+			parts = append(parts, ContentPart{Type: textPartType(item.Role), Text: p.Text})
+		case p.Transcript != "":
+			parts = append(parts, ContentPart{Type: textPartType(item.Role), Text: p.Transcript})
+		case p.Audio != "" && item.Role == "user":
+			parts = append(parts, ContentPart{Type: "input_audio", Audio: p.Audio})
+		}
+	}
+	if len(parts) == 0 {
+		return ConversationItem{}, false
+	}
+	item.Content = parts
+	return item, true
+}
+
+// textPartType is the content type of text in a message from role.
+func textPartType(role string) string {
+	if role == "assistant" {
+		return "text"
+	}
+	return "input_text"
+}
@@ -0,0 +1,75 @@
+package azrealtime
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_RestoreConversation(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+	sent := recordOutbound(client)
+
+	history := []ConversationItem{
+		{ID: "item_1", Type: "message", Role: "user", Status: "completed", Content: []ContentPart{{Type: "input_audio", Transcript: "What's the status of order 42?"}}},
+		{ID: "item_2", Type: "function_call", CallID: "call_1", Name: "lookup_order", Arguments: `{"id":"42"}`},
+		{ID: "item_3", Type: "function_call_output", CallID: "call_1", Output: `{"status":"shipped"}`},
+		{ID: "item_4", Type: "message", Role: "assistant", Content: []ContentPart{{Type: "audio", Transcript: "It has shipped."}}},
+		{ID: "item_5", Type: "message", Role: "assistant", Content: []ContentPart{{Type: "audio"}}},
+		{ID: "item_6", Type: "message", Role: "user", Content: []ContentPart{{Type: "input_text", Text: "Thanks!"}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.RestoreConversation(ctx, history); err != nil {
+		t.Fatalf("RestoreConversation: %v", err)
+	}
+
+	creates := sent.ofType("conversation.item.create")
+	var got []map[string]any
+	for _, c := range creates {
+		got = append(got, c["item"].(map[string]any))
+	}
+	want := []map[string]any{
+		{"type": "message", "role": "user", "content": []any{map[string]any{"type": "input_text", "text": "What's the status of order 42?"}}},
+		{"type": "function_call", "call_id": "call_1", "name": "lookup_order", "arguments": `{"id":"42"}`},
+		{"type": "function_call_output", "call_id": "call_1", "output": `{"status":"shipped"}`},
+		{"type": "message", "role": "assistant", "content": []any{map[string]any{"type": "text", "text": "It has shipped."}}},
+		{"type": "message", "role": "user", "content": []any{map[string]any{"type": "input_text", "text": "Thanks!"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("restored items:\n got %v\nwant %v", got, want)
+	}
+
+	// The caller's history is not modified
+	if history[0].ID != "item_1" || history[0].Content[0].Type != "input_audio" {
+		t.Errorf("history modified: %+v", history[0])
+	}
+}
+
+func TestClient_RestoreTranscriptAfterReconnect(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Ask(ctx, "Remember the number 7."); err != nil {
+		t.Fatalf("Ask: %v", err)
+	}
+	if err := client.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+
+	sent := recordOutbound(client)
+	if err := client.RestoreConversation(ctx, client.Transcript().Items()); err != nil {
+		t.Fatalf("RestoreConversation: %v", err)
+	}
+	creates := sent.ofType("conversation.item.create")
+	if len(creates) != 2 {
+		t.Fatalf("restored %d items, want the prompt and the reply", len(creates))
+	}
+	reply := creates[1]["item"].(map[string]any)
+	if reply["role"] != "assistant" || reply["content"].([]any)[0].(map[string]any)["text"] != "Hello from mock server!" {
+		t.Errorf("restored reply = %v", reply)
+	}
+}
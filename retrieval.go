@@ -0,0 +1,111 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultSnippetHeader introduces retrieved snippets when RetrievalContext.Header is empty.
+const DefaultSnippetHeader = "Use the following retrieved context to answer the user. " +
+	"If it is not relevant, answer from your own knowledge and say so."
+
+// ItemReference points at an existing conversation item from a response's input list.
+type ItemReference struct {
+	Type string `json:"type"` // Always "item_reference"
+	ID   string `json:"id"`   // ID of the referenced conversation item
+}
+
+// NewItemReference returns a reference to the conversation item with the given ID.
+func NewItemReference(id string) ItemReference {
+	return ItemReference{Type: "item_reference", ID: id}
+}
+
+// Snippet is a piece of retrieved content to ground a single response.
+type Snippet struct {
+	Source string // Optional citation label (document title, URL, ...)
+	Text   string // Snippet content
+}
+
+// RetrievalContext describes retrieved content to inject into a single response.
+type RetrievalContext struct {
+	// Snippets are the retrieved passages. At least one is required.
+	Snippets []Snippet
+
+	// ItemIDs reference existing conversation items (typically the recent turns,
+	// ending with the user's question) that the response should see alongside the
+	// snippets. Because response input replaces the default conversation context,
+	// omit nothing the model needs to answer.
+	ItemIDs []string
+
+	// Header precedes the snippets in the injected message. Default: DefaultSnippetHeader.
+	Header string
+
+	// Role of the injected message: "system" (default) or "user".
+	Role string
+}
+
+// CreateResponseWithContext requests a response grounded in retrieved snippets.
+// The snippets are sent as temporary input items of this response only; they are
+// not added to the conversation, so the dialog history stays clean. The response
+// output itself is added to the conversation as usual unless opts.Conversation is "none".
+//
+// Any opts.Input is replaced. Returns the event ID, as CreateResponse does.
+func (c *Client) CreateResponseWithContext(ctx context.Context, opts CreateResponseOptions, rc RetrievalContext) (string, error) {
+	input, err := BuildRetrievalInput(rc)
+	if err != nil {
+		return "", NewSendError("response.create", "", err)
+	}
+	opts.Input = input
+	return c.CreateResponse(ctx, opts)
+}
+
+// BuildRetrievalInput returns the response input items for rc: one item reference
+// per ItemIDs entry followed by a message carrying the formatted snippets.
+func BuildRetrievalInput(rc RetrievalContext) ([]any, error) {
+	if len(rc.Snippets) == 0 {
+		return nil, errors.New("at least one snippet is required")
+	}
+	role := rc.Role
+	if role == "" {
+		role = "system"
+	}
+	if role != "system" && role != "user" {
+		return nil, fmt.Errorf("invalid snippet role %q, must be 'system' or 'user'", role)
+	}
+
+	input := make([]any, 0, len(rc.ItemIDs)+1)
+	for i, id := range rc.ItemIDs {
+		if id == "" {
+			return nil, fmt.Errorf("item ID %d is empty", i)
+		}
+		input = append(input, NewItemReference(id))
+	}
+
+	input = append(input, ConversationItem{
+		Type:    "message",
+		Role:    role,
+		Content: []ContentPart{{Type: "input_text", Text: FormatSnippets(rc.Header, rc.Snippets)}},
+	})
+	return input, nil
+}
+
+// FormatSnippets renders snippets as a numbered list under header, citing sources
+// where given. An empty header uses DefaultSnippetHeader.
+func FormatSnippets(header string, snippets []Snippet) string {
+	if header == "" {
+		header = DefaultSnippetHeader
+	}
+	var b strings.Builder
+	b.WriteString(header)
+	for i, s := range snippets {
+		fmt.Fprintf(&b, "\n\n[%d]", i+1)
+		if s.Source != "" {
+			fmt.Fprintf(&b, " (%s)", s.Source)
+		}
+		b.WriteString(" ")
+		b.WriteString(strings.TrimSpace(s.Text))
+	}
+	return b.String()
+}
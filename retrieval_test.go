@@ -0,0 +1,58 @@
+package azrealtime
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuildRetrievalInput(t *testing.T) {
+	input, err := BuildRetrievalInput(RetrievalContext{
+		ItemIDs: []string{"item_1", "item_2"},
+		Snippets: []Snippet{
+			{Source: "handbook.pdf", Text: "Refunds take 5 days. "},
+			{Text: "Support is open 9-5."},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(input) != 3 {
+		t.Fatalf("expected 3 input items, got %d", len(input))
+	}
+
+	b, _ := json.Marshal(input)
+	got := string(b)
+	if !strings.Contains(got, `{"type":"item_reference","id":"item_1"}`) {
+		t.Errorf("missing item reference: %s", got)
+	}
+
+	msg, ok := input[2].(ConversationItem)
+	if !ok || msg.Role != "system" || msg.Content[0].Type != "input_text" {
+		t.Fatalf("unexpected snippet message: %#v", input[2])
+	}
+	text := msg.Content[0].Text
+	if !strings.HasPrefix(text, DefaultSnippetHeader) ||
+		!strings.Contains(text, "[1] (handbook.pdf) Refunds take 5 days.") ||
+		!strings.Contains(text, "[2] Support is open 9-5.") {
+		t.Errorf("unexpected snippet text: %q", text)
+	}
+}
+
+func TestBuildRetrievalInput_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		rc   RetrievalContext
+	}{
+		{"no snippets", RetrievalContext{}},
+		{"bad role", RetrievalContext{Snippets: []Snippet{{Text: "x"}}, Role: "assistant"}},
+		{"empty item ID", RetrievalContext{Snippets: []Snippet{{Text: "x"}}, ItemIDs: []string{""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BuildRetrievalInput(tt.rc); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -5,13 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 )
 
 // Session defines the configuration for a realtime conversation session.
 // Use this to customize the AI assistant's behavior, audio formats, and interaction modes.
 type Session struct {
 	// Voice specifies which voice to use for audio responses.
-	// Available voices: "alloy", "echo", "fable", "onyx", "nova", "shimmer", "verse"
+	// See the Voice* constants; ValidateSession accepts the names in Voices.
 	Voice *string `json:"voice,omitempty"`
 
 	// Instructions provide system-level guidance to the assistant.
@@ -34,13 +35,74 @@ type Session struct {
 
 	// Tools defines function calling capabilities available to the assistant.
 	Tools []any `json:"tools,omitempty"`
+
+	// Modalities sets the default output types of responses.
+	// Supported: ["text"] or ["text", "audio"]
+	Modalities []string `json:"modalities,omitempty"`
+
+	// Temperature sets the default sampling temperature, between 0.6 and 1.2.
+	// The server default is 0.8.
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// MaxOutputTokens limits the tokens of each response, between 1 and 4096,
+	// or MaxTokensInf for no limit. Zero leaves the setting unchanged.
+	MaxOutputTokens MaxTokens `json:"max_response_output_tokens,omitempty"`
+}
+
+// Session temperature limits enforced by the service.
+const (
+	minSessionTemperature = 0.6
+	maxSessionTemperature = 1.2
+)
+
+// Voices for Session.Voice.
+const (
+	VoiceAlloy   = "alloy"
+	VoiceAsh     = "ash"
+	VoiceBallad  = "ballad"
+	VoiceCedar   = "cedar"
+	VoiceCoral   = "coral"
+	VoiceEcho    = "echo"
+	VoiceFable   = "fable"
+	VoiceMarin   = "marin"
+	VoiceNova    = "nova"
+	VoiceOnyx    = "onyx"
+	VoiceSage    = "sage"
+	VoiceShimmer = "shimmer"
+	VoiceVerse   = "verse"
+)
+
+// Voices lists the voice names ValidateSession accepts. When the service adds
+// a voice before this list does, append it during program initialization
+// (the list is read without locking), or set Voices to nil to skip voice
+// validation altogether.
+var Voices = []string{
+	VoiceAlloy, VoiceAsh, VoiceBallad, VoiceCedar, VoiceCoral, VoiceEcho, VoiceFable,
+	VoiceMarin, VoiceNova, VoiceOnyx, VoiceSage, VoiceShimmer, VoiceVerse,
 }
 
+// Transcription models accepted in InputTranscription.Model. The gpt-4o
+// models stream partial transcripts as
+// ConversationItemInputAudioTranscriptionDelta events.
+const (
+	TranscriptionModelWhisper1            = "whisper-1"
+	TranscriptionModelGPT4oTranscribe     = "gpt-4o-transcribe"
+	TranscriptionModelGPT4oMiniTranscribe = "gpt-4o-mini-transcribe"
+)
+
 // InputTranscription configures automatic speech recognition for user input.
 type InputTranscription struct {
-	Model    string  `json:"model,omitempty"`    // Transcription model to use
-	Language string  `json:"language,omitempty"` // Expected language code (e.g., "en")
-	Prompt   *string `json:"prompt,omitempty"`   // Context to improve transcription accuracy
+	Model    string  `json:"model,omitempty"`    // Transcription model to use (see TranscriptionModelWhisper1 etc.)
+	Language string  `json:"language,omitempty"` // Expected ISO-639-1 language code (e.g., "en"); improves accuracy and latency
+	Prompt   *string `json:"prompt,omitempty"`   // Context to improve transcription accuracy; see KeywordPrompt
+}
+
+// KeywordPrompt builds an InputTranscription.Prompt from terms the
+// transcription should recognize, such as product names. It is a client-side
+// convenience: the service only receives the comma-separated list, which is
+// the prompt whisper-1 expects. The gpt-4o models take free text instead.
+func KeywordPrompt(keywords ...string) *string {
+	return Ptr(strings.Join(keywords, ", "))
 }
 
 // TurnDetection configures voice activity detection and response timing.
@@ -86,28 +148,35 @@ type TurnDetection struct {
 // SessionUpdate sends a session configuration update to the API.
 // This allows you to change settings like voice, instructions, and turn detection
 // without creating a new connection.
+//
+// SessionUpdate is safe to call from multiple goroutines. Overlapping calls are
+// serialized and coalesced by the client's SessionUpdateQueue, and each call
+// returns once the update containing its fields has been sent.
 func (c *Client) SessionUpdate(ctx context.Context, s Session) error {
 	if ctx == nil {
 		return NewSendError("session.update", "", errors.New("context cannot be nil"))
 	}
 
 	// Validate session configuration
-	if err := ValidateSession(s); err != nil {
-		return NewSendError("session.update", "", err)
+	if err := c.validate("session.update", func() error { return ValidateSession(s) }); err != nil {
+		return err
 	}
 
-	payload := map[string]any{"type": "session.update", "session": s}
-	return c.send(ctx, payload)
+	return c.sessionUpdates.submit(ctx, s, func(ctx context.Context, s Session) error {
+		payload := map[string]any{"type": "session.update", "session": s}
+		return c.send(ctx, payload)
+	})
 }
 
 // ValidateSession performs validation on session configuration.
+// All failures are reported together as ValidationErrors.
 func ValidateSession(s Session) error {
+	var errs ValidationErrors
+
 	// Validate voice if specified
-	if s.Voice != nil {
-		validVoices := []string{"alloy", "echo", "fable", "onyx", "nova", "shimmer", "verse"}
-		valid := slices.Contains(validVoices, *s.Voice)
-		if !valid {
-			return fmt.Errorf("invalid voice %q, must be one of: %v", *s.Voice, validVoices)
+	if s.Voice != nil && Voices != nil {
+		if !slices.Contains(Voices, *s.Voice) {
+			errs.add("voice", ValidationCodeInvalidValue, "invalid voice %q, must be one of: %v", *s.Voice, Voices)
 		}
 	}
 
@@ -116,7 +185,7 @@ func ValidateSession(s Session) error {
 		validFormats := []string{"pcm16", "g711_ulaw", "g711_alaw"}
 		valid := slices.Contains(validFormats, *s.InputAudioFormat)
 		if !valid {
-			return fmt.Errorf("invalid input audio format %q, must be one of: %v", *s.InputAudioFormat, validFormats)
+			errs.add("input_audio_format", ValidationCodeInvalidValue, "invalid input audio format %q, must be one of: %v", *s.InputAudioFormat, validFormats)
 		}
 	}
 
@@ -124,31 +193,40 @@ func ValidateSession(s Session) error {
 		validFormats := []string{"pcm16", "g711_ulaw", "g711_alaw"}
 		valid := slices.Contains(validFormats, *s.OutputAudioFormat)
 		if !valid {
-			return fmt.Errorf("invalid output audio format %q, must be one of: %v", *s.OutputAudioFormat, validFormats)
+			errs.add("output_audio_format", ValidationCodeInvalidValue, "invalid output audio format %q, must be one of: %v", *s.OutputAudioFormat, validFormats)
 		}
 	}
 
-	// Validate turn detection
-	if s.TurnDetection != nil {
-		if s.TurnDetection.Type == "" {
-			return errors.New("turn detection type cannot be empty")
+	// Validate input transcription
+	if t := s.InputTranscription; t != nil {
+		validModels := []string{TranscriptionModelWhisper1, TranscriptionModelGPT4oTranscribe, TranscriptionModelGPT4oMiniTranscribe}
+		if t.Model != "" && !slices.Contains(validModels, t.Model) {
+			errs.add("input_audio_transcription.model", ValidationCodeInvalidValue, "invalid transcription model %q, must be one of: %v", t.Model, validModels)
 		}
+		if t.Language != "" && !isLanguageCode(t.Language) {
+			errs.add("input_audio_transcription.language", ValidationCodeInvalidValue, "invalid language %q, must be an ISO-639-1 code such as \"en\"", t.Language)
+		}
+	}
 
+	// Validate turn detection
+	if s.TurnDetection != nil {
 		validTypes := []string{"server_vad", "semantic_vad"}
-		if !slices.Contains(validTypes, s.TurnDetection.Type) {
-			return fmt.Errorf("invalid turn detection type %q, must be one of: %v", s.TurnDetection.Type, validTypes)
+		if s.TurnDetection.Type == "" {
+			errs.add("turn_detection.type", ValidationCodeRequired, "turn detection type cannot be empty")
+		} else if !slices.Contains(validTypes, s.TurnDetection.Type) {
+			errs.add("turn_detection.type", ValidationCodeInvalidValue, "invalid turn detection type %q, must be one of: %v", s.TurnDetection.Type, validTypes)
 		}
 
 		// Server VAD specific validations
 		if s.TurnDetection.Type == "server_vad" {
 			if s.TurnDetection.Threshold < 0.0 || s.TurnDetection.Threshold > 1.0 {
-				return fmt.Errorf("turn detection threshold must be between 0.0 and 1.0, got %f", s.TurnDetection.Threshold)
+				errs.add("turn_detection.threshold", ValidationCodeOutOfRange, "turn detection threshold must be between 0.0 and 1.0, got %f", s.TurnDetection.Threshold)
 			}
 			if s.TurnDetection.PrefixPaddingMS < 0 {
-				return fmt.Errorf("prefix padding must be non-negative, got %d", s.TurnDetection.PrefixPaddingMS)
+				errs.add("turn_detection.prefix_padding_ms", ValidationCodeOutOfRange, "prefix padding must be non-negative, got %d", s.TurnDetection.PrefixPaddingMS)
 			}
 			if s.TurnDetection.SilenceDurationMS < 0 {
-				return fmt.Errorf("silence duration must be non-negative, got %d", s.TurnDetection.SilenceDurationMS)
+				errs.add("turn_detection.silence_duration_ms", ValidationCodeOutOfRange, "silence duration must be non-negative, got %d", s.TurnDetection.SilenceDurationMS)
 			}
 		}
 
@@ -157,16 +235,39 @@ func ValidateSession(s Session) error {
 			if s.TurnDetection.Eagerness != "" {
 				validEagerness := []string{"low", "medium", "high", "auto"}
 				if !slices.Contains(validEagerness, s.TurnDetection.Eagerness) {
-					return fmt.Errorf("invalid eagerness %q, must be one of: %v", s.TurnDetection.Eagerness, validEagerness)
+					errs.add("turn_detection.eagerness", ValidationCodeInvalidValue, "invalid eagerness %q, must be one of: %v", s.TurnDetection.Eagerness, validEagerness)
 				}
 			}
 		}
 	}
 
 	// Validate instructions length (reasonable limit)
-	if s.Instructions != nil && len(*s.Instructions) > 10000 {
-		return fmt.Errorf("instructions too long (%d characters), maximum is 10000", len(*s.Instructions))
+	if s.Instructions != nil && len(*s.Instructions) > MaxInstructionsLength {
+		errs.add("instructions", ValidationCodeTooLong, "instructions too long (%d characters), maximum is %d", len(*s.Instructions), MaxInstructionsLength)
+	}
+
+	// Validate modalities
+	for i, modality := range s.Modalities {
+		if modality != "text" && modality != "audio" {
+			errs.add(fmt.Sprintf("modalities[%d]", i), ValidationCodeInvalidValue, "invalid modality %q, must be 'text' or 'audio'", modality)
+		}
+	}
+
+	// Validate temperature
+	if s.Temperature != nil && (*s.Temperature < minSessionTemperature || *s.Temperature > maxSessionTemperature) {
+		errs.add("temperature", ValidationCodeOutOfRange, "temperature must be between %.1f and %.1f, got %f", minSessionTemperature, maxSessionTemperature, *s.Temperature)
 	}
 
-	return nil
+	// Validate max output tokens
+	if s.MaxOutputTokens != 0 && s.MaxOutputTokens != MaxTokensInf &&
+		(s.MaxOutputTokens < 1 || s.MaxOutputTokens > maxOutputTokensLimit) {
+		errs.add("max_response_output_tokens", ValidationCodeOutOfRange, "max_response_output_tokens must be between 1 and %d or \"inf\", got %d", maxOutputTokensLimit, s.MaxOutputTokens)
+	}
+
+	return errs.err()
+}
+
+// isLanguageCode reports whether s looks like an ISO-639-1 code.
+func isLanguageCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'a' && s[0] <= 'z' && s[1] >= 'a' && s[1] <= 'z'
 }
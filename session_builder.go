@@ -0,0 +1,157 @@
+package azrealtime
+
+// SessionBuilder assembles a Session without pointer-heavy literals:
+//
+//	session := azrealtime.NewSessionBuilder().
+//		Voice("alloy").
+//		ServerVAD(0.5, 300, 500).
+//		Transcription("whisper-1", "en").
+//		Build()
+//
+// Settings that are never set are left out of the Session, so they keep their
+// current value on the server. Each call returns the builder for chaining.
+type SessionBuilder struct {
+	s Session
+}
+
+// SessionPreset applies a named group of settings to a SessionBuilder.
+type SessionPreset func(*SessionBuilder)
+
+// NewSessionBuilder creates an empty SessionBuilder.
+func NewSessionBuilder() *SessionBuilder {
+	return &SessionBuilder{}
+}
+
+// PresetVoiceAssistant configures a spoken conversation: the "alloy" voice,
+// PCM16 audio in both directions, server VAD that answers and allows
+// barge-in, and whisper-1 transcription of the user.
+func PresetVoiceAssistant(b *SessionBuilder) {
+	b.Voice("alloy").
+		AudioFormat("pcm16").
+		ServerVAD(0.5, 300, 500).
+		Transcription("whisper-1", "")
+}
+
+// PresetTranscription configures a session for captioning user speech: PCM16
+// input, server VAD to split turns and whisper-1 transcription. It does not
+// stop the server from creating responses after each turn.
+func PresetTranscription(b *SessionBuilder) {
+	b.InputAudioFormat("pcm16").
+		ServerVAD(0.5, 300, 500).
+		Transcription("whisper-1", "")
+}
+
+// Preset applies the given presets in order. Later builder calls override
+// their settings.
+func (b *SessionBuilder) Preset(presets ...SessionPreset) *SessionBuilder {
+	for _, p := range presets {
+		p(b)
+	}
+	return b
+}
+
+// Voice sets the voice for audio responses (e.g. VoiceAlloy, VoiceMarin).
+func (b *SessionBuilder) Voice(voice string) *SessionBuilder {
+	b.s.Voice = Ptr(voice)
+	return b
+}
+
+// Instructions sets the system instructions.
+func (b *SessionBuilder) Instructions(instructions string) *SessionBuilder {
+	b.s.Instructions = Ptr(instructions)
+	return b
+}
+
+// InputAudioFormat sets the format of audio sent by the client.
+func (b *SessionBuilder) InputAudioFormat(format string) *SessionBuilder {
+	b.s.InputAudioFormat = Ptr(format)
+	return b
+}
+
+// OutputAudioFormat sets the format of audio sent by the assistant.
+func (b *SessionBuilder) OutputAudioFormat(format string) *SessionBuilder {
+	b.s.OutputAudioFormat = Ptr(format)
+	return b
+}
+
+// AudioFormat sets both the input and output audio format.
+func (b *SessionBuilder) AudioFormat(format string) *SessionBuilder {
+	return b.InputAudioFormat(format).OutputAudioFormat(format)
+}
+
+// ServerVAD enables server voice activity detection with the given threshold
+// (0.0-1.0), prefix padding and silence duration in milliseconds. The server
+// responds automatically at the end of each turn and stops its response when
+// the user starts speaking.
+func (b *SessionBuilder) ServerVAD(threshold float64, prefixPaddingMS, silenceDurationMS int) *SessionBuilder {
+	b.s.TurnDetection = &TurnDetection{
+		Type:              "server_vad",
+		Threshold:         threshold,
+		PrefixPaddingMS:   prefixPaddingMS,
+		SilenceDurationMS: silenceDurationMS,
+		CreateResponse:    true,
+		InterruptResponse: true,
+	}
+	return b
+}
+
+// SemanticVAD enables semantic voice activity detection with the given
+// eagerness ("low", "medium", "high" or "auto").
+func (b *SessionBuilder) SemanticVAD(eagerness string) *SessionBuilder {
+	b.s.TurnDetection = &TurnDetection{
+		Type:              "semantic_vad",
+		Eagerness:         eagerness,
+		CreateResponse:    true,
+		InterruptResponse: true,
+	}
+	return b
+}
+
+// Transcription enables transcription of user audio with the given model
+// (e.g. TranscriptionModelGPT4oTranscribe). language is an ISO-639-1 code, or
+// empty to let the model detect it.
+func (b *SessionBuilder) Transcription(model, language string) *SessionBuilder {
+	b.s.InputTranscription = &InputTranscription{Model: model, Language: language}
+	return b
+}
+
+// Tools adds tool definitions, such as Tool or HTTPTool values.
+func (b *SessionBuilder) Tools(tools ...any) *SessionBuilder {
+	b.s.Tools = append(b.s.Tools, tools...)
+	return b
+}
+
+// Modalities sets the default output types of responses ("text", "audio").
+func (b *SessionBuilder) Modalities(modalities ...string) *SessionBuilder {
+	b.s.Modalities = append([]string(nil), modalities...)
+	return b
+}
+
+// Temperature sets the default sampling temperature (0.6-1.2).
+func (b *SessionBuilder) Temperature(temperature float64) *SessionBuilder {
+	b.s.Temperature = Ptr(temperature)
+	return b
+}
+
+// MaxOutputTokens limits the tokens of each response (1-4096, or MaxTokensInf).
+func (b *SessionBuilder) MaxOutputTokens(n MaxTokens) *SessionBuilder {
+	b.s.MaxOutputTokens = n
+	return b
+}
+
+// Build returns the Session. The builder can be reused; later changes do not
+// affect Sessions already built.
+func (b *SessionBuilder) Build() Session {
+	s := b.s
+	if s.InputTranscription != nil {
+		t := *s.InputTranscription
+		s.InputTranscription = &t
+	}
+	if s.TurnDetection != nil {
+		td := *s.TurnDetection
+		s.TurnDetection = &td
+	}
+	s.Tools = append([]any(nil), s.Tools...)
+	s.Modalities = append([]string(nil), s.Modalities...)
+	return s
+}
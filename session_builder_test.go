@@ -0,0 +1,89 @@
+package azrealtime
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSessionBuilder(t *testing.T) {
+	got := NewSessionBuilder().
+		Voice("alloy").
+		Instructions("Be brief.").
+		ServerVAD(0.5, 300, 500).
+		Transcription("whisper-1", "en").
+		Build()
+
+	want := Session{
+		Voice:              Ptr("alloy"),
+		Instructions:       Ptr("Be brief."),
+		InputTranscription: &InputTranscription{Model: "whisper-1", Language: "en"},
+		TurnDetection: &TurnDetection{
+			Type:              "server_vad",
+			Threshold:         0.5,
+			PrefixPaddingMS:   300,
+			SilenceDurationMS: 500,
+			CreateResponse:    true,
+			InterruptResponse: true,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %+v, want %+v", got, want)
+	}
+	if err := ValidateSession(got); err != nil {
+		t.Errorf("built session is invalid: %v", err)
+	}
+
+	got = NewSessionBuilder().Modalities("text").Temperature(0.7).MaxOutputTokens(MaxTokensInf).Build()
+	data, _ := json.Marshal(got)
+	if string(data) != `{"modalities":["text"],"temperature":0.7,"max_response_output_tokens":"inf"}` {
+		t.Errorf("response defaults session = %s", data)
+	}
+
+	// Unset settings are omitted from the update
+	data, _ = json.Marshal(NewSessionBuilder().Voice("verse").Build())
+	if string(data) != `{"voice":"verse"}` {
+		t.Errorf("voice-only session = %s", data)
+	}
+}
+
+func TestSessionBuilder_Presets(t *testing.T) {
+	s := NewSessionBuilder().Preset(PresetVoiceAssistant).Voice("verse").Build()
+	if *s.Voice != "verse" {
+		t.Errorf("Voice = %q, want the override", *s.Voice)
+	}
+	if *s.InputAudioFormat != "pcm16" || *s.OutputAudioFormat != "pcm16" {
+		t.Errorf("audio formats = %q/%q", *s.InputAudioFormat, *s.OutputAudioFormat)
+	}
+	if s.TurnDetection == nil || s.TurnDetection.Type != "server_vad" || s.InputTranscription == nil {
+		t.Errorf("voice assistant preset = %+v", s)
+	}
+	if err := ValidateSession(s); err != nil {
+		t.Errorf("voice assistant preset is invalid: %v", err)
+	}
+
+	s = NewSessionBuilder().Preset(PresetTranscription).Build()
+	if s.Voice != nil || s.OutputAudioFormat != nil || s.InputTranscription == nil {
+		t.Errorf("transcription preset = %+v", s)
+	}
+	if err := ValidateSession(s); err != nil {
+		t.Errorf("transcription preset is invalid: %v", err)
+	}
+}
+
+func TestSessionBuilder_BuildCopies(t *testing.T) {
+	b := NewSessionBuilder().SemanticVAD("low").Tools(Tool{Name: "first"})
+	first := b.Build()
+	b.Tools(Tool{Name: "second"})
+	b.s.TurnDetection.Eagerness = "high"
+
+	if len(first.Tools) != 1 {
+		t.Errorf("earlier session has %d tools, want 1", len(first.Tools))
+	}
+	if first.TurnDetection.Eagerness != "low" {
+		t.Errorf("earlier session eagerness = %q, want low", first.TurnDetection.Eagerness)
+	}
+	if second := b.Build(); len(second.Tools) != 2 || second.TurnDetection.Eagerness != "high" {
+		t.Errorf("second session = %+v", second)
+	}
+}
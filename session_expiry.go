@@ -0,0 +1,109 @@
+package azrealtime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultSessionExpiryWarning is how long before session expiry OnSessionExpiring
+// fires when Config.SessionExpiryWarning is zero.
+const DefaultSessionExpiryWarning = time.Minute
+
+// sessionExpiry tracks the expires_at timestamp of the current server session
+// and the timer that warns about it.
+type sessionExpiry struct {
+	mu        sync.Mutex
+	expiresAt time.Time
+	timer     *time.Timer
+}
+
+// OnSessionExpiring registers a callback invoked shortly before the server-side
+// session expires. The callback receives the time remaining until expiry; how far
+// ahead it fires is controlled by Config.SessionExpiryWarning. If
+// Config.ReconnectOnSessionExpiry is set, the client reconnects right after the
+// callback returns.
+func (c *Client) OnSessionExpiring(fn func(remaining time.Duration)) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	c.onSessionExpiring = fn
+}
+
+// SessionExpiresAt returns the expiry time of the current server session, or the
+// zero time if the server has not reported one.
+func (c *Client) SessionExpiresAt() time.Time {
+	c.expiry.mu.Lock()
+	defer c.expiry.mu.Unlock()
+	return c.expiry.expiresAt
+}
+
+// trackSessionExpiry (re)arms the expiry warning timer for a session expiring at
+// the given Unix timestamp. Timestamps that are unset or already in the past are ignored.
+func (c *Client) trackSessionExpiry(expiresAtUnix int64) {
+	if expiresAtUnix <= 0 {
+		return
+	}
+	expiresAt := time.Unix(expiresAtUnix, 0)
+	if !time.Now().Before(expiresAt) {
+		return
+	}
+
+	warning := c.cfg.SessionExpiryWarning
+	if warning == 0 {
+		warning = DefaultSessionExpiryWarning
+	}
+	wait := time.Until(expiresAt) - warning
+	if wait < 0 {
+		wait = 0
+	}
+
+	c.expiry.mu.Lock()
+	defer c.expiry.mu.Unlock()
+	if c.expiry.timer != nil {
+		c.expiry.timer.Stop()
+	}
+	c.expiry.expiresAt = expiresAt
+	c.expiry.timer = time.AfterFunc(wait, func() { c.sessionExpiring(expiresAt) })
+}
+
+// stopSessionExpiry cancels any pending expiry warning.
+func (c *Client) stopSessionExpiry() {
+	c.expiry.mu.Lock()
+	defer c.expiry.mu.Unlock()
+	if c.expiry.timer != nil {
+		c.expiry.timer.Stop()
+		c.expiry.timer = nil
+	}
+}
+
+// sessionExpiring fires the expiry warning and optionally reconnects.
+func (c *Client) sessionExpiring(expiresAt time.Time) {
+	select {
+	case <-c.closedCh:
+		return
+	default:
+	}
+
+	remaining := time.Until(expiresAt)
+	c.log("session_expiring", map[string]any{"remaining": remaining.String()})
+
+	c.handlerMu.RLock()
+	if c.onSessionExpiring != nil {
+		c.onSessionExpiring(remaining)
+	}
+	c.handlerMu.RUnlock()
+
+	if !c.cfg.ReconnectOnSessionExpiry {
+		return
+	}
+
+	timeout := c.cfg.DialTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := c.Reconnect(ctx); err != nil {
+		c.logError("session_expiry_reconnect_failed", map[string]any{"err": err})
+	}
+}
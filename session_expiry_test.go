@@ -0,0 +1,123 @@
+package azrealtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClient_OnSessionExpiring(t *testing.T) {
+	mockServer := NewMockServer(t)
+	mockServer.SessionExpiresIn = 10 * time.Second
+	defer mockServer.Close()
+
+	config := CreateMockConfig(mockServer.URL())
+	config.SessionExpiryWarning = time.Hour // warn immediately
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	expiring := make(chan time.Duration, 1)
+	client, err := Dial(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+	client.OnSessionExpiring(func(remaining time.Duration) {
+		select {
+		case expiring <- remaining:
+		default:
+		}
+	})
+
+	select {
+	case remaining := <-expiring:
+		if remaining <= 0 || remaining > 11*time.Second {
+			t.Errorf("unexpected remaining time %v", remaining)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for OnSessionExpiring")
+	}
+
+	if client.SessionExpiresAt().IsZero() {
+		t.Error("expected SessionExpiresAt to be set")
+	}
+}
+
+func TestClient_IgnoresPastSessionExpiry(t *testing.T) {
+	mockServer := NewMockServer(t) // reports an expires_at in the past
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	time.Sleep(100 * time.Millisecond)
+	if !client.SessionExpiresAt().IsZero() {
+		t.Errorf("expected past expiry to be ignored, got %v", client.SessionExpiresAt())
+	}
+}
+
+func TestClient_ReconnectOnSessionExpiry(t *testing.T) {
+	mockServer := NewMockServer(t)
+	mockServer.SessionExpiresIn = 3 * time.Second
+	defer mockServer.Close()
+
+	config := CreateMockConfig(mockServer.URL())
+	config.SessionExpiryWarning = 2500 * time.Millisecond
+	config.ReconnectOnSessionExpiry = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.SessionUpdate(ctx, Session{Voice: Ptr("echo")}); err != nil {
+		t.Fatalf("SessionUpdate: %v", err)
+	}
+
+	for mockServer.Connections() < 2 {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected a reconnect, saw %d connections", mockServer.Connections())
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if client.Stats().Reconnects == 0 {
+		t.Error("expected reconnect to be counted in Stats")
+	}
+
+	// The client must stay usable on the new connection.
+	if _, err := client.CreateResponse(ctx, CreateResponseOptions{Modalities: []string{"text"}}); err != nil {
+		t.Errorf("CreateResponse after reconnect: %v", err)
+	}
+}
+
+func TestClient_ReconnectAfterClose(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	client.Close()
+
+	if err := client.Reconnect(ctx); err != ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
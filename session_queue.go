@@ -0,0 +1,180 @@
+package azrealtime
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// SessionUpdateQueue serializes session.update requests issued through a Client.
+// Concurrent SessionUpdate calls never interleave on the wire: while one update is
+// being sent, later calls are queued and coalesced field-by-field into a single
+// follow-up update, so two goroutines changing different fields (for example voice
+// and instructions) both take effect instead of racing each other.
+//
+// Obtain the queue for a client with Client.SessionUpdates().
+type SessionUpdateQueue struct {
+	mu        sync.Mutex
+	inFlight  bool                // True while the drain goroutine is running
+	pending   *sessionUpdateBatch // Coalesced updates waiting to be sent
+	state     Session             // Accumulated configuration of all successfully sent updates
+	onSettled func(Session)       // Called with the accumulated state once the queue drains
+}
+
+// sessionUpdateBatch is a coalesced session update together with the callers waiting on it.
+type sessionUpdateBatch struct {
+	session Session
+	waiters []chan error
+	ctx     context.Context                      // Of the caller that started the batch, without its cancellation
+	send    func(context.Context, Session) error // Of the caller that started the batch, or of restore
+	full    bool                                 // Send the accumulated state along with the batch, see restore
+}
+
+// Pending reports whether a session update is currently being sent or is queued.
+func (q *SessionUpdateQueue) Pending() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.inFlight || q.pending != nil
+}
+
+// State returns the accumulated session configuration of all updates sent so far.
+func (q *SessionUpdateQueue) State() Session {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.state
+}
+
+// OnSettled registers a callback invoked with the final accumulated session
+// configuration whenever the queue drains. The callback runs on the queue's
+// drain goroutine, so it should not block.
+func (q *SessionUpdateQueue) OnSettled(fn func(Session)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onSettled = fn
+}
+
+// submit queues s for sending and blocks until the batch containing it has been
+// sent or ctx is done. Batches are sent by a drain goroutine started when the
+// queue was idle, so no caller waits for updates other than its own.
+//
+// A caller whose context is canceled while waiting returns early, but its fields
+// remain in the queued batch and may still be applied: batches are sent
+// without the cancellation of any caller's context, bounded by the client's
+// send timeout instead, so one caller giving up does not abort the others.
+func (q *SessionUpdateQueue) submit(ctx context.Context, s Session, send func(context.Context, Session) error) error {
+	return q.enqueue(ctx, s, send, false)
+}
+
+// restore queues the whole accumulated configuration for sending with send,
+// e.g. to a new connection after a reconnect. Updates queued by other callers
+// coalesce into the same batch, which is sent with send rather than theirs.
+// Nothing is sent if no configuration has been accumulated.
+func (q *SessionUpdateQueue) restore(ctx context.Context, send func(context.Context, Session) error) error {
+	return q.enqueue(ctx, Session{}, send, true)
+}
+
+func (q *SessionUpdateQueue) enqueue(ctx context.Context, s Session, send func(context.Context, Session) error, full bool) error {
+	done := make(chan error, 1)
+
+	q.mu.Lock()
+	if q.pending == nil {
+		q.pending = &sessionUpdateBatch{ctx: context.WithoutCancel(ctx), send: send}
+	}
+	if full {
+		q.pending.ctx, q.pending.send, q.pending.full = context.WithoutCancel(ctx), send, true
+	}
+	mergeSession(&q.pending.session, s)
+	q.pending.waiters = append(q.pending.waiters, done)
+	if !q.inFlight {
+		q.inFlight = true
+		go q.drain()
+	}
+	q.mu.Unlock()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return NewSendError("session.update", "", ctx.Err())
+	}
+}
+
+// drain sends queued batches until none remain. Once the last one is sent, it
+// marks the queue idle and fires the settled callback before releasing that
+// batch's callers, so they observe the settled queue.
+func (q *SessionUpdateQueue) drain() {
+	q.mu.Lock()
+	for {
+		batch := q.pending
+		q.pending = nil
+		session := batch.session
+		if batch.full {
+			// Taken now rather than when queued, so it includes the batch sent before
+			session = q.state
+			mergeSession(&session, batch.session)
+		}
+		q.mu.Unlock()
+
+		var err error
+		if !batch.full || !reflect.DeepEqual(session, Session{}) {
+			err = batch.send(batch.ctx, session)
+		}
+
+		q.mu.Lock()
+		if err == nil {
+			mergeSession(&q.state, session)
+		}
+		if q.pending == nil {
+			q.inFlight = false
+			state, fn := q.state, q.onSettled
+			q.mu.Unlock()
+			if fn != nil {
+				fn(state)
+			}
+			for _, w := range batch.waiters {
+				w <- err
+			}
+			return
+		}
+		q.mu.Unlock()
+		for _, w := range batch.waiters {
+			w <- err
+		}
+		q.mu.Lock()
+	}
+}
+
+// mergeSession copies every field set in src over the corresponding field in dst.
+// Unset (nil) fields in src leave dst untouched.
+func mergeSession(dst *Session, src Session) {
+	if src.Voice != nil {
+		dst.Voice = src.Voice
+	}
+	if src.Instructions != nil {
+		dst.Instructions = src.Instructions
+	}
+	if src.InputAudioFormat != nil {
+		dst.InputAudioFormat = src.InputAudioFormat
+	}
+	if src.OutputAudioFormat != nil {
+		dst.OutputAudioFormat = src.OutputAudioFormat
+	}
+	if src.InputTranscription != nil {
+		dst.InputTranscription = src.InputTranscription
+	}
+	if src.TurnDetection != nil {
+		dst.TurnDetection = src.TurnDetection
+	}
+	if src.Tools != nil {
+		dst.Tools = src.Tools
+	}
+	if src.Modalities != nil {
+		dst.Modalities = src.Modalities
+	}
+	if src.Temperature != nil {
+		dst.Temperature = src.Temperature
+	}
+	if src.MaxOutputTokens != 0 {
+		dst.MaxOutputTokens = src.MaxOutputTokens
+	}
+}
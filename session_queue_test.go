@@ -0,0 +1,312 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSessionUpdateQueue_CoalescesOverlappingUpdates(t *testing.T) {
+	var q SessionUpdateQueue
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var sent []Session
+	send := func(ctx context.Context, s Session) error {
+		mu.Lock()
+		sent = append(sent, s)
+		first := len(sent) == 1
+		mu.Unlock()
+		if first {
+			<-release
+		}
+		return nil
+	}
+
+	var settled []Session
+	q.OnSettled(func(s Session) { settled = append(settled, s) })
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := q.submit(ctx, Session{Voice: Ptr("alloy")}, send); err != nil {
+			t.Errorf("first submit: %v", err)
+		}
+	}()
+
+	// Wait until the first update is in flight before queueing more.
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(sent)
+		mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("first update was never sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if !q.Pending() {
+		t.Error("expected Pending() to be true while an update is in flight")
+	}
+
+	for _, s := range []Session{
+		{Instructions: Ptr("be brief")},
+		{Voice: Ptr("echo")},
+	} {
+		s := s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := q.submit(ctx, s, send); err != nil {
+				t.Errorf("queued submit: %v", err)
+			}
+		}()
+	}
+
+	// Give the queued callers time to coalesce before releasing the first send.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 sends (original + coalesced), got %d", len(sent))
+	}
+	if *sent[1].Voice != "echo" || *sent[1].Instructions != "be brief" {
+		t.Errorf("unexpected coalesced update: voice=%v instructions=%v", sent[1].Voice, sent[1].Instructions)
+	}
+	if q.Pending() {
+		t.Error("expected Pending() to be false after drain")
+	}
+	if len(settled) != 1 {
+		t.Fatalf("expected settled callback once, got %d", len(settled))
+	}
+	if *settled[0].Voice != "echo" || *settled[0].Instructions != "be brief" {
+		t.Errorf("unexpected settled state: %+v", settled[0])
+	}
+}
+
+func TestSessionUpdateQueue_PropagatesSendError(t *testing.T) {
+	var q SessionUpdateQueue
+	sendErr := errors.New("boom")
+
+	err := q.submit(context.Background(), Session{Voice: Ptr("alloy")}, func(context.Context, Session) error {
+		return sendErr
+	})
+	if !errors.Is(err, sendErr) {
+		t.Fatalf("expected send error, got %v", err)
+	}
+	if q.State().Voice != nil {
+		t.Error("failed update should not be recorded in state")
+	}
+}
+
+// waitQueued waits until an update is queued behind the one in flight.
+func waitQueued(t *testing.T, q *SessionUpdateQueue) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		q.mu.Lock()
+		queued := q.pending != nil
+		q.mu.Unlock()
+		if queued {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("update was never queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSessionUpdateQueue_CanceledCallerDoesNotAbortOthers(t *testing.T) {
+	var q SessionUpdateQueue
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	send := func(ctx context.Context, s Session) error {
+		started <- struct{}{}
+		<-release
+		return ctx.Err() // Fails if a caller's cancellation reached the send
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() { firstErr <- q.submit(first, Session{Voice: Ptr("alloy")}, send) }()
+	<-started
+	otherErr := make(chan error, 1)
+	go func() { otherErr <- q.submit(context.Background(), Session{Instructions: Ptr("be brief")}, send) }()
+
+	// Wait for the second caller to queue, then give up on the first update
+	waitQueued(t, &q)
+	cancel()
+	select {
+	case err := <-firstErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("canceled caller got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("canceled caller did not return")
+	}
+
+	close(release)
+	if err := <-otherErr; err != nil {
+		t.Errorf("other caller got %v, want its update sent", err)
+	}
+	state := q.State()
+	if state.Voice == nil || state.Instructions == nil {
+		t.Errorf("state %+v, want both updates applied", state)
+	}
+}
+
+func TestSessionUpdateQueue_CallerReturnsWhenItsUpdateIsSent(t *testing.T) {
+	var q SessionUpdateQueue
+	firstSent := make(chan struct{})
+	releaseFirst := make(chan struct{})
+	releaseSecond := make(chan struct{})
+	defer close(releaseSecond)
+	var mu sync.Mutex
+	sends := 0
+	send := func(ctx context.Context, s Session) error {
+		mu.Lock()
+		sends++
+		n := sends
+		mu.Unlock()
+		if n == 1 {
+			close(firstSent)
+			<-releaseFirst
+		} else {
+			<-releaseSecond
+		}
+		return nil
+	}
+
+	firstErr := make(chan error, 1)
+	go func() { firstErr <- q.submit(context.Background(), Session{Voice: Ptr("alloy")}, send) }()
+	<-firstSent
+	go func() { _ = q.submit(context.Background(), Session{Voice: Ptr("echo")}, send) }()
+	waitQueued(t, &q)
+
+	// The first caller's update is sent while the second one is still going out
+	close(releaseFirst)
+	select {
+	case err := <-firstErr:
+		if err != nil {
+			t.Errorf("first caller got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("first caller waited for the queued update of another caller")
+	}
+}
+
+func TestSessionUpdateQueue_RestoreSendsWithItsOwnSend(t *testing.T) {
+	var q SessionUpdateQueue
+	ctx := context.Background()
+	if err := q.submit(ctx, Session{Voice: Ptr("alloy")}, func(context.Context, Session) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	// An update in flight on the old connection, and one queued behind it
+	release := make(chan struct{})
+	old := func(ctx context.Context, s Session) error {
+		if s.Instructions != nil && *s.Instructions == "first" {
+			<-release
+		}
+		return nil
+	}
+	go func() { _ = q.submit(ctx, Session{Instructions: Ptr("first")}, old) }()
+	waitUntil(t, "the first update to be in flight", func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return q.inFlight && q.pending == nil
+	})
+	queued := make(chan error, 1)
+	go func() { queued <- q.submit(ctx, Session{Temperature: Ptr(0.7)}, old) }()
+	waitQueued(t, &q)
+
+	var restored []Session
+	restoreErr := make(chan error, 1)
+	go func() {
+		restoreErr <- q.restore(ctx, func(ctx context.Context, s Session) error {
+			restored = append(restored, s)
+			return nil
+		})
+	}()
+	waitUntil(t, "the restore to coalesce", func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return q.pending != nil && q.pending.full
+	})
+	close(release)
+	if err := <-restoreErr; err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if err := <-queued; err != nil {
+		t.Fatalf("queued update: %v", err)
+	}
+
+	// The queued update went out with the restore, which includes everything sent before it
+	if len(restored) != 1 {
+		t.Fatalf("restore sent %d updates, want 1", len(restored))
+	}
+	s := restored[0]
+	if s.Voice == nil || *s.Voice != "alloy" || s.Instructions == nil || *s.Instructions != "first" || s.Temperature == nil {
+		t.Errorf("restored %+v, want voice, instructions and temperature", s)
+	}
+}
+
+func TestSessionUpdateQueue_RestoreWithoutState(t *testing.T) {
+	var q SessionUpdateQueue
+	err := q.restore(context.Background(), func(context.Context, Session) error {
+		t.Error("restore sent an empty session")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_ConcurrentSessionUpdate(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for _, s := range []Session{
+		{Voice: Ptr("shimmer")},
+		{Instructions: Ptr("Test instructions")},
+		{InputAudioFormat: Ptr("pcm16")},
+	} {
+		s := s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.SessionUpdate(ctx, s); err != nil {
+				t.Errorf("SessionUpdate: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if client.SessionUpdates().Pending() {
+		t.Error("expected no pending updates")
+	}
+	state := client.SessionUpdates().State()
+	if state.Voice == nil || state.Instructions == nil || state.InputAudioFormat == nil {
+		t.Errorf("expected all fields in accumulated state, got %+v", state)
+	}
+}
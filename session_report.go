@@ -0,0 +1,264 @@
+package azrealtime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxReportErrors bounds the error details kept for SessionReport.Errors.
+const maxReportErrors = 20
+
+// Pricing holds per-token prices used to estimate SessionReport.EstimatedCost.
+// Prices are per one million tokens, in whatever currency you bill in.
+// Cached input tokens are charged at CachedInputPerMillion (text) or
+// CachedAudioInputPerMillion (audio) instead of the regular input price. When
+// CachedAudioInputPerMillion is zero, cached audio is charged at
+// AudioInputPerMillion, so costs are not underestimated.
+type Pricing struct {
+	TextInputPerMillion        float64
+	TextOutputPerMillion       float64
+	AudioInputPerMillion       float64
+	AudioOutputPerMillion      float64
+	CachedInputPerMillion      float64
+	CachedAudioInputPerMillion float64
+}
+
+// TokenUsage totals the token usage of all responses in a session.
+type TokenUsage struct {
+	InputTokens       int // All input tokens, including cached ones
+	OutputTokens      int // All output tokens
+	TotalTokens       int // Input plus output tokens
+	InputTextTokens   int // Text input tokens
+	InputAudioTokens  int // Audio input tokens
+	CachedTokens      int // Input tokens served from the prompt cache
+	CachedTextTokens  int // Cached text input tokens, when the server reports the breakdown
+	CachedAudioTokens int // Cached audio input tokens, when the server reports the breakdown
+	OutputTextTokens  int // Text output tokens
+	OutputAudioTokens int // Audio output tokens
+}
+
+// add accumulates the usage of one response.
+func (u *TokenUsage) add(r *ResponseUsage) {
+	if r == nil {
+		return
+	}
+	u.InputTokens += r.InputTokens
+	u.OutputTokens += r.OutputTokens
+	u.TotalTokens += r.TotalTokens
+	if d := r.InputTokenDetails; d != nil {
+		u.InputTextTokens += d.TextTokens
+		u.InputAudioTokens += d.AudioTokens
+		u.CachedTokens += d.CachedTokens
+		if c := d.CachedTokensDetails; c != nil {
+			u.CachedTextTokens += c.TextTokens
+			u.CachedAudioTokens += c.AudioTokens
+		}
+	}
+	if d := r.OutputTokenDetails; d != nil {
+		u.OutputTextTokens += d.TextTokens
+		u.OutputAudioTokens += d.AudioTokens
+	}
+}
+
+// Cost estimates the price of u. Cached tokens are billed at the cached rates
+// rather than the input rates; cached tokens the server did not break down
+// into text and audio are assumed to be text.
+func (p Pricing) Cost(u TokenUsage) float64 {
+	cachedText, cachedAudio := u.CachedTextTokens, u.CachedAudioTokens
+	if rest := u.CachedTokens - cachedText - cachedAudio; rest > 0 {
+		cachedText += rest
+	}
+	uncachedText := max(u.InputTextTokens-cachedText, 0)
+	uncachedAudio := max(u.InputAudioTokens-cachedAudio, 0)
+	cachedAudioPrice := p.CachedAudioInputPerMillion
+	if cachedAudioPrice == 0 {
+		cachedAudioPrice = p.AudioInputPerMillion
+	}
+	total := float64(uncachedText)*p.TextInputPerMillion +
+		float64(cachedText)*p.CachedInputPerMillion +
+		float64(uncachedAudio)*p.AudioInputPerMillion +
+		float64(cachedAudio)*cachedAudioPrice +
+		float64(u.OutputTextTokens)*p.TextOutputPerMillion +
+		float64(u.OutputAudioTokens)*p.AudioOutputPerMillion
+	return total / 1e6
+}
+
+// SessionReport summarizes a client's lifetime: one artifact to log per call.
+// It is produced when the client closes; see Client.CloseWithReport and
+// Client.OnSessionReport.
+type SessionReport struct {
+	SessionID       string         // ID of the last server session (changes on Reconnect)
+	StartedAt       time.Time      // When the client connected
+	Duration        time.Duration  // Time from connecting until Close
+	Turns           int            // Responses completed (response.done events)
+	Usage           TokenUsage     // Token usage summed over all responses
+	AudioInSeconds  float64        // Seconds of input audio appended via AppendPCM16 or AppendG711
+	AudioOutSeconds float64        // Seconds of assistant audio received
+	Errors          int            // Error events received from the server or raised by the client
+	ErrorDetails    []ErrorDetails // The first error events, up to 20
+	SendErrors      uint64         // Failed attempts to write an event
+	Reconnects      uint64         // Successful calls to Reconnect
+	EstimatedCost   float64        // Usage priced with Config.Pricing; zero without pricing
+	Err             error          // Why the client stopped (see Client.Err)
+}
+
+// sessionReportTracker accumulates the per-session totals behind SessionReport.
+type sessionReportTracker struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	sessionID string
+	turns     int
+	usage     TokenUsage
+	errors    int
+	details   []ErrorDetails
+	active    map[string]bool     // Responses created but not yet done
+	idle      chan struct{}       // Closed and cleared when active becomes empty
+	onReport  func(SessionReport) // Guarded by mu rather than handlerMu, see finishReport
+
+	once   sync.Once
+	report SessionReport
+}
+
+func (t *sessionReportTracker) sessionCreated(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sessionID = id
+}
+
+func (t *sessionReportTracker) responseCreated(id string) {
+	if id == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active == nil {
+		t.active = make(map[string]bool)
+	}
+	t.active[id] = true
+}
+
+func (t *sessionReportTracker) responseDone(r ResponseObject) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.turns++
+	t.usage.add(r.Usage)
+	delete(t.active, r.ID)
+	t.signalIdleLocked()
+}
+
+func (t *sessionReportTracker) errorReceived(e ErrorDetails) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errors++
+	if len(t.details) < maxReportErrors {
+		t.details = append(t.details, e)
+	}
+}
+
+// reset forgets in-progress responses, which cannot complete on a new connection.
+func (t *sessionReportTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active = nil
+	t.signalIdleLocked()
+}
+
+func (t *sessionReportTracker) signalIdleLocked() {
+	if len(t.active) == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// waitIdle blocks until no response is in progress, ctx is done, or closed is closed.
+func (t *sessionReportTracker) waitIdle(ctx context.Context, closed <-chan struct{}) {
+	t.mu.Lock()
+	if len(t.active) == 0 {
+		t.mu.Unlock()
+		return
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+	case <-closed:
+	}
+}
+
+// Usage returns the token usage of all responses completed so far, summed
+// across reconnects. It is the live counterpart of SessionReport.Usage, e.g.
+// for billing a conversation while it is still running.
+func (c *Client) Usage() TokenUsage {
+	c.report.mu.Lock()
+	defer c.report.mu.Unlock()
+	return c.report.usage
+}
+
+// EstimatedCost prices Usage with Config.Pricing. It returns zero without pricing.
+func (c *Client) EstimatedCost() float64 {
+	if c.cfg.Pricing == nil {
+		return 0
+	}
+	return c.cfg.Pricing.Cost(c.Usage())
+}
+
+// OnSessionReport registers a callback that receives the SessionReport when the
+// client closes, whether through Close or CloseWithReport. It is called once.
+func (c *Client) OnSessionReport(fn func(SessionReport)) {
+	c.report.mu.Lock()
+	defer c.report.mu.Unlock()
+	c.report.onReport = fn
+}
+
+// CloseWithReport waits until in-progress responses finish (so their usage is
+// counted) or ctx is done, closes the client, and returns its SessionReport.
+// Later calls return the same report.
+func (c *Client) CloseWithReport(ctx context.Context) (SessionReport, error) {
+	c.report.waitIdle(ctx, c.closedCh)
+	err := c.Close()
+	return c.finishReport(), err
+}
+
+// finishReport builds the SessionReport on the first call, hands it to the
+// OnSessionReport callback, and returns it.
+func (c *Client) finishReport() SessionReport {
+	t := &c.report
+	t.once.Do(func() {
+		st := c.Stats()
+		t.mu.Lock()
+		r := SessionReport{
+			SessionID:       t.sessionID,
+			StartedAt:       t.startedAt,
+			Turns:           t.turns,
+			Usage:           t.usage,
+			AudioInSeconds:  st.AudioInSeconds,
+			AudioOutSeconds: st.AudioOutSeconds,
+			Errors:          t.errors,
+			ErrorDetails:    append([]ErrorDetails(nil), t.details...),
+			SendErrors:      st.SendErrors,
+			Reconnects:      st.Reconnects,
+			Err:             c.Err(),
+		}
+		// Not under handlerMu: Close may be called from an event handler
+		fn := t.onReport
+		t.mu.Unlock()
+		if !r.StartedAt.IsZero() {
+			r.Duration = time.Since(r.StartedAt)
+		}
+		if c.cfg.Pricing != nil {
+			r.EstimatedCost = c.cfg.Pricing.Cost(r.Usage)
+		}
+		t.report = r
+
+		if fn != nil {
+			fn(r)
+		}
+	})
+	return t.report
+}
@@ -0,0 +1,224 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPricing_Cost(t *testing.T) {
+	p := Pricing{
+		TextInputPerMillion:   5,
+		TextOutputPerMillion:  20,
+		AudioInputPerMillion:  40,
+		AudioOutputPerMillion: 80,
+		CachedInputPerMillion: 2.5,
+	}
+	u := TokenUsage{
+		InputTextTokens:   1_000_000,
+		CachedTokens:      400_000,
+		InputAudioTokens:  500_000,
+		OutputTextTokens:  100_000,
+		OutputAudioTokens: 250_000,
+	}
+	// 0.6M*5 + 0.4M*2.5 + 0.5M*40 + 0.1M*20 + 0.25M*80
+	want := 3 + 1 + 20 + 2 + 20.0
+	if got := p.Cost(u); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost() = %v, want %v", got, want)
+	}
+	if got := (Pricing{}).Cost(u); got != 0 {
+		t.Errorf("zero pricing Cost() = %v, want 0", got)
+	}
+}
+
+func TestPricing_CostCachedAudio(t *testing.T) {
+	p := Pricing{
+		TextInputPerMillion:        5,
+		AudioInputPerMillion:       40,
+		CachedInputPerMillion:      2.5,
+		CachedAudioInputPerMillion: 2.5,
+	}
+	u := TokenUsage{
+		InputTextTokens:   1_000_000,
+		InputAudioTokens:  1_000_000,
+		CachedTokens:      700_000,
+		CachedTextTokens:  200_000,
+		CachedAudioTokens: 400_000,
+	}
+	// The 0.1M cached tokens without a breakdown count as text:
+	// 0.7M*5 + 0.3M*2.5 + 0.6M*40 + 0.4M*2.5
+	want := 3.5 + 0.75 + 24 + 1.0
+	if got := p.Cost(u); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost() = %v, want %v", got, want)
+	}
+
+	// Without a cached audio price, cached audio is billed as regular audio input
+	p.CachedAudioInputPerMillion = 0
+	want = 3.5 + 0.75 + 24 + 0.4*40
+	if got := p.Cost(u); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost() without a cached audio price = %v, want %v", got, want)
+	}
+}
+
+func TestClient_Usage(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	config := CreateMockConfig(mockServer.URL())
+	config.Pricing = &Pricing{TextOutputPerMillion: 1e6, AudioOutputPerMillion: 1e6}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	if u := client.Usage(); u != (TokenUsage{}) {
+		t.Errorf("Usage before any response = %+v", u)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Ask(ctx, "Hi"); err != nil {
+			t.Fatalf("Ask: %v", err)
+		}
+	}
+
+	u := client.Usage()
+	if u.TotalTokens != 300 || u.OutputTextTokens != 20 || u.CachedAudioTokens != 10 {
+		t.Errorf("Usage = %+v, want the sum of two responses", u)
+	}
+	// 10 text + 40 audio output tokens per response, at one unit per token
+	if got := client.EstimatedCost(); got != 100 {
+		t.Errorf("EstimatedCost = %v, want 100", got)
+	}
+}
+
+func TestClient_CloseWithReport(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	config := CreateMockConfig(mockServer.URL())
+	config.Pricing = &Pricing{TextInputPerMillion: 1e6, CachedInputPerMillion: 1e6, AudioOutputPerMillion: 1e6}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	var calls int
+	var fromCallback SessionReport
+	client.OnSessionReport(func(r SessionReport) {
+		calls++
+		fromCallback = r
+	})
+
+	if _, err := client.CreateResponse(ctx, CreateResponseOptions{Modalities: []string{"text"}}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	// Wait for response.created so CloseWithReport has a response to wait for
+	deadline := time.Now().Add(2 * time.Second)
+	for client.Stats().EventsReceived["response.created"] == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	report, err := client.CloseWithReport(ctx)
+	if err != nil {
+		t.Fatalf("CloseWithReport: %v", err)
+	}
+
+	if report.SessionID != "sess_mock_123" {
+		t.Errorf("SessionID = %q, want sess_mock_123", report.SessionID)
+	}
+	if report.Turns != 1 {
+		t.Errorf("Turns = %d, want 1", report.Turns)
+	}
+	want := TokenUsage{
+		InputTokens: 100, OutputTokens: 50, TotalTokens: 150,
+		InputTextTokens: 40, InputAudioTokens: 60, CachedTokens: 20,
+		CachedTextTokens: 15, CachedAudioTokens: 5,
+		OutputTextTokens: 10, OutputAudioTokens: 40,
+	}
+	if report.Usage != want {
+		t.Errorf("Usage = %+v, want %+v", report.Usage, want)
+	}
+	// 25 uncached text + 15 cached text + 40 audio out, at one unit per token
+	if report.EstimatedCost != 80 {
+		t.Errorf("EstimatedCost = %v, want 80", report.EstimatedCost)
+	}
+	if report.Duration <= 0 || report.StartedAt.IsZero() {
+		t.Errorf("Duration = %v, StartedAt = %v, want both set", report.Duration, report.StartedAt)
+	}
+	if !errors.Is(report.Err, ErrClosed) {
+		t.Errorf("Err = %v, want ErrClosed", report.Err)
+	}
+
+	if calls != 1 || fromCallback.Turns != report.Turns {
+		t.Errorf("callback called %d times with %+v, want once with the returned report", calls, fromCallback)
+	}
+
+	// Closing again neither rebuilds the report nor calls the callback again
+	_ = client.Close()
+	again, _ := client.CloseWithReport(ctx)
+	if calls != 1 || again.Duration != report.Duration {
+		t.Errorf("report rebuilt after second close (calls = %d)", calls)
+	}
+}
+
+func TestClient_SessionReportErrors(t *testing.T) {
+	c := NewReplayClient(Config{})
+	for i := 0; i < maxReportErrors+5; i++ {
+		c.handleMessage([]byte(`{"type":"error","error":{"type":"invalid_request_error","message":"bad"}}`))
+	}
+	c.handleMessage([]byte(`{"type":"response.created","response":{"id":"resp_1"}}`))
+
+	// An in-progress response that never completes doesn't block past ctx
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	report, _ := c.CloseWithReport(ctx)
+
+	if report.Errors != maxReportErrors+5 {
+		t.Errorf("Errors = %d, want %d", report.Errors, maxReportErrors+5)
+	}
+	if len(report.ErrorDetails) != maxReportErrors {
+		t.Errorf("len(ErrorDetails) = %d, want %d", len(report.ErrorDetails), maxReportErrors)
+	}
+	if report.Turns != 0 {
+		t.Errorf("Turns = %d, want 0", report.Turns)
+	}
+}
+
+func TestClient_CloseFromHandlerWithWaitingWriter(t *testing.T) {
+	c := NewReplayClient(Config{})
+	reported := make(chan SessionReport, 1)
+	c.OnSessionReport(func(r SessionReport) { reported <- r })
+	c.OnError(func(ErrorEvent) {
+		// A handler registration queues behind this handler's read lock
+		go c.OnResponseDone(func(ResponseDone) {})
+		time.Sleep(20 * time.Millisecond)
+		_ = c.Close()
+	})
+
+	handled := make(chan struct{})
+	go func() {
+		c.handleMessage([]byte(`{"type":"error","error":{"type":"invalid_request_error","message":"bad"}}`))
+		close(handled)
+	}()
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close from an event handler deadlocked")
+	}
+	select {
+	case r := <-reported:
+		if r.Errors != 1 {
+			t.Errorf("Errors = %d, want 1", r.Errors)
+		}
+	default:
+		t.Error("OnSessionReport was not called")
+	}
+}
@@ -0,0 +1,48 @@
+package azrealtime
+
+import "encoding/json"
+
+// SessionResource is the session configuration reported by the server in
+// session.updated events. Unlike Session, which only carries the fields being
+// changed, it holds the effective value of every setting.
+type SessionResource struct {
+	ID                 string              `json:"id,omitempty"`                         // Unique session identifier
+	Object             string              `json:"object,omitempty"`                     // Always "realtime.session"
+	Model              string              `json:"model,omitempty"`                      // Model name (e.g., "gpt-4o-realtime-preview")
+	Modalities         []string            `json:"modalities,omitempty"`                 // Output modalities: ["text", "audio"]
+	Instructions       string              `json:"instructions,omitempty"`               // System instructions
+	Voice              string              `json:"voice,omitempty"`                      // Voice used for audio responses
+	InputAudioFormat   string              `json:"input_audio_format,omitempty"`         // "pcm16", "g711_ulaw" or "g711_alaw"
+	OutputAudioFormat  string              `json:"output_audio_format,omitempty"`        // "pcm16", "g711_ulaw" or "g711_alaw"
+	InputTranscription *InputTranscription `json:"input_audio_transcription,omitempty"`  // Nil when transcription is off
+	TurnDetection      *TurnDetection      `json:"turn_detection,omitempty"`             // Nil when turn detection is off
+	Tools              []ToolDefinition    `json:"tools,omitempty"`                      // Functions available to the model
+	ToolChoice         any                 `json:"tool_choice,omitempty"`                // "auto", "none", "required" or a function object
+	Temperature        float64             `json:"temperature,omitempty"`                // Sampling temperature
+	MaxOutputTokens    MaxTokens           `json:"max_response_output_tokens,omitempty"` // Output limit per response, or MaxTokensInf
+	ExpiresAt          int64               `json:"expires_at,omitempty"`                 // Session expiration timestamp (Unix)
+
+	// Raw is the session object as received, for fields this struct does not
+	// model yet.
+	Raw json.RawMessage `json:"-"`
+}
+
+// ToolDefinition is a tool as reported by the server.
+type ToolDefinition struct {
+	Type        string         `json:"type"`                  // Always "function"
+	Name        string         `json:"name"`                  // Function name
+	Description string         `json:"description,omitempty"` // What the function does
+	Parameters  map[string]any `json:"parameters,omitempty"`  // JSON Schema of the arguments
+}
+
+// UnmarshalJSON decodes the known fields and keeps the whole object in Raw.
+func (s *SessionResource) UnmarshalJSON(data []byte) error {
+	type plain SessionResource
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*s = SessionResource(p)
+	s.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
@@ -0,0 +1,95 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSessionUpdated_Unmarshal(t *testing.T) {
+	raw := `{
+		"type": "session.updated",
+		"event_id": "evt_1",
+		"session": {
+			"id": "sess_1",
+			"object": "realtime.session",
+			"model": "gpt-4o-realtime-preview",
+			"modalities": ["text", "audio"],
+			"instructions": "Be brief.",
+			"voice": "verse",
+			"input_audio_format": "pcm16",
+			"output_audio_format": "g711_ulaw",
+			"input_audio_transcription": {"model": "whisper-1"},
+			"turn_detection": {"type": "server_vad", "threshold": 0.6, "silence_duration_ms": 400, "create_response": true},
+			"tools": [{"type": "function", "name": "lookup_order", "parameters": {"type": "object"}}],
+			"tool_choice": "auto",
+			"temperature": 0.8,
+			"max_response_output_tokens": "inf",
+			"future_setting": {"enabled": true}
+		}
+	}`
+	var e SessionUpdated
+	if err := json.Unmarshal([]byte(raw), &e); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	s := e.Session
+	if s.ID != "sess_1" || s.Voice != "verse" || s.OutputAudioFormat != "g711_ulaw" || s.Temperature != 0.8 {
+		t.Errorf("session = %+v", s)
+	}
+	if s.MaxOutputTokens != MaxTokensInf {
+		t.Errorf("MaxOutputTokens = %d, want MaxTokensInf", s.MaxOutputTokens)
+	}
+	if s.TurnDetection == nil || s.TurnDetection.SilenceDurationMS != 400 || !s.TurnDetection.CreateResponse {
+		t.Errorf("TurnDetection = %+v", s.TurnDetection)
+	}
+	if s.InputTranscription == nil || s.InputTranscription.Model != "whisper-1" {
+		t.Errorf("InputTranscription = %+v", s.InputTranscription)
+	}
+	if len(s.Tools) != 1 || s.Tools[0].Name != "lookup_order" || s.Tools[0].Parameters["type"] != "object" {
+		t.Errorf("Tools = %+v", s.Tools)
+	}
+	if s.ToolChoice != "auto" {
+		t.Errorf("ToolChoice = %v", s.ToolChoice)
+	}
+
+	// Fields the struct does not model are still available in Raw
+	var extra struct {
+		Future struct {
+			Enabled bool `json:"enabled"`
+		} `json:"future_setting"`
+	}
+	if err := json.Unmarshal(s.Raw, &extra); err != nil || !extra.Future.Enabled {
+		t.Errorf("Raw = %s, %v", s.Raw, err)
+	}
+}
+
+func TestClient_OnSessionUpdatedTyped(t *testing.T) {
+	client, _ := dialProfile(t, nil)
+
+	updates := make(chan SessionUpdated, 1)
+	client.OnSessionUpdated(func(e SessionUpdated) { updates <- e })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := client.SessionUpdate(ctx, Session{
+		Voice:         Ptr("alloy"),
+		TurnDetection: &TurnDetection{Type: "semantic_vad", Eagerness: "low"},
+	})
+	if err != nil {
+		t.Fatalf("SessionUpdate: %v", err)
+	}
+
+	select {
+	case e := <-updates:
+		if e.Session.ID != "sess_mock_123" || e.Session.Voice != "alloy" {
+			t.Errorf("session = %+v", e.Session)
+		}
+		if td := e.Session.TurnDetection; td == nil || td.Type != "semantic_vad" || td.Eagerness != "low" {
+			t.Errorf("TurnDetection = %+v", td)
+		}
+	case <-ctx.Done():
+		t.Fatal("no session.updated event")
+	}
+}
@@ -0,0 +1,46 @@
+package azrealtime
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// TrimSilence returns pcm (PCM16 little-endian) without its leading and
+// trailing silence: samples whose level is below thresholdDB dBFS, e.g. -50.
+// The result shares pcm's backing array; it is empty if no sample reaches the
+// threshold. A trailing odd byte is dropped.
+func TrimSilence(pcm []byte, thresholdDB float64) []byte {
+	limit := int(math.Ceil(32768 * math.Pow(10, thresholdDB/20)))
+	loud := func(i int) bool {
+		s := int(int16(binary.LittleEndian.Uint16(pcm[2*i:])))
+		if s < 0 {
+			s = -s
+		}
+		return s >= limit
+	}
+
+	n := len(pcm) / 2
+	start := 0
+	for start < n && !loud(start) {
+		start++
+	}
+	end := n
+	for end > start && !loud(end-1) {
+		end--
+	}
+	return pcm[2*start : 2*end]
+}
+
+// PadSilence returns a copy of pcm with ms milliseconds of silence at
+// DefaultSampleRate added before and after it. Trailing silence helps server
+// VAD end the turn promptly when audio comes from a file rather than a live
+// microphone.
+func PadSilence(pcm []byte, ms int) []byte {
+	if ms <= 0 {
+		return append([]byte(nil), pcm...)
+	}
+	pad := PCM16BytesFor(ms, DefaultSampleRate)
+	out := make([]byte, pad+len(pcm)+pad)
+	copy(out[pad:], pcm)
+	return out
+}
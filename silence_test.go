@@ -0,0 +1,50 @@
+package azrealtime
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTrimSilence(t *testing.T) {
+	tests := []struct {
+		name string
+		pcm  []byte
+		want []byte
+	}{
+		{"both ends", pcm16(0, 3, -5, 1000, -2000, 10, 0), pcm16(1000, -2000)},
+		{"inner quiet kept", pcm16(0, 500, 0, 0, -500, 0), pcm16(500, 0, 0, -500)},
+		{"all silent", pcm16(0, 1, -1, 2), []byte{}},
+		{"nothing to trim", pcm16(400, -400), pcm16(400, -400)},
+		{"odd byte", append(pcm16(0, 700), 0x01), pcm16(700)},
+		{"empty", nil, []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// -40 dBFS is an amplitude of about 328
+			if got := TrimSilence(tt.pcm, -40); !bytes.Equal(got, tt.want) {
+				t.Errorf("TrimSilence = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPadSilence(t *testing.T) {
+	pcm := pcm16(1, 2, 3)
+	got := PadSilence(pcm, 10)
+	pad := PCM16BytesFor(10, DefaultSampleRate)
+	if len(got) != 2*pad+len(pcm) {
+		t.Fatalf("length = %d, want %d", len(got), 2*pad+len(pcm))
+	}
+	if !bytes.Equal(got[pad:pad+len(pcm)], pcm) {
+		t.Error("audio not centered between the padding")
+	}
+	if !bytes.Equal(got[:pad], make([]byte, pad)) || !bytes.Equal(got[pad+len(pcm):], make([]byte, pad)) {
+		t.Error("padding is not silent")
+	}
+
+	same := PadSilence(pcm, 0)
+	same[0] = 9
+	if pcm[0] == 9 {
+		t.Error("PadSilence(0) returned the input instead of a copy")
+	}
+}
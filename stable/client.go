@@ -0,0 +1,71 @@
+package stable
+
+import (
+	"context"
+
+	v1 "github.com/enesunal-m/azrealtime"
+)
+
+// Conn is the set of operations on a connected realtime session. *Client
+// implements it; depend on Conn to substitute fakes or decorators in tests.
+type Conn interface {
+	UpdateSession(ctx context.Context, s SessionConfig) error
+	AppendPCM16(ctx context.Context, pcmLE []byte) error
+	InputCommit(ctx context.Context) error
+	InputClear(ctx context.Context) error
+	CreateConversationItem(ctx context.Context, item ConversationItem) error
+	CreateResponse(ctx context.Context, opts CreateResponseOptions) (string, error)
+	CancelResponse(ctx context.Context) error
+	Close() error
+	Done() <-chan struct{}
+	Err() error
+}
+
+var _ Conn = (*Client)(nil)
+
+// Client is a realtime connection. It embeds the v1 client, so every v1 method
+// is available; register event handlers with On rather than the v1 On* methods.
+type Client struct {
+	*v1.Client
+	hub *hub
+}
+
+// Dial connects to deployment at endpoint, configured by opts:
+//
+//	client, err := stable.Dial(ctx, endpoint, deployment,
+//		stable.WithAPIKey(key),
+//		stable.WithTimeout(15*time.Second),
+//	)
+func Dial(ctx context.Context, endpoint, deployment string, opts ...Option) (*Client, error) {
+	return DialConfig(ctx, NewConfig(endpoint, deployment, opts...))
+}
+
+// DialConfig connects using a fully populated Config.
+func DialConfig(ctx context.Context, cfg Config) (*Client, error) {
+	c, err := v1.Dial(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(c), nil
+}
+
+// Wrap adapts a v1 client, e.g. one from v1.NewReplayClient, to the stable API.
+// Handlers registered with On replace any v1 callback set for the same event.
+func Wrap(c *v1.Client) *Client {
+	return &Client{Client: c, hub: newHub(c)}
+}
+
+// UpdateSession sends a session.update built from s.
+func (c *Client) UpdateSession(ctx context.Context, s SessionConfig) error {
+	return c.SessionUpdate(ctx, s.Session())
+}
+
+// Scope returns a handler group on c. Handlers registered through the scope
+// are removed together by Scope.Close.
+func (c *Client) Scope() *Scope {
+	s := &Scope{}
+	s.view = &hub{parent: c.hub, scope: s}
+	return s
+}
+
+func (c *Client) handlerHub() *hub { return c.hub }
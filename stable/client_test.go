@@ -0,0 +1,129 @@
+package stable
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/enesunal-m/azrealtime"
+)
+
+const transcript = `{"dir":"inbound","type":"response.text.delta","event":{"type":"response.text.delta","response_id":"r1","delta":"Hel"}}
+{"dir":"inbound","type":"response.text.delta","event":{"type":"response.text.delta","response_id":"r1","delta":"lo"}}
+{"dir":"inbound","type":"response.done","event":{"type":"response.done","response":{"id":"r1","status":"completed"}}}
+`
+
+func replay(t *testing.T, c *Client) {
+	t.Helper()
+	if err := v1.Replay(context.Background(), strings.NewReader(transcript), c.Client, v1.ReplayOptions{NoDelay: true}); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+}
+
+func newTestClient() *Client {
+	return Wrap(v1.NewReplayClient(NewConfig("https://example.openai.azure.com", "gpt-4o-realtime", WithAPIKey("k"))))
+}
+
+func TestOn_MultipleHandlersAndOff(t *testing.T) {
+	c := newTestClient()
+
+	var a, b strings.Builder
+	offA := On(c, func(e ResponseTextDelta) { a.WriteString(e.Delta) })
+	On(c, func(e ResponseTextDelta) { b.WriteString(e.Delta) })
+	done := 0
+	On(c, func(ResponseDone) { done++ })
+
+	replay(t, c)
+	if a.String() != "Hello" || b.String() != "Hello" {
+		t.Errorf("handlers got %q and %q, want Hello twice", a.String(), b.String())
+	}
+	if done != 1 {
+		t.Errorf("done handler ran %d times, want 1", done)
+	}
+
+	offA()
+	offA() // Idempotent
+	replay(t, c)
+	if a.String() != "Hello" {
+		t.Errorf("removed handler still ran: %q", a.String())
+	}
+	if b.String() != "HelloHello" {
+		t.Errorf("remaining handler got %q", b.String())
+	}
+}
+
+func TestScope_Close(t *testing.T) {
+	c := newTestClient()
+	s := c.Scope()
+
+	var scoped, global int
+	On(s, func(ResponseTextDelta) { scoped++ })
+	On(c, func(ResponseTextDelta) { global++ })
+
+	replay(t, c)
+	s.Close()
+	replay(t, c)
+	if scoped != 2 || global != 4 {
+		t.Errorf("scoped = %d, global = %d, want 2 and 4", scoped, global)
+	}
+
+	On(s, func(ResponseTextDelta) { scoped++ }) // Registered after Close
+	replay(t, c)
+	if scoped != 2 {
+		t.Errorf("handler registered on closed scope ran")
+	}
+}
+
+func TestSessionConfig_Session(t *testing.T) {
+	got := SessionConfig{
+		Voice:             VoiceVerse,
+		Instructions:      "Be brief.",
+		InputAudioFormat:  AudioFormatPCM16,
+		OutputAudioFormat: AudioFormatG711ULaw,
+		TurnDetection: &TurnDetection{
+			Type:            TurnDetectionServerVAD,
+			Threshold:       0.6,
+			PrefixPadding:   300 * time.Millisecond,
+			SilenceDuration: 500 * time.Millisecond,
+			CreateResponse:  true,
+		},
+		Modalities:      []Modality{ModalityText},
+		Temperature:     0.7,
+		MaxOutputTokens: 512,
+	}.Session()
+
+	want := Session{
+		Voice:             v1.Ptr("verse"),
+		Instructions:      v1.Ptr("Be brief."),
+		InputAudioFormat:  v1.Ptr("pcm16"),
+		OutputAudioFormat: v1.Ptr("g711_ulaw"),
+		TurnDetection: &v1.TurnDetection{
+			Type:              "server_vad",
+			Threshold:         0.6,
+			PrefixPaddingMS:   300,
+			SilenceDurationMS: 500,
+			CreateResponse:    true,
+		},
+		Modalities:      []string{"text"},
+		Temperature:     v1.Ptr(0.7),
+		MaxOutputTokens: 512,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Session() = %+v, want %+v", got, want)
+	}
+
+	if empty := (SessionConfig{}).Session(); !reflect.DeepEqual(empty, Session{}) {
+		t.Errorf("empty config produced %+v", empty)
+	}
+	if err := v1.ValidateSession(got); err != nil {
+		t.Errorf("converted session invalid: %v", err)
+	}
+}
+
+func TestModalities(t *testing.T) {
+	if got := Modalities(ModalityAudio, ModalityText); !reflect.DeepEqual(got, []string{"audio", "text"}) {
+		t.Errorf("Modalities = %v", got)
+	}
+}
@@ -0,0 +1,21 @@
+package stable
+
+import (
+	"context"
+
+	v1 "github.com/enesunal-m/azrealtime"
+)
+
+// Names kept from v1 so call sites can be migrated gradually.
+
+// DialWith connects using functional options.
+//
+// Deprecated: Use Dial, which takes the same arguments.
+func DialWith(ctx context.Context, endpoint, deployment string, opts ...Option) (*Client, error) {
+	return Dial(ctx, endpoint, deployment, opts...)
+}
+
+// Ptr returns a pointer to v, for populating Session fields.
+//
+// Deprecated: Use SessionConfig, whose fields are typed values.
+func Ptr[T any](v T) *T { return v1.Ptr(v) }
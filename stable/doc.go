@@ -0,0 +1,44 @@
+// Package stable is the stable API surface of the azrealtime client.
+//
+// It builds on the root azrealtime package (called v1 below) rather than
+// replacing it: connections, events, sessions and utilities are the same
+// types, re-exported here as aliases, so values move freely between the two
+// packages and v1 helpers such as SessionRecorder keep working. What stable
+// adds is the consolidated ergonomics:
+//
+//   - Functional options: Dial(ctx, endpoint, deployment, opts...) is the one
+//     way to connect; Config remains available through DialConfig.
+//   - Scoped handlers: On registers any number of typed handlers per event and
+//     returns a function that removes them; a Scope removes a group at once.
+//   - Typed enums: Voice, Modality, AudioFormat, TurnDetectionType and
+//     Eagerness replace bare strings in SessionConfig.
+//   - Interfaces: Conn describes the operations of a connected client, for
+//     mocking and decoration.
+//
+// Basic usage:
+//
+//	client, err := stable.Dial(ctx, endpoint, deployment, stable.WithAPIKey(key))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer client.Close()
+//
+//	off := stable.On(client, func(e stable.ResponseTextDelta) { fmt.Print(e.Delta) })
+//	defer off()
+//
+//	err = client.UpdateSession(ctx, stable.SessionConfig{Voice: stable.VoiceAlloy})
+//
+// # Migrating from v1
+//
+//	v1                                   stable
+//	azrealtime.Dial(ctx, cfg)            stable.DialConfig(ctx, cfg)
+//	azrealtime.DialWith(ctx, e, d, ...)  stable.Dial(ctx, e, d, ...)
+//	client.OnResponseTextDelta(fn)       stable.On(client, fn)
+//	client.SessionUpdate(ctx, Session{}) client.UpdateSession(ctx, SessionConfig{})
+//	Ptr("alloy")                         VoiceAlloy
+//
+// v1 names that stable renamed are kept as deprecated shims so code can be
+// migrated one call site at a time. The v1 On* methods remain reachable on
+// Client but replace each other's single callback; do not mix them with On
+// for the same event type.
+package stable
@@ -0,0 +1,211 @@
+package stable
+
+import (
+	"reflect"
+	"sync"
+
+	v1 "github.com/enesunal-m/azrealtime"
+)
+
+// Event is the set of server events handlers can be registered for.
+type Event interface {
+	ErrorEvent | SessionCreated | SessionUpdated | RateLimitsUpdated |
+		ResponseTextDelta | ResponseTextDone | ResponseAudioDelta | ResponseAudioDone |
+		ResponseAudioTranscriptDelta | ResponseAudioTranscriptDone |
+		InputAudioBufferSpeechStarted | InputAudioBufferSpeechStopped |
+		InputAudioBufferCommitted | InputAudioBufferCleared |
+		ConversationItemCreated | ConversationItemInputAudioTranscriptionCompleted |
+		ConversationItemInputAudioTranscriptionFailed | ConversationItemInputAudioTranscriptionDelta |
+		ConversationItemTruncated | ConversationItemDeleted |
+		ResponseCreated | ResponseDone | ResponseOutputItemAdded | ResponseOutputItemDone |
+		ResponseContentPartAdded | ResponseContentPartDone |
+		ResponseFunctionCallArgumentsDelta | ResponseFunctionCallArgumentsDone
+}
+
+// Handlers is where On registers handlers: a *Client or a *Scope.
+type Handlers interface {
+	handlerHub() *hub
+}
+
+// On registers fn for events of type E and returns a function that removes it.
+// Any number of handlers may be registered per event type; they run in
+// registration order on the client's read goroutine, so they must not block.
+//
+//	off := stable.On(client, func(e stable.ResponseTextDelta) {
+//		fmt.Print(e.Delta)
+//	})
+//	defer off()
+func On[E Event](h Handlers, fn func(E)) (off func()) {
+	hb := h.handlerHub()
+	return hb.add(reflect.TypeOf((*E)(nil)).Elem(), fn, install[E])
+}
+
+// Scope groups handlers so they can be removed together, e.g. for the lifetime
+// of one call or UI view. A Scope is safe for concurrent use.
+type Scope struct {
+	view *hub // Registers on the client hub and tracks removal
+
+	mu     sync.Mutex
+	offs   []func()
+	closed bool
+}
+
+func (s *Scope) handlerHub() *hub { return s.view }
+
+// Close removes every handler registered through s. Handlers registered after
+// Close are removed immediately.
+func (s *Scope) Close() {
+	s.mu.Lock()
+	offs := s.offs
+	s.offs, s.closed = nil, true
+	s.mu.Unlock()
+	for _, off := range offs {
+		off()
+	}
+}
+
+// track records off for Close, or calls it right away if s is closed.
+func (s *Scope) track(off func()) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		off()
+		return
+	}
+	s.offs = append(s.offs, off)
+	s.mu.Unlock()
+}
+
+// hub fans each event type out to its registered handlers. The v1 callback for
+// a type is installed the first time a handler is registered for it.
+type hub struct {
+	client *v1.Client
+
+	mu        sync.RWMutex
+	handlers  map[reflect.Type][]*handler
+	installed map[reflect.Type]bool
+
+	// A scope's view of the client hub
+	parent *hub
+	scope  *Scope
+}
+
+type handler struct {
+	fn any // func(E)
+}
+
+func newHub(c *v1.Client) *hub {
+	return &hub{
+		client:    c,
+		handlers:  make(map[reflect.Type][]*handler),
+		installed: make(map[reflect.Type]bool),
+	}
+}
+
+func (h *hub) add(t reflect.Type, fn any, inst func(*hub)) func() {
+	if h.parent != nil {
+		off := h.parent.add(t, fn, inst)
+		h.scope.track(off)
+		return off
+	}
+
+	hd := &handler{fn: fn}
+	h.mu.Lock()
+	h.handlers[t] = append(h.handlers[t], hd)
+	needInstall := !h.installed[t]
+	h.installed[t] = true
+	h.mu.Unlock()
+	if needInstall {
+		inst(h)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			list := h.handlers[t]
+			for i, x := range list {
+				if x == hd {
+					h.handlers[t] = append(list[:i:i], list[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// dispatch calls every handler registered for E. The list is copied so
+// handlers may register or remove handlers.
+func dispatch[E Event](h *hub, e E) {
+	h.mu.RLock()
+	list := h.handlers[reflect.TypeOf(e)]
+	h.mu.RUnlock()
+	for _, hd := range list {
+		hd.fn.(func(E))(e)
+	}
+}
+
+// install hooks the v1 callback for E up to the hub.
+func install[E Event](h *hub) {
+	emit := func(e E) { dispatch(h, e) }
+	c := h.client
+	switch fn := any(emit).(type) {
+	case func(ErrorEvent):
+		c.OnError(fn)
+	case func(SessionCreated):
+		c.OnSessionCreated(fn)
+	case func(SessionUpdated):
+		c.OnSessionUpdated(fn)
+	case func(RateLimitsUpdated):
+		c.OnRateLimitsUpdated(fn)
+	case func(ResponseTextDelta):
+		c.OnResponseTextDelta(fn)
+	case func(ResponseTextDone):
+		c.OnResponseTextDone(fn)
+	case func(ResponseAudioDelta):
+		c.OnResponseAudioDelta(fn)
+	case func(ResponseAudioDone):
+		c.OnResponseAudioDone(fn)
+	case func(ResponseAudioTranscriptDelta):
+		c.OnResponseAudioTranscriptDelta(fn)
+	case func(ResponseAudioTranscriptDone):
+		c.OnResponseAudioTranscriptDone(fn)
+	case func(InputAudioBufferSpeechStarted):
+		c.OnInputAudioBufferSpeechStarted(fn)
+	case func(InputAudioBufferSpeechStopped):
+		c.OnInputAudioBufferSpeechStopped(fn)
+	case func(InputAudioBufferCommitted):
+		c.OnInputAudioBufferCommitted(fn)
+	case func(InputAudioBufferCleared):
+		c.OnInputAudioBufferCleared(fn)
+	case func(ConversationItemCreated):
+		c.OnConversationItemCreated(fn)
+	case func(ConversationItemInputAudioTranscriptionCompleted):
+		c.OnConversationItemInputAudioTranscriptionCompleted(fn)
+	case func(ConversationItemInputAudioTranscriptionFailed):
+		c.OnConversationItemInputAudioTranscriptionFailed(fn)
+	case func(ConversationItemInputAudioTranscriptionDelta):
+		c.OnConversationItemInputAudioTranscriptionDelta(fn)
+	case func(ConversationItemTruncated):
+		c.OnConversationItemTruncated(fn)
+	case func(ConversationItemDeleted):
+		c.OnConversationItemDeleted(fn)
+	case func(ResponseCreated):
+		c.OnResponseCreated(fn)
+	case func(ResponseDone):
+		c.OnResponseDone(fn)
+	case func(ResponseOutputItemAdded):
+		c.OnResponseOutputItemAdded(fn)
+	case func(ResponseOutputItemDone):
+		c.OnResponseOutputItemDone(fn)
+	case func(ResponseContentPartAdded):
+		c.OnResponseContentPartAdded(fn)
+	case func(ResponseContentPartDone):
+		c.OnResponseContentPartDone(fn)
+	case func(ResponseFunctionCallArgumentsDelta):
+		c.OnResponseFunctionCallArgumentsDelta(fn)
+	case func(ResponseFunctionCallArgumentsDone):
+		c.OnResponseFunctionCallArgumentsDone(fn)
+	}
+}
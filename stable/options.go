@@ -0,0 +1,92 @@
+package stable
+
+import (
+	"log/slog"
+	"time"
+
+	v1 "github.com/enesunal-m/azrealtime"
+)
+
+// Option configures a Config built by NewConfig or Dial.
+type Option = v1.Option
+
+// DefaultMaxMessageBytes is the default Config.MaxMessageBytes.
+const DefaultMaxMessageBytes = v1.DefaultMaxMessageBytes
+
+// NewConfig builds a Config for endpoint and deployment, applying opts in order.
+// APIVersion defaults to DefaultAPIVersion.
+func NewConfig(endpoint, deployment string, opts ...Option) Config {
+	return v1.NewConfig(endpoint, deployment, opts...)
+}
+
+// WithProvider selects the realtime service (see Config.Provider).
+func WithProvider(p Provider) Option { return v1.WithProvider(p) }
+
+// WithAPIVersion sets Config.APIVersion.
+func WithAPIVersion(v string) Option { return v1.WithAPIVersion(v) }
+
+// WithCredential sets Config.Credential.
+func WithCredential(cred Credential) Option { return v1.WithCredential(cred) }
+
+// WithAPIKey authenticates with an API key.
+func WithAPIKey(key string) Option { return v1.WithAPIKey(key) }
+
+// WithBearer authenticates with a Bearer token.
+func WithBearer(token string) Option { return v1.WithBearer(token) }
+
+// WithTimeout sets Config.DialTimeout.
+func WithTimeout(d time.Duration) Option { return v1.WithTimeout(d) }
+
+// WithHandshakeHeader adds a header to the WebSocket handshake request.
+func WithHandshakeHeader(key, value string) Option { return v1.WithHandshakeHeader(key, value) }
+
+// WithLogger sets Config.StructuredLogger.
+func WithLogger(l StructuredLogger) Option { return v1.WithLogger(l) }
+
+// WithSlogLogger sets Config.StructuredLogger to a logger writing to sl.
+func WithSlogLogger(sl *slog.Logger) Option { return v1.WithLogger(v1.NewSlogLogger(sl)) }
+
+// WithLogSampling sets Config.LogSampling.
+func WithLogSampling(s LogSampling) Option { return v1.WithLogSampling(s) }
+
+// WithCorrelationID sets Config.CorrelationID.
+func WithCorrelationID(id string) Option { return v1.WithCorrelationID(id) }
+
+// WithLogFunc sets the simple Config.Logger callback.
+func WithLogFunc(fn func(event string, fields map[string]any)) Option { return v1.WithLogFunc(fn) }
+
+// WithSessionExpiryWarning sets Config.SessionExpiryWarning.
+func WithSessionExpiryWarning(d time.Duration) Option { return v1.WithSessionExpiryWarning(d) }
+
+// WithReconnectOnSessionExpiry enables Config.ReconnectOnSessionExpiry.
+func WithReconnectOnSessionExpiry() Option { return v1.WithReconnectOnSessionExpiry() }
+
+// WithStreamIntegrityChecks enables Config.StreamIntegrityChecks.
+func WithStreamIntegrityChecks() Option { return v1.WithStreamIntegrityChecks() }
+
+// WithAutoReconnect sets Config.AutoReconnect.
+func WithAutoReconnect(policy AutoReconnectConfig) Option { return v1.WithAutoReconnect(policy) }
+
+// WithStatsHandler sets Config.StatsHandler and Config.StatsInterval.
+// A zero interval uses the v1 DefaultStatsInterval.
+func WithStatsHandler(fn func(Stats), interval time.Duration) Option {
+	return v1.WithStatsHandler(fn, interval)
+}
+
+// WithReconnectOnErrors sets Config.ReconnectOnErrors.
+func WithReconnectOnErrors(classes ...ErrorClass) Option { return v1.WithReconnectOnErrors(classes...) }
+
+// WithRateLimitMode sets Config.RateLimitMode.
+func WithRateLimitMode(m RateLimitMode) Option { return v1.WithRateLimitMode(m) }
+
+// WithAdaptiveLimiter sets Config.AdaptiveLimiter.
+func WithAdaptiveLimiter(l *AdaptiveLimiter) Option { return v1.WithAdaptiveLimiter(l) }
+
+// WithValidationMode sets Config.ValidationMode.
+func WithValidationMode(m ValidationMode) Option { return v1.WithValidationMode(m) }
+
+// WithMaxMessageBytes sets Config.MaxMessageBytes.
+func WithMaxMessageBytes(n int64) Option { return v1.WithMaxMessageBytes(n) }
+
+// WithPricing sets Config.Pricing, enabling cost estimates in SessionReport.
+func WithPricing(p Pricing) Option { return v1.WithPricing(p) }
@@ -0,0 +1,100 @@
+package stable
+
+import (
+	"time"
+
+	v1 "github.com/enesunal-m/azrealtime"
+)
+
+// SessionConfig is a typed session configuration. Zero-valued fields are left
+// unchanged on the server.
+type SessionConfig struct {
+	Voice             Voice
+	Instructions      string
+	InputAudioFormat  AudioFormat
+	OutputAudioFormat AudioFormat
+
+	// Transcription enables transcription of user audio.
+	Transcription *InputTranscription
+
+	// TurnDetection configures voice activity detection. Nil leaves it unchanged.
+	TurnDetection *TurnDetection
+
+	// Tools defines function calling capabilities available to the assistant.
+	Tools []any
+
+	// Modalities, Temperature and MaxOutputTokens set the defaults for
+	// responses; see the v1 Session.
+	Modalities      []Modality
+	Temperature     float64
+	MaxOutputTokens MaxTokens
+}
+
+// TurnDetection configures voice activity detection with typed values.
+type TurnDetection struct {
+	Type TurnDetectionType
+
+	// Threshold is the server VAD activation threshold (0.0-1.0).
+	Threshold float64
+
+	// PrefixPadding is the audio kept before detected speech (server VAD).
+	PrefixPadding time.Duration
+
+	// SilenceDuration is the silence that ends a turn (server VAD).
+	SilenceDuration time.Duration
+
+	// CreateResponse and InterruptResponse control automatic responses and
+	// barge-in; see the v1 TurnDetection.
+	CreateResponse    bool
+	InterruptResponse bool
+
+	// Eagerness tunes semantic VAD.
+	Eagerness Eagerness
+}
+
+// Session converts s to the wire-level Session.
+func (s SessionConfig) Session() Session {
+	var out Session
+	if s.Voice != "" {
+		out.Voice = v1.Ptr(string(s.Voice))
+	}
+	if s.Instructions != "" {
+		out.Instructions = v1.Ptr(s.Instructions)
+	}
+	if s.InputAudioFormat != "" {
+		out.InputAudioFormat = v1.Ptr(string(s.InputAudioFormat))
+	}
+	if s.OutputAudioFormat != "" {
+		out.OutputAudioFormat = v1.Ptr(string(s.OutputAudioFormat))
+	}
+	out.InputTranscription = s.Transcription
+	if td := s.TurnDetection; td != nil {
+		out.TurnDetection = &v1.TurnDetection{
+			Type:              string(td.Type),
+			Threshold:         td.Threshold,
+			PrefixPaddingMS:   int(td.PrefixPadding / time.Millisecond),
+			SilenceDurationMS: int(td.SilenceDuration / time.Millisecond),
+			CreateResponse:    td.CreateResponse,
+			InterruptResponse: td.InterruptResponse,
+			Eagerness:         string(td.Eagerness),
+		}
+	}
+	out.Tools = s.Tools
+	if len(s.Modalities) > 0 {
+		out.Modalities = Modalities(s.Modalities...)
+	}
+	if s.Temperature != 0 {
+		out.Temperature = v1.Ptr(s.Temperature)
+	}
+	out.MaxOutputTokens = s.MaxOutputTokens
+	return out
+}
+
+// Modalities converts typed modalities for CreateResponseOptions.Modalities.
+func Modalities(ms ...Modality) []string {
+	out := make([]string, len(ms))
+	for i, m := range ms {
+		out[i] = string(m)
+	}
+	return out
+}
@@ -0,0 +1,200 @@
+package stable
+
+import (
+	v1 "github.com/enesunal-m/azrealtime"
+)
+
+// Core types shared with v1.
+type (
+	Config                = v1.Config
+	Credential            = v1.Credential
+	APIKey                = v1.APIKey
+	Bearer                = v1.Bearer
+	Provider              = v1.Provider
+	Session               = v1.Session
+	InputTranscription    = v1.InputTranscription
+	CreateResponseOptions = v1.CreateResponseOptions
+	ConversationItem      = v1.ConversationItem
+	ContentPart           = v1.ContentPart
+	ResponseObject        = v1.ResponseObject
+	ErrorDetails          = v1.ErrorDetails
+	ErrorClass            = v1.ErrorClass
+	Stats                 = v1.Stats
+	LatencyStats          = v1.LatencyStats
+	ResponseLatency       = v1.ResponseLatency
+	SessionReport         = v1.SessionReport
+	SessionResource       = v1.SessionResource
+	ToolDefinition        = v1.ToolDefinition
+	Pricing               = v1.Pricing
+	TokenUsage            = v1.TokenUsage
+	StreamOptions         = v1.StreamOptions
+	RateLimitMode         = v1.RateLimitMode
+	ValidationMode        = v1.ValidationMode
+	AutoReconnectConfig   = v1.AutoReconnectConfig
+	StreamIntegrityError  = v1.StreamIntegrityError
+	AdaptiveLimiter       = v1.AdaptiveLimiter
+	RetryConfig           = v1.RetryConfig
+	Logger                = v1.Logger
+	StructuredLogger      = v1.StructuredLogger
+	LogSampling           = v1.LogSampling
+	Tool                  = v1.Tool
+	ToolHandler           = v1.ToolHandler
+	ToolRegistry          = v1.ToolRegistry
+	ToolCall              = v1.ToolCall
+	ArgumentsAssembler    = v1.ArgumentsAssembler
+	Transcript            = v1.Transcript
+	TranscriptEntry       = v1.TranscriptEntry
+	ResponseTracker       = v1.ResponseTracker
+	CompletedResponse     = v1.CompletedResponse
+	MaxTokens             = v1.MaxTokens
+	ConversationHandle    = v1.ConversationHandle
+	InstructionsTemplate  = v1.InstructionsTemplate
+)
+
+// Server events, usable with On.
+type (
+	ErrorEvent                                       = v1.ErrorEvent
+	SessionCreated                                   = v1.SessionCreated
+	SessionUpdated                                   = v1.SessionUpdated
+	RateLimitsUpdated                                = v1.RateLimitsUpdated
+	ResponseTextDelta                                = v1.ResponseTextDelta
+	ResponseTextDone                                 = v1.ResponseTextDone
+	ResponseAudioDelta                               = v1.ResponseAudioDelta
+	ResponseAudioDone                                = v1.ResponseAudioDone
+	ResponseAudioTranscriptDelta                     = v1.ResponseAudioTranscriptDelta
+	ResponseAudioTranscriptDone                      = v1.ResponseAudioTranscriptDone
+	InputAudioBufferSpeechStarted                    = v1.InputAudioBufferSpeechStarted
+	InputAudioBufferSpeechStopped                    = v1.InputAudioBufferSpeechStopped
+	InputAudioBufferCommitted                        = v1.InputAudioBufferCommitted
+	InputAudioBufferCleared                          = v1.InputAudioBufferCleared
+	ConversationItemCreated                          = v1.ConversationItemCreated
+	ConversationItemInputAudioTranscriptionCompleted = v1.ConversationItemInputAudioTranscriptionCompleted
+	ConversationItemInputAudioTranscriptionFailed    = v1.ConversationItemInputAudioTranscriptionFailed
+	ConversationItemInputAudioTranscriptionDelta     = v1.ConversationItemInputAudioTranscriptionDelta
+	ConversationItemTruncated                        = v1.ConversationItemTruncated
+	ConversationItemDeleted                          = v1.ConversationItemDeleted
+	ResponseCreated                                  = v1.ResponseCreated
+	ResponseDone                                     = v1.ResponseDone
+	ResponseOutputItemAdded                          = v1.ResponseOutputItemAdded
+	ResponseOutputItemDone                           = v1.ResponseOutputItemDone
+	ResponseContentPartAdded                         = v1.ResponseContentPartAdded
+	ResponseContentPartDone                          = v1.ResponseContentPartDone
+	ResponseFunctionCallArgumentsDelta               = v1.ResponseFunctionCallArgumentsDelta
+	ResponseFunctionCallArgumentsDone                = v1.ResponseFunctionCallArgumentsDone
+)
+
+// Errors shared with v1, for use with errors.Is and errors.As.
+var (
+	ErrClosed           = v1.ErrClosed
+	ErrInvalidConfig    = v1.ErrInvalidConfig
+	ErrConnectionFailed = v1.ErrConnectionFailed
+	ErrSendTimeout      = v1.ErrSendTimeout
+	ErrRateLimited      = v1.ErrRateLimited
+
+	ErrTransportUnsupported = v1.ErrTransportUnsupported
+	ErrResponseInterrupted  = v1.ErrResponseInterrupted
+)
+
+type (
+	ConfigError     = v1.ConfigError
+	ConnectionError = v1.ConnectionError
+	SendError       = v1.SendError
+	RateLimitError  = v1.RateLimitError
+)
+
+// Constants shared with v1.
+const (
+	ProviderAzure  = v1.ProviderAzure
+	ProviderOpenAI = v1.ProviderOpenAI
+
+	DefaultAPIVersion = v1.DefaultAPIVersion
+	DefaultSampleRate = v1.DefaultSampleRate
+	DefaultChunkMS    = v1.DefaultChunkMS
+
+	ConversationAuto = v1.ConversationAuto
+	ConversationNone = v1.ConversationNone
+
+	MaxTokensInf       = v1.MaxTokensInf
+	ToolChoiceAuto     = v1.ToolChoiceAuto
+	ToolChoiceNone     = v1.ToolChoiceNone
+	ToolChoiceRequired = v1.ToolChoiceRequired
+
+	TranscriptionModelWhisper1            = v1.TranscriptionModelWhisper1
+	TranscriptionModelGPT4oTranscribe     = v1.TranscriptionModelGPT4oTranscribe
+	TranscriptionModelGPT4oMiniTranscribe = v1.TranscriptionModelGPT4oMiniTranscribe
+
+	RateLimitIgnore = v1.RateLimitIgnore
+	RateLimitWait   = v1.RateLimitWait
+	RateLimitFail   = v1.RateLimitFail
+
+	ValidationStrict = v1.ValidationStrict
+	ValidationWarn   = v1.ValidationWarn
+	ValidationOff    = v1.ValidationOff
+
+	ErrorClassSessionExpired = v1.ErrorClassSessionExpired
+	ErrorClassInvalidState   = v1.ErrorClassInvalidState
+	ErrorClassServer         = v1.ErrorClassServer
+	ErrorClassRateLimit      = v1.ErrorClassRateLimit
+	ErrorClassInvalidRequest = v1.ErrorClassInvalidRequest
+	ErrorClassClient         = v1.ErrorClassClient
+	ErrorClassOther          = v1.ErrorClassOther
+)
+
+// Voice selects the assistant's voice.
+type Voice string
+
+// Voices accepted by ValidateSession (see the v1 Voices list).
+const (
+	VoiceAlloy   Voice = v1.VoiceAlloy
+	VoiceAsh     Voice = v1.VoiceAsh
+	VoiceBallad  Voice = v1.VoiceBallad
+	VoiceCedar   Voice = v1.VoiceCedar
+	VoiceCoral   Voice = v1.VoiceCoral
+	VoiceEcho    Voice = v1.VoiceEcho
+	VoiceFable   Voice = v1.VoiceFable
+	VoiceMarin   Voice = v1.VoiceMarin
+	VoiceNova    Voice = v1.VoiceNova
+	VoiceOnyx    Voice = v1.VoiceOnyx
+	VoiceSage    Voice = v1.VoiceSage
+	VoiceShimmer Voice = v1.VoiceShimmer
+	VoiceVerse   Voice = v1.VoiceVerse
+)
+
+// Modality is an output type of a response.
+type Modality string
+
+// Response modalities.
+const (
+	ModalityText  Modality = "text"
+	ModalityAudio Modality = "audio"
+)
+
+// AudioFormat is the encoding of input or output audio.
+type AudioFormat string
+
+// Audio formats.
+const (
+	AudioFormatPCM16    AudioFormat = "pcm16"     // 16-bit PCM at 24 kHz
+	AudioFormatG711ULaw AudioFormat = "g711_ulaw" // G.711 μ-law at 8 kHz
+	AudioFormatG711ALaw AudioFormat = "g711_alaw" // G.711 A-law at 8 kHz
+)
+
+// TurnDetectionType selects the voice activity detection method.
+type TurnDetectionType string
+
+// Turn detection methods.
+const (
+	TurnDetectionServerVAD   TurnDetectionType = "server_vad"
+	TurnDetectionSemanticVAD TurnDetectionType = "semantic_vad"
+)
+
+// Eagerness controls how quickly semantic VAD ends the user's turn.
+type Eagerness string
+
+// Semantic VAD eagerness levels.
+const (
+	EagernessLow    Eagerness = "low"
+	EagernessMedium Eagerness = "medium"
+	EagernessHigh   Eagerness = "high"
+	EagernessAuto   Eagerness = "auto"
+)
@@ -0,0 +1,130 @@
+package azrealtime
+
+import (
+	"encoding/base64"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStatsInterval is how often Config.StatsHandler is called when
+// Config.StatsInterval is zero.
+const DefaultStatsInterval = 10 * time.Second
+
+// Stats is a point-in-time snapshot of a client's traffic counters.
+// Audio durations assume PCM16 at DefaultSampleRate.
+type Stats struct {
+	EventsReceived  map[string]uint64 // Server events received, keyed by event type
+	EventsSent      uint64            // Client events successfully written to the connection
+	BytesSent       uint64            // Bytes of event JSON written to the connection
+	BytesReceived   uint64            // Bytes of text messages read from the connection
+	AudioInSeconds  float64           // Seconds of input audio appended via AppendPCM16 or AppendG711
+	AudioOutSeconds float64           // Seconds of assistant audio received in response.audio.delta events
+	Reconnects      uint64            // Successful calls to Reconnect
+	SendErrors      uint64            // Failed attempts to write an event to the connection
+	Latency         LatencyStats      // Time to first token, first audio and response.done
+}
+
+// clientStats accumulates the counters behind Client.Stats.
+type clientStats struct {
+	mu             sync.Mutex
+	eventsReceived map[string]uint64
+	eventsSent     uint64
+	bytesSent      uint64
+	bytesReceived  uint64
+	audioInBytes   uint64
+	audioOutBytes  uint64
+	reconnects     uint64
+	sendErrors     uint64
+}
+
+// Stats returns a snapshot of the client's traffic counters.
+// Counters accumulate across reconnects for the lifetime of the client.
+func (c *Client) Stats() Stats {
+	st := &c.stats
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	events := make(map[string]uint64, len(st.eventsReceived))
+	for k, v := range st.eventsReceived {
+		events[k] = v
+	}
+	return Stats{
+		EventsReceived:  events,
+		EventsSent:      st.eventsSent,
+		BytesSent:       st.bytesSent,
+		BytesReceived:   st.bytesReceived,
+		AudioInSeconds:  pcm16Seconds(st.audioInBytes),
+		AudioOutSeconds: pcm16Seconds(st.audioOutBytes),
+		Reconnects:      st.reconnects,
+		SendErrors:      st.sendErrors,
+		Latency:         c.latency.stats(),
+	}
+}
+
+// pcm16Seconds converts a PCM16 byte count at DefaultSampleRate to seconds.
+func pcm16Seconds(n uint64) float64 {
+	return float64(n) / float64(2*DefaultSampleRate)
+}
+
+// base64DecodedLen returns the exact decoded length of a padded standard base64 string.
+func base64DecodedLen(s string) int {
+	n := base64.StdEncoding.DecodedLen(len(s))
+	return n - (len(s) - len(strings.TrimRight(s, "=")))
+}
+
+func (st *clientStats) recordReceived(eventType string, n int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.eventsReceived == nil {
+		st.eventsReceived = make(map[string]uint64)
+	}
+	st.eventsReceived[eventType]++
+	st.bytesReceived += uint64(n)
+}
+
+func (st *clientStats) recordSent(n int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.eventsSent++
+	st.bytesSent += uint64(n)
+}
+
+func (st *clientStats) recordSendError() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sendErrors++
+}
+
+func (st *clientStats) recordAudioIn(n int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.audioInBytes += uint64(n)
+}
+
+func (st *clientStats) recordAudioOut(n int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.audioOutBytes += uint64(n)
+}
+
+func (st *clientStats) recordReconnect() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.reconnects++
+}
+
+// statsLoop periodically hands a Stats snapshot to Config.StatsHandler until the client closes.
+func (c *Client) statsLoop() {
+	t := time.NewTicker(c.cfg.StatsInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.closedCh:
+			c.cfg.StatsHandler(c.Stats())
+			return
+		case <-t.C:
+			c.cfg.StatsHandler(c.Stats())
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestBase64DecodedLen(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 4, 5, 480} {
+		enc := base64.StdEncoding.EncodeToString(make([]byte, n))
+		if got := base64DecodedLen(enc); got != n {
+			t.Errorf("base64DecodedLen(%d bytes) = %d", n, got)
+		}
+	}
+}
+
+func TestClient_Stats(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	mockServer.AddMessage(ResponseAudioDelta{
+		Type:        "response.audio.delta",
+		ResponseID:  "resp_1",
+		DeltaBase64: base64.StdEncoding.EncodeToString(make([]byte, PCM16BytesFor(500, DefaultSampleRate))),
+	})
+
+	config := CreateMockConfig(mockServer.URL())
+	exported := make(chan Stats, 1)
+	config.StatsInterval = 20 * time.Millisecond
+	config.StatsHandler = func(s Stats) {
+		select {
+		case exported <- s:
+		default:
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.AppendPCM16(ctx, make([]byte, PCM16BytesFor(1000, DefaultSampleRate))); err != nil {
+		t.Fatalf("AppendPCM16: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	st := client.Stats()
+	if st.EventsReceived["session.created"] != 1 || st.EventsReceived["response.audio.delta"] != 1 {
+		t.Errorf("unexpected received events: %v", st.EventsReceived)
+	}
+	if st.EventsSent != 1 || st.BytesSent == 0 || st.BytesReceived == 0 {
+		t.Errorf("unexpected byte counters: %+v", st)
+	}
+	if st.AudioInSeconds != 1.0 {
+		t.Errorf("expected 1s of input audio, got %v", st.AudioInSeconds)
+	}
+	if st.AudioOutSeconds != 0.5 {
+		t.Errorf("expected 0.5s of output audio, got %v", st.AudioOutSeconds)
+	}
+
+	select {
+	case <-exported:
+	case <-ctx.Done():
+		t.Fatal("StatsHandler was never called")
+	}
+
+	client.Close()
+	if err := client.InputCommit(ctx); err == nil {
+		t.Fatal("expected send on closed client to fail")
+	}
+	if client.Stats().SendErrors != 1 {
+		t.Errorf("expected 1 send error, got %d", client.Stats().SendErrors)
+	}
+}
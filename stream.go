@@ -0,0 +1,93 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// StreamOptions configures Client.StreamPCM16.
+type StreamOptions struct {
+	// SampleRate of the PCM16 input. Audio at other rates is resampled to
+	// DefaultSampleRate before sending. Default: DefaultSampleRate.
+	SampleRate int
+
+	// ChunkMS is the duration of each append message. Default: DefaultChunkMS.
+	ChunkMS int
+
+	// Speed scales wall-clock pacing: 1.0 streams in real time, 2.0 twice as
+	// fast. Default: 1.0. Ignored when NoPacing is set.
+	Speed float64
+
+	// NoPacing sends chunks as fast as the connection allows.
+	NoPacing bool
+
+	// Commit sends input_audio_buffer.commit once the reader is exhausted, for
+	// sessions without server turn detection.
+	Commit bool
+}
+
+// StreamPCM16 reads 16-bit little-endian mono PCM from r until EOF and appends
+// it to the input audio buffer in ChunkMS chunks, paced at wall-clock speed so
+// the server sees audio as if it came from a live microphone. A trailing odd
+// byte is dropped.
+//
+// It returns nil at EOF, or the first read or send error. Cancel ctx to stop early.
+func (c *Client) StreamPCM16(ctx context.Context, r io.Reader, opts StreamOptions) error {
+	if ctx == nil {
+		return NewSendError("input_audio_buffer.append", "", errors.New("context cannot be nil"))
+	}
+	if r == nil {
+		return NewSendError("input_audio_buffer.append", "", errors.New("reader cannot be nil"))
+	}
+	if opts.ChunkMS <= 0 {
+		opts.ChunkMS = DefaultChunkMS
+	}
+
+	var resampler *Resampler
+	if opts.SampleRate > 0 && opts.SampleRate != DefaultSampleRate {
+		var err error
+		if resampler, err = NewResampler(opts.SampleRate, DefaultSampleRate); err != nil {
+			return err
+		}
+	}
+
+	appender := NewPacedAppender(c, PacedAppenderOptions{
+		MinChunkMS: opts.ChunkMS,
+		MaxChunkMS: opts.ChunkMS,
+		RealTime:   !opts.NoPacing,
+		Speed:      opts.Speed,
+	})
+
+	buf := make([]byte, appender.chunkBytes())
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			pcm := buf[:n]
+			if resampler != nil {
+				pcm = resampler.Process(pcm)
+			}
+			if werr := appender.Write(ctx, pcm); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if resampler != nil {
+		if err := appender.Write(ctx, resampler.Flush()); err != nil {
+			return err
+		}
+	}
+	if err := appender.Flush(ctx); err != nil {
+		return err
+	}
+	if opts.Commit {
+		return c.InputCommit(ctx)
+	}
+	return nil
+}
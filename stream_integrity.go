@@ -0,0 +1,266 @@
+package azrealtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrorCodeStreamIntegrity is the code of the client_error reported when
+// Config.StreamIntegrityChecks finds a missing or out-of-order response event.
+// The ErrorEvent's Cause is a *StreamIntegrityError.
+const ErrorCodeStreamIntegrity = "stream_integrity"
+
+// StreamIntegrityProblem classifies a StreamIntegrityError.
+type StreamIntegrityProblem string
+
+const (
+	// StreamGap means an event was skipped: a delta arrived for an output item
+	// or content part that was never announced, an index jumped ahead, or the
+	// deltas do not add up to the final text of the matching done event.
+	StreamGap StreamIntegrityProblem = "gap"
+	// StreamOutOfOrder means an event arrived after the item, part or response
+	// it belongs to was done, or an index went backwards.
+	StreamOutOfOrder StreamIntegrityProblem = "out_of_order"
+)
+
+// StreamIntegrityError describes the first integrity problem found in a
+// response's event stream. Text or audio assembled from that response's
+// deltas may be incomplete.
+type StreamIntegrityError struct {
+	Problem      StreamIntegrityProblem
+	EventType    string // Type of the event that revealed the problem
+	ResponseID   string
+	ItemID       string
+	OutputIndex  int
+	ContentIndex int
+	Detail       string
+}
+
+func (e *StreamIntegrityError) Error() string {
+	return fmt.Sprintf("azrealtime: stream %s in response %s at %s (output %d, content %d): %s",
+		e.Problem, e.ResponseID, e.EventType, e.OutputIndex, e.ContentIndex, e.Detail)
+}
+
+// streamEvent holds the fields of response events used for integrity checks.
+type streamEvent struct {
+	ResponseID string `json:"response_id"`
+	Response   struct {
+		ID string `json:"id"`
+	} `json:"response"`
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	Delta        string `json:"delta"`
+	Text         string `json:"text"`
+	Transcript   string `json:"transcript"`
+	Arguments    string `json:"arguments"`
+}
+
+// partKey identifies a content part; function call arguments use content -1.
+type partKey struct{ output, content int }
+
+// responseStream is the observed structure of one in-progress response.
+type responseStream struct {
+	outputs     int                          // Output items announced so far
+	doneOutputs map[int]bool                 // Output items that are done
+	parts       map[int]int                  // Content parts announced per output item
+	doneParts   map[partKey]bool             // Content parts that are done
+	text        map[partKey]*strings.Builder // Text, transcript and argument deltas
+	failed      bool                         // A problem was already reported
+}
+
+// maxFinishedStreams bounds how many completed response IDs are remembered to
+// detect events arriving after response.done.
+const maxFinishedStreams = 64
+
+// streamIntegrity checks the ordering of response events for
+// Config.StreamIntegrityChecks.
+type streamIntegrity struct {
+	mu        sync.Mutex
+	responses map[string]*responseStream
+	finished  []string
+}
+
+// observe checks one inbound event and returns the first problem it reveals
+// for its response, if any.
+func (s *streamIntegrity) observe(typ string, raw []byte) *StreamIntegrityError {
+	switch typ {
+	case "response.created", "response.done",
+		"response.output_item.added", "response.output_item.done",
+		"response.content_part.added", "response.content_part.done",
+		"response.text.delta", "response.text.done",
+		"response.audio.delta", "response.audio.done",
+		"response.audio_transcript.delta", "response.audio_transcript.done",
+		"response.function_call_arguments.delta", "response.function_call_arguments.done":
+	default:
+		return nil
+	}
+	var e streamEvent
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil
+	}
+	id := e.ResponseID
+	if id == "" {
+		id = e.Response.ID
+	}
+	if id == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch typ {
+	case "response.created":
+		if s.responses == nil {
+			s.responses = make(map[string]*responseStream)
+		}
+		s.responses[id] = &responseStream{
+			doneOutputs: make(map[int]bool),
+			parts:       make(map[int]int),
+			doneParts:   make(map[partKey]bool),
+			text:        make(map[partKey]*strings.Builder),
+		}
+		return nil
+	case "response.done":
+		delete(s.responses, id)
+		s.finished = append(s.finished, id)
+		if len(s.finished) > maxFinishedStreams {
+			s.finished = s.finished[1:]
+		}
+		return nil
+	}
+
+	r, ok := s.responses[id]
+	if !ok {
+		for i, done := range s.finished {
+			if done == id {
+				// Report once per response
+				s.finished = append(s.finished[:i], s.finished[i+1:]...)
+				return &StreamIntegrityError{Problem: StreamOutOfOrder, EventType: typ, ResponseID: id, ItemID: e.ItemID,
+					OutputIndex: e.OutputIndex, ContentIndex: e.ContentIndex, Detail: "event after response.done"}
+			}
+		}
+		return nil // Response started before checking began
+	}
+	if r.failed {
+		return nil
+	}
+	problem := func(p StreamIntegrityProblem, format string, args ...any) *StreamIntegrityError {
+		r.failed = true
+		return &StreamIntegrityError{Problem: p, EventType: typ, ResponseID: id, ItemID: e.ItemID,
+			OutputIndex: e.OutputIndex, ContentIndex: e.ContentIndex, Detail: fmt.Sprintf(format, args...)}
+	}
+	// checkOutput verifies that the event's output item is announced and open.
+	checkOutput := func() *StreamIntegrityError {
+		if e.OutputIndex >= r.outputs {
+			return problem(StreamGap, "output item %d was never added", e.OutputIndex)
+		}
+		if r.doneOutputs[e.OutputIndex] {
+			return problem(StreamOutOfOrder, "output item %d is already done", e.OutputIndex)
+		}
+		return nil
+	}
+	// checkPart verifies that the event's content part is announced and open.
+	checkPart := func() *StreamIntegrityError {
+		if err := checkOutput(); err != nil {
+			return err
+		}
+		if e.ContentIndex >= r.parts[e.OutputIndex] {
+			return problem(StreamGap, "content part %d was never added", e.ContentIndex)
+		}
+		if r.doneParts[partKey{e.OutputIndex, e.ContentIndex}] {
+			return problem(StreamOutOfOrder, "content part %d is already done", e.ContentIndex)
+		}
+		return nil
+	}
+	// checkText compares the assembled deltas of key with the final text.
+	checkText := func(key partKey, final string) *StreamIntegrityError {
+		got := ""
+		if b := r.text[key]; b != nil {
+			got = b.String()
+		}
+		delete(r.text, key)
+		if final != "" && got != "" && got != final {
+			return problem(StreamGap, "deltas assemble %d bytes, done event has %d", len(got), len(final))
+		}
+		return nil
+	}
+	appendText := func(key partKey, delta string) {
+		b := r.text[key]
+		if b == nil {
+			b = &strings.Builder{}
+			r.text[key] = b
+		}
+		b.WriteString(delta)
+	}
+
+	key := partKey{e.OutputIndex, e.ContentIndex}
+	switch typ {
+	case "response.output_item.added":
+		switch {
+		case e.OutputIndex > r.outputs:
+			return problem(StreamGap, "output item %d added before item %d", e.OutputIndex, r.outputs)
+		case e.OutputIndex < r.outputs:
+			return problem(StreamOutOfOrder, "output item %d added again", e.OutputIndex)
+		}
+		r.outputs++
+	case "response.output_item.done":
+		if err := checkOutput(); err != nil {
+			return err
+		}
+		r.doneOutputs[e.OutputIndex] = true
+	case "response.content_part.added":
+		if err := checkOutput(); err != nil {
+			return err
+		}
+		switch next := r.parts[e.OutputIndex]; {
+		case e.ContentIndex > next:
+			return problem(StreamGap, "content part %d added before part %d", e.ContentIndex, next)
+		case e.ContentIndex < next:
+			return problem(StreamOutOfOrder, "content part %d added again", e.ContentIndex)
+		}
+		r.parts[e.OutputIndex]++
+	case "response.content_part.done":
+		if err := checkPart(); err != nil {
+			return err
+		}
+		r.doneParts[key] = true
+	case "response.audio.delta", "response.audio.done":
+		return checkPart()
+	case "response.text.delta", "response.audio_transcript.delta":
+		if err := checkPart(); err != nil {
+			return err
+		}
+		appendText(key, e.Delta)
+	case "response.text.done":
+		if err := checkPart(); err != nil {
+			return err
+		}
+		return checkText(key, e.Text)
+	case "response.audio_transcript.done":
+		if err := checkPart(); err != nil {
+			return err
+		}
+		return checkText(key, e.Transcript)
+	case "response.function_call_arguments.delta":
+		if err := checkOutput(); err != nil {
+			return err
+		}
+		appendText(partKey{e.OutputIndex, -1}, e.Delta)
+	case "response.function_call_arguments.done":
+		if err := checkOutput(); err != nil {
+			return err
+		}
+		return checkText(partKey{e.OutputIndex, -1}, e.Arguments)
+	}
+	return nil
+}
+
+// reset forgets in-progress responses, which cannot complete on a new connection.
+func (s *streamIntegrity) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = nil
+}
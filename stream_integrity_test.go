@@ -0,0 +1,112 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStreamIntegrity(t *testing.T) {
+	const id = "resp_1"
+	created := map[string]any{"type": "response.created", "response": map[string]any{"id": id}}
+	itemAdded := func(o int) map[string]any {
+		return map[string]any{"type": "response.output_item.added", "response_id": id, "output_index": o}
+	}
+	partAdded := func(o, c int) map[string]any {
+		return map[string]any{"type": "response.content_part.added", "response_id": id, "output_index": o, "content_index": c}
+	}
+	partDone := func(o, c int) map[string]any {
+		return map[string]any{"type": "response.content_part.done", "response_id": id, "output_index": o, "content_index": c}
+	}
+	textDelta := func(o, c int, d string) map[string]any {
+		return map[string]any{"type": "response.text.delta", "response_id": id, "output_index": o, "content_index": c, "delta": d}
+	}
+	textDone := func(text string) map[string]any {
+		return map[string]any{"type": "response.text.done", "response_id": id, "text": text}
+	}
+	done := map[string]any{"type": "response.done", "response": map[string]any{"id": id}}
+
+	tests := []struct {
+		name    string
+		events  []map[string]any
+		problem StreamIntegrityProblem // Empty for a clean stream
+	}{
+		{"complete", []map[string]any{created, itemAdded(0), partAdded(0, 0), textDelta(0, 0, "Hel"), textDelta(0, 0, "lo"), textDone("Hello"), partDone(0, 0), done}, ""},
+		{"missing delta", []map[string]any{created, itemAdded(0), partAdded(0, 0), textDelta(0, 0, "Hel"), textDone("Hello")}, StreamGap},
+		{"missing part", []map[string]any{created, itemAdded(0), textDelta(0, 0, "Hi")}, StreamGap},
+		{"skipped item", []map[string]any{created, itemAdded(0), itemAdded(2)}, StreamGap},
+		{"delta after part done", []map[string]any{created, itemAdded(0), partAdded(0, 0), partDone(0, 0), textDelta(0, 0, "late")}, StreamOutOfOrder},
+		{"repeated part", []map[string]any{created, itemAdded(0), partAdded(0, 0), partAdded(0, 0)}, StreamOutOfOrder},
+		{"delta after response done", []map[string]any{created, done, textDelta(0, 0, "late")}, StreamOutOfOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s streamIntegrity
+			var found []*StreamIntegrityError
+			for _, e := range tt.events {
+				raw, _ := json.Marshal(e)
+				if err := s.observe(e["type"].(string), raw); err != nil {
+					found = append(found, err)
+				}
+			}
+			switch {
+			case tt.problem == "" && len(found) > 0:
+				t.Errorf("unexpected problems: %v", found)
+			case tt.problem != "" && len(found) != 1:
+				t.Errorf("found %d problems, want 1: %v", len(found), found)
+			case tt.problem != "" && found[0].Problem != tt.problem:
+				t.Errorf("problem = %v, want %v", found[0], tt.problem)
+			}
+		})
+	}
+}
+
+func TestClient_StreamIntegrityChecks(t *testing.T) {
+	// GA endpoints spell the text events response.output_text.*
+	for _, tt := range []struct{ name, delta, done string }{
+		{"preview", "response.text.delta", "response.text.done"},
+		{"ga", "response.output_text.delta", "response.output_text.done"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			const id = "resp_gap"
+			mockServer := NewMockServer(t)
+			defer mockServer.Close()
+			mockServer.Profile = &MockProfile{Response: []MockStep{
+				{Event: ResponseCreated{Type: "response.created", Response: ResponseObject{ID: id}}},
+				{Event: ResponseOutputItemAdded{Type: "response.output_item.added", ResponseID: id}},
+				{Event: ResponseContentPartAdded{Type: "response.content_part.added", ResponseID: id}},
+				{Event: ResponseTextDelta{Type: tt.delta, ResponseID: id, Delta: "Hello"}},
+				{Event: ResponseTextDone{Type: tt.done, ResponseID: id, Text: "Hello world"}},
+				{Event: ResponseDone{Type: "response.done", Response: ResponseObject{ID: id, Status: "completed"}}},
+			}}
+			cfg := CreateMockConfig(mockServer.URL())
+			cfg.StreamIntegrityChecks = true
+			client, err := Dial(context.Background(), cfg)
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			defer client.Close()
+
+			errs := make(chan ErrorEvent, 4)
+			client.OnError(func(e ErrorEvent) { errs <- e })
+			if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{Modalities: []string{"text"}}); err != nil {
+				t.Fatalf("CreateResponse: %v", err)
+			}
+
+			select {
+			case e := <-errs:
+				var sie *StreamIntegrityError
+				if e.Error.Code != ErrorCodeStreamIntegrity || e.Class() != ErrorClassClient || !errors.As(e.Cause, &sie) {
+					t.Fatalf("error event = %+v", e)
+				}
+				if sie.Problem != StreamGap || sie.ResponseID != id || sie.EventType != tt.done {
+					t.Errorf("StreamIntegrityError = %+v", sie)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no stream integrity error reported")
+			}
+		})
+	}
+}
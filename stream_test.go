@@ -0,0 +1,181 @@
+package azrealtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_StreamPCM16(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     StreamOptions
+		min, max time.Duration
+	}{
+		// 400ms of audio in 100ms chunks: the last chunk is due 300ms in
+		{"real time", StreamOptions{ChunkMS: 100}, 300 * time.Millisecond, 2 * time.Second},
+		{"four times real time", StreamOptions{ChunkMS: 100, Speed: 4}, 75 * time.Millisecond, 250 * time.Millisecond},
+		{"no pacing", StreamOptions{ChunkMS: 100, NoPacing: true}, 0, 75 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := NewMockServer(t)
+			defer mockServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			defer client.Close()
+
+			start := time.Now()
+			if err := client.StreamPCM16(ctx, bytes.NewReader(make([]byte, PCM16BytesFor(400, DefaultSampleRate))), tt.opts); err != nil {
+				t.Fatalf("StreamPCM16: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.min || elapsed > tt.max {
+				t.Errorf("streaming took %v, want between %v and %v", elapsed, tt.min, tt.max)
+			}
+			if got := client.Stats().AudioInSeconds; got < 0.399 || got > 0.401 {
+				t.Errorf("expected 0.4s of audio sent, got %v", got)
+			}
+		})
+	}
+}
+
+func TestClient_StreamPCM16_ChunksAndCommit(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	var mu sync.Mutex
+	var sent []string
+	client.OnRawEvent(func(dir EventDirection, data []byte) {
+		if dir != DirectionOutbound {
+			return
+		}
+		var env envelope
+		_ = json.Unmarshal(data, &env)
+		mu.Lock()
+		sent = append(sent, env.Type)
+		mu.Unlock()
+	})
+
+	// 250ms plus a stray odd byte: two 100ms chunks, a 50ms tail, then the commit
+	pcm := make([]byte, PCM16BytesFor(250, DefaultSampleRate)+1)
+	if err := client.StreamPCM16(ctx, bytes.NewReader(pcm), StreamOptions{ChunkMS: 100, NoPacing: true, Commit: true}); err != nil {
+		t.Fatalf("StreamPCM16: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"input_audio_buffer.append", "input_audio_buffer.append", "input_audio_buffer.append", "input_audio_buffer.commit"}
+	if len(sent) != len(want) {
+		t.Fatalf("sent %v, want %v", sent, want)
+	}
+	for i := range want {
+		if sent[i] != want[i] {
+			t.Errorf("sent[%d] = %q, want %q", i, sent[i], want[i])
+		}
+	}
+}
+
+func TestClient_StreamPCM16_Canceled(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	client, err := Dial(context.Background(), CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	err = client.StreamPCM16(ctx, bytes.NewReader(make([]byte, PCM16BytesFor(2000, DefaultSampleRate))), StreamOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClient_StreamPCM16_Resamples(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	// 300ms of 16 kHz audio arrives as 300ms of 24 kHz audio
+	pcm := make([]byte, PCM16BytesFor(300, 16000))
+	if err := client.StreamPCM16(ctx, bytes.NewReader(pcm), StreamOptions{SampleRate: 16000, NoPacing: true}); err != nil {
+		t.Fatalf("StreamPCM16: %v", err)
+	}
+	if got := client.Stats().AudioInSeconds; got < 0.299 || got > 0.301 {
+		t.Errorf("expected 0.3s of 24 kHz audio sent, got %v", got)
+	}
+}
+
+func TestClient_AppendPCM16From(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := Dial(ctx, CreateMockConfig(mockServer.URL()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	var mu sync.Mutex
+	var appends int
+	client.OnRawEvent(func(dir EventDirection, data []byte) {
+		var env envelope
+		_ = json.Unmarshal(data, &env)
+		if dir == DirectionOutbound && env.Type == "input_audio_buffer.append" {
+			mu.Lock()
+			appends++
+			mu.Unlock()
+		}
+	})
+
+	// Two and a half chunks plus a stray odd byte
+	size := 2*MaxAppendBytes + MaxAppendBytes/2
+	n, err := client.AppendPCM16From(ctx, bytes.NewReader(make([]byte, size+1)))
+	if err != nil {
+		t.Fatalf("AppendPCM16From: %v", err)
+	}
+	if n != int64(size) {
+		t.Errorf("sent %d bytes, want %d", n, size)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if appends != 3 {
+		t.Errorf("sent %d append messages, want 3", appends)
+	}
+
+	if _, err := client.AppendPCM16From(ctx, nil); err == nil {
+		t.Error("expected error for nil reader")
+	}
+}
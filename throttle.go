@@ -0,0 +1,129 @@
+package azrealtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimitMode controls how the client reacts when the server-reported rate
+// limits (rate_limits.updated) are exhausted.
+type RateLimitMode int
+
+const (
+	// RateLimitIgnore sends requests regardless of reported limits (the default).
+	RateLimitIgnore RateLimitMode = iota
+	// RateLimitWait blocks CreateResponse and AppendPCM16 until the limit resets or the context is done.
+	RateLimitWait
+	// RateLimitFail makes CreateResponse and AppendPCM16 fail immediately with a RateLimitError.
+	RateLimitFail
+)
+
+// Rate limit names reported by the service.
+const (
+	RateLimitRequests = "requests"
+	RateLimitTokens   = "tokens"
+)
+
+// defaultRateLimitReset is assumed when an exhausted limit reports no reset time.
+const defaultRateLimitReset = time.Second
+
+// RateLimitError is returned (wrapped in a SendError) when a request is refused
+// locally because a reported rate limit is exhausted. It matches ErrRateLimited.
+type RateLimitError struct {
+	Limit      string        // Name of the exhausted limit (e.g. "requests")
+	RetryAfter time.Duration // Time until the limit is expected to reset
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("azrealtime: rate limit %q exhausted, retry after %v", e.Limit, e.RetryAfter)
+}
+
+// Is implements error matching for RateLimitError.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// rateBucket is the local view of one server rate limit.
+type rateBucket struct {
+	remaining int
+	resetAt   time.Time
+}
+
+// rateLimiter tracks remaining quota from rate_limits.updated events, consuming
+// it locally between updates.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+	updated chan struct{} // Closed and replaced on every update to wake waiters
+}
+
+// update replaces the buckets with the limits reported by the server.
+func (l *rateLimiter) update(rl RateLimitsUpdated, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buckets = make(map[string]*rateBucket, len(rl.RateLimits))
+	for _, r := range rl.RateLimits {
+		reset := r.resetIn()
+		if reset <= 0 && r.Remaining <= 0 {
+			reset = defaultRateLimitReset
+		}
+		l.buckets[r.Name] = &rateBucket{remaining: r.Remaining, resetAt: now.Add(reset)}
+	}
+	if l.updated != nil {
+		close(l.updated)
+		l.updated = nil
+	}
+}
+
+// acquire checks the named limits and, if none is exhausted, consumes one unit
+// of consume (if non-empty). Otherwise it returns the exhausted limit's error
+// and a channel that is closed on the next update.
+func (l *rateLimiter) acquire(now time.Time, consume string, limits ...string) (*RateLimitError, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, name := range limits {
+		b := l.buckets[name]
+		if b == nil || b.remaining > 0 || !now.Before(b.resetAt) {
+			continue
+		}
+		if l.updated == nil {
+			l.updated = make(chan struct{})
+		}
+		return &RateLimitError{Limit: name, RetryAfter: b.resetAt.Sub(now)}, l.updated
+	}
+	if b := l.buckets[consume]; b != nil && now.Before(b.resetAt) {
+		b.remaining--
+	}
+	return nil, nil
+}
+
+// throttle applies the configured RateLimitMode before sending eventType. It
+// consumes one unit of consume and requires every limit in limits to have quota.
+func (c *Client) throttle(ctx context.Context, eventType, consume string, limits ...string) error {
+	if c.cfg.RateLimitMode == RateLimitIgnore {
+		return nil
+	}
+	for {
+		rlErr, updated := c.limiter.acquire(time.Now(), consume, limits...)
+		if rlErr == nil {
+			return nil
+		}
+		if c.cfg.RateLimitMode == RateLimitFail {
+			return NewSendError(eventType, "", rlErr)
+		}
+		t := time.NewTimer(rlErr.RetryAfter)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return NewSendError(eventType, "", ctx.Err())
+		case <-c.closedCh:
+			t.Stop()
+			return ErrClosed
+		case <-updated:
+			t.Stop()
+		case <-t.C:
+		}
+	}
+}
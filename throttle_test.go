@@ -0,0 +1,139 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Acquire(t *testing.T) {
+	now := time.Now()
+	var l rateLimiter
+
+	// No limits known yet: everything passes
+	if err, _ := l.acquire(now, RateLimitRequests, RateLimitRequests); err != nil {
+		t.Fatalf("expected no limit before first update, got %v", err)
+	}
+
+	l.update(RateLimitsUpdated{RateLimits: []RateLimit{
+		{Name: RateLimitRequests, Limit: 100, Remaining: 1, ResetSeconds: 2},
+		{Name: RateLimitTokens, Limit: 1000, Remaining: 500, Reset: 500 * time.Millisecond},
+	}}, now)
+
+	if err, _ := l.acquire(now, RateLimitRequests, RateLimitRequests, RateLimitTokens); err != nil {
+		t.Fatalf("expected first request to pass, got %v", err)
+	}
+	err, updated := l.acquire(now, RateLimitRequests, RateLimitRequests, RateLimitTokens)
+	if err == nil || err.Limit != RateLimitRequests || err.RetryAfter != 2*time.Second {
+		t.Fatalf("expected requests limit with 2s retry, got %+v", err)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Error("RateLimitError should match ErrRateLimited")
+	}
+
+	// Audio only needs tokens
+	if err, _ := l.acquire(now, "", RateLimitTokens); err != nil {
+		t.Errorf("expected tokens to be available, got %v", err)
+	}
+
+	// After the reset time the limit no longer applies
+	if err, _ := l.acquire(now.Add(3*time.Second), RateLimitRequests, RateLimitRequests); err != nil {
+		t.Errorf("expected limit to reset, got %v", err)
+	}
+
+	l.update(RateLimitsUpdated{}, now)
+	select {
+	case <-updated:
+	default:
+		t.Error("expected waiters to be woken by an update")
+	}
+}
+
+func TestRateLimit_JSON(t *testing.T) {
+	var e RateLimitsUpdated
+	data := `{"type":"rate_limits.updated","rate_limits":[{"name":"requests","limit":100,"remaining":5,"reset_seconds":0.5},{"name":"tokens","limit":1000,"remaining":10,"reset_seconds":60}]}`
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if r := e.RateLimits[0]; r.ResetSeconds != 1 || r.Reset != 500*time.Millisecond {
+		t.Errorf("fractional reset = %ds / %v, want 1s / 500ms", r.ResetSeconds, r.Reset)
+	}
+	if r := e.RateLimits[1]; r.ResetSeconds != 60 || r.Reset != time.Minute || r.Remaining != 10 {
+		t.Errorf("unexpected limit %+v", r)
+	}
+
+	out, err := json.Marshal(RateLimit{Name: "requests", ResetSeconds: 2})
+	if err != nil || string(out) != `{"name":"requests","limit":0,"remaining":0,"reset_seconds":2}` {
+		t.Errorf("marshal = %s, %v", out, err)
+	}
+}
+
+func TestClient_RateLimitModes(t *testing.T) {
+	exhausted := map[string]any{
+		"type": "rate_limits.updated",
+		"rate_limits": []map[string]any{
+			{"name": "requests", "limit": 10, "remaining": 0, "reset_seconds": 0.2},
+		},
+	}
+
+	dial := func(t *testing.T, mode RateLimitMode) *Client {
+		mockServer := NewMockServer(t)
+		t.Cleanup(mockServer.Close)
+		mockServer.AddMessage(exhausted)
+
+		cfg := CreateMockConfig(mockServer.URL())
+		cfg.RateLimitMode = mode
+		client, err := Dial(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			if _, ok := client.LastRateLimits(); ok {
+				return client
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("rate_limits.updated not received")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		client := dial(t, RateLimitFail)
+		_, err := client.CreateResponse(context.Background(), CreateResponseOptions{})
+		if !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected ErrRateLimited, got %v", err)
+		}
+		var rlErr *RateLimitError
+		if !errors.As(err, &rlErr) || rlErr.Limit != RateLimitRequests {
+			t.Errorf("expected RateLimitError for requests, got %v", err)
+		}
+		// Audio is not gated by the requests limit
+		if err := client.AppendPCM16(context.Background(), make([]byte, 4800)); err != nil {
+			t.Errorf("expected audio append to pass, got %v", err)
+		}
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		client := dial(t, RateLimitWait)
+		start := time.Now()
+		if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{}); err != nil {
+			t.Fatalf("CreateResponse: %v", err)
+		}
+		if waited := time.Since(start); waited < 100*time.Millisecond {
+			t.Errorf("expected CreateResponse to wait for the reset, returned after %v", waited)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		client.limiter.update(RateLimitsUpdated{RateLimits: []RateLimit{{Name: RateLimitRequests, Remaining: 0, ResetSeconds: 5}}}, time.Now())
+		if _, err := client.CreateResponse(ctx, CreateResponseOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context deadline while waiting, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Tool is a typed function tool definition. It marshals to the wire format
+// expected in Session.Tools, so it can be used there directly:
+//
+//	session.Tools = []any{tool}
+type Tool struct {
+	Name        string         // Function name the model calls
+	Description string         // What the tool does and when to use it
+	Parameters  map[string]any // JSON schema of the arguments object
+	Handler     ToolHandler    // Executes calls; not sent to the server
+}
+
+// Definition returns the tool definition for Session.Tools.
+func (t Tool) Definition() map[string]any {
+	params := t.Parameters
+	if params == nil {
+		params = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	return map[string]any{
+		"type":        "function",
+		"name":        t.Name,
+		"description": t.Description,
+		"parameters":  params,
+	}
+}
+
+// MarshalJSON encodes the tool as its Definition.
+func (t Tool) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Definition())
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// ToolFromFunc builds a Tool from a Go function of the form
+//
+//	func(ctx context.Context, args T) (R, error)
+//	func(args T) (R, error)
+//
+// where T is a struct (or pointer to one) describing the arguments. The
+// parameter schema is generated from T with SchemaFor, and the handler decodes
+// the call's JSON arguments into T and encodes R as JSON (a string R is
+// returned as is).
+//
+// The tool name is the function's name in snake_case (lookupOrder becomes
+// lookup_order); function literals have no usable name, so set Name yourself.
+// Description is left empty for the caller to fill in.
+func ToolFromFunc(fn any) (Tool, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return Tool{}, fmt.Errorf("azrealtime: ToolFromFunc needs a function, got %T", fn)
+	}
+	ft := v.Type()
+	withCtx := ft.NumIn() == 2 && ft.In(0) == contextType
+	if !(ft.NumIn() == 1 || withCtx) || ft.NumOut() != 2 || ft.Out(1) != errorType {
+		return Tool{}, fmt.Errorf("azrealtime: ToolFromFunc: %v is not func([context.Context,] T) (R, error)", ft)
+	}
+	argType := ft.In(ft.NumIn() - 1)
+	schema, err := schemaForType(argType)
+	if err != nil {
+		return Tool{}, err
+	}
+	if schema["type"] != "object" {
+		return Tool{}, fmt.Errorf("azrealtime: ToolFromFunc: arguments must be a struct, got %v", argType)
+	}
+
+	handler := func(ctx context.Context, arguments string) (string, error) {
+		arg := reflect.New(argType)
+		if strings.TrimSpace(arguments) != "" {
+			if err := json.Unmarshal([]byte(arguments), arg.Interface()); err != nil {
+				return "", fmt.Errorf("decode arguments: %w", err)
+			}
+		}
+		in := []reflect.Value{arg.Elem()}
+		if withCtx {
+			in = append([]reflect.Value{reflect.ValueOf(ctx)}, in...)
+		}
+		out := v.Call(in)
+		if err, _ := out[1].Interface().(error); err != nil {
+			return "", err
+		}
+		if s, ok := out[0].Interface().(string); ok {
+			return s, nil
+		}
+		b, err := json.Marshal(out[0].Interface())
+		if err != nil {
+			return "", fmt.Errorf("encode result: %w", err)
+		}
+		return string(b), nil
+	}
+	return Tool{Name: funcToolName(v), Parameters: schema, Handler: handler}, nil
+}
+
+// funcToolName derives a snake_case tool name from a named function, or
+// returns "" for function literals.
+func funcToolName(v reflect.Value) string {
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+	name := f.Name()
+	name = name[strings.LastIndex(name, ".")+1:]
+	name = strings.TrimSuffix(name, "-fm") // Method values
+	if strings.HasPrefix(name, "func") && strings.Trim(name[4:], "0123456789") == "" {
+		return ""
+	}
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SchemaFor returns the JSON schema of v's type, as used for tool parameters.
+//
+// Struct fields use their json names and are required unless tagged omitempty
+// or a pointer; fields tagged json:"-" are skipped. Two extra tags document
+// fields for the model:
+//
+//	type LookupOrder struct {
+//		OrderID string `json:"order_id" description:"Order identifier"`
+//		Status  string `json:"status,omitempty" enum:"open,shipped,cancelled"`
+//	}
+//
+// Supported kinds are bool, integers, floats, strings, time.Time, slices and
+// arrays, maps with string keys, structs and pointers to these.
+func SchemaFor(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, errors.New("azrealtime: SchemaFor(nil)")
+	}
+	return schemaForType(reflect.TypeOf(v))
+}
+
+// maxSchemaDepth bounds nesting so recursive types terminate.
+const maxSchemaDepth = 16
+
+func schemaForType(t reflect.Type) (map[string]any, error) {
+	return schemaFor(t, 0)
+}
+
+func schemaFor(t reflect.Type, depth int) (map[string]any, error) {
+	if depth > maxSchemaDepth {
+		return nil, fmt.Errorf("azrealtime: schema for %v nests too deeply (recursive type?)", t)
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := schemaFor(t.Elem(), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("azrealtime: schema for %v: map keys must be strings", t)
+		}
+		values, err := schemaFor(t.Elem(), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		return structSchema(t, depth)
+	case reflect.Interface:
+		return map[string]any{}, nil // Any JSON value
+	default:
+		return nil, fmt.Errorf("azrealtime: schema for %v: unsupported kind %v", t, t.Kind())
+	}
+}
+
+func structSchema(t reflect.Type, depth int) (map[string]any, error) {
+	props := map[string]any{}
+	var required []string
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || len(f.Index) > 1 && !isPromoted(t, f) {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			continue // Its fields are visited as promoted fields
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s, err := schemaFor(f.Type, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if d := f.Tag.Get("description"); d != "" {
+			s["description"] = d
+		}
+		if e := f.Tag.Get("enum"); e != "" {
+			s["enum"] = strings.Split(e, ",")
+		}
+		props[name] = s
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// isPromoted reports whether f, a field reached through embedding, is promoted
+// into t's JSON object: every embedded struct on its path must be untagged.
+func isPromoted(t reflect.Type, f reflect.StructField) bool {
+	for i := range f.Index[:len(f.Index)-1] {
+		ef := t.FieldByIndex(f.Index[:i+1])
+		if name, _, _ := strings.Cut(ef.Tag.Get("json"), ","); name != "" || !ef.Anonymous {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package azrealtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Limits applied by CommandTool when the corresponding option is zero.
+const (
+	DefaultToolTimeout        = 30 * time.Second
+	DefaultToolMaxOutputBytes = 64 * 1024
+)
+
+// ToolHandler executes a function call. It receives the call's raw JSON
+// arguments and returns the output sent back to the model.
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// CommandToolOptions configures a tool backed by a local command.
+type CommandToolOptions struct {
+	// Timeout bounds each invocation. Default: DefaultToolTimeout.
+	Timeout time.Duration
+
+	// Dir is the working directory of the command. Default: the current directory.
+	Dir string
+
+	// Env holds extra "KEY=value" environment entries for the command.
+	Env []string
+
+	// CleanEnv starts the command with only PATH and Env instead of inheriting
+	// the caller's environment, so secrets such as API keys are not exposed to it.
+	CleanEnv bool
+
+	// MaxOutputBytes caps the stdout accepted from the command; larger output
+	// fails the call. Default: DefaultToolMaxOutputBytes.
+	MaxOutputBytes int
+}
+
+// CommandTool returns a ToolHandler that runs command (program and arguments)
+// for each call. The JSON arguments are written to the command's stdin and also
+// set in the AZREALTIME_TOOL_ARGS environment variable; its trimmed stdout is
+// the tool result. A non-zero exit status fails the call with the command's stderr.
+func CommandTool(command []string, opts CommandToolOptions) ToolHandler {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultToolTimeout
+	}
+	if opts.MaxOutputBytes <= 0 {
+		opts.MaxOutputBytes = DefaultToolMaxOutputBytes
+	}
+	command = append([]string(nil), command...)
+
+	return func(ctx context.Context, arguments string) (string, error) {
+		if len(command) == 0 {
+			return "", errors.New("tool command is empty")
+		}
+		ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Dir = opts.Dir
+		cmd.Stdin = strings.NewReader(arguments)
+		cmd.WaitDelay = time.Second // don't hang on pipes held open by orphaned children
+
+		env := os.Environ()
+		if opts.CleanEnv {
+			env = []string{"PATH=" + os.Getenv("PATH")}
+		}
+		cmd.Env = append(append(env, opts.Env...), "AZREALTIME_TOOL_ARGS="+arguments)
+
+		stdout := &limitedBuffer{max: opts.MaxOutputBytes}
+		stderr := &limitedBuffer{max: 4096}
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("tool command %s timed out after %v", command[0], opts.Timeout)
+		}
+		if err != nil {
+			return "", fmt.Errorf("tool command %s: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+		}
+		if stdout.overflow {
+			return "", fmt.Errorf("tool command %s: output exceeds %d bytes", command[0], opts.MaxOutputBytes)
+		}
+		return strings.TrimSpace(stdout.String()), nil
+	}
+}
+
+// limitedBuffer keeps the first max bytes written and discards the rest.
+// It deliberately doesn't embed bytes.Buffer, whose ReadFrom would bypass the limit.
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	max      int
+	overflow bool
+}
+
+// Write implements io.Writer, always reporting success so the command isn't killed by EPIPE.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); len(p) > room {
+		b.overflow = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// String returns the retained output.
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,80 @@
+package azrealtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommandTool(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		command []string
+		opts    CommandToolOptions
+		args    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "args on stdin",
+			command: []string{"cat"},
+			args:    `{"city":"Oslo"}`,
+			want:    `{"city":"Oslo"}`,
+		},
+		{
+			name:    "args in environment",
+			command: []string{"sh", "-c", `printf '%s' "$AZREALTIME_TOOL_ARGS"`},
+			args:    `{"n":1}`,
+			want:    `{"n":1}`,
+		},
+		{
+			name:    "extra env with clean environment",
+			command: []string{"sh", "-c", `echo "$GREETING-$HOME"`},
+			opts:    CommandToolOptions{CleanEnv: true, Env: []string{"GREETING=hi"}},
+			want:    "hi-",
+		},
+		{
+			name:    "non-zero exit reports stderr",
+			command: []string{"sh", "-c", "echo bad input >&2; exit 2"},
+			wantErr: "bad input",
+		},
+		{
+			name:    "timeout",
+			command: []string{"sleep", "5"},
+			opts:    CommandToolOptions{Timeout: 50 * time.Millisecond},
+			wantErr: "timed out",
+		},
+		{
+			name:    "output limit",
+			command: []string{"sh", "-c", "head -c 100 /dev/zero"},
+			opts:    CommandToolOptions{MaxOutputBytes: 10},
+			wantErr: "exceeds 10 bytes",
+		},
+		{
+			name:    "empty command",
+			wantErr: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", "/home/test")
+			got, err := CommandTool(tt.command, tt.opts)(ctx, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
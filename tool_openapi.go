@@ -0,0 +1,431 @@
+package azrealtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Limits applied by ToolsFromOpenAPI when the corresponding option is zero.
+const (
+	DefaultHTTPToolTimeout          = 30 * time.Second
+	DefaultHTTPToolMaxResponseBytes = 64 * 1024
+)
+
+// maxSchemaRefDepth bounds $ref resolution so recursive schemas terminate.
+const maxSchemaRefDepth = 8
+
+// openAPIMethods lists the operation keys of an OpenAPI path item, in output order.
+var openAPIMethods = []string{"get", "put", "post", "delete", "patch", "head", "options"}
+
+// invalidToolNameChars matches characters not allowed in function tool names.
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// serverVarPattern matches a {variable} in a server URL.
+var serverVarPattern = regexp.MustCompile(`\{[^{}]+\}`)
+
+// HTTPTool is a function tool that calls a REST operation.
+type HTTPTool struct {
+	Name        string         // Tool name (the operationId, or method and path)
+	Description string         // Operation summary or description
+	Parameters  map[string]any // JSON schema of the tool arguments
+	Method      string         // HTTP method
+	Path        string         // Path template (e.g. "/orders/{id}")
+	Handler     ToolHandler    // Performs the request
+}
+
+// Definition returns the tool definition for Session.Tools.
+func (t HTTPTool) Definition() map[string]any {
+	return map[string]any{
+		"type":        "function",
+		"name":        t.Name,
+		"description": t.Description,
+		"parameters":  t.Parameters,
+	}
+}
+
+// OpenAPIOptions configures ToolsFromOpenAPI.
+type OpenAPIOptions struct {
+	// BaseURL overrides the first server URL of the spec.
+	BaseURL string
+
+	// ServerVariables fill the {variables} of the spec's server URL, overriding
+	// the defaults declared in servers[].variables. Not used with BaseURL.
+	ServerVariables map[string]string
+
+	// Operations limits the generated tools to these operationIds. Default: all operations.
+	Operations []string
+
+	// Client performs the requests. Default: an http.Client with Timeout.
+	Client *http.Client
+
+	// Header is added to every request (e.g. Authorization).
+	Header http.Header
+
+	// Timeout bounds each request when Client is nil. Default: DefaultHTTPToolTimeout.
+	Timeout time.Duration
+
+	// MaxResponseBytes caps the response body returned to the model; longer bodies
+	// are truncated. Default: DefaultHTTPToolMaxResponseBytes.
+	MaxResponseBytes int
+}
+
+// ToolsFromOpenAPI converts the operations of an OpenAPI 3 spec (JSON or YAML)
+// into HTTP tools. Path, query and header parameters become top-level arguments
+// and a JSON request body becomes the "body" argument; local $refs are resolved.
+//
+// Each tool's handler fills the path template, encodes the query and body, and
+// returns the response body. Non-2xx responses fail the call.
+func ToolsFromOpenAPI(spec []byte, opts OpenAPIOptions) ([]HTTPTool, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		if yerr := yaml.Unmarshal(spec, &doc); yerr != nil {
+			return nil, fmt.Errorf("openapi: parse spec: %w", yerr)
+		}
+	}
+	if doc == nil {
+		return nil, errors.New("openapi: empty spec")
+	}
+
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		if servers, _ := doc["servers"].([]any); len(servers) > 0 {
+			if s, _ := servers[0].(map[string]any); s != nil {
+				u, err := serverURL(s, opts.ServerVariables)
+				if err != nil {
+					return nil, err
+				}
+				baseURL = u
+			}
+		}
+	}
+	if baseURL == "" {
+		return nil, errors.New("openapi: no server URL in spec and no BaseURL given")
+	}
+	if opts.Client == nil {
+		timeout := opts.Timeout
+		if timeout <= 0 {
+			timeout = DefaultHTTPToolTimeout
+		}
+		opts.Client = &http.Client{Timeout: timeout}
+	}
+	if opts.MaxResponseBytes <= 0 {
+		opts.MaxResponseBytes = DefaultHTTPToolMaxResponseBytes
+	}
+
+	wanted := make(map[string]bool, len(opts.Operations))
+	for _, op := range opts.Operations {
+		wanted[op] = true
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	pathKeys := make([]string, 0, len(paths))
+	for p := range paths {
+		pathKeys = append(pathKeys, p)
+	}
+	sort.Strings(pathKeys)
+
+	r := refResolver{doc: doc}
+	var tools []HTTPTool
+	seen := make(map[string]bool)
+	for _, path := range pathKeys {
+		item, _ := r.resolve(paths[path], 0).(map[string]any)
+		if item == nil {
+			continue
+		}
+		for _, method := range openAPIMethods {
+			op, _ := item[method].(map[string]any)
+			if op == nil {
+				continue
+			}
+			opID, _ := op["operationId"].(string)
+			if len(wanted) > 0 && !wanted[opID] {
+				continue
+			}
+			tool := r.operationTool(baseURL, method, path, item, op, opts)
+			if seen[tool.Name] {
+				return nil, fmt.Errorf("openapi: duplicate tool name %q", tool.Name)
+			}
+			seen[tool.Name] = true
+			tools = append(tools, tool)
+		}
+	}
+	return tools, nil
+}
+
+// openAPIParam is an operation parameter mapped to a tool argument.
+type openAPIParam struct {
+	Name string
+	In   string // "path", "query" or "header"
+}
+
+// refResolver resolves local JSON references within an OpenAPI document.
+type refResolver struct{ doc map[string]any }
+
+// resolve follows a "$ref" in v, if any, and recursively resolves nested refs.
+func (r refResolver) resolve(v any, depth int) any {
+	switch t := v.(type) {
+	case map[string]any:
+		if ref, ok := t["$ref"].(string); ok {
+			if depth >= maxSchemaRefDepth {
+				return map[string]any{"type": "object"}
+			}
+			return r.resolve(r.lookup(ref), depth+1)
+		}
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			out[k] = r.resolve(val, depth)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = r.resolve(val, depth)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// lookup returns the value at a local reference such as "#/components/schemas/Order".
+func (r refResolver) lookup(ref string) any {
+	if !strings.HasPrefix(ref, "#/") {
+		return map[string]any{} // external references are not supported
+	}
+	var cur any = r.doc
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+		m, _ := cur.(map[string]any)
+		if m == nil {
+			return map[string]any{}
+		}
+		cur = m[part]
+	}
+	return cur
+}
+
+// serverURL returns the URL of a servers[] entry with its {variables} replaced
+// by vars, or else by their declared defaults.
+func serverURL(server map[string]any, vars map[string]string) (string, error) {
+	u, _ := server["url"].(string)
+	declared, _ := server["variables"].(map[string]any)
+	var missing []string
+	out := serverVarPattern.ReplaceAllStringFunc(u, func(m string) string {
+		name := m[1 : len(m)-1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		if d, _ := declared[name].(map[string]any); d != nil {
+			if def, ok := d["default"]; ok {
+				return fmt.Sprint(def)
+			}
+		}
+		missing = append(missing, name)
+		return m
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("openapi: server URL %q: no value for variables %v", u, missing)
+	}
+	return out, nil
+}
+
+// operationTool builds the tool for one operation.
+func (r refResolver) operationTool(baseURL, method, path string, item, op map[string]any, opts OpenAPIOptions) HTTPTool {
+	name, _ := op["operationId"].(string)
+	if name == "" {
+		name = method + "_" + path
+	}
+	name = strings.Trim(invalidToolNameChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	desc, _ := op["summary"].(string)
+	if d, _ := op["description"].(string); desc == "" {
+		desc = d
+	}
+
+	properties := map[string]any{}
+	var required []string
+	var params []openAPIParam
+
+	// Path-level parameters apply to every operation; operation-level ones override them
+	all := slices.Concat(asSlice(item["parameters"]), asSlice(op["parameters"]))
+	byName := map[string]int{}
+	for _, raw := range all {
+		p, _ := r.resolve(raw, 0).(map[string]any)
+		pname, _ := p["name"].(string)
+		in, _ := p["in"].(string)
+		if pname == "" || (in != "path" && in != "query" && in != "header") {
+			continue
+		}
+		schema, _ := p["schema"].(map[string]any)
+		if schema == nil {
+			schema = map[string]any{"type": "string"}
+		}
+		if d, ok := p["description"].(string); ok && schema["description"] == nil {
+			schema = copyMap(schema)
+			schema["description"] = d
+		}
+		properties[pname] = schema
+		if i, ok := byName[pname]; ok {
+			params[i] = openAPIParam{Name: pname, In: in}
+		} else {
+			byName[pname] = len(params)
+			params = append(params, openAPIParam{Name: pname, In: in})
+		}
+		if req, _ := p["required"].(bool); req || in == "path" {
+			if !slices.Contains(required, pname) {
+				required = append(required, pname)
+			}
+		}
+	}
+
+	hasBody := false
+	if body, _ := r.resolve(op["requestBody"], 0).(map[string]any); body != nil {
+		content, _ := body["content"].(map[string]any)
+		if media, _ := content["application/json"].(map[string]any); media != nil {
+			schema, _ := media["schema"].(map[string]any)
+			if schema == nil {
+				schema = map[string]any{"type": "object"}
+			}
+			properties["body"] = schema
+			hasBody = true
+			if req, _ := body["required"].(bool); req {
+				required = append(required, "body")
+			}
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	endpoint := strings.TrimRight(baseURL, "/") + path
+	call := httpToolCall{
+		method:   strings.ToUpper(method),
+		endpoint: endpoint,
+		params:   params,
+		hasBody:  hasBody,
+		opts:     opts,
+	}
+	return HTTPTool{
+		Name:        name,
+		Description: desc,
+		Parameters:  schema,
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		Handler:     call.do,
+	}
+}
+
+// httpToolCall performs the request for one operation.
+type httpToolCall struct {
+	method   string
+	endpoint string
+	params   []openAPIParam
+	hasBody  bool
+	opts     OpenAPIOptions
+}
+
+// do implements ToolHandler.
+func (h httpToolCall) do(ctx context.Context, arguments string) (string, error) {
+	args := map[string]any{}
+	if strings.TrimSpace(arguments) != "" {
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return "", fmt.Errorf("invalid tool arguments: %w", err)
+		}
+	}
+
+	endpoint := h.endpoint
+	query := url.Values{}
+	header := h.opts.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	for _, p := range h.params {
+		v, ok := args[p.Name]
+		if !ok {
+			continue
+		}
+		switch p.In {
+		case "path":
+			endpoint = strings.ReplaceAll(endpoint, "{"+p.Name+"}", url.PathEscape(fmt.Sprint(v)))
+		case "query":
+			if list, ok := v.([]any); ok {
+				for _, item := range list {
+					query.Add(p.Name, fmt.Sprint(item))
+				}
+			} else {
+				query.Set(p.Name, fmt.Sprint(v))
+			}
+		case "header":
+			header.Set(p.Name, fmt.Sprint(v))
+		}
+	}
+	if strings.Contains(endpoint, "{") {
+		return "", fmt.Errorf("missing path parameter in %s", endpoint)
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if body0, ok := args["body"]; ok && h.hasBody {
+		b, err := json.Marshal(body0)
+		if err != nil {
+			return "", fmt.Errorf("encode request body: %w", err)
+		}
+		body = bytes.NewReader(b)
+		header.Set("Content-Type", "application/json")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, h.method, endpoint, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header = header
+	resp, err := h.opts.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	out, err := io.ReadAll(io.LimitReader(resp.Body, int64(h.opts.MaxResponseBytes)))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("%s %s: status %d: %s", h.method, h.endpoint, resp.StatusCode, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// asSlice returns v as a slice, or nil if it isn't one.
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+// copyMap returns a shallow copy of m.
+func copyMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
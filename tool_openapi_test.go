@@ -0,0 +1,202 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.0
+info: {title: Orders, version: "1"}
+servers:
+  - url: https://orders.example.com/api
+paths:
+  /orders/{orderId}:
+    parameters:
+      - name: orderId
+        in: path
+        required: true
+        description: Order identifier
+        schema: {type: string}
+    get:
+      operationId: getOrder
+      summary: Get an order
+      parameters:
+        - name: expand
+          in: query
+          schema: {type: array, items: {type: string}}
+        - name: X-Tenant
+          in: header
+          required: true
+          schema: {type: string}
+  /orders:
+    post:
+      operationId: create-order
+      description: Create an order
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Order'}
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        sku: {type: string}
+        quantity: {type: integer}
+`
+
+func TestToolsFromOpenAPI_Schemas(t *testing.T) {
+	tools, err := ToolsFromOpenAPI([]byte(testOpenAPISpec), OpenAPIOptions{})
+	if err != nil {
+		t.Fatalf("ToolsFromOpenAPI: %v", err)
+	}
+	if len(tools) != 2 {
+		t.Fatalf("expected 2 tools, got %d", len(tools))
+	}
+
+	create, get := tools[0], tools[1]
+	if create.Name != "create-order" || create.Method != "POST" || create.Description != "Create an order" {
+		t.Errorf("unexpected create tool: %+v", create)
+	}
+	body := create.Parameters["properties"].(map[string]any)["body"].(map[string]any)
+	if _, ok := body["properties"].(map[string]any)["quantity"]; !ok {
+		t.Errorf("expected resolved body schema, got %v", body)
+	}
+	if !reflect.DeepEqual(create.Parameters["required"], []string{"body"}) {
+		t.Errorf("expected body to be required, got %v", create.Parameters["required"])
+	}
+
+	if get.Name != "getOrder" || get.Description != "Get an order" {
+		t.Errorf("unexpected get tool: %+v", get)
+	}
+	props := get.Parameters["properties"].(map[string]any)
+	if props["orderId"].(map[string]any)["description"] != "Order identifier" {
+		t.Errorf("expected parameter description in schema, got %v", props["orderId"])
+	}
+	if !reflect.DeepEqual(get.Parameters["required"], []string{"orderId", "X-Tenant"}) {
+		t.Errorf("unexpected required list: %v", get.Parameters["required"])
+	}
+
+	def := get.Definition()
+	if def["type"] != "function" || def["name"] != "getOrder" {
+		t.Errorf("unexpected definition: %v", def)
+	}
+	if _, err := json.Marshal(def); err != nil {
+		t.Errorf("definition must be JSON-serializable: %v", err)
+	}
+}
+
+func TestToolsFromOpenAPI_Handler(t *testing.T) {
+	var gotMethod, gotURL, gotTenant, gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotURL = r.Method, r.URL.String()
+		gotTenant, gotAuth = r.Header.Get("X-Tenant"), r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			http.Error(w, "no such order", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"shipped"}`))
+	}))
+	defer server.Close()
+
+	tools, err := ToolsFromOpenAPI([]byte(testOpenAPISpec), OpenAPIOptions{
+		BaseURL: server.URL + "/api",
+		Header:  http.Header{"Authorization": {"Bearer t"}},
+	})
+	if err != nil {
+		t.Fatalf("ToolsFromOpenAPI: %v", err)
+	}
+	create, get := tools[0], tools[1]
+	ctx := context.Background()
+
+	out, err := get.Handler(ctx, `{"orderId":"a/b","expand":["items","customer"],"X-Tenant":"t1"}`)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if out != `{"status":"shipped"}` {
+		t.Errorf("unexpected output %q", out)
+	}
+	if gotMethod != "GET" || gotURL != "/api/orders/a%2Fb?expand=items&expand=customer" {
+		t.Errorf("unexpected request %s %s", gotMethod, gotURL)
+	}
+	if gotTenant != "t1" || gotAuth != "Bearer t" {
+		t.Errorf("unexpected headers tenant=%q auth=%q", gotTenant, gotAuth)
+	}
+
+	if _, err := create.Handler(ctx, `{"body":{"sku":"X1","quantity":2}}`); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if gotMethod != "POST" || gotBody != `{"quantity":2,"sku":"X1"}` {
+		t.Errorf("unexpected request %s body %s", gotMethod, gotBody)
+	}
+
+	if _, err := get.Handler(ctx, `{"orderId":"missing"}`); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+	if _, err := get.Handler(ctx, `{}`); err == nil {
+		t.Error("expected error for missing path parameter")
+	}
+}
+
+func TestToolsFromOpenAPI_ServerVariables(t *testing.T) {
+	var gotURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	spec := `
+openapi: 3.0.0
+servers:
+  - url: http://{host}/{basePath}/v{version}
+    variables:
+      host: {default: orders.example.com}
+      basePath: {default: api}
+      version: {default: 2, enum: [1, 2]}
+paths:
+  /orders:
+    get: {operationId: listOrders}
+`
+	tools, err := ToolsFromOpenAPI([]byte(spec), OpenAPIOptions{
+		ServerVariables: map[string]string{"host": strings.TrimPrefix(server.URL, "http://")},
+	})
+	if err != nil {
+		t.Fatalf("ToolsFromOpenAPI: %v", err)
+	}
+	if _, err := tools[0].Handler(context.Background(), `{}`); err != nil {
+		t.Fatalf("listOrders: %v", err)
+	}
+	if gotURL != "/api/v2/orders" {
+		t.Errorf("request to %s, want the declared defaults /api/v2/orders", gotURL)
+	}
+
+	undeclared := strings.Replace(spec, "      host: {default: orders.example.com}\n", "", 1)
+	if _, err := ToolsFromOpenAPI([]byte(undeclared), OpenAPIOptions{}); err == nil || !strings.Contains(err.Error(), "host") {
+		t.Errorf("expected an error naming the unset variable, got %v", err)
+	}
+}
+
+func TestToolsFromOpenAPI_Filter(t *testing.T) {
+	tools, err := ToolsFromOpenAPI([]byte(testOpenAPISpec), OpenAPIOptions{Operations: []string{"getOrder"}})
+	if err != nil {
+		t.Fatalf("ToolsFromOpenAPI: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "getOrder" {
+		t.Errorf("expected only getOrder, got %+v", tools)
+	}
+
+	if _, err := ToolsFromOpenAPI([]byte(`{"openapi":"3.0.0","paths":{}}`), OpenAPIOptions{}); err == nil {
+		t.Error("expected error without a server URL")
+	}
+}
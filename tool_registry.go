@@ -0,0 +1,278 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ToolRegistry holds the tools available to the assistant and executes the
+// function calls the model makes. Attach it to a client with Client.UseTools,
+// and declare its tools in the session with Apply (or Definitions).
+// A ToolRegistry is safe for concurrent use.
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	tools map[string]Tool
+	order []string
+}
+
+// NewToolRegistry creates an empty registry.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: make(map[string]Tool)}
+}
+
+// Register adds tools to the registry. Each tool needs a name and a handler;
+// registering a name twice replaces the earlier tool.
+func (r *ToolRegistry) Register(tools ...Tool) error {
+	for _, t := range tools {
+		if t.Name == "" {
+			return errors.New("azrealtime: tool name is required")
+		}
+		if t.Handler == nil {
+			return fmt.Errorf("azrealtime: tool %q has no handler", t.Name)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range tools {
+		if _, ok := r.tools[t.Name]; !ok {
+			r.order = append(r.order, t.Name)
+		}
+		r.tools[t.Name] = t
+	}
+	return nil
+}
+
+// RegisterFunc registers fn, a func([context.Context,] T) (R, error), under
+// name. The parameter schema is derived from T as described in ToolFromFunc.
+func (r *ToolRegistry) RegisterFunc(name, description string, fn any) error {
+	t, err := ToolFromFunc(fn)
+	if err != nil {
+		return err
+	}
+	t.Name, t.Description = name, description
+	return r.Register(t)
+}
+
+// Lookup returns the tool registered under name.
+func (r *ToolRegistry) Lookup(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tools[name]
+	return t, ok
+}
+
+// Definitions returns the tool definitions in registration order, ready for
+// Session.Tools.
+func (r *ToolRegistry) Definitions() []any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defs := make([]any, 0, len(r.order))
+	for _, name := range r.order {
+		defs = append(defs, r.tools[name].Definition())
+	}
+	return defs
+}
+
+// Apply returns a copy of base with Tools set to the registry's definitions.
+func (r *ToolRegistry) Apply(base Session) Session {
+	base.Tools = r.Definitions()
+	return base
+}
+
+// Call runs the named tool with the raw JSON arguments.
+func (r *ToolRegistry) Call(ctx context.Context, name, arguments string) (string, error) {
+	t, ok := r.Lookup(name)
+	if !ok {
+		return "", fmt.Errorf("tool %q is not registered", name)
+	}
+	return t.Handler(ctx, arguments)
+}
+
+// ToolCall describes a function call executed by a ToolRegistry.
+type ToolCall struct {
+	ResponseID string        // Response that made the call
+	ItemID     string        // The function_call conversation item
+	CallID     string        // Call ID the output is reported under
+	Name       string        // Tool name
+	Arguments  string        // Raw JSON arguments
+	Output     string        // Output sent to the model
+	Err        error         // Handler error; Output then carries it as {"error": ...}
+	Duration   time.Duration // Handler run time
+}
+
+// UseTools makes the client execute function calls with r: each call's
+// handler starts as soon as its arguments are complete, and once the response
+// is done the outputs are added to the conversation as function_call_output
+// items and a follow-up response is requested. Handler errors and unknown
+// tools are reported to the model as {"error": "..."} outputs. No follow-up is
+// requested for cancelled or failed responses. Pass nil to stop executing calls.
+//
+// Handlers run off the read loop with a context cancelled when the client
+// closes or reconnects. The registry does not change the session; declare its
+// tools with ToolRegistry.Apply.
+func (c *Client) UseTools(r *ToolRegistry) {
+	c.tools.mu.Lock()
+	defer c.tools.mu.Unlock()
+	c.tools.registry = r
+}
+
+// OnToolCall registers a callback invoked after each function call handled by
+// the registry set with UseTools, before its output is sent.
+func (c *Client) OnToolCall(fn func(ToolCall)) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	c.onToolCall = fn
+}
+
+// toolRunner executes function calls for Client.UseTools.
+type toolRunner struct {
+	mu       sync.Mutex
+	registry *ToolRegistry
+	names    map[string]string     // Function call item ID -> tool name
+	pending  map[string][]*toolRun // Response ID -> calls in output order
+	started  map[string]bool       // Call IDs already running
+	ctx      context.Context       // Cancelled on close and reconnect
+	cancel   context.CancelFunc
+}
+
+type toolRun struct {
+	call ToolCall
+	done chan struct{}
+}
+
+// itemAdded remembers function call names; argument events don't carry them.
+func (t *toolRunner) itemAdded(item ConversationItem) {
+	if item.Type != "function_call" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.registry == nil {
+		return
+	}
+	if t.names == nil {
+		t.names = make(map[string]string)
+	}
+	t.names[item.ID] = item.Name
+}
+
+// argumentsDone starts the call's handler.
+func (t *toolRunner) argumentsDone(c *Client, e ResponseFunctionCallArgumentsDone) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	name, ok := t.names[e.ItemID]
+	if !ok {
+		return // Picked up from the response output on response.done
+	}
+	delete(t.names, e.ItemID)
+	t.startLocked(c, ToolCall{ResponseID: e.ResponseID, ItemID: e.ItemID, CallID: e.CallID, Name: name, Arguments: e.Arguments})
+}
+
+// responseDone starts any calls not seen yet, then sends the outputs and the
+// follow-up response once all of the response's calls have finished.
+func (t *toolRunner) responseDone(c *Client, resp ResponseObject) {
+	t.mu.Lock()
+	if t.registry == nil {
+		t.mu.Unlock()
+		return
+	}
+	for _, item := range resp.Output {
+		if item.Type == "function_call" {
+			delete(t.names, item.ID)
+			t.startLocked(c, ToolCall{ResponseID: resp.ID, ItemID: item.ID, CallID: item.CallID, Name: item.Name, Arguments: item.Arguments})
+		}
+	}
+	runs := t.pending[resp.ID]
+	delete(t.pending, resp.ID)
+	for _, run := range runs {
+		delete(t.started, run.call.CallID)
+	}
+	ctx := t.ctx
+	t.mu.Unlock()
+	if len(runs) == 0 {
+		return
+	}
+
+	go func() {
+		for _, run := range runs {
+			select {
+			case <-run.done:
+			case <-ctx.Done():
+				return
+			}
+		}
+		for _, run := range runs {
+			item := ConversationItem{Type: "function_call_output", CallID: run.call.CallID, Output: run.call.Output}
+			if err := c.CreateConversationItem(ctx, item); err != nil {
+				c.logError("tool_output_failed", map[string]any{"tool": run.call.Name, "call_id": run.call.CallID, "err": err})
+				return
+			}
+		}
+		if resp.Status != "" && resp.Status != "completed" {
+			return
+		}
+		if _, err := c.CreateResponse(ctx, CreateResponseOptions{}); err != nil {
+			c.logError("tool_response_failed", map[string]any{"response_id": resp.ID, "err": err})
+		}
+	}()
+}
+
+// startLocked runs call in the background unless it already started.
+func (t *toolRunner) startLocked(c *Client, call ToolCall) {
+	if t.registry == nil || call.CallID == "" || t.started[call.CallID] {
+		return
+	}
+	if t.started == nil {
+		t.started = make(map[string]bool)
+		t.pending = make(map[string][]*toolRun)
+	}
+	if t.ctx == nil {
+		t.ctx, t.cancel = context.WithCancel(context.Background())
+		go func(ctx context.Context, cancel context.CancelFunc) {
+			select {
+			case <-c.closedCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}(t.ctx, t.cancel)
+	}
+	t.started[call.CallID] = true
+	run := &toolRun{call: call, done: make(chan struct{})}
+	t.pending[call.ResponseID] = append(t.pending[call.ResponseID], run)
+
+	registry, ctx := t.registry, t.ctx
+	go func() {
+		defer close(run.done)
+		start := time.Now()
+		out, err := registry.Call(ctx, call.Name, call.Arguments)
+		run.call.Duration = time.Since(start)
+		if err != nil {
+			b, _ := json.Marshal(map[string]string{"error": err.Error()})
+			out = string(b)
+			c.logError("tool_call_failed", map[string]any{"tool": call.Name, "call_id": call.CallID, "err": err})
+		}
+		run.call.Output, run.call.Err = out, err
+
+		c.handlerMu.RLock()
+		if c.onToolCall != nil {
+			c.onToolCall(run.call)
+		}
+		c.handlerMu.RUnlock()
+	}()
+}
+
+// reset cancels running calls and forgets pending ones, e.g. on reconnect,
+// where the new server session knows nothing about them.
+func (t *toolRunner) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cancel != nil {
+		t.cancel()
+	}
+	t.ctx, t.cancel = nil, nil
+	t.names, t.pending, t.started = nil, nil, nil
+}
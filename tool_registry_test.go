@@ -0,0 +1,161 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestToolRegistry(t *testing.T) {
+	r := NewToolRegistry()
+	if err := r.RegisterFunc("lookup_order", "Look up an order", lookupOrder); err != nil {
+		t.Fatalf("RegisterFunc: %v", err)
+	}
+	if err := r.Register(Tool{Name: "ping", Handler: func(context.Context, string) (string, error) { return "pong", nil }}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := r.Register(Tool{Name: "broken"}); err == nil {
+		t.Error("expected error for a tool without handler")
+	}
+	if err := r.Register(Tool{Handler: func(context.Context, string) (string, error) { return "", nil }}); err == nil {
+		t.Error("expected error for a tool without name")
+	}
+
+	defs := r.Apply(Session{}).Tools
+	if len(defs) != 2 {
+		t.Fatalf("got %d definitions, want 2", len(defs))
+	}
+	if d := defs[0].(map[string]any); d["name"] != "lookup_order" || d["description"] != "Look up an order" {
+		t.Errorf("first definition = %v", d)
+	}
+
+	if out, err := r.Call(context.Background(), "ping", "{}"); out != "pong" || err != nil {
+		t.Errorf("Call(ping) = %q, %v", out, err)
+	}
+	if _, err := r.Call(context.Background(), "missing", "{}"); err == nil {
+		t.Error("expected error for an unknown tool")
+	}
+}
+
+// functionCallResponse scripts a response that calls the given tools.
+func functionCallResponse(id string, calls ...ConversationItem) []MockStep {
+	steps := []MockStep{{Event: ResponseCreated{Type: "response.created", Response: ResponseObject{ID: id, Status: "in_progress"}}}}
+	for i, call := range calls {
+		steps = append(steps,
+			MockStep{Event: ResponseOutputItemAdded{Type: "response.output_item.added", ResponseID: id, OutputIndex: i, Item: ConversationItem{ID: call.ID, Type: "function_call", Name: call.Name, CallID: call.CallID}}},
+			MockStep{Delay: 10 * time.Millisecond, Event: ResponseFunctionCallArgumentsDone{Type: "response.function_call_arguments.done", ResponseID: id, ItemID: call.ID, OutputIndex: i, CallID: call.CallID, Arguments: call.Arguments}},
+		)
+	}
+	return append(steps, MockStep{Event: ResponseDone{Type: "response.done", Response: ResponseObject{ID: id, Status: "completed", Output: calls}}})
+}
+
+func TestClient_UseTools(t *testing.T) {
+	calls := []ConversationItem{
+		{ID: "item_fc1", Type: "function_call", Name: "lookup_order", CallID: "call_1", Arguments: `{"order_id":"A1"}`},
+		{ID: "item_fc2", Type: "function_call", Name: "unknown_tool", CallID: "call_2", Arguments: `{}`},
+	}
+	client, _ := dialProfile(t, &MockProfile{Responses: [][]MockStep{
+		functionCallResponse("resp_1", calls...),
+		{
+			{Event: ResponseCreated{Type: "response.created", Response: ResponseObject{ID: "resp_2", Status: "in_progress"}}},
+			{Event: ResponseDone{Type: "response.done", Response: ResponseObject{ID: "resp_2", Status: "completed"}}},
+		},
+	}})
+	sent := recordOutbound(client)
+
+	r := NewToolRegistry()
+	if err := r.RegisterFunc("lookup_order", "", lookupOrder); err != nil {
+		t.Fatal(err)
+	}
+	client.UseTools(r)
+
+	var mu sync.Mutex
+	var handled []ToolCall
+	client.OnToolCall(func(c ToolCall) {
+		mu.Lock()
+		handled = append(handled, c)
+		mu.Unlock()
+	})
+	done := make(chan string, 2)
+	client.OnResponseDone(func(e ResponseDone) { done <- e.Response.ID })
+
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	for _, want := range []string{"resp_1", "resp_2"} {
+		select {
+		case id := <-done:
+			if id != want {
+				t.Fatalf("response.done for %s, want %s", id, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+
+	outputs := sent.ofType("conversation.item.create")
+	if len(outputs) != 2 {
+		t.Fatalf("sent %d conversation items, want 2", len(outputs))
+	}
+	first := outputs[0]["item"].(map[string]any)
+	if first["type"] != "function_call_output" || first["call_id"] != "call_1" || first["output"] != `{"id":"A1","total":42}` {
+		t.Errorf("first output = %v", first)
+	}
+	second := outputs[1]["item"].(map[string]any)
+	if second["call_id"] != "call_2" || !strings.Contains(second["output"].(string), `"error"`) {
+		t.Errorf("second output = %v, want an error for the unknown tool", second)
+	}
+	if got := len(sent.ofType("response.create")); got != 2 {
+		t.Errorf("sent %d response.create, want 2 (request and follow-up)", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 2 {
+		t.Fatalf("OnToolCall called %d times, want 2", len(handled))
+	}
+	for _, c := range handled {
+		if c.ResponseID != "resp_1" {
+			t.Errorf("call %s has response ID %q", c.CallID, c.ResponseID)
+		}
+		if (c.Name == "unknown_tool") != (c.Err != nil) {
+			t.Errorf("call %s: err = %v", c.Name, c.Err)
+		}
+	}
+}
+
+func TestClient_UseTools_CancelledResponse(t *testing.T) {
+	steps := functionCallResponse("resp_1", ConversationItem{ID: "item_fc1", Type: "function_call", Name: "slow", CallID: "call_1", Arguments: `{}`})
+	last := steps[len(steps)-1].Event.(ResponseDone)
+	last.Response.Status = "cancelled"
+	steps[len(steps)-1].Event = last
+	client, _ := dialProfile(t, &MockProfile{Responses: [][]MockStep{steps}})
+	sent := recordOutbound(client)
+
+	r := NewToolRegistry()
+	_ = r.Register(Tool{Name: "slow", Handler: func(ctx context.Context, _ string) (string, error) {
+		return "", errors.New("unavailable")
+	}})
+	client.UseTools(r)
+	handled := make(chan struct{})
+	client.OnToolCall(func(ToolCall) { close(handled) })
+
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool was not called")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := len(sent.ofType("conversation.item.create")); got != 1 {
+		t.Errorf("sent %d outputs, want 1", got)
+	}
+	if got := len(sent.ofType("response.create")); got != 1 {
+		t.Errorf("sent %d response.create, want no follow-up for a cancelled response", got)
+	}
+}
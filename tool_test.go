@@ -0,0 +1,133 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type orderQuery struct {
+	OrderID string    `json:"order_id" description:"Order identifier"`
+	Status  string    `json:"status,omitempty" enum:"open,shipped"`
+	Limit   *int      `json:"limit"`
+	Tags    []string  `json:"tags,omitempty"`
+	Since   time.Time `json:"since,omitempty"`
+	Ignored string    `json:"-"`
+	paging
+}
+
+type paging struct {
+	Page int `json:"page,omitempty"`
+}
+
+type orderResult struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+func lookupOrder(ctx context.Context, q orderQuery) (orderResult, error) {
+	if q.OrderID == "" {
+		return orderResult{}, errors.New("order_id is required")
+	}
+	return orderResult{ID: q.OrderID, Total: 42 + q.Page}, nil
+}
+
+func TestSchemaFor(t *testing.T) {
+	got, err := SchemaFor(orderQuery{})
+	if err != nil {
+		t.Fatalf("SchemaFor: %v", err)
+	}
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"order_id": map[string]any{"type": "string", "description": "Order identifier"},
+			"status":   map[string]any{"type": "string", "enum": []string{"open", "shipped"}},
+			"limit":    map[string]any{"type": "integer"},
+			"tags":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"since":    map[string]any{"type": "string", "format": "date-time"},
+			"page":     map[string]any{"type": "integer"},
+		},
+		"required": []string{"order_id"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		t.Errorf("SchemaFor = %s", gotJSON)
+	}
+
+	type node struct {
+		Children []node `json:"children"`
+	}
+	if _, err := SchemaFor(node{}); err == nil {
+		t.Error("expected error for a recursive type")
+	}
+	if _, err := SchemaFor(map[int]string{}); err == nil {
+		t.Error("expected error for non-string map keys")
+	}
+}
+
+func TestToolFromFunc(t *testing.T) {
+	tool, err := ToolFromFunc(lookupOrder)
+	if err != nil {
+		t.Fatalf("ToolFromFunc: %v", err)
+	}
+	if tool.Name != "lookup_order" {
+		t.Errorf("Name = %q, want lookup_order", tool.Name)
+	}
+	tool.Description = "Look up an order"
+
+	out, err := tool.Handler(context.Background(), `{"order_id":"A1","page":2}`)
+	if err != nil || out != `{"id":"A1","total":44}` {
+		t.Errorf("Handler = %q, %v", out, err)
+	}
+	if _, err := tool.Handler(context.Background(), `{}`); err == nil || err.Error() != "order_id is required" {
+		t.Errorf("expected the function's error, got %v", err)
+	}
+	if _, err := tool.Handler(context.Background(), `{"order_id":1}`); err == nil {
+		t.Error("expected error for mistyped arguments")
+	}
+
+	// Marshals to the wire format inside a session
+	b, err := json.Marshal(Session{Tools: []any{tool}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wire struct {
+		Tools []map[string]any `json:"tools"`
+	}
+	_ = json.Unmarshal(b, &wire)
+	if len(wire.Tools) != 1 || wire.Tools[0]["type"] != "function" || wire.Tools[0]["name"] != "lookup_order" || wire.Tools[0]["parameters"] == nil {
+		t.Errorf("session tools = %s", b)
+	}
+}
+
+func TestToolFromFunc_Shapes(t *testing.T) {
+	echo, err := ToolFromFunc(func(q struct {
+		Text string `json:"text"`
+	}) (string, error) {
+		return q.Text, nil
+	})
+	if err != nil {
+		t.Fatalf("ToolFromFunc: %v", err)
+	}
+	if echo.Name != "" {
+		t.Errorf("function literal got name %q", echo.Name)
+	}
+	if out, _ := echo.Handler(context.Background(), `{"text":"hi"}`); out != "hi" {
+		t.Errorf("string result = %q, want it unquoted", out)
+	}
+
+	for _, bad := range []any{
+		nil,
+		"not a func",
+		func(string) (string, error) { return "", nil },
+		func(orderQuery) string { return "" },
+		func(context.Context, orderQuery, int) (string, error) { return "", nil },
+	} {
+		if _, err := ToolFromFunc(bad); err == nil {
+			t.Errorf("ToolFromFunc(%T) succeeded", bad)
+		}
+	}
+}
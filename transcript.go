@@ -0,0 +1,260 @@
+package azrealtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TranscriptEntry is one message of the conversation.
+type TranscriptEntry struct {
+	ItemID string        // Conversation item ID
+	Role   string        // "user" or "assistant"
+	Text   string        // Transcribed or typed text; empty until transcription completes
+	Start  time.Duration // Offset from Transcript.StartedAt
+	End    time.Duration // Offset from Transcript.StartedAt
+}
+
+// Transcript is the text of a conversation with approximate timings, as
+// returned by Client.Transcript.
+type Transcript struct {
+	StartedAt time.Time         // When the client connected
+	Entries   []TranscriptEntry // Messages in the order they started
+}
+
+// Transcript returns the conversation so far: user speech (once transcribed;
+// enable Session.InputTranscription), typed user messages and assistant replies.
+// It spans reconnects, and items later deleted from the conversation are kept.
+//
+// Timings are measured by the client: user turns run from speech_started to
+// speech_stopped, assistant turns from their first event until the end of the
+// audio received for them (or their last text delta). They are close to, but
+// not exactly, what was heard on a live call.
+func (c *Client) Transcript() Transcript {
+	return c.transcript.snapshot()
+}
+
+// ExportMarkdown writes the transcript as a Markdown document with one
+// paragraph per message. Entries without text are skipped.
+func (t Transcript) ExportMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# Transcript\n")
+	if !t.StartedAt.IsZero() {
+		fmt.Fprintf(&b, "\n_Started %s_\n", t.StartedAt.UTC().Format("2006-01-02 15:04:05 MST"))
+	}
+	for _, e := range t.Entries {
+		if e.Text == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n**%s** (%s): %s\n", roleTitle(e.Role), formatClock(e.Start), strings.TrimSpace(e.Text))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ExportJSON writes the transcript as an indented JSON document with
+// millisecond offsets:
+//
+//	{"started_at": "...", "entries": [{"item_id": "...", "role": "user", "text": "...", "start_ms": 0, "end_ms": 1200}]}
+func (t Transcript) ExportJSON(w io.Writer) error {
+	type entry struct {
+		ItemID  string `json:"item_id"`
+		Role    string `json:"role"`
+		Text    string `json:"text"`
+		StartMS int64  `json:"start_ms"`
+		EndMS   int64  `json:"end_ms"`
+	}
+	doc := struct {
+		StartedAt time.Time `json:"started_at"`
+		Entries   []entry   `json:"entries"`
+	}{StartedAt: t.StartedAt, Entries: make([]entry, 0, len(t.Entries))}
+	for _, e := range t.Entries {
+		doc.Entries = append(doc.Entries, entry{e.ItemID, e.Role, e.Text, e.Start.Milliseconds(), e.End.Milliseconds()})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// ExportSRT writes the transcript as SubRip subtitles, one cue per message,
+// prefixed with the speaker. Entries without text are skipped, and cues
+// without a measured duration are shown for one second.
+func (t Transcript) ExportSRT(w io.Writer) error {
+	var b strings.Builder
+	n := 0
+	for _, e := range t.Entries {
+		if e.Text == "" {
+			continue
+		}
+		end := e.End
+		if end <= e.Start {
+			end = e.Start + time.Second
+		}
+		n++
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s: %s\n\n", n, formatSRTTime(e.Start), formatSRTTime(end), roleTitle(e.Role), strings.TrimSpace(e.Text))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func roleTitle(role string) string {
+	if role == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
+
+// formatClock formats d as mm:ss, or h:mm:ss from one hour.
+func formatClock(d time.Duration) string {
+	s := int(d / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
+
+// formatSRTTime formats d as hh:mm:ss,mmm.
+func formatSRTTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// transcriptTracker builds the Transcript from conversation events.
+type transcriptTracker struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	entries   []TranscriptEntry
+	index     map[string]int           // Item ID -> entries index
+	audio     map[string]time.Duration // Assistant audio received per item
+}
+
+// entry returns the entry for itemID, adding it at now if needed.
+func (t *transcriptTracker) entry(itemID, role string, now time.Time) *TranscriptEntry {
+	if t.startedAt.IsZero() {
+		t.startedAt = now
+	}
+	if i, ok := t.index[itemID]; ok {
+		if t.entries[i].Role == "" {
+			t.entries[i].Role = role
+		}
+		return &t.entries[i]
+	}
+	if t.index == nil {
+		t.index = make(map[string]int)
+	}
+	at := now.Sub(t.startedAt)
+	t.index[itemID] = len(t.entries)
+	t.entries = append(t.entries, TranscriptEntry{ItemID: itemID, Role: role, Start: at, End: at})
+	return &t.entries[len(t.entries)-1]
+}
+
+func (t *transcriptTracker) speechStarted(itemID string, now time.Time) {
+	if itemID == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entry(itemID, "user", now)
+}
+
+func (t *transcriptTracker) speechStopped(itemID string, now time.Time) {
+	if itemID == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(itemID, "user", now)
+	e.End = now.Sub(t.startedAt)
+}
+
+// itemCreated records message items, with the text of typed messages.
+func (t *transcriptTracker) itemCreated(item ConversationItem, now time.Time) {
+	if item.Type != "message" || item.ID == "" || (item.Role != "user" && item.Role != "assistant") {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(item.ID, item.Role, now)
+	if e.Text != "" {
+		return
+	}
+	var parts []string
+	for _, p := range item.Content {
+		if p.Text != "" {
+			parts = append(parts, p.Text)
+		} else if p.Transcript != "" {
+			parts = append(parts, p.Transcript)
+		}
+	}
+	e.Text = strings.Join(parts, "\n")
+}
+
+func (t *transcriptTracker) userTranscript(itemID, text string, now time.Time) {
+	if itemID == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entry(itemID, "user", now).Text = text
+}
+
+// userTranscriptDelta appends a partial transcript; userTranscript replaces it
+// with the final text.
+func (t *transcriptTracker) userTranscriptDelta(itemID, delta string, now time.Time) {
+	if itemID == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entry(itemID, "user", now).Text += delta
+}
+
+// assistantText appends a text or audio transcript delta.
+func (t *transcriptTracker) assistantText(itemID, delta string, now time.Time) {
+	if itemID == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(itemID, "assistant", now)
+	e.Text += delta
+	if at := now.Sub(t.startedAt); at > e.End {
+		e.End = at
+	}
+}
+
+// assistantTextDone replaces the assembled deltas with the final text.
+func (t *transcriptTracker) assistantTextDone(itemID, text string, now time.Time) {
+	if itemID == "" || text == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entry(itemID, "assistant", now).Text = text
+}
+
+// assistantAudio extends the entry to cover d more of received audio.
+func (t *transcriptTracker) assistantAudio(itemID string, d time.Duration, now time.Time) {
+	if itemID == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(itemID, "assistant", now)
+	if t.audio == nil {
+		t.audio = make(map[string]time.Duration)
+	}
+	t.audio[itemID] += d
+	if end := e.Start + t.audio[itemID]; end > e.End {
+		e.End = end
+	}
+}
+
+func (t *transcriptTracker) snapshot() Transcript {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Transcript{StartedAt: t.startedAt, Entries: append([]TranscriptEntry(nil), t.entries...)}
+}
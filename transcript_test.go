@@ -0,0 +1,186 @@
+package azrealtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func sampleTranscript() Transcript {
+	return Transcript{
+		StartedAt: time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+		Entries: []TranscriptEntry{
+			{ItemID: "item_1", Role: "user", Text: "What's the weather?", Start: 1200 * time.Millisecond, End: 2500 * time.Millisecond},
+			{ItemID: "item_2", Role: "assistant", Text: "Sunny and 22 degrees. ", Start: 3 * time.Second, End: 5*time.Second + 250*time.Millisecond},
+			{ItemID: "item_3", Role: "user", Start: 64 * time.Second, End: 65 * time.Second}, // Not transcribed
+			{ItemID: "item_4", Role: "user", Text: "Thanks", Start: 3725 * time.Second, End: 3725 * time.Second},
+		},
+	}
+}
+
+func TestTranscript_ExportMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleTranscript().ExportMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Transcript\n" +
+		"\n_Started 2025-01-02 15:04:05 UTC_\n" +
+		"\n**User** (00:01): What's the weather?\n" +
+		"\n**Assistant** (00:03): Sunny and 22 degrees.\n" +
+		"\n**User** (1:02:05): Thanks\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ExportMarkdown =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTranscript_ExportSRT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleTranscript().ExportSRT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "1\n00:00:01,200 --> 00:00:02,500\nUser: What's the weather?\n\n" +
+		"2\n00:00:03,000 --> 00:00:05,250\nAssistant: Sunny and 22 degrees.\n\n" +
+		"3\n01:02:05,000 --> 01:02:06,000\nUser: Thanks\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ExportSRT =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTranscript_ExportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleTranscript().ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		StartedAt time.Time `json:"started_at"`
+		Entries   []struct {
+			ItemID  string `json:"item_id"`
+			Role    string `json:"role"`
+			Text    string `json:"text"`
+			StartMS int64  `json:"start_ms"`
+			EndMS   int64  `json:"end_ms"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if !doc.StartedAt.Equal(sampleTranscript().StartedAt) || len(doc.Entries) != 4 {
+		t.Fatalf("decoded %+v", doc)
+	}
+	if e := doc.Entries[1]; e.ItemID != "item_2" || e.Role != "assistant" || e.StartMS != 3000 || e.EndMS != 5250 {
+		t.Errorf("entry 1 = %+v", e)
+	}
+}
+
+func TestClient_Transcript(t *testing.T) {
+	speech := []MockStep{
+		{Event: InputAudioBufferSpeechStarted{Type: "input_audio_buffer.speech_started", ItemID: "item_user"}},
+		{Delay: 50 * time.Millisecond, Event: InputAudioBufferSpeechStopped{Type: "input_audio_buffer.speech_stopped", ItemID: "item_user"}},
+		{Event: ConversationItemCreated{Type: "conversation.item.created", Item: ConversationItem{ID: "item_user", Type: "message", Role: "user", Content: []ContentPart{{Type: "input_audio"}}}}},
+		{Event: ConversationItemInputAudioTranscriptionCompleted{Type: "conversation.item.input_audio_transcription.completed", ItemID: "item_user", Transcript: "Hello there"}},
+	}
+	reply := SyntheticResponse(10*time.Millisecond, 10*time.Millisecond, 3)
+	reply = append(reply[:len(reply)-1],
+		MockStep{Event: ResponseAudioTranscriptDelta{Type: "response.audio_transcript.delta", ItemID: "item_profile", Delta: "Hi, "}},
+		MockStep{Event: ResponseAudioTranscriptDelta{Type: "response.audio_transcript.delta", ItemID: "item_profile", Delta: "how can I help?"}},
+		reply[len(reply)-1],
+	)
+	client, _ := dialProfile(t, &MockProfile{Speech: speech, Response: reply})
+
+	transcribed := make(chan struct{})
+	client.OnConversationItemInputAudioTranscriptionCompleted(func(ConversationItemInputAudioTranscriptionCompleted) { close(transcribed) })
+	done := make(chan struct{})
+	client.OnResponseDone(func(ResponseDone) { close(done) })
+
+	ctx := context.Background()
+	if err := client.AppendPCM16(ctx, make([]byte, PCM16BytesFor(100, DefaultSampleRate))); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	<-transcribed
+	if err := client.CreateConversationItem(ctx, ConversationItem{Type: "message", Role: "user", Content: []ContentPart{{Type: "input_text", Text: "Also, a typed note"}}}); err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	if _, err := client.CreateResponse(ctx, CreateResponseOptions{}); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no response.done")
+	}
+
+	tr := client.Transcript()
+	if len(tr.Entries) != 3 {
+		t.Fatalf("got %d entries: %+v", len(tr.Entries), tr.Entries)
+	}
+	user, typed, assistant := tr.Entries[0], tr.Entries[1], tr.Entries[2]
+	if user.Role != "user" || user.Text != "Hello there" || user.End-user.Start < 40*time.Millisecond {
+		t.Errorf("user entry = %+v", user)
+	}
+	if typed.Role != "user" || typed.Text != "Also, a typed note" {
+		t.Errorf("typed entry = %+v", typed)
+	}
+	if assistant.Role != "assistant" || assistant.Text != "Hi, how can I help?" || assistant.End-assistant.Start < 300*time.Millisecond {
+		t.Errorf("assistant entry = %+v (3 x 100 ms of audio)", assistant)
+	}
+	if tr.StartedAt.IsZero() || assistant.Start < user.Start {
+		t.Errorf("timings out of order: %+v", tr)
+	}
+}
+
+func TestInputTranscription_KeywordPrompt(t *testing.T) {
+	data, err := json.Marshal(InputTranscription{Model: TranscriptionModelWhisper1, Prompt: KeywordPrompt("Contoso", "Fabrikam")})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"model":"whisper-1","prompt":"Contoso, Fabrikam"}` {
+		t.Errorf("keywords encoded as %s", data)
+	}
+
+	data, _ = json.Marshal(InputTranscription{Model: TranscriptionModelGPT4oTranscribe, Prompt: Ptr("Support call")})
+	if string(data) != `{"model":"gpt-4o-transcribe","prompt":"Support call"}` {
+		t.Errorf("prompt encoded as %s", data)
+	}
+}
+
+func TestClient_TranscriptionDelta(t *testing.T) {
+	speech := []MockStep{
+		{Event: InputAudioBufferSpeechStarted{Type: "input_audio_buffer.speech_started", ItemID: "item_user"}},
+		{Event: InputAudioBufferSpeechStopped{Type: "input_audio_buffer.speech_stopped", ItemID: "item_user"}},
+		{Event: ConversationItemInputAudioTranscriptionDelta{Type: "conversation.item.input_audio_transcription.delta", ItemID: "item_user", Delta: "Where is "}},
+		{Event: ConversationItemInputAudioTranscriptionDelta{Type: "conversation.item.input_audio_transcription.delta", ItemID: "item_user", Delta: "my order"}},
+		{Event: ConversationItemInputAudioTranscriptionCompleted{Type: "conversation.item.input_audio_transcription.completed", ItemID: "item_user", Transcript: "Where is my order?"}},
+	}
+	client, _ := dialProfile(t, &MockProfile{Speech: speech})
+
+	deltas := make(chan string, 2)
+	client.OnConversationItemInputAudioTranscriptionDelta(func(e ConversationItemInputAudioTranscriptionDelta) {
+		deltas <- e.Delta
+		if len(deltas) == 2 {
+			// The partial transcript is visible before the completed event
+			if text := client.Transcript().Entries[0].Text; text != "Where is my order" {
+				t.Errorf("partial transcript = %q", text)
+			}
+		}
+	})
+	transcribed := make(chan struct{})
+	client.OnConversationItemInputAudioTranscriptionCompleted(func(ConversationItemInputAudioTranscriptionCompleted) { close(transcribed) })
+
+	if err := client.AppendPCM16(context.Background(), make([]byte, PCM16BytesFor(100, DefaultSampleRate))); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	select {
+	case <-transcribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no transcription")
+	}
+
+	if got := <-deltas + <-deltas; got != "Where is my order" {
+		t.Errorf("deltas = %q", got)
+	}
+	if text := client.Transcript().Entries[0].Text; text != "Where is my order?" {
+		t.Errorf("final transcript = %q", text)
+	}
+}
@@ -0,0 +1,68 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTransportUnsupported is returned by operations that need the WebSocket
+// opened by Dial, such as Reconnect and Ping, on a client created with
+// NewTransportClient.
+var ErrTransportUnsupported = errors.New("azrealtime: operation not supported over this transport")
+
+// Transport carries JSON realtime events for a client created with
+// NewTransportClient, in place of the WebSocket opened by Dial. The webrtc
+// package implements it over a WebRTC data channel.
+type Transport interface {
+	// Send writes one client event. Calls are serialized by the client.
+	Send(ctx context.Context, data []byte) error
+	// Close releases the transport. It is called once, when the client closes.
+	Close() error
+}
+
+// NewTransportClient returns a client that sends its events over t and
+// dispatches the server events handed to the returned deliver function to the
+// same typed callbacks as a client created with Dial. The owner of t calls
+// deliver for each inbound message, from a single goroutine.
+//
+// Connection settings in cfg (endpoint, deployment, credentials) are not used,
+// and neither are AutoReconnect, ReconnectOnErrors and ReconnectOnSessionExpiry:
+// Reconnect and Ping return ErrTransportUnsupported. Close the client when the
+// transport goes away.
+func NewTransportClient(cfg Config, t Transport) (c *Client, deliver func(data []byte)) {
+	cfg.AutoReconnect = nil
+	cfg.ReconnectOnErrors = nil
+	cfg.ReconnectOnSessionExpiry = false
+	normalizeLoggers(&cfg)
+
+	c = &Client{cfg: cfg, transport: t, isTransport: true, closedCh: make(chan struct{})}
+	c.report.startedAt = time.Now()
+	c.transcript.startedAt = c.report.startedAt
+	c.log("transport_connected", nil)
+
+	if cfg.StatsHandler != nil {
+		if c.cfg.StatsInterval == 0 {
+			c.cfg.StatsInterval = DefaultStatsInterval
+		}
+		go c.statsLoop()
+	}
+
+	maxBytes := cfg.MaxMessageBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxMessageBytes
+	}
+	deliver = func(data []byte) {
+		select {
+		case <-c.closedCh:
+			return
+		default:
+		}
+		if int64(len(data)) > maxBytes {
+			c.messageTooLarge(int64(len(data)), maxBytes, data[:maxBytes])
+			return
+		}
+		c.handleMessage(data)
+	}
+	return c, deliver
+}
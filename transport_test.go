@@ -0,0 +1,89 @@
+package azrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeTransport records the events a client sends.
+type fakeTransport struct {
+	mu     sync.Mutex
+	sent   []map[string]any
+	closed int
+}
+
+func (f *fakeTransport) Send(_ context.Context, data []byte) error {
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, m)
+	return nil
+}
+
+func (f *fakeTransport) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed++
+	return nil
+}
+
+func TestNewTransportClient(t *testing.T) {
+	tr := &fakeTransport{}
+	client, deliver := NewTransportClient(Config{}, tr)
+
+	var got []string
+	client.OnResponseTextDelta(func(e ResponseTextDelta) { got = append(got, e.Delta) })
+	deliver([]byte(`{"type":"response.text.delta","response_id":"r1","delta":"Hel"}`))
+	deliver([]byte(`{"type":"response.text.delta","response_id":"r1","delta":"lo"}`))
+	if strings.Join(got, "") != "Hello" {
+		t.Errorf("deltas = %q", got)
+	}
+
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{Modalities: []string{"text"}}); err != nil {
+		t.Fatalf("CreateResponse: %v", err)
+	}
+	tr.mu.Lock()
+	if len(tr.sent) != 1 || tr.sent[0]["type"] != "response.create" {
+		t.Errorf("sent = %v", tr.sent)
+	}
+	tr.mu.Unlock()
+
+	if err := client.Reconnect(context.Background()); !errors.Is(err, ErrTransportUnsupported) {
+		t.Errorf("Reconnect = %v, want ErrTransportUnsupported", err)
+	}
+	if _, err := client.Ping(context.Background()); !errors.Is(err, ErrTransportUnsupported) {
+		t.Errorf("Ping = %v, want ErrTransportUnsupported", err)
+	}
+
+	_ = client.Close()
+	_ = client.Close()
+	if tr.closed != 1 {
+		t.Errorf("transport closed %d times, want 1", tr.closed)
+	}
+	if _, err := client.CreateResponse(context.Background(), CreateResponseOptions{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("CreateResponse after Close = %v, want ErrClosed", err)
+	}
+	deliver([]byte(`{"type":"response.text.delta","delta":"!"}`))
+	if len(got) != 2 {
+		t.Error("event delivered after Close was dispatched")
+	}
+}
+
+func TestNewTransportClient_MessageTooLarge(t *testing.T) {
+	client, deliver := NewTransportClient(Config{MaxMessageBytes: 64}, &fakeTransport{})
+	defer client.Close()
+
+	var codes []string
+	client.OnError(func(e ErrorEvent) { codes = append(codes, e.Error.Code) })
+	deliver([]byte(`{"type":"response.text.delta","delta":"` + strings.Repeat("x", 100) + `"}`))
+	if len(codes) != 1 || codes[0] != ErrorCodeMessageTooLarge {
+		t.Errorf("errors = %v, want %s", codes, ErrorCodeMessageTooLarge)
+	}
+}
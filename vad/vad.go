@@ -0,0 +1,268 @@
+// Package vad is a lightweight, energy-based voice activity detector for PCM16
+// audio. It lets applications that commit audio manually (turn detection
+// disabled) skip uploading silence, which saves bandwidth and audio tokens:
+//
+//	d, _ := vad.New(vad.Config{
+//		OnSpeechEnd: func() { _ = client.InputCommit(ctx) },
+//	})
+//	for chunk := range mic {
+//		if speech := d.Filter(chunk); len(speech) > 0 {
+//			_ = client.AppendPCM16(ctx, speech)
+//		}
+//	}
+//
+// Each frame's RMS level is compared with an absolute threshold and, when
+// Adaptive is set, with a running estimate of the background noise. Audio just
+// before the onset (Prefix) and after the last voiced frame (Hangover) is kept
+// so words are not clipped. The detector is deliberately simple: it does not
+// distinguish speech from other loud sounds, so server-side VAD remains the
+// better choice when its cost is acceptable.
+package vad
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Defaults applied by New for zero Config fields.
+const (
+	DefaultSampleRate  = 24000
+	DefaultFrame       = 20 * time.Millisecond
+	DefaultThresholdDB = -45.0
+	DefaultMarginDB    = 10.0
+	DefaultPrefix      = 200 * time.Millisecond
+	DefaultHangover    = 400 * time.Millisecond
+	DefaultMinSpeech   = 60 * time.Millisecond
+	DefaultNoiseWindow = 1500 * time.Millisecond
+)
+
+// silenceDB is the level reported for digital silence.
+const silenceDB = -100.0
+
+// Config configures a Detector.
+type Config struct {
+	// SampleRate of the PCM16 mono input. Default: DefaultSampleRate.
+	SampleRate int
+
+	// Frame is the analysis window. Default: DefaultFrame.
+	Frame time.Duration
+
+	// ThresholdDB is the minimum frame level, in dBFS, counted as voiced.
+	// Default: DefaultThresholdDB.
+	ThresholdDB float64
+
+	// Adaptive additionally requires voiced frames to be MarginDB above the
+	// estimated noise floor, which helps in noisy rooms.
+	Adaptive bool
+
+	// MarginDB is the required distance above the noise floor when Adaptive.
+	// Default: DefaultMarginDB.
+	MarginDB float64
+
+	// NoiseWindow is the history the noise floor is estimated over when
+	// Adaptive: the floor is the quietest frame in the window, so pauses
+	// between words keep it at the background level while a steady noise
+	// becomes the floor once it has lasted this long.
+	// Default: DefaultNoiseWindow.
+	NoiseWindow time.Duration
+
+	// Prefix is the audio kept from before speech onset. Default: DefaultPrefix.
+	Prefix time.Duration
+
+	// Hangover is how long speech continues after the last voiced frame.
+	// Default: DefaultHangover.
+	Hangover time.Duration
+
+	// MinSpeech is the run of voiced frames needed to start speech, which
+	// rejects clicks. Default: DefaultMinSpeech.
+	MinSpeech time.Duration
+
+	// OnSpeechStart and OnSpeechEnd, if set, are called from Filter on
+	// transitions.
+	OnSpeechStart func()
+	OnSpeechEnd   func()
+}
+
+// Detector classifies a PCM16 stream into speech and silence. It is not safe
+// for concurrent use.
+type Detector struct {
+	cfg        Config
+	frameBytes int
+	prefix     int // Frames
+	hangover   int
+	minSpeech  int
+	window     int
+
+	carry      []byte
+	preroll    [][]byte
+	speaking   bool
+	voicedRun  int
+	silentRun  int
+	levels     []float64 // Recent frame levels, for the noise floor
+	noiseFloor float64
+}
+
+// New creates a Detector, applying defaults for zero fields.
+func New(cfg Config) (*Detector, error) {
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = DefaultSampleRate
+	}
+	if cfg.Frame == 0 {
+		cfg.Frame = DefaultFrame
+	}
+	if cfg.ThresholdDB == 0 {
+		cfg.ThresholdDB = DefaultThresholdDB
+	}
+	if cfg.MarginDB == 0 {
+		cfg.MarginDB = DefaultMarginDB
+	}
+	if cfg.Prefix == 0 {
+		cfg.Prefix = DefaultPrefix
+	}
+	if cfg.Hangover == 0 {
+		cfg.Hangover = DefaultHangover
+	}
+	if cfg.MinSpeech == 0 {
+		cfg.MinSpeech = DefaultMinSpeech
+	}
+	if cfg.NoiseWindow == 0 {
+		cfg.NoiseWindow = DefaultNoiseWindow
+	}
+	if cfg.SampleRate < 0 || cfg.Frame < 0 || cfg.Prefix < 0 || cfg.Hangover < 0 || cfg.MinSpeech < 0 || cfg.NoiseWindow < 0 {
+		return nil, fmt.Errorf("vad: negative configuration value")
+	}
+
+	samples := int(int64(cfg.SampleRate) * int64(cfg.Frame) / int64(time.Second))
+	if samples == 0 {
+		return nil, fmt.Errorf("vad: frame %v is shorter than one sample at %d Hz", cfg.Frame, cfg.SampleRate)
+	}
+	frames := func(d time.Duration) int { return int((d + cfg.Frame - 1) / cfg.Frame) }
+	return &Detector{
+		cfg:        cfg,
+		frameBytes: 2 * samples,
+		prefix:     frames(cfg.Prefix),
+		hangover:   frames(cfg.Hangover),
+		minSpeech:  max(frames(cfg.MinSpeech), 1),
+		window:     max(frames(cfg.NoiseWindow), 1),
+		noiseFloor: cfg.ThresholdDB - cfg.MarginDB,
+	}, nil
+}
+
+// Filter feeds pcm to the detector and returns the audio to upload: speech,
+// with its prefix and hangover. Input that does not fill a frame is held until
+// the next call.
+func (d *Detector) Filter(pcm []byte) []byte {
+	if len(d.carry) > 0 {
+		pcm = append(d.carry, pcm...)
+		d.carry = nil
+	}
+	var out []byte
+	for len(pcm) >= d.frameBytes {
+		out = d.frame(pcm[:d.frameBytes], out)
+		pcm = pcm[d.frameBytes:]
+	}
+	if len(pcm) > 0 {
+		d.carry = append([]byte(nil), pcm...)
+	}
+	return out
+}
+
+func (d *Detector) frame(f []byte, out []byte) []byte {
+	if d.cfg.Adaptive {
+		d.trackNoise(LevelDB(f))
+	}
+	voiced := d.IsVoiced(f)
+
+	if !d.speaking {
+		d.preroll = append(d.preroll, f)
+		if len(d.preroll) > d.prefix+d.minSpeech {
+			d.preroll = d.preroll[1:]
+		}
+		if !voiced {
+			d.voicedRun = 0
+			return out
+		}
+		if d.voicedRun++; d.voicedRun < d.minSpeech {
+			return out
+		}
+		d.speaking, d.silentRun = true, 0
+		for _, p := range d.preroll {
+			out = append(out, p...)
+		}
+		d.preroll = nil
+		if d.cfg.OnSpeechStart != nil {
+			d.cfg.OnSpeechStart()
+		}
+		return out
+	}
+
+	out = append(out, f...)
+	if voiced {
+		d.silentRun = 0
+		return out
+	}
+	if d.silentRun++; d.silentRun >= d.hangover {
+		d.speaking, d.voicedRun = false, 0
+		if d.cfg.OnSpeechEnd != nil {
+			d.cfg.OnSpeechEnd()
+		}
+	}
+	return out
+}
+
+// trackNoise updates the noise floor to the quietest level in the window.
+func (d *Detector) trackNoise(level float64) {
+	d.levels = append(d.levels, level)
+	if len(d.levels) > d.window {
+		d.levels = d.levels[1:]
+	}
+	floor := level
+	for _, l := range d.levels {
+		floor = min(floor, l)
+	}
+	d.noiseFloor = floor
+}
+
+// IsVoiced classifies a single frame by level alone, without the prefix,
+// hangover and minimum-length smoothing applied by Filter.
+func (d *Detector) IsVoiced(frame []byte) bool {
+	level := LevelDB(frame)
+	if level < d.cfg.ThresholdDB {
+		return false
+	}
+	return !d.cfg.Adaptive || level >= d.noiseFloor+d.cfg.MarginDB
+}
+
+// Speaking reports whether the detector is currently inside a speech segment.
+func (d *Detector) Speaking() bool { return d.speaking }
+
+// NoiseFloorDB returns the current background level estimate (Adaptive only).
+func (d *Detector) NoiseFloorDB() float64 { return d.noiseFloor }
+
+// Reset clears all state, e.g. between turns or after a reconnect.
+func (d *Detector) Reset() {
+	d.carry, d.preroll, d.levels = nil, nil, nil
+	d.speaking, d.voicedRun, d.silentRun = false, 0, 0
+	d.noiseFloor = d.cfg.ThresholdDB - d.cfg.MarginDB
+}
+
+// LevelDB returns the RMS level of PCM16 little-endian audio in dBFS, from
+// about -96 for the quietest signal to 0 for a full-scale square wave. Empty or
+// all-zero input returns -100.
+func LevelDB(pcm []byte) float64 {
+	n := len(pcm) / 2
+	if n == 0 {
+		return silenceDB
+	}
+	var sum float64
+	for i := 0; i < n; i++ {
+		s := float64(int16(binary.LittleEndian.Uint16(pcm[2*i:])))
+		sum += s * s
+	}
+	if sum == 0 {
+		return silenceDB
+	}
+	return 20 * math.Log10(math.Sqrt(sum/float64(n))/32768)
+}
@@ -0,0 +1,135 @@
+package vad
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+const rate = 16000
+
+// tone returns d of a 440 Hz sine at amplitude amp (0-1).
+func tone(d time.Duration, amp float64) []byte {
+	n := int(int64(rate) * int64(d) / int64(time.Second))
+	out := make([]byte, 2*n)
+	for i := 0; i < n; i++ {
+		v := amp * 32767 * math.Sin(2*math.Pi*440*float64(i)/rate)
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(int16(v)))
+	}
+	return out
+}
+
+func silence(d time.Duration) []byte {
+	return make([]byte, 2*int(int64(rate)*int64(d)/int64(time.Second)))
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func ms(pcm []byte) time.Duration {
+	return time.Duration(len(pcm)/2) * time.Second / rate
+}
+
+func TestLevelDB(t *testing.T) {
+	if got := LevelDB(nil); got != silenceDB {
+		t.Errorf("LevelDB(nil) = %v", got)
+	}
+	if got := LevelDB(silence(20 * time.Millisecond)); got != silenceDB {
+		t.Errorf("LevelDB(silence) = %v", got)
+	}
+	// A full-scale sine is -3 dBFS, half scale about -9
+	if got := LevelDB(tone(100*time.Millisecond, 1)); math.Abs(got+3.01) > 0.1 {
+		t.Errorf("full-scale sine = %.2f dBFS, want -3", got)
+	}
+	if got := LevelDB(tone(100*time.Millisecond, 0.5)); math.Abs(got+9.03) > 0.1 {
+		t.Errorf("half-scale sine = %.2f dBFS, want -9", got)
+	}
+}
+
+func TestDetector_Filter(t *testing.T) {
+	var starts, ends int
+	d, err := New(Config{
+		SampleRate:    rate,
+		Prefix:        100 * time.Millisecond,
+		Hangover:      200 * time.Millisecond,
+		OnSpeechStart: func() { starts++ },
+		OnSpeechEnd:   func() { ends++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := concat(silence(time.Second), tone(500*time.Millisecond, 0.3), silence(time.Second))
+	var out []byte
+	for i := 0; i < len(in); i += 333 { // Odd chunking exercises the carry
+		out = append(out, d.Filter(in[i:min(i+333, len(in))])...)
+	}
+
+	// Speech plus 100ms prefix and 200ms hangover
+	if got := ms(out); got != 800*time.Millisecond {
+		t.Errorf("kept %v of audio, want 800ms", got)
+	}
+	if starts != 1 || ends != 1 {
+		t.Errorf("starts = %d, ends = %d, want 1 each", starts, ends)
+	}
+	if d.Speaking() {
+		t.Error("still speaking after trailing silence")
+	}
+}
+
+func TestDetector_RejectsClicks(t *testing.T) {
+	d, _ := New(Config{SampleRate: rate})
+	out := d.Filter(concat(silence(200*time.Millisecond), tone(20*time.Millisecond, 0.8), silence(200*time.Millisecond)))
+	if len(out) != 0 {
+		t.Errorf("a 20ms click produced %v of audio", ms(out))
+	}
+}
+
+func TestDetector_QuietSignalBelowThreshold(t *testing.T) {
+	d, _ := New(Config{SampleRate: rate})
+	if out := d.Filter(tone(time.Second, 0.001)); len(out) != 0 { // About -63 dBFS
+		t.Errorf("quiet tone produced %v of audio", ms(out))
+	}
+}
+
+func TestDetector_Adaptive(t *testing.T) {
+	hum := tone(time.Second, 0.02) // About -37 dBFS, above the absolute threshold
+
+	fixed, _ := New(Config{SampleRate: rate})
+	if out := fixed.Filter(hum); len(out) == 0 {
+		t.Fatal("fixed threshold should treat the hum as speech")
+	}
+
+	d, _ := New(Config{SampleRate: rate, Adaptive: true, NoiseWindow: 500 * time.Millisecond})
+	d.Filter(silence(100 * time.Millisecond))
+	d.Filter(hum) // The floor rises to the hum once it fills the window
+	if floor := d.NoiseFloorDB(); math.Abs(floor+37) > 1 {
+		t.Errorf("noise floor = %.1f dBFS, want the hum level", floor)
+	}
+	if out := d.Filter(hum); len(out) != 0 {
+		t.Errorf("adaptive detector kept %v of steady hum", ms(out))
+	}
+	if out := d.Filter(tone(300*time.Millisecond, 0.5)); len(out) == 0 {
+		t.Error("adaptive detector missed speech above the hum")
+	}
+
+	d.Reset()
+	if d.Speaking() {
+		t.Error("Speaking after Reset")
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := New(Config{SampleRate: 8000, Frame: time.Microsecond}); err == nil {
+		t.Error("expected error for a frame shorter than one sample")
+	}
+	if _, err := New(Config{Hangover: -time.Second}); err == nil {
+		t.Error("expected error for negative hangover")
+	}
+}
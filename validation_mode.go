@@ -0,0 +1,48 @@
+package azrealtime
+
+import "errors"
+
+// ValidationMode controls how the client treats payloads that fail its local
+// validation (ValidateSession, ValidateCreateResponseOptions) before sending.
+// The local rules, such as the voice list or the instructions length, can lag
+// behind the service.
+type ValidationMode int
+
+const (
+	// ValidationStrict rejects invalid payloads with a SendError (the default).
+	ValidationStrict ValidationMode = iota
+	// ValidationWarn logs the problems as a "validation_warning" event and sends the payload anyway.
+	ValidationWarn
+	// ValidationOff skips local validation; the server reports problems as error events.
+	ValidationOff
+)
+
+// validate runs check according to Config.ValidationMode. It returns the
+// error to send back to the caller, if any.
+func (c *Client) validate(op string, check func() error) error {
+	switch c.cfg.ValidationMode {
+	case ValidationOff:
+		return nil
+	case ValidationWarn:
+		err := check()
+		if err == nil {
+			return nil
+		}
+		fields := map[string]any{"op": op, "error": err.Error()}
+		var verrs ValidationErrors
+		if errors.As(err, &verrs) {
+			names := make([]string, len(verrs))
+			for i, e := range verrs {
+				names[i] = e.Field
+			}
+			fields["fields"] = names
+		}
+		c.logWarn("validation_warning", fields)
+		return nil
+	default:
+		if err := check(); err != nil {
+			return NewSendError(op, "", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+package azrealtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_ValidationMode(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, tc := range []struct {
+		mode    ValidationMode
+		wantErr bool
+		wantLog bool
+	}{
+		{ValidationStrict, true, false},
+		{ValidationWarn, false, true},
+		{ValidationOff, false, false},
+	} {
+		mockServer := NewMockServer(t)
+		cfg := CreateMockConfig(mockServer.URL())
+		cfg.ValidationMode = tc.mode
+		var mu sync.Mutex
+		var warnings []map[string]any
+		cfg.Logger = func(event string, fields map[string]any) {
+			if event == "WARN: validation_warning" {
+				mu.Lock()
+				warnings = append(warnings, fields)
+				mu.Unlock()
+			}
+		}
+		client, err := Dial(ctx, cfg)
+		if err != nil {
+			mockServer.Close()
+			t.Fatalf("failed to dial: %v", err)
+		}
+		sent := recordOutbound(client)
+
+		err = client.SessionUpdate(ctx, Session{Voice: Ptr("newvoice")})
+		var sendErr *SendError
+		if got := errors.As(err, &sendErr); got != tc.wantErr {
+			t.Errorf("mode %d: SessionUpdate error = %v", tc.mode, err)
+		}
+		wantSent := 1
+		if tc.wantErr {
+			wantSent = 0
+		}
+		if n := len(sent.ofType("session.update")); n != wantSent {
+			t.Errorf("mode %d: sent %d session.update, want %d", tc.mode, n, wantSent)
+		}
+
+		mu.Lock()
+		if got := len(warnings) > 0; got != tc.wantLog {
+			t.Errorf("mode %d: logged warnings %v", tc.mode, warnings)
+		} else if got && warnings[0]["op"] != "session.update" {
+			t.Errorf("mode %d: warning fields = %v", tc.mode, warnings[0])
+		}
+		mu.Unlock()
+
+		client.Close()
+		mockServer.Close()
+	}
+}
+
+func TestValidateConfig_ValidationMode(t *testing.T) {
+	cfg := CreateMockConfig("ws://localhost")
+	cfg.ValidationMode = ValidationOff + 1
+	if err := ValidateConfig(cfg); err == nil {
+		t.Error("expected error for unknown ValidationMode")
+	}
+}
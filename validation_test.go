@@ -2,7 +2,10 @@ package azrealtime
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -83,6 +86,43 @@ func TestAppendPCM16_Validation(t *testing.T) {
 	}
 }
 
+func TestAppendPCM16_AutoChunkAudio(t *testing.T) {
+	mockServer := NewMockServer(t)
+	defer mockServer.Close()
+
+	config := CreateMockConfig(mockServer.URL())
+	config.AutoChunkAudio = true
+	ctx := context.Background()
+
+	client, err := Dial(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	var appends atomic.Int32
+	client.OnRawEvent(func(dir EventDirection, data []byte) {
+		var env envelope
+		_ = json.Unmarshal(data, &env)
+		if dir == DirectionOutbound && env.Type == "input_audio_buffer.append" {
+			appends.Add(1)
+		}
+	})
+
+	// 2.5 MB: split into two full chunks and a half chunk
+	data := make([]byte, 2*MaxAppendBytes+MaxAppendBytes/2)
+	if err := client.AppendPCM16(ctx, data); err != nil {
+		t.Fatalf("AppendPCM16: %v", err)
+	}
+	if got := appends.Load(); got != 3 {
+		t.Errorf("sent %d append messages, want 3", got)
+	}
+	want := float64(len(data)) / (2 * DefaultSampleRate)
+	if got := client.Stats().AudioInSeconds; got < want-0.001 || got > want+0.001 {
+		t.Errorf("AudioInSeconds = %v, want %v", got, want)
+	}
+}
+
 func TestValidateSession(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -107,6 +147,13 @@ func TestValidateSession(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "newer voice",
+			session: Session{
+				Voice: Ptr(VoiceMarin),
+			},
+			expectError: false,
+		},
 		{
 			name: "invalid voice",
 			session: Session{
@@ -192,6 +239,66 @@ func TestValidateSession(t *testing.T) {
 			expectError: true,
 			errorMsg:    "instructions too long",
 		},
+		{
+			name: "valid response defaults",
+			session: Session{
+				Modalities:      []string{"text"},
+				Temperature:     Ptr(0.8),
+				MaxOutputTokens: 1024,
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid session modality",
+			session: Session{
+				Modalities: []string{"text", "video"},
+			},
+			expectError: true,
+			errorMsg:    "invalid modality",
+		},
+		{
+			name: "session temperature out of range",
+			session: Session{
+				Temperature: Ptr(0.2),
+			},
+			expectError: true,
+			errorMsg:    "temperature must be between 0.6 and 1.2",
+		},
+		{
+			name: "session max output tokens out of range",
+			session: Session{
+				MaxOutputTokens: -5,
+			},
+			expectError: true,
+			errorMsg:    "max_response_output_tokens must be between 1 and 4096",
+		},
+		{
+			name: "valid gpt-4o transcription",
+			session: Session{
+				InputTranscription: &InputTranscription{
+					Model:    TranscriptionModelGPT4oMiniTranscribe,
+					Language: "de",
+					Prompt:   Ptr("A call about a delayed parcel."),
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid transcription model",
+			session: Session{
+				InputTranscription: &InputTranscription{Model: "whisper-2"},
+			},
+			expectError: true,
+			errorMsg:    "invalid transcription model",
+		},
+		{
+			name: "invalid transcription language",
+			session: Session{
+				InputTranscription: &InputTranscription{Model: TranscriptionModelWhisper1, Language: "English"},
+			},
+			expectError: true,
+			errorMsg:    "invalid language",
+		},
 	}
 
 	for _, tt := range tests {
@@ -215,6 +322,26 @@ func TestValidateSession(t *testing.T) {
 	}
 }
 
+func TestValidateSession_Voices(t *testing.T) {
+	saved := Voices
+	defer func() { Voices = saved }()
+
+	s := Session{Voice: Ptr("aurora")}
+	if err := ValidateSession(s); err == nil {
+		t.Fatal("expected unknown voice to be rejected")
+	}
+
+	Voices = append(append([]string(nil), saved...), "aurora")
+	if err := ValidateSession(s); err != nil {
+		t.Errorf("voice added to Voices rejected: %v", err)
+	}
+
+	Voices = nil
+	if err := ValidateSession(Session{Voice: Ptr("anything")}); err != nil {
+		t.Errorf("voice rejected with validation disabled: %v", err)
+	}
+}
+
 func TestValidateCreateResponseOptions(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -281,6 +408,56 @@ func TestValidateCreateResponseOptions(t *testing.T) {
 			expectError: true,
 			errorMsg:    "conversation ID too long",
 		},
+		{
+			name: "valid tool overrides",
+			opts: CreateResponseOptions{
+				MaxOutputTokens: MaxTokensInf,
+				Tools:           []any{Tool{Name: "lookup_order"}, ToolChoiceFunction("cancel_order")},
+				ToolChoice:      ToolChoiceFunction("lookup_order"),
+			},
+			expectError: false,
+		},
+		{
+			name: "max output tokens too high",
+			opts: CreateResponseOptions{
+				MaxOutputTokens: 5000,
+			},
+			expectError: true,
+			errorMsg:    "max_output_tokens must be between 1 and 4096",
+		},
+		{
+			name: "invalid tool choice",
+			opts: CreateResponseOptions{
+				ToolChoice: "always",
+			},
+			expectError: true,
+			errorMsg:    "invalid tool_choice",
+		},
+		{
+			name: "tool choice not in tools",
+			opts: CreateResponseOptions{
+				Tools:      []any{Tool{Name: "lookup_order"}},
+				ToolChoice: ToolChoiceFunction("refund"),
+			},
+			expectError: true,
+			errorMsg:    "is not in tools",
+		},
+		{
+			name: "duplicate tool name",
+			opts: CreateResponseOptions{
+				Tools: []any{Tool{Name: "lookup_order"}, Tool{Name: "lookup_order"}},
+			},
+			expectError: true,
+			errorMsg:    "duplicate tool name",
+		},
+		{
+			name: "tool without name",
+			opts: CreateResponseOptions{
+				Tools: []any{map[string]any{"type": "function"}},
+			},
+			expectError: true,
+			errorMsg:    "tool name is required",
+		},
 	}
 
 	for _, tt := range tests {
@@ -386,6 +563,40 @@ func ErrorAs(err error, target interface{}) bool {
 	return false
 }
 
+func TestValidationErrors_FieldPaths(t *testing.T) {
+	err := ValidateSession(Session{
+		Voice: Ptr("robot"),
+		TurnDetection: &TurnDetection{
+			Type:      "server_vad",
+			Threshold: 1.5,
+		},
+	})
+
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("expected ValidationErrors, got %T", err)
+	}
+	if len(verrs) != 2 {
+		t.Fatalf("expected 2 validation errors, got %d: %v", len(verrs), verrs)
+	}
+	if verrs[0].Field != "voice" || verrs[0].Code != ValidationCodeInvalidValue {
+		t.Errorf("unexpected first error: %+v", verrs[0])
+	}
+	if verrs[1].Field != "turn_detection.threshold" || verrs[1].Code != ValidationCodeOutOfRange {
+		t.Errorf("unexpected second error: %+v", verrs[1])
+	}
+
+	// Field paths survive wrapping in SendError
+	client := &Client{}
+	_, err = client.CreateResponse(context.Background(), CreateResponseOptions{Modalities: []string{"text", "video"}})
+	if !errors.As(err, &verrs) {
+		t.Fatalf("expected ValidationErrors inside SendError, got %v", err)
+	}
+	if len(verrs) != 1 || verrs[0].Field != "modalities[1]" {
+		t.Errorf("unexpected validation errors: %+v", verrs)
+	}
+}
+
 func BenchmarkValidateSession(b *testing.B) {
 	session := Session{
 		Voice:             Ptr("alloy"),
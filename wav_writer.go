@@ -0,0 +1,109 @@
+package azrealtime
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// WAVWriter streams PCM16 audio into a WAV file as it arrives. The header is
+// written with placeholder lengths up front and patched on Close, so a long
+// response never has to be held in memory:
+//
+//	f, _ := os.Create("reply.wav")
+//	wav, _ := azrealtime.NewWAVWriter(f, azrealtime.DefaultSampleRate)
+//	assembler := azrealtime.NewStreamingAudioAssembler(wav)
+//	// ... feed response.audio.delta events to assembler ...
+//	wav.Close()
+//	f.Close()
+//
+// A WAVWriter is not safe for concurrent use.
+type WAVWriter struct {
+	w        io.WriteSeeker
+	start    int64 // Offset of the header in w
+	dataLen  int64
+	closed   bool
+	writeErr error
+}
+
+// NewWAVWriter writes a mono PCM16 WAV header at sampleRate to w and returns a
+// writer for the audio data.
+func NewWAVWriter(w io.WriteSeeker, sampleRate int) (*WAVWriter, error) {
+	return NewWAVWriterChannels(w, sampleRate, 1)
+}
+
+// NewWAVWriterChannels is like NewWAVWriter for interleaved audio with the given
+// number of channels.
+func NewWAVWriterChannels(w io.WriteSeeker, sampleRate, channels int) (*WAVWriter, error) {
+	if sampleRate <= 0 {
+		return nil, fmt.Errorf("azrealtime: invalid WAV sample rate %d", sampleRate)
+	}
+	if channels < 1 {
+		return nil, fmt.Errorf("azrealtime: invalid WAV channel count %d", channels)
+	}
+	start, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, wavHeaderSize)
+	putWAVHeader(header, 0, sampleRate, channels)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &WAVWriter{w: w, start: start}, nil
+}
+
+// Write appends PCM16 little-endian audio.
+func (ww *WAVWriter) Write(pcm []byte) (int, error) {
+	if ww.closed {
+		return 0, errors.New("azrealtime: write to closed WAVWriter")
+	}
+	if ww.writeErr != nil {
+		return 0, ww.writeErr
+	}
+	if ww.dataLen+int64(len(pcm)) > math.MaxUint32-36 {
+		return 0, errors.New("azrealtime: WAV data exceeds 4 GiB")
+	}
+	n, err := ww.w.Write(pcm)
+	ww.dataLen += int64(n)
+	if err != nil {
+		ww.writeErr = err
+	}
+	return n, err
+}
+
+// Len returns the number of audio bytes written so far.
+func (ww *WAVWriter) Len() int64 { return ww.dataLen }
+
+// Close patches the RIFF and data chunk lengths and leaves w positioned at the
+// end of the file. It does not close the underlying writer.
+func (ww *WAVWriter) Close() error {
+	if ww.closed {
+		return nil
+	}
+	ww.closed = true
+
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(36+ww.dataLen))
+	if err := ww.patch(4, buf[:]); err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint32(buf[:], uint32(ww.dataLen))
+	if err := ww.patch(40, buf[:]); err != nil {
+		return err
+	}
+	_, err := ww.w.Seek(0, io.SeekEnd)
+	return err
+}
+
+func (ww *WAVWriter) patch(offset int64, b []byte) error {
+	if _, err := ww.w.Seek(ww.start+offset, io.SeekStart); err != nil {
+		return fmt.Errorf("azrealtime: patch WAV header: %w", err)
+	}
+	if _, err := ww.w.Write(b); err != nil {
+		return fmt.Errorf("azrealtime: patch WAV header: %w", err)
+	}
+	return nil
+}
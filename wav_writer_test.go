@@ -0,0 +1,99 @@
+package azrealtime
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWAVWriter_MatchesWAVFromPCM16(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wav")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ww, err := NewWAVWriter(f, DefaultSampleRate)
+	if err != nil {
+		t.Fatalf("NewWAVWriter: %v", err)
+	}
+
+	// Audio arrives as deltas through a streaming assembler
+	a := NewStreamingAudioAssembler(ww)
+	pcm := ramp(1000, 7)
+	for i := 0; i < len(pcm); i += 300 {
+		chunk := pcm[i:min(i+300, len(pcm))]
+		if err := a.OnDelta(ResponseAudioDelta{ResponseID: "r1", DeltaBase64: base64.StdEncoding.EncodeToString(chunk)}); err != nil {
+			t.Fatalf("OnDelta: %v", err)
+		}
+	}
+	if ww.Len() != int64(len(pcm)) {
+		t.Errorf("Len = %d, want %d", ww.Len(), len(pcm))
+	}
+	if err := ww.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := ww.Write([]byte{0, 0}); err == nil {
+		t.Error("expected error writing after Close")
+	}
+
+	// The writer is left at the end, so appending continues the file
+	if pos, _ := f.Seek(0, 1); pos != int64(wavHeaderSize+len(pcm)) {
+		t.Errorf("file position = %d, want end of file", pos)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := WAVFromPCM16Mono(pcm, DefaultSampleRate); !bytes.Equal(got, want) {
+		t.Error("streamed WAV differs from WAVFromPCM16Mono output")
+	}
+}
+
+func TestWAVWriter_Offset(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	prefix := []byte("prefix")
+	if _, err := f.Write(prefix); err != nil {
+		t.Fatal(err)
+	}
+
+	ww, err := NewWAVWriterChannels(f, 16000, 2)
+	if err != nil {
+		t.Fatalf("NewWAVWriterChannels: %v", err)
+	}
+	pcm := InterleavePCM16Stereo(ramp(10, 1), ramp(10, -1))
+	if _, err := ww.Write(pcm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ww.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := os.ReadFile(f.Name())
+	want := append(prefix, WAVFromPCM16(pcm, 16000, 2)...)
+	if !bytes.Equal(got, want) {
+		t.Error("header not patched relative to its starting offset")
+	}
+}
+
+func TestNewWAVWriter_Invalid(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := NewWAVWriter(f, 0); err == nil {
+		t.Error("expected error for zero sample rate")
+	}
+	if _, err := NewWAVWriterChannels(f, 24000, 0); err == nil {
+		t.Error("expected error for zero channels")
+	}
+}
@@ -0,0 +1,216 @@
+// Package audiobridge converts between the PCM16 audio spoken by the WebSocket
+// client and the Opus audio carried by WebRTC tracks, so a relay can feed
+// assistant replies into a TrackLocalStaticSample and transcribe or forward
+// what arrives on a TrackRemote.
+//
+// Building with cgo and the "opus" tag adds OpusEncoder and OpusDecoder,
+// backed by the system libopus. Otherwise bring a codec: Encoder and Decoder
+// match the methods of gopkg.in/hraban/opus.v2, so its types can be passed in
+// directly, and any other implementation with the same shape works too:
+//
+//	enc, _ := audiobridge.NewOpusEncoder() // go build -tags opus
+//	defer enc.Close()
+//	track, _ := webrtc.CreateRelayAudioTrack()
+//	out, _ := audiobridge.NewSender(enc, track)
+//	client.OnResponseAudioDelta(func(e azrealtime.ResponseAudioDelta) { out.WriteDelta(e) })
+//	client.OnResponseAudioDone(func(azrealtime.ResponseAudioDone) { out.Flush() })
+//
+// Opus runs at 48 kHz; audio is resampled to and from azrealtime.DefaultSampleRate.
+// Only mono is supported.
+package audiobridge
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pion/webrtc/v3/pkg/media"
+
+	"github.com/enesunal-m/azrealtime"
+)
+
+// OpusSampleRate is the clock rate of Opus in WebRTC.
+const OpusSampleRate = 48000
+
+// FrameDuration is the duration of each Opus packet produced by Sender.
+const FrameDuration = 20 * time.Millisecond
+
+// frameSamples is the number of 48 kHz samples in one frame.
+const frameSamples = OpusSampleRate * int(FrameDuration/time.Millisecond) / 1000
+
+// maxFrameSamples is the longest Opus packet (120ms) at 48 kHz.
+const maxFrameSamples = OpusSampleRate * 120 / 1000
+
+// maxPacketBytes bounds an encoded packet; 20ms of voice is far smaller.
+const maxPacketBytes = 4000
+
+// Encoder encodes one frame of 48 kHz mono PCM into an Opus packet, returning
+// the packet length.
+type Encoder interface {
+	Encode(pcm []int16, data []byte) (int, error)
+}
+
+// Decoder decodes an Opus packet into 48 kHz mono PCM, returning the number of
+// samples.
+type Decoder interface {
+	Decode(data []byte, pcm []int16) (int, error)
+}
+
+// SampleWriter receives encoded packets. *webrtc.TrackLocalStaticSample
+// implements it.
+type SampleWriter interface {
+	WriteSample(s media.Sample) error
+}
+
+// Sender encodes PCM16 audio at DefaultSampleRate into 20ms Opus samples and
+// writes them to a track. A Sender is not safe for concurrent use.
+type Sender struct {
+	enc Encoder
+	out SampleWriter
+	rs  *azrealtime.Resampler
+
+	pending []int16 // 48 kHz samples not yet forming a whole frame
+	packet  []byte
+}
+
+// NewSender creates a Sender that encodes with enc and writes to out.
+func NewSender(enc Encoder, out SampleWriter) (*Sender, error) {
+	rs, err := azrealtime.NewResampler(azrealtime.DefaultSampleRate, OpusSampleRate)
+	if err != nil {
+		return nil, err
+	}
+	return &Sender{enc: enc, out: out, rs: rs, packet: make([]byte, maxPacketBytes)}, nil
+}
+
+// WriteDelta encodes the audio of a response.audio.delta event.
+func (s *Sender) WriteDelta(e azrealtime.ResponseAudioDelta) error {
+	pcm, err := base64.StdEncoding.DecodeString(e.DeltaBase64)
+	if err != nil {
+		return fmt.Errorf("audiobridge: decode delta: %w", err)
+	}
+	_, err = s.Write(pcm)
+	return err
+}
+
+// Write encodes PCM16 little-endian audio at DefaultSampleRate. Audio that does
+// not fill a frame is held until the next call or Flush.
+func (s *Sender) Write(pcm []byte) (int, error) {
+	s.pending = appendSamples(s.pending, s.rs.Process(pcm))
+	if err := s.writeFrames(); err != nil {
+		return 0, err
+	}
+	return len(pcm), nil
+}
+
+// Flush encodes any held audio, padded with silence to a whole frame. Call it
+// at the end of each response so its last words are not held back.
+func (s *Sender) Flush() error {
+	s.pending = appendSamples(s.pending, s.rs.Flush())
+	if rem := len(s.pending) % frameSamples; rem != 0 {
+		s.pending = append(s.pending, make([]int16, frameSamples-rem)...)
+	}
+	return s.writeFrames()
+}
+
+func (s *Sender) writeFrames() error {
+	for len(s.pending) >= frameSamples {
+		n, err := s.enc.Encode(s.pending[:frameSamples], s.packet)
+		if err != nil {
+			return fmt.Errorf("audiobridge: encode: %w", err)
+		}
+		s.pending = s.pending[frameSamples:]
+		data := make([]byte, n) // The track may hold on to the sample
+		copy(data, s.packet[:n])
+		if err := s.out.WriteSample(media.Sample{Data: data, Duration: FrameDuration}); err != nil {
+			return err
+		}
+	}
+	if len(s.pending) == 0 {
+		s.pending = nil
+	}
+	return nil
+}
+
+// Receiver decodes Opus packets, e.g. RTP payloads read from a TrackRemote,
+// into PCM16 at DefaultSampleRate ready for Client.AppendPCM16. A Receiver is
+// not safe for concurrent use.
+type Receiver struct {
+	dec Decoder
+	rs  *azrealtime.Resampler
+	buf []int16
+}
+
+// NewReceiver creates a Receiver that decodes with dec.
+func NewReceiver(dec Decoder) (*Receiver, error) {
+	rs, err := azrealtime.NewResampler(OpusSampleRate, azrealtime.DefaultSampleRate)
+	if err != nil {
+		return nil, err
+	}
+	return &Receiver{dec: dec, rs: rs, buf: make([]int16, maxFrameSamples)}, nil
+}
+
+// Decode decodes one Opus packet and returns its audio as PCM16 little-endian
+// at DefaultSampleRate.
+func (r *Receiver) Decode(packet []byte) ([]byte, error) {
+	n, err := r.dec.Decode(packet, r.buf)
+	if err != nil {
+		return nil, fmt.Errorf("audiobridge: decode: %w", err)
+	}
+	pcm := make([]byte, 0, 2*n)
+	for _, v := range r.buf[:n] {
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(v))
+	}
+	return r.rs.Process(pcm), nil
+}
+
+// WAVRecorder decodes Opus samples, e.g. from an OnAudioSample tap in the
+// webrtc or relay packages, into a PCM16 WAV file at DefaultSampleRate. Use
+// webrtc.OggRecorder instead to keep the Opus audio without a codec. A
+// WAVRecorder is not safe for concurrent use.
+type WAVRecorder struct {
+	rx  *Receiver
+	wav *azrealtime.WAVWriter
+}
+
+// NewWAVRecorder writes a WAV header to w and returns a recorder that decodes
+// with dec.
+func NewWAVRecorder(dec Decoder, w io.WriteSeeker) (*WAVRecorder, error) {
+	rx, err := NewReceiver(dec)
+	if err != nil {
+		return nil, err
+	}
+	wav, err := azrealtime.NewWAVWriter(w, azrealtime.DefaultSampleRate)
+	if err != nil {
+		return nil, err
+	}
+	return &WAVRecorder{rx: rx, wav: wav}, nil
+}
+
+// WriteSample decodes one Opus packet and appends its audio.
+func (r *WAVRecorder) WriteSample(s media.Sample) error {
+	pcm, err := r.rx.Decode(s.Data)
+	if err != nil {
+		return err
+	}
+	_, err = r.wav.Write(pcm)
+	return err
+}
+
+// Close writes the remaining audio and patches the WAV header. It does not
+// close the underlying writer.
+func (r *WAVRecorder) Close() error {
+	if _, err := r.wav.Write(r.rx.rs.Flush()); err != nil {
+		return err
+	}
+	return r.wav.Close()
+}
+
+// appendSamples appends PCM16 little-endian bytes to dst as samples.
+func appendSamples(dst []int16, pcm []byte) []int16 {
+	for i := 0; i+1 < len(pcm); i += 2 {
+		dst = append(dst, int16(binary.LittleEndian.Uint16(pcm[i:])))
+	}
+	return dst
+}
@@ -0,0 +1,127 @@
+package audiobridge
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v3/pkg/media"
+
+	"github.com/enesunal-m/azrealtime"
+)
+
+// rawCodec "encodes" by storing samples as little-endian bytes.
+type rawCodec struct{ frames []int }
+
+func (c *rawCodec) Encode(pcm []int16, data []byte) (int, error) {
+	c.frames = append(c.frames, len(pcm))
+	for i, v := range pcm {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(v))
+	}
+	return 2 * len(pcm), nil
+}
+
+func (c *rawCodec) Decode(data []byte, pcm []int16) (int, error) {
+	n := len(data) / 2
+	for i := 0; i < n; i++ {
+		pcm[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	return n, nil
+}
+
+type sampleSink struct{ samples []media.Sample }
+
+func (s *sampleSink) WriteSample(m media.Sample) error {
+	s.samples = append(s.samples, m)
+	return nil
+}
+
+func TestSender_FramesAndFlush(t *testing.T) {
+	codec := &rawCodec{}
+	sink := &sampleSink{}
+	s, err := NewSender(codec, sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 50ms at 24 kHz: two whole 20ms frames, the rest held until Flush
+	pcm := make([]byte, azrealtime.PCM16BytesFor(50, azrealtime.DefaultSampleRate))
+	if err := s.WriteDelta(azrealtime.ResponseAudioDelta{DeltaBase64: base64.StdEncoding.EncodeToString(pcm)}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.samples) != 2 {
+		t.Fatalf("got %d samples before Flush, want 2", len(sink.samples))
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.samples) != 3 {
+		t.Fatalf("got %d samples after Flush, want 3", len(sink.samples))
+	}
+	for i, m := range sink.samples {
+		if m.Duration != 20*time.Millisecond || len(m.Data) != 2*frameSamples {
+			t.Errorf("sample %d: duration %v, %d bytes", i, m.Duration, len(m.Data))
+		}
+	}
+	for _, n := range codec.frames {
+		if n != frameSamples {
+			t.Errorf("encoder got a %d-sample frame, want %d", n, frameSamples)
+		}
+	}
+}
+
+func TestReceiver_Resamples(t *testing.T) {
+	r, err := NewReceiver(&rawCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two 20ms packets at 48 kHz become 40ms at 24 kHz
+	packet := make([]byte, 2*frameSamples)
+	var out []byte
+	for i := 0; i < 2; i++ {
+		pcm, err := r.Decode(packet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, pcm...)
+	}
+	want := azrealtime.PCM16BytesFor(40, azrealtime.DefaultSampleRate)
+	if len(out) < want-4 || len(out) > want {
+		t.Errorf("decoded %d bytes, want about %d", len(out), want)
+	}
+}
+
+func TestWAVRecorder(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "rec-*.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rec, err := NewWAVRecorder(&rawCodec{}, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := rec.WriteSample(media.Sample{Data: make([]byte, 2*frameSamples), Duration: FrameDuration}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 100ms at 48 kHz becomes 100ms at 24 kHz after the 44-byte header
+	want := azrealtime.PCM16BytesFor(100, azrealtime.DefaultSampleRate)
+	if string(b[:4]) != "RIFF" || len(b)-44 != want {
+		t.Errorf("got %d audio bytes, want %d", len(b)-44, want)
+	}
+	if got := binary.LittleEndian.Uint32(b[40:]); int(got) != want {
+		t.Errorf("data chunk length %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+//go:build opus && cgo
+
+package audiobridge
+
+/*
+#cgo pkg-config: opus
+#include <opus.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// ErrCodecClosed is returned by an OpusEncoder or OpusDecoder used after Close.
+var ErrCodecClosed = errors.New("audiobridge: opus codec closed")
+
+// OpusEncoder is an Encoder backed by libopus, for 48 kHz mono voice. It is
+// only built with cgo and the "opus" build tag, and needs libopus and its
+// pkg-config file (libopus-dev, opus-devel or brew's opus) at build time:
+//
+//	go build -tags opus ./...
+//
+// An OpusEncoder is not safe for concurrent use, except for Close.
+type OpusEncoder struct {
+	mu sync.Mutex
+	st *C.OpusEncoder
+}
+
+// NewOpusEncoder creates a mono encoder at OpusSampleRate tuned for speech.
+// Close it to free the libopus state.
+func NewOpusEncoder() (*OpusEncoder, error) {
+	var code C.int
+	st := C.opus_encoder_create(C.opus_int32(OpusSampleRate), 1, C.OPUS_APPLICATION_VOIP, &code)
+	if code != C.OPUS_OK {
+		return nil, fmt.Errorf("audiobridge: create opus encoder: %w", opusError(code))
+	}
+	return &OpusEncoder{st: st}, nil
+}
+
+// Encode encodes one frame of pcm (2.5 to 60ms at OpusSampleRate) into data
+// and returns the packet length.
+func (e *OpusEncoder) Encode(pcm []int16, data []byte) (int, error) {
+	if len(pcm) == 0 || len(data) == 0 {
+		return 0, errors.New("audiobridge: opus encode: empty buffer")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.st == nil {
+		return 0, ErrCodecClosed
+	}
+	n := C.opus_encode(e.st,
+		(*C.opus_int16)(unsafe.Pointer(&pcm[0])), C.int(len(pcm)),
+		(*C.uchar)(unsafe.Pointer(&data[0])), C.opus_int32(len(data)))
+	if n < 0 {
+		return 0, opusError(n)
+	}
+	return int(n), nil
+}
+
+// Close frees the encoder. Later calls to Encode fail.
+func (e *OpusEncoder) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.st != nil {
+		C.opus_encoder_destroy(e.st)
+		e.st = nil
+	}
+	return nil
+}
+
+// OpusDecoder is a Decoder backed by libopus, for 48 kHz mono audio. It has
+// the same build requirements as OpusEncoder, and is not safe for concurrent
+// use, except for Close.
+type OpusDecoder struct {
+	mu sync.Mutex
+	st *C.OpusDecoder
+}
+
+// NewOpusDecoder creates a mono decoder at OpusSampleRate. Close it to free
+// the libopus state.
+func NewOpusDecoder() (*OpusDecoder, error) {
+	var code C.int
+	st := C.opus_decoder_create(C.opus_int32(OpusSampleRate), 1, &code)
+	if code != C.OPUS_OK {
+		return nil, fmt.Errorf("audiobridge: create opus decoder: %w", opusError(code))
+	}
+	return &OpusDecoder{st: st}, nil
+}
+
+// Decode decodes one packet into pcm and returns the number of samples. An
+// empty packet stands for a lost one and is concealed with len(pcm) samples,
+// which must then be a multiple of 2.5ms.
+func (d *OpusDecoder) Decode(data []byte, pcm []int16) (int, error) {
+	if len(pcm) == 0 {
+		return 0, errors.New("audiobridge: opus decode: empty buffer")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.st == nil {
+		return 0, ErrCodecClosed
+	}
+	var in *C.uchar
+	if len(data) > 0 {
+		in = (*C.uchar)(unsafe.Pointer(&data[0]))
+	}
+	n := C.opus_decode(d.st, in, C.opus_int32(len(data)),
+		(*C.opus_int16)(unsafe.Pointer(&pcm[0])), C.int(len(pcm)), 0)
+	if n < 0 {
+		return 0, opusError(n)
+	}
+	return int(n), nil
+}
+
+// Close frees the decoder. Later calls to Decode fail.
+func (d *OpusDecoder) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.st != nil {
+		C.opus_decoder_destroy(d.st)
+		d.st = nil
+	}
+	return nil
+}
+
+// opusError describes a negative libopus return code.
+func opusError(code C.int) error {
+	return fmt.Errorf("opus: %s", C.GoString(C.opus_strerror(code)))
+}
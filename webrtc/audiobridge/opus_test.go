@@ -0,0 +1,99 @@
+//go:build opus && cgo
+
+package audiobridge
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/enesunal-m/azrealtime"
+)
+
+func TestOpus_RoundTrip(t *testing.T) {
+	enc, err := NewOpusEncoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer enc.Close()
+	dec, err := NewOpusDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dec.Close()
+
+	// 200ms of a 440 Hz tone at DefaultSampleRate
+	var pcm []byte
+	for i := 0; i < azrealtime.DefaultSampleRate/5; i++ {
+		v := 8000 * math.Sin(2*math.Pi*440*float64(i)/azrealtime.DefaultSampleRate)
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(v)))
+	}
+	sink := &sampleSink{}
+	tx, err := NewSender(enc, sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Write(pcm); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.samples) < 10 {
+		t.Fatalf("%d samples, want at least 10 frames of 20ms", len(sink.samples))
+	}
+
+	rx, err := NewReceiver(dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []byte
+	for _, s := range sink.samples {
+		if len(s.Data) >= 2*frameSamples {
+			t.Errorf("packet of %d bytes is not compressed", len(s.Data))
+		}
+		b, err := rx.Decode(s.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, b...)
+	}
+	if len(out) < len(pcm)*9/10 {
+		t.Errorf("decoded %d bytes, want about %d", len(out), len(pcm))
+	}
+	var energy float64
+	for _, v := range appendSamples(nil, out) {
+		energy += float64(v) * float64(v)
+	}
+	if energy == 0 {
+		t.Error("decoded audio is silent")
+	}
+}
+
+func TestOpus_PacketLossAndClose(t *testing.T) {
+	dec, err := NewOpusDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm := make([]int16, frameSamples)
+	if n, err := dec.Decode(nil, pcm); err != nil || n != frameSamples {
+		t.Errorf("concealment: %d samples, %v; want %d", n, err, frameSamples)
+	}
+	if _, err := dec.Decode([]byte{0xff, 0xff, 0xff}, pcm); err == nil {
+		t.Error("expected an error for a corrupt packet")
+	}
+
+	dec.Close()
+	if _, err := dec.Decode(nil, pcm); !errors.Is(err, ErrCodecClosed) {
+		t.Errorf("Decode after Close: %v", err)
+	}
+	enc, err := NewOpusEncoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc.Close()
+	if _, err := enc.Encode(pcm, make([]byte, maxPacketBytes)); !errors.Is(err, ErrCodecClosed) {
+		t.Errorf("Encode after Close: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package audiobridge
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	pion "github.com/pion/webrtc/v3"
+	"github.com/pion/webrtc/v3/pkg/media"
+)
+
+// PCMTrack is an audio track fed with raw PCM16 at DefaultSampleRate, e.g.
+// from telephony, for EnhancedHeadlessOptions.AudioInputTrack. Audio is
+// encoded into 20ms Opus packets and sent in real time, so writes block while
+// the input runs ahead of the clock:
+//
+//	in, _ := audiobridge.NewPCMTrack(enc)
+//	opts.AudioInputTrack = in.Track()
+//	// ... once connected:
+//	go in.ReadFrom(callAudio) // an io.Reader of PCM16
+//
+// A PCMTrack is safe for concurrent use; writes are sent in order.
+type PCMTrack struct {
+	track *pion.TrackLocalStaticSample
+
+	mu     sync.Mutex
+	sender *Sender
+}
+
+// NewPCMTrack creates an Opus track and a PCMTrack that encodes with enc.
+func NewPCMTrack(enc Encoder) (*PCMTrack, error) {
+	track, err := pion.NewTrackLocalStaticSample(pion.RTPCodecCapability{MimeType: pion.MimeTypeOpus}, "pcm-audio", "pcm-stream")
+	if err != nil {
+		return nil, err
+	}
+	sender, err := NewSender(enc, &pacedWriter{out: track})
+	if err != nil {
+		return nil, err
+	}
+	return &PCMTrack{track: track, sender: sender}, nil
+}
+
+// Track returns the track carrying the encoded audio.
+func (p *PCMTrack) Track() *pion.TrackLocalStaticSample { return p.track }
+
+// Write sends PCM16 little-endian audio at DefaultSampleRate. Audio that does
+// not fill a frame is held until the next call or Flush.
+func (p *PCMTrack) Write(pcm []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sender.Write(pcm)
+}
+
+// Flush sends any held audio, padded with silence to a whole frame.
+func (p *PCMTrack) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sender.Flush()
+}
+
+// ReadFrom sends audio read from r until it returns io.EOF, then flushes.
+func (p *PCMTrack) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, 2*frameSamples) // 40ms at DefaultSampleRate
+	var total int64
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := p.Write(buf[:n]); werr != nil {
+				return total, werr
+			}
+			total += int64(n)
+		}
+		if errors.Is(err, io.EOF) {
+			return total, p.Flush()
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Feed sends the chunks received on ch until it is closed, then flushes. It
+// returns early with ctx.Err() when ctx is done.
+func (p *PCMTrack) Feed(ctx context.Context, ch <-chan []byte) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case pcm, ok := <-ch:
+			if !ok {
+				return p.Flush()
+			}
+			if _, err := p.Write(pcm); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// pacedWriter holds samples back so they leave no faster than real time.
+type pacedWriter struct {
+	out  SampleWriter
+	next time.Time // When the next sample is due
+}
+
+func (w *pacedWriter) WriteSample(s media.Sample) error {
+	now := time.Now()
+	if w.next.Before(now) {
+		w.next = now // Fell behind or idle: resume from now rather than bursting
+	} else {
+		time.Sleep(w.next.Sub(now))
+	}
+	w.next = w.next.Add(s.Duration)
+	return w.out.WriteSample(s)
+}
@@ -0,0 +1,78 @@
+package audiobridge
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v3/pkg/media"
+
+	"github.com/enesunal-m/azrealtime"
+)
+
+func TestPacedWriter_RealTime(t *testing.T) {
+	sink := &sampleSink{}
+	w := &pacedWriter{out: sink}
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := w.WriteSample(media.Sample{Data: []byte{1}, Duration: 20 * time.Millisecond}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first sample leaves at once, each later one 20ms after the previous
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("5 samples took %v, want at least 80ms", elapsed)
+	}
+	if len(sink.samples) != 5 {
+		t.Errorf("got %d samples", len(sink.samples))
+	}
+}
+
+func TestPCMTrack_ReadFrom(t *testing.T) {
+	codec := &rawCodec{}
+	p, err := NewPCMTrack(codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Track() == nil {
+		t.Fatal("no track")
+	}
+
+	// 50ms: two whole frames, and a third once flushed at EOF
+	pcm := make([]byte, azrealtime.PCM16BytesFor(50, azrealtime.DefaultSampleRate))
+	n, err := p.ReadFrom(bytes.NewReader(pcm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(pcm)) {
+		t.Errorf("read %d bytes, want %d", n, len(pcm))
+	}
+	if len(codec.frames) != 3 {
+		t.Errorf("encoded %d frames, want 3", len(codec.frames))
+	}
+}
+
+func TestPCMTrack_Feed(t *testing.T) {
+	codec := &rawCodec{}
+	p, err := NewPCMTrack(codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan []byte, 2)
+	ch <- make([]byte, azrealtime.PCM16BytesFor(20, azrealtime.DefaultSampleRate))
+	ch <- make([]byte, azrealtime.PCM16BytesFor(20, azrealtime.DefaultSampleRate))
+	close(ch)
+	if err := p.Feed(context.Background(), ch); err != nil {
+		t.Fatal(err)
+	}
+	if len(codec.frames) < 2 {
+		t.Errorf("encoded %d frames, want at least 2", len(codec.frames))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Feed(ctx, make(chan []byte)); err != context.Canceled {
+		t.Errorf("Feed with canceled context = %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package webrtc
+
+import (
+	"context"
+	"time"
+)
+
+// clock is the time source of key refresh, key caching and ICE restarts;
+// tests replace it to drive expiry and backoff without waiting.
+type clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) clockTimer
+	AfterFunc(d time.Duration, f func()) clockTimer
+}
+
+// clockTimer is the part of *time.Timer used through a clock.
+type clockTimer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) clockTimer { return realTimer{time.NewTimer(d)} }
+
+func (realClock) AfterFunc(d time.Duration, f func()) clockTimer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.t.C }
+func (t realTimer) Stop() bool          { return t.t.Stop() }
+
+// sleepCtx waits for d or until ctx is done, reporting whether the full duration elapsed.
+func sleepCtx(ctx context.Context, clk clock, d time.Duration) bool {
+	t := clk.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C():
+		return true
+	}
+}
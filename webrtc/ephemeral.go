@@ -6,51 +6,129 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/enesunal-m/azrealtime"
 )
 
 func SessionsURL(resourceEndpoint, apiVersion string) string {
 	if apiVersion == "" {
 		apiVersion = "2025-04-01-preview"
 	}
-	return fmt.Sprintf("%s/openai/realtimeapi/sessions?api-version=%s", resourceEndpoint, apiVersion)
+	return fmt.Sprintf("%s/openai/realtimeapi/sessions?api-version=%s", strings.TrimRight(resourceEndpoint, "/"), apiVersion)
 }
 
 type EphemeralResponse struct {
 	ID           string `json:"id"`
 	ClientSecret struct {
-		Value string `json:"value"`
+		Value     string `json:"value"`
+		ExpiresAt int64  `json:"expires_at,omitempty"`
 	} `json:"client_secret"`
 }
 
+// EphemeralKey is a minted ephemeral key together with the session it belongs to.
+type EphemeralKey struct {
+	SessionID string
+	Value     string
+	ExpiresAt time.Time // Zero if the service did not report an expiry
+}
+
 func MintEphemeralKey(ctx context.Context, resourceEndpoint, apiVersion, deployment, apiKey, voice string) (sessionID, ephemeralKey string, err error) {
-	url := SessionsURL(resourceEndpoint, apiVersion)
-	payload := map[string]any{"model": deployment}
+	key, err := MintEphemeral(ctx, resourceEndpoint, apiVersion, deployment, apiKey, voice)
+	if err != nil {
+		return "", "", err
+	}
+	return key.SessionID, key.Value, nil
+}
+
+// MintEphemeral is like MintEphemeralKey but also reports when the key expires.
+func MintEphemeral(ctx context.Context, resourceEndpoint, apiVersion, deployment, apiKey, voice string) (EphemeralKey, error) {
+	var opts MintSessionOptions
 	if voice != "" {
-		payload["voice"] = voice
+		opts.Session.Voice = &voice
+	}
+	return MintEphemeralSession(ctx, resourceEndpoint, apiVersion, deployment, apiKey, opts)
+}
+
+// MintSessionOptions configures the session created together with an
+// ephemeral key, so a browser connecting with the key gets a fully configured
+// session and never needs to send session.update itself.
+type MintSessionOptions struct {
+	// Session holds the voice, instructions, modalities, turn detection,
+	// input transcription, tools and other settings of the new session.
+	// It is checked with azrealtime.ValidateSession before minting.
+	Session azrealtime.Session
+
+	// ToolChoice controls tool use for the session: azrealtime.ToolChoiceAuto,
+	// ToolChoiceNone, ToolChoiceRequired or ToolChoiceFunction(name).
+	ToolChoice any
+
+	// AccessToken, if set, supplies a Microsoft Entra ID access token for the
+	// resource (scope CognitiveServicesScope), e.g. from a managed identity.
+	// It is sent as "Authorization: Bearer" and the API key is not needed.
+	AccessToken func(ctx context.Context) (string, error)
+}
+
+// CognitiveServicesScope is the Entra ID scope of Azure OpenAI resources in
+// the public cloud.
+const CognitiveServicesScope = "https://cognitiveservices.azure.com/.default"
+
+// MintEphemeralSession mints an ephemeral key for a session configured with
+// opts. apiKey may be empty when opts.AccessToken is set.
+func MintEphemeralSession(ctx context.Context, resourceEndpoint, apiVersion, deployment, apiKey string, opts MintSessionOptions) (EphemeralKey, error) {
+	if err := azrealtime.ValidateSession(opts.Session); err != nil {
+		return EphemeralKey{}, fmt.Errorf("mint ephemeral: %w", err)
+	}
+	sessionJSON, err := json.Marshal(opts.Session)
+	if err != nil {
+		return EphemeralKey{}, fmt.Errorf("mint ephemeral: %w", err)
+	}
+	payload := map[string]any{}
+	if err := json.Unmarshal(sessionJSON, &payload); err != nil {
+		return EphemeralKey{}, fmt.Errorf("mint ephemeral: %w", err)
+	}
+	payload["model"] = deployment
+	if opts.ToolChoice != nil {
+		payload["tool_choice"] = opts.ToolChoice
 	}
 	body, _ := json.Marshal(payload)
 
+	url := SessionsURL(resourceEndpoint, apiVersion)
 	req, _ := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
-	req.Header.Set("api-key", apiKey)
+	if opts.AccessToken != nil {
+		token, err := opts.AccessToken(ctx)
+		if err != nil {
+			return EphemeralKey{}, fmt.Errorf("mint ephemeral: access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else {
+		req.Header.Set("api-key", apiKey)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	httpClient := &http.Client{Timeout: 15 * time.Second}
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", "", err
+		return EphemeralKey{}, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode/100 != 2 {
-		return "", "", fmt.Errorf("mint ephemeral: status %d", resp.StatusCode)
+		return EphemeralKey{}, fmt.Errorf("mint ephemeral: status %d", resp.StatusCode)
 	}
 	var er EphemeralResponse
 	if err := json.NewDecoder(resp.Body).Decode(&er); err != nil {
-		return "", "", err
+		return EphemeralKey{}, err
+	}
+	key := EphemeralKey{SessionID: er.ID, Value: er.ClientSecret.Value}
+	if er.ClientSecret.ExpiresAt > 0 {
+		key.ExpiresAt = time.Unix(er.ClientSecret.ExpiresAt, 0)
 	}
-	return er.ID, er.ClientSecret.Value, nil
+	return key, nil
 }
 
+// RegionWebRTCURL returns the WebRTC endpoint of a public Azure region. For
+// other clouds or private endpoints set EnhancedHeadlessOptions.WebRTCURL.
 func RegionWebRTCURL(region string) string {
 	return fmt.Sprintf("https://%s.realtimeapi-preview.ai.azure.com/v1/realtimertc", region)
 }
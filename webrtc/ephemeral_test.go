@@ -0,0 +1,85 @@
+package webrtc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/enesunal-m/azrealtime"
+)
+
+// mintServer answers session requests with a fixed key and records the last
+// request's path, headers and decoded body.
+type mintServer struct {
+	*httptest.Server
+	path   string
+	header http.Header
+	body   map[string]any
+}
+
+func newMintServer(t *testing.T) *mintServer {
+	s := &mintServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		s.path, s.header, s.body = r.URL.RequestURI(), r.Header, nil
+		if err := json.Unmarshal(b, &s.body); err != nil {
+			t.Errorf("request body %q: %v", b, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id":"sess_1","client_secret":{"value":"ek_1","expires_at":1700000060}}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestMintEphemeralSession_Body(t *testing.T) {
+	srv := newMintServer(t)
+	opts := MintSessionOptions{Session: azrealtime.Session{
+		Voice:        azrealtime.Ptr("alloy"),
+		Instructions: azrealtime.Ptr("Be brief."),
+	}}
+	key, err := MintEphemeralSession(context.Background(), srv.URL+"/", "2025-04-01-preview", "gpt-4o-realtime", "secret", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.SessionID != "sess_1" || key.Value != "ek_1" || !key.ExpiresAt.Equal(time.Unix(1700000060, 0)) {
+		t.Errorf("key = %+v", key)
+	}
+	if want := "/openai/realtimeapi/sessions?api-version=2025-04-01-preview"; srv.path != want {
+		t.Errorf("path = %q, want %q", srv.path, want)
+	}
+	if got := srv.header.Get("api-key"); got != "secret" {
+		t.Errorf("api-key = %q", got)
+	}
+	// Unset session fields and ToolChoice are left out
+	want := map[string]any{"model": "gpt-4o-realtime", "voice": "alloy", "instructions": "Be brief."}
+	if !reflect.DeepEqual(srv.body, want) {
+		t.Errorf("body = %v, want %v", srv.body, want)
+	}
+}
+
+func TestMintEphemeralSession_ToolChoiceAndAccessToken(t *testing.T) {
+	srv := newMintServer(t)
+	opts := MintSessionOptions{
+		ToolChoice:  azrealtime.ToolChoiceRequired,
+		AccessToken: func(context.Context) (string, error) { return "entra", nil },
+	}
+	if _, err := MintEphemeralSession(context.Background(), srv.URL, "", "gpt-4o-realtime", "", opts); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.header.Get("Authorization"); got != "Bearer entra" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := srv.header.Get("api-key"); got != "" {
+		t.Errorf("api-key = %q, want none with an access token", got)
+	}
+	want := map[string]any{"model": "gpt-4o-realtime", "tool_choice": "required"}
+	if !reflect.DeepEqual(srv.body, want) {
+		t.Errorf("body = %v, want %v", srv.body, want)
+	}
+}
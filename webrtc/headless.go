@@ -7,14 +7,18 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	pion "github.com/pion/webrtc/v3"
+	"github.com/pion/webrtc/v3/pkg/media"
 )
 
 // Original HeadlessOptions for backward compatibility
 type HeadlessOptions struct {
 	Region     string
+	WebRTCURL  string
 	Deployment string
 	Ephemeral  string
 	IceServers []pion.ICEServer
@@ -31,43 +35,235 @@ type EnhancedHeadlessOptions struct {
 	OnMessage  func(msg []byte)
 	OnAudioRTP func(pkts uint64)
 
+	// WebRTCURL, if set, is the WebRTC endpoint used instead of
+	// RegionWebRTCURL(Region), for sovereign clouds (Azure Government, Azure
+	// China) or private endpoints. Region is then not needed.
+	WebRTCURL string
+
 	// NEW: Support for sending audio to Azure
 	AudioInputTrack *pion.TrackLocalStaticSample
 	OnReady         func(pc *pion.PeerConnection, dc *pion.DataChannel)
 	OnTrack         func(track *pion.TrackRemote, receiver *pion.RTPReceiver)
+
+	// OnStateChange is called on every peer connection state change.
+	OnStateChange func(state pion.PeerConnectionState)
+	// OnDataChannelOpen is called once the data channel can carry events.
+	// Use it rather than setting the channel's OnOpen handler in OnReady,
+	// which would stop HeadlessConn.Send from flushing its queue.
+	OnDataChannelOpen func()
+	// MaxBufferedMessages bounds the events HeadlessConn.Send (and the
+	// connection's RealtimeClient) queues until the data channel opens;
+	// beyond it sends fail with ErrSendBufferFull.
+	// Default: DefaultMaxBufferedMessages.
+	MaxBufferedMessages int
+	// OnSendError is called when queued events cannot be sent as the data
+	// channel opens. Their Send calls had already returned nil.
+	OnSendError func(err error)
+
+	// ICETransportPolicy selects which ICE candidates are used.
+	// pion.ICETransportPolicyRelay forces all traffic through a TURN server,
+	// for enterprise networks where direct and STUN connectivity fail.
+	ICETransportPolicy pion.ICETransportPolicy
+	// ICECandidatePoolSize is the number of candidates gathered before the offer is made.
+	ICECandidatePoolSize uint8
+	// ICEGatherTimeout is how long to wait for local ICE candidates before the
+	// offer is posted; it is posted as soon as gathering completes. The
+	// service accepts no trickled candidates, so by default the offer is
+	// posted at once, without any: the service then learns the client's
+	// addresses from its connectivity checks, the fastest setup on open
+	// networks. Set it when candidates must be listed in the offer, e.g. for
+	// TURN relays behind restrictive firewalls.
+	ICEGatherTimeout time.Duration
+	// TURNCredentials, if set, is called by every Connect to obtain ICE servers
+	// with short-lived credentials, e.g. from a TURN REST API. They are used in
+	// addition to IceServers.
+	TURNCredentials func(ctx context.Context) ([]pion.ICEServer, error)
+
+	// AutoICERestart makes the connection restart ICE when its path is lost,
+	// e.g. after a network change: when ICE stays disconnected for
+	// ICEDisconnectGrace, or fails, a new offer with the ICE restart flag is
+	// sent. If every attempt fails the connection closes.
+	AutoICERestart bool
+	// ICEDisconnectGrace is how long ICE may stay disconnected before a restart.
+	// Default: DefaultICEDisconnectGrace.
+	ICEDisconnectGrace time.Duration
+	// ICERestartAttempts bounds the restart attempts made before ICE connects
+	// again. Failed attempts are retried after DefaultRetryDelay, doubling
+	// each time. Default: DefaultICERestartAttempts.
+	ICERestartAttempts int
+	// RestartKey, if set, supplies the ephemeral key for a restart's SDP
+	// exchange, e.g. from a KeyManager once the original key has expired.
+	// Otherwise Ephemeral is reused.
+	RestartKey func(ctx context.Context) (string, error)
+	// OnICERestart is called after each restart attempt with its result.
+	OnICERestart func(err error)
+
+	// OnAudioSample taps the conversation's audio for recording (see
+	// OggRecorder): Opus packets received from the service, unless OnTrack is
+	// set, and those sent with HeadlessConn.WriteAudioSample.
+	OnAudioSample func(dir AudioDirection, s media.Sample)
+
+	// OnLocalOffer, if set, may rewrite each offer's SDP before it is posted to
+	// the service, e.g. to change codec preferences, bitrate hints or stereo
+	// flags. The peer connection keeps the original offer, as pion rejects
+	// modified local descriptions, so changes must stay compatible with it.
+	// It is also called for ICE restart offers.
+	OnLocalOffer func(sdp string) string
 }
 
-// Enhanced HeadlessConnect that supports bidirectional audio
-func EnhancedHeadlessConnect(ctx context.Context, opt EnhancedHeadlessOptions) error {
-	if opt.Region == "" || opt.Deployment == "" || opt.Ephemeral == "" {
-		return errors.New("region, deployment and ephemeral are required")
+// newPeerConnection creates the peer connection of Connect; tests replace it
+// to shorten ICE timeouts.
+var newPeerConnection = pion.NewPeerConnection
+
+// iceConfiguration builds the peer connection configuration from opt.
+func iceConfiguration(ctx context.Context, opt EnhancedHeadlessOptions) (pion.Configuration, error) {
+	cfg := pion.Configuration{
+		ICEServers:           append([]pion.ICEServer(nil), opt.IceServers...),
+		ICETransportPolicy:   opt.ICETransportPolicy,
+		ICECandidatePoolSize: opt.ICECandidatePoolSize,
 	}
+	if opt.TURNCredentials != nil {
+		servers, err := opt.TURNCredentials(ctx)
+		if err != nil {
+			return cfg, fmt.Errorf("turn credentials: %w", err)
+		}
+		cfg.ICEServers = append(cfg.ICEServers, servers...)
+	}
+	if cfg.ICETransportPolicy == pion.ICETransportPolicyRelay && !hasTURNServer(cfg.ICEServers) {
+		return cfg, errors.New("relay ICE transport policy requires a TURN server")
+	}
+	return cfg, nil
+}
+
+// hasTURNServer reports whether servers include a turn: or turns: URL.
+func hasTURNServer(servers []pion.ICEServer) bool {
+	for _, s := range servers {
+		for _, u := range s.URLs {
+			if strings.HasPrefix(u, "turn:") || strings.HasPrefix(u, "turns:") {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-	cfg := pion.Configuration{}
-	if len(opt.IceServers) > 0 {
-		cfg.ICEServers = opt.IceServers
+// HeadlessConn is a WebRTC session established by Connect.
+type HeadlessConn struct {
+	pc     *pion.PeerConnection
+	dc     *pion.DataChannel
+	sender *channelSender
+	opt    EnhancedHeadlessOptions
+	done   chan struct{}
+
+	negotiateMu sync.Mutex    // Serializes offer/answer exchanges
+	ice         *iceRestarter // Set with AutoICERestart
+
+	closeOnce sync.Once
+	closeErr  error
+	doneOnce  sync.Once
+}
+
+// PeerConnection returns the underlying peer connection.
+func (c *HeadlessConn) PeerConnection() *pion.PeerConnection { return c.pc }
+
+// DataChannel returns the data channel that carries realtime events.
+func (c *HeadlessConn) DataChannel() *pion.DataChannel { return c.dc }
+
+// State returns the current peer connection state.
+func (c *HeadlessConn) State() pion.PeerConnectionState { return c.pc.ConnectionState() }
+
+// Done returns a channel that is closed when the connection fails or is closed.
+func (c *HeadlessConn) Done() <-chan struct{} { return c.done }
+
+// Send sends an event on the data channel. Events sent before the channel
+// opens are queued, up to MaxBufferedMessages, and delivered in order once it
+// does, so callers need not wait for OnDataChannelOpen.
+func (c *HeadlessConn) Send(data []byte) error {
+	return c.sender.send(data)
+}
+
+// WriteAudioSample sends an Opus sample on AudioInputTrack and passes it to
+// OnAudioSample.
+func (c *HeadlessConn) WriteAudioSample(s media.Sample) error {
+	if c.opt.AudioInputTrack == nil {
+		return errors.New("no audio input track")
+	}
+	if c.opt.OnAudioSample != nil {
+		c.opt.OnAudioSample(AudioToAzure, s)
 	}
+	return c.opt.AudioInputTrack.WriteSample(s)
+}
+
+// Close tears down the peer connection. It is safe to call more than once.
+func (c *HeadlessConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.pc.Close()
+		if c.sender != nil {
+			c.sender.closedChannel()
+		}
+		c.finish()
+	})
+	return c.closeErr
+}
 
-	pc, err := pion.NewPeerConnection(cfg)
+func (c *HeadlessConn) finish() {
+	c.doneOnce.Do(func() { close(c.done) })
+}
+
+// Connect negotiates a WebRTC session with the realtime service and returns
+// once the SDP exchange completes; ctx bounds only that setup. The session
+// then runs until Close is called or the connection fails (see Done).
+func Connect(ctx context.Context, opt EnhancedHeadlessOptions) (*HeadlessConn, error) {
+	if (opt.Region == "" && opt.WebRTCURL == "") || opt.Deployment == "" || opt.Ephemeral == "" {
+		return nil, errors.New("region (or WebRTC URL), deployment and ephemeral are required")
+	}
+
+	cfg, err := iceConfiguration(ctx, opt)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer pc.Close()
+
+	pc, err := newPeerConnection(cfg)
+	if err != nil {
+		return nil, err
+	}
+	conn := &HeadlessConn{pc: pc, opt: opt, done: make(chan struct{})}
+	ok := false
+	defer func() {
+		if !ok {
+			_ = conn.Close()
+		}
+	}()
+
+	pc.OnConnectionStateChange(func(state pion.PeerConnectionState) {
+		if opt.OnStateChange != nil {
+			opt.OnStateChange(state)
+		}
+		// With AutoICERestart a failed connection is restarted instead (see watchICE)
+		if state == pion.PeerConnectionStateClosed || state == pion.PeerConnectionStateFailed && !opt.AutoICERestart {
+			conn.finish()
+		}
+	})
 
 	// Create data channel
 	dc, err := pc.CreateDataChannel("realtime-channel", nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	conn.dc = dc
+	conn.sender = newChannelSender(dc, opt.MaxBufferedMessages, opt.OnSendError)
 
 	if opt.OnMessage != nil {
 		dc.OnMessage(func(m pion.DataChannelMessage) { opt.OnMessage(m.Data) })
 	}
+	if opt.OnDataChannelOpen != nil {
+		conn.sender.whenOpen(opt.OnDataChannelOpen)
+	}
 
 	// NEW: Add audio input track if provided (for sending audio TO Azure)
 	if opt.AudioInputTrack != nil {
 		if _, err := pc.AddTrack(opt.AudioInputTrack); err != nil {
-			return fmt.Errorf("failed to add audio input track: %w", err)
+			return nil, fmt.Errorf("failed to add audio input track: %w", err)
 		}
 	}
 
@@ -76,23 +272,25 @@ func EnhancedHeadlessConnect(ctx context.Context, opt EnhancedHeadlessOptions) e
 		Direction: pion.RTPTransceiverDirectionRecvonly,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// NEW: Enhanced track handling
 	if opt.OnTrack != nil {
 		pc.OnTrack(opt.OnTrack)
-	} else if opt.OnAudioRTP != nil {
+	} else if opt.OnAudioRTP != nil || opt.OnAudioSample != nil {
 		pc.OnTrack(func(track *pion.TrackRemote, receiver *pion.RTPReceiver) {
 			var pkts uint64
-			buf := make([]byte, 1500)
 			for {
-				_, _, e := track.Read(buf)
+				pkt, _, e := track.ReadRTP()
 				if e != nil {
 					return
 				}
 				pkts++
-				if pkts%200 == 0 {
+				if opt.OnAudioSample != nil {
+					opt.OnAudioSample(AudioFromAzure, media.Sample{Data: pkt.Payload, Duration: defaultSampleDuration})
+				}
+				if opt.OnAudioRTP != nil && pkts%200 == 0 {
 					opt.OnAudioRTP(pkts)
 				}
 			}
@@ -104,18 +302,47 @@ func EnhancedHeadlessConnect(ctx context.Context, opt EnhancedHeadlessOptions) e
 		opt.OnReady(pc, dc)
 	}
 
-	offer, err := pc.CreateOffer(nil)
+	if err := conn.negotiate(ctx, nil, opt.Ephemeral); err != nil {
+		return nil, err
+	}
+	if opt.AutoICERestart {
+		conn.watchICE()
+	}
+
+	ok = true
+	return conn, nil
+}
+
+// negotiate makes an offer with options, posts it with the ephemeral key and
+// applies the answer.
+func (c *HeadlessConn) negotiate(ctx context.Context, options *pion.OfferOptions, ephemeral string) error {
+	c.negotiateMu.Lock()
+	defer c.negotiateMu.Unlock()
+
+	offer, err := c.pc.CreateOffer(options)
 	if err != nil {
 		return err
 	}
 
-	if err := pc.SetLocalDescription(offer); err != nil {
+	if err := c.pc.SetLocalDescription(offer); err != nil {
 		return err
 	}
 
-	url := fmt.Sprintf("%s?model=%s", RegionWebRTCURL(opt.Region), opt.Deployment)
-	req, _ := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBufferString(offer.SDP))
-	req.Header.Set("Authorization", "Bearer "+opt.Ephemeral)
+	sdp := offer.SDP
+	if c.opt.ICEGatherTimeout > 0 {
+		sdp = c.gatheredOffer(ctx)
+	}
+	if c.opt.OnLocalOffer != nil {
+		sdp = c.opt.OnLocalOffer(sdp)
+	}
+
+	base := c.opt.WebRTCURL
+	if base == "" {
+		base = RegionWebRTCURL(c.opt.Region)
+	}
+	url := fmt.Sprintf("%s?model=%s", base, c.opt.Deployment)
+	req, _ := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBufferString(sdp))
+	req.Header.Set("Authorization", "Bearer "+ephemeral)
 	req.Header.Set("Content-Type", "application/sdp")
 
 	httpClient := &http.Client{Timeout: 20 * time.Second}
@@ -135,9 +362,31 @@ func EnhancedHeadlessConnect(ctx context.Context, opt EnhancedHeadlessOptions) e
 	}
 
 	answer := pion.SessionDescription{Type: pion.SDPTypeAnswer, SDP: string(b)}
-	if err := pc.SetRemoteDescription(answer); err != nil {
+	return c.pc.SetRemoteDescription(answer)
+}
+
+// gatheredOffer waits up to ICEGatherTimeout for candidate gathering and
+// returns the local offer with the candidates found so far.
+func (c *HeadlessConn) gatheredOffer(ctx context.Context) string {
+	gathered := pion.GatheringCompletePromise(c.pc)
+	t := time.NewTimer(c.opt.ICEGatherTimeout)
+	defer t.Stop()
+	select {
+	case <-gathered:
+	case <-t.C:
+	case <-ctx.Done():
+	}
+	return c.pc.LocalDescription().SDP
+}
+
+// Enhanced HeadlessConnect that supports bidirectional audio. It blocks until
+// ctx is done; use Connect to control the session's lifetime.
+func EnhancedHeadlessConnect(ctx context.Context, opt EnhancedHeadlessOptions) error {
+	conn, err := Connect(ctx, opt)
+	if err != nil {
 		return err
 	}
+	defer conn.Close()
 
 	<-ctx.Done()
 	return nil
@@ -147,6 +396,7 @@ func EnhancedHeadlessConnect(ctx context.Context, opt EnhancedHeadlessOptions) e
 func HeadlessConnect(ctx context.Context, opt HeadlessOptions) error {
 	enhancedOpt := EnhancedHeadlessOptions{
 		Region:     opt.Region,
+		WebRTCURL:  opt.WebRTCURL,
 		Deployment: opt.Deployment,
 		Ephemeral:  opt.Ephemeral,
 		IceServers: opt.IceServers,
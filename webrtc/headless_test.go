@@ -0,0 +1,491 @@
+package webrtc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pion "github.com/pion/webrtc/v3"
+)
+
+// fakeService answers SDP offers like the realtime service's WebRTC endpoint,
+// with a local peer connection per offer, and records what it was sent.
+type fakeService struct {
+	*httptest.Server
+	t *testing.T
+
+	mu       sync.Mutex
+	status   int      // If set, offers are refused with this status
+	noICE    bool     // Answer without candidates; the test adds them with addCandidates
+	offers   []string // Posted SDP bodies
+	auth     []string // Authorization headers
+	peers    []*pion.PeerConnection
+	messages []string // Data channel messages received
+
+	candidates []pion.ICECandidateInit // Gathered by the answering peer connection
+	gathered   <-chan struct{}
+}
+
+func newFakeService(t *testing.T) *fakeService {
+	t.Helper()
+	s := &fakeService{t: t}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(func() {
+		s.Close()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, pc := range s.peers {
+			pc.Close()
+		}
+	})
+	return s
+}
+
+func (s *fakeService) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.offers = append(s.offers, string(body))
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	status := s.status
+	s.mu.Unlock()
+	if status != 0 {
+		http.Error(w, "rejected", status)
+		return
+	}
+
+	answer, err := s.answer(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/sdp")
+	io.WriteString(w, answer)
+}
+
+// answer applies offer to the answering peer connection, creating it for the
+// first offer; later offers are renegotiations such as ICE restarts.
+func (s *fakeService) answer(offer string) (string, error) {
+	s.mu.Lock()
+	var pc *pion.PeerConnection
+	if len(s.peers) > 0 {
+		pc = s.peers[len(s.peers)-1]
+	}
+	s.mu.Unlock()
+	if pc == nil {
+		var err error
+		if pc, err = pion.NewPeerConnection(pion.Configuration{}); err != nil {
+			return "", err
+		}
+		pc.OnICECandidate(func(c *pion.ICECandidate) {
+			if c != nil {
+				s.mu.Lock()
+				s.candidates = append(s.candidates, c.ToJSON())
+				s.mu.Unlock()
+			}
+		})
+		pc.OnDataChannel(func(dc *pion.DataChannel) {
+			dc.OnMessage(func(m pion.DataChannelMessage) {
+				s.mu.Lock()
+				s.messages = append(s.messages, string(m.Data))
+				s.mu.Unlock()
+			})
+		})
+		s.mu.Lock()
+		s.peers = append(s.peers, pc)
+		s.mu.Unlock()
+	}
+
+	if err := pc.SetRemoteDescription(pion.SessionDescription{Type: pion.SDPTypeOffer, SDP: offer}); err != nil {
+		return "", err
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		return "", err
+	}
+	gathered := pion.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.gathered = gathered
+	noICE := s.noICE
+	s.mu.Unlock()
+	if noICE {
+		return answer.SDP, nil
+	}
+	<-gathered
+	return pc.LocalDescription().SDP, nil
+}
+
+// addCandidates gives pc the candidates of the answering peer connection,
+// letting a connection made with noICE come up.
+func (s *fakeService) addCandidates(t *testing.T, pc *pion.PeerConnection) {
+	t.Helper()
+	s.mu.Lock()
+	gathered := s.gathered
+	s.mu.Unlock()
+	<-gathered
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.candidates {
+		if err := pc.AddICECandidate(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func (s *fakeService) snapshot() (offers, auth, messages []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.offers...), append([]string(nil), s.auth...), append([]string(nil), s.messages...)
+}
+
+// peer returns the answering peer connection of the first offer.
+func (s *fakeService) peer() *pion.PeerConnection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peers[0]
+}
+
+// options returns connection options for the service.
+func (s *fakeService) options() EnhancedHeadlessOptions {
+	return EnhancedHeadlessOptions{WebRTCURL: s.URL, Deployment: "gpt-4o-realtime", Ephemeral: "ek_test"}
+}
+
+func connectFake(t *testing.T, opt EnhancedHeadlessOptions) *HeadlessConn {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := Connect(ctx, opt)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func waitDone(t *testing.T, conn *HeadlessConn, what string) {
+	t.Helper()
+	select {
+	case <-conn.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Done not closed %s", what)
+	}
+}
+
+func TestConnect_PostsOffer(t *testing.T) {
+	svc := newFakeService(t)
+	conn := connectFake(t, svc.options())
+
+	offers, auth, _ := svc.snapshot()
+	if len(offers) != 1 || !strings.HasPrefix(offers[0], "v=0") {
+		t.Fatalf("posted %q, want one SDP offer", offers)
+	}
+	if auth[0] != "Bearer ek_test" {
+		t.Errorf("Authorization = %q", auth[0])
+	}
+	if conn.PeerConnection().RemoteDescription() == nil {
+		t.Error("answer was not applied")
+	}
+}
+
+func TestConnect_FailedExchange(t *testing.T) {
+	svc := newFakeService(t)
+	svc.status = http.StatusUnauthorized
+
+	var mu sync.Mutex
+	var states []pion.PeerConnectionState
+	opt := svc.options()
+	opt.OnStateChange = func(s pion.PeerConnectionState) {
+		mu.Lock()
+		states = append(states, s)
+		mu.Unlock()
+	}
+	conn, err := Connect(context.Background(), opt)
+	if err == nil || !strings.Contains(err.Error(), "SDP exchange failed: 401") {
+		t.Fatalf("Connect: %v, want the SDP exchange error", err)
+	}
+	if conn != nil {
+		t.Error("Connect returned a connection with its error")
+	}
+	waitFor(t, "the peer connection to close", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(states) > 0 && states[len(states)-1] == pion.PeerConnectionStateClosed
+	})
+
+	if _, err := Connect(context.Background(), EnhancedHeadlessOptions{Deployment: "d", Ephemeral: "k"}); err == nil {
+		t.Error("expected an error without a region or WebRTC URL")
+	}
+}
+
+func TestHeadlessConn_Close(t *testing.T) {
+	svc := newFakeService(t)
+	conn := connectFake(t, svc.options())
+
+	select {
+	case <-conn.Done():
+		t.Fatal("Done closed before Close")
+	default:
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	waitDone(t, conn, "after Close")
+	if err := conn.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if err := conn.Send([]byte(`{"type":"response.create"}`)); err == nil {
+		t.Error("expected Send to fail after Close")
+	}
+}
+
+func TestHeadlessConn_DoneOnFailure(t *testing.T) {
+	// Fail within a second of losing the service rather than pion's 30
+	defer func(orig func(pion.Configuration) (*pion.PeerConnection, error)) { newPeerConnection = orig }(newPeerConnection)
+	newPeerConnection = func(cfg pion.Configuration) (*pion.PeerConnection, error) {
+		m := &pion.MediaEngine{}
+		if err := m.RegisterDefaultCodecs(); err != nil {
+			return nil, err
+		}
+		var se pion.SettingEngine
+		se.SetICETimeouts(200*time.Millisecond, 500*time.Millisecond, 50*time.Millisecond)
+		return pion.NewAPI(pion.WithMediaEngine(m), pion.WithSettingEngine(se)).NewPeerConnection(cfg)
+	}
+
+	svc := newFakeService(t)
+	opened := make(chan struct{})
+	opt := svc.options()
+	opt.OnDataChannelOpen = func() { close(opened) }
+	conn := connectFake(t, opt)
+	select {
+	case <-opened:
+	case <-time.After(10 * time.Second):
+		t.Fatal("data channel did not open")
+	}
+
+	// The service going away ends the session without Close
+	svc.peer().Close()
+	waitDone(t, conn, "after the service closed the connection")
+}
+
+func TestHeadlessConn_SendBeforeOpen(t *testing.T) {
+	svc := newFakeService(t)
+	svc.noICE = true
+	opened := make(chan struct{})
+	opt := svc.options()
+	opt.MaxBufferedMessages = 2
+	opt.OnDataChannelOpen = func() { close(opened) }
+	conn := connectFake(t, opt)
+
+	// Queued, as the service cannot be reached until it has its candidates
+	for _, m := range []string{`{"type":"session.update"}`, `{"type":"response.create"}`} {
+		if err := conn.Send([]byte(m)); err != nil {
+			t.Fatalf("Send before open: %v", err)
+		}
+	}
+	if err := conn.Send([]byte(`{"type":"response.cancel"}`)); err != ErrSendBufferFull {
+		t.Errorf("Send beyond MaxBufferedMessages = %v, want ErrSendBufferFull", err)
+	}
+	svc.addCandidates(t, conn.PeerConnection())
+	select {
+	case <-opened:
+	case <-time.After(10 * time.Second):
+		t.Fatal("data channel did not open")
+	}
+
+	waitFor(t, "queued messages to arrive", func() bool {
+		_, _, messages := svc.snapshot()
+		return len(messages) == 2
+	})
+	_, _, messages := svc.snapshot()
+	if messages[0] != `{"type":"session.update"}` || messages[1] != `{"type":"response.create"}` {
+		t.Errorf("service received %q, want the queued events in order", messages)
+	}
+}
+
+func TestICEConfiguration(t *testing.T) {
+	stun := pion.ICEServer{URLs: []string{"stun:stun.example.com:3478"}}
+	turn := pion.ICEServer{URLs: []string{"turns:turn.example.com:443?transport=tcp"}, Username: "u", Credential: "p"}
+	minted := pion.ICEServer{URLs: []string{"turn:relay.example.com:3478"}, Username: "short", Credential: "lived"}
+
+	for _, tt := range []struct {
+		name    string
+		opt     EnhancedHeadlessOptions
+		want    []pion.ICEServer
+		wantErr string
+	}{
+		{
+			name: "static servers",
+			opt:  EnhancedHeadlessOptions{IceServers: []pion.ICEServer{stun}},
+			want: []pion.ICEServer{stun},
+		},
+		{
+			name:    "relay policy without a TURN server",
+			opt:     EnhancedHeadlessOptions{IceServers: []pion.ICEServer{stun}, ICETransportPolicy: pion.ICETransportPolicyRelay},
+			wantErr: "requires a TURN server",
+		},
+		{
+			name: "relay policy with a static TURN server",
+			opt:  EnhancedHeadlessOptions{IceServers: []pion.ICEServer{turn}, ICETransportPolicy: pion.ICETransportPolicyRelay},
+			want: []pion.ICEServer{turn},
+		},
+		{
+			name: "credential callback merged after static servers",
+			opt: EnhancedHeadlessOptions{
+				IceServers:         []pion.ICEServer{stun},
+				ICETransportPolicy: pion.ICETransportPolicyRelay,
+				TURNCredentials: func(context.Context) ([]pion.ICEServer, error) {
+					return []pion.ICEServer{minted}, nil
+				},
+			},
+			want: []pion.ICEServer{stun, minted},
+		},
+		{
+			name: "credential callback error",
+			opt: EnhancedHeadlessOptions{
+				IceServers: []pion.ICEServer{turn},
+				TURNCredentials: func(context.Context) ([]pion.ICEServer, error) {
+					return nil, errors.New("turn api unavailable")
+				},
+			},
+			wantErr: "turn credentials: turn api unavailable",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := iceConfiguration(context.Background(), tt.opt)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfg.ICEServers, tt.want) {
+				t.Errorf("servers = %+v, want %+v", cfg.ICEServers, tt.want)
+			}
+			if cfg.ICETransportPolicy != tt.opt.ICETransportPolicy {
+				t.Errorf("policy = %v", cfg.ICETransportPolicy)
+			}
+		})
+	}
+}
+
+func TestICEConfiguration_DoesNotModifyOptions(t *testing.T) {
+	static := make([]pion.ICEServer, 1, 4)
+	static[0] = pion.ICEServer{URLs: []string{"stun:stun.example.com"}}
+	opt := EnhancedHeadlessOptions{
+		IceServers: static,
+		TURNCredentials: func(context.Context) ([]pion.ICEServer, error) {
+			return []pion.ICEServer{{URLs: []string{"turn:relay.example.com"}}}, nil
+		},
+	}
+	for i := 0; i < 2; i++ {
+		cfg, err := iceConfiguration(context.Background(), opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cfg.ICEServers) != 2 {
+			t.Fatalf("connect %d: %d servers, want 2", i, len(cfg.ICEServers))
+		}
+	}
+	if len(opt.IceServers) != 1 || static[:2][1].URLs != nil {
+		t.Error("IceServers was modified")
+	}
+}
+
+func TestConnect_ICEGatherTimeout(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		svc := newFakeService(t)
+		opt := svc.options()
+		opt.ICEGatherTimeout = 5 * time.Second
+		start := time.Now()
+		connectFake(t, opt)
+
+		offers, _, _ := svc.snapshot()
+		if !strings.Contains(offers[0], "a=candidate:") {
+			t.Errorf("posted offer has no candidates:\n%s", offers[0])
+		}
+		// Posted once gathering completed, well before the timeout
+		if elapsed := time.Since(start); elapsed >= opt.ICEGatherTimeout {
+			t.Errorf("Connect took %v, want it to stop waiting when gathering completes", elapsed)
+		}
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		svc := newFakeService(t)
+		connectFake(t, svc.options())
+
+		offers, _, _ := svc.snapshot()
+		if strings.Contains(offers[0], "a=candidate:") {
+			t.Errorf("offer posted after gathering, want it posted at once:\n%s", offers[0])
+		}
+	})
+}
+
+func TestConnect_OnLocalOffer(t *testing.T) {
+	svc := newFakeService(t)
+	const marker = "a=x-rewritten:1\r\n"
+	var mu sync.Mutex
+	var seen []string
+	opt := svc.options()
+	opt.OnLocalOffer = func(sdp string) string {
+		mu.Lock()
+		seen = append(seen, sdp)
+		mu.Unlock()
+		i := strings.Index(sdp, "m=")
+		return sdp[:i] + marker + sdp[i:]
+	}
+	opt.RestartKey = func(context.Context) (string, error) { return "ek_restart", nil }
+	conn := connectFake(t, opt)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := conn.RestartICE(ctx); err != nil {
+		t.Fatalf("RestartICE: %v", err)
+	}
+
+	offers, auth, _ := svc.snapshot()
+	mu.Lock()
+	defer mu.Unlock()
+	if len(offers) != 2 || len(seen) != 2 {
+		t.Fatalf("posted %d offers and rewrote %d, want 2 each", len(offers), len(seen))
+	}
+	for i, offer := range offers {
+		if !strings.Contains(offer, marker) {
+			t.Errorf("offer %d was posted without the rewrite", i)
+		}
+		if strings.Contains(seen[i], marker) {
+			t.Errorf("offer %d was passed to OnLocalOffer already rewritten", i)
+		}
+	}
+	if auth[1] != "Bearer ek_restart" {
+		t.Errorf("restart Authorization = %q", auth[1])
+	}
+	if iceUfrag(seen[0]) == iceUfrag(seen[1]) {
+		t.Error("second offer is not an ICE restart offer")
+	}
+	// The peer connection keeps the original offer
+	if strings.Contains(conn.PeerConnection().LocalDescription().SDP, marker) {
+		t.Error("local description was rewritten")
+	}
+}
+
+// iceUfrag returns the first ice-ufrag attribute of sdp.
+func iceUfrag(sdp string) string {
+	for _, line := range strings.Split(sdp, "\r\n") {
+		if v, ok := strings.CutPrefix(line, "a=ice-ufrag:"); ok {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package webrtc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pion "github.com/pion/webrtc/v3"
+)
+
+// Defaults used by EnhancedHeadlessOptions.AutoICERestart when the
+// corresponding option is zero.
+const (
+	DefaultICEDisconnectGrace = 3 * time.Second // How long ICE may stay disconnected before a restart
+	DefaultICERestartAttempts = 3               // Restart attempts before the connection is closed
+)
+
+// iceRestartTimeout bounds the SDP exchange of one restart attempt.
+const iceRestartTimeout = 30 * time.Second
+
+// RestartICE renegotiates the connection with an ICE restart offer, so it can
+// continue over a new network path. The ephemeral key comes from RestartKey
+// if set, otherwise the original one is reused. Media and data channels stay
+// open across the restart.
+func (c *HeadlessConn) RestartICE(ctx context.Context) error {
+	key := c.opt.Ephemeral
+	if c.opt.RestartKey != nil {
+		k, err := c.opt.RestartKey(ctx)
+		if err != nil {
+			return fmt.Errorf("restart key: %w", err)
+		}
+		key = k
+	}
+	return c.negotiate(ctx, &pion.OfferOptions{ICERestart: true}, key)
+}
+
+// maxICERestartDelay caps the pause between failed restart attempts.
+const maxICERestartDelay = 30 * time.Second
+
+// watchICE restarts ICE when it stays disconnected for ICEDisconnectGrace or
+// fails.
+func (c *HeadlessConn) watchICE() {
+	c.ice = &iceRestarter{
+		grace:     c.opt.ICEDisconnectGrace,
+		limit:     int32(c.opt.ICERestartAttempts),
+		clock:     realClock{},
+		restart:   c.RestartICE,
+		close:     func() { _ = c.Close() },
+		done:      c.done,
+		onRestart: c.opt.OnICERestart,
+	}
+	c.pc.OnICEConnectionStateChange(c.ice.stateChange)
+}
+
+// iceRestarter decides when to restart ICE. The attempt count resets
+// whenever ICE connects again.
+type iceRestarter struct {
+	grace     time.Duration // Default: DefaultICEDisconnectGrace
+	limit     int32         // Default: DefaultICERestartAttempts
+	clock     clock
+	restart   func(ctx context.Context) error
+	close     func()
+	done      <-chan struct{}
+	onRestart func(error)
+
+	mu       sync.Mutex
+	timer    clockTimer   // Grace period of a disconnection
+	running  atomic.Bool  // Restart attempts are being made
+	attempts atomic.Int32 // Restart attempts since ICE last connected
+}
+
+func (r *iceRestarter) stateChange(state pion.ICEConnectionState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	switch state {
+	case pion.ICEConnectionStateConnected, pion.ICEConnectionStateCompleted:
+		r.attempts.Store(0)
+	case pion.ICEConnectionStateDisconnected:
+		grace := r.grace
+		if grace <= 0 {
+			grace = DefaultICEDisconnectGrace
+		}
+		r.timer = r.clock.AfterFunc(grace, r.run)
+	case pion.ICEConnectionStateFailed:
+		go r.run()
+	}
+}
+
+// run makes restart attempts until one completes its SDP exchange, closing
+// the connection once the attempts have been used up since ICE last
+// connected. Failed attempts are retried with a doubling delay.
+func (r *iceRestarter) run() {
+	if !r.running.CompareAndSwap(false, true) {
+		return
+	}
+	defer r.running.Store(false)
+
+	limit := r.limit
+	if limit <= 0 {
+		limit = DefaultICERestartAttempts
+	}
+	delay := DefaultRetryDelay
+	for {
+		select {
+		case <-r.done:
+			return
+		default:
+		}
+		if r.attempts.Add(1) > limit {
+			r.close()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), iceRestartTimeout)
+		err := r.restart(ctx)
+		cancel()
+		if r.onRestart != nil {
+			r.onRestart(err)
+		}
+		if err == nil {
+			return // ICE state changes report whether the new path works
+		}
+
+		t := r.clock.NewTimer(delay)
+		select {
+		case <-r.done:
+			t.Stop()
+			return
+		case <-t.C():
+		}
+		delay = min(2*delay, maxICERestartDelay)
+	}
+}
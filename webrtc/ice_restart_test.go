@@ -0,0 +1,171 @@
+package webrtc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pion "github.com/pion/webrtc/v3"
+)
+
+// restartHarness records what an iceRestarter does on a fake clock.
+type restartHarness struct {
+	clk  *fakeClock
+	r    *iceRestarter
+	done chan struct{}
+
+	mu       sync.Mutex
+	attempts int
+	failing  bool // Restart attempts fail
+	reported []error
+	closed   bool
+}
+
+func newRestartHarness(limit int32) *restartHarness {
+	h := &restartHarness{clk: newFakeClock(), done: make(chan struct{})}
+	h.r = &iceRestarter{
+		grace: 3 * time.Second,
+		limit: limit,
+		clock: h.clk,
+		restart: func(ctx context.Context) error {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.attempts++
+			if h.failing {
+				return errors.New("offer rejected")
+			}
+			return nil
+		},
+		close: func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.closed = true
+		},
+		done: h.done,
+		onRestart: func(err error) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.reported = append(h.reported, err)
+		},
+	}
+	return h
+}
+
+func (h *restartHarness) snapshot() (attempts, reported int, closed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.attempts, len(h.reported), h.closed
+}
+
+// waitIdle waits until the restarter has stopped making attempts.
+func (h *restartHarness) waitIdle(t *testing.T) {
+	t.Helper()
+	waitFor(t, "the restart attempts to end", func() bool { return !h.r.running.Load() })
+}
+
+func TestICERestart_DisconnectedAfterGrace(t *testing.T) {
+	h := newRestartHarness(3)
+
+	// Reconnecting within the grace period cancels the restart
+	h.r.stateChange(pion.ICEConnectionStateDisconnected)
+	h.clk.Advance(2 * time.Second)
+	h.r.stateChange(pion.ICEConnectionStateConnected)
+	if n := h.clk.Pending(); n != 0 {
+		t.Fatalf("%d timers pending after reconnecting, want 0", n)
+	}
+	h.clk.Advance(time.Minute)
+	if attempts, _, _ := h.snapshot(); attempts != 0 {
+		t.Fatalf("%d restarts after a short disconnection, want 0", attempts)
+	}
+
+	h.r.stateChange(pion.ICEConnectionStateDisconnected)
+	h.clk.Advance(3*time.Second - time.Millisecond)
+	if attempts, _, _ := h.snapshot(); attempts != 0 {
+		t.Fatalf("%d restarts within the grace period, want 0", attempts)
+	}
+	h.clk.Advance(time.Millisecond)
+	waitFor(t, "the restart", func() bool { _, r, _ := h.snapshot(); return r == 1 })
+	h.waitIdle(t)
+	if attempts, _, closed := h.snapshot(); attempts != 1 || closed {
+		t.Errorf("%d restarts, closed %v; want 1 restart", attempts, closed)
+	}
+}
+
+func TestICERestart_FailedRestartsImmediately(t *testing.T) {
+	h := newRestartHarness(3)
+	h.r.stateChange(pion.ICEConnectionStateFailed)
+	waitFor(t, "the restart", func() bool { _, r, _ := h.snapshot(); return r == 1 })
+	h.waitIdle(t)
+	if n := h.clk.Pending(); n != 0 {
+		t.Errorf("%d timers pending after a successful restart, want 0", n)
+	}
+}
+
+func TestICERestart_Backoff(t *testing.T) {
+	h := newRestartHarness(3)
+	h.failing = true
+	h.r.stateChange(pion.ICEConnectionStateFailed)
+
+	waitFor(t, "the first attempt", func() bool { _, r, _ := h.snapshot(); return r == 1 })
+	h.clk.WaitPending(t, 1)
+	h.clk.Advance(DefaultRetryDelay)
+	waitFor(t, "the second attempt", func() bool { _, r, _ := h.snapshot(); return r == 2 })
+
+	// The delay doubles after each failed attempt
+	h.clk.WaitPending(t, 1)
+	h.clk.Advance(DefaultRetryDelay)
+	if attempts, _, _ := h.snapshot(); attempts != 2 || h.clk.Pending() != 1 {
+		t.Fatalf("%d attempts after %v, want the third to wait %v", attempts, DefaultRetryDelay, 2*DefaultRetryDelay)
+	}
+	h.clk.Advance(DefaultRetryDelay)
+	waitFor(t, "the third attempt", func() bool { _, r, _ := h.snapshot(); return r == 3 })
+
+	// The attempts are used up, so the connection is closed
+	h.clk.WaitPending(t, 1)
+	h.clk.Advance(4 * DefaultRetryDelay)
+	waitFor(t, "the connection to close", func() bool { _, _, c := h.snapshot(); return c })
+	h.waitIdle(t)
+	if attempts, _, _ := h.snapshot(); attempts != 3 {
+		t.Errorf("%d attempts, want 3", attempts)
+	}
+}
+
+func TestICERestart_AttemptsResetOnConnect(t *testing.T) {
+	h := newRestartHarness(1)
+
+	h.r.stateChange(pion.ICEConnectionStateFailed)
+	waitFor(t, "the first restart", func() bool { _, r, _ := h.snapshot(); return r == 1 })
+	h.waitIdle(t)
+	h.r.stateChange(pion.ICEConnectionStateConnected)
+
+	h.r.stateChange(pion.ICEConnectionStateFailed)
+	waitFor(t, "the second restart", func() bool { _, r, _ := h.snapshot(); return r == 2 })
+	h.waitIdle(t)
+	if _, _, closed := h.snapshot(); closed {
+		t.Fatal("closed after ICE connected between restarts")
+	}
+
+	// Without a connection in between, the single attempt is used up
+	h.r.stateChange(pion.ICEConnectionStateFailed)
+	waitFor(t, "the connection to close", func() bool { _, _, c := h.snapshot(); return c })
+	if attempts, _, _ := h.snapshot(); attempts != 2 {
+		t.Errorf("%d restarts, want 2", attempts)
+	}
+}
+
+func TestICERestart_StopsWhenClosed(t *testing.T) {
+	h := newRestartHarness(3)
+	h.failing = true
+	h.r.stateChange(pion.ICEConnectionStateFailed)
+	waitFor(t, "the first attempt", func() bool { _, r, _ := h.snapshot(); return r == 1 })
+	h.clk.WaitPending(t, 1)
+
+	close(h.done)
+	h.waitIdle(t)
+	h.clk.Advance(time.Minute)
+	if attempts, _, closed := h.snapshot(); attempts != 1 || closed {
+		t.Errorf("%d attempts, closed %v after the connection closed; want 1 attempt", attempts, closed)
+	}
+}
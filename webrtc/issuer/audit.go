@@ -0,0 +1,156 @@
+package issuer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Audit events.
+const (
+	AuditTokenIssued     = "token_issued"
+	AuditQuotaExceeded   = "quota_exceeded"
+	AuditSessionReleased = "session_released"
+
+	// Sessions proxied through SessionHandler
+	AuditProxySessionStarted = "proxy_session_started"
+	AuditProxySessionEnded   = "proxy_session_ended"
+)
+
+// webhookQueueSize bounds the records waiting to be posted to a webhook.
+const webhookQueueSize = 1024
+
+// AuditRecord is one audit log entry. It never contains the ephemeral key.
+type AuditRecord struct {
+	Time       time.Time `json:"time"`
+	Event      string    `json:"event"`
+	Subject    string    `json:"subject,omitempty"`
+	ClientIP   string    `json:"client_ip,omitempty"`
+	Origin     string    `json:"origin,omitempty"`
+	Deployment string    `json:"deployment,omitempty"`
+	Voice      string    `json:"voice,omitempty"`
+	SessionID  string    `json:"session_id,omitempty"`
+	ExpiresAt  int64     `json:"expires_at,omitempty"` // Unix seconds
+}
+
+// AuditSink stores audit records. Implementations must be safe for
+// concurrent use; Audit should not block the request for long.
+type AuditSink interface {
+	Audit(rec AuditRecord) error
+}
+
+// audit completes rec with the caller's identity and stores it.
+func (h *Handler) audit(r *http.Request, rec AuditRecord) {
+	if h.cfg.Audit == nil {
+		return
+	}
+	rec.Time = time.Now().UTC()
+	rec.Subject = Subject(r.Context())
+	rec.ClientIP = h.clientIP(r)
+	rec.Origin = r.Header.Get("Origin")
+	if err := h.cfg.Audit.Audit(rec); err != nil {
+		h.metrics.auditFailures.Add(1)
+		h.reportError(fmt.Errorf("audit: %w", err))
+	}
+}
+
+// NewWriterSink writes records to w as JSON lines.
+func NewWriterSink(w io.Writer) AuditSink {
+	return &writerSink{w: w}
+}
+
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *writerSink) Audit(rec AuditRecord) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(b, '\n'))
+	return err
+}
+
+// NewWebhookSink posts each record as JSON to url, in the background so a
+// slow collector does not delay tokens. Records are dropped, with an error,
+// when the queue is full; failed posts are passed to onError, which may be nil.
+func NewWebhookSink(url string, onError func(error)) AuditSink {
+	s := &webhookSink{
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		queue:   make(chan AuditRecord, webhookQueueSize),
+		onError: onError,
+	}
+	go s.run()
+	return s
+}
+
+type webhookSink struct {
+	url     string
+	client  *http.Client
+	queue   chan AuditRecord
+	onError func(error)
+}
+
+func (s *webhookSink) Audit(rec AuditRecord) error {
+	select {
+	case s.queue <- rec:
+		return nil
+	default:
+		return fmt.Errorf("audit webhook queue full; dropped %s record", rec.Event)
+	}
+}
+
+func (s *webhookSink) run() {
+	for rec := range s.queue {
+		if err := s.post(rec); err != nil && s.onError != nil {
+			s.onError(fmt.Errorf("audit webhook: %w", err))
+		}
+	}
+}
+
+func (s *webhookSink) post(rec AuditRecord) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// MultiSink sends records to every sink and reports the first error.
+type MultiSink []AuditSink
+
+func (m MultiSink) Audit(rec AuditRecord) error {
+	var first error
+	for _, s := range m {
+		if err := s.Audit(rec); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,132 @@
+package issuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v2"
+	oidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Authenticator verifies the caller of a token request and returns its
+// subject, which keys per-subject rate limits, quotas and audit records.
+// Errors reject the request with 401.
+type Authenticator func(r *http.Request) (subject string, err error)
+
+// ErrMissingBearer is returned by BearerToken for requests without a bearer token.
+var ErrMissingBearer = errors.New("missing bearer")
+
+// BearerToken returns the bearer token of the Authorization header.
+func BearerToken(r *http.Request) (string, error) {
+	auth := r.Header.Get("Authorization")
+	if len(auth) < len("Bearer ") || !strings.EqualFold(auth[:len("Bearer ")], "bearer ") {
+		return "", ErrMissingBearer
+	}
+	return strings.TrimSpace(auth[len("Bearer "):]), nil
+}
+
+// OIDCConfig configures NewOIDCAuthenticator.
+type OIDCConfig struct {
+	Issuer   string
+	Audience string
+	// TokenType is "access" for JWT access tokens (the default) or "id" for
+	// ID tokens.
+	TokenType string
+}
+
+// NewOIDCAuthenticator verifies bearer tokens from an OpenID Connect
+// provider such as Entra ID. It discovers the provider's keys from the issuer
+// URL, so it needs network access.
+func NewOIDCAuthenticator(ctx context.Context, cfg OIDCConfig) (Authenticator, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("oidc: issuer and audience are required")
+	}
+	prov, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc provider: %w", err)
+	}
+
+	if cfg.TokenType == "id" {
+		verifier := prov.Verifier(&oidc.Config{ClientID: cfg.Audience})
+		return func(r *http.Request) (string, error) {
+			raw, err := BearerToken(r)
+			if err != nil {
+				return "", err
+			}
+			idTok, err := verifier.Verify(r.Context(), raw)
+			if err != nil {
+				return "", err
+			}
+			return idTok.Subject, nil
+		}, nil
+	}
+
+	// Access token: verify against the provider's JWKS
+	var disc struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := prov.Claims(&disc); err != nil || disc.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc: failed to discover jwks_uri: %v", err)
+	}
+	jwks, err := keyfunc.Get(disc.JWKSURI, keyfunc.Options{
+		RefreshInterval: time.Hour,
+		RefreshTimeout:  10 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	return func(r *http.Request) (string, error) {
+		raw, err := BearerToken(r)
+		if err != nil {
+			return "", err
+		}
+		tok, err := jwt.Parse(raw, jwks.Keyfunc, jwt.WithAudience(cfg.Audience), jwt.WithIssuer(cfg.Issuer))
+		if err != nil {
+			return "", err
+		}
+		if !tok.Valid {
+			return "", errors.New("invalid token")
+		}
+		return tok.Claims.GetSubject()
+	}, nil
+}
+
+// subjectKey is the request context key of the authenticated subject.
+type subjectKey struct{}
+
+// WithSubject returns a copy of ctx carrying subject.
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// Subject returns the authenticated subject of a request context, or "".
+func Subject(ctx context.Context) string {
+	sub, _ := ctx.Value(subjectKey{}).(string)
+	return sub
+}
+
+// auth runs Config.Authenticate and stores the subject in the request context.
+func (h *Handler) auth(next http.Handler) http.Handler {
+	if h.cfg.Authenticate == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sub, err := h.cfg.Authenticate(r)
+		if errors.Is(err, ErrMissingBearer) {
+			h.metrics.authFailures.Add(1)
+			http.Error(w, "missing bearer", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			h.metrics.authFailures.Add(1)
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithSubject(r.Context(), sub)))
+	})
+}
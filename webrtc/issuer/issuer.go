@@ -0,0 +1,363 @@
+// Package issuer serves ephemeral keys for browser WebRTC clients over HTTP,
+// so an API key or Entra ID identity for Azure OpenAI never reaches the
+// browser. The handler verifies callers with an optional Authenticator (such
+// as NewOIDCAuthenticator), applies CORS, per-IP and per-subject rate limits,
+// session quotas and deployment and voice allowlists, writes an audit record
+// for every key, and serves Prometheus metrics and a readiness probe.
+//
+// Mount it in an existing server:
+//
+//	h := issuer.NewHandler(issuer.Config{
+//		Endpoint:   endpoint,
+//		Deployment: deployment,
+//		Region:     "eastus2",
+//		APIKey:     func() string { return apiKey },
+//	})
+//	mux.Handle("/issuer/", http.StripPrefix("/issuer", h))
+//
+// The cmd/ephemeral-issuer command runs the same handler from environment
+// settings.
+package issuer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/enesunal-m/azrealtime/webrtc"
+)
+
+// Defaults for zero Config fields.
+const (
+	DefaultAPIVersion      = "2025-04-01-preview"
+	DefaultSessionDuration = 30 * time.Minute
+	DefaultReadyCache      = 15 * time.Second
+)
+
+// mintTimeout bounds a call to the sessions endpoint.
+const mintTimeout = 10 * time.Second
+
+// ErrMint wraps errors from the sessions endpoint passed to Config.OnError,
+// e.g. to refresh a key that may have been rotated.
+var ErrMint = errors.New("mint failed")
+
+// Config configures NewHandler. Endpoint, Deployment and either Region or
+// WebRTCURL are required.
+type Config struct {
+	Endpoint   string // Azure OpenAI resource endpoint, e.g. https://{resource}.openai.azure.com
+	Deployment string // Default realtime deployment
+	Region     string // Azure region of the WebRTC endpoint, e.g. "eastus2"
+	WebRTCURL  string // Overrides the region URL, e.g. for sovereign clouds
+	APIVersion string // Defaults to DefaultAPIVersion
+	Voice      string // Default voice; empty uses the service default
+
+	// APIKey returns the current API key; it is called for every request so a
+	// rotated key takes effect. When it is nil or returns "", AccessToken
+	// authenticates to Azure OpenAI with Entra ID instead.
+	APIKey      func() string
+	AccessToken func(ctx context.Context) (string, error)
+
+	// Deployments and voices clients may request besides the defaults.
+	AllowedDeployments []string
+	AllowedVoices      []string
+
+	// Authenticate verifies callers and returns their subject. Nil lets
+	// anyone request keys, and disables per-subject limits and quotas.
+	Authenticate Authenticator
+
+	// Origins allowed by CORS; empty allows any origin, as does "*".
+	AllowedOrigins []string
+
+	// Rate limits; a zero per-minute rate disables the limit. Bursts default to 1.
+	IPRatePerMinute      int
+	IPBurst              int
+	SubjectRatePerMinute int
+	SubjectBurst         int
+	TrustProxyHeaders    bool // Take the client IP from X-Forwarded-For
+
+	// TrustedProxyHops is how many proxies in front of the issuer append to
+	// X-Forwarded-For (1 when zero). The client IP is the entry the farthest
+	// of them appended; entries to its left come from the client and are ignored.
+	TrustedProxyHops int
+
+	// Session quotas per subject; zero is unlimited. A session counts as
+	// active for SessionDuration (DefaultSessionDuration when zero), or until
+	// the client releases it with DELETE /token.
+	DailySessions      int
+	ConcurrentSessions int
+	SessionDuration    time.Duration
+
+	// Audit receives a record for every issued key, quota rejection,
+	// released session and proxied session; nil disables auditing.
+	Audit AuditSink
+
+	// ReadyCache is how long a readiness check result is reused
+	// (DefaultReadyCache when zero).
+	ReadyCache time.Duration
+
+	// OnError is called for failed mints (wrapping ErrMint), audit failures
+	// and readiness check failures.
+	OnError func(error)
+}
+
+// TokenRequest is the optional JSON body of POST /token. Empty fields select
+// the defaults.
+type TokenRequest struct {
+	Deployment string `json:"deployment,omitempty"`
+	Voice      string `json:"voice,omitempty"`
+}
+
+// TokenResponse is the JSON body returned by /token.
+type TokenResponse struct {
+	SessionID  string `json:"session_id"`
+	Ephemeral  string `json:"ephemeral"`
+	RegionURL  string `json:"region_url"`
+	Deployment string `json:"deployment"`
+	Voice      string `json:"voice,omitempty"`
+	ExpiresAt  int64  `json:"expires_at,omitempty"` // Unix seconds; lets browsers refresh before the key lapses
+}
+
+// maxTokenRequestBytes bounds the body of POST /token.
+const maxTokenRequestBytes = 4 << 10
+
+// errNotAllowed rejects a deployment or voice outside the allowlists.
+var errNotAllowed = errors.New("not allowed")
+
+// Handler serves the issuer's endpoints:
+//
+//   - /token: GET or POST mints a key, DELETE releases a session
+//   - /metrics: Prometheus metrics
+//   - /readyz: 200 when Azure OpenAI is reachable, 503 otherwise
+//   - /healthz: always 200
+//
+// TokenHandler, MetricsHandler and ReadyHandler serve them individually.
+type Handler struct {
+	cfg            Config
+	mux            *http.ServeMux
+	ipLimiter      *limiter
+	subjectLimiter *limiter
+	quotas         *quotas
+	readiness      *readiness
+	metrics        *metrics
+}
+
+// NewHandler returns a Handler for cfg.
+func NewHandler(cfg Config) *Handler {
+	if cfg.APIVersion == "" {
+		cfg.APIVersion = DefaultAPIVersion
+	}
+	if cfg.SessionDuration <= 0 {
+		cfg.SessionDuration = DefaultSessionDuration
+	}
+	if cfg.ReadyCache <= 0 {
+		cfg.ReadyCache = DefaultReadyCache
+	}
+	if cfg.TrustedProxyHops <= 0 {
+		cfg.TrustedProxyHops = 1
+	}
+	h := &Handler{
+		cfg:            cfg,
+		ipLimiter:      newLimiter(cfg.IPRatePerMinute, cfg.IPBurst),
+		subjectLimiter: newLimiter(cfg.SubjectRatePerMinute, cfg.SubjectBurst),
+		quotas:         newQuotas(cfg.DailySessions, cfg.ConcurrentSessions, cfg.SessionDuration),
+		metrics:        newMetrics(),
+	}
+	h.readiness = newReadiness(h.checkAzure, cfg.ReadyCache, h.reportError)
+
+	h.mux = http.NewServeMux()
+	h.mux.Handle("/token", h.TokenHandler())
+	h.mux.Handle("/metrics", h.MetricsHandler())
+	h.mux.Handle("/readyz", h.ReadyHandler())
+	h.mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// TokenHandler serves /token with CORS, authentication and limits applied.
+func (h *Handler) TokenHandler() http.Handler {
+	return h.cors(h.limitByIP(h.auth(h.limitBySubject(http.HandlerFunc(h.handleToken)))))
+}
+
+// SessionHandler serves next, typically a gateway.Gateway, behind the same
+// authentication, rate limits, session quotas and audit log as /token. The
+// bearer token may also come in the access_token query parameter, since
+// browsers cannot set headers on a WebSocket handshake. An upgraded
+// connection counts against the caller's quotas until next returns; next
+// finds the caller in Subject(r.Context()).
+func (h *Handler) SessionHandler(next http.Handler) http.Handler {
+	return h.limitByIP(tokenFromQuery(h.auth(h.limitBySubject(h.admitSession(next)))))
+}
+
+// Stats returns the handler's counters.
+func (h *Handler) Stats() Stats {
+	return h.metrics.stats()
+}
+
+func (h *Handler) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		h.handleRelease(w, r)
+		return
+	}
+	deployment, voice, err := h.route(r)
+	if errors.Is(err, errNotAllowed) {
+		http.Error(w, "deployment or voice not allowed", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid token request", http.StatusBadRequest)
+		return
+	}
+
+	sub := Subject(r.Context())
+	if wait, err := h.quotas.acquire(sub, time.Now()); err != nil {
+		h.metrics.quotaExceeded.Add(1)
+		h.audit(r, AuditRecord{Event: AuditQuotaExceeded, Deployment: deployment, Voice: voice})
+		tooManyRequests(w, wait)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), mintTimeout)
+	defer cancel()
+	var opts webrtc.MintSessionOptions
+	if voice != "" {
+		opts.Session.Voice = &voice
+	}
+	apiKey := h.apiKey()
+	if apiKey == "" {
+		opts.AccessToken = h.cfg.AccessToken
+	}
+	start := time.Now()
+	key, err := webrtc.MintEphemeralSession(ctx, h.cfg.Endpoint, h.cfg.APIVersion, deployment, apiKey, opts)
+	h.metrics.mintLatency.observe(time.Since(start))
+	if err != nil {
+		h.metrics.mintFailures.Add(1)
+		h.quotas.cancel(sub, time.Now())
+		h.reportError(fmt.Errorf("%w: %w", ErrMint, err))
+		http.Error(w, "mint failed", http.StatusBadGateway)
+		return
+	}
+	h.metrics.tokensIssued.Add(1)
+	h.quotas.commit(sub, key.SessionID, time.Now())
+	resp := TokenResponse{
+		SessionID:  key.SessionID,
+		Ephemeral:  key.Value,
+		RegionURL:  h.regionURL(),
+		Deployment: deployment,
+		Voice:      voice,
+	}
+	if !key.ExpiresAt.IsZero() {
+		resp.ExpiresAt = key.ExpiresAt.Unix()
+	}
+	h.audit(r, AuditRecord{
+		Event:      AuditTokenIssued,
+		Deployment: deployment,
+		Voice:      voice,
+		SessionID:  key.SessionID,
+		ExpiresAt:  resp.ExpiresAt,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handleRelease frees a session counted against the caller's concurrent
+// quota: DELETE /token?session_id=...
+func (h *Handler) handleRelease(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("session_id")
+	if id == "" {
+		http.Error(w, "missing session_id", http.StatusBadRequest)
+		return
+	}
+	if !h.quotas.release(Subject(r.Context()), id, time.Now()) {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+	h.audit(r, AuditRecord{Event: AuditSessionReleased, SessionID: id})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// route picks the deployment and voice for a token request. GET requests and
+// empty fields get the defaults; anything else must be on the allowlists.
+func (h *Handler) route(r *http.Request) (deployment, voice string, err error) {
+	deployment, voice = h.cfg.Deployment, h.cfg.Voice
+	if r.Method != http.MethodPost || r.ContentLength == 0 {
+		return deployment, voice, nil
+	}
+
+	var req TokenRequest
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxTokenRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return "", "", err
+	}
+	if req.Deployment != "" {
+		if req.Deployment != h.cfg.Deployment && !contains(h.cfg.AllowedDeployments, req.Deployment) {
+			return "", "", errNotAllowed
+		}
+		deployment = req.Deployment
+	}
+	if req.Voice != "" {
+		if req.Voice != h.cfg.Voice && !contains(h.cfg.AllowedVoices, req.Voice) {
+			return "", "", errNotAllowed
+		}
+		voice = req.Voice
+	}
+	return deployment, voice, nil
+}
+
+func (h *Handler) apiKey() string {
+	if h.cfg.APIKey == nil {
+		return ""
+	}
+	return h.cfg.APIKey()
+}
+
+// regionURL returns the WebRTC endpoint handed to browsers.
+func (h *Handler) regionURL() string {
+	if h.cfg.WebRTCURL != "" {
+		return h.cfg.WebRTCURL
+	}
+	return webrtc.RegionWebRTCURL(h.cfg.Region)
+}
+
+func (h *Handler) reportError(err error) {
+	if h.cfg.OnError != nil {
+		h.cfg.OnError(err)
+	}
+}
+
+// cors applies Config.AllowedOrigins and answers preflight requests.
+func (h *Handler) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := h.cfg.AllowedOrigins
+		if origin != "" && (len(allowed) == 0 || contains(allowed, origin) || contains(allowed, "*")) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Vary", "Origin")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func contains(a []string, v string) bool {
+	for _, x := range a {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,468 @@
+package issuer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// fakeAzure stands in for the sessions and models endpoints of a resource.
+type fakeAzure struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	minted   int
+	models   []string // Headers of models requests: api-key or Authorization
+	sessions []map[string]any
+	status   int // Returned by the sessions endpoint when non-zero
+}
+
+func newFakeAzure(t *testing.T) *fakeAzure {
+	t.Helper()
+	f := &fakeAzure{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		switch r.URL.Path {
+		case "/openai/models":
+			f.models = append(f.models, r.Header.Get("api-key")+r.Header.Get("Authorization"))
+			if r.Header.Get("api-key") == "bad" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		case "/openai/realtimeapi/sessions":
+			if f.status != 0 {
+				w.WriteHeader(f.status)
+				return
+			}
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			f.sessions = append(f.sessions, body)
+			f.minted++
+			fmt.Fprintf(w, `{"id":"sess_%d","client_secret":{"value":"ek_%d","expires_at":1700000000}}`, f.minted, f.minted)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func testConfig(f *fakeAzure) Config {
+	return Config{
+		Endpoint:   f.URL,
+		Deployment: "gpt-realtime",
+		Region:     "eastus2",
+		Voice:      "verse",
+		APIKey:     func() string { return "key" },
+	}
+}
+
+// headerAuth authenticates requests by their X-User header, for tests.
+func headerAuth(r *http.Request) (string, error) {
+	if u := r.Header.Get("X-User"); u != "" {
+		return u, nil
+	}
+	return "", ErrMissingBearer
+}
+
+func request(t *testing.T, h http.Handler, method, path, user, body string) (*httptest.ResponseRecorder, TokenResponse) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var resp TokenResponse
+	if rec.Code == http.StatusOK && path == "/token" {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode token response: %v: %s", err, rec.Body)
+		}
+	}
+	return rec, resp
+}
+
+func TestHandler_Token(t *testing.T) {
+	f := newFakeAzure(t)
+	var audit []AuditRecord
+	cfg := testConfig(f)
+	cfg.Audit = auditFunc(func(rec AuditRecord) error { audit = append(audit, rec); return nil })
+	h := NewHandler(cfg)
+
+	rec, resp := request(t, h, http.MethodGet, "/token", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	want := TokenResponse{
+		SessionID:  "sess_1",
+		Ephemeral:  "ek_1",
+		RegionURL:  "https://eastus2.realtimeapi-preview.ai.azure.com/v1/realtimertc",
+		Deployment: "gpt-realtime",
+		Voice:      "verse",
+		ExpiresAt:  1700000000,
+	}
+	if resp != want {
+		t.Errorf("response = %+v, want %+v", resp, want)
+	}
+	if got := f.sessions[0]["voice"]; got != "verse" {
+		t.Errorf("minted voice = %v", got)
+	}
+	if len(audit) != 1 || audit[0].Event != AuditTokenIssued || audit[0].SessionID != "sess_1" {
+		t.Errorf("audit = %+v", audit)
+	}
+	if st := h.Stats(); st.TokensIssued != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestHandler_Allowlist(t *testing.T) {
+	f := newFakeAzure(t)
+	cfg := testConfig(f)
+	cfg.AllowedDeployments = []string{"gpt-realtime-mini"}
+	cfg.AllowedVoices = []string{"alloy"}
+	h := NewHandler(cfg)
+
+	rec, resp := request(t, h, http.MethodPost, "/token", "", `{"deployment":"gpt-realtime-mini","voice":"alloy"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if resp.Deployment != "gpt-realtime-mini" || resp.Voice != "alloy" {
+		t.Errorf("response = %+v", resp)
+	}
+	if got := f.sessions[0]["model"]; got != "gpt-realtime-mini" {
+		t.Errorf("minted model = %v", got)
+	}
+
+	for body, code := range map[string]int{
+		`{"deployment":"other"}`: http.StatusForbidden,
+		`{"voice":"other"}`:      http.StatusForbidden,
+		`{"model":"x"}`:          http.StatusBadRequest,
+		`{bad`:                   http.StatusBadRequest,
+	} {
+		if rec, _ := request(t, h, http.MethodPost, "/token", "", body); rec.Code != code {
+			t.Errorf("%s: status %d, want %d", body, rec.Code, code)
+		}
+	}
+	if f.minted != 1 {
+		t.Errorf("minted %d keys, want 1", f.minted)
+	}
+}
+
+func TestHandler_Auth(t *testing.T) {
+	f := newFakeAzure(t)
+	cfg := testConfig(f)
+	var subjects []string
+	cfg.Authenticate = headerAuth
+	cfg.Audit = auditFunc(func(rec AuditRecord) error { subjects = append(subjects, rec.Subject); return nil })
+	h := NewHandler(cfg)
+
+	if rec, _ := request(t, h, http.MethodGet, "/token", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without user: status %d", rec.Code)
+	}
+	if rec, _ := request(t, h, http.MethodGet, "/token", "alice", ""); rec.Code != http.StatusOK {
+		t.Errorf("with user: status %d", rec.Code)
+	}
+	if len(subjects) != 1 || subjects[0] != "alice" {
+		t.Errorf("audited subjects = %v", subjects)
+	}
+	if st := h.Stats(); st.AuthFailures != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestHandler_Quotas(t *testing.T) {
+	f := newFakeAzure(t)
+	cfg := testConfig(f)
+	cfg.Authenticate = headerAuth
+	cfg.DailySessions = 3
+	cfg.ConcurrentSessions = 1
+	h := NewHandler(cfg)
+
+	_, first := request(t, h, http.MethodGet, "/token", "alice", "")
+	rec, _ := request(t, h, http.MethodGet, "/token", "alice", "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("second concurrent session: status %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec, _ := request(t, h, http.MethodGet, "/token", "bob", ""); rec.Code != http.StatusOK {
+		t.Errorf("other subject: status %d", rec.Code)
+	}
+
+	if rec, _ := request(t, h, http.MethodDelete, "/token?session_id="+first.SessionID, "alice", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("release: status %d", rec.Code)
+	}
+	if rec, _ := request(t, h, http.MethodDelete, "/token?session_id="+first.SessionID, "alice", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("second release: status %d", rec.Code)
+	}
+	if rec, _ := request(t, h, http.MethodGet, "/token", "alice", ""); rec.Code != http.StatusOK {
+		t.Errorf("after release: status %d", rec.Code)
+	}
+	if st := h.Stats(); st.QuotaExceeded != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestQuotas_Daily(t *testing.T) {
+	q := newQuotas(2, 0, time.Minute)
+	now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if _, err := q.acquire("alice", now); err != nil {
+			t.Fatal(err)
+		}
+		q.commit("alice", fmt.Sprint(i), now)
+	}
+	wait, err := q.acquire("alice", now)
+	if !errors.Is(err, errQuotaExceeded) || wait != time.Hour {
+		t.Fatalf("third session: wait %v, err %v", wait, err)
+	}
+
+	// A failed mint gives its session back
+	q = newQuotas(1, 0, time.Minute)
+	if _, err := q.acquire("alice", now); err != nil {
+		t.Fatal(err)
+	}
+	q.cancel("alice", now)
+	if _, err := q.acquire("alice", now); err != nil {
+		t.Errorf("after cancel: %v", err)
+	}
+	q.commit("alice", "a", now)
+
+	// The count resets at midnight UTC
+	if _, err := q.acquire("alice", now.Add(time.Hour)); err != nil {
+		t.Errorf("next day: %v", err)
+	}
+}
+
+func TestHandler_MintFailure(t *testing.T) {
+	f := newFakeAzure(t)
+	f.status = http.StatusUnauthorized
+	var reported []error
+	cfg := testConfig(f)
+	cfg.OnError = func(err error) { reported = append(reported, err) }
+	h := NewHandler(cfg)
+
+	if rec, _ := request(t, h, http.MethodGet, "/token", "", ""); rec.Code != http.StatusBadGateway {
+		t.Errorf("status %d", rec.Code)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], ErrMint) {
+		t.Errorf("reported = %v", reported)
+	}
+}
+
+func TestHandler_AccessToken(t *testing.T) {
+	f := newFakeAzure(t)
+	cfg := testConfig(f)
+	cfg.APIKey = nil
+	cfg.AccessToken = func(ctx context.Context) (string, error) { return "entra", nil }
+	h := NewHandler(cfg)
+
+	if rec, _ := request(t, h, http.MethodGet, "/readyz", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("readyz: status %d", rec.Code)
+	}
+	if len(f.models) != 1 || f.models[0] != "Bearer entra" {
+		t.Errorf("models requests = %q", f.models)
+	}
+}
+
+func TestHandler_ReadyAndMetrics(t *testing.T) {
+	f := newFakeAzure(t)
+	key := "bad"
+	var reported []error
+	cfg := testConfig(f)
+	cfg.APIKey = func() string { return key }
+	cfg.ReadyCache = time.Nanosecond
+	cfg.OnError = func(err error) { reported = append(reported, err) }
+	h := NewHandler(cfg)
+
+	if rec, _ := request(t, h, http.MethodGet, "/readyz", "", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("bad key: status %d", rec.Code)
+	}
+	if len(reported) != 1 {
+		t.Errorf("reported = %v", reported)
+	}
+	key = "key"
+	if rec, _ := request(t, h, http.MethodGet, "/readyz", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("good key: status %d", rec.Code)
+	}
+	request(t, h, http.MethodGet, "/token", "", "")
+
+	rec, _ := request(t, h, http.MethodGet, "/metrics", "", "")
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		"ephemeral_issuer_tokens_issued_total 1\n",
+		"ephemeral_issuer_mint_duration_seconds_count 1\n",
+		`ephemeral_issuer_mint_duration_seconds_bucket{le="+Inf"} 1`,
+		"ephemeral_issuer_ready 1\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestHandler_CORS(t *testing.T) {
+	f := newFakeAzure(t)
+	cfg := testConfig(f)
+	cfg.AllowedOrigins = []string{"https://app.example.com"}
+	h := NewHandler(cfg)
+
+	for origin, allowed := range map[string]bool{"https://app.example.com": true, "https://evil.example.com": false} {
+		req := httptest.NewRequest(http.MethodOptions, "/token", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("%s: status %d", origin, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin") != ""; got != allowed {
+			t.Errorf("%s: allowed = %v, want %v", origin, got, allowed)
+		}
+	}
+}
+
+// auditFunc adapts a function to AuditSink.
+type auditFunc func(AuditRecord) error
+
+func (f auditFunc) Audit(rec AuditRecord) error { return f(rec) }
+
+func TestHandler_SessionHandler(t *testing.T) {
+	f := newFakeAzure(t)
+	var mu sync.Mutex
+	var events []string
+	cfg := testConfig(f)
+	cfg.Authenticate = func(r *http.Request) (string, error) {
+		tok, err := BearerToken(r)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimPrefix(tok, "user-"), nil
+	}
+	cfg.ConcurrentSessions = 1
+	cfg.Audit = auditFunc(func(rec AuditRecord) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, rec.Subject+" "+rec.Event)
+		return nil
+	})
+	h := NewHandler(cfg)
+	srv := httptest.NewServer(h.SessionHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		_, _, _ = c.Read(r.Context()) // Until the client closes
+	})))
+	defer srv.Close()
+
+	dial := func(query string) (*websocket.Conn, int) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		c, resp, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+query, nil)
+		if err != nil {
+			if resp == nil {
+				t.Fatal(err)
+			}
+			return nil, resp.StatusCode
+		}
+		return c, http.StatusSwitchingProtocols
+	}
+
+	if _, code := dial(""); code != http.StatusUnauthorized {
+		t.Errorf("without token: status %d", code)
+	}
+	c, code := dial("?access_token=user-alice")
+	if code != http.StatusSwitchingProtocols {
+		t.Fatalf("first session: status %d", code)
+	}
+	if _, code := dial("?access_token=user-alice"); code != http.StatusTooManyRequests {
+		t.Errorf("second concurrent session: status %d", code)
+	}
+	c.Close(websocket.StatusNormalClosure, "")
+	ended := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) > 0 && events[len(events)-1] == "alice proxy_session_ended"
+	}
+	for deadline := time.Now().Add(5 * time.Second); !ended(); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("session did not end")
+		}
+	}
+	if c, code = dial("?access_token=user-alice"); code != http.StatusSwitchingProtocols {
+		t.Fatalf("session after the first ended: status %d", code)
+	}
+	c.Close(websocket.StatusNormalClosure, "")
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := "alice proxy_session_started,alice quota_exceeded,alice proxy_session_ended,alice proxy_session_started"
+	if got := strings.Join(events, ","); !strings.HasPrefix(got, want) {
+		t.Errorf("audit = %q, want prefix %q", got, want)
+	}
+	if st := h.Stats(); st.AuthFailures != 1 || st.QuotaExceeded != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(60, 2) // One token a second, two at once
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("a", now); !ok {
+			t.Fatalf("request %d within the burst was limited", i)
+		}
+	}
+	if ok, wait := l.allow("a", now); ok || wait != time.Second {
+		t.Errorf("request over the burst: ok=%v wait=%v, want false and 1s", ok, wait)
+	}
+	if ok, _ := l.allow("b", now); !ok {
+		t.Error("another key was limited")
+	}
+	if ok, _ := l.allow("a", now.Add(time.Second)); !ok {
+		t.Error("the bucket did not refill")
+	}
+	if newLimiter(0, 5) != nil {
+		t.Error("a zero rate should disable the limiter")
+	}
+}
+
+func TestHandler_LimitByIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	f := newFakeAzure(t)
+	cfg := testConfig(f)
+	cfg.IPRatePerMinute = 1
+	cfg.TrustProxyHeaders = true
+	h := NewHandler(cfg)
+
+	// The client sends its own X-Forwarded-For; the proxy appends the real address
+	for i, spoofed := range []string{"1.1.1.1", "2.2.2.2"} {
+		req := httptest.NewRequest(http.MethodGet, "/token", nil)
+		req.Header.Set("X-Forwarded-For", spoofed+", 203.0.113.7")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if want := []int{http.StatusOK, http.StatusTooManyRequests}[i]; rec.Code != want {
+			t.Errorf("request %d with X-Forwarded-For %s: status %d, want %d", i, spoofed, rec.Code, want)
+		}
+	}
+	if st := h.Stats(); st.RateLimitedIP != 1 {
+		t.Errorf("RateLimitedIP = %d, want 1", st.RateLimitedIP)
+	}
+
+	// Two proxies: the client is the second entry from the right
+	cfg.TrustedProxyHops = 2
+	h = NewHandler(cfg)
+	req := httptest.NewRequest(http.MethodGet, "/token", nil)
+	req.Header.Add("X-Forwarded-For", "1.1.1.1, 203.0.113.7")
+	req.Header.Add("X-Forwarded-For", "10.0.0.1")
+	if ip := h.clientIP(req); ip != "203.0.113.7" {
+		t.Errorf("clientIP = %q, want 203.0.113.7", ip)
+	}
+}
@@ -0,0 +1,126 @@
+package issuer
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// metricsPrefix namespaces the Prometheus metrics.
+const metricsPrefix = "ephemeral_issuer_"
+
+// Stats are a Handler's counters since it was created.
+type Stats struct {
+	TokensIssued       int64
+	MintFailures       int64
+	AuthFailures       int64
+	RateLimitedIP      int64
+	RateLimitedSubject int64
+	QuotaExceeded      int64
+	AuditFailures      int64
+}
+
+type metrics struct {
+	tokensIssued       atomic.Int64
+	mintFailures       atomic.Int64
+	authFailures       atomic.Int64
+	rateLimitedIP      atomic.Int64
+	rateLimitedSubject atomic.Int64
+	quotaExceeded      atomic.Int64
+	auditFailures      atomic.Int64
+
+	// mintLatency measures calls to the sessions endpoint, successful or not.
+	mintLatency *histogram
+}
+
+func newMetrics() *metrics {
+	return &metrics{mintLatency: newHistogram(0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10)}
+}
+
+func (m *metrics) stats() Stats {
+	return Stats{
+		TokensIssued:       m.tokensIssued.Load(),
+		MintFailures:       m.mintFailures.Load(),
+		AuthFailures:       m.authFailures.Load(),
+		RateLimitedIP:      m.rateLimitedIP.Load(),
+		RateLimitedSubject: m.rateLimitedSubject.Load(),
+		QuotaExceeded:      m.quotaExceeded.Load(),
+		AuditFailures:      m.auditFailures.Load(),
+	}
+}
+
+// histogram is a Prometheus histogram of durations in seconds.
+type histogram struct {
+	mu      sync.Mutex
+	bounds  []float64 // Upper bounds, ascending
+	buckets []uint64  // Observations per bound, not cumulative
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(bounds ...float64) *histogram {
+	return &histogram{bounds: bounds, buckets: make([]uint64, len(bounds))}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	v := d.Seconds()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.count++
+	h.sum += v
+	for i, b := range h.bounds {
+		if v <= b {
+			h.buckets[i]++
+			break
+		}
+	}
+}
+
+func (h *histogram) write(w io.Writer, name, help string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	var cum uint64
+	for i, b := range h.bounds {
+		cum += h.buckets[i]
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, strconv.FormatFloat(b, 'g', -1, 64), cum)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// MetricsHandler serves the handler's metrics in the Prometheus text format.
+func (h *Handler) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		st := h.metrics.stats()
+		for _, c := range []struct {
+			name, help string
+			v          int64
+		}{
+			{"tokens_issued_total", "Ephemeral keys minted.", st.TokensIssued},
+			{"mint_failures_total", "Failed calls to the sessions endpoint.", st.MintFailures},
+			{"auth_failures_total", "Requests rejected for a missing or invalid bearer token.", st.AuthFailures},
+			{"rate_limited_ip_total", "Requests rejected by the per-IP rate limit.", st.RateLimitedIP},
+			{"rate_limited_subject_total", "Requests rejected by the per-subject rate limit.", st.RateLimitedSubject},
+			{"quota_exceeded_total", "Requests rejected by a session quota.", st.QuotaExceeded},
+			{"audit_failures_total", "Audit records that could not be stored.", st.AuditFailures},
+		} {
+			name := metricsPrefix + c.name
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, c.help, name, name, c.v)
+		}
+		h.metrics.mintLatency.write(w, metricsPrefix+"mint_duration_seconds", "Latency of calls to the sessions endpoint.")
+
+		ready := 0
+		if h.readiness.lastErr() == nil {
+			ready = 1
+		}
+		name := metricsPrefix + "ready"
+		fmt.Fprintf(w, "# HELP %s Whether the last readiness check reached Azure OpenAI.\n# TYPE %s gauge\n%s %d\n", name, name, name, ready)
+	})
+}
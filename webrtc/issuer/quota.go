@@ -0,0 +1,159 @@
+package issuer
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// errQuotaExceeded rejects a subject that used up its daily or concurrent sessions.
+var errQuotaExceeded = errors.New("session quota exceeded")
+
+// quotas caps the sessions each authenticated subject may start per UTC day
+// and hold at once. The issuer does not see sessions end, so a session counts
+// as active for sessionTTL after its key was minted, or until the client
+// releases it with DELETE /token.
+type quotas struct {
+	daily      int // Zero: unlimited
+	concurrent int // Zero: unlimited
+	sessionTTL time.Duration
+
+	mu    sync.Mutex
+	subs  map[string]*usage
+	swept time.Time
+}
+
+type usage struct {
+	day     string               // UTC day the count is for
+	count   int                  // Sessions started on day, including pending
+	pending int                  // Reserved but not yet minted
+	active  map[string]time.Time // Session ID to when it stops counting
+}
+
+// newQuotas returns nil, which allows everything, if both limits are off.
+func newQuotas(daily, concurrent int, sessionTTL time.Duration) *quotas {
+	if daily <= 0 && concurrent <= 0 {
+		return nil
+	}
+	return &quotas{daily: daily, concurrent: concurrent, sessionTTL: sessionTTL, subs: make(map[string]*usage)}
+}
+
+// acquire reserves a session for sub before a key is minted. On
+// errQuotaExceeded it reports how long until a session is available. Every
+// successful acquire must be followed by commit or cancel.
+func (q *quotas) acquire(sub string, now time.Time) (time.Duration, error) {
+	if q == nil || sub == "" {
+		return 0, nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	u := q.usage(sub, now)
+	if q.daily > 0 && u.count >= q.daily {
+		midnight := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+		return midnight.Sub(now), errQuotaExceeded
+	}
+	if q.concurrent > 0 && len(u.active)+u.pending >= q.concurrent {
+		wait := q.sessionTTL
+		for _, until := range u.active {
+			wait = min(wait, until.Sub(now))
+		}
+		return wait, errQuotaExceeded
+	}
+	u.count++
+	u.pending++
+	return 0, nil
+}
+
+// commit turns a reservation into an active session.
+func (q *quotas) commit(sub, sessionID string, now time.Time) {
+	if q == nil || sub == "" {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	u := q.usage(sub, now)
+	u.pending = max(0, u.pending-1)
+	u.active[sessionID] = now.Add(q.sessionTTL)
+}
+
+// cancel gives back a reservation whose mint failed.
+func (q *quotas) cancel(sub string, now time.Time) {
+	if q == nil || sub == "" {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	u := q.usage(sub, now)
+	u.pending = max(0, u.pending-1)
+	u.count = max(0, u.count-1)
+}
+
+// end finishes a reservation held for the whole of a proxied session; the
+// session still counts toward the day.
+func (q *quotas) end(sub string, now time.Time) {
+	if q == nil || sub == "" {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	u := q.usage(sub, now)
+	u.pending = max(0, u.pending-1)
+}
+
+// release ends an active session of sub early. It reports whether the
+// session was active.
+func (q *quotas) release(sub, sessionID string, now time.Time) bool {
+	if q == nil || sub == "" {
+		return false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	u := q.usage(sub, now)
+	if _, ok := u.active[sessionID]; !ok {
+		return false
+	}
+	delete(u.active, sessionID)
+	return true
+}
+
+// usage returns sub's usage with expired sessions and past days dropped.
+// q.mu must be held.
+func (q *quotas) usage(sub string, now time.Time) *usage {
+	q.sweep(now)
+	today := now.UTC().Format(time.DateOnly)
+	u, ok := q.subs[sub]
+	if !ok {
+		u = &usage{day: today, active: make(map[string]time.Time)}
+		q.subs[sub] = u
+	}
+	u.expire(now)
+	if u.day != today {
+		u.day, u.count = today, u.pending
+	}
+	return u
+}
+
+// sweep forgets subjects with nothing left to track, at most every
+// idleBucketTTL. q.mu must be held.
+func (q *quotas) sweep(now time.Time) {
+	if now.Sub(q.swept) < idleBucketTTL {
+		return
+	}
+	q.swept = now
+	today := now.UTC().Format(time.DateOnly)
+	for k, u := range q.subs {
+		u.expire(now)
+		if u.day != today && u.pending == 0 && len(u.active) == 0 {
+			delete(q.subs, k)
+		}
+	}
+}
+
+// expire drops sessions that no longer count as active.
+func (u *usage) expire(now time.Time) {
+	for id, until := range u.active {
+		if !now.Before(until) {
+			delete(u.active, id)
+		}
+	}
+}
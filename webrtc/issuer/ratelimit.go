@@ -0,0 +1,142 @@
+package issuer
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// idleBucketTTL is how long an unused bucket is kept before it is dropped.
+const idleBucketTTL = 10 * time.Minute
+
+// limiter is a token bucket per key: each key may make burst requests at once
+// and then perMinute requests a minute.
+type limiter struct {
+	rate  float64 // Tokens per second
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter returns nil, which allows everything, if perMinute is not positive.
+func newLimiter(perMinute, burst int) *limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	return &limiter{rate: float64(perMinute) / 60, burst: float64(burst), buckets: make(map[string]*bucket)}
+}
+
+// allow takes a token for key, or reports how long until one is available.
+func (l *limiter) allow(key string, now time.Time) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets unused for idleBucketTTL.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < idleBucketTTL {
+		return
+	}
+	l.swept = now
+	for k, b := range l.buckets {
+		if now.Sub(b.last) > idleBucketTTL {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// limitByIP applies the per-IP rate limit.
+func (h *Handler) limitByIP(next http.Handler) http.Handler {
+	if h.ipLimiter == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := h.ipLimiter.allow(h.clientIP(r), time.Now()); !ok {
+			h.metrics.rateLimitedIP.Add(1)
+			tooManyRequests(w, wait)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitBySubject applies the per-subject rate limit; it runs after auth.
+func (h *Handler) limitBySubject(next http.Handler) http.Handler {
+	if h.subjectLimiter == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sub := Subject(r.Context())
+		if sub == "" {
+			next.ServeHTTP(w, r) // No Authenticator: only the IP limit applies
+			return
+		}
+		if ok, wait := h.subjectLimiter.allow(sub, time.Now()); !ok {
+			h.metrics.rateLimitedSubject.Add(1)
+			tooManyRequests(w, wait)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the caller's address, taken from X-Forwarded-For when the
+// issuer runs behind trusted proxies. Clients can send their own
+// X-Forwarded-For, so the address is counted from the right: it is the entry
+// appended by the farthest of Config.TrustedProxyHops proxies.
+func (h *Handler) clientIP(r *http.Request) string {
+	if h.cfg.TrustProxyHeaders {
+		var hops []string
+		for _, v := range r.Header.Values("X-Forwarded-For") {
+			for _, hop := range strings.Split(v, ",") {
+				if hop = strings.TrimSpace(hop); hop != "" {
+					hops = append(hops, hop)
+				}
+			}
+		}
+		if len(hops) > 0 {
+			return hops[max(0, len(hops)-h.cfg.TrustedProxyHops)]
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func tooManyRequests(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+}
@@ -0,0 +1,97 @@
+package issuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errNotChecked is the readiness before the first check.
+var errNotChecked = errors.New("not checked yet")
+
+// readiness caches the result of a check so frequent probes do not each call
+// Azure OpenAI.
+type readiness struct {
+	check   func(ctx context.Context) error
+	ttl     time.Duration
+	onError func(error) // Called when the check starts failing or fails differently
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+func newReadiness(check func(ctx context.Context) error, ttl time.Duration, onError func(error)) *readiness {
+	return &readiness{check: check, ttl: ttl, onError: onError, err: errNotChecked}
+}
+
+// Check runs the check unless it ran within ttl, and returns its result.
+func (rd *readiness) Check(ctx context.Context) error {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	if !rd.checked.IsZero() && time.Since(rd.checked) < rd.ttl {
+		return rd.err
+	}
+	err := rd.check(ctx)
+	if err != nil && (rd.err == nil || rd.err.Error() != err.Error()) {
+		rd.onError(fmt.Errorf("not ready: %w", err))
+	}
+	rd.checked, rd.err = time.Now(), err
+	return err
+}
+
+// lastErr returns the result of the last check without running one.
+func (rd *readiness) lastErr() error {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	return rd.err
+}
+
+// checkAzure lists the resource's models with the handler's credentials,
+// which needs DNS, TLS and a key or Entra ID token the resource accepts.
+func (h *Handler) checkAzure(ctx context.Context) error {
+	u := strings.TrimRight(h.cfg.Endpoint, "/") + "/openai/models?api-version=" + url.QueryEscape(h.cfg.APIVersion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if key := h.apiKey(); key != "" || h.cfg.AccessToken == nil {
+		req.Header.Set("api-key", key)
+	} else {
+		token, err := h.cfg.AccessToken(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden || resp.StatusCode >= 500 {
+		return fmt.Errorf("azure openai: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ReadyHandler reports 200 when Azure OpenAI is reachable with the handler's
+// credentials and 503 otherwise. Results are cached for Config.ReadyCache.
+func (h *Handler) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		if err := h.readiness.Check(ctx); err != nil {
+			http.Error(w, "not ready", http.StatusServiceUnavailable) // The cause goes to OnError
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+}
@@ -0,0 +1,72 @@
+package issuer
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// tokenQueryParam is the query parameter a browser may carry its bearer
+// token in on a WebSocket handshake.
+const tokenQueryParam = "access_token"
+
+// tokenFromQuery moves a bearer token in the query to the Authorization
+// header, where Authenticators look for it.
+func tokenFromQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tok := r.URL.Query().Get(tokenQueryParam); tok != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+tok)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// admitSession holds a quota reservation while next serves an upgraded
+// connection, and audits its start and end.
+func (h *Handler) admitSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sub := Subject(r.Context())
+		if wait, err := h.quotas.acquire(sub, time.Now()); err != nil {
+			h.metrics.quotaExceeded.Add(1)
+			h.audit(r, AuditRecord{Event: AuditQuotaExceeded})
+			tooManyRequests(w, wait)
+			return
+		}
+		uw := &upgradeWriter{ResponseWriter: w, onUpgrade: func() {
+			h.audit(r, AuditRecord{Event: AuditProxySessionStarted})
+		}}
+		next.ServeHTTP(uw, r)
+		if !uw.upgraded {
+			h.quotas.cancel(sub, time.Now()) // Rejected or failed to connect
+			return
+		}
+		h.quotas.end(sub, time.Now())
+		h.audit(r, AuditRecord{Event: AuditProxySessionEnded})
+	})
+}
+
+// upgradeWriter notices a 101 Switching Protocols response and passes
+// hijacking through to the underlying ResponseWriter.
+type upgradeWriter struct {
+	http.ResponseWriter
+	upgraded  bool
+	onUpgrade func()
+}
+
+func (w *upgradeWriter) WriteHeader(code int) {
+	if code == http.StatusSwitchingProtocols && !w.upgraded {
+		w.upgraded = true
+		w.onUpgrade()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *upgradeWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("issuer: response writer cannot be hijacked")
+	}
+	return hj.Hijack()
+}
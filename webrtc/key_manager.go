@@ -0,0 +1,185 @@
+package webrtc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// KeyManagerOptions configures NewKeyManager.
+type KeyManagerOptions struct {
+	// Mint obtains a fresh ephemeral key. Required.
+	// EphemeralMinter builds one that calls MintEphemeral.
+	Mint func(ctx context.Context) (EphemeralKey, error)
+
+	// RefreshBefore is how long before expiry a cached key stops being handed
+	// out and a new one is minted. Default: DefaultRefreshBefore.
+	RefreshBefore time.Duration
+	// DefaultTTL is the key lifetime assumed when EphemeralKey.ExpiresAt is zero.
+	// Default: DefaultKeyTTL.
+	DefaultTTL time.Duration
+	// RetryDelay is the pause before Run retries a failed mint. Default: DefaultRetryDelay.
+	RetryDelay time.Duration
+
+	// OnKeyRefreshed is called each time a new key has been minted.
+	OnKeyRefreshed func(EphemeralKey)
+	// OnError is called when Run fails to mint a key; it then retries.
+	OnError func(error)
+}
+
+// KeyManager caches an ephemeral key and mints a new one shortly before it
+// expires, so connection attempts and reconnects share keys instead of each
+// minting its own. Concurrent requests for a key share a single mint.
+//
+// Use Key wherever a key is needed, e.g. as KeyRefreshOptions.Mint, and call
+// Run in a goroutine to refresh keys ahead of time rather than on demand.
+// A KeyManager is safe for concurrent use.
+type KeyManager struct {
+	opt   KeyManagerOptions
+	clock clock
+
+	mu       sync.Mutex
+	key      EphemeralKey
+	expires  time.Time // ExpiresAt, or the mint time plus DefaultTTL
+	inflight *mintCall
+}
+
+// mintCall is a mint in progress, shared by concurrent callers of Key.
+type mintCall struct {
+	done chan struct{}
+	key  EphemeralKey
+	err  error
+}
+
+// EphemeralMinter returns a KeyManagerOptions.Mint function that mints keys
+// with MintEphemeral.
+func EphemeralMinter(resourceEndpoint, apiVersion, deployment, apiKey, voice string) func(ctx context.Context) (EphemeralKey, error) {
+	return func(ctx context.Context) (EphemeralKey, error) {
+		return MintEphemeral(ctx, resourceEndpoint, apiVersion, deployment, apiKey, voice)
+	}
+}
+
+// NewKeyManager returns a KeyManager with no cached key.
+func NewKeyManager(opt KeyManagerOptions) (*KeyManager, error) {
+	if opt.Mint == nil {
+		return nil, errors.New("mint is required")
+	}
+	if opt.RefreshBefore <= 0 {
+		opt.RefreshBefore = DefaultRefreshBefore
+	}
+	if opt.DefaultTTL <= 0 {
+		opt.DefaultTTL = DefaultKeyTTL
+	}
+	if opt.RetryDelay <= 0 {
+		opt.RetryDelay = DefaultRetryDelay
+	}
+	return &KeyManager{opt: opt, clock: realClock{}}, nil
+}
+
+// Key returns the cached key while it has more than RefreshBefore left, and
+// otherwise mints a new one.
+func (m *KeyManager) Key(ctx context.Context) (EphemeralKey, error) {
+	m.mu.Lock()
+	if m.freshLocked(m.clock.Now()) {
+		key := m.key
+		m.mu.Unlock()
+		return key, nil
+	}
+	m.mu.Unlock()
+	return m.Refresh(ctx)
+}
+
+// Refresh mints a new key and caches it, even if the cached one is still
+// fresh. If a mint is already in progress, its result is shared.
+func (m *KeyManager) Refresh(ctx context.Context) (EphemeralKey, error) {
+	for {
+		m.mu.Lock()
+		call := m.inflight
+		if call == nil {
+			call = &mintCall{done: make(chan struct{})}
+			m.inflight = call
+			m.mu.Unlock()
+			m.mint(ctx, call)
+			return call.key, call.err
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return EphemeralKey{}, ctx.Err()
+		case <-call.done:
+		}
+		// A mint aborted by its caller's context does not fail the others
+		if call.err != nil && (errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded)) {
+			continue
+		}
+		return call.key, call.err
+	}
+}
+
+// mint runs call and publishes its result.
+func (m *KeyManager) mint(ctx context.Context, call *mintCall) {
+	call.key, call.err = m.opt.Mint(ctx)
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	m.inflight = nil
+	if call.err == nil {
+		m.key = call.key
+		m.expires = call.key.ExpiresAt
+		if m.expires.IsZero() {
+			m.expires = now.Add(m.opt.DefaultTTL)
+		}
+	}
+	m.mu.Unlock()
+	close(call.done)
+
+	if call.err == nil && m.opt.OnKeyRefreshed != nil {
+		m.opt.OnKeyRefreshed(call.key)
+	}
+}
+
+// Invalidate drops the cached key, e.g. after the service rejected it, so the
+// next Key call mints a new one.
+func (m *KeyManager) Invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.key = EphemeralKey{}
+	m.expires = time.Time{}
+}
+
+// Run keeps a fresh key cached, minting a new one RefreshBefore ahead of each
+// expiry and retrying failed mints after RetryDelay. It blocks until ctx is
+// canceled and then returns ctx.Err().
+func (m *KeyManager) Run(ctx context.Context) error {
+	for {
+		if _, err := m.Key(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if m.opt.OnError != nil {
+				m.opt.OnError(err)
+			}
+			if !sleepCtx(ctx, m.clock, m.opt.RetryDelay) {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		m.mu.Lock()
+		refreshIn := m.expires.Sub(m.clock.Now()) - m.opt.RefreshBefore
+		m.mu.Unlock()
+		if refreshIn < m.opt.RetryDelay {
+			refreshIn = m.opt.RetryDelay // Keys shorter-lived than RefreshBefore
+		}
+		if !sleepCtx(ctx, m.clock, refreshIn) {
+			return ctx.Err()
+		}
+	}
+}
+
+// freshLocked reports whether the cached key can still be handed out.
+func (m *KeyManager) freshLocked(now time.Time) bool {
+	return m.key.Value != "" && now.Before(m.expires.Add(-m.opt.RefreshBefore))
+}
@@ -0,0 +1,188 @@
+package webrtc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingMinter mints numbered keys valid for ttl on clk; a zero ttl leaves
+// ExpiresAt unset.
+func countingMinter(clk clock, ttl time.Duration, minted *atomic.Int32) func(context.Context) (EphemeralKey, error) {
+	return func(ctx context.Context) (EphemeralKey, error) {
+		n := minted.Add(1)
+		key := EphemeralKey{Value: fmt.Sprintf("ek_%d", n)}
+		if ttl > 0 {
+			key.ExpiresAt = clk.Now().Add(ttl)
+		}
+		return key, nil
+	}
+}
+
+func newTestKeyManager(t *testing.T, clk *fakeClock, opt KeyManagerOptions) *KeyManager {
+	t.Helper()
+	m, err := NewKeyManager(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.clock = clk
+	return m
+}
+
+func TestKeyManager_Expiry(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		ttl  time.Duration // Zero: the service reports no expiry
+	}{
+		{"reported expiry", 2 * time.Minute},
+		{"default TTL", 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := newFakeClock()
+			var minted atomic.Int32
+			m := newTestKeyManager(t, clk, KeyManagerOptions{
+				Mint:       countingMinter(clk, tt.ttl, &minted),
+				DefaultTTL: 2 * time.Minute,
+			})
+			ctx := context.Background()
+
+			key, _ := m.Key(ctx)
+			clk.Advance(2*time.Minute - DefaultRefreshBefore - time.Second)
+			if again, _ := m.Key(ctx); again != key || minted.Load() != 1 {
+				t.Errorf("key %q, %d mints; want the cached %q", again.Value, minted.Load(), key.Value)
+			}
+			clk.Advance(time.Second) // Now within RefreshBefore of expiry
+			if again, _ := m.Key(ctx); again.Value != "ek_2" {
+				t.Errorf("key %q near expiry, want a new one", again.Value)
+			}
+
+			m.Invalidate()
+			if again, _ := m.Key(ctx); again.Value != "ek_3" {
+				t.Errorf("key %q after Invalidate, want a new one", again.Value)
+			}
+		})
+	}
+}
+
+func TestKeyManager_ConcurrentCallersShareMint(t *testing.T) {
+	release := make(chan struct{})
+	var minted atomic.Int32
+	m := newTestKeyManager(t, newFakeClock(), KeyManagerOptions{
+		Mint: func(ctx context.Context) (EphemeralKey, error) {
+			minted.Add(1)
+			<-release
+			return EphemeralKey{Value: "ek_shared"}, nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	keys := make([]EphemeralKey, 8)
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			keys[i], _ = m.Key(context.Background())
+		}(i)
+	}
+	waitFor(t, "the mint to start", func() bool { return minted.Load() == 1 })
+	close(release)
+	wg.Wait()
+	for i, k := range keys {
+		if k.Value != "ek_shared" {
+			t.Errorf("caller %d got %q", i, k.Value)
+		}
+	}
+	if n := minted.Load(); n != 1 {
+		t.Errorf("%d mints for concurrent callers, want 1", n)
+	}
+}
+
+func TestKeyManager_CanceledCallerDoesNotFailOthers(t *testing.T) {
+	var minted atomic.Int32
+	started := make(chan struct{}, 2)
+	m := newTestKeyManager(t, newFakeClock(), KeyManagerOptions{
+		Mint: func(ctx context.Context) (EphemeralKey, error) {
+			n := minted.Add(1)
+			started <- struct{}{}
+			if n == 1 {
+				<-ctx.Done() // The first mint runs until its caller gives up
+				return EphemeralKey{}, ctx.Err()
+			}
+			return EphemeralKey{Value: "ek_2"}, nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := m.Key(ctx)
+		first <- err
+	}()
+	<-started
+
+	second := make(chan EphemeralKey, 1)
+	go func() {
+		k, _ := m.Key(context.Background())
+		second <- k
+	}()
+	waitFor(t, "the mint in flight", func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return m.inflight != nil
+	})
+	cancel()
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled caller got %v", err)
+	}
+	select {
+	case k := <-second:
+		if k.Value != "ek_2" {
+			t.Errorf("other caller got %q, want a key from a new mint", k.Value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("other caller did not get a key")
+	}
+}
+
+func TestKeyManager_Run(t *testing.T) {
+	clk := newFakeClock()
+	var minted atomic.Int32
+	var failed atomic.Bool
+	var errs atomic.Int32
+	m := newTestKeyManager(t, clk, KeyManagerOptions{
+		Mint: func(ctx context.Context) (EphemeralKey, error) {
+			if minted.Load() == 1 && failed.CompareAndSwap(false, true) {
+				return EphemeralKey{}, errors.New("mint failed")
+			}
+			return countingMinter(clk, time.Minute, &minted)(ctx)
+		},
+		OnError: func(error) { errs.Add(1) },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	clk.WaitPending(t, 1)
+	if minted.Load() != 1 {
+		t.Fatalf("%d mints on start, want 1", minted.Load())
+	}
+	// The refresh RefreshBefore ahead of expiry fails and is retried
+	clk.Advance(time.Minute - DefaultRefreshBefore)
+	waitFor(t, "the failed refresh", func() bool { return errs.Load() == 1 })
+	clk.WaitPending(t, 1)
+	clk.Advance(DefaultRetryDelay)
+	waitFor(t, "the retried refresh", func() bool { return minted.Load() == 2 })
+	if k, _ := m.Key(ctx); k.Value != "ek_2" {
+		t.Errorf("cached key %q, want ek_2", k.Value)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run returned %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package webrtc
+
+import (
+	"context"
+
+	"github.com/enesunal-m/azrealtime"
+	pion "github.com/pion/webrtc/v3"
+)
+
+// RealtimeClient exchanges realtime events over a WebRTC data channel with the
+// same typed callbacks (OnResponseTextDelta, OnError, ...) and request methods
+// (SessionUpdate, CreateResponse, ...) as an azrealtime.Client dialed over
+// WebSocket, so both transports can share the code that drives a session.
+// Audio flows over the peer connection's media tracks instead of events.
+//
+// Reconnect and Ping are not available over a data channel and return
+// azrealtime.ErrTransportUnsupported.
+type RealtimeClient struct {
+	*azrealtime.Client
+	dc *pion.DataChannel
+}
+
+// NewRealtimeClient attaches a client to a data channel not created by
+// Connect; use HeadlessConn.RealtimeClient for those, as this takes over the
+// channel's OnOpen and OnClose handlers. Requests made before the channel
+// opens are queued and sent once it does, up to DefaultMaxBufferedMessages;
+// beyond that they fail with ErrSendBufferFull, and queued requests that
+// cannot be sent are logged through cfg. The client closes when the channel
+// does, and closing the client closes the channel. Connection settings in cfg
+// are not used; its logging, validation and tracking options are.
+func NewRealtimeClient(dc *pion.DataChannel, cfg azrealtime.Config) *RealtimeClient {
+	return newRealtimeClient(dc, newChannelSender(dc, 0, sendErrorLogger(cfg)), cfg)
+}
+
+// RealtimeClient attaches a client to the connection's data channel. Requests
+// share the queue of Send, so they are sent in order once the channel opens,
+// and queued requests that cannot be sent go to OnSendError. The client
+// closes when the channel does, and closing the client closes the channel.
+// Connection settings in cfg are not used; its logging, validation and
+// tracking options are.
+func (c *HeadlessConn) RealtimeClient(cfg azrealtime.Config) *RealtimeClient {
+	return newRealtimeClient(c.dc, c.sender, cfg)
+}
+
+func newRealtimeClient(dc *pion.DataChannel, sender *channelSender, cfg azrealtime.Config) *RealtimeClient {
+	client, deliver := azrealtime.NewTransportClient(cfg, dataChannelTransport{dc: dc, sender: sender})
+	dc.OnMessage(func(m pion.DataChannelMessage) {
+		if m.IsString {
+			deliver(m.Data)
+		}
+	})
+	sender.whenClosed(func() { _ = client.Close() })
+	return &RealtimeClient{Client: client, dc: dc}
+}
+
+// DataChannel returns the data channel the client uses.
+func (c *RealtimeClient) DataChannel() *pion.DataChannel {
+	return c.dc
+}
+
+// dataChannelTransport sends events as text messages on a data channel.
+type dataChannelTransport struct {
+	dc     *pion.DataChannel
+	sender *channelSender
+}
+
+func (t dataChannelTransport) Send(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return t.sender.send(data)
+}
+
+func (t dataChannelTransport) Close() error {
+	return t.dc.Close()
+}
+
+// sendErrorLogger logs queued requests that could not be sent to the logger
+// configured in cfg, if any.
+func sendErrorLogger(cfg azrealtime.Config) func(error) {
+	return func(err error) {
+		fields := map[string]any{"error": err.Error()}
+		if cfg.StructuredLogger != nil {
+			cfg.StructuredLogger.Error("webrtc_send_failed", fields)
+		} else if cfg.Logger != nil {
+			cfg.Logger("ERROR: webrtc_send_failed", fields)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package webrtc
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v3/pkg/media"
+	"github.com/pion/webrtc/v3/pkg/media/oggwriter"
+)
+
+// AudioDirection tells whether an audio sample was sent to or received from
+// the service.
+type AudioDirection int
+
+const (
+	AudioFromAzure AudioDirection = iota // Assistant audio received on the remote track
+	AudioToAzure                         // Microphone audio sent on AudioInputTrack
+)
+
+func (d AudioDirection) String() string {
+	switch d {
+	case AudioFromAzure:
+		return "from_azure"
+	case AudioToAzure:
+		return "to_azure"
+	default:
+		return "unknown"
+	}
+}
+
+// opusClockRate is the RTP clock rate of Opus.
+const opusClockRate = 48000
+
+// defaultSampleDuration is assumed for samples without a duration.
+const defaultSampleDuration = 20 * time.Millisecond
+
+// OggRecorder writes Opus samples to an Ogg file without decoding them, e.g.
+// from an OnAudioSample tap. Use one recorder per direction:
+//
+//	f, _ := os.Create("assistant.ogg")
+//	rec, _ := webrtc.NewOggRecorder(f)
+//	defer rec.Close() // also closes f
+//	opts.OnAudioSample = func(dir webrtc.AudioDirection, s media.Sample) {
+//		if dir == webrtc.AudioFromAzure {
+//			rec.WriteSample(s)
+//		}
+//	}
+//
+// An OggRecorder is safe for concurrent use.
+type OggRecorder struct {
+	mu        sync.Mutex
+	ogg       *oggwriter.OggWriter
+	timestamp uint32
+	seq       uint16
+	closed    bool
+}
+
+// NewOggRecorder writes the Ogg Opus headers to w and returns a recorder for
+// the audio that follows.
+func NewOggRecorder(w io.Writer) (*OggRecorder, error) {
+	ogg, err := oggwriter.NewWith(w, opusClockRate, 2)
+	if err != nil {
+		return nil, err
+	}
+	return &OggRecorder{ogg: ogg}, nil
+}
+
+// WriteSample appends one Opus packet. Its Duration advances the stream
+// position; zero counts as 20ms.
+func (r *OggRecorder) WriteSample(s media.Sample) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errors.New("recorder closed")
+	}
+	if len(s.Data) == 0 {
+		return nil
+	}
+	d := s.Duration
+	if d <= 0 {
+		d = defaultSampleDuration
+	}
+	r.timestamp += uint32(d * opusClockRate / time.Second)
+	r.seq++
+	return r.ogg.WriteRTP(&rtp.Packet{
+		Header:  rtp.Header{SequenceNumber: r.seq, Timestamp: r.timestamp},
+		Payload: s.Data,
+	})
+}
+
+// Close stops the recording and closes the underlying writer if it is an
+// io.Closer.
+func (r *OggRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	return r.ogg.Close()
+}
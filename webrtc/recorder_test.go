@@ -0,0 +1,135 @@
+package webrtc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v3/pkg/media"
+	"github.com/pion/webrtc/v3/pkg/media/oggreader"
+)
+
+// closeBuffer is a bytes.Buffer that records Close.
+type closeBuffer struct {
+	bytes.Buffer
+	closed int
+}
+
+func (b *closeBuffer) Close() error {
+	b.closed++
+	return nil
+}
+
+// readOggPages returns the payload and granule position of each audio page
+// in an Ogg Opus stream.
+func readOggPages(t *testing.T, data []byte) (payloads [][]byte, granules []uint64) {
+	t.Helper()
+	r, header, err := oggreader.NewWith(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.SampleRate != opusClockRate || header.Channels != 2 {
+		t.Errorf("header: %d Hz, %d channels; want %d Hz stereo", header.SampleRate, header.Channels, opusClockRate)
+	}
+	for {
+		payload, page, err := r.ParseNextPage()
+		if errors.Is(err, io.EOF) {
+			return payloads, granules
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.HasPrefix(payload, []byte("OpusTags")) {
+			continue
+		}
+		payloads = append(payloads, payload)
+		granules = append(granules, page.GranulePosition)
+	}
+}
+
+func TestOggRecorder_WritesSamples(t *testing.T) {
+	var buf closeBuffer
+	rec, err := NewOggRecorder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := []media.Sample{
+		{Data: []byte{0xfc, 1, 2}, Duration: 20 * time.Millisecond},
+		{Data: []byte{0xfc, 3, 4}, Duration: 40 * time.Millisecond},
+		{Data: nil, Duration: 20 * time.Millisecond}, // Skipped
+		{Data: []byte{0xfc, 5, 6}},                   // Counts as 20ms
+	}
+	for _, s := range samples {
+		if err := rec.WriteSample(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.closed != 1 {
+		t.Errorf("underlying writer closed %d times, want 1", buf.closed)
+	}
+
+	payloads, granules := readOggPages(t, buf.Bytes())
+	want := [][]byte{{0xfc, 1, 2}, {0xfc, 3, 4}, {0xfc, 5, 6}}
+	if len(payloads) != len(want) {
+		t.Fatalf("%d audio pages, want %d", len(payloads), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(payloads[i], want[i]) {
+			t.Errorf("page %d payload % x, want % x", i, payloads[i], want[i])
+		}
+	}
+	// Each page advances the position by its sample's duration at 48kHz
+	for i, d := range []uint64{40 * 48, 20 * 48} {
+		if got := granules[i+1] - granules[i]; got != d {
+			t.Errorf("page %d advanced the position by %d, want %d", i+1, got, d)
+		}
+	}
+}
+
+func TestOggRecorder_CloseWhileRecording(t *testing.T) {
+	var buf closeBuffer
+	rec, err := NewOggRecorder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	written := 0
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if err := rec.WriteSample(media.Sample{Data: []byte{0xfc, 1}, Duration: 20 * time.Millisecond}); err != nil {
+					return // Closed
+				}
+				mu.Lock()
+				written++
+				mu.Unlock()
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	if err := rec.WriteSample(media.Sample{Data: []byte{0xfc, 1}}); err == nil {
+		t.Error("WriteSample after Close succeeded")
+	}
+	if err := rec.Close(); err != nil || buf.closed != 1 {
+		t.Errorf("second Close: %v, underlying writer closed %d times; want nil and 1", err, buf.closed)
+	}
+	// Every sample accepted before Close is in the file, on whole pages
+	if payloads, _ := readOggPages(t, buf.Bytes()); len(payloads) != written {
+		t.Errorf("%d audio pages, want the %d samples written before Close", len(payloads), written)
+	}
+}
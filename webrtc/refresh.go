@@ -0,0 +1,150 @@
+package webrtc
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RefreshState describes where a refreshing session currently is, so UIs can
+// show a "reconnecting" indicator instead of silently dropping the call.
+type RefreshState int
+
+const (
+	RefreshConnecting   RefreshState = iota // Minting the first key and connecting
+	RefreshConnected                        // A session is running
+	RefreshReconnecting                     // The session ended; connecting again with a fresh key
+	RefreshStopped                          // The run loop has exited
+)
+
+func (s RefreshState) String() string {
+	switch s {
+	case RefreshConnecting:
+		return "connecting"
+	case RefreshConnected:
+		return "connected"
+	case RefreshReconnecting:
+		return "reconnecting"
+	case RefreshStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// Default timings used by RunWithKeyRefresh and KeyManager when the
+// corresponding option is zero.
+const (
+	DefaultKeyTTL        = time.Minute      // Assumed key lifetime when the service reports no expiry
+	DefaultRefreshBefore = 10 * time.Second // How early to mint a replacement before the key expires
+	DefaultRetryDelay    = time.Second      // Pause before retrying after a failed mint or connect
+)
+
+// KeyRefreshOptions configures RunWithKeyRefresh.
+type KeyRefreshOptions struct {
+	// Mint obtains a fresh ephemeral key. Required.
+	// Typically wraps MintEphemeral or a call to an ephemeral-issuer endpoint,
+	// or is KeyManager.Key to share cached keys.
+	Mint func(ctx context.Context) (EphemeralKey, error)
+
+	// Connect runs a WebRTC session with the given key until the session ends
+	// or ctx is canceled. Required. HeadlessConnect and
+	// EnhancedHeadlessConnect have this shape once their options are bound.
+	Connect func(ctx context.Context, key EphemeralKey) error
+
+	// RefreshBefore is how long before key expiry a replacement key is minted.
+	RefreshBefore time.Duration
+	// DefaultTTL is the key lifetime assumed when EphemeralKey.ExpiresAt is zero.
+	DefaultTTL time.Duration
+	// RetryDelay is the pause before retrying after Mint or Connect fails.
+	RetryDelay time.Duration
+
+	// OnStateChange is called on every state transition.
+	OnStateChange func(RefreshState)
+	// OnKeyRefreshed is called each time a new key has been minted.
+	OnKeyRefreshed func(EphemeralKey)
+	// OnError is called when minting or connecting fails; the loop then retries.
+	OnError func(error)
+
+	clock clock // Defaults to the system clock
+}
+
+// RunWithKeyRefresh keeps a WebRTC session alive across ephemeral key expiry.
+// An ephemeral key is only needed to set up a connection, so a running
+// session is never interrupted: while it runs, a fresh key is minted
+// RefreshBefore ahead of each expiry, and when the session ends the loop
+// reconnects at once with that key. Transitions are reported through
+// OnStateChange. It blocks until ctx is canceled and then returns ctx.Err().
+func RunWithKeyRefresh(ctx context.Context, opt KeyRefreshOptions) error {
+	if opt.Mint == nil || opt.Connect == nil {
+		return errors.New("mint and connect are required")
+	}
+	if opt.RetryDelay <= 0 {
+		opt.RetryDelay = DefaultRetryDelay
+	}
+	if opt.clock == nil {
+		opt.clock = realClock{}
+	}
+	keys, err := NewKeyManager(KeyManagerOptions{
+		Mint:           opt.Mint,
+		RefreshBefore:  opt.RefreshBefore,
+		DefaultTTL:     opt.DefaultTTL,
+		RetryDelay:     opt.RetryDelay,
+		OnKeyRefreshed: opt.OnKeyRefreshed,
+		OnError:        opt.OnError,
+	})
+	if err != nil {
+		return err
+	}
+	keys.clock = opt.clock
+
+	setState := func(s RefreshState) {
+		if opt.OnStateChange != nil {
+			opt.OnStateChange(s)
+		}
+	}
+	report := func(err error) {
+		if opt.OnError != nil && err != nil {
+			opt.OnError(err)
+		}
+	}
+	defer setState(RefreshStopped)
+
+	setState(RefreshConnecting)
+	for {
+		key, err := keys.Key(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			report(err)
+			if !sleepCtx(ctx, opt.clock, opt.RetryDelay) {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		// Keep the key for the next connect fresh while the session runs;
+		// keys.Run reports its own mint failures
+		refreshCtx, stopRefresh := context.WithCancel(ctx)
+		refreshed := make(chan struct{})
+		go func() {
+			defer close(refreshed)
+			_ = keys.Run(refreshCtx)
+		}()
+		setState(RefreshConnected)
+		err = opt.Connect(ctx, key)
+		stopRefresh()
+		<-refreshed
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// The session ended on its own; reconnect after a short pause
+		report(err)
+		setState(RefreshReconnecting)
+		if !sleepCtx(ctx, opt.clock, opt.RetryDelay) {
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package webrtc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// refreshHarness records what RunWithKeyRefresh does with fake keys.
+type refreshHarness struct {
+	clk *fakeClock
+
+	mu       sync.Mutex
+	minted   int
+	failNext int // Mints to fail before succeeding
+	connects []string
+	states   []RefreshState
+	errs     []error
+	end      chan error // Ends the running session with the sent error
+}
+
+func newRefreshHarness() *refreshHarness {
+	return &refreshHarness{clk: newFakeClock(), end: make(chan error)}
+}
+
+func (h *refreshHarness) options() KeyRefreshOptions {
+	return KeyRefreshOptions{
+		Mint: func(ctx context.Context) (EphemeralKey, error) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if h.failNext > 0 {
+				h.failNext--
+				return EphemeralKey{}, errors.New("mint failed")
+			}
+			h.minted++
+			return EphemeralKey{Value: fmt.Sprintf("ek_%d", h.minted), ExpiresAt: h.clk.Now().Add(time.Minute)}, nil
+		},
+		Connect: func(ctx context.Context, key EphemeralKey) error {
+			h.mu.Lock()
+			h.connects = append(h.connects, key.Value)
+			h.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-h.end:
+				return err
+			}
+		},
+		OnStateChange: func(s RefreshState) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.states = append(h.states, s)
+		},
+		OnError: func(err error) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.errs = append(h.errs, err)
+		},
+		clock: h.clk,
+	}
+}
+
+func (h *refreshHarness) snapshot() (minted int, connects []string, states []RefreshState, errs int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.minted, append([]string(nil), h.connects...), append([]RefreshState(nil), h.states...), len(h.errs)
+}
+
+func TestRunWithKeyRefresh_KeepsSessionAcrossExpiry(t *testing.T) {
+	h := newRefreshHarness()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- RunWithKeyRefresh(ctx, h.options()) }()
+
+	waitFor(t, "the first session", func() bool { _, c, _, _ := h.snapshot(); return len(c) == 1 })
+	h.clk.WaitPending(t, 1) // The refresher waiting for the key to near expiry
+
+	// Keys are refreshed ahead of expiry while the first session keeps running
+	for want := 2; want <= 3; want++ {
+		h.clk.Advance(55 * time.Second)
+		waitFor(t, "a refreshed key", func() bool { m, _, _, _ := h.snapshot(); return m == want })
+		h.clk.WaitPending(t, 1)
+	}
+	if _, connects, _, _ := h.snapshot(); len(connects) != 1 {
+		t.Fatalf("sessions = %v, want the first one still running", connects)
+	}
+
+	// When the session ends, the loop reconnects with the latest key
+	h.end <- errors.New("peer went away")
+	waitFor(t, "reconnecting", func() bool { _, _, st, _ := h.snapshot(); return st[len(st)-1] == RefreshReconnecting })
+	h.clk.WaitPending(t, 1) // Retry delay
+	h.clk.Advance(DefaultRetryDelay)
+	waitFor(t, "the second session", func() bool { _, c, _, _ := h.snapshot(); return len(c) == 2 })
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("RunWithKeyRefresh returned %v, want context.Canceled", err)
+	}
+	minted, connects, states, errs := h.snapshot()
+	if minted != 3 || connects[0] != "ek_1" || connects[1] != "ek_3" {
+		t.Errorf("minted %d keys, sessions %v; want 3 keys and sessions [ek_1 ek_3]", minted, connects)
+	}
+	want := []RefreshState{RefreshConnecting, RefreshConnected, RefreshReconnecting, RefreshConnected, RefreshStopped}
+	if fmt.Sprint(states) != fmt.Sprint(want) {
+		t.Errorf("states = %v, want %v", states, want)
+	}
+	if errs != 1 {
+		t.Errorf("OnError called %d times, want 1 for the ended session", errs)
+	}
+}
+
+func TestRunWithKeyRefresh_RetriesFailedMint(t *testing.T) {
+	h := newRefreshHarness()
+	h.failNext = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- RunWithKeyRefresh(ctx, h.options()) }()
+
+	waitFor(t, "the mint failure", func() bool { _, _, _, e := h.snapshot(); return e == 1 })
+	h.clk.WaitPending(t, 1) // Retry delay
+	h.clk.Advance(DefaultRetryDelay)
+	waitFor(t, "the session", func() bool { _, c, _, _ := h.snapshot(); return len(c) == 1 })
+
+	cancel()
+	<-done
+	if minted, connects, _, errs := h.snapshot(); minted != 1 || connects[0] != "ek_1" || errs != 1 {
+		t.Errorf("minted %d, sessions %v, errors %d", minted, connects, errs)
+	}
+}
+
+func TestRunWithKeyRefresh_RequiresMintAndConnect(t *testing.T) {
+	if err := RunWithKeyRefresh(context.Background(), KeyRefreshOptions{}); err == nil {
+		t.Error("expected an error without Mint and Connect")
+	}
+}
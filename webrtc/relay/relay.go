@@ -0,0 +1,501 @@
+// Package relay bridges a browser WebRTC peer connection to an Azure OpenAI
+// realtime session: audio tracks are forwarded both ways, data channel events
+// are passed through (buffered until the Azure side is ready), and hooks
+// expose every event, audio packet and finished transcript for logging,
+// recording or compliance capture.
+//
+// A typical signaling handler answers the browser's SDP offer:
+//
+//	r, answer, err := relay.Answer(ctx, offerSDP, relay.Options{
+//		Azure: webrtc.EnhancedHeadlessOptions{Region: region, Deployment: deployment},
+//		Key: func(ctx context.Context) (string, error) {
+//			k, err := keys.Key(ctx) // a webrtc.KeyManager
+//			return k.Value, err
+//		},
+//		OnTranscript: func(e relay.TranscriptEntry) { log.Printf("%s: %s", e.Role, e.Text) },
+//	})
+//
+// The Azure connection is made once the browser connection is established,
+// and the relay closes when either side goes away.
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	pion "github.com/pion/webrtc/v3"
+	"github.com/pion/webrtc/v3/pkg/media"
+
+	"github.com/enesunal-m/azrealtime"
+	"github.com/enesunal-m/azrealtime/webrtc"
+)
+
+// DefaultMaxBufferedMessages is how many browser events are held while the
+// Azure data channel opens when Options.MaxBufferedMessages is zero.
+const DefaultMaxBufferedMessages = 256
+
+// packetDuration is the duration given to forwarded Opus packets.
+const packetDuration = 20 * time.Millisecond
+
+// Direction tells which way an event or audio packet is flowing.
+type Direction int
+
+const (
+	BrowserToAzure Direction = iota
+	AzureToBrowser
+)
+
+func (d Direction) String() string {
+	switch d {
+	case BrowserToAzure:
+		return "browser_to_azure"
+	case AzureToBrowser:
+		return "azure_to_browser"
+	default:
+		return "unknown"
+	}
+}
+
+// State is the lifecycle stage of a Relay.
+type State int
+
+const (
+	StateWaitingForBrowser State = iota // The browser connection is being established
+	StateConnectingAzure                // The browser is connected; the Azure session is being set up
+	StateRelaying                       // Both sides are connected
+	StateClosed                         // The relay has shut down
+)
+
+func (s State) String() string {
+	switch s {
+	case StateWaitingForBrowser:
+		return "waiting_for_browser"
+	case StateConnectingAzure:
+		return "connecting_azure"
+	case StateRelaying:
+		return "relaying"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// TranscriptEntry is a finished utterance seen by the relay: a user turn
+// transcribed by input audio transcription, or an assistant reply.
+type TranscriptEntry struct {
+	Role   string // "user" or "assistant"
+	ItemID string
+	Text   string
+	Time   time.Time
+}
+
+// Options configures a Relay.
+type Options struct {
+	// Azure configures the Azure connection: Region, Deployment, ICE settings
+	// and ICE restart. AudioInputTrack, OnTrack and OnReady are set by the relay.
+	Azure webrtc.EnhancedHeadlessOptions
+
+	// Key, if set, supplies the ephemeral key for the Azure connection, e.g.
+	// from a webrtc.KeyManager. Otherwise Azure.Ephemeral is used.
+	Key func(ctx context.Context) (string, error)
+
+	// Browser configures the peer connection created by Answer.
+	Browser pion.Configuration
+
+	// Session, if set, is sent as session.update once the Azure data channel
+	// opens. Prefer configuring the session when minting the key (see
+	// webrtc.MintEphemeralSession) so the browser never has to.
+	Session *azrealtime.Session
+
+	// MaxBufferedMessages bounds the browser events held until the Azure data
+	// channel opens; later ones are dropped and reported to OnError.
+	// Azure.MaxBufferedMessages defaults to one more, for session.update.
+	// Default: DefaultMaxBufferedMessages.
+	MaxBufferedMessages int
+
+	// OnEvent is called with every data channel message passing through,
+	// and its event type if it is a JSON event.
+	OnEvent func(dir Direction, eventType string, data []byte)
+	// OnTranscript is called with each finished user or assistant utterance.
+	OnTranscript func(TranscriptEntry)
+	// OnAudioPacket is called with every audio packet forwarded, e.g. to record the call.
+	OnAudioPacket func(dir Direction, pkt *rtp.Packet)
+	// OnAudioSample is called with every Opus sample forwarded, e.g. to write
+	// it to a webrtc.OggRecorder per direction.
+	OnAudioSample func(dir Direction, s media.Sample)
+	// OnStateChange is called on every state transition.
+	OnStateChange func(State)
+	// OnError is called for failures that do not stop the relay by themselves,
+	// and for the error that made the Azure connection fail.
+	OnError func(error)
+}
+
+// Relay bridges one browser peer connection to one Azure realtime session.
+type Relay struct {
+	opts    Options
+	browser *pion.PeerConnection
+
+	toBrowser *pion.TrackLocalStaticSample // Azure audio, sent to the browser
+	toAzure   *pion.TrackLocalStaticSample // Browser audio, sent to Azure
+
+	ctx    context.Context // Canceled when the relay closes
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	state     State
+	browserDC *pion.DataChannel
+	azure     *webrtc.HeadlessConn
+	pending   [][]byte // Browser events waiting for the Azure connection
+	started   bool
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// New attaches a relay to a browser peer connection that has not been
+// negotiated yet: it adds the track carrying Azure audio to pc and takes over
+// pc's OnTrack, OnDataChannel and OnConnectionStateChange callbacks.
+func New(pc *pion.PeerConnection, opts Options) (*Relay, error) {
+	if opts.MaxBufferedMessages <= 0 {
+		opts.MaxBufferedMessages = DefaultMaxBufferedMessages
+	}
+	toBrowser, err := pion.NewTrackLocalStaticSample(pion.RTPCodecCapability{MimeType: pion.MimeTypeOpus}, "azure-audio", "azure-stream")
+	if err != nil {
+		return nil, err
+	}
+	toAzure, err := pion.NewTrackLocalStaticSample(pion.RTPCodecCapability{MimeType: pion.MimeTypeOpus}, "browser-audio", "browser-stream")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pc.AddTrack(toBrowser); err != nil {
+		return nil, fmt.Errorf("add azure audio track: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Relay{opts: opts, browser: pc, toBrowser: toBrowser, toAzure: toAzure, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+
+	pc.OnTrack(func(track *pion.TrackRemote, _ *pion.RTPReceiver) {
+		go r.forwardAudio(track, BrowserToAzure, r.toAzure)
+	})
+	pc.OnDataChannel(r.attachBrowserChannel)
+	pc.OnConnectionStateChange(func(state pion.PeerConnectionState) {
+		switch state {
+		case pion.PeerConnectionStateConnected:
+			r.startAzure()
+		case pion.PeerConnectionStateFailed, pion.PeerConnectionStateClosed:
+			_ = r.Close()
+		}
+	})
+	return r, nil
+}
+
+// Answer creates a browser peer connection from opts.Browser, attaches a relay
+// to it, and answers offerSDP. The returned answer contains all gathered ICE
+// candidates; ctx bounds the gathering.
+func Answer(ctx context.Context, offerSDP string, opts Options) (*Relay, string, error) {
+	pc, err := pion.NewPeerConnection(opts.Browser)
+	if err != nil {
+		return nil, "", err
+	}
+	r, err := New(pc, opts)
+	if err != nil {
+		_ = pc.Close()
+		return nil, "", err
+	}
+
+	fail := func(err error) (*Relay, string, error) {
+		_ = r.Close()
+		return nil, "", err
+	}
+	if err := pc.SetRemoteDescription(pion.SessionDescription{Type: pion.SDPTypeOffer, SDP: offerSDP}); err != nil {
+		return fail(err)
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		return fail(err)
+	}
+	gathered := pion.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		return fail(err)
+	}
+	select {
+	case <-gathered:
+	case <-ctx.Done():
+		return fail(ctx.Err())
+	}
+	return r, pc.LocalDescription().SDP, nil
+}
+
+// Browser returns the browser peer connection.
+func (r *Relay) Browser() *pion.PeerConnection { return r.browser }
+
+// Azure returns the Azure connection, or nil until it is established.
+func (r *Relay) Azure() *webrtc.HeadlessConn {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.azure
+}
+
+// State returns the relay's current state.
+func (r *Relay) State() State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// Done returns a channel that is closed when the relay closes.
+func (r *Relay) Done() <-chan struct{} { return r.done }
+
+// SendToAzure sends an event to the Azure session as if the browser had sent it.
+// It is buffered if the Azure data channel is not open yet.
+func (r *Relay) SendToAzure(data []byte) error {
+	r.report(BrowserToAzure, data)
+	return r.forwardToAzure(data)
+}
+
+// SendToBrowser sends an event to the browser as if Azure had sent it.
+func (r *Relay) SendToBrowser(data []byte) error {
+	r.report(AzureToBrowser, data)
+	return r.forwardToBrowser(data)
+}
+
+// Close shuts down both peer connections. It is safe to call more than once.
+func (r *Relay) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		r.cancel()
+		r.mu.Lock()
+		azure := r.azure
+		r.pending = nil
+		r.mu.Unlock()
+		if azure != nil {
+			err = azure.Close()
+		}
+		if cerr := r.browser.Close(); err == nil {
+			err = cerr
+		}
+		r.setState(StateClosed)
+		close(r.done)
+	})
+	return err
+}
+
+func (r *Relay) setState(s State) {
+	r.mu.Lock()
+	if r.state == StateClosed || r.state == s {
+		r.mu.Unlock()
+		return
+	}
+	r.state = s
+	r.mu.Unlock()
+	if r.opts.OnStateChange != nil {
+		r.opts.OnStateChange(s)
+	}
+}
+
+func (r *Relay) reportError(err error) {
+	if r.opts.OnError != nil && err != nil {
+		r.opts.OnError(err)
+	}
+}
+
+// attachBrowserChannel forwards the browser's events to Azure.
+func (r *Relay) attachBrowserChannel(dc *pion.DataChannel) {
+	r.mu.Lock()
+	r.browserDC = dc
+	r.mu.Unlock()
+	dc.OnMessage(func(m pion.DataChannelMessage) {
+		r.report(BrowserToAzure, m.Data)
+		if err := r.forwardToAzure(m.Data); err != nil {
+			r.reportError(err)
+		}
+	})
+}
+
+// forwardToAzure sends data to Azure, or buffers it until the Azure
+// connection exists; from then on the connection queues it until its data
+// channel opens.
+func (r *Relay) forwardToAzure(data []byte) error {
+	r.mu.Lock()
+	azure := r.azure
+	if azure == nil {
+		defer r.mu.Unlock()
+		if r.state == StateClosed {
+			return errors.New("relay: closed")
+		}
+		if len(r.pending) >= r.opts.MaxBufferedMessages {
+			return errors.New("relay: buffer full, dropped browser event")
+		}
+		r.pending = append(r.pending, append([]byte(nil), data...))
+		return nil
+	}
+	r.mu.Unlock()
+	return azure.Send(data)
+}
+
+// forwardToBrowser sends data on the browser data channel, dropping it if the
+// channel is not open.
+func (r *Relay) forwardToBrowser(data []byte) error {
+	r.mu.Lock()
+	dc := r.browserDC
+	r.mu.Unlock()
+	if dc == nil || dc.ReadyState() != pion.DataChannelStateOpen {
+		return errors.New("relay: browser data channel is not open")
+	}
+	return dc.SendText(string(data))
+}
+
+// startAzure connects the Azure side once, when the browser is connected.
+func (r *Relay) startAzure() {
+	r.mu.Lock()
+	if r.started || r.state == StateClosed {
+		r.mu.Unlock()
+		return
+	}
+	r.started = true
+	r.mu.Unlock()
+	r.setState(StateConnectingAzure)
+	go func() {
+		if err := r.connectAzure(); err != nil {
+			r.reportError(fmt.Errorf("azure connection: %w", err))
+			_ = r.Close()
+		}
+	}()
+}
+
+func (r *Relay) connectAzure() error {
+	opt := r.opts.Azure
+	if r.opts.Key != nil {
+		key, err := r.opts.Key(r.ctx)
+		if err != nil {
+			return fmt.Errorf("ephemeral key: %w", err)
+		}
+		opt.Ephemeral = key
+	}
+	opt.AudioInputTrack = r.toAzure
+	opt.OnTrack = func(track *pion.TrackRemote, _ *pion.RTPReceiver) {
+		go r.forwardAudio(track, AzureToBrowser, r.toBrowser)
+	}
+	if opt.MaxBufferedMessages == 0 {
+		opt.MaxBufferedMessages = r.opts.MaxBufferedMessages + 1 // The browser's events and session.update
+	}
+	onOpen := opt.OnDataChannelOpen
+	opt.OnDataChannelOpen = func() {
+		r.setState(StateRelaying)
+		if onOpen != nil {
+			onOpen()
+		}
+	}
+	opt.OnReady = func(_ *pion.PeerConnection, dc *pion.DataChannel) {
+		dc.OnMessage(func(m pion.DataChannelMessage) {
+			r.report(AzureToBrowser, m.Data)
+			if err := r.forwardToBrowser(m.Data); err != nil {
+				r.reportError(err)
+			}
+		})
+	}
+
+	conn, err := webrtc.Connect(r.ctx, opt)
+	if err != nil {
+		return err
+	}
+	// session.update goes ahead of the browser's events
+	var update []byte
+	if r.opts.Session != nil {
+		update, _ = json.Marshal(map[string]any{"type": "session.update", "session": r.opts.Session})
+		r.report(BrowserToAzure, update)
+	}
+	r.mu.Lock()
+	if r.state == StateClosed {
+		r.mu.Unlock()
+		return conn.Close()
+	}
+	var errs []error
+	if update != nil {
+		if err := conn.Send(update); err != nil {
+			errs = append(errs, fmt.Errorf("session.update: %w", err))
+		}
+	}
+	for _, data := range r.pending {
+		if err := conn.Send(data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	r.pending = nil
+	r.azure = conn
+	r.mu.Unlock()
+	for _, err := range errs {
+		r.reportError(err)
+	}
+
+	go func() {
+		select {
+		case <-conn.Done():
+			_ = r.Close()
+		case <-r.done:
+		}
+	}()
+	return nil
+}
+
+// forwardAudio copies Opus packets from track to out until the track ends.
+func (r *Relay) forwardAudio(track *pion.TrackRemote, dir Direction, out *pion.TrackLocalStaticSample) {
+	for {
+		pkt, _, err := track.ReadRTP()
+		if err != nil {
+			if err != io.EOF && r.ctx.Err() == nil {
+				r.reportError(fmt.Errorf("read %s audio: %w", dir, err))
+			}
+			return
+		}
+		if r.opts.OnAudioPacket != nil {
+			r.opts.OnAudioPacket(dir, pkt)
+		}
+		sample := media.Sample{Data: pkt.Payload, Duration: packetDuration}
+		if r.opts.OnAudioSample != nil {
+			r.opts.OnAudioSample(dir, sample)
+		}
+		if err := out.WriteSample(sample); err != nil && err != io.ErrClosedPipe {
+			r.reportError(fmt.Errorf("forward %s audio: %w", dir, err))
+		}
+	}
+}
+
+// report passes an event to OnEvent and, for finished utterances, OnTranscript.
+func (r *Relay) report(dir Direction, data []byte) {
+	if r.opts.OnEvent == nil && r.opts.OnTranscript == nil {
+		return
+	}
+	var env struct {
+		Type       string `json:"type"`
+		ItemID     string `json:"item_id"`
+		Transcript string `json:"transcript"`
+		Text       string `json:"text"`
+	}
+	_ = json.Unmarshal(data, &env)
+	if r.opts.OnEvent != nil {
+		r.opts.OnEvent(dir, env.Type, data)
+	}
+	if r.opts.OnTranscript == nil || dir != AzureToBrowser {
+		return
+	}
+	entry := TranscriptEntry{ItemID: env.ItemID, Time: time.Now()}
+	switch env.Type {
+	case "conversation.item.input_audio_transcription.completed":
+		entry.Role, entry.Text = "user", env.Transcript
+	case "response.audio_transcript.done":
+		entry.Role, entry.Text = "assistant", env.Transcript
+	case "response.text.done":
+		entry.Role, entry.Text = "assistant", env.Text
+	default:
+		return
+	}
+	r.opts.OnTranscript(entry)
+}
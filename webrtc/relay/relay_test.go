@@ -0,0 +1,122 @@
+package relay
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pion "github.com/pion/webrtc/v3"
+)
+
+func TestRelay_Transcripts(t *testing.T) {
+	var got []TranscriptEntry
+	r := &Relay{opts: Options{OnTranscript: func(e TranscriptEntry) { got = append(got, e) }}}
+
+	r.report(AzureToBrowser, []byte(`{"type":"conversation.item.input_audio_transcription.completed","item_id":"u1","transcript":"hello"}`))
+	r.report(AzureToBrowser, []byte(`{"type":"response.audio_transcript.delta","item_id":"a1","delta":"hi"}`))
+	r.report(AzureToBrowser, []byte(`{"type":"response.audio_transcript.done","item_id":"a1","transcript":"hi there"}`))
+	r.report(AzureToBrowser, []byte(`{"type":"response.text.done","item_id":"a2","text":"typed"}`))
+	r.report(BrowserToAzure, []byte(`{"type":"response.text.done","item_id":"a3","text":"ignored"}`))
+
+	want := []TranscriptEntry{{Role: "user", ItemID: "u1", Text: "hello"}, {Role: "assistant", ItemID: "a1", Text: "hi there"}, {Role: "assistant", ItemID: "a2", Text: "typed"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Role != w.Role || got[i].ItemID != w.ItemID || got[i].Text != w.Text || got[i].Time.IsZero() {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestRelay_BuffersUntilAzureOpens(t *testing.T) {
+	var events []string
+	r := &Relay{opts: Options{
+		MaxBufferedMessages: 2,
+		OnEvent:             func(dir Direction, typ string, _ []byte) { events = append(events, dir.String()+" "+typ) },
+	}}
+
+	if err := r.SendToAzure([]byte(`{"type":"input_audio_buffer.clear"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SendToAzure([]byte(`{"type":"response.create"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SendToAzure([]byte(`{"type":"response.cancel"}`)); err == nil {
+		t.Fatal("expected an error once the buffer is full")
+	}
+	if len(r.pending) != 2 {
+		t.Fatalf("got %d pending events, want 2", len(r.pending))
+	}
+	if events[0] != "browser_to_azure input_audio_buffer.clear" {
+		t.Errorf("events = %v", events)
+	}
+}
+
+// TestRelay_AzureFailureCloses connects a local offerer standing in for the
+// browser; the Azure side cannot connect without a region, which is reported
+// and closes the relay.
+func TestRelay_AzureFailureCloses(t *testing.T) {
+	browser, err := pion.NewPeerConnection(pion.Configuration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer browser.Close()
+	if _, err := browser.CreateDataChannel("realtime-channel", nil); err != nil {
+		t.Fatal(err)
+	}
+	offer, err := browser.CreateOffer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gathered := pion.GatheringCompletePromise(browser)
+	if err := browser.SetLocalDescription(offer); err != nil {
+		t.Fatal(err)
+	}
+	<-gathered
+
+	var mu sync.Mutex
+	var states []string
+	errs := make(chan error, 4)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	r, answer, err := Answer(ctx, browser.LocalDescription().SDP, Options{
+		OnStateChange: func(s State) {
+			mu.Lock()
+			states = append(states, s.String())
+			mu.Unlock()
+		},
+		OnError: func(err error) { errs <- err },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := browser.SetRemoteDescription(pion.SessionDescription{Type: pion.SDPTypeAnswer, SDP: answer}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "azure connection") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the Azure connection to fail")
+	}
+	select {
+	case <-r.Done():
+	case <-ctx.Done():
+		t.Fatal("relay did not close")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(states, ","); got != "connecting_azure,closed" {
+		t.Errorf("states = %s", got)
+	}
+	if r.State() != StateClosed {
+		t.Errorf("State() = %v", r.State())
+	}
+}
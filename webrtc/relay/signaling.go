@@ -0,0 +1,239 @@
+package relay
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	pion "github.com/pion/webrtc/v3"
+)
+
+// SessionHeader carries the session ID from the offer response to later
+// signaling requests. The ICE candidate handler also accepts it as the
+// "session" query parameter.
+const SessionHeader = "X-Relay-Session"
+
+// DefaultGatherTimeout bounds ICE gathering for an offer when
+// SignalingOptions.GatherTimeout is zero.
+const DefaultGatherTimeout = 10 * time.Second
+
+// DefaultConnectTimeout is how long a session may wait for its browser to
+// connect when SignalingOptions.ConnectTimeout is zero.
+const DefaultConnectTimeout = 30 * time.Second
+
+// maxOfferBytes bounds the SDP offers read by the offer handler.
+const maxOfferBytes = 64 << 10
+
+// SignalingOptions configures NewSignaling.
+type SignalingOptions struct {
+	// Relay is the template for every session's relay.
+	Relay Options
+
+	// Configure, if set, adjusts a copy of Relay for each offer, e.g. to attach
+	// per-session transcript hooks or authenticate the request. An error
+	// rejects the offer with 403 Forbidden.
+	Configure func(req *http.Request, sessionID string, opts *Options) error
+
+	// AllowOrigin, if set, is sent as Access-Control-Allow-Origin, and CORS
+	// preflight requests are answered.
+	AllowOrigin string
+
+	// GatherTimeout bounds ICE gathering before an answer is returned.
+	// Default: DefaultGatherTimeout.
+	GatherTimeout time.Duration
+	// ConnectTimeout closes sessions whose browser has not connected in time,
+	// e.g. because it never applied the answer. Default: DefaultConnectTimeout.
+	ConnectTimeout time.Duration
+
+	// OnSession is called when a session starts, and OnSessionEnd when its relay closes.
+	OnSession    func(sessionID string, r *Relay)
+	OnSessionEnd func(sessionID string)
+}
+
+// Signaling serves the browser side of relay sessions over HTTP: OfferHandler
+// answers SDP offers with a new relay each, and ICECandidateHandler adds
+// trickled browser candidates to the right one. Sessions are removed when
+// their relay closes, so any number of browsers can be connected at once.
+type Signaling struct {
+	opt SignalingOptions
+
+	mu       sync.Mutex
+	sessions map[string]*Relay
+}
+
+// NewSignaling returns a Signaling with no sessions.
+func NewSignaling(opt SignalingOptions) *Signaling {
+	if opt.GatherTimeout <= 0 {
+		opt.GatherTimeout = DefaultGatherTimeout
+	}
+	if opt.ConnectTimeout <= 0 {
+		opt.ConnectTimeout = DefaultConnectTimeout
+	}
+	return &Signaling{opt: opt, sessions: make(map[string]*Relay)}
+}
+
+// Session returns the relay of a live session, or nil.
+func (s *Signaling) Session(id string) *Relay {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[id]
+}
+
+// Len returns the number of live sessions.
+func (s *Signaling) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// Close closes every live session.
+func (s *Signaling) Close() error {
+	s.mu.Lock()
+	relays := make([]*Relay, 0, len(s.sessions))
+	for _, r := range s.sessions {
+		relays = append(relays, r)
+	}
+	s.mu.Unlock()
+
+	var err error
+	for _, r := range relays {
+		if cerr := r.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// OfferHandler answers a POSTed SDP offer (the request body) with the SDP
+// answer of a new relay session; the session ID is in the SessionHeader
+// response header.
+func (s *Signaling) OfferHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !s.allow(w, req) {
+			return
+		}
+		offer, err := io.ReadAll(io.LimitReader(req.Body, maxOfferBytes))
+		if err != nil || len(offer) == 0 {
+			http.Error(w, "missing SDP offer", http.StatusBadRequest)
+			return
+		}
+
+		id, err := newSessionID()
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		opts := s.opt.Relay
+		if s.opt.Configure != nil {
+			if err := s.opt.Configure(req, id, &opts); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), s.opt.GatherTimeout)
+		defer cancel()
+		r, answer, err := Answer(ctx, string(offer), opts)
+		if err != nil {
+			http.Error(w, "offer rejected: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.add(id, r)
+
+		w.Header().Set(SessionHeader, id)
+		w.Header().Set("Content-Type", "application/sdp")
+		_, _ = io.WriteString(w, answer)
+	})
+}
+
+// ICECandidateHandler adds a POSTed JSON ICE candidate (RTCIceCandidateInit)
+// to the session named by the SessionHeader header or "session" query parameter.
+func (s *Signaling) ICECandidateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !s.allow(w, req) {
+			return
+		}
+		id := req.Header.Get(SessionHeader)
+		if id == "" {
+			id = req.URL.Query().Get("session")
+		}
+		r := s.Session(id)
+		if r == nil {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+
+		var candidate pion.ICECandidateInit
+		if err := json.NewDecoder(io.LimitReader(req.Body, maxOfferBytes)).Decode(&candidate); err != nil {
+			http.Error(w, "invalid ICE candidate", http.StatusBadRequest)
+			return
+		}
+		if err := r.Browser().AddICECandidate(candidate); err != nil {
+			http.Error(w, "invalid ICE candidate: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// add registers a session and removes it again when its relay closes, closing
+// it first if the browser does not connect within ConnectTimeout.
+func (s *Signaling) add(id string, r *Relay) {
+	s.mu.Lock()
+	s.sessions[id] = r
+	s.mu.Unlock()
+	if s.opt.OnSession != nil {
+		s.opt.OnSession(id, r)
+	}
+
+	go func() {
+		t := time.NewTimer(s.opt.ConnectTimeout)
+		select {
+		case <-r.Done():
+			t.Stop()
+		case <-t.C:
+			if r.State() == StateWaitingForBrowser {
+				_ = r.Close()
+			}
+			<-r.Done()
+		}
+		s.mu.Lock()
+		delete(s.sessions, id)
+		s.mu.Unlock()
+		if s.opt.OnSessionEnd != nil {
+			s.opt.OnSessionEnd(id)
+		}
+	}()
+}
+
+// allow applies CORS headers and reports whether req is a POST to be handled.
+func (s *Signaling) allow(w http.ResponseWriter, req *http.Request) bool {
+	if s.opt.AllowOrigin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", s.opt.AllowOrigin)
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, "+SessionHeader)
+		w.Header().Set("Access-Control-Expose-Headers", SessionHeader)
+		if req.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return false
+		}
+	}
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,177 @@
+package relay
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pion "github.com/pion/webrtc/v3"
+)
+
+// browserOffer returns a peer connection standing in for a browser and its
+// complete offer.
+func browserOffer(t *testing.T) (*pion.PeerConnection, string) {
+	t.Helper()
+	pc, err := pion.NewPeerConnection(pion.Configuration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pc.Close() })
+	if _, err := pc.CreateDataChannel("realtime-channel", nil); err != nil {
+		t.Fatal(err)
+	}
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gathered := pion.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(offer); err != nil {
+		t.Fatal(err)
+	}
+	<-gathered
+	return pc, pc.LocalDescription().SDP
+}
+
+func postOffer(t *testing.T, url, sdp string) (id, answer string) {
+	t.Helper()
+	resp, err := http.Post(url, "application/sdp", strings.NewReader(sdp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("offer: status %d: %s", resp.StatusCode, b)
+	}
+	return resp.Header.Get(SessionHeader), string(b)
+}
+
+func TestSignaling_ConcurrentSessions(t *testing.T) {
+	ended := make(chan string, 2)
+	sig := NewSignaling(SignalingOptions{OnSessionEnd: func(id string) { ended <- id }})
+	defer sig.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle("/offer", sig.OfferHandler())
+	mux.Handle("/ice-candidate", sig.ICECandidateHandler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	_, offer1 := browserOffer(t)
+	_, offer2 := browserOffer(t)
+	id1, answer1 := postOffer(t, srv.URL+"/offer", offer1)
+	id2, _ := postOffer(t, srv.URL+"/offer", offer2)
+	if id1 == "" || id1 == id2 {
+		t.Fatalf("session IDs %q and %q", id1, id2)
+	}
+	if !strings.HasPrefix(answer1, "v=0") {
+		t.Errorf("answer is not SDP: %q", answer1)
+	}
+	if sig.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", sig.Len())
+	}
+
+	candidate := `{"candidate":"candidate:1 1 udp 2130706431 127.0.0.1 50000 typ host","sdpMid":"0","sdpMLineIndex":0}`
+	resp, err := http.Post(srv.URL+"/ice-candidate?session="+id1, "application/json", strings.NewReader(candidate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("candidate: status %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(srv.URL+"/ice-candidate?session=unknown", "application/json", strings.NewReader(candidate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown session: status %d", resp.StatusCode)
+	}
+
+	sig.Session(id1).Close()
+	select {
+	case id := <-ended:
+		if id != id1 {
+			t.Errorf("ended %q, want %q", id, id1)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("session did not end")
+	}
+	if sig.Session(id1) != nil || sig.Session(id2) == nil {
+		t.Error("only the closed session should be removed")
+	}
+}
+
+func TestSignaling_RejectsRequests(t *testing.T) {
+	sig := NewSignaling(SignalingOptions{
+		AllowOrigin: "https://app.example.com",
+		Configure: func(req *http.Request, _ string, _ *Options) error {
+			if req.Header.Get("Authorization") == "" {
+				return errors.New("unauthorized")
+			}
+			return nil
+		},
+	})
+	defer sig.Close()
+	srv := httptest.NewServer(sig.OfferHandler())
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodOptions, srv.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("preflight: status %d, headers %v", resp.StatusCode, resp.Header)
+	}
+
+	resp, err = http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(srv.URL, "application/sdp", strings.NewReader("v=0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("unauthorized offer: status %d", resp.StatusCode)
+	}
+	if sig.Len() != 0 {
+		t.Errorf("Len() = %d", sig.Len())
+	}
+}
+
+func TestSignaling_ConnectTimeout(t *testing.T) {
+	ended := make(chan string, 1)
+	sig := NewSignaling(SignalingOptions{ConnectTimeout: 50 * time.Millisecond, OnSessionEnd: func(id string) { ended <- id }})
+	defer sig.Close()
+	srv := httptest.NewServer(sig.OfferHandler())
+	defer srv.Close()
+
+	_, offer := browserOffer(t)
+	id, _ := postOffer(t, srv.URL, offer) // The answer is never applied
+	select {
+	case got := <-ended:
+		if got != id {
+			t.Errorf("ended %q, want %q", got, id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("unconnected session was not closed")
+	}
+	if sig.Len() != 0 {
+		t.Errorf("Len() = %d", sig.Len())
+	}
+}
@@ -0,0 +1,148 @@
+package webrtc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	pion "github.com/pion/webrtc/v3"
+)
+
+// DefaultMaxBufferedMessages is how many messages are queued while the data
+// channel opens when EnhancedHeadlessOptions.MaxBufferedMessages is zero.
+const DefaultMaxBufferedMessages = 256
+
+// ErrSendBufferFull is returned when a message is sent before the data channel
+// opens and the queue of waiting messages is full.
+var ErrSendBufferFull = errors.New("data channel send buffer full")
+
+// errChannelClosed is returned for messages sent after the data channel closed.
+var errChannelClosed = errors.New("data channel closed")
+
+// channelSender sends text messages on a data channel, queueing those sent
+// before it opens and flushing them in order once it does. It owns the
+// channel's OnOpen and OnClose handlers and fans them out, so HeadlessConn and
+// a RealtimeClient on its channel share one queue without replacing each
+// other's handlers.
+type channelSender struct {
+	dc      textChannel
+	max     int
+	onError func(error) // Reports queued messages that could not be sent
+
+	mu      sync.Mutex
+	open    bool
+	closed  bool
+	queue   [][]byte
+	onOpen  []func()
+	onClose []func()
+}
+
+// textChannel is the part of *pion.DataChannel a channelSender writes to.
+type textChannel interface {
+	SendText(s string) error
+}
+
+// newChannelSender returns a sender for dc with a queue of max messages
+// (DefaultMaxBufferedMessages if zero) and takes over dc's OnOpen and OnClose
+// handlers.
+func newChannelSender(dc *pion.DataChannel, max int, onError func(error)) *channelSender {
+	s := newSender(dc, max, onError)
+	dc.OnOpen(s.opened)
+	dc.OnClose(s.closedChannel)
+	return s
+}
+
+func newSender(dc textChannel, max int, onError func(error)) *channelSender {
+	if max <= 0 {
+		max = DefaultMaxBufferedMessages
+	}
+	return &channelSender{dc: dc, max: max, onError: onError}
+}
+
+// send writes data, or queues it until the channel opens.
+func (s *channelSender) send(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.closed:
+		return errChannelClosed
+	case s.open:
+		return s.dc.SendText(string(data))
+	case len(s.queue) >= s.max:
+		return ErrSendBufferFull
+	}
+	s.queue = append(s.queue, append([]byte(nil), data...))
+	return nil
+}
+
+// whenOpen calls f once the channel is open, right away if it already is.
+func (s *channelSender) whenOpen(f func()) {
+	s.mu.Lock()
+	open := s.open
+	if !open {
+		s.onOpen = append(s.onOpen, f)
+	}
+	s.mu.Unlock()
+	if open {
+		go f()
+	}
+}
+
+// whenClosed calls f once the channel has closed.
+func (s *channelSender) whenClosed(f func()) {
+	s.mu.Lock()
+	closed := s.closed
+	if !closed {
+		s.onClose = append(s.onClose, f)
+	}
+	s.mu.Unlock()
+	if closed {
+		go f()
+	}
+}
+
+// opened flushes the queue, holding the lock so later sends stay in order.
+func (s *channelSender) opened() {
+	s.mu.Lock()
+	if s.open || s.closed {
+		s.mu.Unlock()
+		return
+	}
+	var err error
+	for i, data := range s.queue {
+		if err = s.dc.SendText(string(data)); err != nil {
+			err = fmt.Errorf("data channel opened: %d queued messages not sent: %w", len(s.queue)-i, err)
+			break
+		}
+	}
+	s.queue = nil
+	s.open = true
+	handlers := s.onOpen
+	s.onOpen = nil
+	s.mu.Unlock()
+
+	if err != nil && s.onError != nil {
+		s.onError(err)
+	}
+
+	for _, f := range handlers {
+		f()
+	}
+}
+
+func (s *channelSender) closedChannel() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.queue = nil
+	handlers := s.onClose
+	s.onClose = nil
+	s.mu.Unlock()
+
+	for _, f := range handlers {
+		f()
+	}
+}
@@ -0,0 +1,102 @@
+package webrtc
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeChannel records the messages sent on it, failing from the failAt'th
+// message on if failAt is set.
+type fakeChannel struct {
+	mu     sync.Mutex
+	sent   []string
+	failAt int
+}
+
+func (c *fakeChannel) SendText(s string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failAt > 0 && len(c.sent)+1 >= c.failAt {
+		return errors.New("sctp: stream closed")
+	}
+	c.sent = append(c.sent, s)
+	return nil
+}
+
+func (c *fakeChannel) messages() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.sent...)
+}
+
+func TestChannelSender_FlushesInOrder(t *testing.T) {
+	dc := &fakeChannel{}
+	s := newSender(dc, 2, nil)
+	opened := 0
+	s.whenOpen(func() { opened++ })
+
+	for _, m := range []string{"a", "b"} {
+		if err := s.send([]byte(m)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.send([]byte("c")); !errors.Is(err, ErrSendBufferFull) {
+		t.Errorf("third queued send: %v, want ErrSendBufferFull", err)
+	}
+	if got := dc.messages(); len(got) != 0 {
+		t.Fatalf("sent %v before the channel opened", got)
+	}
+
+	s.opened()
+	if err := s.send([]byte("d")); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(dc.messages(), ","); got != "a,b,d" {
+		t.Errorf("sent %s, want a,b,d", got)
+	}
+	if opened != 1 {
+		t.Errorf("open handler called %d times, want 1", opened)
+	}
+}
+
+func TestChannelSender_ReportsFlushErrors(t *testing.T) {
+	dc := &fakeChannel{failAt: 2}
+	var errs []error
+	s := newSender(dc, 0, func(err error) { errs = append(errs, err) })
+	for _, m := range []string{"a", "b", "c"} {
+		if err := s.send([]byte(m)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.opened()
+	if got := dc.messages(); len(got) != 1 || got[0] != "a" {
+		t.Errorf("sent %v, want [a]", got)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "2 queued messages not sent") {
+		t.Errorf("reported %v, want one error for the 2 unsent messages", errs)
+	}
+}
+
+func TestChannelSender_Closed(t *testing.T) {
+	dc := &fakeChannel{}
+	s := newSender(dc, 0, nil)
+	_ = s.send([]byte("a"))
+	closed := 0
+	s.whenClosed(func() { closed++ })
+
+	s.closedChannel()
+	s.closedChannel()
+	s.opened() // A late open does not flush the dropped queue
+	if err := s.send([]byte("b")); !errors.Is(err, errChannelClosed) {
+		t.Errorf("send after close: %v, want errChannelClosed", err)
+	}
+	if got := dc.messages(); len(got) != 0 {
+		t.Errorf("sent %v after the channel closed", got)
+	}
+	if closed != 1 {
+		t.Errorf("close handler called %d times, want 1", closed)
+	}
+}